@@ -0,0 +1,250 @@
+package openapi
+
+import (
+	"sort"
+
+	"github.com/chanced/uri"
+)
+
+func (at AnchorType) String() string {
+	switch at {
+	case AnchorTypeRegular:
+		return "$anchor"
+	case AnchorTypeRecursive:
+		return "$recursiveAnchor"
+	case AnchorTypeDynamic:
+		return "$dynamicAnchor"
+	default:
+		return "undefined"
+	}
+}
+
+// AnchorEntry is an Anchor of an AnchorIndex along with the schema resource
+// which contains it.
+type AnchorEntry struct {
+	Anchor
+	// Resource is the URI of the schema resource which contains the Anchor:
+	// the $id of the nearest Schema with one or, if there is not one, the
+	// resource (e.g. the Document) which contains the Schema.
+	Resource uri.URI
+}
+
+// URI returns the URI which identifies the Anchor (e.g.
+// "https://example.com/openapi.yaml#pet"). $recursiveAnchor anchors, which
+// are unnamed, are identified by the Resource.
+func (ae AnchorEntry) URI() uri.URI {
+	u := ae.Resource
+	u.Fragment = ae.Name.String()
+	u.RawFragment = ""
+	return u
+}
+
+// AnchorShadow is an anchor which shares the name and type of an anchor in
+// another schema resource which is encountered first. Lookups by name alone
+// resolve to the anchor encountered first.
+type AnchorShadow struct {
+	Anchor AnchorEntry
+	By     AnchorEntry
+}
+
+// AnchorIndex is an index of the $anchor, $dynamicAnchor, and
+// $recursiveAnchor anchors of a Document and the resources it references.
+//
+// Unlike the Anchors of a Node, anchors are scoped to their schema resource,
+// so anchors which share a name but are within different resources (e.g.
+// Schemas with different $ids) are not considered duplicates.
+type AnchorIndex struct {
+	entries []AnchorEntry
+}
+
+// AnchorIndex returns an AnchorIndex of the anchors of the Document and the
+// resources it references, in the order they are encountered.
+func (d *Document) AnchorIndex() *AnchorIndex {
+	ai := &AnchorIndex{}
+	if d == nil {
+		return ai
+	}
+	var schemas []*Schema
+	_ = walkNodes(d, func(n node) error {
+		if s, ok := n.(*Schema); ok {
+			schemas = append(schemas, s)
+		}
+		return nil
+	})
+	resources := schemaResources(schemas)
+	for _, s := range schemas {
+		res, ok := resources[s]
+		if !ok {
+			res = s.AbsoluteLocation()
+			res.Fragment = ""
+			res.RawFragment = ""
+		}
+		for _, a := range schemaAnchors(s) {
+			ai.entries = append(ai.entries, AnchorEntry{Anchor: a, Resource: res})
+		}
+	}
+	return ai
+}
+
+// schemaResources returns the URI of the schema resource of each Schema
+// which is, or is nested within, a Schema with an $id.
+func schemaResources(schemas []*Schema) map[*Schema]uri.URI {
+	type scope struct {
+		schema *Schema
+		nodes  []node
+	}
+	var scopes []scope
+	for _, s := range schemas {
+		if s.ID == nil {
+			continue
+		}
+		sc := scope{schema: s}
+		_ = walkLocalNodes(s, func(n node) error {
+			sc.nodes = append(sc.nodes, n)
+			return nil
+		})
+		scopes = append(scopes, sc)
+	}
+	// enclosing resources contain more nodes than those nested within them
+	// and so are assigned first
+	sort.SliceStable(scopes, func(i, j int) bool { return len(scopes[i].nodes) > len(scopes[j].nodes) })
+
+	res := map[*Schema]uri.URI{}
+	for _, sc := range scopes {
+		base, ok := res[sc.schema]
+		if !ok {
+			base = sc.schema.AbsoluteLocation()
+		}
+		id := *base.ResolveReference(sc.schema.ID)
+		id.Fragment = ""
+		id.RawFragment = ""
+		for _, n := range sc.nodes {
+			if s, ok := n.(*Schema); ok {
+				res[s] = id
+			}
+		}
+	}
+	return res
+}
+
+// schemaAnchors returns the anchors declared by s, excluding those of its
+// subschemas
+func schemaAnchors(s *Schema) []Anchor {
+	var anchors []Anchor
+	if s.Anchor != "" {
+		anchors = append(anchors, Anchor{
+			Location: s.Location.AppendLocation("$anchor"),
+			In:       s,
+			Name:     s.Anchor,
+			Type:     AnchorTypeRegular,
+		})
+	}
+	if s.DynamicAnchor != "" {
+		anchors = append(anchors, Anchor{
+			Location: s.Location.AppendLocation("$dynamicAnchor"),
+			In:       s,
+			Name:     s.DynamicAnchor,
+			Type:     AnchorTypeDynamic,
+		})
+	}
+	if s.RecursiveAnchor != nil && *s.RecursiveAnchor {
+		anchors = append(anchors, Anchor{
+			Location: s.Location.AppendLocation("$recursiveAnchor"),
+			In:       s,
+			Type:     AnchorTypeRecursive,
+		})
+	}
+	return anchors
+}
+
+// Anchors returns all entries of the AnchorIndex.
+func (ai *AnchorIndex) Anchors() []AnchorEntry {
+	if ai == nil {
+		return nil
+	}
+	return append([]AnchorEntry(nil), ai.entries...)
+}
+
+// Lookup returns the anchors named name of type typ, regardless of the
+// resource which contains them. Anchors of type AnchorTypeRecursive are
+// unnamed; name should be empty.
+func (ai *AnchorIndex) Lookup(name Text, typ AnchorType) []AnchorEntry {
+	if ai == nil {
+		return nil
+	}
+	var res []AnchorEntry
+	for _, e := range ai.entries {
+		if e.Name == name && e.Type == typ {
+			res = append(res, e)
+		}
+	}
+	return res
+}
+
+// Get returns the anchor named name of type typ within the schema resource
+// identified by resource. If the resource contains more than one, the first
+// is returned. If the resource does not contain a matching anchor, nil is
+// returned.
+func (ai *AnchorIndex) Get(resource uri.URI, name Text, typ AnchorType) *AnchorEntry {
+	if ai == nil {
+		return nil
+	}
+	resource.Fragment = ""
+	resource.RawFragment = ""
+	r := resource.String()
+	for i, e := range ai.entries {
+		if e.Name == name && e.Type == typ && e.Resource.String() == r {
+			return &ai.entries[i]
+		}
+	}
+	return nil
+}
+
+// Duplicates returns the anchors which share a name, type, and schema
+// resource with an anchor which is encountered first. Duplicate anchors are
+// invalid.
+func (ai *AnchorIndex) Duplicates() []*DuplicateAnchorError {
+	if ai == nil {
+		return nil
+	}
+	var res []*DuplicateAnchorError
+	first := map[anchorKey]int{}
+	for i, e := range ai.entries {
+		k := anchorKey{resource: e.Resource.String(), name: e.Name, typ: e.Type}
+		if j, ok := first[k]; ok {
+			res = append(res, &DuplicateAnchorError{A: &ai.entries[j].Anchor, B: &ai.entries[i].Anchor})
+			continue
+		}
+		first[k] = i
+	}
+	return res
+}
+
+// Shadowed returns the anchors which share a name and type with an anchor of
+// a different schema resource which is encountered first. Shadowed anchors
+// are valid but can only be resolved relative to their resource.
+func (ai *AnchorIndex) Shadowed() []AnchorShadow {
+	if ai == nil {
+		return nil
+	}
+	var res []AnchorShadow
+	first := map[anchorKey]int{}
+	for i, e := range ai.entries {
+		k := anchorKey{name: e.Name, typ: e.Type}
+		j, ok := first[k]
+		if !ok {
+			first[k] = i
+			continue
+		}
+		if ai.entries[j].Resource.String() != e.Resource.String() {
+			res = append(res, AnchorShadow{Anchor: e, By: ai.entries[j]})
+		}
+	}
+	return res
+}
+
+type anchorKey struct {
+	resource string
+	name     Text
+	typ      AnchorType
+}
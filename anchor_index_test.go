@@ -0,0 +1,68 @@
+package openapi_test
+
+import (
+	"testing"
+
+	"github.com/chanced/openapi"
+	"github.com/chanced/uri"
+)
+
+func TestAnchorIndex(t *testing.T) {
+	data := []byte(`{
+		"openapi": "3.1.0",
+		"info": { "title": "anchors", "version": "1.0.0" },
+		"paths": {},
+		"components": {
+			"schemas": {
+				"Tree": {
+					"$id": "https://example.com/schemas/tree",
+					"$dynamicAnchor": "node",
+					"type": "object",
+					"properties": {
+						"leaf": { "$anchor": "leaf", "type": "string" }
+					}
+				},
+				"Leaf": { "$anchor": "leaf", "type": "string" },
+				"Node": { "$dynamicAnchor": "node", "type": "object" },
+				"A": { "$anchor": "dup" },
+				"B": { "$anchor": "dup" }
+			}
+		}
+	}`)
+	doc := loadDocument(t, "https://example.com/openapi.json", data)
+	ai := doc.AnchorIndex()
+	if n := len(ai.Anchors()); n != 6 {
+		t.Fatalf("expected 6 anchors, got %d", n)
+	}
+
+	leaves := ai.Lookup("leaf", openapi.AnchorTypeRegular)
+	if len(leaves) != 2 {
+		t.Fatalf("expected 2 leaf anchors, got %d", len(leaves))
+	}
+	if u := leaves[0].URI(); u.String() != "https://example.com/schemas/tree#leaf" {
+		t.Errorf("unexpected URI: %s", u.String())
+	}
+	if u := leaves[1].URI(); u.String() != "https://example.com/openapi.json#leaf" {
+		t.Errorf("unexpected URI: %s", u.String())
+	}
+
+	tree := uri.MustParse("https://example.com/schemas/tree")
+	if a := ai.Get(*tree, "node", openapi.AnchorTypeDynamic); a == nil || a.In != doc.Components.Schemas.Get("Tree") {
+		t.Errorf("expected the $dynamicAnchor of Tree, got %+v", a)
+	}
+	if a := ai.Get(*tree, "node", openapi.AnchorTypeRegular); a != nil {
+		t.Errorf("expected no $anchor named node, got %+v", a)
+	}
+
+	dups := ai.Duplicates()
+	if len(dups) != 1 || dups[0].A.In != doc.Components.Schemas.Get("A") || dups[0].B.In != doc.Components.Schemas.Get("B") {
+		t.Errorf("unexpected duplicates: %+v", dups)
+	}
+	shadowed := ai.Shadowed()
+	if len(shadowed) != 2 {
+		t.Fatalf("expected 2 shadowed anchors, got %+v", shadowed)
+	}
+	if shadowed[0].Anchor.In != doc.Components.Schemas.Get("Leaf") || shadowed[0].By.Resource.String() != tree.String() {
+		t.Errorf("unexpected shadow: %+v", shadowed[0])
+	}
+}
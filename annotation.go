@@ -0,0 +1,308 @@
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/token"
+	"strings"
+	"sync"
+)
+
+// Annotation is a typed extension consumed by code generators (e.g.
+// "x-go-type"). Annotations are registered with an AnnotationRegistry, which
+// validates their values wherever they appear in a Document.
+type Annotation interface {
+	// Key is the name of the extension, including the "x-" prefix.
+	Key() Text
+	// Kinds are the Kinds of nodes which the Annotation may be applied to. If
+	// empty, the Annotation may be applied to any node which supports
+	// extensions.
+	Kinds() []Kind
+	// Validate returns an error if value, the raw JSON of the extension, is
+	// not valid for n.
+	Validate(n Node, value []byte) error
+}
+
+// TypedAnnotation is an Annotation whose value is decoded into T.
+type TypedAnnotation[T any] struct {
+	// Name of the extension, including the "x-" prefix.
+	Name Text
+	// AllowedKinds are the Kinds of nodes which the annotation may be applied
+	// to. If empty, any Kind is allowed.
+	AllowedKinds []Kind
+	// Check, if set, is called with the decoded value of the annotation. An
+	// error returned from Check is wrapped with ErrInvalidAnnotation.
+	Check func(n Node, v T) error
+}
+
+// Key implements Annotation
+func (a TypedAnnotation[T]) Key() Text { return a.Name }
+
+// Kinds implements Annotation
+func (a TypedAnnotation[T]) Kinds() []Kind { return a.AllowedKinds }
+
+// Validate implements Annotation
+func (a TypedAnnotation[T]) Validate(n Node, value []byte) error {
+	var v T
+	if err := json.Unmarshal(value, &v); err != nil {
+		return annotationError(n, a.Name, err)
+	}
+	return a.check(n, v)
+}
+
+func (a TypedAnnotation[T]) check(n Node, v T) error {
+	if !annotationAllows(a, n.Kind()) {
+		return annotationError(n, a.Name, fmt.Errorf("not applicable to %s", n.Kind()))
+	}
+	if a.Check != nil {
+		if err := a.Check(n, v); err != nil {
+			return annotationError(n, a.Name, err)
+		}
+	}
+	return nil
+}
+
+// Get decodes the annotation of n. If n does not have the annotation, the
+// zero value of T and false are returned.
+func (a TypedAnnotation[T]) Get(n Node) (T, bool, error) {
+	v, err := GetExtension[T](n, a.Name)
+	if errors.Is(err, ErrExtensionNotFound) {
+		return v, false, nil
+	}
+	if err != nil {
+		return v, false, err
+	}
+	return v, true, nil
+}
+
+// Set validates v and, if valid, sets it as the annotation of n.
+func (a TypedAnnotation[T]) Set(n Node, v T) error {
+	if err := a.check(n, v); err != nil {
+		return err
+	}
+	return SetExtension(n, a.Name, v)
+}
+
+// SchemaAnnotation is an Annotation whose value is validated against a
+// CompiledSchema. It allows generators to register annotations without
+// writing Go validation logic.
+type SchemaAnnotation struct {
+	Name         Text
+	AllowedKinds []Kind
+	Schema       CompiledSchema
+}
+
+// Key implements Annotation
+func (a SchemaAnnotation) Key() Text { return a.Name }
+
+// Kinds implements Annotation
+func (a SchemaAnnotation) Kinds() []Kind { return a.AllowedKinds }
+
+// Validate implements Annotation
+func (a SchemaAnnotation) Validate(n Node, value []byte) error {
+	if !annotationAllows(a, n.Kind()) {
+		return annotationError(n, a.Name, fmt.Errorf("not applicable to %s", n.Kind()))
+	}
+	if a.Schema == nil {
+		return nil
+	}
+	var v interface{}
+	if err := json.Unmarshal(value, &v); err != nil {
+		return annotationError(n, a.Name, err)
+	}
+	if err := a.Schema.Validate(v); err != nil {
+		return annotationError(n, a.Name, err)
+	}
+	return nil
+}
+
+// GoImport is the value of the "x-go-type-import" annotation.
+type GoImport struct {
+	// Path of the package (e.g. "github.com/google/uuid").
+	Path Text `json:"path"`
+	// Name of the package, if it differs from the last element of Path.
+	Name Text `json:"name,omitempty"`
+}
+
+var (
+	// AnnotationGoType ("x-go-type") overrides the Go type generated for a
+	// Schema, Parameter, or Header (e.g. "uuid.UUID").
+	AnnotationGoType = TypedAnnotation[Text]{
+		Name:         "x-go-type",
+		AllowedKinds: []Kind{KindSchema, KindParameter, KindHeader},
+		Check: func(_ Node, v Text) error {
+			if v == "" || strings.ContainsAny(v.String(), " \t\r\n") {
+				return fmt.Errorf("%q is not a valid Go type", v)
+			}
+			return nil
+		},
+	}
+
+	// AnnotationGoName ("x-go-name") overrides the Go identifier generated
+	// for a node.
+	AnnotationGoName = TypedAnnotation[Text]{
+		Name: "x-go-name",
+		Check: func(_ Node, v Text) error {
+			if !token.IsIdentifier(v.String()) {
+				return fmt.Errorf("%q is not a valid Go identifier", v)
+			}
+			return nil
+		},
+	}
+
+	// AnnotationGoTypeImport ("x-go-type-import") is the package which must
+	// be imported for the type named by AnnotationGoType.
+	AnnotationGoTypeImport = TypedAnnotation[GoImport]{
+		Name:         "x-go-type-import",
+		AllowedKinds: []Kind{KindSchema, KindParameter, KindHeader},
+		Check: func(_ Node, v GoImport) error {
+			if v.Path == "" {
+				return errors.New("path is required")
+			}
+			if v.Name != "" && !token.IsIdentifier(v.Name.String()) {
+				return fmt.Errorf("%q is not a valid package name", v.Name)
+			}
+			return nil
+		},
+	}
+
+	// AnnotationEnumVarNames ("x-enum-varnames") names the constants
+	// generated for each value of a Schema's enum. It must have the same
+	// number of entries as the enum.
+	AnnotationEnumVarNames = TypedAnnotation[Texts]{
+		Name:         "x-enum-varnames",
+		AllowedKinds: []Kind{KindSchema},
+		Check: func(n Node, v Texts) error {
+			if s, ok := n.(*Schema); ok && len(v) != len(s.Enum) {
+				return fmt.Errorf("expected %d names, one for each value of enum, found %d", len(s.Enum), len(v))
+			}
+			for _, name := range v {
+				if !token.IsIdentifier(name.String()) {
+					return fmt.Errorf("%q is not a valid Go identifier", name)
+				}
+			}
+			return nil
+		},
+	}
+
+	// AnnotationEnumDescriptions ("x-enum-descriptions") describes each value
+	// of a Schema's enum. It must have the same number of entries as the
+	// enum.
+	AnnotationEnumDescriptions = TypedAnnotation[Texts]{
+		Name:         "x-enum-descriptions",
+		AllowedKinds: []Kind{KindSchema},
+		Check: func(n Node, v Texts) error {
+			if s, ok := n.(*Schema); ok && len(v) != len(s.Enum) {
+				return fmt.Errorf("expected %d descriptions, one for each value of enum, found %d", len(s.Enum), len(v))
+			}
+			return nil
+		},
+	}
+)
+
+// AnnotationRegistry is a set of Annotations, keyed by extension name. It is
+// safe for concurrent use.
+type AnnotationRegistry struct {
+	mu          sync.RWMutex
+	annotations map[Text]Annotation
+}
+
+// NewAnnotationRegistry returns an AnnotationRegistry with the well-known
+// annotations (AnnotationGoType, AnnotationGoName, AnnotationGoTypeImport,
+// AnnotationEnumVarNames, and AnnotationEnumDescriptions) registered.
+func NewAnnotationRegistry() *AnnotationRegistry {
+	r := &AnnotationRegistry{annotations: map[Text]Annotation{}}
+	for _, a := range []Annotation{
+		AnnotationGoType,
+		AnnotationGoName,
+		AnnotationGoTypeImport,
+		AnnotationEnumVarNames,
+		AnnotationEnumDescriptions,
+	} {
+		r.annotations[a.Key()] = a
+	}
+	return r
+}
+
+// Register adds a to the registry. An error wrapping ErrInvalidAnnotation is
+// returned if the key of a is not an extension key or is already registered.
+func (r *AnnotationRegistry) Register(a Annotation) error {
+	key := a.Key()
+	if !IsExtensionKey(key) {
+		return fmt.Errorf("%w: key %q must start with \"x-\"", ErrInvalidAnnotation, key)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.annotations == nil {
+		r.annotations = map[Text]Annotation{}
+	}
+	if _, ok := r.annotations[key]; ok {
+		return fmt.Errorf("%w: %q is already registered", ErrInvalidAnnotation, key)
+	}
+	r.annotations[key] = a
+	return nil
+}
+
+// Get returns the Annotation registered for key, if any.
+func (r *AnnotationRegistry) Get(key Text) (Annotation, bool) {
+	if !key.HasPrefix("x-") {
+		key = "x-" + key
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	a, ok := r.annotations[key]
+	return a, ok
+}
+
+// Validate validates each registered annotation present in doc. If any are
+// invalid, ExtensionErrors is returned.
+func (r *AnnotationRegistry) Validate(doc *Document) error {
+	var errs ExtensionErrors
+	err := walkNodes(doc, func(n node) error {
+		e, ok := n.(extended)
+		if !ok {
+			return nil
+		}
+		exts := e.exts()
+		for _, key := range exts.Keys() {
+			a, ok := r.Get(key)
+			if !ok {
+				continue
+			}
+			if err := a.Validate(n, exts[key]); err != nil {
+				loc := n.location().AppendLocation(key.String()).AbsoluteLocation()
+				errs = append(errs, NewValidationError(err, n.Kind(), loc))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func annotationAllows(a Annotation, k Kind) bool {
+	kinds := a.Kinds()
+	if len(kinds) == 0 {
+		return true
+	}
+	for _, kind := range kinds {
+		if kind == k {
+			return true
+		}
+	}
+	return false
+}
+
+func annotationError(n Node, key Text, err error) error {
+	loc := n.AbsoluteLocation()
+	if l, ok := n.(node); ok {
+		loc = l.location().AppendLocation(key.String()).AbsoluteLocation()
+	}
+	return NewError(fmt.Errorf("%w %q: %v", ErrInvalidAnnotation, key, err), loc)
+}
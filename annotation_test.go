@@ -0,0 +1,80 @@
+package openapi_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/chanced/openapi"
+)
+
+func TestAnnotations(t *testing.T) {
+	s := &openapi.Schema{Type: openapi.Types{openapi.TypeString}, Enum: openapi.StringEnum("cat", "dog")}
+	if err := openapi.AnnotationGoType.Set(s, "uuid.UUID"); err != nil {
+		t.Fatal(err)
+	}
+	if err := openapi.AnnotationGoTypeImport.Set(s, openapi.GoImport{Path: "github.com/google/uuid"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := openapi.AnnotationGoName.Set(s, "not valid"); !errors.Is(err, openapi.ErrInvalidAnnotation) {
+		t.Errorf("expected ErrInvalidAnnotation, got %v", err)
+	}
+	if err := openapi.AnnotationEnumVarNames.Set(s, openapi.Texts{"Cat"}); !errors.Is(err, openapi.ErrInvalidAnnotation) {
+		t.Errorf("expected ErrInvalidAnnotation for mismatched enum length, got %v", err)
+	}
+	if err := openapi.AnnotationEnumVarNames.Set(s, openapi.Texts{"Cat", "Dog"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := openapi.AnnotationEnumVarNames.Set(&openapi.Info{}, openapi.Texts{}); !errors.Is(err, openapi.ErrInvalidAnnotation) {
+		t.Errorf("expected ErrInvalidAnnotation for an Info, got %v", err)
+	}
+
+	typ, ok, err := openapi.AnnotationGoType.Get(s)
+	if err != nil || !ok || typ != "uuid.UUID" {
+		t.Errorf("expected uuid.UUID, got %q %v %v", typ, ok, err)
+	}
+	if _, ok, err = openapi.AnnotationGoName.Get(s); ok || err != nil {
+		t.Errorf("expected x-go-name to be absent, got %v %v", ok, err)
+	}
+
+	r := openapi.NewAnnotationRegistry()
+	if err = r.Register(openapi.AnnotationGoName); !errors.Is(err, openapi.ErrInvalidAnnotation) {
+		t.Errorf("expected ErrInvalidAnnotation for a duplicate, got %v", err)
+	}
+	if err = r.Register(openapi.TypedAnnotation[bool]{Name: "omitempty"}); !errors.Is(err, openapi.ErrInvalidAnnotation) {
+		t.Errorf("expected ErrInvalidAnnotation for a key without x-, got %v", err)
+	}
+	if err = r.Register(openapi.TypedAnnotation[bool]{Name: "x-omitempty", AllowedKinds: []openapi.Kind{openapi.KindSchema}}); err != nil {
+		t.Fatal(err)
+	}
+
+	info := &openapi.Info{Title: "annotations", Version: "1.0.0"}
+	doc := &openapi.Document{
+		Info:       info,
+		Components: &openapi.Components{Schemas: &openapi.SchemaMap{}},
+	}
+	doc.Components.Schemas.Set("Pet", s)
+	if err = r.Validate(doc); err != nil {
+		t.Fatal(err)
+	}
+
+	s.Extensions.SetRawExtension("x-omitempty", []byte(`"yes"`))
+	info.Extensions.SetRawExtension("x-go-name", []byte(`"1Info"`))
+	err = r.Validate(doc)
+	var ee openapi.ExtensionErrors
+	if !errors.As(err, &ee) || len(ee) != 2 {
+		t.Fatalf("expected 2 ExtensionErrors, got %v", err)
+	}
+	if !errors.Is(err, openapi.ErrInvalidAnnotation) {
+		t.Errorf("expected errors to wrap ErrInvalidAnnotation")
+	}
+
+	v, err := openapi.NewOfflineValidator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	v.Annotations = r
+	var ve *openapi.ValidationError
+	if err = v.ValidateDocument(doc); !errors.As(err, &ve) {
+		t.Errorf("expected a ValidationError, got %v", err)
+	}
+}
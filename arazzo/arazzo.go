@@ -0,0 +1,423 @@
+// Package arazzo models OAI Arazzo documents, which describe workflows as
+// sequences of calls to the Operations of OpenAPI Documents.
+//
+// A Document is parsed from JSON or YAML with Parse or loaded, along with the
+// OpenAPI Documents of its source descriptions, with Load. Validate checks
+// the structure of a Document and cross-validates that the operationIds,
+// operationPaths, parameters, and workflowIds referenced by its steps exist
+// in the Documents of its sources.
+//
+// Specification extensions are not modeled.
+package arazzo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Masterminds/semver"
+	"github.com/chanced/openapi"
+	"github.com/chanced/transcode"
+	"github.com/chanced/uri"
+)
+
+var (
+	// ErrUnsupportedVersion is returned when the arazzo version of a
+	// Document does not satisfy SupportedVersions.
+	ErrUnsupportedVersion = errors.New("arazzo: unsupported version")
+
+	// ErrRequired indicates that a required field is missing.
+	ErrRequired = errors.New("arazzo: missing required field")
+
+	// ErrDuplicate indicates that a name or identifier is not unique.
+	ErrDuplicate = errors.New("arazzo: duplicate identifier")
+
+	// ErrInvalidStep indicates that a Step does not set exactly one of
+	// operationId, operationPath, and workflowId.
+	ErrInvalidStep = errors.New("arazzo: step must have exactly one of operationId, operationPath, or workflowId")
+
+	// ErrInvalidAction indicates that a SuccessAction or FailureAction is
+	// not valid.
+	ErrInvalidAction = errors.New("arazzo: invalid action")
+
+	// ErrInvalidExpression indicates that a runtime expression is malformed.
+	ErrInvalidExpression = errors.New("arazzo: invalid runtime expression")
+
+	// ErrSourceNotFound indicates that a Document does not have a source
+	// description of a given name.
+	ErrSourceNotFound = errors.New("arazzo: source description not found")
+
+	// ErrSourceNotLoaded indicates that the Document of a source description
+	// has not been loaded or added.
+	ErrSourceNotLoaded = errors.New("arazzo: source description not loaded")
+
+	// ErrOperationNotFound indicates that an operationId or operationPath
+	// does not reference an Operation of a source.
+	ErrOperationNotFound = errors.New("arazzo: operation not found")
+
+	// ErrAmbiguousOperation indicates that an unqualified operationId matches
+	// Operations of more than one source.
+	ErrAmbiguousOperation = errors.New("arazzo: ambiguous operationId")
+
+	// ErrWorkflowNotFound indicates that a workflowId does not reference a
+	// Workflow.
+	ErrWorkflowNotFound = errors.New("arazzo: workflow not found")
+
+	// ErrStepNotFound indicates that a stepId does not reference a Step of
+	// the Workflow.
+	ErrStepNotFound = errors.New("arazzo: step not found")
+
+	// ErrComponentNotFound indicates that the reference of a reusable object
+	// does not reference a component.
+	ErrComponentNotFound = errors.New("arazzo: component not found")
+
+	// ErrUnknownParameter indicates that a Parameter of a Step is not a
+	// Parameter of its Operation.
+	ErrUnknownParameter = errors.New("arazzo: unknown parameter")
+)
+
+// SupportedVersions are the versions of Arazzo supported by this package.
+var SupportedVersions, _ = semver.NewConstraint(">= 1.0.0, < 1.1.0")
+
+const (
+	// SourceTypeOpenAPI is the type of source descriptions which are OpenAPI
+	// Documents.
+	SourceTypeOpenAPI = "openapi"
+	// SourceTypeArazzo is the type of source descriptions which are Arazzo
+	// Documents.
+	SourceTypeArazzo = "arazzo"
+)
+
+const (
+	// ActionEnd ends the workflow.
+	ActionEnd = "end"
+	// ActionGoto transfers control to a step or workflow.
+	ActionGoto = "goto"
+	// ActionRetry retries the step. It is only valid for FailureActions.
+	ActionRetry = "retry"
+)
+
+// Document is an Arazzo Document.
+type Document struct {
+	// Arazzo is the version of the Arazzo Specification the Document uses.
+	Arazzo             string              `json:"arazzo"`
+	Info               Info                `json:"info"`
+	SourceDescriptions []SourceDescription `json:"sourceDescriptions"`
+	Workflows          []Workflow          `json:"workflows"`
+	Components         *Components         `json:"components,omitempty"`
+
+	uri       uri.URI
+	documents map[string]*openapi.Document
+	workflows map[string]*Document
+}
+
+// Info provides metadata about the workflows of a Document.
+type Info struct {
+	Title       string `json:"title"`
+	Summary     string `json:"summary,omitempty"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// SourceDescription describes an OpenAPI or Arazzo Document referenced by
+// the workflows of a Document.
+type SourceDescription struct {
+	// Name identifies the source description within runtime expressions
+	// (e.g. "$sourceDescriptions.petstore.getPet").
+	Name string `json:"name"`
+	// URL of the source Document, which may be relative to the URI of the
+	// Arazzo Document.
+	URL string `json:"url"`
+	// Type is either SourceTypeOpenAPI or SourceTypeArazzo. If empty, the
+	// source is treated as an OpenAPI Document.
+	Type string `json:"type,omitempty"`
+}
+
+// Workflow describes the steps taken to achieve a goal.
+type Workflow struct {
+	WorkflowID  string `json:"workflowId"`
+	Summary     string `json:"summary,omitempty"`
+	Description string `json:"description,omitempty"`
+	// Inputs is the JSON Schema of the inputs of the Workflow.
+	Inputs json.RawMessage `json:"inputs,omitempty"`
+	// DependsOn are the workflowIds of the Workflows which must be completed
+	// before the Workflow is processed. They may be runtime expressions
+	// referencing the Workflows of Arazzo sources (e.g.
+	// "$sourceDescriptions.other.login").
+	DependsOn      []string          `json:"dependsOn,omitempty"`
+	Steps          []Step            `json:"steps"`
+	SuccessActions []SuccessAction   `json:"successActions,omitempty"`
+	FailureActions []FailureAction   `json:"failureActions,omitempty"`
+	Outputs        map[string]string `json:"outputs,omitempty"`
+	Parameters     []Parameter       `json:"parameters,omitempty"`
+}
+
+// Step returns the Step of w with the given stepId, or nil if w does not have
+// one.
+func (w *Workflow) Step(id string) *Step {
+	if w == nil {
+		return nil
+	}
+	for i := range w.Steps {
+		if w.Steps[i].StepID == id {
+			return &w.Steps[i]
+		}
+	}
+	return nil
+}
+
+// Step is a call to an Operation or Workflow.
+//
+// Exactly one of OperationID, OperationPath, and WorkflowID is set.
+type Step struct {
+	StepID      string `json:"stepId"`
+	Description string `json:"description,omitempty"`
+	// OperationID is the operationId of the Operation to call. If the
+	// Document has more than one OpenAPI source, it is a runtime expression
+	// qualified by the name of the source (e.g.
+	// "$sourceDescriptions.petstore.getPet").
+	OperationID string `json:"operationId,omitempty"`
+	// OperationPath is a reference to the Operation to call, consisting of
+	// the url of a source and a JSON Pointer to the Operation (e.g.
+	// "{$sourceDescriptions.petstore.url}#/paths/~1pets/get").
+	OperationPath string `json:"operationPath,omitempty"`
+	// WorkflowID is the workflowId of the Workflow to call. It may be a
+	// runtime expression referencing the Workflow of an Arazzo source.
+	WorkflowID      string            `json:"workflowId,omitempty"`
+	Parameters      []Parameter       `json:"parameters,omitempty"`
+	RequestBody     *RequestBody      `json:"requestBody,omitempty"`
+	SuccessCriteria []Criterion       `json:"successCriteria,omitempty"`
+	OnSuccess       []SuccessAction   `json:"onSuccess,omitempty"`
+	OnFailure       []FailureAction   `json:"onFailure,omitempty"`
+	Outputs         map[string]string `json:"outputs,omitempty"`
+}
+
+// Parameter is a value passed to an Operation or Workflow.
+//
+// If Reference is set, the Parameter is a reusable object referencing a
+// Parameter of the Document's Components (e.g.
+// "$components.parameters.page") and Value, if set, overrides its value.
+type Parameter struct {
+	Reference string `json:"reference,omitempty"`
+	Name      string `json:"name,omitempty"`
+	// In is the location of the Parameter (i.e. path, query, header, or
+	// cookie). It is required for the Parameters of steps which call
+	// Operations and must be empty otherwise.
+	In    openapi.In      `json:"in,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// RequestBody is the request body passed to an Operation.
+type RequestBody struct {
+	ContentType  string               `json:"contentType,omitempty"`
+	Payload      json.RawMessage      `json:"payload,omitempty"`
+	Replacements []PayloadReplacement `json:"replacements,omitempty"`
+}
+
+// PayloadReplacement replaces the value at a location of a RequestBody's
+// payload.
+type PayloadReplacement struct {
+	// Target is a JSON Pointer or XPath expression.
+	Target string          `json:"target"`
+	Value  json.RawMessage `json:"value"`
+}
+
+// Criterion is an assertion used to determine the success of a Step or
+// whether an action applies.
+type Criterion struct {
+	Context   string         `json:"context,omitempty"`
+	Condition string         `json:"condition"`
+	Type      *CriterionType `json:"type,omitempty"`
+}
+
+// CriterionType is the type of a Criterion's condition (i.e. simple, regex,
+// jsonpath, or xpath) and, optionally, the version of the expression
+// language.
+type CriterionType struct {
+	Type    string
+	Version string
+}
+
+// MarshalJSON encodes ct as a string unless Version is set.
+func (ct CriterionType) MarshalJSON() ([]byte, error) {
+	if ct.Version == "" {
+		return json.Marshal(ct.Type)
+	}
+	return json.Marshal(struct {
+		Type    string `json:"type"`
+		Version string `json:"version"`
+	}{ct.Type, ct.Version})
+}
+
+// UnmarshalJSON decodes ct from either a string or an object.
+func (ct *CriterionType) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*ct = CriterionType{Type: s}
+		return nil
+	}
+	var v struct {
+		Type    string `json:"type"`
+		Version string `json:"version"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*ct = CriterionType{Type: v.Type, Version: v.Version}
+	return nil
+}
+
+// SuccessAction is an action taken when a Step succeeds.
+//
+// If Reference is set, the SuccessAction is a reusable object referencing a
+// SuccessAction of the Document's Components.
+type SuccessAction struct {
+	Reference  string      `json:"reference,omitempty"`
+	Name       string      `json:"name,omitempty"`
+	Type       string      `json:"type,omitempty"`
+	WorkflowID string      `json:"workflowId,omitempty"`
+	StepID     string      `json:"stepId,omitempty"`
+	Criteria   []Criterion `json:"criteria,omitempty"`
+}
+
+// FailureAction is an action taken when a Step fails.
+//
+// If Reference is set, the FailureAction is a reusable object referencing a
+// FailureAction of the Document's Components.
+type FailureAction struct {
+	Reference  string `json:"reference,omitempty"`
+	Name       string `json:"name,omitempty"`
+	Type       string `json:"type,omitempty"`
+	WorkflowID string `json:"workflowId,omitempty"`
+	StepID     string `json:"stepId,omitempty"`
+	// RetryAfter is the number of seconds to wait before retrying.
+	RetryAfter *float64    `json:"retryAfter,omitempty"`
+	RetryLimit *int        `json:"retryLimit,omitempty"`
+	Criteria   []Criterion `json:"criteria,omitempty"`
+}
+
+// Components holds reusable objects of a Document.
+type Components struct {
+	Inputs         map[string]json.RawMessage `json:"inputs,omitempty"`
+	Parameters     map[string]Parameter       `json:"parameters,omitempty"`
+	SuccessActions map[string]SuccessAction   `json:"successActions,omitempty"`
+	FailureActions map[string]FailureAction   `json:"failureActions,omitempty"`
+}
+
+// Parse parses an Arazzo Document from JSON or YAML.
+//
+// The Documents of the source descriptions are not loaded. They can be added
+// with AddSource and AddArazzoSource or the Document can be loaded with Load
+// instead.
+func Parse(data []byte) (*Document, error) {
+	data, err := transcode.JSONFromYAML(data)
+	if err != nil {
+		return nil, fmt.Errorf("arazzo: failed to transcode data: %w", err)
+	}
+	var d Document
+	if err = json.Unmarshal(data, &d); err != nil {
+		return nil, fmt.Errorf("arazzo: %w", err)
+	}
+	if d.Arazzo == "" {
+		return nil, fmt.Errorf("%w: arazzo", ErrRequired)
+	}
+	v, err := semver.NewVersion(d.Arazzo)
+	if err != nil || !SupportedVersions.Check(v) {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedVersion, d.Arazzo)
+	}
+	return &d, nil
+}
+
+// URI returns the URI the Document was loaded from, if it was loaded with
+// Load.
+func (d *Document) URI() uri.URI {
+	return d.uri
+}
+
+// Workflow returns the Workflow with the given workflowId, or nil if d does
+// not have one.
+func (d *Document) Workflow(id string) *Workflow {
+	for i := range d.Workflows {
+		if d.Workflows[i].WorkflowID == id {
+			return &d.Workflows[i]
+		}
+	}
+	return nil
+}
+
+// SourceDescription returns the SourceDescription with the given name, or
+// nil if d does not have one.
+func (d *Document) SourceDescription(name string) *SourceDescription {
+	for i := range d.SourceDescriptions {
+		if d.SourceDescriptions[i].Name == name {
+			return &d.SourceDescriptions[i]
+		}
+	}
+	return nil
+}
+
+// Source returns the OpenAPI Document of the source description name, or nil
+// if it has not been loaded or added.
+func (d *Document) Source(name string) *openapi.Document {
+	return d.documents[name]
+}
+
+// ArazzoSource returns the Arazzo Document of the source description name,
+// or nil if it has not been loaded or added.
+func (d *Document) ArazzoSource(name string) *Document {
+	return d.workflows[name]
+}
+
+// AddSource sets the OpenAPI Document of the source description name to doc.
+func (d *Document) AddSource(name string, doc *openapi.Document) error {
+	sd := d.SourceDescription(name)
+	if sd == nil {
+		return fmt.Errorf("%w: %q", ErrSourceNotFound, name)
+	}
+	if !sd.isOpenAPI() {
+		return fmt.Errorf("arazzo: source description %q is not of type %q", name, SourceTypeOpenAPI)
+	}
+	if d.documents == nil {
+		d.documents = map[string]*openapi.Document{}
+	}
+	d.documents[name] = doc
+	return nil
+}
+
+// AddArazzoSource sets the Arazzo Document of the source description name to
+// doc.
+func (d *Document) AddArazzoSource(name string, doc *Document) error {
+	sd := d.SourceDescription(name)
+	if sd == nil {
+		return fmt.Errorf("%w: %q", ErrSourceNotFound, name)
+	}
+	if sd.Type != SourceTypeArazzo {
+		return fmt.Errorf("arazzo: source description %q is not of type %q", name, SourceTypeArazzo)
+	}
+	if d.workflows == nil {
+		d.workflows = map[string]*Document{}
+	}
+	d.workflows[name] = doc
+	return nil
+}
+
+func (sd *SourceDescription) isOpenAPI() bool {
+	return sd.Type == "" || sd.Type == SourceTypeOpenAPI
+}
+
+const sourceDescriptionsPrefix = "$sourceDescriptions."
+
+// splitSourceExpression splits a runtime expression of the form
+// "$sourceDescriptions.<name>.<rest>" into name and rest
+func splitSourceExpression(expr string) (name, rest string, ok bool) {
+	if !strings.HasPrefix(expr, sourceDescriptionsPrefix) {
+		return "", "", false
+	}
+	expr = expr[len(sourceDescriptionsPrefix):]
+	i := strings.IndexByte(expr, '.')
+	if i <= 0 || i == len(expr)-1 {
+		return "", "", false
+	}
+	return expr[:i], expr[i+1:], true
+}
@@ -0,0 +1,214 @@
+package arazzo_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/chanced/openapi"
+	"github.com/chanced/openapi/arazzo"
+	"github.com/chanced/uri"
+)
+
+var petstore = []byte(`{
+	"openapi": "3.1.0",
+	"info": { "title": "Pet Store", "version": "1.0.0" },
+	"paths": {
+		"/pets": {
+			"post": {
+				"operationId": "createPet",
+				"responses": { "201": { "description": "created" } }
+			}
+		},
+		"/pets/{petId}": {
+			"parameters": [{ "name": "petId", "in": "path", "required": true, "schema": { "type": "integer" } }],
+			"get": {
+				"operationId": "getPet",
+				"parameters": [{ "name": "fields", "in": "query", "schema": { "type": "string" } }],
+				"responses": { "200": { "description": "ok" } }
+			}
+		}
+	}
+}`)
+
+var workflows = []byte(`
+arazzo: 1.0.0
+info:
+  title: Adopt a pet
+  version: 1.0.0
+sourceDescriptions:
+  - name: petstore
+    url: ./openapi.json
+    type: openapi
+workflows:
+  - workflowId: adopt
+    steps:
+      - stepId: create
+        operationId: createPet
+        requestBody:
+          contentType: application/json
+          payload: { "name": "Fido" }
+        successCriteria:
+          - condition: $statusCode == 201
+        onSuccess:
+          - name: fetch
+            type: goto
+            stepId: fetch
+        outputs:
+          id: $response.body#/id
+      - stepId: fetch
+        operationPath: '{$sourceDescriptions.petstore.url}#/paths/~1pets~1{petId}/get'
+        parameters:
+          - name: petId
+            in: path
+            value: $steps.create.outputs.id
+          - reference: $components.parameters.fields
+        successCriteria:
+          - condition: $.name
+            context: $response.body
+            type:
+              type: jsonpath
+              version: draft-goessner-dispatch-jsonpath-00
+        onFailure:
+          - reference: $components.failureActions.retry
+  - workflowId: adoptTwice
+    dependsOn: [adopt]
+    steps:
+      - stepId: again
+        workflowId: adopt
+components:
+  parameters:
+    fields:
+      name: fields
+      in: query
+      value: name
+  failureActions:
+    retry:
+      name: retry
+      type: retry
+      retryAfter: 1
+      retryLimit: 3
+`)
+
+func TestLoad(t *testing.T) {
+	fn := func(ctx context.Context, u uri.URI, kind openapi.Kind) (openapi.Kind, []byte, error) {
+		switch u.String() {
+		case "https://example.com/openapi.json":
+			return openapi.KindDocument, petstore, nil
+		case "https://example.com/workflows.arazzo.yaml":
+			return openapi.KindUndefined, workflows, nil
+		default:
+			return openapi.KindUndefined, nil, fmt.Errorf("not found: %s", u)
+		}
+	}
+	v, err := openapi.NewOfflineValidator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := arazzo.Load(context.Background(), "https://example.com/workflows.arazzo.yaml", v, fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Source("petstore") == nil {
+		t.Fatal("expected petstore to be loaded")
+	}
+	w := d.Workflow("adopt")
+	if w == nil {
+		t.Fatal("expected workflow adopt")
+	}
+	for _, s := range w.Steps {
+		target, err := d.ResolveOperation(&s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if target.Source != "petstore" || target.Document != d.Source("petstore") {
+			t.Errorf("unexpected source %q for step %q", target.Source, s.StepID)
+		}
+		if s.StepID == "fetch" && (target.Key != "/pets/{petId}" || target.Method != openapi.MethodGet) {
+			t.Errorf("expected fetch to resolve to GET /pets/{petId}, got %s %s", target.Method, target.Key)
+		}
+	}
+	ct := w.Step("fetch").SuccessCriteria[0].Type
+	if ct == nil || ct.Type != "jsonpath" || ct.Version == "" {
+		t.Errorf("expected criterion type to be decoded, got %+v", ct)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	v, err := openapi.NewOfflineValidator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := openapi.Load(context.Background(), "https://example.com/openapi.json", v, func(ctx context.Context, u uri.URI, kind openapi.Kind) (openapi.Kind, []byte, error) {
+		return openapi.KindDocument, petstore, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := arazzo.Parse([]byte(`{
+		"arazzo": "1.0.0",
+		"info": { "title": "Broken", "version": "1.0.0" },
+		"sourceDescriptions": [{ "name": "petstore", "url": "./openapi.json" }],
+		"workflows": [{
+			"workflowId": "broken",
+			"dependsOn": ["missing"],
+			"steps": [
+				{ "stepId": "a", "operationId": "deletePet" },
+				{
+					"stepId": "b",
+					"operationId": "getPet",
+					"parameters": [{ "name": "verbose", "in": "query", "value": true }],
+					"onSuccess": [{ "name": "next", "type": "goto", "stepId": "c" }]
+				},
+				{ "stepId": "b", "operationId": "getPet", "workflowId": "broken" }
+			]
+		}]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// sources which have not been added are not cross-validated
+	if err = d.Validate(); err == nil {
+		t.Fatal("expected an error")
+	}
+	var ve arazzo.ValidationErrors
+	if !errors.As(err, &ve) || len(ve) != 4 {
+		t.Fatalf("expected 4 errors prior to adding the source, got %v", err)
+	}
+
+	if err = d.AddSource("petstore", doc); err != nil {
+		t.Fatal(err)
+	}
+	err = d.Validate()
+	if !errors.As(err, &ve) {
+		t.Fatalf("expected ValidationErrors, got %v", err)
+	}
+	expected := []struct {
+		err error
+		ptr string
+	}{
+		{arazzo.ErrWorkflowNotFound, "/workflows/0/dependsOn/0"},
+		{arazzo.ErrOperationNotFound, "/workflows/0/steps/0/operationId"},
+		{arazzo.ErrUnknownParameter, "/workflows/0/steps/1/parameters/0"},
+		{arazzo.ErrStepNotFound, "/workflows/0/steps/1/onSuccess/0/stepId"},
+		{arazzo.ErrDuplicate, "/workflows/0/steps/2/stepId"},
+		{arazzo.ErrInvalidStep, "/workflows/0/steps/2"},
+	}
+	if len(ve) != len(expected) {
+		t.Fatalf("expected %d errors, got %d: %v", len(expected), len(ve), err)
+	}
+	for i, e := range expected {
+		if !errors.Is(ve[i], e.err) {
+			t.Errorf("expected error %d to be %v, got %v", i, e.err, ve[i])
+		}
+		var oe *openapi.Error
+		if !errors.As(ve[i], &oe) || oe.ResourceURI.Fragment != e.ptr {
+			t.Errorf("expected error %d to be located at %q, got %v", i, e.ptr, ve[i])
+		}
+	}
+
+	if _, err = arazzo.Parse([]byte(`{"arazzo": "2.0.0"}`)); !errors.Is(err, arazzo.ErrUnsupportedVersion) {
+		t.Errorf("expected ErrUnsupportedVersion, got %v", err)
+	}
+}
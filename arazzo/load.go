@@ -0,0 +1,108 @@
+package arazzo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chanced/openapi"
+	"github.com/chanced/uri"
+)
+
+// Load loads the Arazzo Document at documentURI and the Documents of its
+// source descriptions, resolving their urls relative to documentURI.
+//
+// OpenAPI sources are loaded with openapi.Load using validator, fn, and
+// opts. Arazzo sources are loaded recursively; fn is called with
+// openapi.KindUndefined for Arazzo Documents. Sources referenced by more
+// than one Arazzo Document are only loaded once.
+//
+// The Document is validated with Validate before it is returned.
+func Load(ctx context.Context, documentURI string, validator openapi.Validator, fn func(ctx context.Context, uri uri.URI, kind openapi.Kind) (openapi.Kind, []byte, error), opts ...openapi.LoadOpts) (*Document, error) {
+	if fn == nil {
+		panic("fn cannot be nil")
+	}
+	if documentURI == "" {
+		return nil, fmt.Errorf("arazzo: documentURI cannot be empty")
+	}
+	u, err := uri.Parse(documentURI)
+	if err != nil {
+		return nil, fmt.Errorf("arazzo: failed to parse documentURI: %w", err)
+	}
+	if u.Fragment != "" {
+		return nil, openapi.NewError(fmt.Errorf("arazzo: documentURI may not contain a fragment: received \"%s\"", u), *u)
+	}
+	l := &loader{
+		validator: validator,
+		fn:        fn,
+		opts:      opts,
+		openapi:   map[string]*openapi.Document{},
+		arazzo:    map[string]*Document{},
+	}
+	d, err := l.load(ctx, *u)
+	if err != nil {
+		return nil, err
+	}
+	if err = d.Validate(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+type loader struct {
+	validator openapi.Validator
+	fn        func(context.Context, uri.URI, openapi.Kind) (openapi.Kind, []byte, error)
+	opts      []openapi.LoadOpts
+	// openapi and arazzo are the Documents loaded so far, keyed by URI
+	openapi map[string]*openapi.Document
+	arazzo  map[string]*Document
+}
+
+func (l *loader) load(ctx context.Context, u uri.URI) (*Document, error) {
+	if d, ok := l.arazzo[u.String()]; ok {
+		return d, nil
+	}
+	_, data, err := l.fn(ctx, u, openapi.KindUndefined)
+	if err != nil {
+		return nil, openapi.NewError(err, u)
+	}
+	d, err := Parse(data)
+	if err != nil {
+		return nil, openapi.NewError(err, u)
+	}
+	d.uri = u
+	// added prior to loading sources in case of cycles
+	l.arazzo[u.String()] = d
+
+	for i, sd := range d.SourceDescriptions {
+		su, err := uri.Parse(sd.URL)
+		if err != nil {
+			return nil, d.newError(fmt.Errorf("arazzo: invalid url of source description %q: %w", sd.Name, err), "sourceDescriptions", fmt.Sprint(i), "url")
+		}
+		su = u.ResolveReference(su)
+		switch sd.Type {
+		case SourceTypeArazzo:
+			src, err := l.load(ctx, *su)
+			if err != nil {
+				return nil, err
+			}
+			if err = d.AddArazzoSource(sd.Name, src); err != nil {
+				return nil, d.newError(err, "sourceDescriptions", fmt.Sprint(i))
+			}
+		case "", SourceTypeOpenAPI:
+			src, ok := l.openapi[su.String()]
+			if !ok {
+				src, err = openapi.Load(ctx, su.String(), l.validator, l.fn, l.opts...)
+				if err != nil {
+					return nil, err
+				}
+				l.openapi[su.String()] = src
+			}
+			if err = d.AddSource(sd.Name, src); err != nil {
+				return nil, d.newError(err, "sourceDescriptions", fmt.Sprint(i))
+			}
+		default:
+			return nil, d.newError(fmt.Errorf("arazzo: unsupported source description type %q", sd.Type), "sourceDescriptions", fmt.Sprint(i), "type")
+		}
+	}
+	return d, nil
+}
@@ -0,0 +1,134 @@
+package arazzo
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/chanced/jsonpointer"
+	"github.com/chanced/openapi"
+)
+
+// OperationTarget is the Operation called by a Step.
+type OperationTarget struct {
+	// Source is the name of the source description of the Document which
+	// contains the Operation.
+	Source   string
+	Document *openapi.Document
+	openapi.OperationEntry
+}
+
+// ResolveOperation returns the Operation called by s, as identified by
+// either its OperationID or OperationPath.
+//
+// An unqualified operationId is matched against the Operations of each
+// OpenAPI source; ErrAmbiguousOperation is returned if more than one
+// matches.
+func (d *Document) ResolveOperation(s *Step) (*OperationTarget, error) {
+	switch {
+	case s.OperationID != "":
+		return d.resolveOperationID(s.OperationID)
+	case s.OperationPath != "":
+		return d.resolveOperationPath(s.OperationPath)
+	default:
+		return nil, fmt.Errorf("%w: step %q does not call an operation", ErrOperationNotFound, s.StepID)
+	}
+}
+
+func (d *Document) resolveOperationID(id string) (*OperationTarget, error) {
+	if strings.HasPrefix(id, "$") {
+		name, opID, ok := splitSourceExpression(id)
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidExpression, id)
+		}
+		doc, err := d.openAPISource(name)
+		if err != nil {
+			return nil, err
+		}
+		e, ok := findOperationID(doc, opID)
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrOperationNotFound, id)
+		}
+		return &OperationTarget{Source: name, Document: doc, OperationEntry: e}, nil
+	}
+	var found []*OperationTarget
+	var unloaded bool
+	for _, sd := range d.SourceDescriptions {
+		if !sd.isOpenAPI() {
+			continue
+		}
+		doc := d.documents[sd.Name]
+		if doc == nil {
+			unloaded = true
+			continue
+		}
+		if e, ok := findOperationID(doc, id); ok {
+			found = append(found, &OperationTarget{Source: sd.Name, Document: doc, OperationEntry: e})
+		}
+	}
+	switch {
+	case len(found) == 1:
+		return found[0], nil
+	case len(found) > 1:
+		return nil, fmt.Errorf("%w: %q is an operation of sources %q and %q", ErrAmbiguousOperation, id, found[0].Source, found[1].Source)
+	case unloaded:
+		return nil, fmt.Errorf("%w: %q", ErrSourceNotLoaded, id)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrOperationNotFound, id)
+	}
+}
+
+// resolveOperationPath resolves an operationPath of the form
+// "{$sourceDescriptions.<name>.url}#/paths/<path>/<method>"
+func (d *Document) resolveOperationPath(p string) (*OperationTarget, error) {
+	end := strings.IndexByte(p, '}')
+	if !strings.HasPrefix(p, "{") || end < 0 {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidExpression, p)
+	}
+	name, field, ok := splitSourceExpression(p[1:end])
+	if !ok || field != "url" || !strings.HasPrefix(p[end+1:], "#") {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidExpression, p)
+	}
+	ptr, err := jsonpointer.Parse(p[end+1:])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q: %v", ErrInvalidExpression, p, err)
+	}
+	doc, err := d.openAPISource(name)
+	if err != nil {
+		return nil, err
+	}
+	tokens := ptr.Tokens()
+	if len(tokens) != 4 || (tokens[1] != "paths" && tokens[1] != "webhooks") {
+		return nil, fmt.Errorf("%w: %q", ErrOperationNotFound, p)
+	}
+	webhook := tokens[1] == "webhooks"
+	for _, e := range doc.Operations() {
+		if e.Webhook == webhook && e.Key.String() == tokens[2] && strings.EqualFold(e.Method.String(), tokens[3]) {
+			return &OperationTarget{Source: name, Document: doc, OperationEntry: e}, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %q", ErrOperationNotFound, p)
+}
+
+func (d *Document) openAPISource(name string) (*openapi.Document, error) {
+	sd := d.SourceDescription(name)
+	if sd == nil {
+		return nil, fmt.Errorf("%w: %q", ErrSourceNotFound, name)
+	}
+	if !sd.isOpenAPI() {
+		return nil, fmt.Errorf("arazzo: source description %q is not of type %q", name, SourceTypeOpenAPI)
+	}
+	doc := d.documents[name]
+	if doc == nil {
+		return nil, fmt.Errorf("%w: %q", ErrSourceNotLoaded, name)
+	}
+	return doc, nil
+}
+
+func findOperationID(doc *openapi.Document, id string) (openapi.OperationEntry, bool) {
+	for _, e := range doc.Operations() {
+		if e.Operation.OperationID.String() == id {
+			return e, true
+		}
+	}
+	return openapi.OperationEntry{}, false
+}
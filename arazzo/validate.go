@@ -0,0 +1,382 @@
+package arazzo
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/chanced/jsonpointer"
+	"github.com/chanced/openapi"
+)
+
+// ValidationErrors is returned from Validate when a Document is not valid.
+// Each error is an *openapi.Error whose ResourceURI locates the offending
+// node of the Document.
+type ValidationErrors []error
+
+func (e ValidationErrors) Error() string {
+	b := strings.Builder{}
+	b.WriteString("arazzo: invalid document:")
+	for _, err := range e {
+		b.WriteString(fmt.Sprintf("\n- %s", err))
+	}
+	return b.String()
+}
+
+func (e ValidationErrors) As(target interface{}) bool {
+	for _, v := range e {
+		if errors.As(v, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e ValidationErrors) Is(err error) bool {
+	for _, v := range e {
+		if errors.Is(v, err) {
+			return true
+		}
+	}
+	return false
+}
+
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9_\-]+$`)
+
+// Validate checks that d is a valid Arazzo Document and that the Operations,
+// Parameters, and Workflows referenced by its steps exist. If d is not
+// valid, the returned error is a ValidationErrors.
+//
+// References to source descriptions whose Documents have not been loaded or
+// added are not checked.
+func (d *Document) Validate() error {
+	v := validator{doc: d}
+	v.validate()
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return v.errs
+}
+
+// newError wraps err with the location of the node at tokens
+func (d *Document) newError(err error, tokens ...string) error {
+	loc := d.uri
+	loc.Fragment = jsonpointer.New(tokens...).String()
+	loc.RawFragment = ""
+	return openapi.NewError(err, loc)
+}
+
+type validator struct {
+	doc  *Document
+	errs ValidationErrors
+}
+
+func (v *validator) report(err error, tokens ...string) {
+	if errors.Is(err, ErrSourceNotLoaded) {
+		return
+	}
+	v.errs = append(v.errs, v.doc.newError(err, tokens...))
+}
+
+func required(field string) error {
+	return fmt.Errorf("%w: %s", ErrRequired, field)
+}
+
+func path(tokens []string, more ...interface{}) []string {
+	p := append([]string(nil), tokens...)
+	for _, t := range more {
+		p = append(p, fmt.Sprint(t))
+	}
+	return p
+}
+
+func (v *validator) validate() {
+	d := v.doc
+	if d.Info.Title == "" {
+		v.report(required("title"), "info")
+	}
+	if d.Info.Version == "" {
+		v.report(required("version"), "info")
+	}
+	if len(d.SourceDescriptions) == 0 {
+		v.report(required("sourceDescriptions"))
+	}
+	names := map[string]bool{}
+	for i, sd := range d.SourceDescriptions {
+		p := path(nil, "sourceDescriptions", i)
+		switch {
+		case sd.Name == "":
+			v.report(required("name"), p...)
+		case !namePattern.MatchString(sd.Name):
+			v.report(fmt.Errorf("arazzo: source description name %q must match %s", sd.Name, namePattern), path(p, "name")...)
+		case names[sd.Name]:
+			v.report(fmt.Errorf("%w: source description %q", ErrDuplicate, sd.Name), path(p, "name")...)
+		}
+		names[sd.Name] = true
+		if sd.URL == "" {
+			v.report(required("url"), p...)
+		}
+		if sd.Type != "" && sd.Type != SourceTypeOpenAPI && sd.Type != SourceTypeArazzo {
+			v.report(fmt.Errorf("arazzo: unsupported source description type %q", sd.Type), path(p, "type")...)
+		}
+	}
+
+	if len(d.Workflows) == 0 {
+		v.report(required("workflows"))
+	}
+	ids := map[string]bool{}
+	for i := range d.Workflows {
+		w := &d.Workflows[i]
+		p := path(nil, "workflows", i)
+		switch {
+		case w.WorkflowID == "":
+			v.report(required("workflowId"), p...)
+		case ids[w.WorkflowID]:
+			v.report(fmt.Errorf("%w: workflow %q", ErrDuplicate, w.WorkflowID), path(p, "workflowId")...)
+		}
+		ids[w.WorkflowID] = true
+		v.validateWorkflow(w, p)
+	}
+}
+
+func (v *validator) validateWorkflow(w *Workflow, p []string) {
+	for i, dep := range w.DependsOn {
+		if err := v.doc.checkWorkflowID(dep); err != nil {
+			v.report(err, path(p, "dependsOn", i)...)
+		}
+	}
+	for i, prm := range w.Parameters {
+		if prm, ok := v.parameter(prm, path(p, "parameters", i)); ok && prm.Name == "" {
+			v.report(required("name"), path(p, "parameters", i)...)
+		}
+	}
+	if len(w.Steps) == 0 {
+		v.report(required("steps"), p...)
+	}
+	ids := map[string]bool{}
+	for i := range w.Steps {
+		s := &w.Steps[i]
+		sp := path(p, "steps", i)
+		switch {
+		case s.StepID == "":
+			v.report(required("stepId"), sp...)
+		case ids[s.StepID]:
+			v.report(fmt.Errorf("%w: step %q", ErrDuplicate, s.StepID), path(sp, "stepId")...)
+		}
+		ids[s.StepID] = true
+		v.validateStep(w, s, sp)
+	}
+	v.validateSuccessActions(w, w.SuccessActions, path(p, "successActions"))
+	v.validateFailureActions(w, w.FailureActions, path(p, "failureActions"))
+}
+
+func (v *validator) validateStep(w *Workflow, s *Step, p []string) {
+	targets := 0
+	for _, t := range []string{s.OperationID, s.OperationPath, s.WorkflowID} {
+		if t != "" {
+			targets++
+		}
+	}
+	if targets != 1 {
+		v.report(ErrInvalidStep, p...)
+	}
+
+	var params map[string]bool
+	switch {
+	case s.OperationID != "" || s.OperationPath != "":
+		field := "operationId"
+		if s.OperationID == "" {
+			field = "operationPath"
+		}
+		t, err := v.doc.ResolveOperation(s)
+		if err != nil {
+			v.report(err, path(p, field)...)
+			break
+		}
+		if ps, err := t.Operation.EffectiveParameters(t.PathItem); err == nil {
+			params = map[string]bool{}
+			for _, prm := range ps {
+				params[prm.In.String()+":"+prm.Name.String()] = true
+			}
+		}
+	case s.WorkflowID != "":
+		if err := v.doc.checkWorkflowID(s.WorkflowID); err != nil {
+			v.report(err, path(p, "workflowId")...)
+		}
+	}
+
+	for i, prm := range s.Parameters {
+		pp := path(p, "parameters", i)
+		prm, ok := v.parameter(prm, pp)
+		if !ok {
+			continue
+		}
+		if prm.Name == "" {
+			v.report(required("name"), pp...)
+			continue
+		}
+		switch {
+		case s.WorkflowID != "":
+			if prm.In != "" {
+				v.report(fmt.Errorf("arazzo: parameter %q of a step which calls a workflow must not specify in", prm.Name), path(pp, "in")...)
+			}
+		case prm.In == "":
+			v.report(required("in"), pp...)
+		case prm.In != openapi.InPath && prm.In != openapi.InQuery && prm.In != openapi.InHeader && prm.In != openapi.InCookie:
+			v.report(fmt.Errorf("arazzo: invalid parameter location %q", prm.In), path(pp, "in")...)
+		case params != nil && !params[prm.In.String()+":"+prm.Name]:
+			v.report(fmt.Errorf("%w: %q in %s", ErrUnknownParameter, prm.Name, prm.In), pp...)
+		}
+	}
+	v.validateSuccessActions(w, s.OnSuccess, path(p, "onSuccess"))
+	v.validateFailureActions(w, s.OnFailure, path(p, "onFailure"))
+}
+
+// parameter returns prm or, if prm is a reusable object, the component
+// Parameter it references with its value overridden
+func (v *validator) parameter(prm Parameter, p []string) (Parameter, bool) {
+	if prm.Reference == "" {
+		return prm, true
+	}
+	name, err := componentName(prm.Reference, "parameters")
+	if err != nil {
+		v.report(err, path(p, "reference")...)
+		return prm, false
+	}
+	c, ok := Parameter{}, false
+	if v.doc.Components != nil {
+		c, ok = v.doc.Components.Parameters[name]
+	}
+	if !ok {
+		v.report(fmt.Errorf("%w: %q", ErrComponentNotFound, prm.Reference), path(p, "reference")...)
+		return prm, false
+	}
+	if prm.Value != nil {
+		c.Value = prm.Value
+	}
+	return c, true
+}
+
+func (v *validator) validateSuccessActions(w *Workflow, actions []SuccessAction, p []string) {
+	for i, a := range actions {
+		ap := path(p, i)
+		if a.Reference != "" {
+			name, err := componentName(a.Reference, "successActions")
+			if err != nil {
+				v.report(err, path(ap, "reference")...)
+				continue
+			}
+			if v.doc.Components == nil {
+				v.report(fmt.Errorf("%w: %q", ErrComponentNotFound, a.Reference), path(ap, "reference")...)
+				continue
+			}
+			c, ok := v.doc.Components.SuccessActions[name]
+			if !ok {
+				v.report(fmt.Errorf("%w: %q", ErrComponentNotFound, a.Reference), path(ap, "reference")...)
+				continue
+			}
+			a = c
+		}
+		if a.Type == ActionRetry {
+			v.report(fmt.Errorf("%w: type %q is only valid for failure actions", ErrInvalidAction, a.Type), path(ap, "type")...)
+			continue
+		}
+		v.validateAction(w, a.Name, a.Type, a.WorkflowID, a.StepID, ap)
+	}
+}
+
+func (v *validator) validateFailureActions(w *Workflow, actions []FailureAction, p []string) {
+	for i, a := range actions {
+		ap := path(p, i)
+		if a.Reference != "" {
+			name, err := componentName(a.Reference, "failureActions")
+			if err != nil {
+				v.report(err, path(ap, "reference")...)
+				continue
+			}
+			if v.doc.Components == nil {
+				v.report(fmt.Errorf("%w: %q", ErrComponentNotFound, a.Reference), path(ap, "reference")...)
+				continue
+			}
+			c, ok := v.doc.Components.FailureActions[name]
+			if !ok {
+				v.report(fmt.Errorf("%w: %q", ErrComponentNotFound, a.Reference), path(ap, "reference")...)
+				continue
+			}
+			a = c
+		}
+		v.validateAction(w, a.Name, a.Type, a.WorkflowID, a.StepID, ap)
+	}
+}
+
+func (v *validator) validateAction(w *Workflow, name, typ, workflowID, stepID string, p []string) {
+	if name == "" {
+		v.report(required("name"), p...)
+	}
+	switch typ {
+	case "":
+		v.report(required("type"), p...)
+		return
+	case ActionEnd:
+		return
+	case ActionGoto, ActionRetry:
+	default:
+		v.report(fmt.Errorf("%w: unknown type %q", ErrInvalidAction, typ), path(p, "type")...)
+		return
+	}
+	switch {
+	case workflowID != "" && stepID != "":
+		v.report(fmt.Errorf("%w: workflowId and stepId are mutually exclusive", ErrInvalidAction), p...)
+	case workflowID != "":
+		if err := v.doc.checkWorkflowID(workflowID); err != nil {
+			v.report(err, path(p, "workflowId")...)
+		}
+	case stepID != "":
+		if w.Step(stepID) == nil {
+			v.report(fmt.Errorf("%w: %q", ErrStepNotFound, stepID), path(p, "stepId")...)
+		}
+	case typ == ActionGoto:
+		v.report(fmt.Errorf("%w: goto requires either workflowId or stepId", ErrInvalidAction), p...)
+	}
+}
+
+// checkWorkflowID checks that id references a Workflow of d or, if id is a
+// runtime expression, of an Arazzo source
+func (d *Document) checkWorkflowID(id string) error {
+	if !strings.HasPrefix(id, "$") {
+		if d.Workflow(id) == nil {
+			return fmt.Errorf("%w: %q", ErrWorkflowNotFound, id)
+		}
+		return nil
+	}
+	name, wid, ok := splitSourceExpression(id)
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrInvalidExpression, id)
+	}
+	sd := d.SourceDescription(name)
+	if sd == nil {
+		return fmt.Errorf("%w: %q", ErrSourceNotFound, name)
+	}
+	if sd.Type != SourceTypeArazzo {
+		return fmt.Errorf("arazzo: source description %q is not of type %q", name, SourceTypeArazzo)
+	}
+	src := d.workflows[name]
+	if src == nil {
+		return fmt.Errorf("%w: %q", ErrSourceNotLoaded, name)
+	}
+	if src.Workflow(wid) == nil {
+		return fmt.Errorf("%w: %q", ErrWorkflowNotFound, id)
+	}
+	return nil
+}
+
+// componentName returns the name of the component of kind referenced by ref
+// (e.g. "page" for "$components.parameters.page")
+func componentName(ref, kind string) (string, error) {
+	prefix := "$components." + kind + "."
+	if !strings.HasPrefix(ref, prefix) || len(ref) == len(prefix) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidExpression, ref)
+	}
+	return ref[len(prefix):], nil
+}
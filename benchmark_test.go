@@ -0,0 +1,174 @@
+package openapi_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chanced/openapi"
+	"github.com/chanced/uri"
+)
+
+// benchmarkSizes are the number of resources (paths and schemas) of the
+// generated Documents which are benchmarked
+var benchmarkSizes = []int{10, 100, 1000}
+
+// generateDocument returns a Document with n paths, each with operations
+// which reference one of n Schemas.
+func generateDocument(n int) []byte {
+	b := strings.Builder{}
+	b.WriteString(`{"openapi":"3.1.0","info":{"title":"Generated","version":"1.0.0"},"paths":{`)
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, `"/resources%[1]d/{id}":{"parameters":[{"name":"id","in":"path","required":true,"schema":{"type":"string","format":"uuid"}}],`+
+			`"get":{"operationId":"getResource%[1]d","responses":{"200":{"description":"ok","content":{"application/json":{"schema":{"$ref":"#/components/schemas/Resource%[1]d"}}}},"default":{"$ref":"#/components/responses/Error"}}},`+
+			`"put":{"operationId":"putResource%[1]d","requestBody":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/Resource%[1]d"}}}},"responses":{"204":{"description":"updated"}}}}`, i)
+	}
+	b.WriteString(`},"components":{"responses":{"Error":{"description":"error","content":{"application/json":{"schema":{"$ref":"#/components/schemas/Error"}}}}},"schemas":{`)
+	b.WriteString(`"Error":{"type":"object","required":["message"],"properties":{"message":{"type":"string"}}}`)
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, `,"Resource%[1]d":{"type":"object","required":["id","name"],"properties":{"id":{"type":"string","format":"uuid"},"name":{"type":"string","minLength":1},`+
+			`"tags":{"type":"array","items":{"type":"string"}},"created":{"type":"string","format":"date-time"},"next":{"$ref":"#/components/schemas/Resource%[2]d"}}}`, i, (i+1)%n)
+	}
+	b.WriteString(`}}}`)
+	return []byte(b.String())
+}
+
+func benchmarkLoad(b *testing.B, data []byte, v openapi.Validator) {
+	fn := func(ctx context.Context, u uri.URI, kind openapi.Kind) (openapi.Kind, []byte, error) {
+		return openapi.KindDocument, data, nil
+	}
+	var total openapi.LoadProfile
+	var p openapi.LoadProfile
+	b.ReportAllocs()
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := openapi.Load(context.Background(), "https://example.com/openapi.json", v, fn, openapi.LoadOpts{Profile: &p}); err != nil {
+			b.Fatal(err)
+		}
+		total.Unmarshal += p.Unmarshal
+		total.SetLocation += p.SetLocation
+		total.Anchors += p.Anchors
+		total.Resolve += p.Resolve
+		total.Validation += p.Validation
+	}
+	report := func(d time.Duration, unit string) {
+		b.ReportMetric(float64(d.Nanoseconds())/float64(b.N), unit)
+	}
+	report(total.Unmarshal, "unmarshal-ns/op")
+	report(total.SetLocation, "setlocation-ns/op")
+	report(total.Anchors, "anchors-ns/op")
+	report(total.Resolve, "resolve-ns/op")
+	report(total.Validation, "validation-ns/op")
+}
+
+// BenchmarkLoad benchmarks Load without validation, reporting the time spent
+// in each phase of loading (see LoadProfile).
+func BenchmarkLoad(b *testing.B) {
+	for _, n := range benchmarkSizes {
+		data := generateDocument(n)
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			benchmarkLoad(b, data, nil)
+		})
+	}
+}
+
+// BenchmarkLoadValidated benchmarks Load with a StdValidator.
+func BenchmarkLoadValidated(b *testing.B) {
+	v, err := openapi.NewOfflineValidator()
+	if err != nil {
+		b.Fatal(err)
+	}
+	for _, n := range benchmarkSizes {
+		data := generateDocument(n)
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			benchmarkLoad(b, data, v)
+		})
+	}
+}
+
+func BenchmarkDocumentUnmarshalJSON(b *testing.B) {
+	for _, n := range benchmarkSizes {
+		data := generateDocument(n)
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(data)))
+			for i := 0; i < b.N; i++ {
+				var doc openapi.Document
+				if err := json.Unmarshal(data, &doc); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkDocumentMarshalJSON(b *testing.B) {
+	for _, n := range benchmarkSizes {
+		var doc openapi.Document
+		if err := json.Unmarshal(generateDocument(n), &doc); err != nil {
+			b.Fatal(err)
+		}
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := doc.MarshalJSON(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkValidateDocument compares validating an edited Document in full
+// with validating it incrementally.
+func BenchmarkValidateDocument(b *testing.B) {
+	sv, err := openapi.NewOfflineValidator()
+	if err != nil {
+		b.Fatal(err)
+	}
+	for _, n := range benchmarkSizes {
+		data := generateDocument(n)
+		fn := func(ctx context.Context, u uri.URI, kind openapi.Kind) (openapi.Kind, []byte, error) {
+			return openapi.KindDocument, data, nil
+		}
+		doc, err := openapi.Load(context.Background(), "https://example.com/openapi.json", nil, fn)
+		if err != nil {
+			b.Fatal(err)
+		}
+		schema := doc.Components.Schemas.Get("Resource0")
+		b.Run(fmt.Sprintf("%d/full", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				schema.Description = openapi.Text(fmt.Sprint(i))
+				if err := sv.ValidateDocument(doc); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("%d/incremental", n), func(b *testing.B) {
+			iv, err := openapi.NewIncrementalValidator(sv)
+			if err != nil {
+				b.Fatal(err)
+			}
+			if err = iv.ValidateDocument(doc); err != nil {
+				b.Fatal(err)
+			}
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				schema.Description = openapi.Text(fmt.Sprint(i))
+				iv.InvalidateNode(schema)
+				if err := iv.ValidateDocument(doc); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
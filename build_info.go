@@ -0,0 +1,124 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"runtime/debug"
+	"time"
+)
+
+const (
+	// ExtensionBuildCommit is the extension of Info which holds the revision
+	// of the source control commit from which a Document was built.
+	ExtensionBuildCommit Text = "x-build-commit"
+	// ExtensionBuildTime is the extension of Info which holds the time, in
+	// RFC 3339 format, at which a Document was built.
+	ExtensionBuildTime Text = "x-build-time"
+	// ExtensionBuildModified is the extension of Info which indicates that
+	// the working tree had uncommitted changes when a Document was built.
+	ExtensionBuildModified Text = "x-build-modified"
+)
+
+var commitPattern = regexp.MustCompile(`^[0-9a-f]{7,64}$`)
+
+// BuildInfo is the build metadata of a Document, stored in the extensions of
+// its Info.
+type BuildInfo struct {
+	// Commit is the revision (e.g. a git SHA-1 or SHA-256 hash, abbreviated
+	// or in full) of the commit from which the Document was built.
+	Commit Text
+	// Time is the time at which the Document was built.
+	Time time.Time
+	// Modified indicates that the working tree had uncommitted changes.
+	Modified bool
+}
+
+// IsZero reports whether b is empty.
+func (b BuildInfo) IsZero() bool {
+	return b.Commit == "" && b.Time.IsZero() && !b.Modified
+}
+
+// ReadBuildInfo returns the version control information stamped into the
+// running binary by the go command (see debug.ReadBuildInfo). The returned
+// BuildInfo is empty if the binary was built without version control
+// information.
+func ReadBuildInfo() BuildInfo {
+	var b BuildInfo
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return b
+	}
+	for _, s := range bi.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			b.Commit = Text(s.Value)
+		case "vcs.time":
+			b.Time, _ = time.Parse(time.RFC3339, s.Value)
+		case "vcs.modified":
+			b.Modified = s.Value == "true"
+		}
+	}
+	return b
+}
+
+// SetBuildInfo stamps b onto the extensions of i, replacing any existing
+// build metadata. Empty fields of b are removed. The time is stored in UTC.
+//
+// An error is returned if the commit of b is not a lowercase hexadecimal
+// revision of between 7 and 64 characters.
+func (i *Info) SetBuildInfo(b BuildInfo) error {
+	if b.Commit != "" && !commitPattern.MatchString(b.Commit.String()) {
+		return fmt.Errorf("openapi: invalid build commit %q", b.Commit)
+	}
+	i.DeleteExtension(ExtensionBuildCommit)
+	i.DeleteExtension(ExtensionBuildTime)
+	i.DeleteExtension(ExtensionBuildModified)
+	if b.Commit != "" {
+		if err := i.SetExtension(ExtensionBuildCommit, b.Commit); err != nil {
+			return err
+		}
+	}
+	if !b.Time.IsZero() {
+		if err := i.SetExtension(ExtensionBuildTime, b.Time.UTC().Format(time.RFC3339)); err != nil {
+			return err
+		}
+	}
+	if b.Modified {
+		if err := i.SetExtension(ExtensionBuildModified, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BuildInfo returns the build metadata stamped onto the extensions of i. The
+// returned BuildInfo is empty if i has none.
+//
+// An error is returned if any of the extensions can not be decoded.
+func (i *Info) BuildInfo() (BuildInfo, error) {
+	var b BuildInfo
+	if i == nil {
+		return b, nil
+	}
+	if data, ok := i.Extensions[ExtensionBuildCommit]; ok {
+		if err := json.Unmarshal(data, &b.Commit); err != nil {
+			return b, i.buildInfoErr(ExtensionBuildCommit, err)
+		}
+	}
+	if data, ok := i.Extensions[ExtensionBuildTime]; ok {
+		if err := json.Unmarshal(data, &b.Time); err != nil {
+			return b, i.buildInfoErr(ExtensionBuildTime, err)
+		}
+	}
+	if data, ok := i.Extensions[ExtensionBuildModified]; ok {
+		if err := json.Unmarshal(data, &b.Modified); err != nil {
+			return b, i.buildInfoErr(ExtensionBuildModified, err)
+		}
+	}
+	return b, nil
+}
+
+func (i *Info) buildInfoErr(key Text, err error) error {
+	return NewError(fmt.Errorf("openapi: failed to decode extension %q: %w", key, err), i.AbsoluteLocation())
+}
@@ -0,0 +1,290 @@
+package openapi
+
+import (
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/chanced/uri"
+)
+
+// Bundle makes the Document self-contained by moving the nodes it references
+// from other resources into its Components. The Document must have been
+// loaded, or resolved with Resolve, so that its references are resolved.
+//
+// Each node of another resource which is referenced, directly or through
+// another referenced node, is added to the Components map of its type (e.g.
+// a Schema to "schemas" and a Response to "responses") and each reference to
+// it is rewritten as a fragment (e.g. "#/components/schemas/Pet"). Components
+// are named after the last token of the node's JSON pointer or, for the root
+// of a resource, the name of its file without extension (e.g. "pet" for
+// "schemas/pet.json"), with a numeric suffix if the name is already taken. A
+// node nested within another bundled node is referenced within it rather
+// than bundled separately.
+//
+// References to the Document itself which include its path (e.g.
+// "openapi.yaml#/components/schemas/Pet") are rewritten as fragments.
+// Unresolved references, $dynamicRefs and $recursiveRefs, references within
+// Schemas which have an $id, and OperationRefs to other resources are left
+// as is.
+//
+// Bundle modifies the Document and the referenced nodes, which are relocated
+// to their position within the Document.
+func (d *Document) Bundle() error {
+	if d == nil {
+		return nil
+	}
+	root := d.AbsoluteLocation()
+	root.Fragment = ""
+	root.RawFragment = ""
+	b := bundler{
+		doc:     d,
+		root:    root.String(),
+		targets: map[node]string{},
+	}
+
+	// references within Schemas which have an $id are resolved relative to
+	// the $id rather than the resource
+	scoped := map[node]bool{}
+	_ = walkNodes(d, func(n node) error {
+		s, ok := n.(*Schema)
+		if !ok || s.ID == nil || scoped[s] {
+			return nil
+		}
+		return walkLocalNodes(s, func(c node) error {
+			if c != n {
+				scoped[c] = true
+			}
+			return nil
+		})
+	})
+
+	var refs []Ref
+	err := walkNodes(d, func(n node) error {
+		r, ok := n.(Ref)
+		if !ok || scoped[n] || r.URI() == nil {
+			return nil
+		}
+		switch r.RefType() {
+		case RefTypeSchemaDynamicRef, RefTypeSchemaRecursiveRef:
+			return nil
+		}
+		target, ok := r.ResolvedNode().(node)
+		if !ok || target == nil || target.isNil() {
+			return nil
+		}
+		refs = append(refs, r)
+		if !b.local(target) {
+			b.targets[target] = target.AbsoluteLocation().String()
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if err = b.bundleTargets(); err != nil {
+		return err
+	}
+	for _, r := range refs {
+		target := r.ResolvedNode().(node)
+		var ptr string
+		if b.local(target) {
+			u := r.URI()
+			if u.Host == "" && u.Path == "" {
+				// fragments are already relative to the Document
+				continue
+			}
+			ptr = target.AbsoluteLocation().Fragment
+		} else if p, ok := b.pointers[target]; ok {
+			ptr = p
+		} else {
+			// OperationRefs can not be bundled
+			continue
+		}
+		*r.URI() = uri.URI{Fragment: ptr}
+	}
+	return d.setLocation(d.Location)
+}
+
+type bundler struct {
+	doc  *Document
+	root string
+	// targets are the absolute locations of the referenced nodes of other
+	// resources
+	targets map[node]string
+	// pointers are the JSON pointers of the bundled targets within the
+	// Document
+	pointers map[node]string
+}
+
+// local reports whether n is within the resource of the Document
+func (b *bundler) local(n node) bool {
+	loc := n.AbsoluteLocation()
+	loc.Fragment = ""
+	loc.RawFragment = ""
+	return loc.String() == b.root
+}
+
+// bundleTargets adds each target which is not nested within another target to
+// the Components of the Document and assigns the JSON pointer of each target
+func (b *bundler) bundleTargets() error {
+	type target struct {
+		node     node
+		resource string
+		ptr      string
+	}
+	targets := make([]target, 0, len(b.targets))
+	for n, loc := range b.targets {
+		resource, ptr := loc, ""
+		if i := strings.IndexByte(loc, '#'); i >= 0 {
+			resource, ptr = loc[:i], loc[i+1:]
+		}
+		targets = append(targets, target{node: n, resource: resource, ptr: ptr})
+	}
+	// ancestors precede their descendants
+	sort.Slice(targets, func(i, j int) bool {
+		if targets[i].resource != targets[j].resource {
+			return targets[i].resource < targets[j].resource
+		}
+		if len(targets[i].ptr) != len(targets[j].ptr) {
+			return len(targets[i].ptr) < len(targets[j].ptr)
+		}
+		return targets[i].ptr < targets[j].ptr
+	})
+	b.pointers = make(map[node]string, len(targets))
+	var bundled []target
+	for _, t := range targets {
+		var nested bool
+		for _, a := range bundled {
+			if a.resource == t.resource && (a.ptr == "" || strings.HasPrefix(t.ptr, a.ptr+"/")) {
+				b.pointers[t.node] = b.pointers[a.node] + t.ptr[len(a.ptr):]
+				nested = true
+				break
+			}
+		}
+		if nested {
+			continue
+		}
+		name := bundleName(t.resource, t.ptr)
+		ptr, ok := b.doc.addComponent(name, t.node)
+		if !ok {
+			continue
+		}
+		b.pointers[t.node] = ptr
+		bundled = append(bundled, t)
+	}
+	return nil
+}
+
+// bundleName returns the name of the component for the node located at ptr
+// within resource
+func bundleName(resource, ptr string) Text {
+	name := ""
+	if ptr != "" {
+		toks := splitPointerTokens(ptr)
+		name = toks[len(toks)-1]
+	} else {
+		name = path.Base(resource)
+		name = strings.TrimSuffix(name, path.Ext(name))
+	}
+	name = strings.Trim(unsafeFileNameChars.ReplaceAllString(name, "_"), "_")
+	if name == "" {
+		name = "bundled"
+	}
+	return Text(name)
+}
+
+// addComponent adds n to the Components map of its type under name or, if
+// name is taken, name with the lowest numeric suffix, starting at 2, which is
+// not. The JSON pointer of the component is returned along with false if n
+// can not be a component.
+func (d *Document) addComponent(name Text, n node) (string, bool) {
+	if d.Components == nil {
+		d.Components = &Components{}
+	}
+	c := d.Components
+	var section string
+	var has func(Text) bool
+	var set func(Text)
+	switch v := n.(type) {
+	case *Schema:
+		if c.Schemas == nil {
+			c.Schemas = &SchemaMap{}
+		}
+		section = "schemas"
+		has = func(k Text) bool { return c.Schemas.Get(k) != nil }
+		set = func(k Text) { c.Schemas.Set(k, v) }
+	case *Response:
+		if c.Responses == nil {
+			c.Responses = &ResponseMap{}
+		}
+		section = "responses"
+		has = func(k Text) bool { return c.Responses.Get(k) != nil }
+		set = func(k Text) { c.Responses.SetObject(k, v) }
+	case *Parameter:
+		if c.Parameters == nil {
+			c.Parameters = &ParameterMap{}
+		}
+		section = "parameters"
+		has = func(k Text) bool { return c.Parameters.Get(k) != nil }
+		set = func(k Text) { c.Parameters.SetObject(k, v) }
+	case *Example:
+		if c.Examples == nil {
+			c.Examples = &ExampleMap{}
+		}
+		section = "examples"
+		has = func(k Text) bool { return c.Examples.Get(k) != nil }
+		set = func(k Text) { c.Examples.SetObject(k, v) }
+	case *RequestBody:
+		if c.RequestBodies == nil {
+			c.RequestBodies = &RequestBodyMap{}
+		}
+		section = "requestBodies"
+		has = func(k Text) bool { return c.RequestBodies.Get(k) != nil }
+		set = func(k Text) { c.RequestBodies.SetObject(k, v) }
+	case *Header:
+		if c.Headers == nil {
+			c.Headers = &HeaderMap{}
+		}
+		section = "headers"
+		has = func(k Text) bool { return c.Headers.Get(k) != nil }
+		set = func(k Text) { c.Headers.SetObject(k, v) }
+	case *SecurityScheme:
+		if c.SecuritySchemes == nil {
+			c.SecuritySchemes = &SecuritySchemeMap{}
+		}
+		section = "securitySchemes"
+		has = func(k Text) bool { return c.SecuritySchemes.Get(k) != nil }
+		set = func(k Text) { c.SecuritySchemes.SetObject(k, v) }
+	case *Link:
+		if c.Links == nil {
+			c.Links = &LinkMap{}
+		}
+		section = "links"
+		has = func(k Text) bool { return c.Links.Get(k) != nil }
+		set = func(k Text) { c.Links.SetObject(k, v) }
+	case *Callbacks:
+		if c.Callbacks == nil {
+			c.Callbacks = &CallbacksMap{}
+		}
+		section = "callbacks"
+		has = func(k Text) bool { return c.Callbacks.Get(k) != nil }
+		set = func(k Text) { c.Callbacks.SetObject(k, v) }
+	case *PathItem:
+		if c.PathItems == nil {
+			c.PathItems = &PathItemMap{}
+		}
+		section = "pathItems"
+		has = func(k Text) bool { return c.PathItems.Get(k) != nil }
+		set = func(k Text) { c.PathItems.SetObject(k, v) }
+	default:
+		return "", false
+	}
+	key := name
+	for i := 2; has(key); i++ {
+		key = name + Text(strconv.Itoa(i))
+	}
+	set(key)
+	return "/components/" + section + "/" + escapePointerToken(key.String()), true
+}
@@ -0,0 +1,135 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/chanced/openapi"
+)
+
+func TestDocumentBundle(t *testing.T) {
+	doc := loadResources(t, "https://example.com/api/openapi.json", map[string][]byte{
+		"https://example.com/api/openapi.json": []byte(`{
+			"openapi": "3.1.0",
+			"info": { "title": "Pet Store", "version": "1.0.0" },
+			"paths": {
+				"/pets": {
+					"get": {
+						"responses": {
+							"200": { "$ref": "shared.json#/components/responses/Pets" },
+							"default": { "$ref": "openapi.json#/components/responses/Error" }
+						}
+					}
+				}
+			},
+			"components": {
+				"schemas": {
+					"pet": { "type": "string" }
+				},
+				"responses": {
+					"Error": { "description": "error" }
+				}
+			}
+		}`),
+		"https://example.com/api/shared.json": []byte(`{
+			"openapi": "3.1.0",
+			"info": { "title": "Shared", "version": "1.0.0" },
+			"components": {
+				"responses": {
+					"Pets": {
+						"description": "pets",
+						"content": {
+							"application/json": {
+								"schema": { "type": "array", "items": { "$ref": "schemas/pet.json" } }
+							}
+						}
+					}
+				}
+			}
+		}`),
+		"https://example.com/api/schemas/pet.json": []byte(`{
+			"type": "object",
+			"properties": {
+				"owner": { "$ref": "owner.json" },
+				"tag": { "$ref": "#/$defs/Tag" }
+			},
+			"$defs": {
+				"Tag": { "type": "string" }
+			}
+		}`),
+		"https://example.com/api/schemas/owner.json": []byte(`{
+			"type": "object",
+			"properties": {
+				"name": { "type": "string" },
+				"tag": { "$ref": "pet.json#/$defs/Tag" }
+			}
+		}`),
+	})
+	if err := doc.Bundle(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var refs []string
+	var collect func(v interface{})
+	collect = func(v interface{}) {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			for k, c := range v {
+				if k == "$ref" {
+					refs = append(refs, c.(string))
+				}
+				collect(c)
+			}
+		case []interface{}:
+			for _, c := range v {
+				collect(c)
+			}
+		}
+	}
+	var raw interface{}
+	if err = json.Unmarshal(data, &raw); err != nil {
+		t.Fatal(err)
+	}
+	collect(raw)
+	if len(refs) != 6 {
+		t.Errorf("expected 6 references, got %v", refs)
+	}
+	for _, ref := range refs {
+		if !strings.HasPrefix(ref, "#/") {
+			t.Errorf("expected %q to be a fragment", ref)
+		}
+	}
+
+	responses := doc.Paths.Get("/pets").Get.Responses
+	if ref := responses.Get("200").Reference.Ref.String(); ref != "#/components/responses/Pets" {
+		t.Errorf("expected the response to be bundled, got %q", ref)
+	}
+	if ref := responses.Get("default").Reference.Ref.String(); ref != "#/components/responses/Error" {
+		t.Errorf("expected the reference to the Document to be a fragment, got %q", ref)
+	}
+	schemas := doc.Components.Schemas
+	if schemas.Get("pet") == nil || schemas.Get("pet2") == nil || schemas.Get("owner") == nil {
+		t.Fatalf("expected pet2 and owner to be bundled, got %v", schemaKeys(schemas))
+	}
+	if schemas.Get("Tag") != nil {
+		t.Error("expected Tag to be referenced within pet2")
+	}
+	if ref := schemas.Get("owner").Properties.Get("tag").Ref.Ref.String(); ref != "#/components/schemas/pet2/$defs/Tag" {
+		t.Errorf("expected the nested reference to be within pet2, got %q", ref)
+	}
+	if loc := schemas.Get("owner").AbsoluteLocation().String(); loc != "https://example.com/api/openapi.json#/components/schemas/owner" {
+		t.Errorf("expected owner to be relocated, got %q", loc)
+	}
+
+	v, err := openapi.NewOfflineValidator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = v.ValidateDocument(doc); err != nil {
+		t.Errorf("expected the bundled Document to be valid: %v", err)
+	}
+}
@@ -0,0 +1,253 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/chanced/jsonpointer"
+	"github.com/chanced/uri"
+)
+
+// ExtensionCallbacks is the extension of a Document which records the
+// callbacks which the webhooks created by LiftCallbacks were lifted from,
+// keyed by the name of the webhook, so that LowerCallbacks can restore them.
+const ExtensionCallbacks Text = "x-callbacks"
+
+// CallbackOrigin identifies the callback of an Operation.
+type CallbackOrigin struct {
+	// Key is the path of the PathItem or, if Webhook is true, the name of
+	// the webhook which contains the Operation declaring the callback.
+	Key     Text `json:"key"`
+	Webhook bool `json:"webhook,omitempty"`
+	Method  Text `json:"method"`
+	// Name is the name of the callback within the Operation's Callbacks.
+	Name Text `json:"name"`
+	// Expression is the runtime expression which identifies the URL of the
+	// callback request (e.g. "{$request.body#/callbackUrl}").
+	Expression Text `json:"expression"`
+	// Ref is the reference of the Operation's callback if it is a Reference
+	// (e.g. "#/components/callbacks/petEvents").
+	Ref string `json:"ref,omitempty"`
+}
+
+// CallbackEntry is the PathItem of a callback along with the Operation which
+// declares it.
+type CallbackEntry struct {
+	CallbackOrigin
+	// Operation is the Operation which declares the callback.
+	Operation *Operation
+	// PathItem describes the requests of the callback.
+	PathItem *PathItem
+}
+
+// CallbackItems returns each callback of the Operations of the Document's
+// Paths and Webhooks, in order. References are resolved; callbacks which are
+// unresolved references are omitted.
+func (d *Document) CallbackItems() []CallbackEntry {
+	var entries []CallbackEntry
+	for _, op := range d.Operations() {
+		if op.Operation.Callbacks == nil {
+			continue
+		}
+		for _, cb := range op.Operation.Callbacks.Items {
+			c := cb.Component
+			if c == nil || c.Object == nil {
+				continue
+			}
+			var ref string
+			if c.IsReference() && c.Reference.Ref != nil {
+				ref = c.Reference.Ref.String()
+			}
+			for _, item := range c.Object.PathItems.Items {
+				if item.Value == nil {
+					continue
+				}
+				entries = append(entries, CallbackEntry{
+					CallbackOrigin: CallbackOrigin{
+						Key:        op.Key,
+						Webhook:    op.Webhook,
+						Method:     op.Method,
+						Name:       cb.Key,
+						Expression: item.Key,
+						Ref:        ref,
+					},
+					Operation: op.Operation,
+					PathItem:  item.Value,
+				})
+			}
+		}
+	}
+	return entries
+}
+
+// LiftedCallback is a callback which was moved into the Webhooks of a
+// Document by LiftCallbacks or back into the Callbacks of its Operation by
+// LowerCallbacks.
+type LiftedCallback struct {
+	// Webhook is the name of the webhook.
+	Webhook Text
+	Origin  CallbackOrigin
+}
+
+// LiftCallbacks moves the callbacks of each Operation into the Document's
+// Webhooks, providing a single inventory of the out-of-band requests the API
+// may make. The callbacks of the Operations of existing webhooks are lifted
+// as well.
+//
+// Webhooks are named after the operationId of their Operation, or one
+// generated by OperationIDCamelCase, and the name of the callback (e.g.
+// "createPet.onAdopted"), suffixed with a number if the name is taken. The
+// origin of each webhook is recorded in the ExtensionCallbacks extension of
+// the Document. Components referenced by callbacks are left in place.
+//
+// Webhooks were introduced in OpenAPI 3.1; the Document must be updated
+// accordingly if it is of an earlier version.
+//
+// The lifted callbacks are returned in order.
+func (d *Document) LiftCallbacks() ([]LiftedCallback, error) {
+	if d == nil {
+		return nil, nil
+	}
+	entries := d.CallbackItems()
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	origins := map[Text]CallbackOrigin{}
+	if err := d.decodeCallbackOrigins(origins); err != nil {
+		return nil, err
+	}
+	if d.Webhooks == nil {
+		d.Webhooks = &PathItemMap{}
+	}
+	used := map[Text]bool{}
+	for _, k := range d.Webhooks.Keys() {
+		used[k] = true
+	}
+	lifted := make([]LiftedCallback, 0, len(entries))
+	for _, e := range entries {
+		base := e.Operation.OperationID
+		if base == "" {
+			base = OperationIDCamelCase(e.Method, e.Key)
+		}
+		base += "." + e.Name
+		name := base
+		for n := 2; used[name]; n++ {
+			name = base + Text(strconv.Itoa(n))
+		}
+		used[name] = true
+		d.Webhooks.SetObject(name, e.PathItem)
+		origins[name] = e.CallbackOrigin
+		lifted = append(lifted, LiftedCallback{Webhook: name, Origin: e.CallbackOrigin})
+	}
+	for _, e := range entries {
+		e.Operation.Callbacks = nil
+	}
+	if err := d.SetExtension(ExtensionCallbacks, origins); err != nil {
+		return lifted, err
+	}
+	return lifted, d.setLocation(d.Location)
+}
+
+// LowerCallbacks reverses LiftCallbacks, moving each webhook recorded in the
+// ExtensionCallbacks extension of the Document back into the Callbacks of
+// the Operation it originated from. Callbacks which were references to
+// components of the Document are restored as references. The extension is
+// removed.
+//
+// The lowered callbacks are returned in the order of the Document's
+// Webhooks.
+func (d *Document) LowerCallbacks() ([]LiftedCallback, error) {
+	if d == nil {
+		return nil, nil
+	}
+	origins := map[Text]CallbackOrigin{}
+	if err := d.decodeCallbackOrigins(origins); err != nil || len(origins) == 0 {
+		return nil, err
+	}
+	var lowered []LiftedCallback
+	for _, name := range d.Webhooks.Keys() {
+		origin, ok := origins[name]
+		if !ok {
+			continue
+		}
+		pi := d.Webhook(name)
+		if pi == nil {
+			return lowered, fmt.Errorf("%w: webhook %q", ErrUnresolvedReference, name)
+		}
+		op := d.callbackOperation(origin)
+		if op == nil {
+			return lowered, fmt.Errorf("%w: operation %s %q of callback %q", ErrNotFound, origin.Method, origin.Key, origin.Name)
+		}
+		if op.Callbacks == nil {
+			op.Callbacks = &CallbacksMap{}
+		}
+		if c := op.Callbacks.Get(origin.Name); c == nil {
+			op.Callbacks.Set(origin.Name, d.callbackComponent(origin))
+		}
+		c := op.Callbacks.Get(origin.Name)
+		if !c.IsReference() {
+			c.Object.PathItems.Set(origin.Expression, pi)
+		}
+		d.Webhooks.Del(name)
+		lowered = append(lowered, LiftedCallback{Webhook: name, Origin: origin})
+	}
+	if d.Webhooks.Len() == 0 {
+		d.Webhooks = nil
+	}
+	d.DeleteExtension(ExtensionCallbacks)
+	return lowered, d.setLocation(d.Location)
+}
+
+func (d *Document) decodeCallbackOrigins(dst map[Text]CallbackOrigin) error {
+	raw, ok := d.Extensions[ExtensionCallbacks]
+	if !ok {
+		return nil
+	}
+	if err := json.Unmarshal(raw, &dst); err != nil {
+		return fmt.Errorf("openapi: invalid %s extension: %w", ExtensionCallbacks, err)
+	}
+	return nil
+}
+
+// callbackOperation returns the Operation of origin, or nil if it does not
+// exist
+func (d *Document) callbackOperation(origin CallbackOrigin) *Operation {
+	var pi *PathItem
+	switch {
+	case origin.Webhook:
+		pi = d.Webhook(origin.Key)
+	case d.Paths != nil:
+		pi = d.Paths.Get(origin.Key)
+	}
+	for _, mo := range pi.Operations() {
+		if strings.EqualFold(mo.Method.String(), origin.Method.String()) {
+			return mo.Operation
+		}
+	}
+	return nil
+}
+
+// callbackComponent returns the Component of the callback of origin. If the
+// callback was a reference to a component of the Document which still
+// exists, a resolved Reference is returned.
+func (d *Document) callbackComponent(origin CallbackOrigin) *Component[*Callbacks] {
+	if origin.Ref != "" && strings.HasPrefix(origin.Ref, "#/components/callbacks/") && d.Components != nil {
+		tokens := jsonpointer.Pointer(strings.TrimPrefix(origin.Ref, "#")).Tokens()
+		if cc := d.Components.Callbacks.Get(Text(tokens[len(tokens)-1])); cc != nil && cc.Object != nil {
+			if ref, err := uri.Parse(origin.Ref); err == nil {
+				c := &Component[*Callbacks]{Object: cc.Object}
+				c.Reference = &Reference[*Callbacks]{
+					Ref:            ref,
+					ReferencedKind: KindCallbacks,
+					Resolved:       cc.Object,
+					dst:            &c.Object,
+					resolved:       true,
+				}
+				return c
+			}
+		}
+	}
+	return &Component[*Callbacks]{Object: &Callbacks{}}
+}
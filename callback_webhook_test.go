@@ -0,0 +1,107 @@
+package openapi_test
+
+import (
+	"testing"
+
+	"github.com/chanced/openapi"
+)
+
+func TestLiftCallbacks(t *testing.T) {
+	data := []byte(`{
+		"openapi": "3.1.0",
+		"info": { "title": "callbacks", "version": "1.0.0" },
+		"paths": {
+			"/subscriptions": {
+				"post": {
+					"operationId": "subscribe",
+					"callbacks": {
+						"onEvent": {
+							"{$request.body#/callbackUrl}": {
+								"post": { "responses": { "204": { "description": "ok" } } }
+							}
+						},
+						"shared": { "$ref": "#/components/callbacks/Shared" }
+					},
+					"responses": { "201": { "description": "created" } }
+				}
+			},
+			"/pets": {
+				"post": {
+					"callbacks": {
+						"shared": { "$ref": "#/components/callbacks/Shared" }
+					},
+					"responses": { "201": { "description": "created" } }
+				}
+			}
+		},
+		"components": {
+			"callbacks": {
+				"Shared": {
+					"{$request.query.url}": {
+						"put": { "responses": { "200": { "description": "ok" } } }
+					}
+				}
+			}
+		}
+	}`)
+	doc := loadDocument(t, "https://example.com/callbacks.json", data)
+	if n := len(doc.CallbackItems()); n != 3 {
+		t.Fatalf("expected 3 callbacks, got %d", n)
+	}
+	lifted, err := doc.LiftCallbacks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []openapi.Text{"subscribe.onEvent", "subscribe.shared", "postPets.shared"}
+	if len(lifted) != len(expected) {
+		t.Fatalf("expected %d lifted callbacks, got %d", len(expected), len(lifted))
+	}
+	for i, name := range expected {
+		if lifted[i].Webhook != name {
+			t.Errorf("expected webhook %d to be %q, got %q", i, name, lifted[i].Webhook)
+		}
+		if doc.Webhook(name) == nil {
+			t.Errorf("expected webhook %q", name)
+		}
+	}
+	if lifted[1].Origin.Ref != "#/components/callbacks/Shared" || lifted[1].Origin.Expression != "{$request.query.url}" {
+		t.Errorf("unexpected origin: %+v", lifted[1].Origin)
+	}
+	if doc.Paths.Get("/subscriptions").Post.Callbacks != nil {
+		t.Error("expected callbacks to be removed from the Operation")
+	}
+	if len(doc.CallbackItems()) != 0 {
+		t.Error("expected no callbacks after lifting")
+	}
+	if loc := doc.Webhook("subscribe.onEvent").Post.AbsoluteLocation(); loc.Fragment != "/webhooks/subscribe.onEvent/post" {
+		t.Errorf("expected location to be updated, got %q", loc.Fragment)
+	}
+
+	lowered, err := doc.LowerCallbacks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lowered) != 3 {
+		t.Fatalf("expected 3 lowered callbacks, got %d", len(lowered))
+	}
+	if doc.Webhooks != nil {
+		t.Errorf("expected webhooks to be removed, got %v", doc.Webhooks.Keys())
+	}
+	if _, ok := doc.Extensions[openapi.ExtensionCallbacks]; ok {
+		t.Error("expected the extension to be removed")
+	}
+	op := doc.Paths.Get("/subscriptions").Post
+	if keys := op.Callbacks.Keys(); len(keys) != 2 || keys[0] != "onEvent" || keys[1] != "shared" {
+		t.Fatalf("unexpected callbacks: %v", keys)
+	}
+	shared := op.Callbacks.Get("shared")
+	if !shared.IsReference() || shared.Reference.Ref.String() != "#/components/callbacks/Shared" || shared.Object == nil {
+		t.Error("expected shared to be restored as a resolved reference")
+	}
+	if pi := op.Callbacks.Get("onEvent").Object.PathItems.Get("{$request.body#/callbackUrl}"); pi == nil || pi.Post == nil {
+		t.Error("expected onEvent to be restored")
+	}
+	if n := len(doc.CallbackItems()); n != 3 {
+		t.Errorf("expected 3 callbacks after lowering, got %d", n)
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"strings"
 
-	"github.com/chanced/transcode"
 	"github.com/tidwall/gjson"
 	"gopkg.in/yaml.v3"
 )
@@ -109,11 +108,11 @@ func (c *Callbacks) UnmarshalJSON(data []byte) error {
 	var err error
 	gjson.ParseBytes(data).ForEach(func(key, value gjson.Result) bool {
 		if strings.HasPrefix(key.String(), "x-") {
-			c.SetRawExtension(Text(key.String()), []byte(value.Raw))
+			c.SetRawExtension(intern(key.String()), []byte(value.Raw))
 		} else {
 			var v PathItem
 			err = json.Unmarshal([]byte(value.Raw), &v)
-			c.Set(Text(key.String()), &v)
+			c.Set(intern(key.String()), &v)
 		}
 		return err == nil
 	})
@@ -143,11 +142,7 @@ func (c Callbacks) MarshalYAML() (interface{}, error) {
 
 // UnmarshalYAML implements yaml.Unmarshaler
 func (c *Callbacks) UnmarshalYAML(value *yaml.Node) error {
-	v, err := yaml.Marshal(value)
-	if err != nil {
-		return err
-	}
-	j, err := transcode.JSONFromYAML(v)
+	j, err := jsonFromYAMLNode(value)
 	if err != nil {
 		return err
 	}
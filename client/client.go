@@ -0,0 +1,394 @@
+// Package client builds HTTP requests for the Operations of OpenAPI
+// Documents.
+//
+// A Client indexes the Operations of a Document by operationId. NewRequest
+// selects a server, expands the Operation's path template, serializes
+// Parameters according to their style, and encodes the request body
+// according to its content type. This allows thin, typed clients to be
+// generated or Operations to be invoked dynamically.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/chanced/openapi"
+)
+
+var (
+	// ErrOperationNotFound is returned when a Document does not have an
+	// Operation with a given operationId.
+	ErrOperationNotFound = errors.New("client: operation not found")
+
+	// ErrMissingParameter is returned when a value is not provided for a
+	// required Parameter.
+	ErrMissingParameter = errors.New("client: missing required parameter")
+
+	// ErrUnknownParameter is returned when a value is provided for a
+	// Parameter which the Operation does not have.
+	ErrUnknownParameter = errors.New("client: unknown parameter")
+
+	// ErrMissingBody is returned when a request body is required but is not
+	// provided.
+	ErrMissingBody = errors.New("client: missing required request body")
+
+	// ErrUnsupportedContentType is returned when a body can not be encoded or
+	// decoded as its content type or the Operation does not accept the
+	// content type.
+	ErrUnsupportedContentType = errors.New("client: unsupported content type")
+)
+
+// Options configures a Client.
+type Options struct {
+	// Server, if set, is used in place of the servers of the Document. It may
+	// contain server variables (e.g. "https://{region}.example.com").
+	Server string
+
+	// ServerVariables are the values of server variables, keyed by name.
+	// Variables which are not set use their default value.
+	ServerVariables map[string]string
+
+	// Base, if set, is used to resolve relative server URLs (e.g. "/v1").
+	// It is typically the URL from which the Document was retrieved.
+	Base *url.URL
+
+	// ValidateResponses, if true, causes DecodeResponse to validate response
+	// bodies against the Schema of their media type.
+	ValidateResponses bool
+
+	// CompileOpts are used to compile the Schemas of responses when
+	// ValidateResponses is true.
+	CompileOpts openapi.CompileOpts
+}
+
+// Client builds HTTP requests for the Operations of a Document.
+type Client struct {
+	doc  *openapi.Document
+	opts Options
+	ops  map[openapi.Text]openapi.OperationEntry
+
+	mu       sync.Mutex
+	compiled map[*openapi.Schema]openapi.CompiledSchema
+}
+
+// New returns a Client for the Operations of doc's Paths. Webhooks are not
+// included as they are initiated by the API rather than its clients.
+//
+// An error is returned if more than one Operation shares an operationId.
+func New(doc *openapi.Document, opts Options) (*Client, error) {
+	if doc == nil {
+		return nil, fmt.Errorf("client: cannot create a Client for a nil Document")
+	}
+	c := &Client{
+		doc:      doc,
+		opts:     opts,
+		ops:      map[openapi.Text]openapi.OperationEntry{},
+		compiled: map[*openapi.Schema]openapi.CompiledSchema{},
+	}
+	for _, e := range doc.Operations() {
+		id := e.Operation.OperationID
+		if e.Webhook || id == "" {
+			continue
+		}
+		if prev, ok := c.ops[id]; ok {
+			return nil, fmt.Errorf("client: operationId %q of %s %s is also used by %s %s", id, e.Method, e.Key, prev.Method, prev.Key)
+		}
+		c.ops[id] = e
+	}
+	return c, nil
+}
+
+// Document returns the Document of the Client.
+func (c *Client) Document() *openapi.Document { return c.doc }
+
+// Operation returns the Operation with the operationId id.
+func (c *Client) Operation(id openapi.Text) (openapi.OperationEntry, bool) {
+	e, ok := c.ops[id]
+	return e, ok
+}
+
+// Values are the arguments of an Operation.
+type Values struct {
+	// Params are the values of the Operation's Parameters, keyed by name.
+	// Parameters which share a name but differ in location can be
+	// distinguished by prefixing the name with the location and a period
+	// (e.g. "header.id").
+	//
+	// Values may be primitives, slices, maps with string keys, or any other
+	// value which can be encoded as JSON.
+	Params map[string]interface{}
+
+	// Body is the request body. []byte, string, and io.Reader values are
+	// sent as is; other values are encoded according to ContentType.
+	Body interface{}
+
+	// ContentType is the content type of Body. It defaults to the JSON media
+	// type of the request body or, if there is not one, the first.
+	ContentType string
+}
+
+// NewRequest returns an *http.Request for the Operation with the operationId
+// id.
+func (c *Client) NewRequest(ctx context.Context, id openapi.Text, v Values) (*http.Request, error) {
+	e, ok := c.ops[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrOperationNotFound, id)
+	}
+	req, err := c.newRequest(ctx, e, v)
+	if err != nil {
+		return nil, fmt.Errorf("client: failed to build request for %q: %w", id, err)
+	}
+	return req, nil
+}
+
+func (c *Client) newRequest(ctx context.Context, e openapi.OperationEntry, v Values) (*http.Request, error) {
+	params, err := e.Operation.EffectiveParameters(e.PathItem)
+	if err != nil {
+		return nil, err
+	}
+	used := map[string]bool{}
+	path := e.Key.String()
+	var query, cookies []string
+	header := http.Header{}
+	for _, p := range params {
+		val, key, ok := paramValue(v.Params, p)
+		if !ok {
+			if p.In == openapi.InPath || (p.Required != nil && *p.Required) {
+				return nil, fmt.Errorf("%w: %s %q", ErrMissingParameter, p.In, p.Name)
+			}
+			continue
+		}
+		used[key] = true
+		s, err := p.Serialize(val)
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize %s parameter %q: %w", p.In, p.Name, err)
+		}
+		switch p.In {
+		case openapi.InPath:
+			path = strings.ReplaceAll(path, "{"+p.Name.String()+"}", s)
+		case openapi.InQuery:
+			query = append(query, s)
+		case openapi.InHeader:
+			header.Set(p.Name.String(), s)
+		case openapi.InCookie:
+			cookies = append(cookies, s)
+		}
+	}
+	if m := templateVariable.FindStringSubmatch(path); m != nil {
+		return nil, fmt.Errorf("%w: path %q", ErrMissingParameter, m[1])
+	}
+	var unknown []string
+	for k := range v.Params {
+		if !used[k] {
+			unknown = append(unknown, k)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("%w: %s", ErrUnknownParameter, strings.Join(unknown, ", "))
+	}
+
+	server, err := c.doc.ServerURL(e, openapi.ServerURLOpts{
+		Server:    c.opts.Server,
+		Variables: c.opts.ServerVariables,
+		Base:      c.opts.Base,
+	})
+	if err != nil {
+		return nil, err
+	}
+	u := server + path
+	if len(query) > 0 {
+		u += "?" + strings.Join(query, "&")
+	}
+
+	body, contentType, err := c.body(e.Operation, v)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, e.Method.String(), u, body)
+	if err != nil {
+		return nil, err
+	}
+	for k, vs := range header {
+		req.Header[k] = vs
+	}
+	if len(cookies) > 0 {
+		req.Header.Set("Cookie", strings.Join(cookies, "; "))
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	return req, nil
+}
+
+// paramValue returns the value of p from params, along with its key
+func paramValue(params map[string]interface{}, p *openapi.Parameter) (interface{}, string, bool) {
+	for _, key := range []string{p.In.String() + "." + p.Name.String(), p.Name.String()} {
+		if v, ok := params[key]; ok {
+			return v, key, true
+		}
+	}
+	return nil, "", false
+}
+
+var templateVariable = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// body returns the encoded request body of v and its content type
+func (c *Client) body(op *openapi.Operation, v Values) (io.Reader, string, error) {
+	var rb *openapi.RequestBody
+	if op.RequestBody != nil {
+		rb = op.RequestBody.Object
+		if rb == nil && op.RequestBody.Reference != nil {
+			return nil, "", fmt.Errorf("%w: %s", openapi.ErrUnresolvedReference, op.RequestBody.Reference.Ref)
+		}
+	}
+	if v.Body == nil {
+		if rb != nil && rb.Required {
+			return nil, "", ErrMissingBody
+		}
+		return nil, "", nil
+	}
+	contentType := v.ContentType
+	if rb != nil && rb.Content != nil && len(rb.Content.Items) > 0 {
+		if contentType == "" {
+			contentType = defaultContentType(rb.Content)
+		} else if _, _, ok := openapi.MatchContent(rb.Content, contentType); !ok {
+			return nil, "", fmt.Errorf("%w: %q is not accepted by the operation", ErrUnsupportedContentType, contentType)
+		}
+	}
+	return encodeBody(contentType, v.Body)
+}
+
+// defaultContentType returns the JSON media type of content or, if there is
+// not one, the first
+func defaultContentType(content *openapi.ContentMap) string {
+	for _, item := range content.Items {
+		if isJSON(item.Key.String()) {
+			return item.Key.String()
+		}
+	}
+	return content.Items[0].Key.String()
+}
+
+func isJSON(contentType string) bool {
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mt == "application/json" || strings.HasSuffix(mt, "+json")
+}
+
+// encodeBody encodes body as contentType, returning the encoded body and the
+// value of the Content-Type header
+func encodeBody(contentType string, body interface{}) (io.Reader, string, error) {
+	mt, _, _ := mime.ParseMediaType(contentType)
+	if strings.Contains(mt, "*") {
+		// a media type range (e.g. "image/*") is not a valid Content-Type
+		contentType, mt = "application/octet-stream", "application/octet-stream"
+	}
+	switch b := body.(type) {
+	case io.Reader:
+		return b, contentType, nil
+	case []byte:
+		return bytes.NewReader(b), contentType, nil
+	case string:
+		return strings.NewReader(b), contentType, nil
+	}
+	switch {
+	case mt == "" || isJSON(mt):
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, "", err
+		}
+		if contentType == "" {
+			contentType = "application/json"
+		}
+		return bytes.NewReader(data), contentType, nil
+	case mt == "application/x-www-form-urlencoded":
+		fields, err := toFields(body)
+		if err != nil {
+			return nil, "", err
+		}
+		form := url.Values{}
+		for _, f := range fields {
+			form[f.key] = append(form[f.key], f.values...)
+		}
+		return strings.NewReader(form.Encode()), contentType, nil
+	case mt == "multipart/form-data":
+		return encodeMultipart(body)
+	case strings.HasPrefix(mt, "text/"):
+		s, err := scalar(body)
+		if err != nil {
+			return nil, "", err
+		}
+		return strings.NewReader(s), contentType, nil
+	default:
+		return nil, "", fmt.Errorf("%w: cannot encode %T as %q", ErrUnsupportedContentType, body, contentType)
+	}
+}
+
+func encodeMultipart(body interface{}) (io.Reader, string, error) {
+	b := &bytes.Buffer{}
+	w := multipart.NewWriter(b)
+	var fields []field
+	if m, ok := body.(map[string]interface{}); ok {
+		// files are only supported by maps as they can not be encoded as JSON
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			var r io.Reader
+			switch v := m[k].(type) {
+			case io.Reader:
+				r = v
+			case []byte:
+				r = bytes.NewReader(v)
+			}
+			if r == nil {
+				f, err := toField(k, m[k])
+				if err != nil {
+					return nil, "", err
+				}
+				fields = append(fields, f)
+				continue
+			}
+			fw, err := w.CreateFormFile(k, k)
+			if err != nil {
+				return nil, "", err
+			}
+			if _, err = io.Copy(fw, r); err != nil {
+				return nil, "", err
+			}
+		}
+	} else {
+		var err error
+		if fields, err = toFields(body); err != nil {
+			return nil, "", err
+		}
+	}
+	for _, f := range fields {
+		for _, v := range f.values {
+			if err := w.WriteField(f.key, v); err != nil {
+				return nil, "", err
+			}
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, "", err
+	}
+	return b, w.FormDataContentType(), nil
+}
@@ -0,0 +1,299 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/chanced/openapi"
+	"github.com/chanced/openapi/client"
+	"github.com/chanced/uri"
+)
+
+func loadClient(t *testing.T, opts client.Options) *client.Client {
+	t.Helper()
+	data := []byte(`{
+		"openapi": "3.1.0",
+		"info": { "title": "Pet Store", "version": "1.0.0" },
+		"servers": [
+			{
+				"url": "https://{env}.example.com/v1/",
+				"variables": { "env": { "default": "api", "enum": ["api", "staging"] } }
+			}
+		],
+		"paths": {
+			"/pets/{petId}": {
+				"parameters": [
+					{ "name": "petId", "in": "path", "required": true, "schema": { "type": "integer" } }
+				],
+				"get": {
+					"operationId": "getPet",
+					"parameters": [
+						{ "name": "fields", "in": "query", "schema": { "type": "array", "items": { "type": "string" } } },
+						{ "name": "tags", "in": "query", "style": "pipeDelimited", "schema": { "type": "array", "items": { "type": "string" } } },
+						{ "name": "filter", "in": "query", "style": "deepObject", "explode": true, "schema": { "type": "object" } },
+						{ "name": "q", "in": "query", "content": { "application/json": { "schema": { "type": "object" } } } },
+						{ "name": "X-Request-ID", "in": "header", "schema": { "type": "string" } },
+						{ "name": "session", "in": "cookie", "schema": { "type": "string" } },
+						{ "name": "ids", "in": "query", "style": "form", "schema": { "type": "array", "items": { "type": "integer" } } },
+						{ "name": "sort", "in": "query", "style": "form", "explode": false, "schema": { "type": "array", "items": { "type": "string" } } }
+					],
+					"responses": { "200": { "description": "ok" } }
+				}
+			},
+			"/pets/{color}/{point}": {
+				"get": {
+					"operationId": "styled",
+					"servers": [{ "url": "/api" }],
+					"parameters": [
+						{ "name": "color", "in": "path", "required": true, "style": "label", "schema": { "type": "array" } },
+						{ "name": "point", "in": "path", "required": true, "style": "matrix", "explode": true, "schema": { "type": "object" } }
+					],
+					"responses": { "200": { "description": "ok" } }
+				}
+			},
+			"/pets": {
+				"post": {
+					"operationId": "createPet",
+					"requestBody": {
+						"required": true,
+						"content": {
+							"application/json": { "schema": { "type": "object" } },
+							"application/x-www-form-urlencoded": { "schema": { "type": "object" } },
+							"multipart/form-data": { "schema": { "type": "object" } }
+						}
+					},
+					"responses": { "201": { "description": "created" } }
+				}
+			}
+		}
+	}`)
+	c, err := client.New(load(t, data), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func load(t *testing.T, data []byte) *openapi.Document {
+	t.Helper()
+	fn := func(ctx context.Context, u uri.URI, kind openapi.Kind) (openapi.Kind, []byte, error) {
+		return openapi.KindDocument, data, nil
+	}
+	v, err := openapi.NewOfflineValidator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := openapi.Load(context.Background(), "https://example.com/openapi.json", v, fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+func TestNewRequestParameters(t *testing.T) {
+	c := loadClient(t, client.Options{ServerVariables: map[string]string{"env": "staging"}})
+	req, err := c.NewRequest(context.Background(), "getPet", client.Values{
+		Params: map[string]interface{}{
+			"petId":        7,
+			"fields":       []string{"name", "born on"},
+			"tags":         []string{"a", "b"},
+			"filter":       map[string]interface{}{"status": "sold", "age": 3},
+			"q":            map[string]string{"name": "fido"},
+			"X-Request-ID": "abc",
+			"session":      "s1",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Method != "GET" {
+		t.Errorf("expected GET, got %s", req.Method)
+	}
+	if req.URL.Scheme != "https" || req.URL.Host != "staging.example.com" || req.URL.Path != "/v1/pets/7" {
+		t.Errorf("unexpected url: %s", req.URL)
+	}
+	expected := "fields=name&fields=born+on&tags=a|b&filter[age]=3&filter[status]=sold&q=" + url.QueryEscape(`{"name":"fido"}`)
+	if req.URL.RawQuery != expected {
+		t.Errorf("expected query\n\t%s\ngot\n\t%s", expected, req.URL.RawQuery)
+	}
+	if req.Header.Get("X-Request-ID") != "abc" {
+		t.Errorf("unexpected header: %v", req.Header)
+	}
+	if req.Header.Get("Cookie") != "session=s1" {
+		t.Errorf("unexpected cookie: %q", req.Header.Get("Cookie"))
+	}
+}
+
+func TestNewRequestFormExplode(t *testing.T) {
+	c := loadClient(t, client.Options{})
+	req, err := c.NewRequest(context.Background(), "getPet", client.Values{
+		Params: map[string]interface{}{
+			"petId": 7,
+			"ids":   []int{1, 2},
+			"sort":  []string{"name", "age"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// explode defaults to true for an explicit form style
+	expected := "ids=1&ids=2&sort=name,age"
+	if req.URL.RawQuery != expected {
+		t.Errorf("expected query\n\t%s\ngot\n\t%s", expected, req.URL.RawQuery)
+	}
+}
+
+func TestNewRequestMatchesFixtures(t *testing.T) {
+	doc := load(t, []byte(`{
+		"openapi": "3.1.0",
+		"info": { "title": "Pet Store", "version": "1.0.0" },
+		"paths": {
+			"/pets/{color}": {
+				"get": {
+					"operationId": "listPets",
+					"parameters": [
+						{ "name": "color", "in": "path", "required": true, "style": "label", "schema": { "type": "array" }, "example": ["blue", "black"] },
+						{ "name": "ids", "in": "query", "style": "form", "schema": { "type": "array" }, "example": [1, 2] },
+						{ "name": "sort", "in": "query", "explode": false, "schema": { "type": "array" }, "example": ["name", "age"] },
+						{ "name": "filter", "in": "query", "style": "deepObject", "explode": true, "schema": { "type": "object" }, "example": { "status": "sold out" } },
+						{ "name": "X-Tags", "in": "header", "schema": { "type": "array" }, "example": ["a", "b"] },
+						{ "name": "session", "in": "cookie", "schema": { "type": "string" }, "example": "s1" }
+					],
+					"responses": { "204": { "description": "ok" } }
+				}
+			}
+		}
+	}`))
+	fixtures, err := doc.Fixtures(openapi.FixtureOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fixtures) != 1 {
+		t.Fatalf("expected 1 fixture, got %d", len(fixtures))
+	}
+	f := fixtures[0]
+	c, err := client.New(doc, client.Options{Server: "https://example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, err := c.NewRequest(context.Background(), "listPets", client.Values{
+		Params: map[string]interface{}{
+			"color":   []string{"blue", "black"},
+			"ids":     []int{1, 2},
+			"sort":    []string{"name", "age"},
+			"filter":  map[string]string{"status": "sold out"},
+			"X-Tags":  []string{"a", "b"},
+			"session": "s1",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.URL.RequestURI() != f.Request.Path {
+		t.Errorf("expected the client and fixture paths to match:\n\t%s\n\t%s", req.URL.RequestURI(), f.Request.Path)
+	}
+	for _, h := range []string{"X-Tags", "Cookie"} {
+		if req.Header.Get(h) != f.Request.Headers[h] {
+			t.Errorf("expected the client and fixture %s headers to match: %q, %q", h, req.Header.Get(h), f.Request.Headers[h])
+		}
+	}
+}
+
+func TestNewRequestPathStyles(t *testing.T) {
+	c := loadClient(t, client.Options{Base: &url.URL{Scheme: "http", Host: "localhost:8080"}})
+	req, err := c.NewRequest(context.Background(), "styled", client.Values{
+		Params: map[string]interface{}{
+			"color": []string{"blue", "black"},
+			"point": map[string]int{"x": 1, "y": 2},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := req.URL.String(); s != "http://localhost:8080/api/pets/.blue,black/;x=1;y=2" {
+		t.Errorf("unexpected url: %s", s)
+	}
+}
+
+func TestNewRequestBody(t *testing.T) {
+	c := loadClient(t, client.Options{Server: "https://pets.example.com"})
+
+	req, err := c.NewRequest(context.Background(), "createPet", client.Values{
+		Body: struct {
+			Name string `json:"name"`
+		}{"Fido"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("unexpected content type: %q", req.Header.Get("Content-Type"))
+	}
+	b, _ := io.ReadAll(req.Body)
+	var body map[string]interface{}
+	if err = json.Unmarshal(b, &body); err != nil || body["name"] != "Fido" {
+		t.Errorf("unexpected body: %s", b)
+	}
+
+	req, err = c.NewRequest(context.Background(), "createPet", client.Values{
+		ContentType: "application/x-www-form-urlencoded",
+		Body:        map[string]interface{}{"name": "Fido", "tags": []string{"a", "b"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ = io.ReadAll(req.Body)
+	if string(b) != "name=Fido&tags=a&tags=b" {
+		t.Errorf("unexpected form body: %s", b)
+	}
+
+	req, err = c.NewRequest(context.Background(), "createPet", client.Values{
+		ContentType: "multipart/form-data",
+		Body:        map[string]interface{}{"name": "Fido", "photo": []byte("png")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = req.ParseMultipartForm(1 << 20); err != nil {
+		t.Fatal(err)
+	}
+	if req.FormValue("name") != "Fido" || len(req.MultipartForm.File["photo"]) != 1 {
+		t.Errorf("unexpected multipart form: %+v", req.MultipartForm)
+	}
+}
+
+func TestNewRequestErrors(t *testing.T) {
+	c := loadClient(t, client.Options{})
+	ctx := context.Background()
+
+	if _, err := c.NewRequest(ctx, "missing", client.Values{}); !errors.Is(err, client.ErrOperationNotFound) {
+		t.Errorf("expected ErrOperationNotFound, got %v", err)
+	}
+	if _, err := c.NewRequest(ctx, "getPet", client.Values{}); !errors.Is(err, client.ErrMissingParameter) {
+		t.Errorf("expected ErrMissingParameter, got %v", err)
+	}
+	_, err := c.NewRequest(ctx, "getPet", client.Values{Params: map[string]interface{}{"petId": 1, "nope": 2}})
+	if !errors.Is(err, client.ErrUnknownParameter) || !strings.Contains(err.Error(), "nope") {
+		t.Errorf("expected ErrUnknownParameter, got %v", err)
+	}
+	if _, err = c.NewRequest(ctx, "createPet", client.Values{}); !errors.Is(err, client.ErrMissingBody) {
+		t.Errorf("expected ErrMissingBody, got %v", err)
+	}
+	_, err = c.NewRequest(ctx, "createPet", client.Values{ContentType: "application/xml", Body: "<pet/>"})
+	if !errors.Is(err, client.ErrUnsupportedContentType) {
+		t.Errorf("expected ErrUnsupportedContentType, got %v", err)
+	}
+
+	c, err = client.New(c.Document(), client.Options{ServerVariables: map[string]string{"env": "prod"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = c.NewRequest(ctx, "getPet", client.Values{Params: map[string]interface{}{"petId": 1}}); err == nil {
+		t.Error("expected an error for a server variable value not in its enum")
+	}
+}
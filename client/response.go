@@ -0,0 +1,213 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/chanced/openapi"
+)
+
+var (
+	// ErrUndocumentedStatus is matched by an UndocumentedStatusError.
+	ErrUndocumentedStatus = errors.New("client: undocumented response status")
+
+	// ErrUndocumentedContentType is returned when the content type of a
+	// response is not one of the media types of its Response.
+	ErrUndocumentedContentType = errors.New("client: undocumented response content type")
+
+	// ErrInvalidResponse is returned when the body of a response is not valid
+	// according to the Schema of its media type.
+	ErrInvalidResponse = errors.New("client: invalid response")
+)
+
+// UndocumentedStatusError is returned by DecodeResponse when the status code
+// of a response does not match a Response of the Operation and the Operation
+// does not have a default Response.
+type UndocumentedStatusError struct {
+	OperationID openapi.Text
+	StatusCode  int
+	// Header is the header of the response.
+	Header http.Header
+	// Body is the body of the response.
+	Body []byte
+}
+
+func (e *UndocumentedStatusError) Error() string {
+	return fmt.Sprintf("client: status %d is not a documented response of %q", e.StatusCode, e.OperationID)
+}
+
+// Is reports whether target is ErrUndocumentedStatus.
+func (e *UndocumentedStatusError) Is(target error) bool {
+	return target == ErrUndocumentedStatus
+}
+
+// Response is the Response and MediaType of an Operation which an HTTP
+// response was matched to.
+type Response struct {
+	// StatusCode is the status code of the HTTP response.
+	StatusCode int
+	// Key is the key of the matched Response (e.g. "200", "2XX", or
+	// "default").
+	Key openapi.Text
+	// Response is the matched Response.
+	Response *openapi.Response
+	// ContentType is the key of the matched MediaType (e.g.
+	// "application/json"). It is empty if the Response does not have content.
+	ContentType openapi.Text
+	// MediaType is the matched MediaType, if any.
+	MediaType *openapi.MediaType
+}
+
+// DecodeResponse matches res to a Response of the Operation with the
+// operationId id and decodes its body into dst.
+//
+// The Response is selected by status code, falling back to the range of the
+// status code (e.g. "4XX") and then the default Response. The MediaType is
+// selected by the Content-Type of res (see openapi.MatchContent). If the
+// status code is not documented, an *UndocumentedStatusError is returned.
+//
+// JSON bodies are decoded with json.Unmarshal. Other bodies may be decoded
+// into a *string, *[]byte, or io.Writer. If dst is nil, the body is
+// discarded. If the Client's ValidateResponses option is set, the body is
+// validated against the Schema of the MediaType before it is decoded.
+//
+// The body of res is read and closed.
+func (c *Client) DecodeResponse(ctx context.Context, id openapi.Text, res *http.Response, dst interface{}) (*Response, error) {
+	e, ok := c.ops[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrOperationNotFound, id)
+	}
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("client: failed to read response body of %q: %w", id, err)
+	}
+
+	key, r, err := matchResponse(e.Operation.Responses, res.StatusCode)
+	if err != nil {
+		return nil, fmt.Errorf("client: failed to match response of %q: %w", id, err)
+	}
+	if r == nil {
+		return nil, &UndocumentedStatusError{
+			OperationID: id,
+			StatusCode:  res.StatusCode,
+			Header:      res.Header,
+			Body:        body,
+		}
+	}
+	result := &Response{StatusCode: res.StatusCode, Key: key, Response: r}
+	if r.Content == nil || len(r.Content.Items) == 0 {
+		return result, nil
+	}
+
+	contentType := res.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = defaultContentType(r.Content)
+	}
+	ct, mt, ok := openapi.MatchContent(r.Content, contentType)
+	if !ok {
+		return result, fmt.Errorf("%w: %q is not documented for status %d of %q", ErrUndocumentedContentType, contentType, res.StatusCode, id)
+	}
+	result.ContentType, result.MediaType = ct, mt
+
+	if c.opts.ValidateResponses && mt.Schema != nil {
+		if err = c.validate(ctx, mt.Schema, contentType, body); err != nil {
+			return result, fmt.Errorf("%w: status %d of %q: %v", ErrInvalidResponse, res.StatusCode, id, err)
+		}
+	}
+	if dst == nil {
+		return result, nil
+	}
+	if err = decodeBody(contentType, body, dst); err != nil {
+		return result, fmt.Errorf("client: failed to decode response of %q: %w", id, err)
+	}
+	return result, nil
+}
+
+// matchResponse returns the Response of responses for statusCode, falling
+// back to its range and then the default Response. If there is not a match,
+// a nil Response is returned.
+func matchResponse(responses *openapi.ResponseMap, statusCode int) (openapi.Text, *openapi.Response, error) {
+	item := openapi.MatchResponse(responses, statusCode)
+	if item == nil {
+		return "", nil, nil
+	}
+	r, err := resolvedResponse(item.Component)
+	return item.Key, r, err
+}
+
+func resolvedResponse(c *openapi.Component[*openapi.Response]) (*openapi.Response, error) {
+	if c == nil {
+		return nil, nil
+	}
+	if c.Object == nil && c.Reference != nil {
+		return nil, fmt.Errorf("%w: %s", openapi.ErrUnresolvedReference, c.Reference.Ref)
+	}
+	return c.Object, nil
+}
+
+// validate validates body against s
+func (c *Client) validate(ctx context.Context, s *openapi.Schema, contentType string, body []byte) error {
+	var instance interface{}
+	if isJSON(contentType) {
+		if len(bytes.TrimSpace(body)) == 0 {
+			return fmt.Errorf("empty body")
+		}
+		dec := json.NewDecoder(bytes.NewReader(body))
+		dec.UseNumber()
+		if err := dec.Decode(&instance); err != nil {
+			return err
+		}
+	} else if isText(contentType) {
+		instance = string(body)
+	} else {
+		// binary content can not be validated
+		return nil
+	}
+	c.mu.Lock()
+	compiled, ok := c.compiled[s]
+	c.mu.Unlock()
+	if !ok {
+		var err error
+		if compiled, err = s.Compile(ctx, c.opts.CompileOpts); err != nil {
+			return err
+		}
+		c.mu.Lock()
+		c.compiled[s] = compiled
+		c.mu.Unlock()
+	}
+	return compiled.Validate(instance)
+}
+
+// decodeBody decodes body, of contentType, into dst
+func decodeBody(contentType string, body []byte, dst interface{}) error {
+	switch d := dst.(type) {
+	case *[]byte:
+		*d = body
+		return nil
+	case io.Writer:
+		_, err := d.Write(body)
+		return err
+	case *string:
+		if !isJSON(contentType) {
+			*d = string(body)
+			return nil
+		}
+	}
+	if !isJSON(contentType) {
+		return fmt.Errorf("%w: cannot decode %q into %T", ErrUnsupportedContentType, contentType, dst)
+	}
+	return json.Unmarshal(body, dst)
+}
+
+func isText(contentType string) bool {
+	mt, _, err := mime.ParseMediaType(contentType)
+	return err == nil && strings.HasPrefix(mt, "text/")
+}
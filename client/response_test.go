@@ -0,0 +1,107 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/chanced/openapi/client"
+)
+
+func TestDecodeResponse(t *testing.T) {
+	doc := load(t, []byte(`{
+		"openapi": "3.1.0",
+		"info": { "title": "Pet Store", "version": "1.0.0" },
+		"paths": {
+			"/pets/{petId}": {
+				"get": {
+					"operationId": "getPet",
+					"parameters": [
+						{ "name": "petId", "in": "path", "required": true, "schema": { "type": "integer" } }
+					],
+					"responses": {
+						"200": {
+							"description": "ok",
+							"content": {
+								"application/json": {
+									"schema": {
+										"type": "object",
+										"required": ["name"],
+										"properties": { "name": { "type": "string" } }
+									}
+								},
+								"text/plain": { "schema": { "type": "string" } }
+							}
+						},
+						"4XX": {
+							"description": "client error",
+							"content": { "application/problem+json": { "schema": { "type": "object" } } }
+						},
+						"204": { "description": "no content" }
+					}
+				}
+			}
+		}
+	}`))
+	c, err := client.New(doc, client.Options{ValidateResponses: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	response := func(status int, contentType, body string) *http.Response {
+		h := http.Header{}
+		if contentType != "" {
+			h.Set("Content-Type", contentType)
+		}
+		return &http.Response{StatusCode: status, Header: h, Body: io.NopCloser(strings.NewReader(body))}
+	}
+
+	var pet struct{ Name string }
+	res, err := c.DecodeResponse(ctx, "getPet", response(200, "application/json; charset=utf-8", `{"name":"Fido"}`), &pet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pet.Name != "Fido" || res.Key != "200" || res.ContentType != "application/json" {
+		t.Errorf("unexpected result: %+v, %+v", pet, res)
+	}
+
+	var s string
+	if _, err = c.DecodeResponse(ctx, "getPet", response(200, "text/plain", "Fido"), &s); err != nil || s != "Fido" {
+		t.Errorf("expected text body to be decoded, got %q, %v", s, err)
+	}
+
+	var problem map[string]interface{}
+	res, err = c.DecodeResponse(ctx, "getPet", response(404, "application/problem+json", `{"title":"not found"}`), &problem)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Key != "4XX" || problem["title"] != "not found" {
+		t.Errorf("unexpected result: %v, %+v", problem, res)
+	}
+
+	if res, err = c.DecodeResponse(ctx, "getPet", response(204, "", ""), nil); err != nil || res.Response == nil {
+		t.Errorf("expected 204 to match, got %+v, %v", res, err)
+	}
+
+	_, err = c.DecodeResponse(ctx, "getPet", response(200, "application/json", `{"id":1}`), &pet)
+	if !errors.Is(err, client.ErrInvalidResponse) {
+		t.Errorf("expected ErrInvalidResponse, got %v", err)
+	}
+
+	_, err = c.DecodeResponse(ctx, "getPet", response(200, "application/xml", `<pet/>`), &pet)
+	if !errors.Is(err, client.ErrUndocumentedContentType) {
+		t.Errorf("expected ErrUndocumentedContentType, got %v", err)
+	}
+
+	_, err = c.DecodeResponse(ctx, "getPet", response(500, "text/plain", "boom"), nil)
+	var statusErr *client.UndocumentedStatusError
+	if !errors.As(err, &statusErr) || !errors.Is(err, client.ErrUndocumentedStatus) {
+		t.Fatalf("expected *UndocumentedStatusError, got %v", err)
+	}
+	if statusErr.StatusCode != 500 || string(statusErr.Body) != "boom" {
+		t.Errorf("unexpected error: %+v", statusErr)
+	}
+}
@@ -0,0 +1,118 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+func isPrimitive(rv reflect.Value) bool {
+	switch rv.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// scalar returns v as a string if it is a primitive or, otherwise, as JSON
+func scalar(v interface{}) (string, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return "", nil
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return "", nil
+	}
+	if isPrimitive(rv) {
+		return fmt.Sprint(rv.Interface()), nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// jsonValue returns v encoded and decoded as JSON
+func jsonValue(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var x interface{}
+	if err = dec.Decode(&x); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// field is a field of a form
+type field struct {
+	key    string
+	values []string
+}
+
+// toFields returns the properties of the object v as form fields, sorted by
+// key. Array properties have a value for each item.
+func toFields(v interface{}) ([]field, error) {
+	x, err := jsonValue(v)
+	if err != nil {
+		return nil, err
+	}
+	m, ok := x.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("cannot encode %T as form fields; it must be an object", v)
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	fields := make([]field, 0, len(keys))
+	for _, k := range keys {
+		f, err := toField(k, m[k])
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+// toField returns v as the form field key. Arrays have a value for each
+// item; objects are encoded as JSON.
+func toField(key string, v interface{}) (field, error) {
+	f := field{key: key}
+	rv := reflect.ValueOf(v)
+	if (rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array) && rv.Type().Elem().Kind() != reflect.Uint8 {
+		f.values = make([]string, rv.Len())
+		for i := range f.values {
+			s, err := scalar(rv.Index(i).Interface())
+			if err != nil {
+				return field{}, err
+			}
+			f.values[i] = s
+		}
+		return f, nil
+	}
+	if b, ok := v.([]byte); ok {
+		f.values = []string{string(b)}
+		return f, nil
+	}
+	s, err := scalar(v)
+	if err != nil {
+		return field{}, err
+	}
+	f.values = []string{s}
+	return f, nil
+}
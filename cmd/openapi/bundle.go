@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chanced/openapi"
+)
+
+func runBundle(ctx context.Context, e *env, args []string) int {
+	return runBundler(ctx, e, "bundle", args, func(doc *openapi.Document) error {
+		return doc.Bundle()
+	})
+}
+
+func runDeref(ctx context.Context, e *env, args []string) int {
+	return runBundler(ctx, e, "deref", args, func(doc *openapi.Document) error {
+		if err := doc.Dereference(openapi.DereferenceOpts{ApplyOverrides: true}); err != nil {
+			return err
+		}
+		// references to Schemas remain, as they may be recursive
+		return doc.Bundle()
+	})
+}
+
+// runBundler loads a document, transforms it with fn, and writes the result.
+func runBundler(ctx context.Context, e *env, name string, args []string, fn func(doc *openapi.Document) error) int {
+	fs, format := newFlagSet(e, name, "<document>")
+	if err := fs.Parse(args); err != nil || fs.NArg() != 1 || !checkFormat(e, *format) {
+		if err == nil {
+			fs.Usage()
+		}
+		return exitUsage
+	}
+	path := fs.Arg(0)
+	v, err := openapi.NewOfflineValidator()
+	if err != nil {
+		fmt.Fprintf(e.stderr, "openapi: %v\n", err)
+		return exitFailure
+	}
+	doc, err := load(ctx, e, path, v)
+	if err != nil {
+		reportError(e, path, err)
+		return exitFailure
+	}
+	if err = fn(doc); err != nil {
+		reportError(e, path, err)
+		return exitFailure
+	}
+	data, err := doc.MarshalJSON()
+	if err != nil {
+		fmt.Fprintf(e.stderr, "openapi: %v\n", err)
+		return exitFailure
+	}
+	if err = writeDocument(e, *format, data); err != nil {
+		fmt.Fprintf(e.stderr, "openapi: %v\n", err)
+		return exitFailure
+	}
+	return exitOK
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Masterminds/semver"
+	"github.com/chanced/openapi"
+	"github.com/chanced/uri"
+)
+
+func runConvert(ctx context.Context, e *env, args []string) int {
+	fs, format := newFlagSet(e, "convert", "<document>")
+	to := fs.String("to", "3.1.0", "OpenAPI version to convert to: 3.0.x or 3.1.x")
+	if err := fs.Parse(args); err != nil || fs.NArg() != 1 || !checkFormat(e, *format) {
+		if err == nil {
+			fs.Usage()
+		}
+		return exitUsage
+	}
+	version, err := semver.NewVersion(*to)
+	if err != nil {
+		fmt.Fprintf(e.stderr, "openapi: invalid version %q: %v\n", *to, err)
+		return exitUsage
+	}
+	path := fs.Arg(0)
+	s, err := documentURI(path)
+	if err != nil {
+		fmt.Fprintf(e.stderr, "openapi: %v\n", err)
+		return exitFailure
+	}
+	u, err := uri.Parse(s)
+	if err != nil {
+		fmt.Fprintf(e.stderr, "openapi: %v\n", err)
+		return exitFailure
+	}
+	// the document is converted as is, as documents of versions other than
+	// 3.1 can not be loaded
+	_, data, err := e.fetch(ctx, *u, openapi.KindDocument)
+	if err != nil {
+		reportError(e, path, err)
+		return exitFailure
+	}
+	if data, err = openapi.ConvertVersion(data, *version); err != nil {
+		reportError(e, path, err)
+		return exitFailure
+	}
+	if err = writeDocument(e, *format, data); err != nil {
+		fmt.Fprintf(e.stderr, "openapi: %v\n", err)
+		return exitFailure
+	}
+	return exitOK
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/chanced/openapi"
+)
+
+func runDiff(ctx context.Context, e *env, args []string) int {
+	fs, format := newFlagSet(e, "diff", "<from> <to>")
+	if err := fs.Parse(args); err != nil || fs.NArg() != 2 || !checkFormat(e, *format) {
+		if err == nil {
+			fs.Usage()
+		}
+		return exitUsage
+	}
+	v, err := openapi.NewOfflineValidator()
+	if err != nil {
+		fmt.Fprintf(e.stderr, "openapi: %v\n", err)
+		return exitFailure
+	}
+	docs := make([]*openapi.Document, 2)
+	for i, path := range fs.Args() {
+		if docs[i], err = load(ctx, e, path, v); err != nil {
+			reportError(e, path, err)
+			return exitFailure
+		}
+	}
+	patch, err := openapi.Diff(docs[0], docs[1])
+	if err != nil {
+		fmt.Fprintf(e.stderr, "openapi: %v\n", err)
+		return exitFailure
+	}
+	var ops []json.RawMessage
+	if err = json.Unmarshal(patch, &ops); err != nil {
+		fmt.Fprintf(e.stderr, "openapi: %v\n", err)
+		return exitFailure
+	}
+	err = write(e, *format, ops, func(w io.Writer) {
+		// each operation of the patch on its own line
+		for _, op := range ops {
+			fmt.Fprintf(w, "%s\n", op)
+		}
+	})
+	if err != nil {
+		fmt.Fprintf(e.stderr, "openapi: %v\n", err)
+		return exitFailure
+	}
+	return exitOK
+}
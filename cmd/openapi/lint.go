@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/chanced/openapi"
+	"github.com/chanced/openapi/lint"
+)
+
+type lintIssue struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Location string `json:"location"`
+	Message  string `json:"message"`
+}
+
+// severities is a flag.Value of rule=severity pairs
+type severities map[string]lint.Severity
+
+func (s severities) String() string {
+	var pairs []string
+	for rule, sev := range s {
+		pairs = append(pairs, rule+"="+sev.String())
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (s severities) Set(value string) error {
+	rule, sev, ok := strings.Cut(value, "=")
+	if !ok {
+		return fmt.Errorf("expected rule=severity, got %q", value)
+	}
+	v, err := lint.ParseSeverity(sev)
+	if err != nil {
+		return err
+	}
+	s[rule] = v
+	return nil
+}
+
+func runLint(ctx context.Context, e *env, args []string) int {
+	fs, format := newFlagSet(e, "lint", "<document>")
+	sevs := severities{}
+	fs.Var(sevs, "rule", "override the severity of a rule as rule=severity (e.g. operation-description=off); may be repeated")
+	failOn := fs.String("fail-on", "error", "minimum severity which results in a non-zero exit status")
+	if err := fs.Parse(args); err != nil || fs.NArg() != 1 || !checkFormat(e, *format) {
+		if err == nil {
+			fs.Usage()
+		}
+		return exitUsage
+	}
+	threshold, err := lint.ParseSeverity(*failOn)
+	if err != nil {
+		fmt.Fprintf(e.stderr, "openapi: %v\n", err)
+		return exitUsage
+	}
+	linter, err := lint.NewLinter(lint.Config{Severities: sevs})
+	if err != nil {
+		fmt.Fprintf(e.stderr, "openapi: %v\n", err)
+		return exitUsage
+	}
+	path := fs.Arg(0)
+	v, err := openapi.NewOfflineValidator()
+	if err != nil {
+		fmt.Fprintf(e.stderr, "openapi: %v\n", err)
+		return exitFailure
+	}
+	doc, err := load(ctx, e, path, v)
+	if err != nil {
+		reportError(e, path, err)
+		return exitFailure
+	}
+	issues := linter.Lint(doc)
+	out := make([]lintIssue, len(issues))
+	for i, is := range issues {
+		out[i] = lintIssue{
+			Rule:     is.Rule,
+			Severity: is.Severity.String(),
+			Location: is.Location.String(),
+			Message:  is.Message,
+		}
+	}
+	err = write(e, *format, out, func(w io.Writer) {
+		for _, is := range issues {
+			fmt.Fprintln(w, is.String())
+		}
+	})
+	if err != nil {
+		fmt.Fprintf(e.stderr, "openapi: %v\n", err)
+		return exitFailure
+	}
+	if threshold != lint.SeverityOff && len(issues.AtLeast(threshold)) > 0 {
+		return exitFailure
+	}
+	return exitOK
+}
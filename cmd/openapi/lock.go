@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/chanced/openapi"
+)
+
+func runLock(ctx context.Context, e *env, args []string) int {
+	fs := flag.NewFlagSet("lock", flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	file := fs.String("file", "openapi.lock", "path of the lockfile, or - for stdout")
+	verify := fs.Bool("verify", false, "verify the document's external resources against the lockfile rather than writing it")
+	fs.Usage = func() {
+		fmt.Fprint(e.stderr, "Usage: openapi lock [flags] <document>\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil || fs.NArg() != 1 || (*verify && *file == "-") {
+		if err == nil {
+			fs.Usage()
+		}
+		return exitUsage
+	}
+	path := fs.Arg(0)
+	v, err := openapi.NewOfflineValidator()
+	if err != nil {
+		fmt.Fprintf(e.stderr, "openapi: %v\n", err)
+		return exitFailure
+	}
+
+	lf := &openapi.Lockfile{}
+	mode := openapi.LockUpdate
+	if *verify {
+		data, err := os.ReadFile(*file)
+		if err != nil {
+			fmt.Fprintf(e.stderr, "openapi: %v\n", err)
+			return exitFailure
+		}
+		if lf, err = openapi.ParseLockfile(data); err != nil {
+			fmt.Fprintf(e.stderr, "openapi: %s: %v\n", *file, err)
+			return exitFailure
+		}
+		mode = openapi.LockVerify
+	}
+	if _, err := load(ctx, e, path, v, openapi.LoadOpts{Lockfile: lf, LockMode: mode}); err != nil {
+		reportError(e, path, err)
+		return exitFailure
+	}
+	if *verify {
+		fmt.Fprintf(e.stdout, "%s: %d resources match %s\n", path, lf.Len(), *file)
+		return exitOK
+	}
+
+	data, err := lf.MarshalText()
+	if err == nil {
+		if *file == "-" {
+			_, err = e.stdout.Write(data)
+		} else {
+			err = os.WriteFile(*file, data, 0o644)
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(e.stderr, "openapi: %v\n", err)
+		return exitFailure
+	}
+	return exitOK
+}
@@ -0,0 +1,251 @@
+// Command openapi validates, lints, inspects, transforms, and compares
+// OpenAPI 3.1 documents.
+//
+// Usage:
+//
+//	openapi <command> [flags] <document>...
+//
+// The commands are:
+//
+//	validate    validate documents against the OpenAPI specification
+//	lint        evaluate lint rules against a document
+//	stats       report statistics of a document
+//	bundle      move the external references of a document into its components
+//	deref       inline the references of a document
+//	convert     convert a document between OpenAPI 3.0 and 3.1
+//	diff        produce a JSON Patch transforming one document into another
+//	lock        write or verify the lockfile of a document's external resources
+//
+// Documents may be file paths or http(s) URLs. References to other resources
+// are resolved relative to the document. Output is written as text, JSON, or
+// YAML, as selected with -format; the documents written by bundle, deref, and
+// convert are JSON unless -format is yaml.
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/chanced/openapi"
+	"github.com/chanced/transcode"
+	"github.com/chanced/uri"
+)
+
+const (
+	exitOK      = 0
+	exitFailure = 1
+	exitUsage   = 2
+)
+
+type command struct {
+	name    string
+	summary string
+	run     func(ctx context.Context, env *env, args []string) int
+}
+
+var commands = []command{
+	{name: "validate", summary: "validate documents against the OpenAPI specification", run: runValidate},
+	{name: "lint", summary: "evaluate lint rules against a document", run: runLint},
+	{name: "stats", summary: "report statistics of a document", run: runStats},
+	{name: "bundle", summary: "move the external references of a document into its components", run: runBundle},
+	{name: "deref", summary: "inline the references of a document", run: runDeref},
+	{name: "convert", summary: "convert a document between OpenAPI 3.0 and 3.1", run: runConvert},
+	{name: "diff", summary: "produce a JSON Patch transforming one document into another", run: runDiff},
+	{name: "lock", summary: "write or verify the lockfile of a document's external resources", run: runLock},
+}
+
+// env is the environment of a command
+type env struct {
+	stdout io.Writer
+	stderr io.Writer
+	fetch  func(ctx context.Context, u uri.URI, kind openapi.Kind) (openapi.Kind, []byte, error)
+}
+
+func main() {
+	e := &env{stdout: os.Stdout, stderr: os.Stderr, fetch: fetch}
+	os.Exit(run(context.Background(), e, os.Args[1:]))
+}
+
+func run(ctx context.Context, e *env, args []string) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "-help" || args[0] == "help" {
+		usage(e.stderr)
+		return exitUsage
+	}
+	for _, c := range commands {
+		if c.name == args[0] {
+			return c.run(ctx, e, args[1:])
+		}
+	}
+	fmt.Fprintf(e.stderr, "openapi: unknown command %q\n\n", args[0])
+	usage(e.stderr)
+	return exitUsage
+}
+
+func usage(w io.Writer) {
+	fmt.Fprint(w, "Usage:\n\n\topenapi <command> [flags] <document>...\n\nThe commands are:\n\n")
+	for _, c := range commands {
+		fmt.Fprintf(w, "\t%-10s  %s\n", c.name, c.summary)
+	}
+	fmt.Fprintln(w, "\nUse \"openapi <command> -h\" for more information about a command.")
+}
+
+// newFlagSet creates a flag.FlagSet for the command name with a -format
+// flag.
+func newFlagSet(e *env, name, args string) (*flag.FlagSet, *string) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	format := fs.String("format", "text", "output format: text, json, or yaml")
+	fs.Usage = func() {
+		fmt.Fprintf(e.stderr, "Usage: openapi %s [flags] %s\n\nFlags:\n", name, args)
+		fs.PrintDefaults()
+	}
+	return fs, format
+}
+
+func checkFormat(e *env, format string) bool {
+	switch format {
+	case "text", "json", "yaml":
+		return true
+	default:
+		fmt.Fprintf(e.stderr, "openapi: unknown format %q\n", format)
+		return false
+	}
+}
+
+// write encodes v as JSON or YAML to e.stdout. If format is "text", text is
+// called instead.
+func write(e *env, format string, v interface{}, text func(w io.Writer)) error {
+	if format == "text" {
+		text(e.stdout)
+		return nil
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	if format == "yaml" {
+		if data, err = transcode.YAMLFromJSON(data); err != nil {
+			return err
+		}
+	} else {
+		data = append(data, '\n')
+	}
+	_, err = e.stdout.Write(data)
+	return err
+}
+
+// writeDocument writes data, a JSON encoded document, to e.stdout as YAML if
+// format is "yaml" and as indented JSON otherwise.
+func writeDocument(e *env, format string, data []byte) error {
+	var err error
+	if format == "yaml" {
+		data, err = transcode.YAMLFromJSON(data)
+	} else {
+		var b bytes.Buffer
+		if err = json.Indent(&b, data, "", "  "); err == nil {
+			b.WriteByte('\n')
+			data = b.Bytes()
+		}
+	}
+	if err != nil {
+		return err
+	}
+	_, err = e.stdout.Write(data)
+	return err
+}
+
+// load loads the document at path, which may be a file path or a URL.
+func load(ctx context.Context, e *env, path string, v openapi.Validator, opts ...openapi.LoadOpts) (*openapi.Document, error) {
+	u, err := documentURI(path)
+	if err != nil {
+		return nil, err
+	}
+	return openapi.Load(ctx, u, v, e.fetch, opts...)
+}
+
+func documentURI(path string) (string, error) {
+	for _, prefix := range []string{"http://", "https://", "file://"} {
+		if strings.HasPrefix(path, prefix) {
+			return path, nil
+		}
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(abs)}).String(), nil
+}
+
+// fetch loads resources from the file system or over HTTP.
+func fetch(ctx context.Context, u uri.URI, kind openapi.Kind) (openapi.Kind, []byte, error) {
+	u.Fragment = ""
+	u.RawFragment = ""
+	var data []byte
+	var err error
+	switch u.Scheme {
+	case "http", "https":
+		data, err = fetchHTTP(ctx, u.String())
+	case "file", "":
+		data, err = os.ReadFile(filepath.FromSlash(u.Path))
+	default:
+		err = fmt.Errorf("unsupported scheme %q", u.Scheme)
+	}
+	if err != nil {
+		return kind, nil, err
+	}
+	return detectKind(kind, data), data, nil
+}
+
+func fetchHTTP(ctx context.Context, u string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", u, res.Status)
+	}
+	return io.ReadAll(res.Body)
+}
+
+// detectKind returns kind or, if kind is undefined, KindDocument if data
+// has an openapi field and KindSchema otherwise.
+func detectKind(kind openapi.Kind, data []byte) openapi.Kind {
+	if kind != openapi.KindUndefined {
+		return kind
+	}
+	j, err := transcode.JSONFromYAML(data)
+	if err != nil {
+		return kind
+	}
+	if _, ok := openapi.TryGetOpenAPIVersion(j); ok {
+		return openapi.KindDocument
+	}
+	return openapi.KindSchema
+}
+
+// reportError writes err to e.stderr, grouping errors by file where
+// possible.
+func reportError(e *env, path string, err error) {
+	var ve *openapi.ValidationError
+	var ee openapi.ExtensionErrors
+	if errors.As(err, &ve) || errors.As(err, &ee) {
+		fmt.Fprintf(e.stderr, "%s: invalid:\n%s", path, openapi.NewErrorReport(err))
+		return
+	}
+	fmt.Fprintf(e.stderr, "%s: %v\n", path, err)
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	ctx := context.Background()
+	dir := filepath.Join("..", "..", "testdata", "documents", "validation")
+	pass := filepath.Join(dir, "pass", "minimal_paths.yaml")
+	fail := filepath.Join(dir, "fail", "servers.yaml")
+
+	exec := func(args ...string) (int, string, string) {
+		var stdout, stderr bytes.Buffer
+		code := run(ctx, &env{stdout: &stdout, stderr: &stderr, fetch: fetch}, args)
+		return code, stdout.String(), stderr.String()
+	}
+
+	if code, _, _ := exec(); code != exitUsage {
+		t.Errorf("expected exit status %d without a command, got %d", exitUsage, code)
+	}
+	if code, _, _ := exec("validate", "-format", "xml", pass); code != exitUsage {
+		t.Errorf("expected exit status %d for an unknown format, got %d", exitUsage, code)
+	}
+
+	code, stdout, stderr := exec("validate", pass)
+	if code != exitOK {
+		t.Errorf("expected %s to be valid: %s", pass, stderr)
+	}
+	if !strings.Contains(stdout, "valid") {
+		t.Errorf("unexpected output: %s", stdout)
+	}
+
+	code, stdout, _ = exec("validate", "-format", "json", fail)
+	if code != exitFailure {
+		t.Errorf("expected exit status %d for %s, got %d", exitFailure, fail, code)
+	}
+	var results []validationResult
+	if err := json.Unmarshal([]byte(stdout), &results); err != nil {
+		t.Fatalf("failed to decode output %q: %v", stdout, err)
+	}
+	if len(results) != 1 || results[0].Valid || len(results[0].Errors) == 0 {
+		t.Errorf("expected %s to be reported as invalid, got %+v", fail, results)
+	}
+
+	code, stdout, stderr = exec("stats", "-format", "json", pass)
+	if code != exitOK {
+		t.Fatalf("stats failed: %s", stderr)
+	}
+	var stats statsResult
+	if err := json.Unmarshal([]byte(stdout), &stats); err != nil {
+		t.Fatalf("failed to decode output %q: %v", stdout, err)
+	}
+
+	code, stdout, stderr = exec("lint", "-fail-on", "off", pass)
+	if code != exitOK {
+		t.Errorf("lint failed: %s", stderr)
+	}
+
+	tmp := filepath.Join(t.TempDir(), "changed.yaml")
+	data, err := os.ReadFile(pass)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.WriteFile(tmp, bytes.Replace(data, []byte("title: API"), []byte("title: Changed"), 1), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	code, stdout, stderr = exec("diff", "-format", "json", pass, tmp)
+	if code != exitOK {
+		t.Fatalf("diff failed: %s", stderr)
+	}
+	var patch []map[string]interface{}
+	if err = json.Unmarshal([]byte(stdout), &patch); err != nil {
+		t.Fatalf("failed to decode output %q: %v", stdout, err)
+	}
+	if len(patch) != 1 || patch[0]["path"] != "/info/title" || patch[0]["value"] != "Changed" {
+		t.Errorf("unexpected patch: %v", patch)
+	}
+}
+
+func TestLock(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	doc := filepath.Join(dir, "openapi.yaml")
+	pet := filepath.Join(dir, "pet.json")
+	lock := filepath.Join(dir, "openapi.lock")
+	write := func(path, data string) {
+		if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(doc, "openapi: 3.1.0\ninfo:\n  title: API\n  version: 1.0.0\npaths: {}\ncomponents:\n  schemas:\n    Pet:\n      $ref: pet.json\n")
+	write(pet, `{"type": "object"}`)
+
+	exec := func(args ...string) (int, string, string) {
+		var stdout, stderr bytes.Buffer
+		code := run(ctx, &env{stdout: &stdout, stderr: &stderr, fetch: fetch}, args)
+		return code, stdout.String(), stderr.String()
+	}
+
+	if code, _, stderr := exec("lock", "-file", lock, doc); code != exitOK {
+		t.Fatalf("lock failed: %s", stderr)
+	}
+	data, err := os.ReadFile(lock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "/pet.json sha256:") {
+		t.Errorf("unexpected lockfile: %s", data)
+	}
+	if code, _, stderr := exec("lock", "-verify", "-file", lock, doc); code != exitOK {
+		t.Errorf("expected the lockfile to be satisfied: %s", stderr)
+	}
+
+	write(pet, `{"type": "string"}`)
+	code, _, stderr := exec("lock", "-verify", "-file", lock, doc)
+	if code != exitFailure || !strings.Contains(stderr, "does not match lockfile") {
+		t.Errorf("expected verification to fail, got %d: %s", code, stderr)
+	}
+}
+
+func TestTransform(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	doc := filepath.Join(dir, "openapi.yaml")
+	write := func(path, data string) {
+		if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(doc, "openapi: 3.1.0\ninfo:\n  title: API\n  version: 1.0.0\npaths:\n  /pets:\n    get:\n      responses:\n        '200':\n          $ref: '#/components/responses/Pets'\ncomponents:\n  responses:\n    Pets:\n      description: pets\n      content:\n        application/json:\n          schema:\n            $ref: pet.json\n")
+	write(filepath.Join(dir, "pet.json"), `{"type": "object", "properties": {"name": {"type": ["string", "null"]}}}`)
+
+	exec := func(args ...string) (int, string, string) {
+		var stdout, stderr bytes.Buffer
+		code := run(ctx, &env{stdout: &stdout, stderr: &stderr, fetch: fetch}, args)
+		return code, stdout.String(), stderr.String()
+	}
+	decode := func(s string) map[string]interface{} {
+		t.Helper()
+		var v map[string]interface{}
+		if err := json.Unmarshal([]byte(s), &v); err != nil {
+			t.Fatalf("failed to decode output %q: %v", s, err)
+		}
+		return v
+	}
+
+	code, stdout, stderr := exec("bundle", doc)
+	if code != exitOK {
+		t.Fatalf("bundle failed: %s", stderr)
+	}
+	if strings.Contains(stdout, "pet.json") || !strings.Contains(stdout, `"$ref": "#/components/schemas/pet"`) {
+		t.Errorf("expected pet.json to be bundled:\n%s", stdout)
+	}
+	bundled := decode(stdout)
+	if _, ok := bundled["components"].(map[string]interface{})["schemas"].(map[string]interface{})["pet"]; !ok {
+		t.Errorf("expected the pet schema to be a component:\n%s", stdout)
+	}
+
+	code, stdout, stderr = exec("deref", "-format", "yaml", doc)
+	if code != exitOK {
+		t.Fatalf("deref failed: %s", stderr)
+	}
+	if strings.Contains(stdout, "#/components/responses/Pets") || strings.Contains(stdout, "pet.json") {
+		t.Errorf("expected the references to be inlined:\n%s", stdout)
+	}
+
+	code, stdout, stderr = exec("convert", "-to", "3.0.3", doc)
+	if code != exitOK {
+		t.Fatalf("convert failed: %s", stderr)
+	}
+	if decode(stdout)["openapi"] != "3.0.3" {
+		t.Errorf("expected the document to be converted to 3.0.3:\n%s", stdout)
+	}
+	converted := filepath.Join(dir, "converted.json")
+	write(converted, stdout)
+	code, stdout, stderr = exec("convert", converted)
+	if code != exitOK {
+		t.Fatalf("convert failed: %s", stderr)
+	}
+	if decode(stdout)["openapi"] != "3.1.0" {
+		t.Errorf("expected the document to be converted to 3.1.0:\n%s", stdout)
+	}
+
+	write(filepath.Join(dir, "webhooks.json"), `{"openapi": "3.1.0", "info": {"title": "API", "version": "1.0.0"}, "webhooks": {"pet": {}}}`)
+	if code, _, stderr = exec("convert", "-to", "3.0.3", filepath.Join(dir, "webhooks.json")); code != exitFailure || !strings.Contains(stderr, "unsupported conversion") {
+		t.Errorf("expected the conversion to fail, got %d: %s", code, stderr)
+	}
+}
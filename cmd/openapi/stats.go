@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/chanced/openapi"
+)
+
+type statsResult struct {
+	openapi.Stats
+	ExampleCoverage     float64 `json:"exampleCoverage"`
+	DescriptionCoverage float64 `json:"descriptionCoverage"`
+}
+
+func runStats(ctx context.Context, e *env, args []string) int {
+	fs, format := newFlagSet(e, "stats", "<document>")
+	if err := fs.Parse(args); err != nil || fs.NArg() != 1 || !checkFormat(e, *format) {
+		if err == nil {
+			fs.Usage()
+		}
+		return exitUsage
+	}
+	path := fs.Arg(0)
+	v, err := openapi.NewOfflineValidator()
+	if err != nil {
+		fmt.Fprintf(e.stderr, "openapi: %v\n", err)
+		return exitFailure
+	}
+	doc, err := load(ctx, e, path, v)
+	if err != nil {
+		reportError(e, path, err)
+		return exitFailure
+	}
+	s := doc.Stats()
+	res := statsResult{
+		Stats:               s,
+		ExampleCoverage:     s.ExampleCoverage(),
+		DescriptionCoverage: s.DescriptionCoverage(),
+	}
+	err = write(e, *format, res, func(w io.Writer) {
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintf(tw, "paths\t%d\n", s.Paths)
+		fmt.Fprintf(tw, "webhooks\t%d\n", s.Webhooks)
+		fmt.Fprintf(tw, "operations\t%d\n", s.Operations)
+		methods := make([]string, 0, len(s.OperationsByMethod))
+		for m := range s.OperationsByMethod {
+			methods = append(methods, m.String())
+		}
+		sort.Strings(methods)
+		for _, m := range methods {
+			fmt.Fprintf(tw, "  %s\t%d\n", m, s.OperationsByMethod[openapi.Text(m)])
+		}
+		fmt.Fprintf(tw, "deprecated operations\t%d\n", s.DeprecatedOperations)
+		fmt.Fprintf(tw, "description coverage\t%.1f%%\n", res.DescriptionCoverage*100)
+		fmt.Fprintf(tw, "example coverage\t%.1f%%\n", res.ExampleCoverage*100)
+		fmt.Fprintf(tw, "component schemas\t%d\n", s.ComponentSchemas)
+		fmt.Fprintf(tw, "schemas\t%d\n", s.Schemas)
+		fmt.Fprintf(tw, "  referenced\t%d\n", s.ReferencedSchemas)
+		fmt.Fprintf(tw, "  inline\t%d\n", s.InlineSchemas)
+		tw.Flush()
+	})
+	if err != nil {
+		fmt.Fprintf(e.stderr, "openapi: %v\n", err)
+		return exitFailure
+	}
+	return exitOK
+}
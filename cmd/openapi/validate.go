@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/chanced/openapi"
+)
+
+type validationResult struct {
+	Document string            `json:"document"`
+	Valid    bool              `json:"valid"`
+	Errors   []validationError `json:"errors,omitempty"`
+}
+
+type validationError struct {
+	File    string `json:"file,omitempty"`
+	Pointer string `json:"pointer"`
+	Message string `json:"message"`
+}
+
+func runValidate(ctx context.Context, e *env, args []string) int {
+	fs, format := newFlagSet(e, "validate", "<document>...")
+	if err := fs.Parse(args); err != nil || fs.NArg() == 0 || !checkFormat(e, *format) {
+		if err == nil {
+			fs.Usage()
+		}
+		return exitUsage
+	}
+	v, err := openapi.NewOfflineValidator()
+	if err != nil {
+		fmt.Fprintf(e.stderr, "openapi: %v\n", err)
+		return exitFailure
+	}
+	code := exitOK
+	var results []validationResult
+	for _, path := range fs.Args() {
+		res := validationResult{Document: path, Valid: true}
+		if _, err := load(ctx, e, path, v); err != nil {
+			code = exitFailure
+			res.Valid = false
+			var ve *openapi.ValidationError
+			var ee openapi.ExtensionErrors
+			if !errors.As(err, &ve) && !errors.As(err, &ee) {
+				// not a validation error; the document could not be loaded
+				reportError(e, path, err)
+				return exitFailure
+			}
+			for _, f := range openapi.NewErrorReport(err).Files {
+				for _, fe := range f.Errors {
+					res.Errors = append(res.Errors, validationError{
+						File:    f.URI.String(),
+						Pointer: fe.Pointer,
+						Message: fe.Message,
+					})
+				}
+			}
+			if *format == "text" {
+				reportError(e, path, err)
+			}
+		}
+		results = append(results, res)
+	}
+	err = write(e, *format, results, func(w io.Writer) {
+		for _, res := range results {
+			if res.Valid {
+				fmt.Fprintf(w, "%s: valid\n", res.Document)
+			}
+		}
+	})
+	if err != nil {
+		fmt.Fprintf(e.stderr, "openapi: %v\n", err)
+		return exitFailure
+	}
+	return code
+}
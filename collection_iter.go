@@ -0,0 +1,15 @@
+//go:build go1.23
+
+package openapi
+
+import "iter"
+
+// All returns an iterator over the entries of the ObjMap, in order.
+func (om *ObjMap[T]) All() iter.Seq2[Text, T] {
+	return om.Range
+}
+
+// All returns an iterator over the entries of the ComponentMap, in order.
+func (cm *ComponentMap[T]) All() iter.Seq2[Text, *Component[T]] {
+	return cm.Range
+}
@@ -0,0 +1,72 @@
+//go:build go1.23
+
+package openapi_test
+
+import (
+	"testing"
+
+	"github.com/chanced/openapi"
+)
+
+func TestObjMapAll(t *testing.T) {
+	om := openapi.NewObjMap(map[openapi.Text]*openapi.PathItem{
+		"/pets":   {Summary: "pets"},
+		"/owners": {Summary: "owners"},
+		"/toys":   {Summary: "toys"},
+	})
+	var keys []openapi.Text
+	for key, pi := range om.All() {
+		if pi != om.Get(key) {
+			t.Errorf("expected the value of %s", key)
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) != 3 || keys[0] != "/owners" || keys[1] != "/pets" || keys[2] != "/toys" {
+		t.Errorf("expected the entries in order, got %v", keys)
+	}
+
+	keys = nil
+	for key := range om.All() {
+		keys = append(keys, key)
+		if key == "/pets" {
+			break
+		}
+	}
+	if len(keys) != 2 {
+		t.Errorf("expected iteration to stop at /pets, got %v", keys)
+	}
+
+	var nilMap *openapi.ObjMap[*openapi.PathItem]
+	for key := range nilMap.All() {
+		t.Errorf("unexpected entry %s of a nil ObjMap", key)
+	}
+}
+
+func TestComponentMapAll(t *testing.T) {
+	cm := openapi.NewComponentMap(map[openapi.Text]*openapi.Response{
+		"NotFound":   {Description: "not found"},
+		"BadRequest": {Description: "bad request"},
+		"Conflict":   {Description: "conflict"},
+	})
+	var descriptions []openapi.Text
+	for _, c := range cm.All() {
+		descriptions = append(descriptions, c.Object.Description)
+	}
+	if len(descriptions) != 3 || descriptions[0] != "bad request" || descriptions[1] != "conflict" || descriptions[2] != "not found" {
+		t.Errorf("expected the entries in order, got %v", descriptions)
+	}
+
+	n := 0
+	for range cm.All() {
+		n++
+		break
+	}
+	if n != 1 {
+		t.Errorf("expected iteration to stop after 1 entry, got %d", n)
+	}
+
+	var nilMap *openapi.ComponentMap[*openapi.Response]
+	for key := range nilMap.All() {
+		t.Errorf("unexpected entry %s of a nil ComponentMap", key)
+	}
+}
@@ -0,0 +1,68 @@
+package openapi_test
+
+import (
+	"testing"
+
+	"github.com/chanced/openapi"
+)
+
+func TestObjMapCollection(t *testing.T) {
+	om := openapi.NewObjMap(map[openapi.Text]*openapi.PathItem{
+		"/pets":   {Summary: "pets"},
+		"/owners": {Summary: "owners"},
+		"/toys":   {Summary: "toys"},
+	})
+	if om.Len() != 3 {
+		t.Fatalf("expected 3 entries, got %d", om.Len())
+	}
+	keys := om.Keys()
+	if keys[0] != "/owners" || keys[1] != "/pets" || keys[2] != "/toys" {
+		t.Errorf("expected keys to be ordered, got %v", keys)
+	}
+	if v := om.Values(); v[1].Summary != "pets" {
+		t.Errorf("unexpected values: %v", v)
+	}
+	var visited []openapi.Text
+	om.Range(func(key openapi.Text, pi *openapi.PathItem) bool {
+		visited = append(visited, key)
+		return len(visited) < 2
+	})
+	if len(visited) != 2 {
+		t.Errorf("expected Range to stop after 2 entries, got %v", visited)
+	}
+	om.Set("/a", &openapi.PathItem{})
+	om.SortByKey()
+	if om.Keys()[0] != "/a" {
+		t.Errorf("expected /a to be sorted first, got %v", om.Keys())
+	}
+	var nilMap *openapi.ObjMap[*openapi.PathItem]
+	if nilMap.Len() != 0 || nilMap.Keys() != nil {
+		t.Error("expected an empty nil ObjMap")
+	}
+}
+
+func TestComponentMapCollection(t *testing.T) {
+	cm := openapi.NewComponentMap(map[openapi.Text]*openapi.Response{
+		"NotFound":   {Description: "not found"},
+		"BadRequest": {Description: "bad request"},
+	})
+	if cm.Len() != 2 || cm.Keys()[0] != "BadRequest" {
+		t.Errorf("unexpected keys: %v", cm.Keys())
+	}
+	if c := cm.Values()[1]; c.Object.Description != "not found" {
+		t.Errorf("unexpected value: %+v", c)
+	}
+	n := 0
+	cm.Range(func(key openapi.Text, c *openapi.Component[*openapi.Response]) bool {
+		n++
+		return true
+	})
+	if n != 2 {
+		t.Errorf("expected 2 entries, got %d", n)
+	}
+	cm.Items[0], cm.Items[1] = cm.Items[1], cm.Items[0]
+	cm.SortByKey()
+	if cm.Keys()[0] != "BadRequest" {
+		t.Errorf("expected entries to be sorted, got %v", cm.Keys())
+	}
+}
@@ -3,8 +3,8 @@ package openapi
 import (
 	"encoding/json"
 	"fmt"
+	"reflect"
 
-	"github.com/chanced/transcode"
 	"github.com/chanced/uri"
 	"gopkg.in/yaml.v3"
 )
@@ -15,6 +15,13 @@ type Component[T refable] struct {
 	Object    T
 }
 
+func (c *Component[T]) Nodes() []Node {
+	if c == nil {
+		return nil
+	}
+	return downcastNodes(c.nodes())
+}
+
 func (c *Component[T]) nodes() []node {
 	if c == nil {
 		return nil
@@ -101,6 +108,68 @@ func (c *Component[T]) location() Location {
 // IsReference returns true if this Component contains a Reference
 func (c *Component[T]) IsReference() bool { return !c.Reference.isNil() }
 
+// EffectiveSummary returns the summary of the Reference, if the Component is
+// a Reference which overrides it, or otherwise the summary of the Object. As
+// per the specification, the override has no effect if the Object's type
+// does not have a summary (i.e. it is neither an Example nor a PathItem).
+func (c *Component[T]) EffectiveSummary() Text {
+	if c == nil {
+		return ""
+	}
+	s, ok := objectSummary(any(c.Object))
+	if ok && c.Reference != nil && c.Reference.Summary != "" {
+		return c.Reference.Summary
+	}
+	return s
+}
+
+// EffectiveDescription returns the description of the Reference, if the
+// Component is a Reference which overrides it, or otherwise the description
+// of the Object. The override has no effect if the Object's type does not
+// have a description (i.e. it is Callbacks).
+func (c *Component[T]) EffectiveDescription() Text {
+	if c == nil {
+		return ""
+	}
+	d, ok := objectDescription(any(c.Object))
+	if ok && c.Reference != nil && c.Reference.Description != "" {
+		return c.Reference.Description
+	}
+	return d
+}
+
+// Effective returns the Object of the Component with the summary and
+// description overrides of its Reference, if any, merged in. As the Object
+// may be referenced elsewhere, overrides are applied to a shallow copy of it
+// rather than to the Object itself; if the Reference does not override
+// either field, the Object is returned as is.
+//
+// For example, a webhook which references a PathItem of the Components and
+// provides its own description yields a PathItem with the operations of the
+// referenced PathItem and the description of the webhook.
+func (c *Component[T]) Effective() T {
+	var obj T
+	if c == nil {
+		return obj
+	}
+	obj = c.Object
+	if c.Reference == nil || obj.isNil() {
+		return obj
+	}
+	summary, description := c.EffectiveSummary(), c.EffectiveDescription()
+	s, _ := objectSummary(any(obj))
+	d, _ := objectDescription(any(obj))
+	if summary == s && description == d {
+		return obj
+	}
+	v := reflect.New(reflect.TypeOf(obj).Elem())
+	v.Elem().Set(reflect.ValueOf(obj).Elem())
+	obj = v.Interface().(T)
+	setObjectSummary(any(obj), summary)
+	setObjectDescription(any(obj), description)
+	return obj
+}
+
 func (c *Component[T]) Refs() []Ref {
 	if c == nil {
 		return nil
@@ -182,11 +251,7 @@ func (c *Component[T]) MarshalYAML() (interface{}, error) {
 
 // UnmarshalYAML implements yaml.Unmarshaler
 func (c *Component[T]) UnmarshalYAML(value *yaml.Node) error {
-	v, err := yaml.Marshal(value)
-	if err != nil {
-		return err
-	}
-	j, err := transcode.JSONFromYAML(v)
+	j, err := jsonFromYAMLNode(value)
 	if err != nil {
 		return err
 	}
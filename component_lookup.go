@@ -0,0 +1,163 @@
+package openapi
+
+import (
+	"fmt"
+
+	"github.com/chanced/uri"
+)
+
+// GetComponent returns the component of type T named name from the
+// Components of doc (e.g. GetComponent[*Response](doc, "NotFound")).
+//
+// If the component does not exist, an error matching ErrNotFound is
+// returned. If the component is a Reference which has not been resolved, an
+// error matching ErrUnresolvedReference is returned. If T is not the type of
+// a component, an error matching ErrUnsupportedKind is returned.
+func GetComponent[T Node](doc *Document, name Text) (T, error) {
+	var zero T
+	var c *Components
+	if doc != nil {
+		c = doc.Components
+	}
+	var n Node
+	var err error
+	switch any(zero).(type) {
+	case *Schema:
+		n, err = c.SchemaByName(name)
+	case *Response:
+		n, err = c.ResponseByName(name)
+	case *Parameter:
+		n, err = c.ParameterByName(name)
+	case *RequestBody:
+		n, err = c.RequestBodyByName(name)
+	case *Header:
+		n, err = c.HeaderByName(name)
+	case *SecurityScheme:
+		n, err = c.SecuritySchemeByName(name)
+	case *Link:
+		n, err = c.LinkByName(name)
+	case *Callbacks:
+		n, err = c.CallbacksByName(name)
+	case *PathItem:
+		n, err = c.PathItemByName(name)
+	case *Example:
+		n, err = c.ExampleByName(name)
+	default:
+		return zero, fmt.Errorf("%w: %T is not a component", ErrUnsupportedKind, zero)
+	}
+	if err != nil {
+		return zero, err
+	}
+	return n.(T), nil
+}
+
+// SchemaByName returns the Schema named name.
+//
+// The Schema is returned as is; if it has a $ref, see Schema.ResolvedRef.
+func (c *Components) SchemaByName(name Text) (*Schema, error) {
+	if c != nil && c.Schemas != nil {
+		if s := c.Schemas.Get(name); s != nil {
+			return s, nil
+		}
+	}
+	return nil, c.errComponentNotFound("schemas", name)
+}
+
+// ResponseByName returns the Response named name, resolving the Component.
+func (c *Components) ResponseByName(name Text) (*Response, error) {
+	if c == nil {
+		return nil, c.errComponentNotFound("responses", name)
+	}
+	return componentByName(c, c.Responses, "responses", name)
+}
+
+// ParameterByName returns the Parameter named name, resolving the
+// Component.
+func (c *Components) ParameterByName(name Text) (*Parameter, error) {
+	if c == nil {
+		return nil, c.errComponentNotFound("parameters", name)
+	}
+	return componentByName(c, c.Parameters, "parameters", name)
+}
+
+// RequestBodyByName returns the RequestBody named name, resolving the
+// Component.
+func (c *Components) RequestBodyByName(name Text) (*RequestBody, error) {
+	if c == nil {
+		return nil, c.errComponentNotFound("requestBodies", name)
+	}
+	return componentByName(c, c.RequestBodies, "requestBodies", name)
+}
+
+// HeaderByName returns the Header named name, resolving the Component.
+func (c *Components) HeaderByName(name Text) (*Header, error) {
+	if c == nil {
+		return nil, c.errComponentNotFound("headers", name)
+	}
+	return componentByName(c, c.Headers, "headers", name)
+}
+
+// SecuritySchemeByName returns the SecurityScheme named name, resolving the
+// Component.
+func (c *Components) SecuritySchemeByName(name Text) (*SecurityScheme, error) {
+	if c == nil {
+		return nil, c.errComponentNotFound("securitySchemes", name)
+	}
+	return componentByName(c, c.SecuritySchemes, "securitySchemes", name)
+}
+
+// LinkByName returns the Link named name, resolving the Component.
+func (c *Components) LinkByName(name Text) (*Link, error) {
+	if c == nil {
+		return nil, c.errComponentNotFound("links", name)
+	}
+	return componentByName(c, c.Links, "links", name)
+}
+
+// CallbacksByName returns the Callbacks named name, resolving the
+// Component.
+func (c *Components) CallbacksByName(name Text) (*Callbacks, error) {
+	if c == nil {
+		return nil, c.errComponentNotFound("callbacks", name)
+	}
+	return componentByName(c, c.Callbacks, "callbacks", name)
+}
+
+// PathItemByName returns the PathItem named name, resolving the Component.
+func (c *Components) PathItemByName(name Text) (*PathItem, error) {
+	if c == nil {
+		return nil, c.errComponentNotFound("pathItems", name)
+	}
+	return componentByName(c, c.PathItems, "pathItems", name)
+}
+
+// ExampleByName returns the Example named name, resolving the Component.
+func (c *Components) ExampleByName(name Text) (*Example, error) {
+	if c == nil {
+		return nil, c.errComponentNotFound("examples", name)
+	}
+	return componentByName(c, c.Examples, "examples", name)
+}
+
+func componentByName[T refable](c *Components, cm *ComponentMap[T], kind string, name Text) (T, error) {
+	var zero T
+	comp := cm.Get(name)
+	if comp == nil {
+		return zero, c.errComponentNotFound(kind, name)
+	}
+	if comp.Object.isNil() {
+		if comp.Reference != nil {
+			return zero, newErrUnresolvedReference(comp.Reference)
+		}
+		return zero, c.errComponentNotFound(kind, name)
+	}
+	return comp.Object, nil
+}
+
+func (c *Components) errComponentNotFound(kind string, name Text) error {
+	var u uri.URI
+	if c != nil {
+		u = c.AbsoluteLocation()
+	}
+	return NewError(fmt.Errorf("%w: %s %q", ErrNotFound, kind, name), u)
+}
@@ -0,0 +1,65 @@
+package openapi_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/chanced/openapi"
+	"github.com/chanced/uri"
+)
+
+func TestGetComponent(t *testing.T) {
+	data := []byte(`{
+		"openapi": "3.1.0",
+		"info": { "title": "components", "version": "1.0.0" },
+		"paths": {},
+		"components": {
+			"schemas": {
+				"Pet": { "type": "object" }
+			},
+			"responses": {
+				"NotFound": { "description": "not found" },
+				"Missing": { "$ref": "#/components/responses/NotFound" }
+			},
+			"parameters": {
+				"limit": { "name": "limit", "in": "query", "schema": { "type": "integer" } }
+			}
+		}
+	}`)
+	doc := loadDocument(t, "https://example.com/openapi.json", data)
+
+	pet, err := openapi.GetComponent[*openapi.Schema](doc, "Pet")
+	if err != nil || pet != doc.Components.Schemas.Get("Pet") {
+		t.Errorf("unexpected result: %v, %v", pet, err)
+	}
+	res, err := openapi.GetComponent[*openapi.Response](doc, "Missing")
+	if err != nil || res.Description != "not found" {
+		t.Errorf("expected the referenced Response, got %v, %v", res, err)
+	}
+	param, err := doc.Components.ParameterByName("limit")
+	if err != nil || param.Name != "limit" {
+		t.Errorf("unexpected result: %v, %v", param, err)
+	}
+	if _, err = openapi.GetComponent[*openapi.Header](doc, "X-Rate-Limit"); !errors.Is(err, openapi.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	if _, err = doc.Components.SchemaByName("Owner"); !errors.Is(err, openapi.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	if _, err = openapi.GetComponent[*openapi.Info](doc, "Info"); !errors.Is(err, openapi.ErrUnsupportedKind) {
+		t.Errorf("expected ErrUnsupportedKind, got %v", err)
+	}
+	if _, err = openapi.GetComponent[*openapi.Schema](nil, "Pet"); !errors.Is(err, openapi.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+
+	c := &openapi.Components{Responses: &openapi.ResponseMap{}}
+	ref := uri.MustParse("#/components/responses/NotFound")
+	c.Responses.Items = append(c.Responses.Items, &openapi.ComponentEntry[*openapi.Response]{
+		Key:       "Unresolved",
+		Component: &openapi.Component[*openapi.Response]{Reference: &openapi.Reference[*openapi.Response]{Ref: ref}},
+	})
+	if _, err = c.ResponseByName("Unresolved"); !errors.Is(err, openapi.ErrUnresolvedReference) {
+		t.Errorf("expected ErrUnresolvedReference, got %v", err)
+	}
+}
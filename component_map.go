@@ -1,12 +1,12 @@
 package openapi
 
 import (
-	"bytes"
 	"encoding/json"
 	"reflect"
+	"sort"
 
 	"github.com/chanced/jsonx"
-	"github.com/chanced/transcode"
+	"github.com/chanced/uri"
 	"github.com/tidwall/gjson"
 	"gopkg.in/yaml.v3"
 )
@@ -29,6 +29,13 @@ type ComponentMap[T refable] struct {
 	Items []*ComponentEntry[T]
 }
 
+func (cm *ComponentMap[T]) Nodes() []Node {
+	if cm == nil {
+		return nil
+	}
+	return downcastNodes(cm.nodes())
+}
+
 func (cm *ComponentMap[T]) nodes() []node {
 	if cm == nil {
 		return nil
@@ -59,6 +66,73 @@ func (cm ComponentMap[T]) Map() map[Text]*Component[T] {
 	return m
 }
 
+// NewComponentMap returns a ComponentMap of the objects of m, ordered by key.
+func NewComponentMap[T refable](m map[Text]T) *ComponentMap[T] {
+	keys := make([]Text, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	cm := &ComponentMap[T]{Items: make([]*ComponentEntry[T], len(keys))}
+	for i, k := range keys {
+		cm.Items[i] = &ComponentEntry[T]{Key: k, Component: &Component[T]{Object: m[k]}}
+	}
+	return cm
+}
+
+// Len returns the number of entries in the ComponentMap.
+func (cm *ComponentMap[T]) Len() int {
+	if cm == nil {
+		return 0
+	}
+	return len(cm.Items)
+}
+
+// Keys returns the keys of the ComponentMap, in order.
+func (cm *ComponentMap[T]) Keys() []Text {
+	if cm == nil {
+		return nil
+	}
+	keys := make([]Text, len(cm.Items))
+	for i, item := range cm.Items {
+		keys[i] = item.Key
+	}
+	return keys
+}
+
+// Values returns the Components of the ComponentMap, in order.
+func (cm *ComponentMap[T]) Values() []*Component[T] {
+	if cm == nil {
+		return nil
+	}
+	values := make([]*Component[T], len(cm.Items))
+	for i, item := range cm.Items {
+		values[i] = item.Component
+	}
+	return values
+}
+
+// Range calls fn for each entry of the ComponentMap, in order, until fn
+// returns false.
+func (cm *ComponentMap[T]) Range(fn func(key Text, c *Component[T]) bool) {
+	if cm == nil {
+		return
+	}
+	for _, item := range cm.Items {
+		if !fn(item.Key, item.Component) {
+			return
+		}
+	}
+}
+
+// SortByKey orders the entries of the ComponentMap by key.
+func (cm *ComponentMap[T]) SortByKey() {
+	if cm == nil {
+		return
+	}
+	sort.SliceStable(cm.Items, func(i, j int) bool { return cm.Items[i].Key < cm.Items[j].Key })
+}
+
 func (*ComponentMap[T]) Kind() Kind {
 	var t T
 	return t.mapKind()
@@ -75,16 +149,20 @@ func (cm *ComponentMap[T]) UnmarshalJSON(data []byte) error {
 		}
 	}
 	var err error
+	obj := gjson.ParseBytes(data)
+	n := countMembers(obj)
 	*cm = ComponentMap[T]{
-		Items: make([]*ComponentEntry[T], 0),
+		Items: make([]*ComponentEntry[T], 0, n),
 	}
-	gjson.ParseBytes(data).ForEach(func(key, value gjson.Result) bool {
-		var comp Component[T]
+	entries := newAllocator[ComponentEntry[T]](n)
+	comps := newAllocator[Component[T]](n)
+	obj.ForEach(func(key, value gjson.Result) bool {
+		comp := comps.new()
 		err = comp.UnmarshalJSON([]byte(value.Raw))
-		cm.Items = append(cm.Items, &ComponentEntry[T]{
-			Key:       Text(key.String()),
-			Component: &comp,
-		})
+		entry := entries.new()
+		entry.Key = intern(key.String())
+		entry.Component = comp
+		cm.Items = append(cm.Items, entry)
 		return err == nil
 	})
 	return err
@@ -96,22 +174,23 @@ func (cm *ComponentMap[T]) isNil() bool {
 
 // MarshalJSON marshals JSON
 func (cm ComponentMap[T]) MarshalJSON() ([]byte, error) {
-	b := bytes.Buffer{}
+	b := getBuffer()
 	b.WriteByte('{')
 	for _, field := range cm.Items {
 		if b.Len() > 1 {
 			b.WriteByte(',')
 		}
-		jsonx.EncodeAndWriteString(&b, field.Key)
+		jsonx.EncodeAndWriteString(b, field.Key)
 		b.WriteByte(':')
 		cb, err := field.Component.MarshalJSON()
 		if err != nil {
+			putBuffer(b)
 			return nil, err
 		}
 		b.Write(cb)
 	}
 	b.WriteByte('}')
-	return b.Bytes(), nil
+	return releaseBuffer(b), nil
 }
 
 func (cm *ComponentMap[T]) Get(key Text) *Component[T] {
@@ -126,26 +205,55 @@ func (cm *ComponentMap[T]) Get(key Text) *Component[T] {
 	return nil
 }
 
-// Set sets the value of the key in the ComponentMap
+// Set sets the Component of key. If key exists, its Component is replaced in
+// place, retaining its position; otherwise the entry is appended.
+//
+// cm must not be nil.
 func (cm *ComponentMap[T]) Set(key Text, value *Component[T]) {
-	if cm == nil {
-		*cm = ComponentMap[T]{}
+	cm.Upsert(key, func(*Component[T], bool) *Component[T] { return value })
+}
+
+// SetObject sets the Component of key to obj, as Set does.
+func (cm *ComponentMap[T]) SetObject(key Text, obj T) {
+	cm.Set(key, &Component[T]{Object: obj})
+}
+
+// SetRef sets the Component of key to a Reference to ref, as Set does. The
+// Reference is unresolved.
+func (cm *ComponentMap[T]) SetRef(key Text, ref uri.URI) {
+	c := &Component[T]{}
+	var t T
+	c.Reference = &Reference[T]{
+		Ref:            &ref,
+		ReferencedKind: t.Kind(),
+		dst:            &c.Object,
 	}
-	for i, v := range cm.Items {
+	cm.Set(key, c)
+}
+
+// Upsert sets the Component of key to the result of fn, which is called with
+// the existing Component of key, if any, and whether it exists. The entry
+// retains its position if it exists and is appended otherwise.
+//
+// cm must not be nil.
+func (cm *ComponentMap[T]) Upsert(key Text, fn func(existing *Component[T], exists bool) *Component[T]) {
+	for _, v := range cm.Items {
 		if v.Key == key {
-			cm.Items[i] = &ComponentEntry[T]{
-				Key:       key,
-				Component: value,
-			}
+			v.Component = fn(v.Component, true)
+			return
 		}
 	}
 	cm.Items = append(cm.Items, &ComponentEntry[T]{
 		Key:       key,
-		Component: value,
+		Component: fn(nil, false),
 	})
 }
 
+// Del removes the entry of key, if it exists.
 func (cm *ComponentMap[T]) Del(key Text) {
+	if cm == nil {
+		return
+	}
 	for i, v := range cm.Items {
 		if v.Key == key {
 			cm.Items = append(cm.Items[:i], cm.Items[i+1:]...)
@@ -169,11 +277,7 @@ func (cm *ComponentMap[T]) MarshalYAML() (interface{}, error) {
 
 // UnmarshalYAML implements yaml.Unmarshaler
 func (cm *ComponentMap[T]) UnmarshalYAML(value *yaml.Node) error {
-	v, err := yaml.Marshal(value)
-	if err != nil {
-		return err
-	}
-	j, err := transcode.JSONFromYAML(v)
+	j, err := jsonFromYAMLNode(value)
 	if err != nil {
 		return err
 	}
@@ -202,7 +306,7 @@ func (cm *ComponentMap[T]) Anchors() (*Anchors, error) {
 	var err error
 
 	for _, kv := range cm.Items {
-		if anchors, err = kv.Component.Anchors(); err != nil {
+		if anchors, err = anchors.merge(kv.Component.Anchors()); err != nil {
 			return nil, err
 		}
 	}
@@ -0,0 +1,77 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/chanced/uri"
+)
+
+func testComponentMapSet[T refable](t *testing.T, newObj func() T) {
+	var t0 T
+	t.Run(t0.Kind().String(), func(t *testing.T) {
+		cm := &ComponentMap[T]{}
+		a, b, c := newObj(), newObj(), newObj()
+		cm.SetObject("a", a)
+		cm.SetObject("b", b)
+		cm.SetObject("c", c)
+
+		replacement := newObj()
+		cm.SetObject("b", replacement)
+		if cm.Len() != 3 {
+			t.Fatalf("expected 3 entries after replacement, got %d", cm.Len())
+		}
+		if keys := cm.Keys(); keys[0] != "a" || keys[1] != "b" || keys[2] != "c" {
+			t.Errorf("expected order to be retained, got %v", keys)
+		}
+		if any(cm.Get("b").Object) != any(replacement) {
+			t.Error("expected b to be replaced")
+		}
+
+		cm.SetRef("a", *uri.MustParse("#/components/x/b"))
+		comp := cm.Get("a")
+		if !comp.IsReference() || comp.Reference.Ref.String() != "#/components/x/b" {
+			t.Errorf("expected a to be a reference, got %+v", comp)
+		}
+		if comp.Reference.ReferencedKind != t0.Kind() {
+			t.Errorf("expected referenced kind %s, got %s", t0.Kind(), comp.Reference.ReferencedKind)
+		}
+		if err := comp.Reference.resolve(replacement); err != nil {
+			t.Fatal(err)
+		}
+		if any(comp.Object) != any(replacement) {
+			t.Error("expected resolving the reference to set the object")
+		}
+
+		var calls []bool
+		upsert := func(existing *Component[T], exists bool) *Component[T] {
+			calls = append(calls, exists)
+			return &Component[T]{Object: newObj()}
+		}
+		cm.Upsert("c", upsert)
+		cm.Upsert("d", upsert)
+		if len(calls) != 2 || !calls[0] || calls[1] {
+			t.Errorf("unexpected upsert calls: %v", calls)
+		}
+		if keys := cm.Keys(); len(keys) != 4 || keys[2] != "c" || keys[3] != "d" {
+			t.Errorf("unexpected keys: %v", keys)
+		}
+
+		cm.Del("b")
+		cm.Del("missing")
+		if keys := cm.Keys(); len(keys) != 3 || keys[1] != "c" {
+			t.Errorf("unexpected keys after delete: %v", keys)
+		}
+	})
+}
+
+func TestComponentMapSet(t *testing.T) {
+	testComponentMapSet(t, func() *Response { return &Response{} })
+	testComponentMapSet(t, func() *Parameter { return &Parameter{} })
+	testComponentMapSet(t, func() *RequestBody { return &RequestBody{} })
+	testComponentMapSet(t, func() *Header { return &Header{} })
+	testComponentMapSet(t, func() *SecurityScheme { return &SecurityScheme{} })
+	testComponentMapSet(t, func() *Link { return &Link{} })
+	testComponentMapSet(t, func() *Callbacks { return &Callbacks{} })
+	testComponentMapSet(t, func() *PathItem { return &PathItem{} })
+	testComponentMapSet(t, func() *Example { return &Example{} })
+}
@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"strconv"
 
-	"github.com/chanced/transcode"
 	"gopkg.in/yaml.v3"
 )
 
@@ -14,6 +13,13 @@ type ComponentSlice[T refable] struct {
 	Items    []*Component[T] `json:"-"`
 }
 
+func (cs *ComponentSlice[T]) Nodes() []Node {
+	if cs == nil {
+		return nil
+	}
+	return downcastNodes(cs.nodes())
+}
+
 func (cs *ComponentSlice[T]) nodes() []node {
 	if cs == nil {
 		return nil
@@ -108,7 +114,7 @@ func (cs *ComponentSlice[T]) Anchors() (*Anchors, error) {
 	var anchors *Anchors
 	var err error
 	for _, item := range cs.Items {
-		if anchors, err = item.Anchors(); err != nil {
+		if anchors, err = anchors.merge(item.Anchors()); err != nil {
 			return nil, err
 		}
 	}
@@ -130,11 +136,7 @@ func (cs *ComponentSlice[T]) MarshalYAML() (interface{}, error) {
 
 // UnmarshalYAML implements yaml.Unmarshaler
 func (cs *ComponentSlice[T]) UnmarshalYAML(value *yaml.Node) error {
-	v, err := yaml.Marshal(value)
-	if err != nil {
-		return err
-	}
-	j, err := transcode.JSONFromYAML(v)
+	j, err := jsonFromYAMLNode(value)
 	if err != nil {
 		return err
 	}
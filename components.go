@@ -3,7 +3,6 @@ package openapi
 import (
 	"encoding/json"
 
-	"github.com/chanced/transcode"
 	"gopkg.in/yaml.v3"
 )
 
@@ -147,6 +146,13 @@ func (c *Components) Refs() []Ref {
 //			return nil, newErrNotResolvable(c.AbsoluteLocation(), tok)
 //		}
 //	}
+func (c *Components) Nodes() []Node {
+	if c == nil {
+		return nil
+	}
+	return downcastNodes(c.nodes())
+}
+
 func (c *Components) nodes() []node {
 	if c == nil {
 		return nil
@@ -283,11 +289,7 @@ func (c Components) MarshalYAML() (interface{}, error) {
 
 // UnmarshalYAML implements yaml.Unmarshaler
 func (c *Components) UnmarshalYAML(value *yaml.Node) error {
-	v, err := yaml.Marshal(value)
-	if err != nil {
-		return err
-	}
-	j, err := transcode.JSONFromYAML(v)
+	j, err := jsonFromYAMLNode(value)
 	if err != nil {
 		return err
 	}
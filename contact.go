@@ -3,7 +3,6 @@ package openapi
 import (
 	"encoding/json"
 
-	"github.com/chanced/transcode"
 	"github.com/chanced/uri"
 	"gopkg.in/yaml.v3"
 )
@@ -44,6 +43,13 @@ func (*Contact) Refs() []Ref { return nil }
 // 	return nil, newErrNotResolvable(c.AbsoluteLocation(), tok)
 // }
 
+func (c *Contact) Nodes() []Node {
+	if c == nil {
+		return nil
+	}
+	return downcastNodes(c.nodes())
+}
+
 func (*Contact) nodes() []node        { return nil }
 func (c *Contact) isNil() bool        { return c == nil }
 func (c *Contact) location() Location { return c.Location }
@@ -88,11 +94,7 @@ func (c Contact) MarshalYAML() (interface{}, error) {
 
 // UnmarshalYAML implements yaml.Unmarshaler
 func (c *Contact) UnmarshalYAML(value *yaml.Node) error {
-	v, err := yaml.Marshal(value)
-	if err != nil {
-		return err
-	}
-	j, err := transcode.JSONFromYAML(v)
+	j, err := jsonFromYAMLNode(value)
 	if err != nil {
 		return err
 	}
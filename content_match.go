@@ -0,0 +1,64 @@
+package openapi
+
+import (
+	"mime"
+	"strings"
+)
+
+// MatchContent returns the key and MediaType of content which applies to the
+// media type mediaType (e.g. the Content-Type of a request or response),
+// according to the specificity rules of the specification: an exact match
+// (e.g. "text/plain") is preferred over a subtype wildcard (e.g. "text/*"),
+// which is preferred over a type wildcard ("*/*").
+//
+// Parameters of mediaType (e.g. "charset=utf-8") are ignored unless a key
+// specifies them, in which case each must match for the key to apply. Among
+// keys of equal specificity, those with more matching parameters are
+// preferred; remaining ties are resolved by order.
+//
+// If no key applies or mediaType is invalid, ok is false.
+func MatchContent(content *ContentMap, mediaType string) (key Text, mt *MediaType, ok bool) {
+	if content == nil {
+		return "", nil, false
+	}
+	typ, params, err := mime.ParseMediaType(mediaType)
+	if err != nil {
+		return "", nil, false
+	}
+	best := -1
+	for _, item := range content.Items {
+		score := mediaTypeScore(item.Key.String(), typ, params)
+		if score > best {
+			best = score
+			key, mt, ok = item.Key, item.Value, true
+		}
+	}
+	return key, mt, ok
+}
+
+// mediaTypeScore returns the specificity with which the media type range r
+// matches the media type typ with params or -1 if it does not.
+func mediaTypeScore(r string, typ string, params map[string]string) int {
+	rtyp, rparams, err := mime.ParseMediaType(r)
+	if err != nil {
+		return -1
+	}
+	var score int
+	switch {
+	case rtyp == typ:
+		score = 3
+	case rtyp == "*/*" || rtyp == "*":
+		score = 1
+	case strings.HasSuffix(rtyp, "/*") && strings.HasPrefix(typ, strings.TrimSuffix(rtyp, "*")):
+		score = 2
+	default:
+		return -1
+	}
+	for k, v := range rparams {
+		if !strings.EqualFold(params[k], v) {
+			return -1
+		}
+	}
+	// parameters refine, but never outweigh, the specificity of the type
+	return score<<8 + len(rparams)
+}
@@ -0,0 +1,50 @@
+package openapi_test
+
+import (
+	"testing"
+
+	"github.com/chanced/openapi"
+)
+
+func TestMatchContent(t *testing.T) {
+	content := &openapi.ContentMap{}
+	for _, key := range []openapi.Text{
+		"*/*",
+		"text/*",
+		"text/plain",
+		"text/plain; charset=utf-8",
+		"application/json",
+		"application/*",
+	} {
+		content.Set(key, &openapi.MediaType{})
+	}
+	tests := []struct {
+		mediaType string
+		expected  openapi.Text
+	}{
+		{"text/plain", "text/plain"},
+		{"text/plain; charset=UTF-8", "text/plain; charset=utf-8"},
+		{"text/plain; charset=iso-8859-1", "text/plain"},
+		{"TEXT/HTML", "text/*"},
+		{"application/json; charset=utf-8", "application/json"},
+		{"application/xml", "application/*"},
+		{"image/png", "*/*"},
+	}
+	for _, test := range tests {
+		key, mt, ok := openapi.MatchContent(content, test.mediaType)
+		if !ok || key != test.expected {
+			t.Errorf("expected %q to match %q, got %q", test.mediaType, test.expected, key)
+		}
+		if mt != content.Get(test.expected) {
+			t.Errorf("expected the MediaType of %q", test.expected)
+		}
+	}
+	if _, _, ok := openapi.MatchContent(content, "not a media type"); ok {
+		t.Error("expected an invalid media type to not match")
+	}
+	content = &openapi.ContentMap{}
+	content.Set("application/json", &openapi.MediaType{})
+	if _, _, ok := openapi.MatchContent(content, "text/plain"); ok {
+		t.Error("expected no match")
+	}
+}
@@ -0,0 +1,532 @@
+package openapi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Masterminds/semver"
+	"gopkg.in/yaml.v3"
+)
+
+// ConvertVersion converts data, an OpenAPI 3.0 or 3.1 Document encoded as
+// JSON or YAML, to the OpenAPI version to, which must be 3.0.x or 3.1.x. The
+// converted Document is returned encoded as JSON, with the order of its keys
+// preserved.
+//
+// When converting from 3.0 to 3.1, the Schemas of the Document are converted
+// as follows:
+//   - nullable is replaced by "null" in type
+//   - boolean exclusiveMinimum and exclusiveMaximum are replaced by the value
+//     of minimum and maximum respectively
+//   - example is replaced by examples
+//   - the formats byte and binary are replaced by a contentEncoding of
+//     base64 and a contentMediaType of application/octet-stream
+//
+// When converting from 3.1 to 3.0, the reverse is performed, const is
+// replaced by an enum of its value, boolean Schemas are replaced by {} and
+// {"not": {}}, and an empty paths object is added if the Document does not
+// have one. An error wrapping ErrUnsupportedConversion is returned if the
+// Document uses a feature which 3.0 can not represent, such as webhooks, the
+// pathItems of Components, a Schema with multiple non-null types, or a JSON
+// Schema keyword which OpenAPI 3.0 does not support (e.g. prefixItems).
+//
+// References are not followed; resources other than the Document must be
+// converted separately.
+func ConvertVersion(data []byte, to semver.Version) ([]byte, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, err
+	}
+	// normalizing through JSON expands aliases and merge keys
+	j, err := jsonFromYAMLNode(&root)
+	if err != nil {
+		return nil, err
+	}
+	root = yaml.Node{}
+	if err = yaml.Unmarshal(j, &root); err != nil {
+		return nil, err
+	}
+	if root.Kind != yaml.DocumentNode || len(root.Content) == 0 || root.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("openapi: expected a Document")
+	}
+	doc := root.Content[0]
+	vn := yamlMappingValue(doc, "openapi")
+	if vn == nil {
+		return nil, ErrMissingOpenAPIVersion
+	}
+	from, err := semver.NewVersion(vn.Value)
+	if err != nil {
+		return nil, fmt.Errorf("openapi: invalid openapi version %q: %w", vn.Value, err)
+	}
+	for _, v := range []*semver.Version{from, &to} {
+		if v.Major() != 3 || v.Minor() > 1 {
+			return nil, fmt.Errorf("%w: OpenAPI %s", ErrUnsupportedConversion, v)
+		}
+	}
+	vn.Value = to.String()
+	vn.Tag = "!!str"
+	vn.Style = 0
+	if from.Minor() != to.Minor() {
+		c := converter{upgrade: to.Minor() == 1}
+		if err = c.document(doc); err != nil {
+			return nil, err
+		}
+	}
+	return jsonFromYAMLNode(&root)
+}
+
+// converter converts the raw nodes of a Document between OpenAPI 3.0 and 3.1
+type converter struct {
+	// upgrade is true when converting from 3.0 to 3.1 and false when
+	// converting from 3.1 to 3.0
+	upgrade bool
+}
+
+func (c *converter) unsupported(ptr string, format string, args ...interface{}) error {
+	if ptr == "" {
+		ptr = "/"
+	}
+	return fmt.Errorf("%w: %s at %q", ErrUnsupportedConversion, fmt.Sprintf(format, args...), ptr)
+}
+
+func (c *converter) document(n *yaml.Node) error {
+	if !c.upgrade {
+		if v := yamlMappingValue(n, "webhooks"); v != nil && len(v.Content) > 0 {
+			return c.unsupported("/webhooks", "webhooks")
+		}
+		if yamlMappingValue(n, "jsonSchemaDialect") != nil {
+			return c.unsupported("/jsonSchemaDialect", "jsonSchemaDialect")
+		}
+		if info := yamlMappingValue(n, "info"); info != nil {
+			if yamlMappingValue(info, "summary") != nil {
+				return c.unsupported("/info/summary", "the summary of info")
+			}
+			if l := yamlMappingValue(info, "license"); l != nil && yamlMappingValue(l, "identifier") != nil {
+				return c.unsupported("/info/license/identifier", "the identifier of license")
+			}
+		}
+		yamlMappingDelete(n, "webhooks")
+		if yamlMappingValue(n, "paths") == nil {
+			yamlMappingInsertAfter(n, "info", "paths", &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"})
+		}
+	}
+	if err := c.each(n, "", "paths", c.pathItem); err != nil {
+		return err
+	}
+	if err := c.each(n, "", "webhooks", c.pathItem); err != nil {
+		return err
+	}
+	return c.components(yamlMappingValue(n, "components"), "/components")
+}
+
+func (c *converter) components(n *yaml.Node, ptr string) error {
+	if n == nil {
+		return nil
+	}
+	if !c.upgrade {
+		if v := yamlMappingValue(n, "pathItems"); v != nil && len(v.Content) > 0 {
+			return c.unsupported(ptr+"/pathItems", "the pathItems of components")
+		}
+		yamlMappingDelete(n, "pathItems")
+	}
+	for _, m := range []struct {
+		key string
+		fn  func(*yaml.Node, string) error
+	}{
+		{"schemas", c.schema},
+		{"responses", c.response},
+		{"parameters", c.parameter},
+		{"requestBodies", c.requestBody},
+		{"headers", c.parameter},
+		{"callbacks", c.callback},
+		{"pathItems", c.pathItem},
+	} {
+		if err := c.each(n, ptr, m.key, m.fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *converter) pathItem(n *yaml.Node, ptr string) error {
+	if err := c.items(n, ptr, "parameters", c.parameter); err != nil {
+		return err
+	}
+	for _, m := range []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"} {
+		if op := yamlMappingValue(n, m); op != nil {
+			if err := c.operation(op, ptr+"/"+m); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (c *converter) operation(n *yaml.Node, ptr string) error {
+	if err := c.items(n, ptr, "parameters", c.parameter); err != nil {
+		return err
+	}
+	if rb := yamlMappingValue(n, "requestBody"); rb != nil {
+		if err := c.requestBody(rb, ptr+"/requestBody"); err != nil {
+			return err
+		}
+	}
+	if err := c.each(n, ptr, "responses", c.response); err != nil {
+		return err
+	}
+	return c.each(n, ptr, "callbacks", c.callback)
+}
+
+func (c *converter) callback(n *yaml.Node, ptr string) error {
+	if n.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if err := c.pathItem(n.Content[i+1], ptr+"/"+escapePointerToken(n.Content[i].Value)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parameter converts a Parameter or a Header
+func (c *converter) parameter(n *yaml.Node, ptr string) error {
+	if s := yamlMappingValue(n, "schema"); s != nil {
+		if err := c.schema(s, ptr+"/schema"); err != nil {
+			return err
+		}
+	}
+	return c.each(n, ptr, "content", c.mediaType)
+}
+
+func (c *converter) requestBody(n *yaml.Node, ptr string) error {
+	return c.each(n, ptr, "content", c.mediaType)
+}
+
+func (c *converter) response(n *yaml.Node, ptr string) error {
+	if err := c.each(n, ptr, "headers", c.parameter); err != nil {
+		return err
+	}
+	return c.each(n, ptr, "content", c.mediaType)
+}
+
+func (c *converter) mediaType(n *yaml.Node, ptr string) error {
+	if s := yamlMappingValue(n, "schema"); s != nil {
+		if err := c.schema(s, ptr+"/schema"); err != nil {
+			return err
+		}
+	}
+	return c.each(n, ptr, "encoding", func(e *yaml.Node, ptr string) error {
+		return c.each(e, ptr, "headers", c.parameter)
+	})
+}
+
+// schemaMaps are the keywords of a Schema which are objects of Schemas
+var schemaMaps = []string{"properties", "patternProperties", "$defs", "dependentSchemas"}
+
+// schemaSlices are the keywords of a Schema which are arrays of Schemas
+var schemaSlices = []string{"allOf", "anyOf", "oneOf", "prefixItems"}
+
+// schemaValues are the keywords of a Schema which are Schemas
+var schemaValues = []string{
+	"items", "additionalProperties", "not", "if", "then", "else", "contains",
+	"propertyNames", "unevaluatedItems", "unevaluatedProperties", "additionalItems",
+}
+
+// oas30SchemaKeywords are the keywords of an OpenAPI 3.0 Schema
+var oas30SchemaKeywords = map[string]bool{
+	"$ref": true, "title": true, "multipleOf": true, "maximum": true,
+	"exclusiveMaximum": true, "minimum": true, "exclusiveMinimum": true,
+	"maxLength": true, "minLength": true, "pattern": true, "maxItems": true,
+	"minItems": true, "uniqueItems": true, "maxProperties": true,
+	"minProperties": true, "required": true, "enum": true, "type": true,
+	"allOf": true, "oneOf": true, "anyOf": true, "not": true, "items": true,
+	"properties": true, "additionalProperties": true, "description": true,
+	"format": true, "default": true, "nullable": true, "discriminator": true,
+	"readOnly": true, "writeOnly": true, "xml": true, "externalDocs": true,
+	"example": true, "deprecated": true,
+}
+
+func (c *converter) schema(n *yaml.Node, ptr string) error {
+	if n.Kind == yaml.ScalarNode && n.ShortTag() == "!!bool" && !c.upgrade {
+		// 3.0 does not have boolean Schemas
+		s := yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		if n.Value == "false" {
+			yamlMappingSet(&s, "not", &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"})
+		}
+		*n = s
+		return nil
+	}
+	if n.Kind != yaml.MappingNode {
+		return nil
+	}
+	var err error
+	if c.upgrade {
+		c.upgradeSchema(n)
+	} else if err = c.downgradeSchema(n, ptr); err != nil {
+		return err
+	}
+	for _, key := range schemaMaps {
+		if err = c.each(n, ptr, key, c.schema); err != nil {
+			return err
+		}
+	}
+	for _, key := range schemaSlices {
+		if err = c.items(n, ptr, key, c.schema); err != nil {
+			return err
+		}
+	}
+	for _, key := range schemaValues {
+		v := yamlMappingValue(n, key)
+		if v == nil {
+			continue
+		}
+		if v.Kind == yaml.SequenceNode {
+			// items as an array, prior to 2020-12
+			err = c.items(n, ptr, key, c.schema)
+		} else {
+			err = c.schema(v, ptr+"/"+key)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *converter) upgradeSchema(n *yaml.Node) {
+	if v := yamlMappingValue(n, "nullable"); v != nil {
+		yamlMappingDelete(n, "nullable")
+		// nullable has no effect without a type
+		if t := yamlMappingValue(n, "type"); t != nil && v.Value == "true" {
+			if t.Kind == yaml.ScalarNode {
+				*t = yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Content: []*yaml.Node{yamlScalar(t.Value)}}
+			}
+			if !yamlSequenceContains(t, "!!str", "null") {
+				t.Content = append(t.Content, yamlScalar("null"))
+			}
+		}
+	}
+	for _, m := range [][2]string{{"exclusiveMinimum", "minimum"}, {"exclusiveMaximum", "maximum"}} {
+		v := yamlMappingValue(n, m[0])
+		if v == nil || v.ShortTag() != "!!bool" {
+			continue
+		}
+		limit := yamlMappingValue(n, m[1])
+		if v.Value == "true" && limit != nil {
+			*v = *limit
+			yamlMappingDelete(n, m[1])
+		} else {
+			yamlMappingDelete(n, m[0])
+		}
+	}
+	if yamlMappingValue(n, "examples") == nil {
+		if v := yamlMappingValue(n, "example"); v != nil {
+			*v = yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Content: []*yaml.Node{yamlCopy(v)}}
+			yamlMappingRename(n, "example", "examples")
+		}
+	}
+	if f := yamlMappingValue(n, "format"); f != nil {
+		switch f.Value {
+		case "byte":
+			f.Value = "base64"
+			yamlMappingRename(n, "format", "contentEncoding")
+		case "binary":
+			f.Value = "application/octet-stream"
+			yamlMappingRename(n, "format", "contentMediaType")
+		}
+	}
+}
+
+func (c *converter) downgradeSchema(n *yaml.Node, ptr string) error {
+	if t := yamlMappingValue(n, "type"); t != nil && t.Kind == yaml.SequenceNode {
+		var types []*yaml.Node
+		nullable := false
+		for _, v := range t.Content {
+			if v.Value == "null" {
+				nullable = true
+			} else {
+				types = append(types, v)
+			}
+		}
+		switch len(types) {
+		case 0:
+			return c.unsupported(ptr+"/type", "a type of null")
+		case 1:
+			*t = *types[0]
+		default:
+			return c.unsupported(ptr+"/type", "multiple types")
+		}
+		if nullable {
+			yamlMappingInsertAfter(n, "type", "nullable", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: "true"})
+		}
+	} else if t != nil && t.Value == "null" {
+		return c.unsupported(ptr+"/type", "a type of null")
+	}
+	for _, m := range [][2]string{{"exclusiveMinimum", "minimum"}, {"exclusiveMaximum", "maximum"}} {
+		v := yamlMappingValue(n, m[0])
+		if v == nil || v.ShortTag() == "!!bool" {
+			continue
+		}
+		if limit := yamlMappingValue(n, m[1]); limit != nil {
+			// both apply, so only the more restrictive is retained
+			ev, err1 := strconv.ParseFloat(v.Value, 64)
+			lv, err2 := strconv.ParseFloat(limit.Value, 64)
+			if err1 != nil || err2 != nil {
+				return c.unsupported(ptr+"/"+m[0], "a non-numeric %s", m[0])
+			}
+			if (m[1] == "minimum" && lv > ev) || (m[1] == "maximum" && lv < ev) {
+				yamlMappingDelete(n, m[0])
+				continue
+			}
+			yamlMappingDelete(n, m[1])
+		}
+		yamlMappingRename(n, m[0], m[1])
+		yamlMappingInsertAfter(n, m[1], m[0], &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: "true"})
+	}
+	if v := yamlMappingValue(n, "examples"); v != nil && v.Kind == yaml.SequenceNode {
+		if len(v.Content) == 0 || yamlMappingValue(n, "example") != nil {
+			yamlMappingDelete(n, "examples")
+		} else {
+			*v = *v.Content[0]
+			yamlMappingRename(n, "examples", "example")
+		}
+	}
+	if v := yamlMappingValue(n, "const"); v != nil {
+		yamlMappingDelete(n, "enum")
+		*v = yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Content: []*yaml.Node{yamlCopy(v)}}
+		yamlMappingRename(n, "const", "enum")
+	}
+	if e := yamlMappingValue(n, "contentEncoding"); e != nil {
+		if e.Value != "base64" {
+			return c.unsupported(ptr+"/contentEncoding", "a contentEncoding of %q", e.Value)
+		}
+		yamlMappingDelete(n, "contentMediaType")
+		if yamlMappingValue(n, "format") == nil {
+			e.Value = "byte"
+			yamlMappingRename(n, "contentEncoding", "format")
+		} else {
+			yamlMappingDelete(n, "contentEncoding")
+		}
+	} else if m := yamlMappingValue(n, "contentMediaType"); m != nil {
+		if yamlMappingValue(n, "format") == nil {
+			m.Value = "binary"
+			yamlMappingRename(n, "contentMediaType", "format")
+		} else {
+			yamlMappingDelete(n, "contentMediaType")
+		}
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		key := n.Content[i].Value
+		if !oas30SchemaKeywords[key] && !strings.HasPrefix(key, "x-") {
+			return c.unsupported(ptr+"/"+escapePointerToken(key), "the keyword %s", key)
+		}
+	}
+	return nil
+}
+
+// each calls fn for each value of the object n[key]
+func (c *converter) each(n *yaml.Node, ptr, key string, fn func(*yaml.Node, string) error) error {
+	m := yamlMappingValue(n, key)
+	if m == nil || m.Kind != yaml.MappingNode {
+		return nil
+	}
+	ptr += "/" + escapePointerToken(key)
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if err := fn(m.Content[i+1], ptr+"/"+escapePointerToken(m.Content[i].Value)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// items calls fn for each item of the array n[key]
+func (c *converter) items(n *yaml.Node, ptr, key string, fn func(*yaml.Node, string) error) error {
+	s := yamlMappingValue(n, key)
+	if s == nil || s.Kind != yaml.SequenceNode {
+		return nil
+	}
+	ptr += "/" + escapePointerToken(key)
+	for i, v := range s.Content {
+		if err := fn(v, ptr+"/"+strconv.Itoa(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// yamlMappingValue returns the value of key in the mapping n, if any
+func yamlMappingValue(n *yaml.Node, key string) *yaml.Node {
+	if n == nil || n.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func yamlMappingSet(n *yaml.Node, key string, v *yaml.Node) {
+	if cur := yamlMappingValue(n, key); cur != nil {
+		*cur = *v
+		return
+	}
+	n.Content = append(n.Content, yamlScalar(key), v)
+}
+
+// yamlMappingInsertAfter sets key to v in the mapping n, positioned after
+// the key after if it is present or last otherwise
+func yamlMappingInsertAfter(n *yaml.Node, after, key string, v *yaml.Node) {
+	yamlMappingDelete(n, key)
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == after {
+			content := make([]*yaml.Node, 0, len(n.Content)+2)
+			content = append(content, n.Content[:i+2]...)
+			content = append(content, yamlScalar(key), v)
+			n.Content = append(content, n.Content[i+2:]...)
+			return
+		}
+	}
+	n.Content = append(n.Content, yamlScalar(key), v)
+}
+
+func yamlMappingDelete(n *yaml.Node, key string) {
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			n.Content = append(n.Content[:i], n.Content[i+2:]...)
+			return
+		}
+	}
+}
+
+// yamlMappingRename renames the key from of the mapping n to, retaining its
+// position
+func yamlMappingRename(n *yaml.Node, from, to string) {
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == from {
+			n.Content[i] = yamlScalar(to)
+			return
+		}
+	}
+}
+
+func yamlSequenceContains(n *yaml.Node, tag, value string) bool {
+	for _, v := range n.Content {
+		if v.ShortTag() == tag && v.Value == value {
+			return true
+		}
+	}
+	return false
+}
+
+func yamlScalar(s string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: s}
+}
+
+func yamlCopy(n *yaml.Node) *yaml.Node {
+	c := *n
+	return &c
+}
@@ -0,0 +1,99 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/Masterminds/semver"
+	"github.com/chanced/openapi"
+)
+
+func TestConvertVersion(t *testing.T) {
+	v30 := *semver.MustParse("3.0.3")
+	v31 := *semver.MustParse("3.1.0")
+
+	oas30 := `{
+		"openapi": "3.0.3",
+		"info": { "title": "Pet Store", "version": "1.0.0" },
+		"paths": {
+			"/pets": {
+				"post": {
+					"requestBody": {
+						"content": {
+							"multipart/form-data": {
+								"schema": {
+									"type": "object",
+									"properties": { "photo": { "type": "string", "format": "binary" } }
+								}
+							}
+						}
+					},
+					"responses": { "201": { "description": "created" } }
+				}
+			}
+		},
+		"components": {
+			"schemas": {
+				"Pet": {
+					"type": "object",
+					"properties": {
+						"name": { "type": "string", "nullable": true, "example": "Tom" },
+						"age": { "type": "integer", "minimum": 0, "exclusiveMinimum": true },
+						"kind": { "type": "string", "enum": ["cat", "dog"], "nullable": true },
+						"data": { "type": "string", "format": "byte" }
+					}
+				}
+			}
+		}
+	}`
+	oas31 := `{"openapi":"3.1.0","info":{"title":"Pet Store","version":"1.0.0"},"paths":{"/pets":{"post":{"requestBody":{"content":{"multipart/form-data":{"schema":{"type":"object","properties":{"photo":{"type":"string","contentMediaType":"application/octet-stream"}}}}}},"responses":{"201":{"description":"created"}}}}},` +
+		`"components":{"schemas":{"Pet":{"type":"object","properties":{"name":{"type":["string","null"],"examples":["Tom"]},"age":{"type":"integer","exclusiveMinimum":0},"kind":{"type":["string","null"],"enum":["cat","dog"]},"data":{"type":"string","contentEncoding":"base64"}}}}}}`
+
+	data, err := openapi.ConvertVersion([]byte(oas30), v31)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != oas31 {
+		t.Errorf("expected:\n%s\ngot:\n%s", oas31, data)
+	}
+	var compact map[string]interface{}
+	if err = json.Unmarshal([]byte(oas30), &compact); err != nil {
+		t.Fatal(err)
+	}
+	data, err = openapi.ConvertVersion([]byte(oas31), v30)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var roundTrip map[string]interface{}
+	if err = json.Unmarshal(data, &roundTrip); err != nil {
+		t.Fatal(err)
+	}
+	expected, _ := json.Marshal(compact)
+	actual, _ := json.Marshal(roundTrip)
+	if string(actual) != string(expected) {
+		t.Errorf("expected the 3.0 Document to round-trip:\n%s\ngot:\n%s", expected, actual)
+	}
+
+	data, err = openapi.ConvertVersion([]byte("openapi: 3.1.0\ninfo:\n  title: API\n  version: 1.0.0\ncomponents:\n  schemas:\n    Color:\n      const: red\n    Never: false\n"), v30)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := `{"openapi":"3.0.3","info":{"title":"API","version":"1.0.0"},"paths":{},"components":{"schemas":{"Color":{"enum":["red"]},"Never":{"not":{}}}}}`; string(data) != s {
+		t.Errorf("expected:\n%s\ngot:\n%s", s, data)
+	}
+
+	unsupported := []string{
+		`{"openapi": "3.1.0", "info": { "title": "API", "version": "1.0.0" }, "webhooks": { "pet": {} }}`,
+		`{"openapi": "3.1.0", "info": { "title": "API", "version": "1.0.0" }, "components": { "schemas": { "Pet": { "type": ["string", "integer"] } } }}`,
+		`{"openapi": "3.1.0", "info": { "title": "API", "version": "1.0.0" }, "components": { "schemas": { "Pair": { "prefixItems": [{}, {}] } } }}`,
+	}
+	for _, doc := range unsupported {
+		if _, err = openapi.ConvertVersion([]byte(doc), v30); !errors.Is(err, openapi.ErrUnsupportedConversion) {
+			t.Errorf("expected ErrUnsupportedConversion for %s, got %v", doc, err)
+		}
+	}
+	if _, err = openapi.ConvertVersion([]byte(oas31), *semver.MustParse("2.0.0")); !errors.Is(err, openapi.ErrUnsupportedConversion) {
+		t.Errorf("expected ErrUnsupportedConversion for 2.0.0, got %v", err)
+	}
+}
@@ -0,0 +1,289 @@
+// Package coverage tracks which parts of an OpenAPI Document are exercised
+// by HTTP traffic.
+//
+// A Recorder matches observed requests to Operations and their responses to
+// the Operations' Responses and media types. It can be installed as an
+// http.RoundTripper, to record the requests of a client, or as middleware, to
+// record the requests handled by a server. The resulting Report describes
+// which of the documented surface was exercised and which traffic was not
+// documented, which makes it useful in integration test suites.
+package coverage
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/chanced/openapi"
+)
+
+// Options configures a Recorder.
+type Options struct {
+	// BasePaths are prefixes which are removed from the paths of requests
+	// before they are matched to the Document's paths (e.g. "/v1"). If empty,
+	// the paths of the URLs of the Document's servers are used.
+	BasePaths []string
+}
+
+// Recorder records HTTP traffic against the Operations of a Document. It is
+// safe for concurrent use.
+type Recorder struct {
+	doc    *openapi.Document
+	router *router
+
+	mu        sync.Mutex
+	hits      map[hit]int
+	unmatched []Request
+}
+
+type hit struct {
+	op          *openapi.Operation
+	status      int
+	contentType string
+}
+
+type route struct {
+	entry    openapi.OperationEntry
+	pattern  *regexp.Regexp
+	vars     int
+	literals int
+}
+
+// Request is a request which could not be matched to an Operation.
+type Request struct {
+	Method     string
+	Path       string
+	StatusCode int
+}
+
+// NewRecorder returns a Recorder for the Operations of doc's Paths.
+func NewRecorder(doc *openapi.Document, opts Options) (*Recorder, error) {
+	if doc == nil {
+		return nil, fmt.Errorf("coverage: cannot record against a nil Document")
+	}
+	rt, err := newRouter(doc, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Recorder{doc: doc, router: rt, hits: map[hit]int{}}, nil
+}
+
+// router matches requests to the Operations of a Document
+type router struct {
+	routes    []*route
+	basePaths []string
+}
+
+func newRouter(doc *openapi.Document, opts Options) (*router, error) {
+	r := &router{basePaths: append([]string(nil), opts.BasePaths...)}
+	for _, e := range doc.Operations() {
+		if e.Webhook {
+			continue
+		}
+		rt, err := newRoute(e)
+		if err != nil {
+			return nil, err
+		}
+		r.routes = append(r.routes, rt)
+	}
+	// concrete paths are matched before templated paths
+	sort.SliceStable(r.routes, func(i, j int) bool {
+		if r.routes[i].vars != r.routes[j].vars {
+			return r.routes[i].vars < r.routes[j].vars
+		}
+		return r.routes[i].literals > r.routes[j].literals
+	})
+	if len(r.basePaths) == 0 && doc.Servers != nil {
+		for _, s := range doc.Servers.Items {
+			if s == nil {
+				continue
+			}
+			if p := serverPath(s); p != "" {
+				r.basePaths = append(r.basePaths, p)
+			}
+		}
+	}
+	for i, p := range r.basePaths {
+		r.basePaths[i] = strings.TrimSuffix(p, "/")
+	}
+	return r, nil
+}
+
+var templateVariable = regexp.MustCompile(`\{[^{}]+\}`)
+
+func newRoute(e openapi.OperationEntry) (*route, error) {
+	path := e.Key.String()
+	rt := &route{entry: e}
+	var b strings.Builder
+	b.WriteString("^")
+	last := 0
+	for _, loc := range templateVariable.FindAllStringIndex(path, -1) {
+		b.WriteString(regexp.QuoteMeta(path[last:loc[0]]))
+		b.WriteString("[^/]+")
+		rt.literals += loc[0] - last
+		rt.vars++
+		last = loc[1]
+	}
+	b.WriteString(regexp.QuoteMeta(path[last:]))
+	rt.literals += len(path) - last
+	b.WriteString("/?$")
+	var err error
+	if rt.pattern, err = regexp.Compile(b.String()); err != nil {
+		return nil, fmt.Errorf("coverage: invalid path %q: %w", path, err)
+	}
+	return rt, nil
+}
+
+// serverPath returns the path of the URL of s with its variables replaced by
+// their defaults
+func serverPath(s *openapi.Server) string {
+	raw := templateVariable.ReplaceAllStringFunc(s.URL.String(), func(m string) string {
+		if s.Variables != nil {
+			if v := s.Variables.Get(openapi.Text(m[1 : len(m)-1])); v != nil {
+				return v.Default.String()
+			}
+		}
+		return m
+	})
+	u, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(u.Path, "/")
+}
+
+// match returns the Operation of the request method and path. If the path
+// matches a path of the Document but the method does not, pathFound is true.
+func (r *router) match(method, path string) (e openapi.OperationEntry, found, pathFound bool) {
+	candidates := []string{path}
+	for _, p := range r.basePaths {
+		if p != "" && strings.HasPrefix(path, p) {
+			rest := path[len(p):]
+			if rest == "" {
+				rest = "/"
+			}
+			if rest[0] == '/' {
+				candidates = append([]string{rest}, candidates...)
+			}
+		}
+	}
+	for _, c := range candidates {
+		for _, rt := range r.routes {
+			if !rt.pattern.MatchString(c) {
+				continue
+			}
+			if strings.EqualFold(rt.entry.Method.String(), method) {
+				return rt.entry, true, true
+			}
+			pathFound = true
+		}
+	}
+	return openapi.OperationEntry{}, false, pathFound
+}
+
+// Record records a request and the status code and Content-Type of its
+// response.
+func (r *Recorder) Record(req *http.Request, statusCode int, contentType string) {
+	e, ok, _ := r.router.match(req.Method, req.URL.Path)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !ok {
+		r.unmatched = append(r.unmatched, Request{Method: req.Method, Path: req.URL.Path, StatusCode: statusCode})
+		return
+	}
+	r.hits[hit{op: e.Operation, status: statusCode, contentType: contentType}]++
+}
+
+// Reset discards all recorded traffic.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.hits = map[hit]int{}
+	r.unmatched = nil
+}
+
+// Transport returns an http.RoundTripper which records the requests sent
+// through next. If next is nil, http.DefaultTransport is used.
+func (r *Recorder) Transport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return roundTripper{recorder: r, next: next}
+}
+
+type roundTripper struct {
+	recorder *Recorder
+	next     http.RoundTripper
+}
+
+func (rt roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	res, err := rt.next.RoundTrip(req)
+	if err != nil {
+		return res, err
+	}
+	rt.recorder.Record(req, res.StatusCode, res.Header.Get("Content-Type"))
+	return res, nil
+}
+
+// Middleware returns an http.Handler which records the requests handled by
+// next.
+func (r *Recorder) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		sw := &statusWriter{ResponseWriter: w}
+		next.ServeHTTP(sw, req)
+		if sw.status == 0 {
+			sw.status = http.StatusOK
+		}
+		r.Record(req, sw.status, sw.Header().Get("Content-Type"))
+	})
+}
+
+// statusWriter records the status code of a response and, if limit is
+// greater than zero, up to limit bytes of its body
+type statusWriter struct {
+	http.ResponseWriter
+	status    int
+	limit     int
+	body      []byte
+	truncated bool
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	if w.limit > 0 && !w.truncated {
+		if len(w.body)+len(b) > w.limit {
+			w.truncated = true
+			w.body = nil
+		} else {
+			w.body = append(w.body, b...)
+		}
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap returns the underlying http.ResponseWriter, for use with
+// http.ResponseController.
+func (w *statusWriter) Unwrap() http.ResponseWriter { return w.ResponseWriter }
+
+// responseKey returns the key of the Response of responses which matches
+// statusCode, falling back to its range (e.g. "4XX") and then "default"
+func responseKey(responses *openapi.ResponseMap, statusCode int) (openapi.Text, bool) {
+	e := openapi.MatchResponse(responses, statusCode)
+	if e == nil {
+		return "", false
+	}
+	return e.Key, true
+}
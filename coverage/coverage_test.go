@@ -0,0 +1,157 @@
+package coverage_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/chanced/openapi"
+	"github.com/chanced/openapi/coverage"
+	"github.com/chanced/uri"
+)
+
+func loadDocument(t *testing.T) *openapi.Document {
+	t.Helper()
+	data := []byte(`{
+		"openapi": "3.1.0",
+		"info": { "title": "Pet Store", "version": "1.0.0" },
+		"servers": [{ "url": "https://api.example.com/v1" }],
+		"paths": {
+			"/pets": {
+				"get": {
+					"operationId": "listPets",
+					"responses": {
+						"200": {
+							"description": "ok",
+							"content": {
+								"application/json": { "schema": { "type": "array" } },
+								"application/xml": { "schema": { "type": "array" } }
+							}
+						}
+					}
+				}
+			},
+			"/pets/mine": {
+				"get": {
+					"operationId": "listMyPets",
+					"responses": { "200": { "description": "ok" } }
+				}
+			},
+			"/pets/{petId}": {
+				"get": {
+					"operationId": "getPet",
+					"responses": {
+						"200": { "description": "ok" },
+						"4XX": { "description": "client error" }
+					}
+				},
+				"delete": {
+					"operationId": "deletePet",
+					"responses": { "204": { "description": "deleted" } }
+				}
+			}
+		}
+	}`)
+	fn := func(ctx context.Context, u uri.URI, kind openapi.Kind) (openapi.Kind, []byte, error) {
+		return openapi.KindDocument, data, nil
+	}
+	v, err := openapi.NewOfflineValidator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := openapi.Load(context.Background(), "https://example.com/openapi.json", v, fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+func TestRecorder(t *testing.T) {
+	rec, err := coverage.NewRecorder(loadDocument(t), coverage.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/pets", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		_, _ = w.Write([]byte(`[]`))
+	})
+	mux.HandleFunc("/v1/pets/mine", func(w http.ResponseWriter, r *http.Request) {})
+	mux.HandleFunc("/v1/pets/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/pets/0" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	srv := httptest.NewServer(rec.Middleware(mux))
+	defer srv.Close()
+
+	for _, path := range []string{"/v1/pets", "/v1/pets/mine", "/v1/pets/0", "/v1/pets/1", "/v1/owners"} {
+		res, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+	}
+
+	report := rec.Report()
+	stats := report.Stats()
+	expected := coverage.Stats{
+		Operations: 4, CoveredOperations: 3,
+		Responses: 5, CoveredResponses: 3,
+		MediaTypes: 2, CoveredMediaTypes: 1,
+	}
+	if stats != expected {
+		t.Errorf("expected stats %+v, got %+v", expected, stats)
+	}
+	if r := stats.Ratio(); r != 7.0/11.0 {
+		t.Errorf("unexpected ratio: %f", r)
+	}
+	uncovered := []string{
+		"GET /pets 200 application/xml",
+		"GET /pets/{petId} 200",
+		"DELETE /pets/{petId}",
+	}
+	if !reflect.DeepEqual(report.Uncovered(), uncovered) {
+		t.Errorf("expected uncovered %v, got %v", uncovered, report.Uncovered())
+	}
+	undocumented := []string{"GET /v1/owners", "GET /pets/{petId} 500"}
+	if !reflect.DeepEqual(report.Undocumented(), undocumented) {
+		t.Errorf("expected undocumented %v, got %v", undocumented, report.Undocumented())
+	}
+}
+
+func TestRecorderTransport(t *testing.T) {
+	rec, err := coverage.NewRecorder(loadDocument(t), coverage.Options{BasePaths: []string{"/api"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	c := &http.Client{Transport: rec.Transport(nil)}
+	req, err := http.NewRequest(http.MethodDelete, srv.URL+"/api/pets/1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := c.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+
+	for _, oc := range rec.Report().Operations {
+		if oc.OperationID == "deletePet" {
+			if oc.Calls != 1 || oc.Responses[0].Calls != 1 {
+				t.Errorf("expected deletePet to be covered: %+v", oc)
+			}
+			return
+		}
+	}
+	t.Error("deletePet not found in report")
+}
@@ -0,0 +1,503 @@
+package coverage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/chanced/openapi"
+)
+
+// FindingKind is the kind of a Finding.
+type FindingKind uint8
+
+const (
+	// FindingUnknownPath indicates that the path of a request does not match
+	// a path of the Document.
+	FindingUnknownPath FindingKind = iota + 1
+	// FindingUnknownMethod indicates that the path of a request matches a path
+	// of the Document but the path does not have an Operation for the method.
+	FindingUnknownMethod
+	// FindingUndocumentedParameter indicates that a request has a query
+	// parameter which is not a Parameter of its Operation.
+	FindingUndocumentedParameter
+	// FindingUndocumentedRequestContentType indicates that the Content-Type of
+	// a request is not a media type of the Operation's request body.
+	FindingUndocumentedRequestContentType
+	// FindingUndocumentedStatus indicates that the status code of a response
+	// does not match a Response of the Operation.
+	FindingUndocumentedStatus
+	// FindingUndocumentedResponseContentType indicates that the Content-Type
+	// of a response is not a media type of the matched Response.
+	FindingUndocumentedResponseContentType
+	// FindingUndocumentedProperty indicates that the JSON body of a response
+	// has a property which is not described by the Schema of its media type.
+	FindingUndocumentedProperty
+)
+
+func (k FindingKind) String() string {
+	switch k {
+	case FindingUnknownPath:
+		return "unknown path"
+	case FindingUnknownMethod:
+		return "unknown method"
+	case FindingUndocumentedParameter:
+		return "undocumented parameter"
+	case FindingUndocumentedRequestContentType:
+		return "undocumented request content type"
+	case FindingUndocumentedStatus:
+		return "undocumented status"
+	case FindingUndocumentedResponseContentType:
+		return "undocumented response content type"
+	case FindingUndocumentedProperty:
+		return "undocumented property"
+	default:
+		return "unknown"
+	}
+}
+
+// Finding is traffic which is not described by the Document.
+type Finding struct {
+	Kind FindingKind
+	// Method is the method of the request.
+	Method string
+	// Path is the path of the matched Operation (e.g. "/pets/{petId}") or,
+	// for FindingUnknownPath and FindingUnknownMethod, the path of the
+	// request.
+	Path string
+	// OperationID is the operationId of the matched Operation, if any.
+	OperationID openapi.Text
+	// StatusCode is the status code of the response, for findings which
+	// concern responses.
+	StatusCode int
+	// Name is the name of the undocumented parameter, the undocumented
+	// content type, or the JSON pointer of the undocumented property within
+	// the response body (e.g. "/owner/nickname").
+	Name string
+	// Count is the number of times the finding was observed.
+	Count int
+}
+
+func (f Finding) String() string {
+	s := fmt.Sprintf("%s %s: %s", f.Method, f.Path, f.Kind)
+	if f.StatusCode != 0 {
+		s += fmt.Sprintf(" (status %d)", f.StatusCode)
+	}
+	if f.Name != "" {
+		s += fmt.Sprintf(" %q", f.Name)
+	}
+	return s
+}
+
+// DriftOptions configures a DriftDetector.
+type DriftOptions struct {
+	Options
+	// MaxBodySize is the maximum size of response bodies which are checked
+	// for undocumented properties. Defaults to 1 MiB.
+	MaxBodySize int
+}
+
+// DriftDetector records traffic which is not described by a Document, such
+// as requests to unknown paths, undocumented query parameters, undocumented
+// status codes and content types, and properties of JSON response bodies
+// which are not described by their Schema.
+//
+// A response property is considered undocumented if it is not declared by
+// the properties or patternProperties of the Schema, including those of the
+// Schemas it references or composes with allOf, anyOf, oneOf, then, or else,
+// as if the Schema had "unevaluatedProperties": false. Objects which allow
+// additional properties, or which do not declare any properties, are not
+// checked.
+//
+// A DriftDetector is safe for concurrent use.
+type DriftDetector struct {
+	router  *router
+	maxBody int
+
+	mu       sync.Mutex
+	findings []*Finding
+	index    map[findingKey]*Finding
+	shapes   map[*openapi.Schema]*shape
+}
+
+type findingKey struct {
+	kind   FindingKind
+	method string
+	path   string
+	status int
+	name   string
+}
+
+// NewDriftDetector returns a DriftDetector for the Operations of doc's
+// Paths.
+func NewDriftDetector(doc *openapi.Document, opts DriftOptions) (*DriftDetector, error) {
+	if doc == nil {
+		return nil, fmt.Errorf("coverage: cannot detect drift against a nil Document")
+	}
+	rt, err := newRouter(doc, opts.Options)
+	if err != nil {
+		return nil, err
+	}
+	if opts.MaxBodySize <= 0 {
+		opts.MaxBodySize = 1 << 20
+	}
+	return &DriftDetector{
+		router:  rt,
+		maxBody: opts.MaxBodySize,
+		index:   map[findingKey]*Finding{},
+		shapes:  map[*openapi.Schema]*shape{},
+	}, nil
+}
+
+// Findings returns the findings recorded so far, in the order they were
+// first observed.
+func (d *DriftDetector) Findings() []Finding {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	res := make([]Finding, len(d.findings))
+	for i, f := range d.findings {
+		res[i] = *f
+	}
+	return res
+}
+
+// Reset discards all recorded findings.
+func (d *DriftDetector) Reset() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.findings = nil
+	d.index = map[findingKey]*Finding{}
+}
+
+func (d *DriftDetector) add(f Finding) {
+	k := findingKey{kind: f.Kind, method: f.Method, path: f.Path, status: f.StatusCode, name: f.Name}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if existing, ok := d.index[k]; ok {
+		existing.Count++
+		return
+	}
+	f.Count = 1
+	d.findings = append(d.findings, &f)
+	d.index[k] = &f
+}
+
+// Record checks a request and its response against the Document. body is the
+// body of the response; if it is nil, properties of the response are not
+// checked.
+func (d *DriftDetector) Record(req *http.Request, statusCode int, header http.Header, body []byte) {
+	e, ok, pathFound := d.router.match(req.Method, req.URL.Path)
+	if !ok {
+		kind := FindingUnknownPath
+		if pathFound {
+			kind = FindingUnknownMethod
+		}
+		d.add(Finding{Kind: kind, Method: req.Method, Path: req.URL.Path})
+		return
+	}
+	base := Finding{Method: req.Method, Path: e.Key.String(), OperationID: e.Operation.OperationID}
+	with := func(kind FindingKind, status int, name string) Finding {
+		f := base
+		f.Kind, f.StatusCode, f.Name = kind, status, name
+		return f
+	}
+
+	for _, name := range undocumentedQueryParameters(e, req) {
+		d.add(with(FindingUndocumentedParameter, 0, name))
+	}
+
+	if ct := req.Header.Get("Content-Type"); ct != "" {
+		var content *openapi.ContentMap
+		if rb := e.Operation.RequestBody; rb != nil && rb.Object != nil {
+			content = rb.Object.Content
+		}
+		if _, _, ok := openapi.MatchContent(content, ct); !ok {
+			d.add(with(FindingUndocumentedRequestContentType, 0, ct))
+		}
+	}
+
+	key, ok := responseKey(e.Operation.Responses, statusCode)
+	if !ok {
+		d.add(with(FindingUndocumentedStatus, statusCode, ""))
+		return
+	}
+	comp := e.Operation.Responses.Get(key)
+	if comp == nil || comp.Object == nil || comp.Object.Content == nil || len(comp.Object.Content.Items) == 0 {
+		return
+	}
+	ct := header.Get("Content-Type")
+	if ct == "" {
+		return
+	}
+	_, mt, ok := openapi.MatchContent(comp.Object.Content, ct)
+	if !ok {
+		d.add(with(FindingUndocumentedResponseContentType, statusCode, ct))
+		return
+	}
+	if mt.Schema == nil || len(body) == 0 || !isJSON(ct) {
+		return
+	}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return
+	}
+	for _, ptr := range d.undocumentedProperties(mt.Schema, v, "") {
+		d.add(with(FindingUndocumentedProperty, statusCode, ptr))
+	}
+}
+
+// Transport returns an http.RoundTripper which records the requests sent
+// through next. If next is nil, http.DefaultTransport is used.
+//
+// Response bodies no larger than MaxBodySize are read in order to be checked
+// and replaced with an equivalent reader.
+func (d *DriftDetector) Transport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return driftRoundTripper{detector: d, next: next}
+}
+
+type driftRoundTripper struct {
+	detector *DriftDetector
+	next     http.RoundTripper
+}
+
+func (rt driftRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	res, err := rt.next.RoundTrip(req)
+	if err != nil {
+		return res, err
+	}
+	var body []byte
+	if res.Body != nil && res.ContentLength <= int64(rt.detector.maxBody) {
+		body, err = io.ReadAll(io.LimitReader(res.Body, int64(rt.detector.maxBody)+1))
+		rest := res.Body
+		res.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(body), rest), Closer: rest}
+		if err != nil || len(body) > rt.detector.maxBody {
+			body = nil
+		}
+	}
+	rt.detector.Record(req, res.StatusCode, res.Header, body)
+	return res, nil
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// Middleware returns an http.Handler which records the requests handled by
+// next.
+func (d *DriftDetector) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		sw := &statusWriter{ResponseWriter: w, limit: d.maxBody}
+		next.ServeHTTP(sw, req)
+		if sw.status == 0 {
+			sw.status = http.StatusOK
+		}
+		d.Record(req, sw.status, sw.Header(), sw.body)
+	})
+}
+
+// undocumentedQueryParameters returns the names of the query parameters of
+// req which are not Parameters of the Operation e, sorted
+func undocumentedQueryParameters(e openapi.OperationEntry, req *http.Request) []string {
+	params, err := e.Operation.EffectiveParameters(e.PathItem)
+	if err != nil {
+		return nil
+	}
+	declared := map[string]bool{}
+	for _, p := range params {
+		if p.In != openapi.InQuery {
+			continue
+		}
+		if style, explode := p.EffectiveStyle(); p.Schema != nil && p.Schema.View().Type().ContainsObject() &&
+			style == openapi.StyleForm && explode {
+			// the properties of exploded form objects are serialized as
+			// individual query parameters and can not be distinguished
+			return nil
+		}
+		declared[p.Name.String()] = true
+	}
+	var res []string
+	for name := range req.URL.Query() {
+		n := name
+		if i := strings.IndexByte(n, '['); i > 0 {
+			// deepObject (e.g. "filter[status]")
+			n = n[:i]
+		}
+		if !declared[n] {
+			res = append(res, name)
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+// shape is the set of properties described for an object by a Schema
+type shape struct {
+	props    map[string]*openapi.Schema
+	patterns []*regexp.Regexp
+	pschemas []*openapi.Schema
+	open     bool
+}
+
+func (d *DriftDetector) shape(s *openapi.Schema) *shape {
+	d.mu.Lock()
+	sh, ok := d.shapes[s]
+	d.mu.Unlock()
+	if ok {
+		return sh
+	}
+	sh = &shape{props: map[string]*openapi.Schema{}}
+	sh.collect(s, map[*openapi.Schema]bool{})
+	if len(sh.props) == 0 && len(sh.patterns) == 0 {
+		sh.open = true
+	}
+	d.mu.Lock()
+	d.shapes[s] = sh
+	d.mu.Unlock()
+	return sh
+}
+
+func (sh *shape) collect(s *openapi.Schema, seen map[*openapi.Schema]bool) {
+	if s == nil || seen[s] {
+		return
+	}
+	seen[s] = true
+	if s.Properties != nil {
+		for _, item := range s.Properties.Items {
+			if _, ok := sh.props[item.Key.String()]; !ok {
+				sh.props[item.Key.String()] = item.Schema
+			}
+		}
+	}
+	if s.PatternProperties != nil {
+		for _, item := range s.PatternProperties.Items {
+			re, err := regexp.Compile(item.Key.String())
+			if err != nil {
+				// a pattern which can not be evaluated may match anything
+				sh.open = true
+				continue
+			}
+			sh.patterns = append(sh.patterns, re)
+			sh.pschemas = append(sh.pschemas, item.Schema)
+		}
+	}
+	if (s.AdditionalProperties != nil && !s.AdditionalProperties.IsNever()) ||
+		(s.UnevaluatedProperties != nil && !s.UnevaluatedProperties.IsNever()) {
+		sh.open = true
+	}
+	for _, r := range []*openapi.SchemaRef{s.Ref, s.DynamicRef, s.RecursiveRef} {
+		if r != nil {
+			sh.collect(r.Resolved, seen)
+		}
+	}
+	for _, ss := range []*openapi.SchemaSlice{s.AllOf, s.AnyOf, s.OneOf} {
+		if ss == nil {
+			continue
+		}
+		for _, x := range ss.Items {
+			sh.collect(x, seen)
+		}
+	}
+	sh.collect(s.Then, seen)
+	sh.collect(s.Else, seen)
+}
+
+// undocumentedProperties returns the JSON pointers of the properties of v
+// which are not described by s
+func (d *DriftDetector) undocumentedProperties(s *openapi.Schema, v interface{}, ptr string) []string {
+	if s == nil {
+		return nil
+	}
+	var res []string
+	switch x := v.(type) {
+	case map[string]interface{}:
+		sh := d.shape(s)
+		keys := make([]string, 0, len(x))
+		for k := range x {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			p := ptr + "/" + pointerEscaper.Replace(k)
+			ps, ok := sh.props[k]
+			if !ok {
+				for i, re := range sh.patterns {
+					if re.MatchString(k) {
+						ps, ok = sh.pschemas[i], true
+						break
+					}
+				}
+			}
+			if !ok {
+				if !sh.open {
+					res = append(res, p)
+				}
+				continue
+			}
+			res = append(res, d.undocumentedProperties(ps, x[k], p)...)
+		}
+	case []interface{}:
+		prefix, items := arraySchemas(s)
+		for i, item := range x {
+			is := items
+			if i < len(prefix) {
+				is = prefix[i]
+			}
+			res = append(res, d.undocumentedProperties(is, item, fmt.Sprintf("%s/%d", ptr, i))...)
+		}
+	}
+	return res
+}
+
+// arraySchemas returns the prefixItems and items of s, following its $ref
+// and allOf
+func arraySchemas(s *openapi.Schema) ([]*openapi.Schema, *openapi.Schema) {
+	seen := map[*openapi.Schema]bool{}
+	var prefix []*openapi.Schema
+	var items *openapi.Schema
+	var visit func(s *openapi.Schema)
+	visit = func(s *openapi.Schema) {
+		if s == nil || seen[s] {
+			return
+		}
+		seen[s] = true
+		if prefix == nil && s.PrefixItems != nil {
+			prefix = s.PrefixItems.Items
+		}
+		if items == nil && s.Items != nil {
+			items = s.Items
+		}
+		if s.Ref != nil {
+			visit(s.Ref.Resolved)
+		}
+		if s.AllOf != nil {
+			for _, x := range s.AllOf.Items {
+				visit(x)
+			}
+		}
+	}
+	visit(s)
+	return prefix, items
+}
+
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+func isJSON(contentType string) bool {
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mt == "application/json" || strings.HasSuffix(mt, "+json")
+}
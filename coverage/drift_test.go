@@ -0,0 +1,134 @@
+package coverage_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/chanced/openapi"
+	"github.com/chanced/openapi/coverage"
+	"github.com/chanced/uri"
+)
+
+func TestDriftDetector(t *testing.T) {
+	data := []byte(`{
+		"openapi": "3.1.0",
+		"info": { "title": "Pet Store", "version": "1.0.0" },
+		"paths": {
+			"/pets/{petId}": {
+				"get": {
+					"operationId": "getPet",
+					"parameters": [
+						{ "name": "petId", "in": "path", "required": true, "schema": { "type": "integer" } },
+						{ "name": "filter", "in": "query", "style": "deepObject", "schema": { "type": "object" } },
+						{ "name": "fields", "in": "query", "schema": { "type": "string" } }
+					],
+					"responses": {
+						"200": {
+							"description": "ok",
+							"content": {
+								"application/json": { "schema": { "$ref": "#/components/schemas/Pet" } }
+							}
+						}
+					}
+				}
+			}
+		},
+		"components": {
+			"schemas": {
+				"Pet": {
+					"allOf": [{ "$ref": "#/components/schemas/Named" }],
+					"properties": {
+						"owner": {
+							"type": "object",
+							"properties": { "name": { "type": "string" } }
+						},
+						"tags": {
+							"type": "array",
+							"items": { "type": "object", "properties": { "id": { "type": "integer" } } }
+						},
+						"meta": { "type": "object", "additionalProperties": true }
+					},
+					"patternProperties": { "^x-": {} }
+				},
+				"Named": {
+					"properties": { "name": { "type": "string" } }
+				}
+			}
+		}
+	}`)
+	fn := func(ctx context.Context, u uri.URI, kind openapi.Kind) (openapi.Kind, []byte, error) {
+		return openapi.KindDocument, data, nil
+	}
+	v, err := openapi.NewOfflineValidator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := openapi.Load(context.Background(), "https://example.com/openapi.json", v, fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := coverage.NewDriftDetector(doc, coverage.DriftOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(d.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/pets/0" {
+			w.WriteHeader(http.StatusTeapot)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{
+			"name": "Fido",
+			"x-internal": 1,
+			"color": "brown",
+			"owner": { "name": "Jo", "nickname": "J" },
+			"tags": [{ "id": 1 }, { "id": 2, "label": "b" }],
+			"meta": { "anything": true }
+		}`)
+	})))
+	defer srv.Close()
+
+	get := func(path string) {
+		res, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+	}
+	get("/pets/1?fields=name&filter[status]=sold&debug=true")
+	get("/pets/1")
+	get("/pets/0")
+	get("/owners")
+	res, err := http.Post(srv.URL+"/pets/1", "text/plain", strings.NewReader("x"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+
+	var got []string
+	for _, f := range d.Findings() {
+		got = append(got, f.String())
+	}
+	expected := []string{
+		`GET /pets/{petId}: undocumented parameter "debug"`,
+		`GET /pets/{petId}: undocumented property (status 200) "/color"`,
+		`GET /pets/{petId}: undocumented property (status 200) "/owner/nickname"`,
+		`GET /pets/{petId}: undocumented property (status 200) "/tags/1/label"`,
+		`GET /pets/{petId}: undocumented status (status 418)`,
+		`GET /owners: unknown path`,
+		`POST /pets/1: unknown method`,
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected findings:\n\t%s\ngot:\n\t%s", strings.Join(expected, "\n\t"), strings.Join(got, "\n\t"))
+	}
+	for _, f := range d.Findings() {
+		if f.Kind == coverage.FindingUndocumentedProperty && f.Name == "/color" && f.Count != 2 {
+			t.Errorf("expected /color to be observed twice, got %d", f.Count)
+		}
+	}
+}
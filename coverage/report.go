@@ -0,0 +1,220 @@
+package coverage
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/chanced/openapi"
+)
+
+// Report describes the coverage of a Document by recorded traffic.
+type Report struct {
+	// Operations are the Operations of the Document, in the order of
+	// Document.Operations.
+	Operations []OperationCoverage
+	// Unmatched are the requests which did not match an Operation.
+	Unmatched []Request
+}
+
+// OperationCoverage is the coverage of an Operation.
+type OperationCoverage struct {
+	OperationID openapi.Text
+	Method      openapi.Text
+	Path        openapi.Text
+	// Calls is the number of requests matched to the Operation.
+	Calls int
+	// Responses are the Responses of the Operation, in the order they are
+	// declared.
+	Responses []ResponseCoverage
+	// UndocumentedStatuses are the number of responses, by status code, which
+	// did not match a Response of the Operation.
+	UndocumentedStatuses map[int]int
+}
+
+// ResponseCoverage is the coverage of a Response of an Operation.
+type ResponseCoverage struct {
+	// Key is the key of the Response (e.g. "200", "4XX", or "default").
+	Key openapi.Text
+	// Calls is the number of responses matched to the Response.
+	Calls int
+	// MediaTypes are the media types of the Response, in the order they are
+	// declared.
+	MediaTypes []MediaTypeCoverage
+	// UndocumentedContentTypes are the number of responses, by Content-Type,
+	// which did not match a media type of the Response.
+	UndocumentedContentTypes map[string]int
+}
+
+// MediaTypeCoverage is the coverage of a media type of a Response.
+type MediaTypeCoverage struct {
+	// ContentType is the key of the media type (e.g. "application/json").
+	ContentType openapi.Text
+	// Calls is the number of responses matched to the media type.
+	Calls int
+}
+
+// Stats are the totals of a Report.
+type Stats struct {
+	Operations        int
+	CoveredOperations int
+	Responses         int
+	CoveredResponses  int
+	MediaTypes        int
+	CoveredMediaTypes int
+}
+
+// Ratio returns the ratio, from 0 to 1, of Operations, Responses, and media
+// types which are covered. If there is nothing to cover, 1 is returned.
+func (s Stats) Ratio() float64 {
+	total := s.Operations + s.Responses + s.MediaTypes
+	if total == 0 {
+		return 1
+	}
+	return float64(s.CoveredOperations+s.CoveredResponses+s.CoveredMediaTypes) / float64(total)
+}
+
+// Report returns a Report of the traffic recorded so far.
+func (r *Recorder) Report() *Report {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	byOp := map[*openapi.Operation][]hit{}
+	for h := range r.hits {
+		byOp[h.op] = append(byOp[h.op], h)
+	}
+	report := &Report{Unmatched: append([]Request(nil), r.unmatched...)}
+	for _, e := range r.doc.Operations() {
+		if e.Webhook {
+			continue
+		}
+		oc := OperationCoverage{
+			OperationID:          e.Operation.OperationID,
+			Method:               e.Method,
+			Path:                 e.Key,
+			UndocumentedStatuses: map[int]int{},
+		}
+		responses := e.Operation.Responses
+		idx := map[openapi.Text]int{}
+		if responses != nil {
+			for _, item := range responses.Items {
+				rc := ResponseCoverage{Key: item.Key, UndocumentedContentTypes: map[string]int{}}
+				if item.Component != nil && item.Component.Object != nil && item.Component.Object.Content != nil {
+					for _, mt := range item.Component.Object.Content.Items {
+						rc.MediaTypes = append(rc.MediaTypes, MediaTypeCoverage{ContentType: mt.Key})
+					}
+				}
+				idx[item.Key] = len(oc.Responses)
+				oc.Responses = append(oc.Responses, rc)
+			}
+		}
+		for _, h := range byOp[e.Operation] {
+			n := r.hits[h]
+			oc.Calls += n
+			key, ok := responseKey(responses, h.status)
+			if !ok {
+				oc.UndocumentedStatuses[h.status] += n
+				continue
+			}
+			rc := &oc.Responses[idx[key]]
+			rc.Calls += n
+			if len(rc.MediaTypes) == 0 || h.contentType == "" {
+				continue
+			}
+			comp := responses.Get(key)
+			mtKey, _, ok := openapi.MatchContent(comp.Object.Content, h.contentType)
+			if !ok {
+				rc.UndocumentedContentTypes[h.contentType] += n
+				continue
+			}
+			for i := range rc.MediaTypes {
+				if rc.MediaTypes[i].ContentType == mtKey {
+					rc.MediaTypes[i].Calls += n
+				}
+			}
+		}
+		report.Operations = append(report.Operations, oc)
+	}
+	return report
+}
+
+// Stats returns the totals of the Report.
+func (r *Report) Stats() Stats {
+	var s Stats
+	for _, oc := range r.Operations {
+		s.Operations++
+		if oc.Calls > 0 {
+			s.CoveredOperations++
+		}
+		for _, rc := range oc.Responses {
+			s.Responses++
+			if rc.Calls > 0 {
+				s.CoveredResponses++
+			}
+			for _, mt := range rc.MediaTypes {
+				s.MediaTypes++
+				if mt.Calls > 0 {
+					s.CoveredMediaTypes++
+				}
+			}
+		}
+	}
+	return s
+}
+
+// Uncovered returns descriptions of the Operations, Responses, and media
+// types which were not exercised (e.g. "GET /pets/{petId} 404" or
+// "GET /pets 200 application/xml").
+func (r *Report) Uncovered() []string {
+	var res []string
+	for _, oc := range r.Operations {
+		op := fmt.Sprintf("%s %s", oc.Method, oc.Path)
+		if oc.Calls == 0 {
+			res = append(res, op)
+			continue
+		}
+		for _, rc := range oc.Responses {
+			if rc.Calls == 0 {
+				res = append(res, fmt.Sprintf("%s %s", op, rc.Key))
+				continue
+			}
+			for _, mt := range rc.MediaTypes {
+				if mt.Calls == 0 {
+					res = append(res, fmt.Sprintf("%s %s %s", op, rc.Key, mt.ContentType))
+				}
+			}
+		}
+	}
+	return res
+}
+
+// Undocumented returns descriptions of the recorded traffic which was not
+// documented: requests which did not match an Operation, status codes which
+// did not match a Response, and content types which did not match a media
+// type.
+func (r *Report) Undocumented() []string {
+	var res []string
+	for _, req := range r.Unmatched {
+		res = append(res, fmt.Sprintf("%s %s", req.Method, req.Path))
+	}
+	for _, oc := range r.Operations {
+		op := fmt.Sprintf("%s %s", oc.Method, oc.Path)
+		codes := make([]int, 0, len(oc.UndocumentedStatuses))
+		for code := range oc.UndocumentedStatuses {
+			codes = append(codes, code)
+		}
+		sort.Ints(codes)
+		for _, code := range codes {
+			res = append(res, fmt.Sprintf("%s %d", op, code))
+		}
+		for _, rc := range oc.Responses {
+			cts := make([]string, 0, len(rc.UndocumentedContentTypes))
+			for ct := range rc.UndocumentedContentTypes {
+				cts = append(cts, ct)
+			}
+			sort.Strings(cts)
+			for _, ct := range cts {
+				res = append(res, fmt.Sprintf("%s %s %s", op, rc.Key, ct))
+			}
+		}
+	}
+	return res
+}
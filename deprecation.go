@@ -0,0 +1,197 @@
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/chanced/uri"
+)
+
+// ExtensionSunset is the extension which holds the date after which a
+// deprecated node may be removed (e.g. "2025-01-31"). See RFC 8594.
+const ExtensionSunset Text = "x-sunset"
+
+// sunsetFormats are the formats accepted for the value of ExtensionSunset
+var sunsetFormats = []string{"2006-01-02", time.RFC3339, http.TimeFormat}
+
+// Deprecation is a deprecated node of a Document.
+type Deprecation struct {
+	Node     Node
+	Kind     Kind
+	Location uri.URI
+	// Sunset is the time after which the node may be removed, as indicated
+	// by its ExtensionSunset extension. Sunset is the zero time if the node
+	// does not have a sunset or the value could not be parsed.
+	Sunset time.Time
+	// Causes are the locations of the deprecated nodes which a node was
+	// deprecated for by PropagateDeprecations.
+	Causes []uri.URI
+}
+
+// Deprecate marks n, which must be a *Schema, *Operation, *Parameter, or
+// *Header, as deprecated. If sunset is not the zero time, it is set as the
+// ExtensionSunset extension of n.
+func Deprecate(n Node, sunset time.Time) error {
+	t := true
+	switch v := n.(type) {
+	case *Schema:
+		v.Deprecated = &t
+	case *Operation:
+		v.Deprecated = true
+	case *Parameter:
+		v.Deprecated = true
+	case *Header:
+		v.Deprecated = &t
+	default:
+		return fmt.Errorf("openapi: %s can not be deprecated", n.Kind())
+	}
+	if sunset.IsZero() {
+		return nil
+	}
+	return SetExtension(n, ExtensionSunset, formatSunset(sunset))
+}
+
+// IsDeprecated reports whether n is a deprecated *Schema, *Operation,
+// *Parameter, or *Header.
+func IsDeprecated(n Node) bool {
+	switch v := n.(type) {
+	case *Schema:
+		return v != nil && v.Deprecated != nil && *v.Deprecated
+	case *Operation:
+		return v != nil && v.Deprecated
+	case *Parameter:
+		return v != nil && v.Deprecated
+	case *Header:
+		return v != nil && v.Deprecated != nil && *v.Deprecated
+	}
+	return false
+}
+
+// Sunset returns the value of the ExtensionSunset extension of n, if
+// present and valid.
+func Sunset(n Node) (time.Time, bool) {
+	v, err := GetExtension[string](n, ExtensionSunset)
+	if err != nil {
+		return time.Time{}, false
+	}
+	for _, f := range sunsetFormats {
+		if t, err := time.Parse(f, v); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func formatSunset(t time.Time) string {
+	t = t.UTC()
+	if t.Equal(t.Truncate(24 * time.Hour)) {
+		return t.Format("2006-01-02")
+	}
+	return t.Format(time.RFC3339)
+}
+
+func newDeprecation(n Node) Deprecation {
+	d := Deprecation{Node: n, Kind: n.Kind(), Location: n.AbsoluteLocation()}
+	d.Sunset, _ = Sunset(n)
+	return d
+}
+
+// Deprecations returns each deprecated Schema, Operation, Parameter, and
+// Header of the Document, including those of referenced resources, in the
+// order they are encountered.
+func (d *Document) Deprecations() []Deprecation {
+	var res []Deprecation
+	_ = walkNodes(d, func(n node) error {
+		if IsDeprecated(n) {
+			res = append(res, newDeprecation(n))
+		}
+		return nil
+	})
+	return res
+}
+
+// PropagateDeprecations marks each Operation of the Document which depends
+// upon a deprecated node as deprecated. An Operation depends upon:
+//
+//   - its deprecated Parameters or those of its PathItem
+//   - the Schemas of its Parameters and request body, including those they
+//     reference and the items of arrays
+//
+// Deprecated properties of a Schema do not cause the Operation to be
+// deprecated. If the Operation does not have a sunset, it inherits the
+// earliest sunset of its causes.
+//
+// The Operations which were marked deprecated are returned.
+func (d *Document) PropagateDeprecations() ([]Deprecation, error) {
+	var res []Deprecation
+	for _, op := range d.Operations() {
+		o := op.Operation
+		if o.Deprecated {
+			continue
+		}
+		var causes []Node
+		params, err := o.EffectiveParameters(op.PathItem)
+		if err != nil {
+			return res, err
+		}
+		for _, p := range params {
+			if p.Deprecated {
+				causes = append(causes, p)
+			} else if s := deprecatedSchema(p.Schema); s != nil {
+				causes = append(causes, s)
+			}
+		}
+		if rb := o.RequestBody; rb != nil && rb.Object != nil && rb.Object.Content != nil {
+			for _, item := range rb.Object.Content.Items {
+				if item.Value == nil {
+					continue
+				}
+				if s := deprecatedSchema(item.Value.Schema); s != nil {
+					causes = append(causes, s)
+				}
+			}
+		}
+		if len(causes) == 0 {
+			continue
+		}
+		var sunset time.Time
+		if _, ok := Sunset(o); !ok {
+			for _, c := range causes {
+				if t, ok := Sunset(c); ok && (sunset.IsZero() || t.Before(sunset)) {
+					sunset = t
+				}
+			}
+		}
+		if err = Deprecate(o, sunset); err != nil {
+			return res, err
+		}
+		dep := newDeprecation(o)
+		for _, c := range causes {
+			dep.Causes = append(dep.Causes, c.AbsoluteLocation())
+		}
+		res = append(res, dep)
+	}
+	return res, nil
+}
+
+// deprecatedSchema returns the first deprecated Schema of s, the Schemas it
+// references, or the items of arrays
+func deprecatedSchema(s *Schema) *Schema {
+	seen := map[*Schema]bool{}
+	for s != nil && !seen[s] {
+		seen[s] = true
+		if IsDeprecated(s) {
+			return s
+		}
+		switch {
+		case s.Ref != nil:
+			s = s.Ref.Resolved
+		case s.Items != nil:
+			s = s.Items
+		default:
+			s = nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package openapi_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chanced/openapi"
+)
+
+func TestDeprecations(t *testing.T) {
+	data := []byte(`{
+		"openapi": "3.1.0",
+		"info": { "title": "deprecations", "version": "1.0.0" },
+		"paths": {
+			"/pets": {
+				"post": {
+					"requestBody": {
+						"content": {
+							"application/json": { "schema": { "$ref": "#/components/schemas/Pet" } }
+						}
+					},
+					"responses": { "201": { "description": "created" } }
+				},
+				"get": {
+					"parameters": [{ "name": "legacy", "in": "query", "deprecated": true, "schema": { "type": "string" } }],
+					"responses": { "200": { "description": "ok" } }
+				}
+			},
+			"/owners": {
+				"get": {
+					"parameters": [{ "name": "q", "in": "query", "schema": { "$ref": "#/components/schemas/Owner" } }],
+					"responses": { "200": { "description": "ok" } }
+				}
+			}
+		},
+		"components": {
+			"schemas": {
+				"Pet": { "type": "object", "deprecated": true, "x-sunset": "2030-01-31" },
+				"Owner": {
+					"type": "object",
+					"properties": { "nickname": { "type": "string", "deprecated": true } }
+				}
+			}
+		}
+	}`)
+	doc := loadDocument(t, "https://example.com/openapi.json", data)
+
+	if deps := doc.Deprecations(); len(deps) != 3 {
+		t.Fatalf("expected 3 deprecations, got %+v", deps)
+	}
+
+	propagated, err := doc.PropagateDeprecations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(propagated) != 2 {
+		t.Fatalf("expected 2 operations to be deprecated, got %+v", propagated)
+	}
+	post := doc.Paths.Get("/pets").Post
+	if !post.Deprecated {
+		t.Error("expected POST /pets to be deprecated")
+	}
+	if doc.Paths.Get("/owners").Get.Deprecated {
+		t.Error("expected GET /owners to not be deprecated by a deprecated property")
+	}
+	for _, d := range propagated {
+		if d.Node != post {
+			continue
+		}
+		if len(d.Causes) != 1 || d.Causes[0].Fragment != "/components/schemas/Pet" {
+			t.Errorf("unexpected causes: %v", d.Causes)
+		}
+		if expected := time.Date(2030, 1, 31, 0, 0, 0, 0, time.UTC); !d.Sunset.Equal(expected) {
+			t.Errorf("expected sunset %v, got %v", expected, d.Sunset)
+		}
+	}
+
+	owner := doc.Components.Schemas.Get("Owner")
+	sunset := time.Date(2031, 6, 1, 0, 0, 0, 0, time.UTC)
+	if err = openapi.Deprecate(owner, sunset); err != nil {
+		t.Fatal(err)
+	}
+	if !openapi.IsDeprecated(owner) {
+		t.Error("expected Owner to be deprecated")
+	}
+	if s, ok := openapi.Sunset(owner); !ok || !s.Equal(sunset) {
+		t.Errorf("expected sunset %v, got %v", sunset, s)
+	}
+	if err = openapi.Deprecate(doc.Info, time.Time{}); err == nil {
+		t.Error("expected an error deprecating Info")
+	}
+	if deps := doc.Deprecations(); len(deps) != 6 {
+		t.Errorf("expected 6 deprecations, got %d", len(deps))
+	}
+}
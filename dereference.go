@@ -0,0 +1,175 @@
+package openapi
+
+import "errors"
+
+// errStopWalk stops a walk of nodes early
+var errStopWalk = errors.New("stop walk")
+
+// DereferenceOpts configures Document.Dereference.
+type DereferenceOpts struct {
+	// ApplyOverrides applies the summary and description of each Reference
+	// which overrides them onto a copy of the referenced object, which is
+	// inlined in place of the Reference. Otherwise, the overrides are
+	// discarded.
+	ApplyOverrides bool
+}
+
+// Dereference replaces each resolved Reference of the Document's Components
+// (e.g. Responses, Parameters, and PathItems) with the object it references.
+// Inlined objects are shared by each site which referenced them, retaining
+// their Locations.
+//
+// References to Schemas are not dereferenced, nor are references which are
+// unresolved or which, directly or indirectly, reference the site of the
+// reference itself. References within resources other than the Document
+// remain unless they are reached through the Document's Components.
+func (d *Document) Dereference(opts DereferenceOpts) error {
+	if d == nil {
+		return nil
+	}
+	var components []dereferencer
+	err := walkLocalNodes(d, func(n node) error {
+		if c, ok := n.(dereferencer); ok && c.IsReference() && c.IsResolved() {
+			components = append(components, c)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, c := range components {
+		if c.cyclic() {
+			continue
+		}
+		c.dereference(opts.ApplyOverrides)
+	}
+	return nil
+}
+
+type dereferencer interface {
+	node
+	IsReference() bool
+	IsResolved() bool
+	cyclic() bool
+	dereference(applyOverrides bool)
+}
+
+// cyclic reports whether the object referenced by c leads back to c
+func (c *Component[T]) cyclic() bool {
+	var found bool
+	_ = walkNodes(c.Object, func(n node) error {
+		if n == node(c) {
+			found = true
+			return errStopWalk
+		}
+		return nil
+	})
+	return found
+}
+
+// dereference replaces the Reference of c with the referenced Object. If
+// applyOverrides is true, the Object is merged with the summary and
+// description overrides of the Reference (see Effective).
+func (c *Component[T]) dereference(applyOverrides bool) {
+	obj := c.Object
+	if applyOverrides {
+		obj = c.Effective()
+	}
+	c.Reference = nil
+	c.Object = obj
+}
+
+// objectSummary returns the summary of n and whether the type of n has a
+// summary
+func objectSummary(n interface{}) (Text, bool) {
+	switch v := n.(type) {
+	case *Example:
+		if v == nil {
+			return "", true
+		}
+		return v.Summary, true
+	case *PathItem:
+		if v == nil {
+			return "", true
+		}
+		return v.Summary, true
+	default:
+		return "", false
+	}
+}
+
+func setObjectSummary(n interface{}, s Text) {
+	switch v := n.(type) {
+	case *Example:
+		v.Summary = s
+	case *PathItem:
+		v.Summary = s
+	}
+}
+
+// objectDescription returns the description of n and whether the type of n
+// has a description
+func objectDescription(n interface{}) (Text, bool) {
+	var d *Text
+	switch v := n.(type) {
+	case *Example:
+		if v != nil {
+			d = &v.Description
+		}
+	case *PathItem:
+		if v != nil {
+			d = &v.Description
+		}
+	case *Response:
+		if v != nil {
+			d = &v.Description
+		}
+	case *Parameter:
+		if v != nil {
+			d = &v.Description
+		}
+	case *RequestBody:
+		if v != nil {
+			d = &v.Description
+		}
+	case *Header:
+		if v != nil {
+			d = &v.Description
+		}
+	case *Link:
+		if v != nil {
+			d = &v.Description
+		}
+	case *SecurityScheme:
+		if v != nil {
+			d = &v.Description
+		}
+	default:
+		return "", false
+	}
+	if d == nil {
+		return "", true
+	}
+	return *d, true
+}
+
+func setObjectDescription(n interface{}, d Text) {
+	switch v := n.(type) {
+	case *Example:
+		v.Description = d
+	case *PathItem:
+		v.Description = d
+	case *Response:
+		v.Description = d
+	case *Parameter:
+		v.Description = d
+	case *RequestBody:
+		v.Description = d
+	case *Header:
+		v.Description = d
+	case *Link:
+		v.Description = d
+	case *SecurityScheme:
+		v.Description = d
+	}
+}
@@ -0,0 +1,112 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/chanced/openapi"
+)
+
+var dereferenceData = []byte(`{
+	"openapi": "3.1.0",
+	"info": { "title": "Pet Store", "version": "1.0.0" },
+	"webhooks": {
+		"pets": {
+			"$ref": "#/components/pathItems/Pets",
+			"summary": "All the pets"
+		}
+	},
+	"components": {
+		"pathItems": {
+			"Pets": {
+				"summary": "Pets",
+				"get": {
+					"parameters": [{ "$ref": "#/components/parameters/Limit" }],
+					"responses": {
+						"200": { "$ref": "#/components/responses/Pets", "description": "The pets", "summary": "ignored" }
+					}
+				}
+			}
+		},
+		"parameters": {
+			"Limit": { "name": "limit", "in": "query", "description": "max items", "schema": { "type": "integer" } }
+		},
+		"responses": {
+			"Pets": {
+				"description": "A list of pets",
+				"content": { "application/json": { "schema": { "$ref": "#/components/schemas/Pet" } } }
+			}
+		},
+		"schemas": {
+			"Pet": { "type": "object" }
+		}
+	}
+}`)
+
+func loadDereferenceDocument(t *testing.T) *openapi.Document {
+	t.Helper()
+	doc := loadDocument(t, "https://example.com/openapi.json", dereferenceData)
+	return doc
+}
+
+func TestComponentEffectiveDescription(t *testing.T) {
+	doc := loadDereferenceDocument(t)
+	if s := doc.Webhooks.Get("pets").EffectiveSummary(); s != "All the pets" {
+		t.Errorf("expected the summary to be overridden, got %q", s)
+	}
+	res := doc.Components.PathItems.Get("Pets").Object.Get.Responses.Get("200")
+	if d := res.EffectiveDescription(); d != "The pets" {
+		t.Errorf("expected the description to be overridden, got %q", d)
+	}
+	if s := res.EffectiveSummary(); s != "" {
+		t.Errorf("expected summary to have no effect on a Response, got %q", s)
+	}
+	param := doc.Components.PathItems.Get("Pets").Object.Get.Parameters.Items[0]
+	if d := param.EffectiveDescription(); d != "max items" {
+		t.Errorf("expected the description of the Parameter, got %q", d)
+	}
+}
+
+func TestDereference(t *testing.T) {
+	doc := loadDereferenceDocument(t)
+	if err := doc.Dereference(openapi.DereferenceOpts{ApplyOverrides: true}); err != nil {
+		t.Fatal(err)
+	}
+	if doc.Webhooks.Get("pets").IsReference() {
+		t.Fatal("expected the pets webhook to be inlined")
+	}
+	pets := doc.Webhook("pets")
+	if pets.Summary != "All the pets" {
+		t.Errorf("expected the summary override to be applied, got %q", pets.Summary)
+	}
+	if doc.Components.PathItems.Get("Pets").Object.Summary != "Pets" {
+		t.Error("expected the component to be unchanged")
+	}
+	res := pets.Get.Responses.Get("200")
+	if res.IsReference() || res.Object.Description != "The pets" {
+		t.Errorf("expected the response to be inlined with its description overridden")
+	}
+	if doc.Components.Responses.Get("Pets").Object.Description != "A list of pets" {
+		t.Error("expected the response component to be unchanged")
+	}
+	schema := res.Object.Content.Get("application/json").Schema
+	if schema.Ref == nil {
+		t.Error("expected schema references to remain")
+	}
+	b, err := json.Marshal(doc.Webhooks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), "#/components/parameters") || strings.Contains(string(b), "#/components/responses") {
+		t.Errorf("expected references to be inlined: %s", b)
+	}
+
+	doc = loadDereferenceDocument(t)
+	if err := doc.Dereference(openapi.DereferenceOpts{}); err != nil {
+		t.Fatal(err)
+	}
+	if s := doc.Webhook("pets").Summary; s != "Pets" {
+		t.Errorf("expected the summary override to be discarded, got %q", s)
+	}
+}
@@ -0,0 +1,214 @@
+package openapi
+
+import (
+	"strings"
+
+	"github.com/chanced/uri"
+)
+
+// RefSemantics determines how a "$ref" keyword is evaluated in relation to
+// its sibling keywords.
+type RefSemantics uint8
+
+const (
+	// RefSemanticsApplicator indicates that "$ref" is an applicator and that
+	// sibling keywords are evaluated alongside the referenced schema. This is
+	// the behavior of JSON Schema 2019-09 and later.
+	RefSemanticsApplicator RefSemantics = iota
+	// RefSemanticsOverride indicates that sibling keywords of "$ref" are
+	// ignored. This is the behavior of JSON Schema draft-07 and earlier.
+	RefSemanticsOverride
+)
+
+// Dialect is a JSON Schema dialect which can be referenced by a Schema's
+// $schema or a Document's jsonSchemaDialect.
+type Dialect interface {
+	// MetaSchema is the URI of the meta-schema which identifies the dialect.
+	MetaSchema() uri.URI
+
+	// Base is the URI of the JSON Schema draft which the dialect extends. It
+	// is used to validate and compile schemas of the dialect and must be one
+	// of the following:
+	//   - JSON Schema 2020-12: "https://json-schema.org/draft/2020-12/schema"
+	//   - JSON Schema 2019-09: "https://json-schema.org/draft/2019-09/schema"
+	//   - JSON Schema 07: "http://json-schema.org/draft-07/schema#"
+	Base() uri.URI
+
+	// Keywords are the keywords defined by the dialect's vocabularies.
+	Keywords() []Text
+
+	// RefSemantics determines how "$ref" is evaluated
+	RefSemantics() RefSemantics
+}
+
+// StdDialect is an implementation of Dialect.
+type StdDialect struct {
+	ID      uri.URI
+	BaseURI uri.URI
+	// KeywordSet is the set of keywords defined by the dialect
+	KeywordSet []Text
+	Refs       RefSemantics
+}
+
+func (d StdDialect) MetaSchema() uri.URI        { return d.ID }
+func (d StdDialect) Base() uri.URI              { return d.BaseURI }
+func (d StdDialect) Keywords() []Text           { return d.KeywordSet }
+func (d StdDialect) RefSemantics() RefSemantics { return d.Refs }
+
+var _ Dialect = StdDialect{}
+
+var (
+	keywords202012 = []Text{
+		"$schema", "$id", "$anchor", "$dynamicAnchor", "$ref", "$dynamicRef",
+		"$defs", "$comment", "$vocabulary", "allOf", "anyOf", "oneOf", "not",
+		"if", "then", "else", "dependentSchemas", "prefixItems", "items",
+		"contains", "properties", "patternProperties", "additionalProperties",
+		"propertyNames", "unevaluatedItems", "unevaluatedProperties", "type",
+		"const", "enum", "multipleOf", "maximum", "exclusiveMaximum", "minimum",
+		"exclusiveMinimum", "maxLength", "minLength", "pattern", "maxItems",
+		"minItems", "uniqueItems", "maxContains", "minContains", "maxProperties",
+		"minProperties", "required", "dependentRequired", "title", "description",
+		"default", "deprecated", "readOnly", "writeOnly", "examples", "format",
+		"contentEncoding", "contentMediaType", "contentSchema",
+	}
+	keywords201909 = []Text{
+		"$schema", "$id", "$anchor", "$recursiveAnchor", "$ref", "$recursiveRef",
+		"$defs", "$comment", "$vocabulary", "allOf", "anyOf", "oneOf", "not",
+		"if", "then", "else", "dependentSchemas", "items", "additionalItems",
+		"contains", "properties", "patternProperties", "additionalProperties",
+		"propertyNames", "unevaluatedItems", "unevaluatedProperties", "type",
+		"const", "enum", "multipleOf", "maximum", "exclusiveMaximum", "minimum",
+		"exclusiveMinimum", "maxLength", "minLength", "pattern", "maxItems",
+		"minItems", "uniqueItems", "maxContains", "minContains", "maxProperties",
+		"minProperties", "required", "dependentRequired", "title", "description",
+		"default", "deprecated", "readOnly", "writeOnly", "examples", "format",
+		"contentEncoding", "contentMediaType", "contentSchema",
+	}
+	keywords07 = []Text{
+		"$schema", "$id", "$ref", "$comment", "definitions", "allOf", "anyOf",
+		"oneOf", "not", "if", "then", "else", "items", "additionalItems",
+		"contains", "properties", "patternProperties", "additionalProperties",
+		"dependencies", "propertyNames", "type", "const", "enum", "multipleOf",
+		"maximum", "exclusiveMaximum", "minimum", "exclusiveMinimum",
+		"maxLength", "minLength", "pattern", "maxItems", "minItems",
+		"uniqueItems", "maxProperties", "minProperties", "required", "title",
+		"description", "default", "readOnly", "writeOnly", "examples", "format",
+		"contentEncoding", "contentMediaType",
+	}
+)
+
+var (
+	// Dialect202012 is the Dialect for JSON Schema 2020-12
+	Dialect202012 = StdDialect{
+		ID:         JSONSchemaDialect202012,
+		BaseURI:    JSONSchemaDialect202012,
+		KeywordSet: keywords202012,
+		Refs:       RefSemanticsApplicator,
+	}
+	// Dialect201909 is the Dialect for JSON Schema 2019-09
+	Dialect201909 = StdDialect{
+		ID:         JSONSchemaDialect201909,
+		BaseURI:    JSONSchemaDialect201909,
+		KeywordSet: keywords201909,
+		Refs:       RefSemanticsApplicator,
+	}
+	// Dialect07 is the Dialect for JSON Schema draft-07
+	Dialect07 = StdDialect{
+		ID:         JSONSchemaDialect07,
+		BaseURI:    JSONSchemaDialect07,
+		KeywordSet: keywords07,
+		Refs:       RefSemanticsOverride,
+	}
+	// DialectOpenAPI31 is the OpenAPI 3.1 Schema Object dialect, which
+	// extends JSON Schema 2020-12 with the OAS base vocabulary.
+	DialectOpenAPI31 = StdDialect{
+		ID:         OpenAPI31Dialect,
+		BaseURI:    JSONSchemaDialect202012,
+		KeywordSet: append(append([]Text{}, keywords202012...), "discriminator", "xml", "externalDocs", "example"),
+		Refs:       RefSemanticsApplicator,
+	}
+)
+
+// DialectRegistry is a registry of Dialects, keyed by the URI of their
+// meta-schema.
+type DialectRegistry struct {
+	dialects map[string]Dialect
+}
+
+// NewDialectRegistry creates a new DialectRegistry with the following
+// Dialects registered in addition to dialects:
+//   - Dialect202012
+//   - Dialect201909
+//   - Dialect07
+//   - DialectOpenAPI31
+func NewDialectRegistry(dialects ...Dialect) *DialectRegistry {
+	dr := &DialectRegistry{dialects: map[string]Dialect{}}
+	dr.Register(Dialect202012)
+	dr.Register(Dialect201909)
+	dr.Register(Dialect07)
+	dr.Register(DialectOpenAPI31)
+	for _, d := range dialects {
+		dr.Register(d)
+	}
+	return dr
+}
+
+// Register adds or replaces d.
+func (dr *DialectRegistry) Register(d Dialect) {
+	if dr.dialects == nil {
+		dr.dialects = map[string]Dialect{}
+	}
+	dr.dialects[dialectKey(d.MetaSchema())] = d
+}
+
+// Lookup returns the Dialect identified by the meta-schema URI u. An empty
+// fragment as well as the scheme (http or https) are disregarded.
+func (dr *DialectRegistry) Lookup(u uri.URI) (Dialect, bool) {
+	if dr == nil {
+		return nil, false
+	}
+	d, ok := dr.dialects[dialectKey(u)]
+	return d, ok
+}
+
+// IsKeyword reports whether name is a keyword of the Dialect identified by
+// u.
+func (dr *DialectRegistry) IsKeyword(u uri.URI, name Text) bool {
+	d, ok := dr.Lookup(u)
+	if !ok {
+		return false
+	}
+	for _, kw := range d.Keywords() {
+		if kw == name {
+			return true
+		}
+	}
+	return false
+}
+
+// base returns the Base of the Dialect identified by u. If u is not
+// registered, u is returned.
+func (dr *DialectRegistry) base(u uri.URI) uri.URI {
+	if d, ok := dr.Lookup(u); ok {
+		return d.Base()
+	}
+	return u
+}
+
+// refSemantics returns the RefSemantics of the Dialect identified by u,
+// falling back to those of the base draft.
+func (dr *DialectRegistry) refSemantics(u uri.URI) RefSemantics {
+	if d, ok := dr.Lookup(u); ok {
+		return d.RefSemantics()
+	}
+	if dialectKey(u) == dialectKey(JSONSchemaDialect07) {
+		return RefSemanticsOverride
+	}
+	return RefSemanticsApplicator
+}
+
+func dialectKey(u uri.URI) string {
+	s := strings.TrimSuffix(strings.TrimSuffix(u.String(), "#"), "/")
+	s = strings.TrimPrefix(s, "https://")
+	return strings.TrimPrefix(s, "http://")
+}
@@ -0,0 +1,63 @@
+package openapi_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/chanced/openapi"
+	"github.com/chanced/uri"
+)
+
+func TestDialectRegistry(t *testing.T) {
+	internal := openapi.StdDialect{
+		ID:         *uri.MustParse("https://example.com/dialect"),
+		BaseURI:    openapi.JSONSchemaDialect07,
+		KeywordSet: []openapi.Text{"$ref", "type", "minLength"},
+		Refs:       openapi.RefSemanticsOverride,
+	}
+	dr := openapi.NewDialectRegistry(internal)
+	if _, ok := dr.Lookup(*uri.MustParse("https://json-schema.org/draft-07/schema")); !ok {
+		t.Error("expected draft-07 to be registered")
+	}
+	if !dr.IsKeyword(openapi.OpenAPI31Dialect, "discriminator") {
+		t.Error("expected discriminator to be a keyword of the OpenAPI 3.1 dialect")
+	}
+
+	data := []byte(`{
+		"openapi": "3.1.0",
+		"info": { "title": "dialects", "version": "1.0.0" },
+		"components": {
+			"schemas": {
+				"Str": { "type": "string" },
+				"Ref": {
+					"$schema": "https://example.com/dialect",
+					"$ref": "#/components/schemas/Str",
+					"minLength": 3
+				}
+			}
+		}
+	}`)
+	ctx := context.Background()
+	fn := func(ctx context.Context, uri uri.URI, kind openapi.Kind) (openapi.Kind, []byte, error) {
+		return openapi.KindDocument, data, nil
+	}
+	_, err := openapi.Load(ctx, "https://example.com/dialects.json", NoopValidator{}, fn, openapi.LoadOpts{Dialects: openapi.NewDialectRegistry()})
+	if !errors.Is(err, openapi.ErrUnsupportedDialect) {
+		t.Errorf("expected ErrUnsupportedDialect, got %v", err)
+	}
+	doc, err := openapi.Load(ctx, "https://example.com/dialects.json", NoopValidator{}, fn, openapi.LoadOpts{Dialects: dr})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cs, err := doc.Components.Schemas.Get("Ref").Compile(ctx, openapi.CompileOpts{Dialects: dr})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = cs.Validate("ab"); err != nil {
+		t.Errorf("expected siblings of $ref to be ignored, got %v", err)
+	}
+	if err = cs.Validate(float64(1)); err == nil {
+		t.Error("expected $ref to be applied")
+	}
+}
@@ -2,8 +2,10 @@ package openapi
 
 import (
 	"encoding/json"
+	"fmt"
+	"strings"
 
-	"github.com/chanced/transcode"
+	"github.com/chanced/uri"
 	"gopkg.in/yaml.v3"
 )
 
@@ -90,11 +92,7 @@ func (d Discriminator) MarshalYAML() (interface{}, error) {
 
 // UnmarshalYAML implements yaml.Unmarshaler
 func (d *Discriminator) UnmarshalYAML(value *yaml.Node) error {
-	v, err := yaml.Marshal(value)
-	if err != nil {
-		return err
-	}
-	j, err := transcode.JSONFromYAML(v)
+	j, err := jsonFromYAMLNode(value)
 	if err != nil {
 		return err
 	}
@@ -135,3 +133,217 @@ func (*Discriminator) mapKind() Kind   { return KindUndefined }
 func (*Discriminator) sliceKind() Kind { return KindUndefined }
 
 var _ node = (*Discriminator)(nil)
+
+// ResolveMapping returns each discriminator value paired with the Schema it
+// identifies within doc. owner is the Schema which d is the Discriminator of.
+//
+// Explicit entries of Mapping are resolved first and may either be the name
+// of a schema in doc's Components or a URI reference to a Schema. A URI is
+// resolved relative to the location of the Discriminator and must point to a
+// schema in doc's Components or to a Schema which was resolved while loading
+// doc.
+//
+// Schemas in doc's Components which are not already the target of an
+// explicit entry are then implicitly mapped by the name of the component, as
+// the value of the discriminator property may be the name of the component.
+// These are, in order, the components referenced by a branch of owner's oneOf
+// and anyOf, followed by the components which inherit from owner by
+// referencing it in their allOf. Inline branches and other components are not
+// mapped.
+//
+// An error wrapping ErrNotFound is returned if an explicit entry can not be
+// resolved.
+func (d *Discriminator) ResolveMapping(doc *Document, owner *Schema) (Map[*Schema], error) {
+	var res Map[*Schema]
+	if d == nil {
+		return res, nil
+	}
+	mapped := map[*Schema]struct{}{}
+	if d.Mapping != nil {
+		for _, kv := range d.Mapping.Items {
+			s, err := d.resolveMappingValue(doc, kv.Value)
+			if err != nil {
+				return Map[*Schema]{}, err
+			}
+			res.Set(kv.Key, s)
+			mapped[s] = struct{}{}
+		}
+	}
+	if doc == nil || doc.Components == nil || doc.Components.Schemas == nil || owner == nil {
+		return res, nil
+	}
+	implicit := func(name Text, s *Schema) {
+		if _, ok := mapped[s]; ok {
+			return
+		}
+		if res.Has(name) {
+			return
+		}
+		res.Set(name, s)
+	}
+	for _, ss := range []*SchemaSlice{owner.OneOf, owner.AnyOf} {
+		if ss == nil {
+			continue
+		}
+		for _, b := range ss.Items {
+			if name, s, ok := doc.componentSchemaOf(b); ok {
+				implicit(name, s)
+			}
+		}
+	}
+	for _, item := range doc.Components.Schemas.Items {
+		if item.Schema == nil || item.Schema == owner || item.Schema.AllOf == nil {
+			continue
+		}
+		for _, b := range item.Schema.AllOf.Items {
+			if doc.refTarget(b) == owner {
+				implicit(item.Key, item.Schema)
+				break
+			}
+		}
+	}
+	return res, nil
+}
+
+// componentSchemaOf returns the name and Schema of the component of d which
+// the branch b references, if any
+func (d *Document) componentSchemaOf(b *Schema) (Text, *Schema, bool) {
+	target := d.refTarget(b)
+	if target == nil {
+		return "", nil, false
+	}
+	for _, item := range d.Components.Schemas.Items {
+		if item.Schema == target {
+			return item.Key, target, true
+		}
+	}
+	return "", nil, false
+}
+
+// refTarget returns the Schema of d which b references, if any
+func (d *Document) refTarget(b *Schema) *Schema {
+	if b == nil || b.Ref == nil {
+		return nil
+	}
+	if b.Ref.Resolved != nil {
+		return b.Ref.Resolved
+	}
+	if b.Ref.Ref != nil {
+		return findSchema(d, b.Ref.AbsoluteLocation(), *b.Ref.Ref)
+	}
+	return nil
+}
+
+func (d *Discriminator) resolveMappingValue(doc *Document, v Text) (*Schema, error) {
+	if doc == nil {
+		return nil, NewError(fmt.Errorf("%w: %q", ErrNotFound, v), d.AbsoluteLocation())
+	}
+	if !v.Contains("/") && !v.Contains("#") {
+		// the value is the name of a component
+		if doc.Components != nil && doc.Components.Schemas != nil {
+			if s := doc.Components.Schemas.Get(v); s != nil {
+				return s, nil
+			}
+		}
+		return nil, NewError(fmt.Errorf("%w: %q", ErrNotFound, v), d.AbsoluteLocation())
+	}
+	u, err := uri.Parse(v.String())
+	if err != nil {
+		return nil, NewError(fmt.Errorf("openapi: failed to parse discriminator mapping %q: %w", v, err), d.AbsoluteLocation())
+	}
+	if s := findSchema(doc, d.AbsoluteLocation(), *u); s != nil {
+		return s, nil
+	}
+	return nil, NewError(fmt.Errorf("%w: %q", ErrNotFound, v), d.AbsoluteLocation())
+}
+
+// findSchema searches doc for a Schema identified by the reference u, resolved
+// relative to base.
+func findSchema(doc *Document, base uri.URI, u uri.URI) *Schema {
+	if doc == nil {
+		return nil
+	}
+	if u.Host == "" && u.Path == "" {
+		if name, ok := componentSchemaName(u.Fragment); ok {
+			if doc.Components != nil && doc.Components.Schemas != nil {
+				if s := doc.Components.Schemas.Get(name); s != nil {
+					return s
+				}
+			}
+		}
+	}
+	target := base.ResolveReference(&u).String()
+	if doc.Components != nil && doc.Components.Schemas != nil {
+		for _, item := range doc.Components.Schemas.Items {
+			if item.Schema.AbsoluteLocation().String() == target {
+				return item.Schema
+			}
+		}
+	}
+	for _, r := range doc.Refs() {
+		s, ok := r.ResolvedNode().(*Schema)
+		if !ok || s == nil {
+			continue
+		}
+		if s.AbsoluteLocation().String() == target {
+			return s
+		}
+	}
+	return nil
+}
+
+func componentSchemaName(fragment string) (Text, bool) {
+	const prefix = "/components/schemas/"
+	if !strings.HasPrefix(fragment, prefix) {
+		return "", false
+	}
+	name := strings.TrimPrefix(fragment, prefix)
+	if strings.Contains(name, "/") {
+		return "", false
+	}
+	name = strings.ReplaceAll(name, "~1", "/")
+	name = strings.ReplaceAll(name, "~0", "~")
+	return Text(name), true
+}
+
+// ValidateDiscriminator ensures that each branch of s's oneOf and anyOf is
+// reachable from the mapping of s's Discriminator, as resolved by
+// Discriminator.ResolveMapping.
+//
+// A branch is reachable if it, or the Schema it references, is the target of
+// a mapping entry. Inline branches can only be reached through an explicit
+// mapping entry.
+//
+// ValidateDiscriminator returns nil if s does not have a Discriminator.
+func (s *Schema) ValidateDiscriminator(doc *Document) error {
+	if s == nil || s.Discriminator == nil {
+		return nil
+	}
+	m, err := s.Discriminator.ResolveMapping(doc, s)
+	if err != nil {
+		return err
+	}
+	targets := make(map[*Schema]struct{}, len(m.Items))
+	for _, kv := range m.Items {
+		targets[kv.Value] = struct{}{}
+	}
+	reachable := func(b *Schema) bool {
+		if _, ok := targets[b]; ok {
+			return true
+		}
+		_, ok := targets[doc.refTarget(b)]
+		return ok
+	}
+	for _, ss := range []*SchemaSlice{s.OneOf, s.AnyOf} {
+		if ss == nil {
+			continue
+		}
+		for _, b := range ss.Items {
+			if b == nil || reachable(b) {
+				continue
+			}
+			return NewValidationError(fmt.Errorf("openapi: %s is not reachable from the discriminator mapping", b.AbsoluteLocation()), KindDiscriminator, s.Discriminator.AbsoluteLocation())
+		}
+	}
+	return nil
+}
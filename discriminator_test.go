@@ -1,5 +1,141 @@
 package openapi_test
 
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/chanced/openapi"
+)
+
+func TestDiscriminatorResolveMapping(t *testing.T) {
+	var doc openapi.Document
+	err := json.Unmarshal([]byte(`{
+		"openapi": "3.1.0",
+		"info": { "title": "pets", "version": "1.0.0" },
+		"components": {
+			"schemas": {
+				"Pet": {
+					"oneOf": [
+						{ "$ref": "#/components/schemas/Dog" },
+						{ "$ref": "#/components/schemas/Cat" }
+					],
+					"discriminator": {
+						"propertyName": "petType",
+						"mapping": { "dog": "#/components/schemas/Dog" }
+					}
+				},
+				"Dog": { "type": "object" },
+				"Cat": { "type": "object" },
+				"Error": { "type": "object" }
+			}
+		}
+	}`), &doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pet := doc.Components.Schemas.Get("Pet")
+	m, err := pet.Discriminator.ResolveMapping(&doc, pet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := m.Get("dog"); v != doc.Components.Schemas.Get("Dog") {
+		t.Errorf("expected \"dog\" to map to Dog")
+	}
+	if v, _ := m.Get("Cat"); v != doc.Components.Schemas.Get("Cat") {
+		t.Errorf("expected \"Cat\" to be implicitly mapped to Cat")
+	}
+	if m.Has("Dog") {
+		t.Errorf("expected \"Dog\" to not be implicitly mapped as it is explicitly mapped")
+	}
+	var keys []openapi.Text
+	for _, kv := range m.Items {
+		keys = append(keys, kv.Key)
+	}
+	if len(keys) != 2 || keys[0] != "dog" || keys[1] != "Cat" {
+		t.Errorf("expected only the branches of oneOf to be mapped, got %v", keys)
+	}
+	if err = pet.ValidateDiscriminator(&doc); err != nil {
+		t.Errorf("expected discriminator to be valid, got %v", err)
+	}
+
+	pet.OneOf.Items = append(pet.OneOf.Items, &openapi.Schema{Type: openapi.Types{openapi.TypeObject}})
+	if err = pet.ValidateDiscriminator(&doc); err == nil {
+		t.Errorf("expected an error for an unreachable inline branch")
+	}
+
+	pet.Discriminator.Mapping.Set("bird", "#/components/schemas/Bird")
+	if _, err = pet.Discriminator.ResolveMapping(&doc, pet); err == nil {
+		t.Errorf("expected an error for an unresolvable mapping")
+	}
+}
+
+func TestDiscriminatorResolveMappingAllOf(t *testing.T) {
+	var doc openapi.Document
+	err := json.Unmarshal([]byte(`{
+		"openapi": "3.1.0",
+		"info": { "title": "pets", "version": "1.0.0" },
+		"components": {
+			"schemas": {
+				"Pet": {
+					"type": "object",
+					"required": ["petType"],
+					"properties": { "petType": { "type": "string" } },
+					"discriminator": {
+						"propertyName": "petType",
+						"mapping": { "dog": "Dog" }
+					}
+				},
+				"Cat": {
+					"allOf": [
+						{ "$ref": "#/components/schemas/Pet" },
+						{ "type": "object", "properties": { "name": { "type": "string" } } }
+					]
+				},
+				"Dog": {
+					"allOf": [
+						{ "$ref": "#/components/schemas/Pet" },
+						{ "type": "object", "properties": { "bark": { "type": "string" } } }
+					]
+				},
+				"Lizard": {
+					"allOf": [{ "$ref": "#/components/schemas/Reptile" }]
+				},
+				"Reptile": { "type": "object" },
+				"Error": { "type": "object" }
+			}
+		}
+	}`), &doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pet := doc.Components.Schemas.Get("Pet")
+	m, err := pet.Discriminator.ResolveMapping(&doc, pet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var keys []openapi.Text
+	for _, kv := range m.Items {
+		keys = append(keys, kv.Key)
+	}
+	if len(keys) != 2 || keys[0] != "dog" || keys[1] != "Cat" {
+		t.Fatalf("expected the components which inherit from Pet to be mapped, got %v", keys)
+	}
+	if v, _ := m.Get("Cat"); v != doc.Components.Schemas.Get("Cat") {
+		t.Errorf("expected \"Cat\" to be implicitly mapped to Cat")
+	}
+	if v, _ := m.Get("dog"); v != doc.Components.Schemas.Get("Dog") {
+		t.Errorf("expected \"dog\" to map to Dog")
+	}
+
+	m, err = pet.Discriminator.ResolveMapping(&doc, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Items) != 1 || !m.Has("dog") {
+		t.Errorf("expected only the explicit entry without an owner, got %d entries", len(m.Items))
+	}
+}
+
 // import (
 // 	"encoding/json"
 // 	"fmt"
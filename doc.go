@@ -1,2 +1,13 @@
 // Package openapi provides types, loading and validation for OpenAPI 3.1 and 3.0.
+//
+// # Concurrency
+//
+// A Document, and the nodes within it, may be read concurrently by multiple
+// goroutines provided that none of them modify it. Methods which modify a
+// Document (e.g. ApplyJSONPatch, SetExtension) or assignment to any of its
+// fields require exclusive access.
+//
+// Document.Freeze returns a FrozenDocument, a deep copy of the Document
+// which is intended to be shared for the lifetime of a program (e.g. by the
+// request validators of an HTTP server).
 package openapi
@@ -1,11 +1,13 @@
 package openapi
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 
 	"github.com/Masterminds/semver"
-	"github.com/chanced/transcode"
 	"github.com/chanced/uri"
 	"gopkg.in/yaml.v3"
 )
@@ -78,6 +80,14 @@ type Document struct {
 
 	// Additional external documentation.
 	ExternalDocs *ExternalDocs `json:"externalDocs,omitempty"`
+
+	// source is retained by Load if LoadOpts.Refreshable is set
+	source *loadSource
+
+	// revision is incremented each time the Document is replaced in place
+	// (e.g. by ApplyJSONPatch) so that a MarshalCache does not reuse the
+	// encoding of the prior Document.
+	revision uint64
 }
 
 func (*Document) Kind() Kind { return KindDocument }
@@ -111,6 +121,13 @@ func (d *Document) Refs() []Ref {
 	return refs
 }
 
+func (d *Document) Nodes() []Node {
+	if d == nil {
+		return nil
+	}
+	return downcastNodes(d.nodes())
+}
+
 func (d *Document) nodes() []node {
 	edges := appendEdges(nil, d.Info)
 	edges = appendEdges(edges, d.Tags)
@@ -170,6 +187,30 @@ func (d Document) MarshalJSON() ([]byte, error) {
 	return marshalExtendedJSON(document(d))
 }
 
+// Checksum returns a hex-encoded SHA-256 hash of the normalized content of
+// the Document. The hash is independent of formatting, the order of object
+// keys, and the representation of numbers (e.g. 10 and 10.0), making it
+// suitable for cache keys and change detection. Numbers are compared exactly,
+// without rounding to float64.
+func (d Document) Checksum() (string, error) {
+	data, err := d.MarshalJSON()
+	if err != nil {
+		return "", fmt.Errorf("openapi: failed to marshal document: %w", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v interface{}
+	if err = dec.Decode(&v); err != nil {
+		return "", fmt.Errorf("openapi: failed to normalize document: %w", err)
+	}
+	// encoding/json sorts the keys of maps
+	if data, err = json.Marshal(canonicalNumbers(v)); err != nil {
+		return "", fmt.Errorf("openapi: failed to normalize document: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
 // UnmarshalJSON unmarshals JSON
 func (d *Document) UnmarshalJSON(data []byte) error {
 	type openapi Document
@@ -195,11 +236,7 @@ func (d Document) MarshalYAML() (interface{}, error) {
 
 // UnmarshalYAML satisfies gopkg.in/yaml.v3 Unmarshaler interface
 func (d *Document) UnmarshalYAML(value *yaml.Node) error {
-	v, err := yaml.Marshal(value)
-	if err != nil {
-		return err
-	}
-	j, err := transcode.JSONFromYAML(v)
+	j, err := jsonFromYAMLNode(value)
 	if err != nil {
 		return err
 	}
@@ -1,6 +1,7 @@
 package openapi_test
 
 import (
+	"bytes"
 	"encoding/json"
 	"io"
 	"testing"
@@ -52,6 +53,48 @@ func TestUnmarshal(t *testing.T) {
 	// litter.Dump(v)
 }
 
+func TestDocumentChecksum(t *testing.T) {
+	a := []byte(`{
+		"openapi": "3.1.0",
+		"info": { "title": "checksum", "version": "1.0.0" },
+		"components": {
+			"schemas": {
+				"Pet": { "type": "object", "maxProperties": 10 },
+				"Owner": { "type": "object" }
+			}
+		}
+	}`)
+	b := []byte(`{"components":{"schemas":{"Owner":{"type":"object"},"Pet":{"maxProperties":10.0,"type":"object"}}},"info":{"version":"1.0.0","title":"checksum"},"openapi":"3.1.0"}`)
+	checksum := func(data []byte) string {
+		var doc openapi.Document
+		if err := json.Unmarshal(data, &doc); err != nil {
+			t.Fatal(err)
+		}
+		sum, err := doc.Checksum()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sum
+	}
+	if checksum(a) != checksum(b) {
+		t.Error("expected checksums of equivalent documents to match")
+	}
+	c := bytes.Replace(b, []byte(`"Owner"`), []byte(`"Person"`), 1)
+	if checksum(a) == checksum(c) {
+		t.Error("expected checksums of different documents to differ")
+	}
+	// 9007199254740993 can not be represented by a float64
+	d := bytes.Replace(b, []byte(`10.0`), []byte(`9007199254740992`), 1)
+	e := bytes.Replace(b, []byte(`10.0`), []byte(`9007199254740993`), 1)
+	if checksum(d) == checksum(e) {
+		t.Error("expected checksums of documents which differ beyond the precision of float64 to differ")
+	}
+	f := bytes.Replace(b, []byte(`10.0`), []byte(`9007199254740993.0`), 1)
+	if checksum(e) != checksum(f) {
+		t.Error("expected checksums of equal large numbers to match")
+	}
+}
+
 func TestValidate(t *testing.T) {
 	// ps, err := fs.ReadFile(testdata, "testdata/petstore.yaml")
 	// if err != nil {
@@ -3,7 +3,6 @@ package openapi
 import (
 	"encoding/json"
 
-	"github.com/chanced/transcode"
 	"gopkg.in/yaml.v3"
 )
 
@@ -60,6 +59,21 @@ type Encoding struct {
 	AllowReserved *bool `json:"allowReserved,omitempty"`
 }
 
+// Header returns the header name of the Encoding, matched case-insensitively
+// (e.g. "X-Rate-Limit" matches "x-rate-limit"). An exact match is preferred.
+func (e *Encoding) Header(name Text) *Component[*Header] {
+	if e == nil {
+		return nil
+	}
+	return lookupHeader(e.Headers, name)
+}
+
+// HasHeader reports whether the Encoding has the header name, matched
+// case-insensitively.
+func (e *Encoding) HasHeader(name Text) bool {
+	return e.Header(name) != nil
+}
+
 func (e *Encoding) Nodes() []Node {
 	if e == nil {
 		return nil
@@ -157,11 +171,7 @@ func (e Encoding) MarshalYAML() (interface{}, error) {
 
 // UnmarshalYAML satisfies gopkg.in/yaml.v3 Unmarshaler interface
 func (e *Encoding) UnmarshalYAML(value *yaml.Node) error {
-	v, err := yaml.Marshal(value)
-	if err != nil {
-		return err
-	}
-	j, err := transcode.JSONFromYAML(v)
+	j, err := jsonFromYAMLNode(value)
 	if err != nil {
 		return err
 	}
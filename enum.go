@@ -0,0 +1,383 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/chanced/jsonx"
+)
+
+// Enum is the enum of a Schema. Each member is the raw JSON of a value,
+// which may be of any type.
+type Enum []jsonx.RawMessage
+
+// StringEnum returns an Enum of the string values.
+func StringEnum(values ...Text) Enum {
+	e := make(Enum, len(values))
+	for i, v := range values {
+		e[i], _ = json.Marshal(v)
+	}
+	return e
+}
+
+// NewEnum returns an Enum of the JSON encoding of each of values.
+func NewEnum(values ...interface{}) (Enum, error) {
+	e := make(Enum, len(values))
+	for i, v := range values {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("%w: member %d: %v", ErrInvalidEnum, i, err)
+		}
+		e[i] = data
+	}
+	return e, nil
+}
+
+// Texts returns the members of e which are strings, decoded, and whether
+// every member of e is a string.
+func (e Enum) Texts() (Texts, bool) {
+	var res Texts
+	ok := true
+	for _, v := range e {
+		var t Text
+		if !v.IsString() || json.Unmarshal(v, &t) != nil {
+			ok = false
+			continue
+		}
+		res = append(res, t)
+	}
+	return res, ok
+}
+
+// Index returns the index of the first member of e which is equal to the
+// JSON value v, or -1 if there is not one. Values are compared as JSON:
+// whitespace and the order of object keys are insignificant, as are
+// differences in the representation of numbers (e.g. 1 and 1.0).
+func (e Enum) Index(v []byte) int {
+	key := canonicalJSON(v)
+	for i, m := range e {
+		if canonicalJSON(m) == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// Contains reports whether e has a member equal to the JSON value v. See
+// Index.
+func (e Enum) Contains(v []byte) bool { return e.Index(v) != -1 }
+
+// Clone returns a deep copy of e.
+func (e Enum) Clone() Enum {
+	if e == nil {
+		return nil
+	}
+	c := make(Enum, len(e))
+	for i, v := range e {
+		c[i] = append(jsonx.RawMessage(nil), v...)
+	}
+	return c
+}
+
+// String returns the JSON array of the members of e.
+func (e Enum) String() string {
+	b := bytes.Buffer{}
+	b.WriteByte('[')
+	for i, v := range e {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.Write(v)
+	}
+	b.WriteByte(']')
+	return b.String()
+}
+
+// DecodeEnum decodes each member of the enum of s into a T.
+func DecodeEnum[T any](s *Schema) ([]T, error) {
+	if s == nil || len(s.Enum) == 0 {
+		return nil, nil
+	}
+	res := make([]T, len(s.Enum))
+	for i, v := range s.Enum {
+		if err := json.Unmarshal(v, &res[i]); err != nil {
+			return nil, NewError(fmt.Errorf("openapi: failed to decode enum member %d: %w", i, err), s.AbsoluteLocation())
+		}
+	}
+	return res, nil
+}
+
+// DecodeConst decodes the const of s into a T. If s does not have a const,
+// the zero value of T and false are returned.
+func DecodeConst[T any](s *Schema) (T, bool, error) {
+	var v T
+	if s == nil || s.Const == nil {
+		return v, false, nil
+	}
+	if err := json.Unmarshal(s.Const, &v); err != nil {
+		return v, false, NewError(fmt.Errorf("openapi: failed to decode const: %w", err), s.AbsoluteLocation())
+	}
+	return v, true, nil
+}
+
+// canonicalJSON returns a representation of the JSON value data which is
+// equal to that of any equivalent JSON value. If data is not valid JSON, it
+// is returned as is.
+func canonicalJSON(data []byte) string {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	var v interface{}
+	if err := d.Decode(&v); err != nil {
+		return string(data)
+	}
+	res, err := json.Marshal(canonicalNumbers(v))
+	if err != nil {
+		return string(data)
+	}
+	return string(res)
+}
+
+// canonicalNumbers replaces each json.Number of v with its canonical form
+func canonicalNumbers(v interface{}) interface{} {
+	switch x := v.(type) {
+	case json.Number:
+		if r, ok := new(big.Rat).SetString(x.String()); ok {
+			return json.Number(r.RatString())
+		}
+		return x
+	case map[string]interface{}:
+		for k, e := range x {
+			x[k] = canonicalNumbers(e)
+		}
+		return x
+	case []interface{}:
+		for i, e := range x {
+			x[i] = canonicalNumbers(e)
+		}
+		return x
+	default:
+		return v
+	}
+}
+
+// jsonType returns the Type of the JSON value data. Numbers which are
+// integers (e.g. 1 and 1.0) are TypeInteger.
+func jsonType(data jsonx.RawMessage) Type {
+	switch {
+	case data.IsString():
+		return TypeString
+	case data.IsNumber():
+		if r, ok := new(big.Rat).SetString(string(bytes.TrimSpace(data))); ok && r.IsInt() {
+			return TypeInteger
+		}
+		return TypeNumber
+	case data.IsBool():
+		return TypeBoolean
+	case data.IsNull():
+		return TypeNull
+	case data.IsObject():
+		return TypeObject
+	case data.IsArray():
+		return TypeArray
+	default:
+		return ""
+	}
+}
+
+// typesAllow reports whether a value of Type t is permitted by types
+func typesAllow(types Types, t Type) bool {
+	return len(types) == 0 || types.Contains(t) || (t == TypeInteger && types.ContainsNumber())
+}
+
+// EnumEntry is a member of the enum of a Schema along with its name and
+// description.
+type EnumEntry struct {
+	Value jsonx.RawMessage
+	// Name is the name of the constant generated for Value (see
+	// AnnotationEnumVarNames).
+	Name Text
+	// Description describes Value (see AnnotationEnumDescriptions).
+	Description Text
+}
+
+// alternate extensions for the names and descriptions of enum members used
+// by some generators
+const (
+	extensionEnumNames        Text = "x-enumNames"
+	extensionEnumDescriptions Text = "x-enumDescriptions"
+)
+
+// EnumEntries returns an EnumEntry for each member of the enum of s.
+//
+// Names are read from AnnotationEnumVarNames ("x-enum-varnames") or, if
+// absent, "x-enumNames". Descriptions are read from
+// AnnotationEnumDescriptions ("x-enum-descriptions") or, if absent,
+// "x-enumDescriptions", which may also be an object keyed by member (the
+// value of string members or the JSON of other members).
+func (s *Schema) EnumEntries() ([]EnumEntry, error) {
+	if s == nil || len(s.Enum) == 0 {
+		return nil, nil
+	}
+	names, err := s.enumNames()
+	if err != nil {
+		return nil, err
+	}
+	descriptions, err := s.enumDescriptions()
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]EnumEntry, len(s.Enum))
+	for i, v := range s.Enum {
+		entries[i].Value = v
+		if i < len(names) {
+			entries[i].Name = names[i]
+		}
+		if descriptions != nil {
+			entries[i].Description = descriptions(i, v)
+		}
+	}
+	return entries, nil
+}
+
+func (s *Schema) enumNames() (Texts, error) {
+	names, ok, err := AnnotationEnumVarNames.Get(s)
+	if ok || err != nil {
+		return names, err
+	}
+	names, err = GetExtension[Texts](s, extensionEnumNames)
+	if errors.Is(err, ErrExtensionNotFound) {
+		return nil, nil
+	}
+	return names, err
+}
+
+// enumDescriptions returns a function which returns the description of the
+// member v at index i
+func (s *Schema) enumDescriptions() (func(i int, v jsonx.RawMessage) Text, error) {
+	descriptions, ok, err := AnnotationEnumDescriptions.Get(s)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		data, ok := s.Extensions[extensionEnumDescriptions]
+		if !ok {
+			return nil, nil
+		}
+		if err = json.Unmarshal(data, &descriptions); err != nil {
+			byValue := map[Text]Text{}
+			if json.Unmarshal(data, &byValue) != nil {
+				return nil, NewError(fmt.Errorf("openapi: failed to decode extension %q: %w", extensionEnumDescriptions, err), s.AbsoluteLocation())
+			}
+			return func(_ int, v jsonx.RawMessage) Text {
+				var key Text
+				if json.Unmarshal(v, &key) != nil {
+					key = Text(v)
+				}
+				return byValue[key]
+			}, nil
+		}
+	}
+	return func(i int, _ jsonx.RawMessage) Text {
+		if i < len(descriptions) {
+			return descriptions[i]
+		}
+		return ""
+	}, nil
+}
+
+// SetEnumEntries sets the enum of s to the Value of each entry. The names and
+// descriptions of entries are set as the AnnotationEnumVarNames and
+// AnnotationEnumDescriptions annotations of s, respectively, unless none of
+// the entries have one, in which case the annotation is removed. The
+// alternate "x-enumNames" and "x-enumDescriptions" extensions are removed.
+func (s *Schema) SetEnumEntries(entries []EnumEntry) error {
+	if len(entries) == 0 {
+		s.Enum = nil
+	} else {
+		s.Enum = make(Enum, len(entries))
+	}
+	var names, descriptions Texts
+	for i, e := range entries {
+		s.Enum[i] = e.Value
+		if e.Name != "" && names == nil {
+			names = make(Texts, len(entries))
+		}
+		if e.Description != "" && descriptions == nil {
+			descriptions = make(Texts, len(entries))
+		}
+	}
+	for i, e := range entries {
+		if names != nil {
+			names[i] = e.Name
+		}
+		if descriptions != nil {
+			descriptions[i] = e.Description
+		}
+	}
+	s.Extensions.DeleteExtension(extensionEnumNames)
+	s.Extensions.DeleteExtension(extensionEnumDescriptions)
+	s.Extensions.DeleteExtension(AnnotationEnumVarNames.Name)
+	s.Extensions.DeleteExtension(AnnotationEnumDescriptions.Name)
+	if names != nil {
+		if err := AnnotationEnumVarNames.Set(s, names); err != nil {
+			return err
+		}
+	}
+	if descriptions != nil {
+		if err := AnnotationEnumDescriptions.Set(s, descriptions); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateEnum returns an error wrapping ErrInvalidEnum if the enum of s
+// contains duplicate members or members which do not match the type of s, or
+// if the names or descriptions of its members (see EnumEntries) do not
+// correspond to the members.
+func (s *Schema) ValidateEnum() error {
+	if s == nil || len(s.Enum) == 0 {
+		return nil
+	}
+	invalid := func(format string, args ...interface{}) error {
+		return NewError(fmt.Errorf("%w: %s", ErrInvalidEnum, fmt.Sprintf(format, args...)), s.AbsoluteLocation())
+	}
+	seen := make(map[string]bool, len(s.Enum))
+	for _, v := range s.Enum {
+		key := canonicalJSON(v)
+		if seen[key] {
+			return invalid("duplicate member %s", v)
+		}
+		seen[key] = true
+		if t := jsonType(v); t == "" {
+			return invalid("member %s is not valid JSON", v)
+		} else if !typesAllow(s.Type, t) {
+			return invalid("member %s is of type %s but the type is %v", v, t, s.Type)
+		}
+	}
+	names, err := s.enumNames()
+	if err != nil {
+		return err
+	}
+	if names != nil && len(names) != len(s.Enum) {
+		return invalid("expected %d names, found %d", len(s.Enum), len(names))
+	}
+	uniqueNames := make(map[Text]bool, len(names))
+	for _, n := range names {
+		if uniqueNames[n] {
+			return invalid("duplicate name %q", n)
+		}
+		uniqueNames[n] = true
+	}
+	descriptions, ok, err := AnnotationEnumDescriptions.Get(s)
+	if err != nil {
+		return err
+	}
+	if ok && len(descriptions) != len(s.Enum) {
+		return invalid("expected %d descriptions, found %d", len(s.Enum), len(descriptions))
+	}
+	return nil
+}
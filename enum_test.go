@@ -0,0 +1,156 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/chanced/jsonx"
+	"github.com/chanced/openapi"
+)
+
+func TestEnumEntries(t *testing.T) {
+	var s openapi.Schema
+	err := json.Unmarshal([]byte(`{
+		"type": "string",
+		"enum": ["cat", "dog"],
+		"x-enum-varnames": ["PetCat", "PetDog"],
+		"x-enumDescriptions": { "dog": "A dog" }
+	}`), &s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries, err := s.EnumEntries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []openapi.EnumEntry{
+		{Value: jsonx.RawMessage(`"cat"`), Name: "PetCat"},
+		{Value: jsonx.RawMessage(`"dog"`), Name: "PetDog", Description: "A dog"},
+	}
+	if len(entries) != len(expected) {
+		t.Fatalf("expected %d entries, got %d", len(expected), len(entries))
+	}
+	for i, e := range entries {
+		if !e.Value.Equal(expected[i].Value) || e.Name != expected[i].Name || e.Description != expected[i].Description {
+			t.Errorf("expected %+v, got %+v", expected[i], e)
+		}
+	}
+	if err = s.ValidateEnum(); err != nil {
+		t.Error(err)
+	}
+
+	entries = append(entries, openapi.EnumEntry{Value: jsonx.RawMessage(`"bird"`), Description: "A bird"})
+	entries[0].Description = "A cat"
+	if err = s.SetEnumEntries(entries); !errors.Is(err, openapi.ErrInvalidAnnotation) {
+		t.Errorf("expected ErrInvalidAnnotation for an empty name, got %v", err)
+	}
+	entries[2].Name = "PetBird"
+	if err = s.SetEnumEntries(entries); err != nil {
+		t.Fatal(err)
+	}
+	b, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var v map[string]interface{}
+	if err = json.Unmarshal(b, &v); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := v["x-enumDescriptions"]; ok {
+		t.Error("expected x-enumDescriptions to be removed")
+	}
+	if d, ok := v["x-enum-descriptions"].([]interface{}); !ok || len(d) != 3 || d[1] != "A dog" {
+		t.Errorf("expected x-enum-descriptions to be set, got %v", v["x-enum-descriptions"])
+	}
+	if err = s.ValidateEnum(); err != nil {
+		t.Error(err)
+	}
+
+	s.Enum = append(s.Enum, jsonx.RawMessage(`"cat"`))
+	if err = s.ValidateEnum(); !errors.Is(err, openapi.ErrInvalidEnum) {
+		t.Errorf("expected ErrInvalidEnum for a duplicate member, got %v", err)
+	}
+	s.Enum = s.Enum[:3]
+	s.Type = openapi.Types{openapi.TypeInteger}
+	if err = s.ValidateEnum(); !errors.Is(err, openapi.ErrInvalidEnum) {
+		t.Errorf("expected ErrInvalidEnum for a type mismatch, got %v", err)
+	}
+}
+
+func TestDecodeEnum(t *testing.T) {
+	var s openapi.Schema
+	err := json.Unmarshal([]byte(`{
+		"type": ["number", "object", "null"],
+		"enum": [1, 2.5, {"a": [1, "b"]}, null],
+		"const": 2.5
+	}`), &s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var v struct {
+		Enum json.RawMessage `json:"enum"`
+	}
+	if err = json.Unmarshal(b, &v); err != nil {
+		t.Fatal(err)
+	}
+	if string(v.Enum) != `[1,2.5,{"a":[1,"b"]},null]` {
+		t.Errorf("expected enum to round-trip, got %s", v.Enum)
+	}
+	if err = s.ValidateEnum(); err != nil {
+		t.Error(err)
+	}
+	if _, ok := s.Enum.Texts(); ok {
+		t.Error("expected Texts to report non-string members")
+	}
+	if i := s.Enum.Index([]byte(`{ "a": [1.0, "b"] }`)); i != 2 {
+		t.Errorf("expected index 2, got %d", i)
+	}
+
+	members, err := openapi.DecodeEnum[interface{}](&s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(members) != 4 || members[0] != 1.0 || members[1] != 2.5 || members[3] != nil {
+		t.Errorf("unexpected members %v", members)
+	}
+	if _, ok := members[2].(map[string]interface{}); !ok {
+		t.Errorf("expected member 2 to be an object, got %T", members[2])
+	}
+	nums, err := openapi.DecodeEnum[*float64](&openapi.Schema{Enum: openapi.Enum{s.Enum[0], s.Enum[1], s.Enum[3]}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nums) != 3 || *nums[0] != 1 || *nums[1] != 2.5 || nums[2] != nil {
+		t.Errorf("unexpected members %v", nums)
+	}
+	if _, err = openapi.DecodeEnum[int](&s); err == nil {
+		t.Error("expected an error decoding an object member into an int")
+	}
+	c, ok, err := openapi.DecodeConst[float64](&s)
+	if err != nil || !ok || c != 2.5 {
+		t.Errorf("expected const 2.5, got %v, %v, %v", c, ok, err)
+	}
+
+	s.Enum = append(s.Enum, jsonx.RawMessage(`1.0`))
+	if err = s.ValidateEnum(); !errors.Is(err, openapi.ErrInvalidEnum) {
+		t.Errorf("expected ErrInvalidEnum for a duplicate member, got %v", err)
+	}
+	s.Enum = s.Enum[:4]
+	s.Enum = append(s.Enum, jsonx.RawMessage(`"c"`))
+	if err = s.ValidateEnum(); !errors.Is(err, openapi.ErrInvalidEnum) {
+		t.Errorf("expected ErrInvalidEnum for a type mismatch, got %v", err)
+	}
+
+	e, err := openapi.NewEnum(1, "two", map[string]int{"three": 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.String() != `[1,"two",{"three":3}]` {
+		t.Errorf("unexpected enum %s", e)
+	}
+}
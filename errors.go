@@ -8,6 +8,7 @@ import (
 	"github.com/Masterminds/semver"
 	"github.com/chanced/jsonpointer"
 	"github.com/chanced/uri"
+	"github.com/santhosh-tekuri/jsonschema/v5"
 )
 
 var (
@@ -28,8 +29,144 @@ var (
 	ErrInvalidSemVerPrerelease = errors.New("invalid semantic version prerelease string")
 
 	ErrInvalidResolution = errors.New("openapi: invalid resolution")
+
+	// ErrUnresolvedReference is returned when a referenced object is required
+	// but the reference has not yet been resolved.
+	ErrUnresolvedReference = errors.New("openapi: unresolved reference")
+
+	// ErrUnsupportedDialect is returned when a JSON Schema dialect is not
+	// registered with the DialectRegistry in use. Errors matching
+	// ErrUnsupportedDialect are of type *UnsupportedDialectError.
+	ErrUnsupportedDialect = errors.New("openapi: unsupported JSON Schema dialect")
+
+	// ErrOffline is returned when a resource which has not been provided
+	// would need to be fetched over the network.
+	ErrOffline = errors.New("openapi: resource not available offline")
+
+	// ErrExampleAndExamples is returned when both example and examples are
+	// set on a Parameter, Header, or MediaType; the fields are mutually
+	// exclusive.
+	ErrExampleAndExamples = errors.New("openapi: example and examples are mutually exclusive")
+
+	// ErrMissingExampleValue is returned when the value of an Example is
+	// required but neither value nor the data of externalValue is available.
+	ErrMissingExampleValue = errors.New("openapi: example does not have a value")
+
+	// ErrExtensionNotFound is returned when an extension is expected to be
+	// present but is not.
+	ErrExtensionNotFound = errors.New("openapi: extension not found")
+
+	// ErrWebhookServers is returned when a webhook's PathItem or one of its
+	// Operations defines servers. Webhooks are requests sent by the API
+	// provider, so servers have no meaning.
+	ErrWebhookServers = errors.New("openapi: webhooks must not define servers")
+
+	// ErrInvalidPatch is returned when a JSON Patch or JSON Merge Patch is
+	// malformed or can not be applied.
+	ErrInvalidPatch = errors.New("openapi: invalid patch")
+
+	// ErrPatchTestFailed is returned when a "test" operation of a JSON Patch
+	// fails.
+	ErrPatchTestFailed = errors.New("openapi: patch test failed")
+
+	// ErrLimitExceeded is returned by Load when one of the limits of
+	// LoadOpts (e.g. MaxResources) is exceeded.
+	ErrLimitExceeded = errors.New("openapi: limit exceeded")
+
+	// ErrRefNotAllowed is returned by Load when the target of a reference is
+	// rejected by LoadOpts.AllowRef.
+	ErrRefNotAllowed = errors.New("openapi: reference not allowed")
+
+	// ErrRefNotFound is returned when the target of a reference can not be
+	// found. Errors matching ErrRefNotFound are of type *RefNotFoundError.
+	ErrRefNotFound = errors.New("openapi: ref URI not found")
+
+	// ErrUnsupportedKind is returned when a Kind of resource can not be
+	// loaded. Errors matching ErrUnsupportedKind are of type
+	// *UnsupportedKindError.
+	ErrUnsupportedKind = errors.New("openapi: unsupported kind")
+
+	// ErrInvalidAnnotation is returned when the value of an Annotation is
+	// invalid or the Annotation is applied to a Kind of node which it does
+	// not support.
+	ErrInvalidAnnotation = errors.New("openapi: invalid annotation")
+
+	// ErrInvalidEnum is returned when the enum of a Schema contains
+	// duplicate members, members which do not match the Schema's type, or
+	// names or descriptions which do not correspond to its members.
+	ErrInvalidEnum = errors.New("openapi: invalid enum")
+
+	// ErrInvalidNumber is returned when a Number is not a valid JSON number
+	// or can not be represented exactly by the requested type.
+	ErrInvalidNumber = errors.New("openapi: invalid number")
+
+	// ErrInvalidPattern is returned when the pattern of a Schema can not be
+	// compiled according to the PatternMode.
+	ErrInvalidPattern = errors.New("openapi: invalid pattern")
+
+	// ErrNonPortablePattern is returned by CheckPatternPortability when a
+	// pattern is not interpreted identically by ECMA-262 and RE2.
+	ErrNonPortablePattern = errors.New("openapi: non-portable pattern")
+
+	// ErrInvalidContent is returned when a string instance is not encoded
+	// according to the contentEncoding of a Schema or its decoded content
+	// does not satisfy contentMediaType or contentSchema.
+	ErrInvalidContent = errors.New("openapi: invalid content")
+
+	// ErrInvalidResponseCode is returned when the key of a ResponseMap is
+	// neither an HTTP status code, a range of status codes (e.g. "4XX"), nor
+	// "default".
+	ErrInvalidResponseCode = errors.New("openapi: invalid response code")
+
+	// ErrInvalidLicense is returned when a License is missing a name, has
+	// both an identifier and a url, or its identifier is not a valid SPDX
+	// license expression.
+	ErrInvalidLicense = errors.New("openapi: invalid license")
+
+	// ErrInvalidInfo is returned when the Info of a Document is missing a
+	// required field or does not satisfy an InfoPolicy.
+	ErrInvalidInfo = errors.New("openapi: invalid info")
+
+	// ErrInvalidOAuthFlow is returned when an OAuthFlow is missing a URL
+	// required by its flow type or its scopes, or one of its URLs is not an
+	// absolute https URL.
+	ErrInvalidOAuthFlow = errors.New("openapi: invalid oauth flow")
+
+	// ErrNotModified should be returned by the fn passed to Load when a
+	// conditional request made during Document.Refresh indicates that the
+	// resource has not changed (e.g. a 304 response). See
+	// ConditionalRequest.
+	ErrNotModified = errors.New("openapi: resource not modified")
+
+	// ErrNotRefreshable is returned by Document.Refresh when the Document
+	// was not loaded with LoadOpts.Refreshable.
+	ErrNotRefreshable = errors.New("openapi: document is not refreshable")
+
+	// ErrLockMismatch is returned when an external resource is missing from
+	// a Lockfile or its data does not match the hash recorded for it.
+	ErrLockMismatch = errors.New("openapi: resource does not match lockfile")
+
+	// ErrInstanceNotGenerated is returned by an InstanceGenerator when no
+	// instance of a Schema, valid or invalid as requested, could be found.
+	ErrInstanceNotGenerated = errors.New("openapi: unable to generate instance")
+
+	// ErrUnknownSnippetLanguage is returned when rendering a snippet in a
+	// language which is not registered with the SnippetRegistry.
+	ErrUnknownSnippetLanguage = errors.New("openapi: unknown snippet language")
+
+	// ErrUnsupportedConversion is returned by ConvertVersion when a Document
+	// uses a feature which the target version can not represent.
+	ErrUnsupportedConversion = errors.New("openapi: unsupported conversion")
+
+	// ErrInvalidServerVariable is returned by Document.ServerURL when a server
+	// variable does not have a value or its value is not one of its enum.
+	ErrInvalidServerVariable = errors.New("openapi: invalid server variable")
 )
 
+func newErrUnresolvedReference(r Ref) error {
+	return NewError(fmt.Errorf("%w: %s", ErrUnresolvedReference, r.URI()), r.AbsoluteLocation())
+}
+
 type Error struct {
 	Err         error
 	ResourceURI uri.URI
@@ -50,6 +187,23 @@ func (e *Error) Unwrap() error {
 	return e.Err
 }
 
+// withLocation wraps err with NewError unless err already contains an *Error
+// or a *ValidationError
+func withLocation(err error, resource uri.URI) error {
+	if err == nil {
+		return nil
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return err
+	}
+	var ve *ValidationError
+	if errors.As(err, &ve) {
+		return err
+	}
+	return NewError(err, resource)
+}
+
 func newErrNotFound(uri uri.URI, tok jsonpointer.Token) error {
 	return NewError(fmt.Errorf("%w: %q", ErrNotFound, tok), uri)
 }
@@ -58,6 +212,66 @@ func newErrNotResolvable(uri uri.URI, tok jsonpointer.Token) error {
 	return NewError(fmt.Errorf("%w: %q", ErrNotResolvable, tok), uri)
 }
 
+// RefNotFoundError is returned when the target of a reference can not be
+// found.
+type RefNotFoundError struct {
+	// URI is the URI of the reference (e.g. the value of $ref)
+	URI uri.URI
+	// Location is the absolute location of the reference
+	Location uri.URI
+}
+
+func newRefNotFoundError(r Ref) error {
+	e := &RefNotFoundError{Location: r.AbsoluteLocation()}
+	if u := r.URI(); u != nil {
+		e.URI = *u
+	}
+	return NewError(e, e.Location)
+}
+
+func (e *RefNotFoundError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrRefNotFound, e.URI.String())
+}
+
+func (e *RefNotFoundError) Is(err error) bool {
+	return err == ErrRefNotFound
+}
+
+// UnsupportedKindError is returned when a resource of Kind can not be
+// loaded.
+type UnsupportedKindError struct {
+	Kind Kind
+	// URI of the resource
+	URI uri.URI
+}
+
+func (e *UnsupportedKindError) Error() string {
+	return fmt.Sprintf("%s: loading %s as an external resource is not currently supported", ErrUnsupportedKind, e.Kind)
+}
+
+func (e *UnsupportedKindError) Is(err error) bool {
+	return err == ErrUnsupportedKind
+}
+
+// UnsupportedDialectError is returned when a JSON Schema dialect is not
+// registered with the DialectRegistry in use or its base is not supported.
+type UnsupportedDialectError struct {
+	Dialect uri.URI
+	// Base, if set, is the unsupported base dialect of Dialect
+	Base *uri.URI
+}
+
+func (e *UnsupportedDialectError) Error() string {
+	if e.Base != nil {
+		return fmt.Sprintf("%s: unsupported base %s for %s", ErrUnsupportedDialect, e.Base.String(), e.Dialect.String())
+	}
+	return fmt.Sprintf("%s: %s", ErrUnsupportedDialect, e.Dialect.String())
+}
+
+func (e *UnsupportedDialectError) Is(err error) bool {
+	return err == ErrUnsupportedDialect
+}
+
 type UnsupportedVersionError struct {
 	Version string  `json:"version"`
 	Errs    []error `json:"errors"`
@@ -91,6 +305,38 @@ func (e *UnsupportedVersionError) Is(err error) bool {
 	return false
 }
 
+// ExtensionErrors is returned from StdValidator.ValidateDocument when one or
+// more extensions are invalid. Each error is a *ValidationError containing
+// the location of the extension.
+type ExtensionErrors []error
+
+func (e ExtensionErrors) Error() string {
+	b := strings.Builder{}
+	b.WriteString("openapi: invalid extensions:")
+	for _, err := range e {
+		b.WriteString(fmt.Sprintf("\n- %s", err))
+	}
+	return b.String()
+}
+
+func (e ExtensionErrors) As(target interface{}) bool {
+	for _, v := range e {
+		if errors.As(v, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e ExtensionErrors) Is(err error) bool {
+	for _, v := range e {
+		if errors.Is(v, err) {
+			return true
+		}
+	}
+	return false
+}
+
 type ValidationError struct {
 	Kind Kind
 	Err  error
@@ -118,6 +364,34 @@ func (e *ValidationError) Unwrap() error {
 	return e.Err
 }
 
+// Causes returns the individual causes of the ValidationError.
+//
+// If Err is a *jsonschema.ValidationError, a *ValidationError is returned for
+// each of its leaf causes with URI set to the location of the offending
+// value. Otherwise, a slice containing only e is returned.
+func (e *ValidationError) Causes() []*ValidationError {
+	var jve *jsonschema.ValidationError
+	if !errors.As(e.Err, &jve) {
+		return []*ValidationError{e}
+	}
+	var causes []*ValidationError
+	var walk func(v *jsonschema.ValidationError)
+	walk = func(v *jsonschema.ValidationError) {
+		if len(v.Causes) == 0 {
+			u := e.URI
+			u.Fragment += v.InstanceLocation
+			u.RawFragment = u.Fragment
+			causes = append(causes, &ValidationError{Kind: e.Kind, Err: v, URI: u})
+			return
+		}
+		for _, c := range v.Causes {
+			walk(c)
+		}
+	}
+	walk(jve)
+	return causes
+}
+
 type SemVerError struct {
 	Value string
 	Err   error
@@ -0,0 +1,68 @@
+package openapi_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/chanced/openapi"
+	"github.com/chanced/uri"
+)
+
+func TestErrorTypes(t *testing.T) {
+	ctx := context.Background()
+	load := func(data string, v openapi.Validator) error {
+		fn := func(_ context.Context, _ uri.URI, kind openapi.Kind) (openapi.Kind, []byte, error) {
+			return openapi.KindDocument, []byte(data), nil
+		}
+		_, err := openapi.Load(ctx, "https://example.com/openapi.json", v, fn)
+		return err
+	}
+
+	err := load(`{
+		"openapi": "3.1.0",
+		"info": { "title": "errors", "version": "1.0.0" },
+		"components": {
+			"schemas": {
+				"Pet": { "$ref": "#/components/schemas/Missing" }
+			}
+		}
+	}`, NoopValidator{})
+	if !errors.Is(err, openapi.ErrRefNotFound) {
+		t.Errorf("expected ErrRefNotFound, got %v", err)
+	}
+	var rnf *openapi.RefNotFoundError
+	if !errors.As(err, &rnf) {
+		t.Fatalf("expected *RefNotFoundError, got %T", err)
+	}
+	if rnf.URI.String() != "#/components/schemas/Missing" {
+		t.Errorf("expected URI to be %q, got %q", "#/components/schemas/Missing", rnf.URI.String())
+	}
+	if rnf.Location.String() != "https://example.com/openapi.json#/components/schemas/Pet/$ref" {
+		t.Errorf("unexpected Location: %s", rnf.Location.String())
+	}
+
+	v, err := openapi.NewOfflineValidator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = load(`{
+		"openapi": "3.1.0",
+		"info": { "title": "errors" },
+		"paths": {}
+	}`, v)
+	var ve *openapi.ValidationError
+	if !errors.As(err, &ve) {
+		t.Fatalf("expected *ValidationError, got %v", err)
+	}
+	causes := ve.Causes()
+	if len(causes) == 0 {
+		t.Fatal("expected at least one cause")
+	}
+	for _, c := range causes {
+		if !strings.HasPrefix(c.URI.String(), "https://example.com/openapi.json") {
+			t.Errorf("expected cause URI to be within the document, got %s", c.URI.String())
+		}
+	}
+}
@@ -1,10 +1,12 @@
 package openapi
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 
 	"github.com/chanced/jsonx"
-	"github.com/chanced/transcode"
 	"github.com/chanced/uri"
 	"gopkg.in/yaml.v3"
 )
@@ -40,6 +42,143 @@ type Example struct {
 	// documents. The value field and externalValue field are mutually
 	// exclusive. See the rules for resolving Relative References.
 	ExternalValue *uri.URI `json:"externalValue,omitempty"`
+
+	// ExternalData is the raw data referenced by ExternalValue. It is
+	// populated by LoadExternalValue or by Load when LoadOpts.ExternalValues
+	// is true.
+	ExternalData []byte `json:"-"`
+}
+
+// NewExample creates a new Example with v marshaled as its Value.
+//
+// If v is a json.RawMessage or jsonx.RawMessage, it is used as is.
+func NewExample(v interface{}) (*Example, error) {
+	b, err := encodeExample(v)
+	if err != nil {
+		return nil, fmt.Errorf("openapi: failed to marshal example value: %w", err)
+	}
+	return &Example{Value: b}, nil
+}
+
+// value returns the JSON encoded value of the Example. If Value is not set,
+// ExternalData is used; data which is not valid JSON is encoded as a JSON
+// string.
+func (e *Example) value() (jsonx.RawMessage, error) {
+	if e == nil {
+		return nil, ErrMissingExampleValue
+	}
+	if len(e.Value) > 0 {
+		return e.Value, nil
+	}
+	if e.ExternalData == nil {
+		return nil, NewError(ErrMissingExampleValue, e.AbsoluteLocation())
+	}
+	if json.Valid(e.ExternalData) {
+		return e.ExternalData, nil
+	}
+	b, err := json.Marshal(string(e.ExternalData))
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// Decode unmarshals the value of the Example into dst.
+//
+// If Value is not set, the data fetched from ExternalValue is used. External
+// data which is not valid JSON is decoded as a string.
+//
+// An error wrapping ErrMissingExampleValue is returned if neither are
+// available.
+func (e *Example) Decode(dst interface{}) error {
+	v, err := e.value()
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(v, dst)
+}
+
+// Validate validates the value of the Example, as determined by Decode,
+// against schema.
+//
+// A CompiledSchema can be obtained from Schema.Compile.
+func (e *Example) Validate(schema CompiledSchema) error {
+	if schema == nil {
+		return fmt.Errorf("openapi: schema is required")
+	}
+	var v interface{}
+	if err := e.Decode(&v); err != nil {
+		return err
+	}
+	if err := schema.Validate(v); err != nil {
+		return NewValidationError(err, KindExample, e.AbsoluteLocation())
+	}
+	return nil
+}
+
+// LoadExternalValue fetches the data referenced by ExternalValue with fn,
+// storing it in ExternalData. ExternalValue is resolved relative to the
+// location of the Example.
+//
+// fn has the same signature as the function passed to Load and is invoked
+// with KindExample. The data is not transcoded.
+func (e *Example) LoadExternalValue(ctx context.Context, fn func(ctx context.Context, uri uri.URI, kind Kind) (Kind, []byte, error)) error {
+	if e == nil || e.ExternalValue == nil {
+		return nil
+	}
+	if fn == nil {
+		return errors.New("openapi: fn is required")
+	}
+	loc := e.AbsoluteLocation()
+	u := loc.ResolveReference(e.ExternalValue)
+	_, data, err := fn(ctx, *u, KindExample)
+	if err != nil {
+		return NewError(fmt.Errorf("openapi: failed to load externalValue %q: %w", u.String(), err), loc)
+	}
+	e.ExternalData = data
+	return nil
+}
+
+// encodeExample marshals v for use as the example field of a Parameter,
+// Header, or MediaType.
+func encodeExample(v interface{}) (jsonx.RawMessage, error) {
+	if r, ok := v.(jsonx.RawMessage); ok {
+		return r, nil
+	}
+	if r, ok := v.(json.RawMessage); ok {
+		return jsonx.RawMessage(r), nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return jsonx.RawMessage(b), nil
+}
+
+// validateExampleExclusivity returns an error wrapping ErrExampleAndExamples
+// if both example and examples are set.
+func validateExampleExclusivity(example jsonx.RawMessage, examples *ExampleMap, kind Kind, loc uri.URI) error {
+	if len(example) == 0 || examples == nil || len(examples.Items) == 0 {
+		return nil
+	}
+	return NewValidationError(ErrExampleAndExamples, kind, loc)
+}
+
+// validateExamples walks n and its descendants, returning an error wrapping
+// ErrExampleAndExamples for the first Parameter, Header, or MediaType which
+// has both example and examples set.
+func validateExamples(n node) error {
+	return walkNodes(n, func(n node) error {
+		switch t := n.(type) {
+		case *Parameter:
+			return t.ValidateExamples()
+		case *Header:
+			return t.ValidateExamples()
+		case *MediaType:
+			return t.ValidateExamples()
+		}
+		return nil
+	})
 }
 
 func (e *Example) Nodes() []Node {
@@ -92,11 +231,7 @@ func (e Example) MarshalYAML() (interface{}, error) {
 
 // UnmarshalYAML satisfies gopkg.in/yaml.v3 Unmarshaler interface
 func (e *Example) UnmarshalYAML(value *yaml.Node) error {
-	v, err := yaml.Marshal(value)
-	if err != nil {
-		return err
-	}
-	j, err := transcode.JSONFromYAML(v)
+	j, err := jsonFromYAMLNode(value)
 	if err != nil {
 		return err
 	}
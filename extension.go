@@ -92,9 +92,69 @@ func (e *Extensions) SetRawExtension(key Text, val []byte) {
 	if !key.HasPrefix("x-") {
 		key = "x-" + key
 	}
+	if *e == nil {
+		*e = Extensions{}
+	}
 	(*e)[key] = val
 }
 
+// DeleteExtension removes the extension key
+func (e Extensions) DeleteExtension(key Text) {
+	if !key.HasPrefix("x-") {
+		key = "x-" + key
+	}
+	delete(e, key)
+}
+
+// Keys returns the keys of the extensions, sorted lexicographically.
+func (e Extensions) Keys() []Text {
+	keys := make([]Text, 0, len(e))
+	for _, kv := range maps.SortByKeys(e) {
+		keys = append(keys, kv.Key)
+	}
+	return keys
+}
+
+// GetExtension decodes the extension key of n into a value of type T.
+//
+// An error wrapping ErrExtensionNotFound is returned if n does not have the
+// extension.
+func GetExtension[T any](n Node, key Text) (T, error) {
+	var v T
+	if !key.HasPrefix("x-") {
+		key = "x-" + key
+	}
+	e, ok := n.(extended)
+	if !ok {
+		return v, fmt.Errorf("openapi: %s does not support extensions", n.Kind())
+	}
+	data, ok := e.exts()[key]
+	if !ok {
+		return v, NewError(fmt.Errorf("%w: %q", ErrExtensionNotFound, key), n.AbsoluteLocation())
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return v, NewError(fmt.Errorf("openapi: failed to decode extension %q: %w", key, err), n.AbsoluteLocation())
+	}
+	return v, nil
+}
+
+// SetExtension encodes val and sets the result to the extension key of n.
+func SetExtension(n Node, key Text, val interface{}) error {
+	e, ok := n.(interface {
+		extended
+		extender
+	})
+	if !ok {
+		return fmt.Errorf("openapi: %s does not support extensions", n.Kind())
+	}
+	exts := e.exts()
+	if err := exts.SetExtension(key, val); err != nil {
+		return err
+	}
+	e.setExts(exts)
+	return nil
+}
+
 // Extension returns an extension by name
 func (e Extensions) Extension(key Text) (interface{}, bool) {
 	if !key.HasPrefix("x-") {
@@ -116,7 +176,7 @@ func unmarshalExtendedJSON(data []byte, dst extender) error {
 	}
 	gjson.ParseBytes(data).ForEach(func(key, value gjson.Result) bool {
 		if IsExtensionKey(Text(key.String())) {
-			ev[Text(key.String())] = jsonx.RawMessage(value.Raw)
+			ev[intern(key.String())] = jsonx.RawMessage(value.Raw)
 		}
 		return true
 	})
@@ -134,11 +194,13 @@ func marshalExtendedJSON(dst extended) ([]byte, error) {
 		return nil, fmt.Errorf("openapi: cannot marshal extensions into non-object")
 	}
 
-	b := bytes.Buffer{}
+	b := getBuffer()
 	b.Write(data[:len(data)-1])
-	return marshalExtensionsInto(&b, dst.exts())
+	return marshalExtensionsInto(b, dst.exts())
 }
 
+// marshalExtensionsInto writes e and the closing brace of the object to b,
+// which is released.
 func marshalExtensionsInto(b *bytes.Buffer, e Extensions) ([]byte, error) {
 	var err error
 	for _, kv := range maps.SortByKeys(e) {
@@ -153,5 +215,5 @@ func marshalExtensionsInto(b *bytes.Buffer, e Extensions) ([]byte, error) {
 		}
 	}
 	b.WriteByte('}')
-	return b.Bytes(), nil
+	return releaseBuffer(b), nil
 }
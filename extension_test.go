@@ -0,0 +1,109 @@
+package openapi_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/chanced/openapi"
+)
+
+func TestExtensions(t *testing.T) {
+	info := &openapi.Info{Title: "extensions", Version: "1.0.0"}
+	if err := openapi.SetExtension(info, "x-owner", map[string]string{"team": "pets"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := openapi.SetExtension(info, "audience", "public"); err != nil {
+		t.Fatal(err)
+	}
+	owner, err := openapi.GetExtension[map[string]string](info, "x-owner")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if owner["team"] != "pets" {
+		t.Errorf("expected %q, got %q", "pets", owner["team"])
+	}
+	if _, err = openapi.GetExtension[string](info, "x-missing"); !errors.Is(err, openapi.ErrExtensionNotFound) {
+		t.Errorf("expected ErrExtensionNotFound, got %v", err)
+	}
+	keys := info.Extensions.Keys()
+	if len(keys) != 2 || keys[0] != "x-audience" || keys[1] != "x-owner" {
+		t.Errorf("expected [x-audience x-owner], got %v", keys)
+	}
+
+	var s openapi.Schema
+	if err = json.Unmarshal([]byte(`{ "enum": ["public", "internal"] }`), &s); err != nil {
+		t.Fatal(err)
+	}
+	cs, err := s.Compile(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := openapi.NewOfflineValidator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	v.RegisterExtension("x-audience", cs)
+	doc := openapi.Document{Info: info}
+	if err = openapi.SetExtension(info, "x-audience", "everyone"); err != nil {
+		t.Fatal(err)
+	}
+	var ve *openapi.ValidationError
+	if err = v.ValidateDocument(&doc); !errors.As(err, &ve) {
+		t.Errorf("expected a ValidationError, got %v", err)
+	}
+}
+
+func TestExtensionProfiles(t *testing.T) {
+	var s openapi.Schema
+	if err := json.Unmarshal([]byte(`{ "type": "string" }`), &s); err != nil {
+		t.Fatal(err)
+	}
+	cs, err := s.Compile(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := openapi.NewOfflineValidator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = v.RegisterExtensionProfile(openapi.ExtensionProfile{Name: "go", Patterns: []openapi.Text{"go-*"}, Schema: cs}); err == nil {
+		t.Error("expected an error for a pattern without the x- prefix")
+	}
+	if err = v.RegisterExtensionProfile(openapi.ExtensionProfile{Name: "go", Patterns: []openapi.Text{"x-go-*"}, Schema: cs}); err != nil {
+		t.Fatal(err)
+	}
+	info := &openapi.Info{Title: "profiles", Version: "1.0.0"}
+	doc := openapi.Document{Info: info}
+	if err = openapi.SetExtension(info, "x-go-name", "PetInfo"); err != nil {
+		t.Fatal(err)
+	}
+	if err = openapi.SetExtension(info, "x-other", 1); err != nil {
+		t.Fatal(err)
+	}
+	if err = v.ValidateDocument(&doc); err != nil {
+		var ee openapi.ExtensionErrors
+		if errors.As(err, &ee) {
+			t.Fatalf("expected extensions to be valid, got %v", err)
+		}
+	}
+	if err = openapi.SetExtension(info, "x-go-package", 1); err != nil {
+		t.Fatal(err)
+	}
+	if err = openapi.SetExtension(info, "x-go-type", true); err != nil {
+		t.Fatal(err)
+	}
+	err = v.ValidateDocument(&doc)
+	var ee openapi.ExtensionErrors
+	if !errors.As(err, &ee) {
+		t.Fatalf("expected ExtensionErrors, got %v", err)
+	}
+	if len(ee) != 2 {
+		t.Errorf("expected 2 errors, got %d", len(ee))
+	}
+	var ve *openapi.ValidationError
+	if !errors.As(err, &ve) {
+		t.Errorf("expected a ValidationError, got %v", err)
+	}
+}
@@ -0,0 +1,384 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+// OperationSelector selects the Operations retained by Document.Filter. An
+// Operation is selected if it satisfies any of the criteria which are set.
+type OperationSelector struct {
+	// Tags selects the Operations tagged with any of Tags.
+	Tags []Text
+
+	// OperationIDs selects the Operations with any of OperationIDs.
+	OperationIDs []Text
+
+	// Paths selects the Operations of each PathItem with a path (e.g.
+	// "/pets/{petId}") or, for webhooks, a name which matches any of the
+	// patterns of Paths. Patterns are matched with path.Match, so "*" matches
+	// a single segment (e.g. "/pets/*" matches "/pets/{petId}" but not
+	// "/pets/{petId}/toys").
+	Paths []string
+
+	// Match, if set, selects each Operation for which it returns true.
+	Match func(op OperationEntry) bool
+}
+
+func (s OperationSelector) validate() error {
+	for _, p := range s.Paths {
+		if _, err := path.Match(p, ""); err != nil {
+			return fmt.Errorf("openapi: invalid path pattern %q: %w", p, err)
+		}
+	}
+	return nil
+}
+
+func (s OperationSelector) selects(op OperationEntry) bool {
+	for _, tag := range s.Tags {
+		for _, t := range op.Operation.Tags {
+			if t == tag {
+				return true
+			}
+		}
+	}
+	for _, id := range s.OperationIDs {
+		if op.Operation.OperationID == id {
+			return true
+		}
+	}
+	for _, p := range s.Paths {
+		if ok, _ := path.Match(p, op.Key.String()); ok {
+			return true
+		}
+	}
+	return s.Match != nil && s.Match(op)
+}
+
+// Filter returns a new Document containing only the Operations of d selected
+// by sel, along with the components they reference, directly or
+// transitively, for publishing a subset of an API to a particular audience.
+// d is not modified.
+//
+// PathItems and webhooks without a selected Operation are removed, as are the
+// tags of the Document which are not used by a selected Operation. Components
+// are retained if they are referenced with a local $ref, named by a
+// discriminator's mapping, or, in the case of security schemes, named by a
+// security requirement of the Document or a selected Operation. All other
+// members of the Document, including extensions, are retained as they are.
+//
+// The returned Document is unmarshaled from the encoding of d; it must be
+// loaded (e.g. with Document.Resolve) before its references are resolved.
+func (d *Document) Filter(sel OperationSelector) (*Document, error) {
+	if err := sel.validate(); err != nil {
+		return nil, err
+	}
+	data, err := d.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	f := filterer{
+		root:       gjson.ParseBytes(data),
+		selected:   map[string]bool{},
+		components: map[string]bool{},
+		tags:       map[Text]bool{},
+	}
+	for _, op := range d.Operations() {
+		if sel.selects(op) {
+			f.selected[f.operationKey(op.Webhook, op.Key.String(), strings.ToLower(op.Method.String()))] = true
+			for _, t := range op.Operation.Tags {
+				f.tags[t] = true
+			}
+		}
+	}
+	f.collectRoots()
+
+	b := bytes.Buffer{}
+	b.WriteByte('{')
+	i := 0
+	f.root.ForEach(func(key, value gjson.Result) bool {
+		member := bytes.Buffer{}
+		switch key.String() {
+		case "paths":
+			f.writePathItems(&member, value, false)
+		case "webhooks":
+			if f.writePathItems(&member, value, true) == 0 {
+				return true
+			}
+		case "components":
+			if f.writeComponents(&member, value) == 0 {
+				return true
+			}
+		case "tags":
+			f.writeTags(&member, value)
+		default:
+			member.WriteString(value.Raw)
+		}
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		i++
+		b.WriteString(key.Raw)
+		b.WriteByte(':')
+		b.Write(member.Bytes())
+		return true
+	})
+	b.WriteByte('}')
+
+	var res Document
+	if err = json.Unmarshal(b.Bytes(), &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+type filterer struct {
+	root gjson.Result
+	// selected are the keys of the selected Operations (see operationKey)
+	selected map[string]bool
+	// components are the JSON pointers of the retained components (e.g.
+	// "/components/schemas/Pet")
+	components map[string]bool
+	// tags are the tags of the selected Operations
+	tags map[Text]bool
+}
+
+func (f *filterer) operationKey(webhook bool, key string, method string) string {
+	if webhook {
+		return joinPointerTokens([]string{"webhooks", key, method})
+	}
+	return joinPointerTokens([]string{"paths", key, method})
+}
+
+func (f *filterer) isMethod(key string) bool {
+	switch key {
+	case "get", "put", "post", "delete", "options", "head", "patch", "trace":
+		return true
+	}
+	return false
+}
+
+// collectRoots collects the components referenced by each member of the
+// Document which is retained, aside from components
+func (f *filterer) collectRoots() {
+	f.root.ForEach(func(key, value gjson.Result) bool {
+		switch key.String() {
+		case "components":
+		case "paths", "webhooks":
+			webhook := key.String() == "webhooks"
+			value.ForEach(func(k, item gjson.Result) bool {
+				if !f.retainsPathItem(webhook, k.String(), item) {
+					return true
+				}
+				tokens := []string{key.String(), k.String()}
+				item.ForEach(func(m, v gjson.Result) bool {
+					t := append(tokens[:len(tokens):len(tokens)], m.String())
+					if f.isMethod(m.String()) {
+						if !f.selected[f.operationKey(webhook, k.String(), m.String())] {
+							return true
+						}
+						f.collectSecurity(v.Get("security"))
+					}
+					f.collect(v, t)
+					return true
+				})
+				return true
+			})
+		case "security":
+			f.collectSecurity(value)
+		default:
+			f.collect(value, []string{key.String()})
+		}
+		return true
+	})
+}
+
+// collect collects the components referenced by v, located at tokens
+func (f *filterer) collect(v gjson.Result, tokens []string) {
+	switch {
+	case v.IsObject():
+		v.ForEach(func(key, value gjson.Result) bool {
+			t := append(tokens[:len(tokens):len(tokens)], key.String())
+			switch {
+			case key.String() == "$ref" && value.Type == gjson.String:
+				f.collectRef(value.String())
+			case isSplitDataMember(t, value):
+			case key.String() == "discriminator" && value.IsObject():
+				value.Get("mapping").ForEach(func(_, target gjson.Result) bool {
+					if ref := target.String(); strings.ContainsAny(ref, "#/") {
+						f.collectRef(ref)
+					} else {
+						f.collectRef("#" + joinPointerTokens([]string{"components", "schemas", ref}))
+					}
+					return true
+				})
+			default:
+				f.collect(value, t)
+			}
+			return true
+		})
+	case v.IsArray():
+		for i, value := range v.Array() {
+			f.collect(value, append(tokens[:len(tokens):len(tokens)], fmt.Sprint(i)))
+		}
+	}
+}
+
+// collectRef collects the component referenced by ref, if it is a local
+// reference to a component
+func (f *filterer) collectRef(ref string) {
+	u, err := url.Parse(ref)
+	if err != nil || u.Scheme != "" || u.Host != "" || u.Path != "" {
+		return
+	}
+	tokens := splitPointerTokens(u.Fragment)
+	if len(tokens) < 3 || tokens[0] != "components" {
+		return
+	}
+	f.collectComponent(tokens[1], tokens[2])
+}
+
+// collectSecurity collects the security schemes named by the security
+// requirements of v
+func (f *filterer) collectSecurity(v gjson.Result) {
+	v.ForEach(func(_, req gjson.Result) bool {
+		req.ForEach(func(name, _ gjson.Result) bool {
+			f.collectComponent("securitySchemes", name.String())
+			return true
+		})
+		return true
+	})
+}
+
+func (f *filterer) collectComponent(kind string, name string) {
+	tokens := []string{"components", kind, name}
+	ptr := joinPointerTokens(tokens)
+	if f.components[ptr] {
+		return
+	}
+	v := f.root.Get("components." + gjsonEscape(kind) + "." + gjsonEscape(name))
+	if !v.Exists() {
+		return
+	}
+	f.components[ptr] = true
+	f.collect(v, tokens)
+}
+
+// retainsPathItem reports whether the PathItem item at key has a selected
+// Operation
+func (f *filterer) retainsPathItem(webhook bool, key string, item gjson.Result) bool {
+	retained := false
+	item.ForEach(func(m, _ gjson.Result) bool {
+		retained = f.isMethod(m.String()) && f.selected[f.operationKey(webhook, key, m.String())]
+		return !retained
+	})
+	return retained
+}
+
+// writePathItems writes the retained PathItems of the object v, along with
+// its extensions, to b, returning the number of PathItems written
+func (f *filterer) writePathItems(b *bytes.Buffer, v gjson.Result, webhook bool) int {
+	n := 0
+	b.WriteByte('{')
+	v.ForEach(func(key, item gjson.Result) bool {
+		ext := IsExtensionKey(Text(key.String()))
+		if !ext && !f.retainsPathItem(webhook, key.String(), item) {
+			return true
+		}
+		if b.Len() > 1 {
+			b.WriteByte(',')
+		}
+		b.WriteString(key.Raw)
+		b.WriteByte(':')
+		if ext {
+			b.WriteString(item.Raw)
+			return true
+		}
+		n++
+		b.WriteByte('{')
+		i := 0
+		item.ForEach(func(m, op gjson.Result) bool {
+			if f.isMethod(m.String()) && !f.selected[f.operationKey(webhook, key.String(), m.String())] {
+				return true
+			}
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			i++
+			b.WriteString(m.Raw)
+			b.WriteByte(':')
+			b.WriteString(op.Raw)
+			return true
+		})
+		b.WriteByte('}')
+		return true
+	})
+	b.WriteByte('}')
+	return n
+}
+
+// writeComponents writes the retained components of v, along with
+// extensions, to b, returning the number of members written
+func (f *filterer) writeComponents(b *bytes.Buffer, v gjson.Result) int {
+	n := 0
+	b.WriteByte('{')
+	v.ForEach(func(kind, m gjson.Result) bool {
+		member := bytes.Buffer{}
+		if IsExtensionKey(Text(kind.String())) || !m.IsObject() {
+			member.WriteString(m.Raw)
+		} else {
+			member.WriteByte('{')
+			m.ForEach(func(key, value gjson.Result) bool {
+				if !f.components[joinPointerTokens([]string{"components", kind.String(), key.String()})] {
+					return true
+				}
+				if member.Len() > 1 {
+					member.WriteByte(',')
+				}
+				member.WriteString(key.Raw)
+				member.WriteByte(':')
+				member.WriteString(value.Raw)
+				return true
+			})
+			if member.Len() == 1 {
+				return true
+			}
+			member.WriteByte('}')
+		}
+		if n > 0 {
+			b.WriteByte(',')
+		}
+		n++
+		b.WriteString(kind.Raw)
+		b.WriteByte(':')
+		b.Write(member.Bytes())
+		return true
+	})
+	b.WriteByte('}')
+	return n
+}
+
+// writeTags writes the tags of the array v which are used by a selected
+// Operation to b
+func (f *filterer) writeTags(b *bytes.Buffer, v gjson.Result) {
+	b.WriteByte('[')
+	i := 0
+	v.ForEach(func(_, tag gjson.Result) bool {
+		if !f.tags[Text(tag.Get("name").String())] {
+			return true
+		}
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		i++
+		b.WriteString(tag.Raw)
+		return true
+	})
+	b.WriteByte(']')
+}
@@ -0,0 +1,160 @@
+package openapi_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/chanced/openapi"
+)
+
+func TestFilter(t *testing.T) {
+	data := []byte(`{
+		"openapi": "3.1.0",
+		"info": { "title": "filter", "version": "1.0.0" },
+		"tags": [{ "name": "pets" }, { "name": "admin" }],
+		"security": [{ "apiKey": [] }],
+		"paths": {
+			"/pets": {
+				"parameters": [{ "$ref": "#/components/parameters/Limit" }],
+				"get": {
+					"operationId": "listPets",
+					"tags": ["pets"],
+					"responses": {
+						"200": {
+							"description": "ok",
+							"content": { "application/json": { "schema": { "$ref": "#/components/schemas/Pets" } } }
+						}
+					}
+				},
+				"delete": {
+					"operationId": "deletePets",
+					"tags": ["admin"],
+					"security": [{ "oauth": ["admin"] }],
+					"responses": { "204": { "$ref": "#/components/responses/Deleted" } }
+				}
+			},
+			"/users": {
+				"get": {
+					"operationId": "listUsers",
+					"tags": ["admin"],
+					"responses": {
+						"200": {
+							"description": "ok",
+							"content": { "application/json": { "schema": { "$ref": "#/components/schemas/User" } } }
+						}
+					}
+				}
+			},
+			"x-internal": true
+		},
+		"components": {
+			"parameters": {
+				"Limit": { "name": "limit", "in": "query", "schema": { "type": "integer" } }
+			},
+			"responses": {
+				"Deleted": { "description": "deleted" }
+			},
+			"schemas": {
+				"Pets": { "type": "array", "items": { "$ref": "#/components/schemas/Pet" } },
+				"Pet": {
+					"oneOf": [{ "$ref": "#/components/schemas/Cat" }, { "$ref": "#/components/schemas/Dog" }],
+					"discriminator": { "propertyName": "kind", "mapping": { "cat": "Cat", "dog": "#/components/schemas/Dog" } },
+					"example": { "$ref": "#/components/schemas/User" }
+				},
+				"Cat": { "type": "object" },
+				"Dog": { "type": "object" },
+				"User": { "type": "object" }
+			},
+			"securitySchemes": {
+				"apiKey": { "type": "apiKey", "name": "key", "in": "header" },
+				"oauth": { "type": "oauth2", "flows": { "implicit": { "authorizationUrl": "https://example.com/auth", "scopes": { "admin": "admin" } } } }
+			}
+		}
+	}`)
+	doc := loadDocument(t, "https://example.com/filter.json", data)
+
+	res, err := doc.Filter(openapi.OperationSelector{Tags: []openapi.Text{"pets"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ops := res.Operations(); len(ops) != 1 || ops[0].Operation.OperationID != "listPets" {
+		t.Errorf("expected only listPets to be selected, got %v", ops)
+	}
+	if res.Paths.Get("/pets").Parameters == nil {
+		t.Error("expected the parameters of /pets to be retained")
+	}
+	if _, ok := res.Paths.Extensions["x-internal"]; !ok {
+		t.Error("expected the extensions of paths to be retained")
+	}
+	if res.Tags == nil || len(res.Tags.Items) != 1 || res.Tags.Items[0].Name != "pets" {
+		t.Errorf("expected only the pets tag to be retained, got %v", res.Tags)
+	}
+	expected := map[string][]openapi.Text{
+		"schemas":         {"Pets", "Pet", "Cat", "Dog"},
+		"parameters":      {"Limit"},
+		"securitySchemes": {"apiKey"},
+	}
+	c := res.Components
+	for kind, keys := range map[string][]openapi.Text{
+		"schemas":         schemaKeys(c.Schemas),
+		"parameters":      c.Parameters.Keys(),
+		"securitySchemes": c.SecuritySchemes.Keys(),
+	} {
+		if len(keys) != len(expected[kind]) {
+			t.Errorf("expected %s %v, got %v", kind, expected[kind], keys)
+			continue
+		}
+		for i, k := range keys {
+			if k != expected[kind][i] {
+				t.Errorf("expected %s %v, got %v", kind, expected[kind], keys)
+				break
+			}
+		}
+	}
+	if c.Responses != nil {
+		t.Error("expected unreferenced responses to be removed")
+	}
+	if doc.Components.Schemas.Get("User") == nil || len(doc.Operations()) != 3 {
+		t.Error("expected the Document to be unmodified")
+	}
+
+	res, err = doc.Filter(openapi.OperationSelector{
+		OperationIDs: []openapi.Text{"deletePets"},
+		Paths:        []string{"/u*"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ops := res.Operations(); len(ops) != 2 || ops[0].Operation.OperationID != "deletePets" || ops[1].Operation.OperationID != "listUsers" {
+		t.Errorf("expected deletePets and listUsers to be selected, got %v", ops)
+	}
+	if res.Components.Responses.Get("Deleted") == nil || res.Components.SecuritySchemes.Get("oauth") == nil {
+		t.Error("expected the components of deletePets to be retained")
+	}
+	if res.Components.Schemas.Get("Pet") != nil || res.Components.Schemas.Get("User") == nil {
+		t.Errorf("expected only User to be retained, got %v", schemaKeys(res.Components.Schemas))
+	}
+	v, err := openapi.NewOfflineValidator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, err = res.MarshalJSON(); err != nil {
+		t.Fatal(err)
+	}
+	fn := fetchResources(map[string][]byte{"https://example.com/filter.json": data})
+	if _, err = openapi.Load(context.Background(), "https://example.com/filter.json", v, fn); err != nil {
+		t.Errorf("expected the filtered Document to be valid: %v", err)
+	}
+
+	if _, err = doc.Filter(openapi.OperationSelector{Paths: []string{"["}}); err == nil {
+		t.Error("expected an error for an invalid pattern")
+	}
+}
+
+func schemaKeys(sm *openapi.SchemaMap) []openapi.Text {
+	var keys []openapi.Text
+	for _, e := range sm.Items {
+		keys = append(keys, e.Key)
+	}
+	return keys
+}
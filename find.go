@@ -0,0 +1,60 @@
+package openapi
+
+// Find returns each Node of the Document, including those of the resources
+// it references, for which match returns true. Nodes are visited
+// depth-first in the order in which they are defined; each Node is visited
+// once, regardless of how many times it is referenced.
+func (d *Document) Find(match func(n Node) bool) []Node {
+	if d == nil {
+		return nil
+	}
+	var res []Node
+	_ = walkNodes(d, func(n node) error {
+		if match(n) {
+			res = append(res, n)
+		}
+		return nil
+	})
+	return res
+}
+
+// SchemasWithFormat returns each Schema of the Document, including those of
+// the resources it references, with the format (e.g. "uuid").
+func (d *Document) SchemasWithFormat(format Text) []*Schema {
+	var res []*Schema
+	for _, n := range d.Find(func(n Node) bool {
+		s, ok := n.(*Schema)
+		return ok && s.Format == format
+	}) {
+		res = append(res, n.(*Schema))
+	}
+	return res
+}
+
+// ParametersIn returns each Parameter of the Document, including those of
+// the resources it references, which is located in "in" (e.g. InQuery).
+func (d *Document) ParametersIn(in In) []*Parameter {
+	var res []*Parameter
+	for _, n := range d.Find(func(n Node) bool {
+		p, ok := n.(*Parameter)
+		return ok && p.In == in
+	}) {
+		res = append(res, n.(*Parameter))
+	}
+	return res
+}
+
+// OperationsWithTag returns each Operation of the Document's Paths and
+// Webhooks which is tagged with tag. See Operations.
+func (d *Document) OperationsWithTag(tag Text) []OperationEntry {
+	var res []OperationEntry
+	for _, op := range d.Operations() {
+		for _, t := range op.Operation.Tags {
+			if t == tag {
+				res = append(res, op)
+				break
+			}
+		}
+	}
+	return res
+}
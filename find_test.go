@@ -0,0 +1,75 @@
+package openapi_test
+
+import (
+	"testing"
+
+	"github.com/chanced/openapi"
+)
+
+func TestFind(t *testing.T) {
+	data := []byte(`{
+		"openapi": "3.1.0",
+		"info": { "title": "find", "version": "1.0.0" },
+		"paths": {
+			"/pets": {
+				"get": {
+					"operationId": "listPets",
+					"tags": ["pets"],
+					"parameters": [{ "$ref": "#/components/parameters/Limit" }],
+					"responses": { "200": { "description": "ok" } }
+				},
+				"delete": {
+					"operationId": "deletePets",
+					"tags": ["pets", "admin"],
+					"parameters": [
+						{ "$ref": "#/components/parameters/Limit" },
+						{ "name": "X-Request-ID", "in": "header", "schema": { "type": "string", "format": "uuid" } }
+					],
+					"responses": { "200": { "description": "ok" } }
+				}
+			},
+			"/pets/{id}": {
+				"parameters": [{ "name": "id", "in": "path", "required": true, "schema": { "$ref": "#/components/schemas/ID" } }],
+				"get": { "operationId": "getPet", "tags": ["pets"], "responses": { "200": { "description": "ok" } } }
+			}
+		},
+		"components": {
+			"parameters": {
+				"Limit": { "name": "limit", "in": "query", "schema": { "type": "integer" } }
+			},
+			"schemas": {
+				"ID": { "type": "string", "format": "uuid" }
+			}
+		}
+	}`)
+	doc := loadDocument(t, "https://example.com/find.json", data)
+
+	ops := doc.Find(func(n openapi.Node) bool { return n.Kind() == openapi.KindOperation })
+	if len(ops) != 3 {
+		t.Errorf("expected 3 operations, got %d", len(ops))
+	}
+
+	schemas := doc.SchemasWithFormat("uuid")
+	if len(schemas) != 2 {
+		t.Fatalf("expected 2 uuid schemas, got %d", len(schemas))
+	}
+	if schemas[1] != doc.Components.Schemas.Get("ID") {
+		t.Error("expected the referenced ID schema to be found")
+	}
+
+	params := doc.ParametersIn(openapi.InQuery)
+	if len(params) != 1 || params[0].Name != "limit" {
+		t.Errorf("expected the limit parameter to be found once, got %d", len(params))
+	}
+	if params := doc.ParametersIn(openapi.InPath); len(params) != 1 || params[0].Name != "id" {
+		t.Errorf("expected the id parameter, got %v", params)
+	}
+
+	admin := doc.OperationsWithTag("admin")
+	if len(admin) != 1 || admin[0].Operation.OperationID != "deletePets" {
+		t.Errorf("expected deletePets to be tagged admin, got %v", admin)
+	}
+	if n := len(doc.OperationsWithTag("pets")); n != 3 {
+		t.Errorf("expected 3 operations tagged pets, got %d", n)
+	}
+}
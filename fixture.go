@@ -0,0 +1,488 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/chanced/jsonx"
+)
+
+// Fixture is a request and response pair of an Operation, for seeding
+// contract tests.
+type Fixture struct {
+	// Name identifies the Fixture: the operationId (or the method and path)
+	// of the Operation, the status of the response, and the name of the
+	// example, if any (e.g. "getPet 200 cat").
+	Name string `json:"name"`
+	// OperationID is the operationId of the Operation, if any.
+	OperationID Text `json:"operationId,omitempty"`
+	// Request to send
+	Request FixtureRequest `json:"request"`
+	// Response expected
+	Response FixtureResponse `json:"response"`
+}
+
+// FixtureRequest is the request of a Fixture.
+type FixtureRequest struct {
+	// Method is the HTTP method of the request (e.g. "GET").
+	Method string `json:"method"`
+	// Path is the path of the request, with its path parameters substituted
+	// and its query parameters appended (e.g. "/pets/42?limit=10").
+	Path string `json:"path"`
+	// Headers of the request, including Content-Type if the request has a
+	// body, Accept if the response has a body, and Cookie if the Operation
+	// has cookie parameters.
+	Headers map[string]string `json:"headers,omitempty"`
+	// Body is the JSON encoded value of the body, if any.
+	Body jsonx.RawMessage `json:"body,omitempty"`
+}
+
+// FixtureResponse is the expected response of a Fixture.
+type FixtureResponse struct {
+	// Status code of the response
+	Status int `json:"status"`
+	// Headers of the response, including Content-Type if the response has a
+	// body and each required header.
+	Headers map[string]string `json:"headers,omitempty"`
+	// Body is the JSON encoded value of the body, if any.
+	Body jsonx.RawMessage `json:"body,omitempty"`
+}
+
+// FixtureOpts configures Document.Fixtures.
+type FixtureOpts struct {
+	// Generate configures the InstanceGenerator used to generate values
+	// which do not have an example.
+	Generate GenerateOpts
+	// MediaType is preferred when choosing among the content of a request
+	// body or response (see MatchContent). If no key matches, the first is
+	// used.
+	//
+	// Defaults to "application/json"
+	MediaType string
+	// OptionalParameters, if true, includes optional parameters without an
+	// example. Optional parameters with an example are always included.
+	OptionalParameters bool
+}
+
+func (opts FixtureOpts) mediaType() string {
+	if opts.MediaType == "" {
+		return "application/json"
+	}
+	return opts.MediaType
+}
+
+// Fixtures returns request and response pairs for each response of each
+// Operation of the Document's Paths, in order, for seeding contract tests.
+//
+// A Fixture is created for each named example of a response's content, or a
+// single Fixture if there are none. The values of the request and response,
+// such as bodies and parameters, are taken from the example of the same name
+// if there is one, then from the first example, example, or the example,
+// examples, or default of the Schema, and are otherwise generated with an
+// InstanceGenerator.
+//
+// Parameters, and required response headers, are serialized with
+// Parameter.Serialize, as they are by the client package. Responses with a
+// range of status codes (e.g. "4XX") use the first code of the range; the
+// default response is skipped.
+//
+// An error wrapping ErrUnresolvedReference is returned if a referenced
+// Parameter, RequestBody, Response, Header, or Example has not been resolved.
+func (d *Document) Fixtures(opts FixtureOpts) ([]Fixture, error) {
+	if d == nil || d.Paths == nil {
+		return nil, nil
+	}
+	fe := &fixtureEmitter{opts: opts, gen: NewInstanceGenerator(opts.Generate)}
+	var fixtures []Fixture
+	for _, item := range d.Paths.Items {
+		for _, mo := range item.Value.Operations() {
+			fs, err := fe.operation(item.Key, item.Value, mo)
+			if err != nil {
+				return nil, err
+			}
+			fixtures = append(fixtures, fs...)
+		}
+	}
+	return fixtures, nil
+}
+
+type fixtureEmitter struct {
+	opts FixtureOpts
+	gen  *InstanceGenerator
+}
+
+func (fe *fixtureEmitter) operation(path Text, pi *PathItem, mo MethodOperation) ([]Fixture, error) {
+	op := mo.Operation
+	params, err := op.EffectiveParameters(pi)
+	if err != nil {
+		return nil, err
+	}
+	var body *RequestBody
+	if op.RequestBody != nil {
+		if !op.RequestBody.IsResolved() {
+			return nil, newErrUnresolvedReference(op.RequestBody.Reference)
+		}
+		body = op.RequestBody.Object
+	}
+	name := op.OperationID.String()
+	if name == "" {
+		name = strings.ToUpper(mo.Method.String()) + " " + path.String()
+	}
+
+	var fixtures []Fixture
+	if op.Responses == nil {
+		return nil, nil
+	}
+	for _, entry := range op.Responses.Items {
+		rc, err := ParseResponseCode(entry.Key)
+		if err != nil || rc.IsDefault() {
+			continue
+		}
+		status := rc.Code
+		if rc.IsRange() {
+			status = int(rc.Class) * 100
+		}
+		c := entry.Component
+		if c == nil {
+			continue
+		}
+		if !c.IsResolved() {
+			return nil, newErrUnresolvedReference(c.Reference)
+		}
+		res := c.Object
+		if res == nil {
+			continue
+		}
+		mediaType, mt := fe.content(res.Content)
+		examples := []Text{""}
+		if mt != nil && mt.Examples != nil && len(mt.Examples.Items) > 0 {
+			examples = mt.Examples.Keys()
+		}
+		for _, example := range examples {
+			f := Fixture{
+				Name:        fmt.Sprintf("%s %d", name, status),
+				OperationID: op.OperationID,
+				Request: FixtureRequest{
+					Method:  strings.ToUpper(mo.Method.String()),
+					Headers: map[string]string{},
+				},
+				Response: FixtureResponse{
+					Status:  status,
+					Headers: map[string]string{},
+				},
+			}
+			if example != "" {
+				f.Name += " " + example.String()
+			}
+			if f.Request.Path, err = fe.request(&f.Request, path, params, body, example); err != nil {
+				return nil, err
+			}
+			if mt != nil {
+				if f.Response.Body, err = fe.value(mt.Schema, mt.Example, mt.Examples, example); err != nil {
+					return nil, err
+				}
+				f.Response.Headers["Content-Type"] = mediaType
+				f.Request.Headers["Accept"] = mediaType
+			}
+			if err = fe.responseHeaders(&f.Response, res, example); err != nil {
+				return nil, err
+			}
+			if len(f.Request.Headers) == 0 {
+				f.Request.Headers = nil
+			}
+			if len(f.Response.Headers) == 0 {
+				f.Response.Headers = nil
+			}
+			fixtures = append(fixtures, f)
+		}
+	}
+	return fixtures, nil
+}
+
+// content returns the media type and MediaType of content to use
+func (fe *fixtureEmitter) content(content *ContentMap) (string, *MediaType) {
+	if content == nil || len(content.Items) == 0 {
+		return "", nil
+	}
+	key, mt, ok := MatchContent(content, fe.opts.mediaType())
+	if !ok {
+		key, mt = content.Items[0].Key, content.Items[0].Value
+	}
+	if strings.Contains(key.String(), "*") {
+		// a range, such as "application/*", is not a valid Content-Type
+		if !ok {
+			return "application/octet-stream", mt
+		}
+		return fe.opts.mediaType(), mt
+	}
+	return key.String(), mt
+}
+
+// request sets the headers and body of r, returning its path
+func (fe *fixtureEmitter) request(r *FixtureRequest, path Text, params []*Parameter, body *RequestBody, example Text) (string, error) {
+	p := path.String()
+	var query, cookies []string
+	for _, param := range params {
+		v, ok, err := fe.parameter(param, example)
+		if err != nil {
+			return "", err
+		}
+		if !ok {
+			continue
+		}
+		s, err := param.Serialize(v)
+		if err != nil {
+			return "", NewError(err, param.AbsoluteLocation())
+		}
+		switch param.In {
+		case InPath:
+			p = strings.ReplaceAll(p, "{"+param.Name.String()+"}", s)
+		case InQuery:
+			query = append(query, s)
+		case InHeader:
+			r.Headers[param.Name.String()] = s
+		case InCookie:
+			cookies = append(cookies, s)
+		}
+	}
+	if len(cookies) > 0 {
+		r.Headers["Cookie"] = strings.Join(cookies, "; ")
+	}
+	if len(query) > 0 {
+		p += "?" + strings.Join(query, "&")
+	}
+	if body == nil {
+		return p, nil
+	}
+	mediaType, mt := fe.content(body.Content)
+	if mt == nil {
+		return p, nil
+	}
+	b, err := fe.value(mt.Schema, mt.Example, mt.Examples, example)
+	if err != nil {
+		return "", err
+	}
+	r.Body = b
+	r.Headers["Content-Type"] = mediaType
+	return p, nil
+}
+
+// parameter returns the value of param, decoded with
+// json.Decoder.UseNumber. ok is false if param is optional and has no example
+// unless FixtureOpts.OptionalParameters is set.
+func (fe *fixtureEmitter) parameter(param *Parameter, example Text) (v interface{}, ok bool, err error) {
+	schema, ex, examples := param.Schema, param.Example, param.Examples
+	if param.Content != nil && len(param.Content.Items) > 0 {
+		_, mt := fe.content(param.Content)
+		schema, ex, examples = mt.Schema, mt.Example, mt.Examples
+	}
+	required := param.In == InPath || (param.Required != nil && *param.Required)
+	if !required && !fe.opts.OptionalParameters && ex == nil && (examples == nil || len(examples.Items) == 0) && !hasSchemaExample(schema) {
+		return nil, false, nil
+	}
+	data, err := fe.value(schema, ex, examples, example)
+	if err != nil {
+		return nil, false, err
+	}
+	if param.Content != nil && len(param.Content.Items) > 0 {
+		// the value is serialized as the media type of the content
+		return json.RawMessage(data), true, nil
+	}
+	v, _ = decodeInstance(data)
+	return v, true, nil
+}
+
+func (fe *fixtureEmitter) responseHeaders(r *FixtureResponse, res *Response, example Text) error {
+	if res.Headers == nil {
+		return nil
+	}
+	for _, entry := range res.Headers.Items {
+		if strings.EqualFold(entry.Key.String(), "Content-Type") || entry.Component == nil {
+			continue
+		}
+		if !entry.Component.IsResolved() {
+			return newErrUnresolvedReference(entry.Component.Reference)
+		}
+		h := entry.Component.Object
+		if h == nil || h.Required == nil || !*h.Required {
+			continue
+		}
+		data, err := fe.value(h.Schema, h.Example, h.Examples, example)
+		if err != nil {
+			return err
+		}
+		v, _ := decodeInstance(data)
+		// a Header follows the rules of a header Parameter
+		param := &Parameter{Name: entry.Key, In: InHeader, Style: h.Style, Explode: h.Explode}
+		s, err := param.Serialize(v)
+		if err != nil {
+			return NewError(err, h.AbsoluteLocation())
+		}
+		r.Headers[entry.Key.String()] = s
+	}
+	return nil
+}
+
+// value returns the JSON encoded value described by the example of the name
+// example, the first of examples, ex, the examples of schema, or a generated
+// instance of schema, in that order
+func (fe *fixtureEmitter) value(schema *Schema, ex jsonx.RawMessage, examples *ExampleMap, example Text) (jsonx.RawMessage, error) {
+	if examples != nil && len(examples.Items) > 0 {
+		c := examples.Items[0].Component
+		for _, entry := range examples.Items {
+			if entry.Key == example {
+				c = entry.Component
+				break
+			}
+		}
+		if c != nil {
+			if !c.IsResolved() {
+				return nil, newErrUnresolvedReference(c.Reference)
+			}
+			return c.Object.value()
+		}
+	}
+	if ex != nil {
+		return ex, nil
+	}
+	if data := schemaExampleData(schema); data != nil {
+		return data, nil
+	}
+	v, err := fe.gen.Generate(schema)
+	if err != nil {
+		return nil, NewError(err, schema.AbsoluteLocation())
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// schemaExampleData returns the example, first of examples, or default of s,
+// following a resolved $ref
+func schemaExampleData(s *Schema) jsonx.RawMessage {
+	for i := 0; s != nil && i < maxEffectiveDepth; i++ {
+		switch {
+		case s.Example != nil:
+			return s.Example
+		case len(s.Examples) > 0:
+			return s.Examples[0]
+		case s.Default != nil:
+			return s.Default
+		}
+		if s.Ref == nil {
+			return nil
+		}
+		s = s.Ref.Resolved
+	}
+	return nil
+}
+
+func hasSchemaExample(s *Schema) bool {
+	return schemaExampleData(s) != nil
+}
+
+// FixtureFiles encodes fixtures as indented JSON, with the Fixtures of each
+// Operation written to their own file named for its operationId or, if it
+// has none, its method and path (e.g. "getPet.json", "POST_pets.json").
+// The files are keyed by name.
+func FixtureFiles(fixtures []Fixture) (map[string][]byte, error) {
+	files := map[string][]Fixture{}
+	var order []string
+	for _, f := range fixtures {
+		name := f.OperationID.String()
+		if name == "" {
+			name = f.Request.Method + strings.SplitN(f.Name, " ", 3)[1]
+		}
+		file := splitFileName(name) + ".json"
+		if _, ok := files[file]; !ok {
+			order = append(order, file)
+		}
+		files[file] = append(files[file], f)
+	}
+	res := make(map[string][]byte, len(files))
+	for _, file := range order {
+		data, err := json.MarshalIndent(files[file], "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		res[file] = append(data, '\n')
+	}
+	return res, nil
+}
+
+// FixturesGo returns Go source code of the package pkg which declares the
+// variable name as a table of the fixtures, for use in table-driven tests.
+// The table is a slice of structs with the fields Name, OperationID, Method,
+// Path, RequestHeaders, RequestBody, Status, ResponseHeaders, and
+// ResponseBody, where bodies are JSON strings. The source does not depend on
+// this package.
+func FixturesGo(pkg string, name string, fixtures []Fixture) ([]byte, error) {
+	if !token.IsIdentifier(pkg) {
+		return nil, fmt.Errorf("openapi: invalid package name %q", pkg)
+	}
+	if !token.IsIdentifier(name) {
+		return nil, fmt.Errorf("openapi: invalid variable name %q", name)
+	}
+	var b bytes.Buffer
+	b.WriteString("// Code generated by github.com/chanced/openapi. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+	fmt.Fprintf(&b, "var %s = []struct {\n", name)
+	b.WriteString("Name string\nOperationID string\nMethod string\nPath string\n")
+	b.WriteString("RequestHeaders map[string]string\nRequestBody string\n")
+	b.WriteString("Status int\nResponseHeaders map[string]string\nResponseBody string\n")
+	b.WriteString("}{\n")
+	for _, f := range fixtures {
+		b.WriteString("{\n")
+		fmt.Fprintf(&b, "Name: %s,\n", strconv.Quote(f.Name))
+		if f.OperationID != "" {
+			fmt.Fprintf(&b, "OperationID: %s,\n", strconv.Quote(f.OperationID.String()))
+		}
+		fmt.Fprintf(&b, "Method: %s,\n", strconv.Quote(f.Request.Method))
+		fmt.Fprintf(&b, "Path: %s,\n", strconv.Quote(f.Request.Path))
+		writeGoHeaders(&b, "RequestHeaders", f.Request.Headers)
+		if f.Request.Body != nil {
+			fmt.Fprintf(&b, "RequestBody: %s,\n", goStringLiteral(string(f.Request.Body)))
+		}
+		fmt.Fprintf(&b, "Status: %d,\n", f.Response.Status)
+		writeGoHeaders(&b, "ResponseHeaders", f.Response.Headers)
+		if f.Response.Body != nil {
+			fmt.Fprintf(&b, "ResponseBody: %s,\n", goStringLiteral(string(f.Response.Body)))
+		}
+		b.WriteString("},\n")
+	}
+	b.WriteString("}\n")
+	return format.Source(b.Bytes())
+}
+
+func writeGoHeaders(b *bytes.Buffer, field string, headers map[string]string) {
+	if len(headers) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(headers))
+	for k := range headers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	fmt.Fprintf(b, "%s: map[string]string{\n", field)
+	for _, k := range keys {
+		fmt.Fprintf(b, "%s: %s,\n", strconv.Quote(k), strconv.Quote(headers[k]))
+	}
+	b.WriteString("},\n")
+}
+
+// goStringLiteral returns s as a raw string literal if possible, as JSON is
+// more legible without escapes, or otherwise as an interpreted string literal
+func goStringLiteral(s string) string {
+	if strconv.CanBackquote(s) {
+		return "`" + s + "`"
+	}
+	return strconv.Quote(s)
+}
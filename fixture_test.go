@@ -0,0 +1,193 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/chanced/openapi"
+)
+
+var fixtureData = []byte(`{
+	"openapi": "3.1.0",
+	"info": { "title": "Pet Store", "version": "1.0.0" },
+	"paths": {
+		"/pets/{petId}": {
+			"parameters": [{ "name": "petId", "in": "path", "required": true, "schema": { "type": "integer", "minimum": 1 }, "example": 42 }],
+			"get": {
+				"operationId": "getPet",
+				"parameters": [
+					{ "name": "fields", "in": "query", "schema": { "type": "array", "items": { "type": "string" } }, "example": ["name", "tag"] },
+					{ "name": "verbose", "in": "query", "schema": { "type": "boolean" } },
+					{ "name": "X-Request-ID", "in": "header", "required": true, "schema": { "type": "string", "format": "uuid" } }
+				],
+				"responses": {
+					"200": {
+						"description": "a pet",
+						"headers": {
+							"X-Rate-Limit": { "required": true, "schema": { "type": "integer" }, "example": 100 }
+						},
+						"content": {
+							"application/json": {
+								"schema": { "$ref": "#/components/schemas/Pet" },
+								"examples": {
+									"cat": { "value": { "id": 42, "name": "Tom" } },
+									"dog": { "value": { "id": 42, "name": "Spike" } }
+								}
+							}
+						}
+					},
+					"404": { "description": "not found" },
+					"default": { "description": "error" }
+				}
+			}
+		},
+		"/pets": {
+			"post": {
+				"requestBody": {
+					"required": true,
+					"content": { "application/json": { "schema": { "$ref": "#/components/schemas/Pet" } } }
+				},
+				"responses": {
+					"2XX": {
+						"description": "created",
+						"content": { "application/json": { "schema": { "$ref": "#/components/schemas/Pet" } } }
+					}
+				}
+			}
+		}
+	},
+	"components": {
+		"schemas": {
+			"Pet": {
+				"type": "object",
+				"required": ["id", "name"],
+				"properties": {
+					"id": { "type": "integer", "minimum": 1 },
+					"name": { "type": "string", "minLength": 1 }
+				}
+			}
+		}
+	}
+}`)
+
+func TestDocumentFixtures(t *testing.T) {
+	doc := loadDocument(t, "https://example.com/openapi.json", fixtureData)
+	fixtures, err := doc.Fixtures(openapi.FixtureOpts{Generate: openapi.GenerateOpts{Seed: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, f := range fixtures {
+		names = append(names, f.Name)
+	}
+	expected := []string{"getPet 200 cat", "getPet 200 dog", "getPet 404", "POST /pets 200"}
+	if strings.Join(names, ", ") != strings.Join(expected, ", ") {
+		t.Fatalf("expected fixtures %v, got %v", expected, names)
+	}
+
+	cat := fixtures[0]
+	if cat.Request.Method != "GET" {
+		t.Errorf("expected method GET, got %q", cat.Request.Method)
+	}
+	if cat.Request.Path != "/pets/42?fields=name&fields=tag" {
+		t.Errorf("expected the path and query to be substituted, got %q", cat.Request.Path)
+	}
+	if cat.Request.Headers["X-Request-ID"] == "" {
+		t.Errorf("expected the required header to be generated, got %v", cat.Request.Headers)
+	}
+	if cat.Request.Headers["Accept"] != "application/json" {
+		t.Errorf("expected Accept to be application/json, got %v", cat.Request.Headers)
+	}
+	if cat.Response.Status != 200 {
+		t.Errorf("expected status 200, got %d", cat.Response.Status)
+	}
+	if string(cat.Response.Body) != `{"id":42,"name":"Tom"}` {
+		t.Errorf("expected the body of the cat example, got %s", cat.Response.Body)
+	}
+	if cat.Response.Headers["X-Rate-Limit"] != "100" {
+		t.Errorf("expected the required response header, got %v", cat.Response.Headers)
+	}
+
+	notFound := fixtures[2]
+	if notFound.Response.Body != nil || notFound.Response.Headers != nil {
+		t.Errorf("expected no body or headers for 404, got %+v", notFound.Response)
+	}
+
+	create := fixtures[3]
+	if create.Request.Headers["Content-Type"] != "application/json" {
+		t.Errorf("expected Content-Type to be application/json, got %v", create.Request.Headers)
+	}
+	for _, body := range []json.RawMessage{json.RawMessage(create.Request.Body), json.RawMessage(create.Response.Body)} {
+		var pet struct {
+			ID   int    `json:"id"`
+			Name string `json:"name"`
+		}
+		if err := json.Unmarshal(body, &pet); err != nil {
+			t.Fatal(err)
+		}
+		if pet.ID < 1 || pet.Name == "" {
+			t.Errorf("expected a generated pet, got %s", body)
+		}
+	}
+
+	fixtures, err = doc.Fixtures(openapi.FixtureOpts{OptionalParameters: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(fixtures[0].Request.Path, "verbose=") {
+		t.Errorf("expected the optional parameter to be included, got %q", fixtures[0].Request.Path)
+	}
+}
+
+func TestFixturesGo(t *testing.T) {
+	doc := loadDocument(t, "https://example.com/openapi.json", fixtureData)
+	fixtures, err := doc.Fixtures(openapi.FixtureOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, err := openapi.FixturesGo("petstore", "fixtures", fixtures)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), "fixtures.go", src, 0); err != nil {
+		t.Fatalf("expected valid Go source, got %v:\n%s", err, src)
+	}
+	for _, s := range []string{"package petstore", "var fixtures = []struct", `"getPet 200 cat"`, "`{\"id\":42,\"name\":\"Tom\"}`"} {
+		if !strings.Contains(string(src), s) {
+			t.Errorf("expected the source to contain %q:\n%s", s, src)
+		}
+	}
+	if _, err := openapi.FixturesGo("pet-store", "fixtures", fixtures); err == nil {
+		t.Error("expected an error for an invalid package name")
+	}
+}
+
+func TestFixtureFiles(t *testing.T) {
+	doc := loadDocument(t, "https://example.com/openapi.json", fixtureData)
+	fixtures, err := doc.Fixtures(openapi.FixtureOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	files, err := openapi.FixtureFiles(fixtures)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("expected a file for each operation, got %d", len(files))
+	}
+	var getPet []openapi.Fixture
+	if err := json.Unmarshal(files["getPet.json"], &getPet); err != nil {
+		t.Fatal(err)
+	}
+	if len(getPet) != 3 {
+		t.Errorf("expected 3 fixtures for getPet, got %d", len(getPet))
+	}
+	for name := range files {
+		if name != "getPet.json" && name != "POST_pets.json" {
+			t.Errorf("unexpected file %q", name)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package openapi
+
+import (
+	"net/mail"
+	"net/url"
+	"regexp"
+	"time"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+var uuidRegexp = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// FormatRegistry is a registry of functions which validate values of the
+// "format" keyword, keyed by format name.
+//
+// Functions should only check values of the types they are applicable to and
+// return true for all others (e.g. a string format should permit numbers).
+type FormatRegistry struct {
+	formats map[Text]func(v interface{}) bool
+}
+
+// NewFormatRegistry creates a new FormatRegistry with the following formats
+// registered:
+//   - uuid
+//   - date-time
+//   - email
+//   - uri
+func NewFormatRegistry() *FormatRegistry {
+	return &FormatRegistry{
+		formats: map[Text]func(v interface{}) bool{
+			"uuid":      isUUID,
+			"date-time": isDateTime,
+			"email":     isEmail,
+			"uri":       isURI,
+		},
+	}
+}
+
+// Register adds or replaces the format name.
+func (fr *FormatRegistry) Register(name Text, fn func(v interface{}) bool) {
+	if fr.formats == nil {
+		fr.formats = map[Text]func(v interface{}) bool{}
+	}
+	fr.formats[name] = fn
+}
+
+// Lookup returns the function registered for the format name.
+func (fr *FormatRegistry) Lookup(name Text) (func(v interface{}) bool, bool) {
+	if fr == nil {
+		return nil, false
+	}
+	fn, ok := fr.formats[name]
+	return fn, ok
+}
+
+// IsValid reports whether v is valid for the format name. Unknown formats are
+// considered valid.
+func (fr *FormatRegistry) IsValid(name Text, v interface{}) bool {
+	fn, ok := fr.Lookup(name)
+	if !ok {
+		return true
+	}
+	return fn(v)
+}
+
+// AssertFormats configures compiler to treat the "format" keyword as an
+// assertion, validating values with the formats of fr rather than those
+// built into the compiler. Formats which are not registered with fr are
+// treated as annotations.
+//
+// AssertFormats must be called prior to compiling schemas, e.g. before
+// NewValidator or CompileSchemas, in order to apply to document validation.
+func (fr *FormatRegistry) AssertFormats(compiler *jsonschema.Compiler) {
+	compiler.AssertFormat = false
+	compiler.RegisterExtension("openapi-format", nil, formatExtension{fr})
+}
+
+// formatExtension adapts a FormatRegistry to the extension interfaces of
+// github.com/santhosh-tekuri/jsonschema/v5
+type formatExtension struct {
+	formats *FormatRegistry
+}
+
+func (fe formatExtension) Compile(ctx jsonschema.CompilerContext, m map[string]interface{}) (jsonschema.ExtSchema, error) {
+	f, ok := m["format"].(string)
+	if !ok {
+		return nil, nil
+	}
+	fn, ok := fe.formats.Lookup(Text(f))
+	if !ok {
+		return nil, nil
+	}
+	return formatSchema{format: f, fn: fn}, nil
+}
+
+type formatSchema struct {
+	format string
+	fn     func(v interface{}) bool
+}
+
+func (fs formatSchema) Validate(ctx jsonschema.ValidationContext, v interface{}) error {
+	if !fs.fn(v) {
+		return ctx.Error("format", "%v is not valid %q", v, fs.format)
+	}
+	return nil
+}
+
+func isUUID(v interface{}) bool {
+	s, ok := v.(string)
+	if !ok {
+		return true
+	}
+	return uuidRegexp.MatchString(s)
+}
+
+func isDateTime(v interface{}) bool {
+	s, ok := v.(string)
+	if !ok {
+		return true
+	}
+	_, err := time.Parse(time.RFC3339Nano, s)
+	return err == nil
+}
+
+func isEmail(v interface{}) bool {
+	s, ok := v.(string)
+	if !ok {
+		return true
+	}
+	a, err := mail.ParseAddress(s)
+	return err == nil && a.Name == "" && a.Address == s
+}
+
+func isURI(v interface{}) bool {
+	s, ok := v.(string)
+	if !ok {
+		return true
+	}
+	u, err := url.Parse(s)
+	return err == nil && u.IsAbs()
+}
@@ -0,0 +1,61 @@
+package openapi_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/chanced/openapi"
+)
+
+func TestFormatRegistry(t *testing.T) {
+	fr := openapi.NewFormatRegistry()
+	tests := []struct {
+		format openapi.Text
+		value  interface{}
+		valid  bool
+	}{
+		{"uuid", "3fa85f64-5717-4562-b3fc-2c963f66afa6", true},
+		{"uuid", "3fa85f64", false},
+		{"date-time", "2022-10-16T04:53:38Z", true},
+		{"date-time", "2022-10-16", false},
+		{"email", "user@example.com", true},
+		{"email", "user", false},
+		{"uri", "https://example.com/path", true},
+		{"uri", "/path", false},
+		{"uri", 42, true},
+		{"unknown", "anything", true},
+	}
+	for _, test := range tests {
+		if v := fr.IsValid(test.format, test.value); v != test.valid {
+			t.Errorf("expected IsValid(%q, %v) to be %t", test.format, test.value, test.valid)
+		}
+	}
+
+	fr.Register("upper", func(v interface{}) bool {
+		s, ok := v.(string)
+		return !ok || strings.ToUpper(s) == s
+	})
+	var s openapi.Schema
+	if err := json.Unmarshal([]byte(`{ "type": "string", "format": "upper" }`), &s); err != nil {
+		t.Fatal(err)
+	}
+	cs, err := s.Compile(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = cs.Validate("lower"); err != nil {
+		t.Errorf("expected format to be an annotation by default, got %v", err)
+	}
+	cs, err = s.Compile(context.Background(), openapi.CompileOpts{AssertFormat: true, Formats: fr})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = cs.Validate("UPPER"); err != nil {
+		t.Errorf("expected %q to be valid, got %v", "UPPER", err)
+	}
+	if err = cs.Validate("lower"); err == nil {
+		t.Errorf("expected %q to be invalid", "lower")
+	}
+}
@@ -0,0 +1,189 @@
+package openapi
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// FrozenDocument is a read-only snapshot of a Document, created with
+// Document.Freeze. A FrozenDocument is safe for concurrent use by multiple
+// goroutines (e.g. request validators of an HTTP server).
+//
+// The snapshot is a deep copy; subsequent changes to the Document it was
+// created from are not reflected.
+type FrozenDocument struct {
+	doc        *Document
+	data       []byte
+	checksum   string
+	operations []OperationEntry
+	byID       map[Text]int
+}
+
+// Freeze returns a FrozenDocument, a read-only snapshot of the Document. The
+// Document, including the targets of all resolved references, is deep
+// copied.
+func (d *Document) Freeze() (*FrozenDocument, error) {
+	if d == nil {
+		return nil, fmt.Errorf("openapi: cannot freeze nil Document")
+	}
+	doc, err := d.clone()
+	if err != nil {
+		return nil, err
+	}
+	data, err := doc.MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("openapi: failed to marshal document: %w", err)
+	}
+	checksum, err := doc.Checksum()
+	if err != nil {
+		return nil, err
+	}
+	f := &FrozenDocument{
+		doc:        doc,
+		data:       data,
+		checksum:   checksum,
+		operations: doc.Operations(),
+		byID:       map[Text]int{},
+	}
+	for i, op := range f.operations {
+		if id := op.Operation.OperationID; id != "" {
+			if _, ok := f.byID[id]; !ok {
+				f.byID[id] = i
+			}
+		}
+	}
+	return f, nil
+}
+
+// Document returns the underlying Document of the snapshot.
+//
+// The Document, and every node reachable from it, is shared by all users of
+// the FrozenDocument and MUST NOT be modified. Use Thaw to obtain a Document
+// which can be modified.
+func (f *FrozenDocument) Document() *Document {
+	return f.doc
+}
+
+// Thaw returns a deep copy of the snapshot which may be modified.
+func (f *FrozenDocument) Thaw() (*Document, error) {
+	return f.doc.clone()
+}
+
+// MarshalJSON returns the JSON encoding of the snapshot.
+func (f *FrozenDocument) MarshalJSON() ([]byte, error) {
+	data := make([]byte, len(f.data))
+	copy(data, f.data)
+	return data, nil
+}
+
+// Checksum returns the Checksum of the snapshot.
+func (f *FrozenDocument) Checksum() string {
+	return f.checksum
+}
+
+// Operations returns each Operation of the snapshot, in the order of
+// Document.Operations.
+//
+// The returned slice may be modified, but the nodes it references MUST NOT.
+func (f *FrozenDocument) Operations() []OperationEntry {
+	ops := make([]OperationEntry, len(f.operations))
+	copy(ops, f.operations)
+	return ops
+}
+
+// Operation returns the Operation with the given operationId. If more than
+// one Operation shares the id, the first in the order of Operations is
+// returned.
+func (f *FrozenDocument) Operation(id Text) (OperationEntry, bool) {
+	i, ok := f.byID[id]
+	if !ok {
+		return OperationEntry{}, false
+	}
+	return f.operations[i], true
+}
+
+// clone returns a deep copy of the Document. The targets of resolved
+// references which are not within the Document are copied as well.
+func (d *Document) clone() (*Document, error) {
+	c := cloner{clones: map[node]node{}}
+	n, err := c.clone(d)
+	if err != nil {
+		return nil, err
+	}
+	return n.(*Document), nil
+}
+
+type cloner struct {
+	clones map[node]node
+}
+
+// clone returns a deep copy of n. Each node is only copied once so that
+// cyclic references are preserved.
+func (c *cloner) clone(n node) (node, error) {
+	if cn, ok := c.clones[n]; ok {
+		return cn, nil
+	}
+	data, err := n.MarshalJSON()
+	if err != nil {
+		return nil, NewError(fmt.Errorf("openapi: failed to marshal %s: %w", n.Kind(), err), n.AbsoluteLocation())
+	}
+	t := reflect.TypeOf(n)
+	if t.Kind() != reflect.Ptr {
+		return nil, NewError(fmt.Errorf("openapi: can not copy %s", n.Kind()), n.AbsoluteLocation())
+	}
+	cn, ok := reflect.New(t.Elem()).Interface().(node)
+	if !ok {
+		return nil, NewError(fmt.Errorf("openapi: can not copy %s", n.Kind()), n.AbsoluteLocation())
+	}
+	if err = cn.UnmarshalJSON(data); err != nil {
+		return nil, NewError(fmt.Errorf("openapi: failed to unmarshal %s: %w", n.Kind(), err), n.AbsoluteLocation())
+	}
+	if err = cn.setLocation(n.location()); err != nil {
+		return nil, NewError(err, n.AbsoluteLocation())
+	}
+	c.clones[n] = cn
+	if err = c.resolveRefs(cn, n); err != nil {
+		return nil, err
+	}
+	return cn, nil
+}
+
+// resolveRefs resolves each reference of dst, a copy of src, to the copy of
+// the target of the corresponding reference in src. Targets within dst are
+// resolved to the node in dst; all others are copied.
+func (c *cloner) resolveRefs(dst, src node) error {
+	refs := map[string]Ref{}
+	for _, r := range src.Refs() {
+		refs[r.AbsoluteLocation().String()] = r
+	}
+	local := map[string]node{}
+	_ = walkLocalNodes(dst, func(n node) error {
+		local[n.AbsoluteLocation().String()] = n
+		return nil
+	})
+	for _, r := range dst.Refs() {
+		rr, ok := r.(ref)
+		if !ok {
+			continue
+		}
+		p, ok := refs[r.AbsoluteLocation().String()]
+		if !ok {
+			continue
+		}
+		target, ok := resolvedNode(p)
+		if !ok {
+			continue
+		}
+		n, ok := local[target.AbsoluteLocation().String()]
+		if !ok || n.Kind() != target.Kind() {
+			var err error
+			if n, err = c.clone(target); err != nil {
+				return err
+			}
+		}
+		if err := rr.resolve(n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package openapi_test
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestFreeze(t *testing.T) {
+	data := []byte(`{
+		"openapi": "3.1.0",
+		"info": { "title": "frozen", "version": "1.0.0" },
+		"paths": {
+			"/pets": {
+				"get": {
+					"operationId": "listPets",
+					"responses": {
+						"200": {
+							"description": "ok",
+							"content": {
+								"application/json": { "schema": { "$ref": "#/components/schemas/Pet" } }
+							}
+						}
+					}
+				}
+			}
+		},
+		"components": {
+			"schemas": {
+				"Pet": { "type": "object" }
+			}
+		}
+	}`)
+	doc := loadDocument(t, "https://example.com/frozen.json", data)
+	checksum, err := doc.Checksum()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := doc.Freeze()
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc.Info.Title = "thawed"
+	doc.Components.Schemas.Get("Pet").Format = "changed"
+
+	if f.Checksum() != checksum {
+		t.Error("expected snapshot to be unaffected by changes to the Document")
+	}
+	frozen := f.Document()
+	if frozen.Info.Title != "frozen" {
+		t.Errorf("expected title to be \"frozen\", got %q", frozen.Info.Title)
+	}
+	pet := frozen.Components.Schemas.Get("Pet")
+	if pet == doc.Components.Schemas.Get("Pet") {
+		t.Error("expected schemas to be copied")
+	}
+	if loc := pet.AbsoluteLocation(); loc.String() != "https://example.com/frozen.json#/components/schemas/Pet" {
+		t.Errorf("expected location to be retained, got %s", loc.String())
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			op, ok := f.Operation("listPets")
+			if !ok {
+				t.Error("expected operation listPets")
+				return
+			}
+			ref := op.Operation.Responses.Get("200").Object.Content.Get("application/json").Schema.Ref
+			if ref.Resolved != pet {
+				t.Error("expected $ref to be resolved to the copied schema")
+			}
+		}()
+	}
+	wg.Wait()
+
+	thawed, err := f.Thaw()
+	if err != nil {
+		t.Fatal(err)
+	}
+	thawed.Info.Title = "thawed"
+	if f.Document().Info.Title != "frozen" {
+		t.Error("expected snapshot to be unaffected by changes to a thawed Document")
+	}
+}
@@ -0,0 +1,496 @@
+// Package har scaffolds OpenAPI Documents from HAR (HTTP Archive) captures.
+//
+// Import groups the entries of a HAR log by method and path, templating path
+// segments which appear to be identifiers (e.g. "/pets/42" becomes
+// "/pets/{petId}"), and infers the Schemas of query parameters, request
+// bodies, and responses from the captured values. The resulting Document is
+// a starting point for documenting a service and is expected to be refined
+// by hand.
+package har
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Masterminds/semver"
+	"github.com/chanced/openapi"
+)
+
+// HAR is an HTTP Archive.
+//
+// Only the fields used by Import are modeled.
+type HAR struct {
+	Log Log `json:"log"`
+}
+
+// Log is the log of a HAR.
+type Log struct {
+	Entries []Entry `json:"entries"`
+}
+
+// Entry is an exchanged request and response.
+type Entry struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is the request of an Entry.
+type Request struct {
+	Method      string    `json:"method"`
+	URL         string    `json:"url"`
+	Headers     []NVP     `json:"headers"`
+	QueryString []NVP     `json:"queryString"`
+	PostData    *PostData `json:"postData,omitempty"`
+}
+
+// Response is the response of an Entry.
+type Response struct {
+	Status     int     `json:"status"`
+	StatusText string  `json:"statusText"`
+	Headers    []NVP   `json:"headers"`
+	Content    Content `json:"content"`
+}
+
+// NVP is a name/value pair (e.g. a header or query parameter).
+type NVP struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// PostData is the body of a Request.
+type PostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+// Content is the body of a Response.
+type Content struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+// Options configures Import.
+type Options struct {
+	// Title of the Document.
+	//
+	// Defaults to the host of the first entry.
+	Title string
+	// Version of the Document.
+	//
+	// Defaults to "0.0.0".
+	Version string
+	// Templates are path templates (e.g. "/users/{userId}/repos/{repo}") to
+	// match request paths against before the segments of a path are
+	// templated heuristically.
+	Templates []string
+	// Hosts, if set, restricts the entries imported to those whose host is
+	// one of Hosts.
+	Hosts []string
+}
+
+// Parse parses a HAR from data.
+func Parse(data []byte) (*HAR, error) {
+	var h HAR
+	if err := json.Unmarshal(data, &h); err != nil {
+		return nil, fmt.Errorf("har: failed to parse: %w", err)
+	}
+	return &h, nil
+}
+
+// Import returns a Document scaffolded from the entries of h.
+func Import(h *HAR, opts Options) (*openapi.Document, error) {
+	if h == nil {
+		return nil, fmt.Errorf("har: cannot import nil HAR")
+	}
+	templates, err := compileTemplates(opts.Templates)
+	if err != nil {
+		return nil, err
+	}
+	hosts := map[string]bool{}
+	for _, host := range opts.Hosts {
+		hosts[strings.ToLower(host)] = true
+	}
+
+	ops := map[opKey]*capture{}
+	var order []opKey
+	var servers []string
+	seenServers := map[string]bool{}
+	for _, e := range h.Log.Entries {
+		u, err := url.Parse(e.Request.URL)
+		if err != nil {
+			return nil, fmt.Errorf("har: invalid request URL %q: %w", e.Request.URL, err)
+		}
+		if len(hosts) > 0 && !hosts[strings.ToLower(u.Hostname())] {
+			continue
+		}
+		server := u.Scheme + "://" + u.Host
+		if !seenServers[server] {
+			seenServers[server] = true
+			servers = append(servers, server)
+		}
+		if opts.Title == "" {
+			opts.Title = u.Hostname()
+		}
+		path, params := templatePath(u.Path, templates)
+		k := opKey{method: strings.ToUpper(e.Request.Method), path: path}
+		c, ok := ops[k]
+		if !ok {
+			c = newCapture()
+			ops[k] = c
+			order = append(order, k)
+		}
+		c.add(e, u, params)
+	}
+	if opts.Version == "" {
+		opts.Version = "0.0.0"
+	}
+
+	doc := &openapi.Document{
+		OpenAPI: semver.MustParse("3.1.0"),
+		Info:    &openapi.Info{Title: openapi.Text(opts.Title), Version: openapi.Text(opts.Version)},
+		Paths:   &openapi.Paths{},
+	}
+	if len(servers) > 0 {
+		doc.Servers = &openapi.ServerSlice{}
+		for _, s := range servers {
+			doc.Servers.Items = append(doc.Servers.Items, &openapi.Server{URL: openapi.Text(s)})
+		}
+	}
+	sort.SliceStable(order, func(i, j int) bool { return order[i].path < order[j].path })
+	for _, k := range order {
+		pi := doc.Paths.Get(openapi.Text(k.path))
+		if pi == nil {
+			pi = &openapi.PathItem{}
+			doc.Paths.Set(openapi.Text(k.path), pi)
+		}
+		if err := setOperation(pi, k.method, ops[k].operation()); err != nil {
+			return nil, err
+		}
+	}
+	return doc, nil
+}
+
+type opKey struct {
+	method string
+	path   string
+}
+
+func setOperation(pi *openapi.PathItem, method string, op *openapi.Operation) error {
+	switch openapi.Text(method) {
+	case openapi.MethodGet:
+		pi.Get = op
+	case openapi.MethodPut:
+		pi.Put = op
+	case openapi.MethodPost:
+		pi.Post = op
+	case openapi.MethodDelete:
+		pi.Delete = op
+	case openapi.MethodOptions:
+		pi.Options = op
+	case openapi.MethodHead:
+		pi.Head = op
+	case openapi.MethodPatch:
+		pi.Patch = op
+	case openapi.MethodTrace:
+		pi.Trace = op
+	default:
+		return fmt.Errorf("har: unsupported method %q", method)
+	}
+	return nil
+}
+
+// capture accumulates the entries of an operation
+type capture struct {
+	entries     int
+	pathParams  []string
+	pathValues  map[string][]string
+	query       map[string][]string
+	queryCounts map[string]int
+	queryOrder  []string
+	bodies      map[string][]string
+	bodyOrder   []string
+	responses   map[int]*responseCapture
+}
+
+type responseCapture struct {
+	description string
+	bodies      map[string][]string
+	bodyOrder   []string
+}
+
+func newCapture() *capture {
+	return &capture{
+		pathValues:  map[string][]string{},
+		query:       map[string][]string{},
+		queryCounts: map[string]int{},
+		bodies:      map[string][]string{},
+		responses:   map[int]*responseCapture{},
+	}
+}
+
+func (c *capture) add(e Entry, u *url.URL, params []pathParam) {
+	c.entries++
+	if c.pathParams == nil {
+		for _, p := range params {
+			c.pathParams = append(c.pathParams, p.name)
+		}
+	}
+	for _, p := range params {
+		c.pathValues[p.name] = append(c.pathValues[p.name], p.value)
+	}
+	query := e.Request.QueryString
+	if len(query) == 0 {
+		for name, values := range u.Query() {
+			for _, v := range values {
+				query = append(query, NVP{Name: name, Value: v})
+			}
+		}
+		sort.SliceStable(query, func(i, j int) bool { return query[i].Name < query[j].Name })
+	}
+	seen := map[string]bool{}
+	for _, q := range query {
+		if _, ok := c.query[q.Name]; !ok {
+			c.queryOrder = append(c.queryOrder, q.Name)
+		}
+		c.query[q.Name] = append(c.query[q.Name], q.Value)
+		if !seen[q.Name] {
+			seen[q.Name] = true
+			c.queryCounts[q.Name]++
+		}
+	}
+	if pd := e.Request.PostData; pd != nil && pd.Text != "" {
+		mt := mediaType(pd.MimeType)
+		if _, ok := c.bodies[mt]; !ok {
+			c.bodyOrder = append(c.bodyOrder, mt)
+		}
+		c.bodies[mt] = append(c.bodies[mt], pd.Text)
+	}
+	status := e.Response.Status
+	if status == 0 {
+		return
+	}
+	rc, ok := c.responses[status]
+	if !ok {
+		rc = &responseCapture{description: e.Response.StatusText, bodies: map[string][]string{}}
+		c.responses[status] = rc
+	}
+	if rc.description == "" {
+		rc.description = e.Response.StatusText
+	}
+	if ct := e.Response.Content; ct.Text != "" && ct.Encoding == "" {
+		mt := mediaType(ct.MimeType)
+		if _, ok := rc.bodies[mt]; !ok {
+			rc.bodyOrder = append(rc.bodyOrder, mt)
+		}
+		rc.bodies[mt] = append(rc.bodies[mt], ct.Text)
+	}
+}
+
+func (c *capture) operation() *openapi.Operation {
+	op := &openapi.Operation{}
+	var params []*openapi.Component[*openapi.Parameter]
+	for _, name := range c.pathParams {
+		required := true
+		params = append(params, &openapi.Component[*openapi.Parameter]{Object: &openapi.Parameter{
+			Name:     openapi.Text(name),
+			In:       openapi.InPath,
+			Required: &required,
+			Schema:   inferParameter(c.pathValues[name]),
+		}})
+	}
+	for _, name := range c.queryOrder {
+		p := &openapi.Parameter{
+			Name:   openapi.Text(name),
+			In:     openapi.InQuery,
+			Schema: inferParameter(c.query[name]),
+		}
+		if c.queryCounts[name] == c.entries {
+			required := true
+			p.Required = &required
+		}
+		params = append(params, &openapi.Component[*openapi.Parameter]{Object: p})
+	}
+	if len(params) > 0 {
+		op.Parameters = &openapi.ParameterSlice{Items: params}
+	}
+	if len(c.bodyOrder) > 0 {
+		op.RequestBody = &openapi.Component[*openapi.RequestBody]{Object: &openapi.RequestBody{
+			Content:  content(c.bodies, c.bodyOrder),
+			Required: true,
+		}}
+	}
+	codes := make([]int, 0, len(c.responses))
+	for code := range c.responses {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+	op.Responses = &openapi.ResponseMap{}
+	for _, code := range codes {
+		rc := c.responses[code]
+		r := &openapi.Response{Description: openapi.Text(rc.description)}
+		if r.Description == "" {
+			r.Description = openapi.Text(strconv.Itoa(code))
+		}
+		if len(rc.bodyOrder) > 0 {
+			r.Content = content(rc.bodies, rc.bodyOrder)
+		}
+		op.Responses.Items = append(op.Responses.Items, &openapi.ComponentEntry[*openapi.Response]{
+			Key:       openapi.Text(strconv.Itoa(code)),
+			Component: &openapi.Component[*openapi.Response]{Object: r},
+		})
+	}
+	return op
+}
+
+func content(bodies map[string][]string, order []string) *openapi.ContentMap {
+	cm := &openapi.ContentMap{}
+	for _, mt := range order {
+		m := &openapi.MediaType{}
+		if isJSON(mt) {
+			samples := make([][]byte, len(bodies[mt]))
+			for i, b := range bodies[mt] {
+				samples[i] = []byte(b)
+			}
+			m.Schema = openapi.InferSchema(samples...)
+		}
+		cm.Set(openapi.Text(mt), m)
+	}
+	return cm
+}
+
+// inferParameter returns a Schema describing the values of a parameter.
+// Values which are JSON numbers or booleans are inferred as such; all others
+// are strings.
+func inferParameter(values []string) *openapi.Schema {
+	samples := make([][]byte, len(values))
+	for i, v := range values {
+		var x interface{}
+		if err := json.Unmarshal([]byte(v), &x); err == nil {
+			switch x.(type) {
+			case float64, bool:
+				samples[i] = []byte(v)
+				continue
+			}
+		}
+		samples[i], _ = json.Marshal(v)
+	}
+	return openapi.InferSchema(samples...)
+}
+
+func mediaType(mimeType string) string {
+	mt := strings.ToLower(strings.TrimSpace(strings.Split(mimeType, ";")[0]))
+	if mt == "" {
+		return "application/octet-stream"
+	}
+	return mt
+}
+
+func isJSON(mt string) bool {
+	return mt == "application/json" || strings.HasSuffix(mt, "+json")
+}
+
+type pathParam struct {
+	name  string
+	value string
+}
+
+type template struct {
+	segments []string
+}
+
+func compileTemplates(templates []string) ([]template, error) {
+	res := make([]template, 0, len(templates))
+	for _, t := range templates {
+		if !strings.HasPrefix(t, "/") {
+			return nil, fmt.Errorf("har: invalid path template %q: must begin with \"/\"", t)
+		}
+		res = append(res, template{segments: strings.Split(strings.Trim(t, "/"), "/")})
+	}
+	return res, nil
+}
+
+func (t template) match(segments []string) ([]pathParam, bool) {
+	if len(segments) != len(t.segments) {
+		return nil, false
+	}
+	var params []pathParam
+	for i, s := range t.segments {
+		if strings.HasPrefix(s, "{") && strings.HasSuffix(s, "}") {
+			params = append(params, pathParam{name: s[1 : len(s)-1], value: segments[i]})
+			continue
+		}
+		if s != segments[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+var (
+	uuidPattern    = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	numericPattern = regexp.MustCompile(`^[0-9]+$`)
+	hexPattern     = regexp.MustCompile(`^[0-9a-fA-F]{16,}$`)
+)
+
+// isIdentifier reports whether the path segment s appears to be an
+// identifier rather than a static part of the path
+func isIdentifier(s string) bool {
+	return numericPattern.MatchString(s) || uuidPattern.MatchString(s) || hexPattern.MatchString(s)
+}
+
+// templatePath returns the path template of path and the values of its
+// parameters
+func templatePath(path string, templates []template) (string, []pathParam) {
+	trimmed := strings.Trim(path, "/")
+	if trimmed == "" {
+		return "/", nil
+	}
+	segments := strings.Split(trimmed, "/")
+	for _, t := range templates {
+		if params, ok := t.match(segments); ok {
+			return "/" + strings.Join(t.segments, "/"), params
+		}
+	}
+	var params []pathParam
+	taken := map[string]bool{}
+	res := make([]string, len(segments))
+	for i, s := range segments {
+		if !isIdentifier(s) {
+			res[i] = s
+			continue
+		}
+		name := "id"
+		if i > 0 && !isIdentifier(segments[i-1]) {
+			// ToLowerCamel would render "Id" as the initialism "ID"
+			name = openapi.Text(singular(segments[i-1])).ToLowerCamel().String() + "Id"
+		}
+		for n := 2; taken[name]; n++ {
+			name = strings.TrimRightFunc(name, func(r rune) bool { return r >= '0' && r <= '9' }) + strconv.Itoa(n)
+		}
+		taken[name] = true
+		params = append(params, pathParam{name: name, value: s})
+		res[i] = "{" + name + "}"
+	}
+	return "/" + strings.Join(res, "/"), params
+}
+
+func singular(s string) string {
+	switch {
+	case strings.HasSuffix(s, "ies") && len(s) > 3:
+		return s[:len(s)-3] + "y"
+	case strings.HasSuffix(s, "ses"), strings.HasSuffix(s, "xes"):
+		return s[:len(s)-2]
+	case strings.HasSuffix(s, "ss"):
+		return s
+	case strings.HasSuffix(s, "s") && len(s) > 1:
+		return s[:len(s)-1]
+	default:
+		return s
+	}
+}
@@ -0,0 +1,151 @@
+package har_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/chanced/openapi"
+	"github.com/chanced/openapi/har"
+)
+
+func TestImport(t *testing.T) {
+	data := []byte(`{
+		"log": {
+			"entries": [
+				{
+					"request": {
+						"method": "GET",
+						"url": "https://api.example.com/pets/1?verbose=true",
+						"queryString": [{ "name": "verbose", "value": "true" }]
+					},
+					"response": {
+						"status": 200,
+						"statusText": "OK",
+						"content": {
+							"mimeType": "application/json; charset=utf-8",
+							"text": "{\"id\": 1, \"name\": \"Fido\", \"born\": \"2020-01-02T03:04:05Z\", \"tags\": [\"good\"]}"
+						}
+					}
+				},
+				{
+					"request": { "method": "GET", "url": "https://api.example.com/pets/2" },
+					"response": {
+						"status": 200,
+						"statusText": "OK",
+						"content": {
+							"mimeType": "application/json",
+							"text": "{\"id\": 2, \"name\": \"Rex\", \"weight\": 4.5, \"tags\": []}"
+						}
+					}
+				},
+				{
+					"request": { "method": "GET", "url": "https://api.example.com/pets/3" },
+					"response": { "status": 404, "statusText": "Not Found", "content": {} }
+				},
+				{
+					"request": {
+						"method": "POST",
+						"url": "https://api.example.com/pets",
+						"postData": { "mimeType": "application/json", "text": "{\"name\": \"Spot\"}" }
+					},
+					"response": { "status": 201, "statusText": "Created", "content": {} }
+				},
+				{
+					"request": { "method": "GET", "url": "https://cdn.example.com/logo.png" },
+					"response": { "status": 200, "content": {} }
+				}
+			]
+		}
+	}`)
+	h, err := har.Parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := har.Import(h, har.Options{Hosts: []string{"api.example.com"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Info.Title != "api.example.com" {
+		t.Errorf("expected title api.example.com, got %s", doc.Info.Title)
+	}
+	if len(doc.Servers.Items) != 1 || doc.Servers.Items[0].URL != "https://api.example.com" {
+		t.Errorf("unexpected servers: %+v", doc.Servers.Items)
+	}
+	if len(doc.Paths.Items) != 2 {
+		t.Fatalf("expected 2 paths, got %d", len(doc.Paths.Items))
+	}
+	pets := doc.Paths.Get("/pets")
+	if pets == nil || pets.Post == nil || pets.Post.RequestBody == nil {
+		t.Fatalf("expected POST /pets with a request body")
+	}
+	pet := doc.Paths.Get("/pets/{petId}")
+	if pet == nil || pet.Get == nil {
+		t.Fatalf("expected GET /pets/{petId}")
+	}
+	params := pet.Get.Parameters.Items
+	if len(params) != 2 {
+		t.Fatalf("expected 2 parameters, got %d", len(params))
+	}
+	if p := params[0].Object; p.Name != "petId" || p.In != openapi.InPath || !p.Schema.Type.ContainsInteger() {
+		t.Errorf("unexpected path parameter: %+v", p)
+	}
+	if p := params[1].Object; p.Name != "verbose" || p.Required != nil || !p.Schema.Type.ContainsBoolean() {
+		t.Errorf("unexpected query parameter: %+v", p)
+	}
+	if res := pet.Get.Responses.Get("404"); res == nil || res.Object.Description != "Not Found" {
+		t.Errorf("expected a 404 response")
+	}
+	res := pet.Get.Responses.Get("200")
+	if res == nil || res.Object.Content == nil {
+		t.Fatalf("expected a 200 response with content")
+	}
+	s := res.Object.Content.Get("application/json").Schema
+
+	b, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"type":"object","required":["id","name","tags"],"properties":{"born":{"type":"string","format":"date-time"},"id":{"type":"integer"},"name":{"type":"string"},"tags":{"type":"array","items":{"type":"string"}},"weight":{"type":"number"}}}`
+	var a, e interface{}
+	if err = json.Unmarshal(b, &a); err != nil {
+		t.Fatal(err)
+	}
+	if err = json.Unmarshal([]byte(expected), &e); err != nil {
+		t.Fatal(err)
+	}
+	ab, _ := json.Marshal(a)
+	eb, _ := json.Marshal(e)
+	if string(ab) != string(eb) {
+		t.Errorf("expected schema:\n%s\ngot:\n%s", eb, ab)
+	}
+}
+
+func TestImportPathParameterNames(t *testing.T) {
+	data := []byte(`{
+		"log": {
+			"entries": [
+				{
+					"request": { "method": "GET", "url": "https://api.example.com/pet-owners/7/orders/3" },
+					"response": { "status": 204, "content": {} }
+				},
+				{
+					"request": { "method": "GET", "url": "https://api.example.com/pet-owners/8/orders/4" },
+					"response": { "status": 204, "content": {} }
+				}
+			]
+		}
+	}`)
+	h, err := har.Parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := har.Import(h, har.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Paths.Get("/pet-owners/{petOwnerId}/orders/{orderId}") == nil {
+		for _, item := range doc.Paths.Items {
+			t.Errorf("expected /pet-owners/{petOwnerId}/orders/{orderId}, got %s", item.Key)
+		}
+	}
+}
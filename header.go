@@ -2,15 +2,33 @@ package openapi
 
 import (
 	"encoding/json"
+	"strings"
 
 	"github.com/chanced/jsonx"
-	"github.com/chanced/transcode"
 	"gopkg.in/yaml.v3"
 )
 
 // HeaderMap holds reusable HeaderMap.
 type HeaderMap = ComponentMap[*Header]
 
+// lookupHeader returns the Component of the header name within hm. As header
+// names are case-insensitive (RFC 7230), an exact match is preferred but
+// otherwise the first key which matches name case-insensitively is returned.
+func lookupHeader(hm *HeaderMap, name Text) *Component[*Header] {
+	if hm == nil {
+		return nil
+	}
+	if c := hm.Get(name); c != nil {
+		return c
+	}
+	for _, item := range hm.Items {
+		if strings.EqualFold(item.Key.String(), name.String()) {
+			return item.Component
+		}
+	}
+	return nil
+}
+
 // Header follows the structure of the Parameter Object with the following
 // changes:
 //   - name MUST NOT be specified, it is given in the corresponding headers map.
@@ -85,6 +103,36 @@ type Header struct {
 	Example jsonx.RawMessage `json:"example,omitempty"`
 }
 
+// SetExample marshals v and sets it as the example of the Header, clearing
+// Examples as the fields are mutually exclusive.
+//
+// If v is a json.RawMessage or jsonx.RawMessage, it is used as is.
+func (h *Header) SetExample(v interface{}) error {
+	b, err := encodeExample(v)
+	if err != nil {
+		return err
+	}
+	h.Example = b
+	h.Examples = nil
+	return nil
+}
+
+// SetExamples sets the examples of the Header, clearing Example as the fields
+// are mutually exclusive.
+func (h *Header) SetExamples(examples *ExampleMap) {
+	h.Examples = examples
+	h.Example = nil
+}
+
+// ValidateExamples returns an error wrapping ErrExampleAndExamples if both
+// Example and Examples are set.
+func (h *Header) ValidateExamples() error {
+	if h == nil {
+		return nil
+	}
+	return validateExampleExclusivity(h.Example, h.Examples, KindHeader, h.AbsoluteLocation())
+}
+
 func (h *Header) Nodes() []Node {
 	if h == nil {
 		return nil
@@ -156,11 +204,7 @@ func (h Header) MarshalYAML() (interface{}, error) {
 
 // UnmarshalYAML satisfies gopkg.in/yaml.v3 Unmarshaler interface
 func (h *Header) UnmarshalYAML(value *yaml.Node) error {
-	v, err := yaml.Marshal(value)
-	if err != nil {
-		return err
-	}
-	j, err := transcode.JSONFromYAML(v)
+	j, err := jsonFromYAMLNode(value)
 	if err != nil {
 		return err
 	}
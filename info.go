@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 
 	"github.com/Masterminds/semver"
-	"github.com/chanced/transcode"
 	"gopkg.in/yaml.v3"
 )
 
@@ -92,6 +91,13 @@ func (*Info) Refs() []Ref { return nil }
 // 	}
 // }
 
+func (i *Info) Nodes() []Node {
+	if i == nil {
+		return nil
+	}
+	return downcastNodes(i.nodes())
+}
+
 func (i *Info) nodes() []node {
 	edges := appendEdges(nil, i.Contact)
 	edges = appendEdges(edges, i.License)
@@ -160,11 +166,7 @@ func (i Info) MarshalYAML() (interface{}, error) {
 
 // UnmarshalYAML satisfies gopkg.in/yaml.v3 Unmarshaler interface
 func (i *Info) UnmarshalYAML(value *yaml.Node) error {
-	v, err := yaml.Marshal(value)
-	if err != nil {
-		return err
-	}
-	j, err := transcode.JSONFromYAML(v)
+	j, err := jsonFromYAMLNode(value)
 	if err != nil {
 		return err
 	}
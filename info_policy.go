@@ -0,0 +1,166 @@
+package openapi
+
+import (
+	"fmt"
+	"net/mail"
+	"regexp"
+)
+
+// VersionPolicy is a policy for the format of Info.Version.
+type VersionPolicy uint8
+
+const (
+	// VersionPolicyAny permits any non-empty version.
+	VersionPolicyAny VersionPolicy = iota
+	// VersionPolicySemVer requires the version to be a Semantic Version 2.0
+	// (e.g. "1.2.3" or "1.2.3-beta.1"), without a "v" prefix.
+	VersionPolicySemVer
+	// VersionPolicyCalVer requires the version to be a calendar version
+	// beginning with a four digit year (e.g. "2024.05", "2024-05-01", or
+	// "2024.5.1").
+	VersionPolicyCalVer
+)
+
+var (
+	semVerPattern = regexp.MustCompile(`^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)` +
+		`(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?` +
+		`(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
+	calVerPattern = regexp.MustCompile(`^\d{4}(?:[.-](?:0?[1-9]|1[0-2]))(?:[.-](?:0?[1-9]|[12]\d|3[01]))?(?:[.-_+][0-9A-Za-z.-]+)?$`)
+)
+
+// InfoPolicy configures Info.Validate.
+type InfoPolicy struct {
+	// Version is the policy for the format of the version. The default is
+	// VersionPolicyAny.
+	Version VersionPolicy
+
+	// VersionPattern, if set, must match the version in addition to Version.
+	VersionPattern *regexp.Regexp
+
+	// RequireContact requires the Info to have a Contact.
+	RequireContact bool
+
+	// RequireLicense requires the Info to have a License.
+	RequireLicense bool
+
+	// AllowUnknownLicenses permits License identifiers which are well-formed
+	// SPDX license expressions but which contain identifiers that are not
+	// known. See CheckSPDXExpression.
+	AllowUnknownLicenses bool
+}
+
+// Validate checks that i has a title and a version which satisfies policy,
+// along with the Contact and License, if any. A License must have a name and
+// may have either an identifier, which must be a valid SPDX license
+// expression, or a url but not both.
+//
+// The error returned is a ValidationError wrapping ErrInvalidInfo or
+// ErrInvalidLicense.
+func (i *Info) Validate(policy InfoPolicy) error {
+	if i == nil {
+		return fmt.Errorf("%w: missing info", ErrInvalidInfo)
+	}
+	invalid := func(format string, args ...interface{}) error {
+		err := fmt.Errorf("%w: "+format, append([]interface{}{ErrInvalidInfo}, args...)...)
+		return NewValidationError(err, KindInfo, i.AbsoluteLocation())
+	}
+	if i.Title == "" {
+		return invalid("missing title")
+	}
+	if i.Version == "" {
+		return invalid("missing version")
+	}
+	switch policy.Version {
+	case VersionPolicySemVer:
+		if !semVerPattern.MatchString(i.Version.String()) {
+			return invalid("version %q is not a semantic version", i.Version)
+		}
+	case VersionPolicyCalVer:
+		if !calVerPattern.MatchString(i.Version.String()) {
+			return invalid("version %q is not a calendar version", i.Version)
+		}
+	}
+	if policy.VersionPattern != nil && !policy.VersionPattern.MatchString(i.Version.String()) {
+		return invalid("version %q does not match %q", i.Version, policy.VersionPattern)
+	}
+	if i.Contact == nil && policy.RequireContact {
+		return invalid("missing contact")
+	}
+	if err := i.Contact.Validate(); err != nil {
+		return err
+	}
+	if i.License == nil {
+		if policy.RequireLicense {
+			return invalid("missing license")
+		}
+		return nil
+	}
+	if policy.AllowUnknownLicenses {
+		return i.License.validate(false)
+	}
+	return i.License.Validate()
+}
+
+// Validate checks that l has a name and either an identifier or a url but
+// not both. The identifier, if set, must be a valid SPDX license expression
+// composed of known licenses (see CheckSPDXExpression).
+//
+// The error returned is a ValidationError wrapping ErrInvalidLicense.
+func (l *License) Validate() error {
+	return l.validate(true)
+}
+
+func (l *License) validate(known bool) error {
+	if l == nil {
+		return nil
+	}
+	invalid := func(err error) error {
+		return NewValidationError(err, KindLicense, l.AbsoluteLocation())
+	}
+	if err := l.checkExclusive(); err != nil {
+		return invalid(err)
+	}
+	if l.Name == "" {
+		return invalid(fmt.Errorf("%w: missing name", ErrInvalidLicense))
+	}
+	if l.Identifier == "" {
+		return nil
+	}
+	if err := checkSPDXExpression(l.Identifier, known); err != nil {
+		return invalid(err)
+	}
+	return nil
+}
+
+func (l *License) checkExclusive() error {
+	if l.Identifier != "" && l.URL != nil {
+		return fmt.Errorf("%w: identifier and url are mutually exclusive", ErrInvalidLicense)
+	}
+	return nil
+}
+
+// Validate checks that the email of c, if any, is a valid email address.
+//
+// The error returned is a ValidationError wrapping ErrInvalidInfo.
+func (c *Contact) Validate() error {
+	if c == nil || c.Emails == "" {
+		return nil
+	}
+	if _, err := mail.ParseAddress(c.Emails.String()); err != nil {
+		err = fmt.Errorf("%w: invalid contact email %q", ErrInvalidInfo, c.Emails)
+		return NewValidationError(err, KindContact, c.AbsoluteLocation())
+	}
+	return nil
+}
+
+// validateLicense ensures that the License of doc, if any, does not have both
+// an identifier and a url.
+func validateLicense(doc *Document) error {
+	if doc.Info == nil || doc.Info.License == nil {
+		return nil
+	}
+	if err := doc.Info.License.checkExclusive(); err != nil {
+		return NewValidationError(err, KindLicense, doc.Info.License.AbsoluteLocation())
+	}
+	return nil
+}
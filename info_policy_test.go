@@ -0,0 +1,168 @@
+package openapi_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/chanced/openapi"
+	"github.com/chanced/uri"
+)
+
+func TestCheckSPDXExpression(t *testing.T) {
+	for _, expr := range []openapi.Text{
+		"MIT",
+		"mit",
+		"Apache-2.0",
+		"MIT OR Apache-2.0",
+		"(MIT OR Apache-2.0) AND BSD-3-Clause",
+		"GPL-2.0-or-later WITH Classpath-exception-2.0",
+		"LicenseRef-Proprietary",
+	} {
+		if err := openapi.CheckSPDXExpression(expr); err != nil {
+			t.Errorf("%q: %v", expr, err)
+		}
+	}
+	for _, expr := range []openapi.Text{
+		"",
+		"MIT OR",
+		"(MIT",
+		"MIT Apache-2.0",
+		"AND MIT",
+		"MIT WITH",
+		"Apache 2",
+	} {
+		if err := openapi.CheckSPDXExpression(expr); !errors.Is(err, openapi.ErrInvalidLicense) {
+			t.Errorf("%q: expected ErrInvalidLicense, got %v", expr, err)
+		}
+	}
+}
+
+func TestSuggestSPDXLicenses(t *testing.T) {
+	for id, expected := range map[openapi.Text]openapi.Text{
+		"Apache2":  "Apache-2.0",
+		"apache 2": "Apache-2.0",
+		"BSD3":     "BSD-3-Clause",
+		"MIT2":     "MIT",
+		"GPL-3":    "GPL-3.0-only",
+		"MPL-2":    "MPL-2.0",
+	} {
+		s := openapi.SuggestSPDXLicenses(id)
+		found := false
+		for _, v := range s {
+			found = found || v == expected
+		}
+		if !found {
+			t.Errorf("%q: expected %q to be suggested, got %v", id, expected, s)
+		}
+	}
+	if s := openapi.SuggestSPDXLicenses("completely-unrelated"); len(s) != 0 {
+		t.Errorf("expected no suggestions, got %v", s)
+	}
+}
+
+func TestInfoValidate(t *testing.T) {
+	u := uri.MustParse("https://example.com/license")
+	tests := []struct {
+		name   string
+		info   openapi.Info
+		policy openapi.InfoPolicy
+		err    error
+	}{
+		{"valid", openapi.Info{Title: "t", Version: "1.0"}, openapi.InfoPolicy{}, nil},
+		{"missing title", openapi.Info{Version: "1.0"}, openapi.InfoPolicy{}, openapi.ErrInvalidInfo},
+		{"missing version", openapi.Info{Title: "t"}, openapi.InfoPolicy{}, openapi.ErrInvalidInfo},
+		{"semver", openapi.Info{Title: "t", Version: "1.2.3-beta.1+build.5"}, openapi.InfoPolicy{Version: openapi.VersionPolicySemVer}, nil},
+		{"not semver", openapi.Info{Title: "t", Version: "v1.2"}, openapi.InfoPolicy{Version: openapi.VersionPolicySemVer}, openapi.ErrInvalidInfo},
+		{"calver", openapi.Info{Title: "t", Version: "2024.05.01"}, openapi.InfoPolicy{Version: openapi.VersionPolicyCalVer}, nil},
+		{"not calver", openapi.Info{Title: "t", Version: "1.2.3"}, openapi.InfoPolicy{Version: openapi.VersionPolicyCalVer}, openapi.ErrInvalidInfo},
+		{"pattern", openapi.Info{Title: "t", Version: "1.2.3"}, openapi.InfoPolicy{VersionPattern: regexp.MustCompile(`^2\.`)}, openapi.ErrInvalidInfo},
+		{"require license", openapi.Info{Title: "t", Version: "1"}, openapi.InfoPolicy{RequireLicense: true}, openapi.ErrInvalidInfo},
+		{"require contact", openapi.Info{Title: "t", Version: "1"}, openapi.InfoPolicy{RequireContact: true}, openapi.ErrInvalidInfo},
+		{"invalid email", openapi.Info{Title: "t", Version: "1", Contact: &openapi.Contact{Emails: "nope"}}, openapi.InfoPolicy{}, openapi.ErrInvalidInfo},
+		{"license", openapi.Info{Title: "t", Version: "1", License: &openapi.License{Name: "MIT", Identifier: "MIT"}}, openapi.InfoPolicy{}, nil},
+		{"license url", openapi.Info{Title: "t", Version: "1", License: &openapi.License{Name: "Custom", URL: u}}, openapi.InfoPolicy{}, nil},
+		{"license name", openapi.Info{Title: "t", Version: "1", License: &openapi.License{Identifier: "MIT"}}, openapi.InfoPolicy{}, openapi.ErrInvalidLicense},
+		{"license exclusive", openapi.Info{Title: "t", Version: "1", License: &openapi.License{Name: "MIT", Identifier: "MIT", URL: u}}, openapi.InfoPolicy{}, openapi.ErrInvalidLicense},
+		{"unknown license", openapi.Info{Title: "t", Version: "1", License: &openapi.License{Name: "x", Identifier: "Foo-1.0"}}, openapi.InfoPolicy{}, openapi.ErrInvalidLicense},
+		{"allow unknown license", openapi.Info{Title: "t", Version: "1", License: &openapi.License{Name: "x", Identifier: "Foo-1.0 OR MIT"}}, openapi.InfoPolicy{AllowUnknownLicenses: true}, nil},
+		{"malformed unknown license", openapi.Info{Title: "t", Version: "1", License: &openapi.License{Name: "x", Identifier: "Foo-1.0 OR"}}, openapi.InfoPolicy{AllowUnknownLicenses: true}, openapi.ErrInvalidLicense},
+	}
+	for _, test := range tests {
+		err := test.info.Validate(test.policy)
+		if test.err == nil && err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+		}
+		if test.err != nil && !errors.Is(err, test.err) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.err, err)
+		}
+	}
+}
+
+func TestValidateLicense(t *testing.T) {
+	data := []byte(`{
+		"openapi": "3.1.0",
+		"info": {
+			"title": "license",
+			"version": "1.0.0",
+			"license": { "name": "MIT", "identifier": "MIT", "url": "https://opensource.org/licenses/MIT" }
+		},
+		"paths": {}
+	}`)
+	fn := func(ctx context.Context, uri uri.URI, kind openapi.Kind) (openapi.Kind, []byte, error) {
+		return openapi.KindDocument, data, nil
+	}
+	v, err := openapi.NewOfflineValidator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := openapi.Load(context.Background(), "https://example.com/license.json", NoopValidator{}, fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = v.ValidateDocument(doc)
+	if !errors.Is(err, openapi.ErrInvalidLicense) {
+		t.Fatalf("expected ErrInvalidLicense, got %v", err)
+	}
+	var ve *openapi.ValidationError
+	if !errors.As(err, &ve) || ve.URI.Fragment != "/info/license" {
+		t.Errorf("expected the error to be located at the license, got %v", err)
+	}
+}
+
+func TestBuildInfo(t *testing.T) {
+	info := openapi.Info{Title: "t", Version: "1"}
+	b := openapi.BuildInfo{
+		Commit:   "0123456789abcdef0123456789abcdef01234567",
+		Time:     time.Date(2024, 5, 1, 12, 30, 0, 0, time.FixedZone("", 3600)),
+		Modified: true,
+	}
+	if err := info.SetBuildInfo(b); err != nil {
+		t.Fatal(err)
+	}
+	if string(info.Extensions["x-build-time"]) != `"2024-05-01T11:30:00Z"` {
+		t.Errorf("expected the time to be stored in UTC, got %s", info.Extensions["x-build-time"])
+	}
+	res, err := info.BuildInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Commit != b.Commit || !res.Time.Equal(b.Time) || !res.Modified {
+		t.Errorf("expected %v, got %v", b, res)
+	}
+
+	if err := info.SetBuildInfo(openapi.BuildInfo{Commit: "abc1234"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := info.Extensions["x-build-time"]; ok {
+		t.Error("expected x-build-time to be removed")
+	}
+	if err := info.SetBuildInfo(openapi.BuildInfo{Commit: "main"}); err == nil {
+		t.Error("expected an error for an invalid commit")
+	}
+	if res, err := (&openapi.Info{}).BuildInfo(); err != nil || !res.IsZero() {
+		t.Errorf("expected empty build info, got %v, %v", res, err)
+	}
+}
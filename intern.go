@@ -0,0 +1,90 @@
+package openapi
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// maxInternedLen is the length of the longest string which is interned.
+// Longer strings, such as descriptions, rarely repeat.
+const maxInternedLen = 64
+
+// maxInterned is the number of strings which are interned before the table is
+// cleared, bounding its memory.
+const maxInterned = 1 << 16
+
+// interning is 1 if interning is enabled. See SetInterning.
+var interning int32
+
+var internTable = struct {
+	sync.RWMutex
+	strings map[string]string
+}{strings: map[string]string{}}
+
+// SetInterning enables or disables the interning of Text values while
+// decoding. Interning is disabled by default.
+//
+// When enabled, short, frequently repeated values, such as the keys of maps
+// (property names, media types, status codes, etc.) and the types and
+// formats of Schemas, share a single copy of their contents across all
+// decoded Documents. This reduces the memory footprint of large Documents and
+// allows equality checks between interned values to short-circuit.
+//
+// The interned values are held in a table shared by the process, which
+// retains up to 65536 strings while interning is enabled. Decoding a value
+// which is not yet interned locks the table, so Documents which are decoded
+// concurrently contend for it. Interning is best suited to processes which
+// hold many large Documents in memory. Disabling interning releases the
+// table.
+//
+// SetInterning is safe for concurrent use but only affects subsequent
+// operations.
+func SetInterning(enabled bool) {
+	if enabled {
+		atomic.StoreInt32(&interning, 1)
+		return
+	}
+	atomic.StoreInt32(&interning, 0)
+	internTable.Lock()
+	defer internTable.Unlock()
+	internTable.strings = map[string]string{}
+}
+
+// Interning reports whether interning is enabled. See SetInterning.
+func Interning() bool {
+	return atomic.LoadInt32(&interning) == 1
+}
+
+// intern returns s as a Text, sharing its contents with previously interned
+// strings of equal value if interning is enabled.
+func intern(s string) Text {
+	if len(s) > maxInternedLen || !Interning() {
+		return Text(s)
+	}
+	internTable.RLock()
+	v, ok := internTable.strings[s]
+	internTable.RUnlock()
+	if ok {
+		return Text(v)
+	}
+	internTable.Lock()
+	defer internTable.Unlock()
+	if v, ok = internTable.strings[s]; ok {
+		return Text(v)
+	}
+	if len(internTable.strings) >= maxInterned {
+		internTable.strings = map[string]string{}
+	}
+	// s is copied as it is likely a substring of a larger input which would
+	// otherwise be retained
+	v = string(append([]byte(nil), s...))
+	internTable.strings[v] = v
+	return Text(v)
+}
+
+// internTexts interns each of t in place.
+func internTexts(t Texts) {
+	for i, v := range t {
+		t[i] = intern(string(v))
+	}
+}
@@ -0,0 +1,71 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"testing"
+	"unsafe"
+
+	"github.com/chanced/openapi"
+)
+
+func TestInterning(t *testing.T) {
+	if openapi.Interning() {
+		t.Error("expected interning to be disabled by default")
+	}
+	defer openapi.SetInterning(false)
+	data := []byte(`{
+		"openapi": "3.1.0",
+		"info": { "title": "Interning", "version": "1.0.0" },
+		"components": {
+			"schemas": {
+				"Pet": {
+					"type": "object",
+					"required": ["name"],
+					"properties": { "name": { "type": "string", "format": "uuid" } }
+				},
+				"Owner": {
+					"type": "object",
+					"required": ["name"],
+					"properties": { "name": { "type": "string", "format": "uuid" } }
+				}
+			}
+		}
+	}`)
+	// dataOf returns the address of the contents of s
+	dataOf := func(s openapi.Text) uintptr {
+		return *(*uintptr)(unsafe.Pointer(&s))
+	}
+	decode := func(interning bool) (*openapi.Schema, *openapi.Schema) {
+		t.Helper()
+		openapi.SetInterning(interning)
+		var doc openapi.Document
+		if err := json.Unmarshal(data, &doc); err != nil {
+			t.Fatal(err)
+		}
+		return doc.Components.Schemas.Get("Pet"), doc.Components.Schemas.Get("Owner")
+	}
+
+	pet, owner := decode(true)
+	a, b := pet.Properties.Items[0], owner.Properties.Items[0]
+	if a.Key != "name" || b.Key != "name" {
+		t.Fatalf("expected properties to be named \"name\", got %q and %q", a.Key, b.Key)
+	}
+	if dataOf(a.Key) != dataOf(b.Key) {
+		t.Error("expected property names to be interned")
+	}
+	if dataOf(pet.Required[0]) != dataOf(a.Key) {
+		t.Error("expected required to be interned")
+	}
+	if dataOf(a.Schema.Format) != dataOf(b.Schema.Format) {
+		t.Error("expected formats to be interned")
+	}
+
+	pet, owner = decode(false)
+	if openapi.Interning() {
+		t.Error("expected interning to be disabled")
+	}
+	a, b = pet.Properties.Items[0], owner.Properties.Items[0]
+	if a.Key != b.Key || dataOf(a.Key) == dataOf(b.Key) {
+		t.Error("expected property names not to be interned")
+	}
+}
@@ -0,0 +1,776 @@
+// Package ir builds an intermediate representation of an OpenAPI Document
+// intended for code generators and templating engines.
+//
+// The IR is a normalized view of a resolved Document: Parameters of a
+// PathItem and its Operations are merged, references are replaced by the
+// Models they refer to, a single media type is negotiated for each request
+// and response body, allOf compositions are merged into a single Model, and
+// the variants of oneOf and anyOf Schemas are enumerated along with their
+// discriminator values.
+//
+// Names of Models, Operations, and Fields are assigned by a naming.Namer so
+// that generators built on the IR agree with those using the Namer directly.
+package ir
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/chanced/openapi"
+	"github.com/chanced/openapi/naming"
+)
+
+// DefaultContentTypes are the media types preferred, in order, when
+// negotiating the content of a body. Media types with a "+json" suffix are
+// preferred after these, followed by the first media type defined.
+var DefaultContentTypes = []openapi.Text{"application/json"}
+
+// Options configures Build.
+type Options struct {
+	// Namer assigns the names of Models, Operations, and Fields. If nil, a
+	// Namer is created from the Document with default naming.Options.
+	Namer *naming.Namer
+	// ContentTypes are the media types preferred, in order, when negotiating
+	// the content of a request or response body. Defaults to
+	// DefaultContentTypes.
+	ContentTypes []openapi.Text
+	// Webhooks indicates that the Operations of the Document's Webhooks
+	// should be included.
+	Webhooks bool
+}
+
+// API is the intermediate representation of a Document.
+type API struct {
+	Title       openapi.Text
+	Version     openapi.Text
+	Description openapi.Text
+	Servers     []Server
+	Operations  []*Operation
+	// Models are the Models of the Document's component Schemas, in the
+	// order they are defined, followed by the Models of inline Schemas in
+	// the order they were encountered.
+	Models []*Model
+}
+
+// Model returns the Model named name.
+func (a *API) Model(name string) (*Model, bool) {
+	for _, m := range a.Models {
+		if m.Name == name {
+			return m, true
+		}
+	}
+	return nil, false
+}
+
+// Operation returns the Operation named name.
+func (a *API) Operation(name string) (*Operation, bool) {
+	for _, op := range a.Operations {
+		if op.Name == name {
+			return op, true
+		}
+	}
+	return nil, false
+}
+
+// Server is a server of the API.
+type Server struct {
+	URL         openapi.Text
+	Description openapi.Text
+}
+
+// Operation is an Operation of the Document along with the effective
+// Parameters of its PathItem.
+type Operation struct {
+	// Name is the Go identifier of the Operation, derived from its
+	// operationId or, if absent, its method and path.
+	Name        string
+	ID          openapi.Text
+	Method      openapi.Text
+	Path        openapi.Text
+	Webhook     bool
+	Summary     openapi.Text
+	Description openapi.Text
+	Tags        openapi.Texts
+	Deprecated  bool
+	// Parameters are the effective parameters of the Operation: those of
+	// its PathItem followed by its own, with references resolved and
+	// duplicates removed.
+	Parameters []*Parameter
+	Body       *Body
+	Responses  []*Response
+	Source     *openapi.Operation
+}
+
+// ParametersIn returns the Parameters of op located in in (e.g.
+// openapi.InPath).
+func (op *Operation) ParametersIn(in openapi.In) []*Parameter {
+	var params []*Parameter
+	for _, p := range op.Parameters {
+		if p.In == in {
+			params = append(params, p)
+		}
+	}
+	return params
+}
+
+// SuccessResponse returns the first Response with a 2XX status code, if
+// any.
+func (op *Operation) SuccessResponse() *Response {
+	for _, r := range op.Responses {
+		if r.Success {
+			return r
+		}
+	}
+	return nil
+}
+
+// Parameter is a Parameter of an Operation. Style and Explode are set to
+// their defaults when not specified.
+type Parameter struct {
+	Name        openapi.Text
+	FieldName   string
+	In          openapi.In
+	Description openapi.Text
+	Required    bool
+	Deprecated  bool
+	Style       openapi.Text
+	Explode     bool
+	Type        *TypeRef
+	Source      *openapi.Parameter
+}
+
+// Body is a request body with its negotiated media type.
+type Body struct {
+	Description openapi.Text
+	Required    bool
+	// ContentType is the negotiated media type.
+	ContentType openapi.Text
+	// ContentTypes are each of the media types of the body, in the order
+	// they are defined.
+	ContentTypes []openapi.Text
+	Type         *TypeRef
+	Source       *openapi.RequestBody
+}
+
+// Response is a Response of an Operation with its negotiated media type.
+type Response struct {
+	// StatusCode is the key of the Response (e.g. "200", "4XX", "default").
+	StatusCode   openapi.Text
+	Default      bool
+	Success      bool
+	Description  openapi.Text
+	ContentType  openapi.Text
+	ContentTypes []openapi.Text
+	// Type is nil if the Response does not have content.
+	Type    *TypeRef
+	Headers []*Header
+	Source  *openapi.Response
+}
+
+// Header is a header of a Response.
+type Header struct {
+	Name        openapi.Text
+	FieldName   string
+	Description openapi.Text
+	Required    bool
+	Type        *TypeRef
+}
+
+// TypeKind is the kind of a TypeRef.
+type TypeKind uint8
+
+const (
+	// TypeAny is any JSON value.
+	TypeAny TypeKind = iota
+	// TypePrimitive is a string, number, integer, or boolean.
+	TypePrimitive
+	// TypeArray is an array of Elem.
+	TypeArray
+	// TypeMap is an object with string keys and values of Elem.
+	TypeMap
+	// TypeModel is a reference to a Model.
+	TypeModel
+)
+
+var typeKindNames = [...]string{
+	TypeAny:       "any",
+	TypePrimitive: "primitive",
+	TypeArray:     "array",
+	TypeMap:       "map",
+	TypeModel:     "model",
+}
+
+func (k TypeKind) String() string {
+	if int(k) < len(typeKindNames) {
+		return typeKindNames[k]
+	}
+	return fmt.Sprintf("TypeKind(%d)", k)
+}
+
+// TypeRef is the type of a value.
+type TypeRef struct {
+	Kind TypeKind
+	// Model is the name of the Model if Kind is TypeModel.
+	Model string
+	// Primitive is the type of the value if Kind is TypePrimitive.
+	Primitive openapi.Type
+	Format    openapi.Text
+	// Elem is the type of the elements of an array or values of a map.
+	Elem     *TypeRef
+	Nullable bool
+	Schema   *openapi.Schema
+}
+
+// ModelKind is the kind of a Model.
+type ModelKind uint8
+
+const (
+	// ModelObject is an object with Fields.
+	ModelObject ModelKind = iota
+	// ModelEnum is a string enumeration.
+	ModelEnum
+	// ModelUnion is a oneOf or anyOf of Variants.
+	ModelUnion
+	// ModelAlias is a named TypeRef, such as an array or primitive.
+	ModelAlias
+)
+
+var modelKindNames = [...]string{
+	ModelObject: "object",
+	ModelEnum:   "enum",
+	ModelUnion:  "union",
+	ModelAlias:  "alias",
+}
+
+func (k ModelKind) String() string {
+	if int(k) < len(modelKindNames) {
+		return modelKindNames[k]
+	}
+	return fmt.Sprintf("ModelKind(%d)", k)
+}
+
+// Model is a named type derived from a Schema. allOf compositions are merged
+// into a single Model.
+type Model struct {
+	Name string
+	// Key is the key of the component Schema. It is empty for Models of
+	// inline Schemas.
+	Key         openapi.Text
+	Kind        ModelKind
+	Description openapi.Text
+	Deprecated  bool
+	// Fields of a ModelObject, in the order they are defined.
+	Fields []*Field
+	// AdditionalProperties is the type of the additional properties of a
+	// ModelObject, if permitted by a Schema.
+	AdditionalProperties *TypeRef
+	// Values of a ModelEnum.
+	Values []EnumValue
+	// Variants of a ModelUnion.
+	Variants []*Variant
+	// Exclusive indicates that the Variants of a ModelUnion are from a oneOf
+	// rather than an anyOf.
+	Exclusive     bool
+	Discriminator *Discriminator
+	// Alias is the type of a ModelAlias.
+	Alias  *TypeRef
+	Schema *openapi.Schema
+}
+
+// Field is a property of a ModelObject.
+type Field struct {
+	Name        string
+	Key         openapi.Text
+	Description openapi.Text
+	Required    bool
+	Deprecated  bool
+	ReadOnly    bool
+	WriteOnly   bool
+	Type        *TypeRef
+}
+
+// EnumValue is a value of a ModelEnum.
+type EnumValue struct {
+	Name  string
+	Value openapi.Text
+}
+
+// Variant is a branch of a ModelUnion.
+type Variant struct {
+	Name string
+	Type *TypeRef
+	// DiscriminatorValue is the value of the discriminator property which
+	// selects the Variant, if the union has a Discriminator.
+	DiscriminatorValue openapi.Text
+}
+
+// Discriminator is the discriminator of a ModelUnion.
+type Discriminator struct {
+	PropertyName openapi.Text
+	// Mapping is the discriminator value of each Variant, keyed by Variant
+	// name.
+	Mapping map[string]openapi.Text
+}
+
+// Build returns the intermediate representation of doc.
+//
+// doc should be resolved (e.g. loaded with openapi.Load); references which
+// have not been resolved are represented as TypeAny.
+func Build(doc *openapi.Document, opts Options) (*API, error) {
+	b := &builder{
+		doc:      doc,
+		opts:     opts,
+		namer:    opts.Namer,
+		models:   map[*openapi.Schema]*Model{},
+		used:     map[string]bool{},
+		visiting: map[*openapi.Schema]bool{},
+		api:      &API{},
+	}
+	if b.namer == nil {
+		b.namer = naming.New(doc, naming.Options{})
+	}
+	if len(b.opts.ContentTypes) == 0 {
+		b.opts.ContentTypes = DefaultContentTypes
+	}
+	if doc.Info != nil {
+		b.api.Title = doc.Info.Title
+		b.api.Version = doc.Info.Version
+		b.api.Description = doc.Info.Description
+	}
+	if doc.Servers != nil {
+		for _, s := range doc.Servers.Items {
+			if s != nil {
+				b.api.Servers = append(b.api.Servers, Server{URL: s.URL, Description: s.Description})
+			}
+		}
+	}
+	b.buildModels()
+	if err := b.buildOperations(); err != nil {
+		return nil, err
+	}
+	return b.api, nil
+}
+
+type builder struct {
+	doc   *openapi.Document
+	opts  Options
+	namer *naming.Namer
+	api   *API
+	// models are the Models of Schemas, keyed by Schema
+	models map[*openapi.Schema]*Model
+	// used are the names of Models
+	used     map[string]bool
+	visiting map[*openapi.Schema]bool
+}
+
+func (b *builder) buildModels() {
+	if b.doc.Components == nil || b.doc.Components.Schemas == nil {
+		return
+	}
+	// each component Model is registered prior to being built so that
+	// references between them, including cycles, are resolved
+	var models []*Model
+	for _, item := range b.doc.Components.Schemas.Items {
+		if item.Schema == nil {
+			continue
+		}
+		name, ok := b.namer.Type(openapi.KindSchema, item.Key)
+		if !ok {
+			name = b.namer.Identifier(item.Key)
+		}
+		m := &Model{Name: name, Key: item.Key, Schema: item.Schema}
+		b.used[name] = true
+		b.models[item.Schema] = m
+		b.api.Models = append(b.api.Models, m)
+		models = append(models, m)
+	}
+	for _, m := range models {
+		b.buildModel(m, m.Schema)
+	}
+}
+
+// inline returns a new Model for the inline Schema s
+func (b *builder) inline(hint string, s *openapi.Schema) *Model {
+	name := b.namer.Identifier(openapi.Text(hint))
+	if b.used[name] {
+		for i := 2; ; i++ {
+			if n := fmt.Sprintf("%s%d", name, i); !b.used[n] {
+				name = n
+				break
+			}
+		}
+	}
+	m := &Model{Name: name, Schema: s}
+	b.used[name] = true
+	b.models[s] = m
+	b.api.Models = append(b.api.Models, m)
+	b.buildModel(m, s)
+	return m
+}
+
+func (b *builder) buildModel(m *Model, s *openapi.Schema) {
+	m.Description = s.Description
+	m.Deprecated = s.Deprecated != nil && *s.Deprecated
+	if s.Ref != nil {
+		m.Kind = ModelAlias
+		m.Alias = b.typeOf(s, m.Name)
+		return
+	}
+	if s.AllOf != nil && len(s.AllOf.Items) > 0 {
+		s, _ = s.MergeAllOf()
+	}
+	switch {
+	case s.OneOf != nil && len(s.OneOf.Items) > 0:
+		m.Kind = ModelUnion
+		m.Exclusive = true
+		b.buildVariants(m, s, s.OneOf.Items)
+	case s.AnyOf != nil && len(s.AnyOf.Items) > 0:
+		m.Kind = ModelUnion
+		b.buildVariants(m, s, s.AnyOf.Items)
+	case isStringEnum(s):
+		m.Kind = ModelEnum
+		b.buildEnum(m, s)
+	case isObject(s):
+		m.Kind = ModelObject
+		b.buildFields(m, s)
+	default:
+		m.Kind = ModelAlias
+		m.Alias = b.describe(s, m.Name)
+	}
+}
+
+func (b *builder) buildFields(m *Model, s *openapi.Schema) {
+	required := map[openapi.Text]bool{}
+	for _, r := range s.Required {
+		required[r] = true
+	}
+	for _, f := range b.namer.Fields(s) {
+		p := s.Properties.Get(f.Key)
+		field := &Field{
+			Name:     f.Name,
+			Key:      f.Key,
+			Required: required[f.Key],
+			Type:     b.typeOf(p, m.Name+f.Name),
+		}
+		if p != nil {
+			field.Description = p.Description
+			field.Deprecated = p.Deprecated != nil && *p.Deprecated
+			field.ReadOnly = p.ReadOnly != nil && *p.ReadOnly
+			field.WriteOnly = p.WriteOnly != nil && *p.WriteOnly
+		}
+		m.Fields = append(m.Fields, field)
+	}
+	if ap := s.AdditionalProperties; ap != nil && !ap.IsNever() {
+		m.AdditionalProperties = b.typeOf(ap, m.Name+"Value")
+	}
+}
+
+func (b *builder) buildEnum(m *Model, s *openapi.Schema) {
+	values, _ := s.Enum.Texts()
+	names, ok, _ := openapi.AnnotationEnumVarNames.Get(s)
+	if ok && len(names) != len(values) {
+		ok = false
+	}
+	for i, v := range values {
+		ev := EnumValue{Value: v}
+		if ok {
+			ev.Name = names[i].String()
+		} else {
+			ev.Name = b.namer.Identifier(openapi.Text(m.Name + " " + v.String()))
+		}
+		m.Values = append(m.Values, ev)
+	}
+}
+
+// isStringEnum reports whether s has an enum of only strings. Enums of other
+// types are described as aliases of their type.
+func isStringEnum(s *openapi.Schema) bool {
+	if len(s.Enum) == 0 {
+		return false
+	}
+	_, ok := s.Enum.Texts()
+	return ok
+}
+
+func (b *builder) buildVariants(m *Model, s *openapi.Schema, branches []*openapi.Schema) {
+	if s.Discriminator != nil {
+		m.Discriminator = &Discriminator{
+			PropertyName: s.Discriminator.PropertyName,
+			Mapping:      map[string]openapi.Text{},
+		}
+	}
+	for i, branch := range branches {
+		v := &Variant{Type: b.typeOf(branch, fmt.Sprintf("%s%d", m.Name, i+1))}
+		switch v.Type.Kind {
+		case TypeModel:
+			v.Name = v.Type.Model
+		case TypePrimitive:
+			v.Name = b.namer.Identifier(openapi.Text(m.Name + " " + v.Type.Primitive.String()))
+		default:
+			v.Name = fmt.Sprintf("%s%d", m.Name, i+1)
+		}
+		if m.Discriminator != nil {
+			v.DiscriminatorValue = discriminatorValue(s.Discriminator, b.componentKey(branch))
+			if v.DiscriminatorValue != "" {
+				m.Discriminator.Mapping[v.Name] = v.DiscriminatorValue
+			}
+		}
+		m.Variants = append(m.Variants, v)
+	}
+}
+
+// componentKey returns the key of the component Schema referenced by s, if
+// any
+func (b *builder) componentKey(s *openapi.Schema) openapi.Text {
+	if s == nil || s.Ref == nil || s.Ref.Resolved == nil {
+		return ""
+	}
+	if m, ok := b.models[s.Ref.Resolved]; ok {
+		return m.Key
+	}
+	return ""
+}
+
+// discriminatorValue returns the value of the discriminator property which
+// selects the component key. Explicit mappings take precedence over the
+// implicit value, which is the component's key.
+func discriminatorValue(d *openapi.Discriminator, key openapi.Text) openapi.Text {
+	if key == "" {
+		return ""
+	}
+	if d.Mapping != nil {
+		for _, kv := range d.Mapping.Items {
+			if kv.Value == key || strings.HasSuffix(kv.Value.String(), "/"+key.String()) {
+				return kv.Key
+			}
+		}
+	}
+	return key
+}
+
+// typeOf returns the TypeRef of s. Inline objects, enums, and unions are
+// declared as Models named after hint.
+func (b *builder) typeOf(s *openapi.Schema, hint string) *TypeRef {
+	if s == nil {
+		return &TypeRef{Kind: TypeAny}
+	}
+	if s.Ref != nil {
+		r := s.Ref.Resolved
+		if r == nil || b.visiting[r] {
+			return &TypeRef{Kind: TypeAny, Schema: s}
+		}
+		if m, ok := b.models[r]; ok {
+			return &TypeRef{Kind: TypeModel, Model: m.Name, Nullable: s.IsNullable(), Schema: s}
+		}
+		b.visiting[r] = true
+		defer delete(b.visiting, r)
+		return b.typeOf(r, hint)
+	}
+	if m, ok := b.models[s]; ok {
+		return &TypeRef{Kind: TypeModel, Model: m.Name, Nullable: s.IsNullable(), Schema: s}
+	}
+	return b.describe(s, hint)
+}
+
+// describe returns the TypeRef of s without regard to whether s is the
+// Schema of a Model
+func (b *builder) describe(s *openapi.Schema, hint string) *TypeRef {
+	if s.AllOf != nil && len(s.AllOf.Items) > 0 {
+		// an allOf of a single reference, such as one used to add a
+		// description, is the referenced type
+		if len(s.AllOf.Items) == 1 && s.AllOf.Items[0].Ref != nil && !isObject(s) {
+			t := b.typeOf(s.AllOf.Items[0], hint)
+			t.Nullable = t.Nullable || s.IsNullable()
+			return t
+		}
+		return &TypeRef{Kind: TypeModel, Model: b.inline(hint, s).Name, Nullable: s.IsNullable(), Schema: s}
+	}
+	if (s.OneOf != nil && len(s.OneOf.Items) > 0) || (s.AnyOf != nil && len(s.AnyOf.Items) > 0) || isStringEnum(s) {
+		return &TypeRef{Kind: TypeModel, Model: b.inline(hint, s).Name, Nullable: s.IsNullable(), Schema: s}
+	}
+	t := &TypeRef{Nullable: s.IsNullable(), Schema: s}
+	var types openapi.Types
+	for _, typ := range s.Type {
+		if typ != openapi.TypeNull {
+			types = append(types, typ)
+		}
+	}
+	switch {
+	case len(types) != 1 && !(len(types) == 0 && s.Properties != nil):
+		t.Kind = TypeAny
+	case types.ContainsArray():
+		t.Kind = TypeArray
+		t.Elem = b.typeOf(s.Items, hint+"Item")
+	case s.Properties != nil && len(s.Properties.Items) > 0:
+		t.Kind = TypeModel
+		t.Model = b.inline(hint, s).Name
+	case types.ContainsObject():
+		t.Kind = TypeMap
+		if s.AdditionalProperties != nil && !s.AdditionalProperties.IsNever() {
+			t.Elem = b.typeOf(s.AdditionalProperties, hint+"Value")
+		} else {
+			t.Elem = &TypeRef{Kind: TypeAny}
+		}
+	default:
+		t.Kind = TypePrimitive
+		t.Primitive = types[0]
+		t.Format = s.Format
+	}
+	return t
+}
+
+func (b *builder) buildOperations() error {
+	var entries []openapi.OperationEntry
+	for _, op := range b.doc.Operations() {
+		if op.Operation == nil || (op.Webhook && !b.opts.Webhooks) {
+			continue
+		}
+		entries = append(entries, op)
+	}
+	used := map[string]bool{}
+	for _, entry := range entries {
+		op, err := b.buildOperation(entry)
+		if err != nil {
+			return err
+		}
+		name := op.Name
+		for i := 2; used[op.Name]; i++ {
+			op.Name = fmt.Sprintf("%s%d", name, i)
+		}
+		used[op.Name] = true
+		b.api.Operations = append(b.api.Operations, op)
+	}
+	return nil
+}
+
+func (b *builder) buildOperation(entry openapi.OperationEntry) (*Operation, error) {
+	o := entry.Operation
+	op := &Operation{
+		ID:          o.OperationID,
+		Method:      entry.Method,
+		Path:        entry.Key,
+		Webhook:     entry.Webhook,
+		Summary:     o.Summary,
+		Description: o.Description,
+		Tags:        o.Tags,
+		Deprecated:  o.Deprecated,
+		Source:      o,
+	}
+	if o.OperationID != "" {
+		op.Name = b.namer.Identifier(o.OperationID)
+	} else {
+		path := strings.NewReplacer("{", " ", "}", " ", "/", " ").Replace(entry.Key.String())
+		op.Name = b.namer.Identifier(openapi.Text(strings.ToLower(entry.Method.String()) + " " + path))
+	}
+
+	params, err := o.EffectiveParameters(entry.PathItem)
+	if err != nil {
+		return nil, fmt.Errorf("ir: failed to build %s %s: %w", strings.ToUpper(entry.Method.String()), entry.Key, err)
+	}
+	for _, p := range params {
+		op.Parameters = append(op.Parameters, b.buildParameter(op, p))
+	}
+
+	if rb := o.RequestBody; rb != nil && rb.Object != nil {
+		body := &Body{
+			Description: rb.Object.Description,
+			Required:    rb.Object.Required,
+			Source:      rb.Object,
+		}
+		var mt *openapi.MediaType
+		body.ContentType, body.ContentTypes, mt = b.negotiate(rb.Object.Content)
+		if mt != nil {
+			body.Type = b.typeOf(mt.Schema, op.Name+"Request")
+		}
+		op.Body = body
+	}
+
+	if o.Responses != nil {
+		for _, item := range o.Responses.Items {
+			if item.Component == nil || item.Component.Object == nil {
+				continue
+			}
+			op.Responses = append(op.Responses, b.buildResponse(op, item.Key, item.Component.Object))
+		}
+	}
+	return op, nil
+}
+
+func (b *builder) buildParameter(op *Operation, p *openapi.Parameter) *Parameter {
+	style, explode := p.EffectiveStyle()
+	return &Parameter{
+		Name:        p.Name,
+		FieldName:   b.namer.Identifier(p.Name),
+		In:          p.In,
+		Description: p.Description,
+		Required:    p.In == openapi.InPath || (p.Required != nil && *p.Required),
+		Deprecated:  p.Deprecated,
+		Style:       style,
+		Explode:     explode,
+		Type:        b.typeOf(p.Schema, op.Name+b.namer.Identifier(p.Name)),
+		Source:      p,
+	}
+}
+
+func (b *builder) buildResponse(op *Operation, code openapi.Text, r *openapi.Response) *Response {
+	res := &Response{
+		StatusCode:  code,
+		Default:     code == "default",
+		Success:     strings.HasPrefix(code.String(), "2"),
+		Description: r.Description,
+		Source:      r,
+	}
+	var mt *openapi.MediaType
+	res.ContentType, res.ContentTypes, mt = b.negotiate(r.Content)
+	if mt != nil {
+		res.Type = b.typeOf(mt.Schema, op.Name+" "+code.String()+" Response")
+	}
+	if r.Headers != nil {
+		for _, item := range r.Headers.Items {
+			if item.Component == nil || item.Component.Object == nil {
+				continue
+			}
+			h := item.Component.Object
+			res.Headers = append(res.Headers, &Header{
+				Name:        item.Key,
+				FieldName:   b.namer.Identifier(item.Key),
+				Description: h.Description,
+				Required:    h.Required != nil && *h.Required,
+				Type:        b.typeOf(h.Schema, op.Name+b.namer.Identifier(item.Key)+"Header"),
+			})
+		}
+	}
+	return res
+}
+
+// negotiate returns the preferred media type of content along with each of
+// the media types defined
+func (b *builder) negotiate(content *openapi.ContentMap) (openapi.Text, []openapi.Text, *openapi.MediaType) {
+	if content == nil || len(content.Items) == 0 {
+		return "", nil, nil
+	}
+	types := make([]openapi.Text, len(content.Items))
+	for i, item := range content.Items {
+		types[i] = item.Key
+	}
+	for _, pref := range b.opts.ContentTypes {
+		for _, item := range content.Items {
+			if item.Key == pref {
+				return item.Key, types, item.Value
+			}
+		}
+	}
+	for _, item := range content.Items {
+		if strings.HasSuffix(item.Key.String(), "+json") {
+			return item.Key, types, item.Value
+		}
+	}
+	return content.Items[0].Key, types, content.Items[0].Value
+}
+
+func isObject(s *openapi.Schema) bool {
+	if s.Properties != nil && len(s.Properties.Items) > 0 {
+		return true
+	}
+	return s.Type.ContainsObject() && !s.Type.ContainsArray()
+}
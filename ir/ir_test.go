@@ -0,0 +1,197 @@
+package ir_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/chanced/openapi"
+	"github.com/chanced/openapi/ir"
+	"github.com/chanced/uri"
+)
+
+func TestBuild(t *testing.T) {
+	data := []byte(`{
+		"openapi": "3.1.0",
+		"info": { "title": "Pet Store", "version": "1.0.0" },
+		"servers": [{ "url": "https://example.com/v1" }],
+		"paths": {
+			"/pets/{petId}": {
+				"parameters": [
+					{ "name": "petId", "in": "path", "required": true, "schema": { "type": "integer", "format": "int64" } }
+				],
+				"get": {
+					"operationId": "get_pet",
+					"parameters": [
+						{ "name": "fields", "in": "query", "schema": { "type": "array", "items": { "type": "string" } } }
+					],
+					"responses": {
+						"200": {
+							"description": "ok",
+							"headers": {
+								"X-Rate-Limit": { "schema": { "type": "integer" } }
+							},
+							"content": {
+								"application/xml": { "schema": { "type": "string" } },
+								"application/json": { "schema": { "$ref": "#/components/schemas/Animal" } }
+							}
+						},
+						"default": { "description": "error" }
+					}
+				}
+			},
+			"/pets": {
+				"post": {
+					"requestBody": {
+						"required": true,
+						"content": {
+							"application/vnd.pet+json": { "schema": { "$ref": "#/components/schemas/Dog" } }
+						}
+					},
+					"responses": {
+						"201": {
+							"description": "created",
+							"content": {
+								"application/json": {
+									"schema": { "type": "object", "properties": { "id": { "type": "integer" } } }
+								}
+							}
+						}
+					}
+				}
+			}
+		},
+		"components": {
+			"schemas": {
+				"Base": {
+					"type": "object",
+					"required": ["id"],
+					"properties": {
+						"id": { "type": "integer" },
+						"kind": { "type": "string" }
+					}
+				},
+				"Dog": {
+					"allOf": [
+						{ "$ref": "#/components/schemas/Base" },
+						{
+							"type": "object",
+							"properties": {
+								"status": { "type": "string", "enum": ["good", "very_good"] },
+								"owner": { "type": "object", "properties": { "name": { "type": "string" } } },
+								"tags": { "type": ["array", "null"], "items": { "type": "string" } }
+							}
+						}
+					]
+				},
+				"Cat": {
+					"type": "object",
+					"properties": { "lives": { "type": "integer" } }
+				},
+				"Animal": {
+					"oneOf": [
+						{ "$ref": "#/components/schemas/Dog" },
+						{ "$ref": "#/components/schemas/Cat" }
+					],
+					"discriminator": {
+						"propertyName": "kind",
+						"mapping": { "canine": "#/components/schemas/Dog" }
+					}
+				},
+				"Names": {
+					"type": "array",
+					"items": { "type": "string" }
+				}
+			}
+		}
+	}`)
+	fn := func(ctx context.Context, u uri.URI, kind openapi.Kind) (openapi.Kind, []byte, error) {
+		return openapi.KindDocument, data, nil
+	}
+	v, err := openapi.NewOfflineValidator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := openapi.Load(context.Background(), "https://example.com/openapi.json", v, fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	api, err := ir.Build(doc, ir.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if api.Title != "Pet Store" || len(api.Servers) != 1 {
+		t.Errorf("unexpected api: %+v", api)
+	}
+
+	dog, ok := api.Model("Dog")
+	if !ok || dog.Kind != ir.ModelObject {
+		t.Fatalf("expected Dog to be an object model, got %+v", dog)
+	}
+	names := []string{}
+	for _, f := range dog.Fields {
+		names = append(names, f.Name)
+	}
+	if len(dog.Fields) != 5 || !dog.Fields[0].Required || dog.Fields[0].Name != "ID" {
+		t.Fatalf("expected the fields of Base to be merged into Dog, got %v", names)
+	}
+	status := dog.Fields[2].Type
+	if status.Kind != ir.TypeModel || status.Model != "DogStatus" {
+		t.Errorf("expected inline enum model DogStatus, got %+v", status)
+	}
+	if m, _ := api.Model("DogStatus"); m == nil || m.Kind != ir.ModelEnum || m.Values[1].Name != "DogStatusVeryGood" {
+		t.Errorf("unexpected enum: %+v", m)
+	}
+	if owner := dog.Fields[3].Type; owner.Kind != ir.TypeModel || owner.Model != "DogOwner" {
+		t.Errorf("expected inline model DogOwner, got %+v", owner)
+	}
+	if tags := dog.Fields[4].Type; tags.Kind != ir.TypeArray || !tags.Nullable || tags.Elem.Primitive != openapi.TypeString {
+		t.Errorf("unexpected tags type: %+v", tags)
+	}
+
+	animal, _ := api.Model("Animal")
+	if animal == nil || animal.Kind != ir.ModelUnion || !animal.Exclusive || len(animal.Variants) != 2 {
+		t.Fatalf("expected Animal to be a union, got %+v", animal)
+	}
+	if animal.Variants[0].DiscriminatorValue != "canine" || animal.Variants[1].DiscriminatorValue != "Cat" {
+		t.Errorf("unexpected discriminator values: %+v %+v", animal.Variants[0], animal.Variants[1])
+	}
+	if names, _ := api.Model("Names"); names == nil || names.Kind != ir.ModelAlias || names.Alias.Kind != ir.TypeArray {
+		t.Errorf("expected Names to be an alias of an array, got %+v", names)
+	}
+
+	get, ok := api.Operation("GetPet")
+	if !ok {
+		t.Fatal("expected operation GetPet")
+	}
+	if len(get.Parameters) != 2 || get.Parameters[0].Name != "petId" || !get.Parameters[0].Required {
+		t.Fatalf("expected path item parameters to be flattened, got %+v", get.Parameters)
+	}
+	fields := get.ParametersIn(openapi.InQuery)
+	if len(fields) != 1 || fields[0].Style != "form" || !fields[0].Explode || fields[0].Type.Kind != ir.TypeArray {
+		t.Errorf("unexpected query parameter: %+v", fields)
+	}
+	res := get.SuccessResponse()
+	if res == nil || res.ContentType != "application/json" || len(res.ContentTypes) != 2 {
+		t.Fatalf("expected application/json to be negotiated, got %+v", res)
+	}
+	if res.Type.Kind != ir.TypeModel || res.Type.Model != "Animal" {
+		t.Errorf("expected response type Animal, got %+v", res.Type)
+	}
+	if len(res.Headers) != 1 || res.Headers[0].FieldName != "XRateLimit" {
+		t.Errorf("unexpected headers: %+v", res.Headers)
+	}
+	if len(get.Responses) != 2 || !get.Responses[1].Default || get.Responses[1].Type != nil {
+		t.Errorf("unexpected responses: %+v", get.Responses)
+	}
+
+	post, ok := api.Operation("PostPets")
+	if !ok {
+		t.Fatal("expected operation PostPets")
+	}
+	if post.Body == nil || post.Body.ContentType != "application/vnd.pet+json" || post.Body.Type.Model != "Dog" {
+		t.Errorf("unexpected body: %+v", post.Body)
+	}
+	if created := post.SuccessResponse(); created == nil || created.Type.Model != "PostPets201Response" {
+		t.Errorf("expected inline response model, got %+v", created)
+	}
+}
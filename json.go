@@ -44,7 +44,7 @@ func (j *OrderedJSONObj) UnmarshalJSON(data []byte) error {
 	case jsonx.TypeObject:
 		gjson.ParseBytes(data).ForEach(func(key, value gjson.Result) bool {
 			v = append(v, JSONObjEntry{
-				Key:   Text(key.String()),
+				Key:   intern(key.String()),
 				Value: jsonx.RawMessage(value.Raw),
 			})
 			return true
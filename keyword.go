@@ -0,0 +1,217 @@
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/chanced/jsonx"
+	"github.com/chanced/uri"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// ErrKeywordNotRegistered is returned when a keyword is expected to be
+// registered with a KeywordRegistry but is not.
+var ErrKeywordNotRegistered = errors.New("openapi: keyword not registered")
+
+// Keyword defines the behavior of a custom JSON Schema keyword. Unknown
+// keywords of a Schema are stored in Schema.Keywords; registering a Keyword
+// with a KeywordRegistry allows for them to be decoded and validated.
+type Keyword struct {
+	// Name of the keyword. It may not start with "x-" nor be a keyword which
+	// is already defined by Schema.
+	Name Text
+	// Dialects the keyword is applicable to. If empty, the keyword is
+	// applicable to all dialects.
+	Dialects []uri.URI
+	// Decode decodes the raw JSON value of the keyword into its typed
+	// representation. If nil, the value is decoded with json.Unmarshal into
+	// an interface{}.
+	Decode func(data jsonx.RawMessage) (interface{}, error)
+	// ValidateSchema, if set, is invoked by Load for each Schema which
+	// contains the keyword with the decoded value of the keyword.
+	ValidateSchema func(schema *Schema, value interface{}) error
+	// ValidateInstance, if set, is invoked when validating an instance
+	// against a CompiledSchema of a Schema which contains the keyword.
+	ValidateInstance func(value interface{}, instance interface{}) error
+}
+
+func (kw Keyword) appliesTo(dialect uri.URI) bool {
+	if len(kw.Dialects) == 0 {
+		return true
+	}
+	for _, d := range kw.Dialects {
+		if d.String() == dialect.String() {
+			return true
+		}
+	}
+	return false
+}
+
+func (kw Keyword) decode(data jsonx.RawMessage) (interface{}, error) {
+	if kw.Decode != nil {
+		return kw.Decode(data)
+	}
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// KeywordRegistry is a registry of custom Keywords, keyed by name and
+// dialect.
+type KeywordRegistry struct {
+	keywords map[Text][]Keyword
+}
+
+// NewKeywordRegistry creates a new KeywordRegistry with the given keywords
+// registered.
+func NewKeywordRegistry(keywords ...Keyword) (*KeywordRegistry, error) {
+	kr := &KeywordRegistry{keywords: map[Text][]Keyword{}}
+	for _, kw := range keywords {
+		if err := kr.Register(kw); err != nil {
+			return nil, err
+		}
+	}
+	return kr, nil
+}
+
+// Register adds kw to the registry.
+//
+// An error is returned if kw's Name is empty, starts with "x-", is a keyword
+// defined by Schema, or has already been registered for any of kw's Dialects.
+func (kr *KeywordRegistry) Register(kw Keyword) error {
+	if kw.Name == "" {
+		return errors.New("openapi: keyword name is required")
+	}
+	if kw.Name.HasPrefix("x-") {
+		return fmt.Errorf("openapi: keyword %q may not start with \"x-\"", kw.Name)
+	}
+	if (&Schema{}).field(kw.Name.String()) != nil {
+		return fmt.Errorf("openapi: keyword %q is defined by Schema", kw.Name)
+	}
+	if kr.keywords == nil {
+		kr.keywords = map[Text][]Keyword{}
+	}
+	for _, existing := range kr.keywords[kw.Name] {
+		if len(existing.Dialects) == 0 || len(kw.Dialects) == 0 {
+			return fmt.Errorf("openapi: keyword %q is already registered", kw.Name)
+		}
+		for _, d := range kw.Dialects {
+			if existing.appliesTo(d) {
+				return fmt.Errorf("openapi: keyword %q is already registered for %s", kw.Name, d.String())
+			}
+		}
+	}
+	kr.keywords[kw.Name] = append(kr.keywords[kw.Name], kw)
+	return nil
+}
+
+// Lookup returns the Keyword registered as name for dialect.
+func (kr *KeywordRegistry) Lookup(dialect uri.URI, name Text) (Keyword, bool) {
+	if kr == nil {
+		return Keyword{}, false
+	}
+	for _, kw := range kr.keywords[name] {
+		if kw.appliesTo(dialect) {
+			return kw, true
+		}
+	}
+	return Keyword{}, false
+}
+
+// Decode decodes the value of keyword name of s with the Keyword registered
+// for dialect. If s does not contain the keyword, nil is returned.
+func (kr *KeywordRegistry) Decode(s *Schema, dialect uri.URI, name Text) (interface{}, error) {
+	kw, ok := kr.Lookup(dialect, name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrKeywordNotRegistered, name)
+	}
+	if s == nil {
+		return nil, nil
+	}
+	data, ok := s.Keywords[name]
+	if !ok {
+		return nil, nil
+	}
+	v, err := kw.decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("openapi: failed to decode keyword %q: %w", name, err)
+	}
+	return v, nil
+}
+
+// ValidateSchema decodes and validates each of the registered keywords
+// present in s's Keywords. Subschemas of s are not validated.
+func (kr *KeywordRegistry) ValidateSchema(s *Schema, dialect uri.URI) error {
+	if kr == nil || s == nil {
+		return nil
+	}
+	for name, data := range s.Keywords {
+		kw, ok := kr.Lookup(dialect, name)
+		if !ok {
+			continue
+		}
+		v, err := kw.decode(data)
+		if err != nil {
+			return fmt.Errorf("openapi: failed to decode keyword %q: %w", name, err)
+		}
+		if kw.ValidateSchema == nil {
+			continue
+		}
+		if err = kw.ValidateSchema(s, v); err != nil {
+			return fmt.Errorf("openapi: invalid keyword %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// register registers each Keyword applicable to dialect which has
+// ValidateInstance set as an extension of compiler.
+func (kr *KeywordRegistry) register(compiler *jsonschema.Compiler, dialect uri.URI) {
+	if kr == nil {
+		return
+	}
+	for name := range kr.keywords {
+		kw, ok := kr.Lookup(dialect, name)
+		if !ok || kw.ValidateInstance == nil {
+			continue
+		}
+		compiler.RegisterExtension(name.String(), nil, keywordExtension{kw})
+	}
+}
+
+// keywordExtension adapts a Keyword to the extension interfaces of
+// github.com/santhosh-tekuri/jsonschema/v5
+type keywordExtension struct {
+	keyword Keyword
+}
+
+func (ke keywordExtension) Compile(ctx jsonschema.CompilerContext, m map[string]interface{}) (jsonschema.ExtSchema, error) {
+	raw, ok := m[ke.keyword.Name.String()]
+	if !ok {
+		return nil, nil
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	v, err := ke.keyword.decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("openapi: failed to decode keyword %q: %w", ke.keyword.Name, err)
+	}
+	return keywordSchema{keyword: ke.keyword, value: v}, nil
+}
+
+type keywordSchema struct {
+	keyword Keyword
+	value   interface{}
+}
+
+func (ks keywordSchema) Validate(ctx jsonschema.ValidationContext, v interface{}) error {
+	if err := ks.keyword.ValidateInstance(ks.value, v); err != nil {
+		return ctx.Error(ks.keyword.Name.String(), "%v", err)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package openapi_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/chanced/jsonx"
+	"github.com/chanced/openapi"
+)
+
+func TestKeywordRegistry(t *testing.T) {
+	even := openapi.Keyword{
+		Name: "even",
+		Decode: func(data jsonx.RawMessage) (interface{}, error) {
+			var b bool
+			err := json.Unmarshal(data, &b)
+			return b, err
+		},
+		ValidateSchema: func(s *openapi.Schema, value interface{}) error {
+			if !s.Type.Contains(openapi.TypeInteger) {
+				return errors.New("even requires an integer type")
+			}
+			return nil
+		},
+		ValidateInstance: func(value interface{}, instance interface{}) error {
+			n, ok := instance.(float64)
+			if ok && value.(bool) && int(n)%2 != 0 {
+				return fmt.Errorf("%v is not even", n)
+			}
+			return nil
+		},
+	}
+	kr, err := openapi.NewKeywordRegistry(even)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = kr.Register(even); err == nil {
+		t.Error("expected an error registering a duplicate keyword")
+	}
+	if err = kr.Register(openapi.Keyword{Name: "minimum"}); err == nil {
+		t.Error("expected an error registering a keyword defined by Schema")
+	}
+	if err = kr.Register(openapi.Keyword{Name: "x-even"}); err == nil {
+		t.Error("expected an error registering an extension as a keyword")
+	}
+
+	var s openapi.Schema
+	if err = json.Unmarshal([]byte(`{ "type": "integer", "even": true }`), &s); err != nil {
+		t.Fatal(err)
+	}
+	v, err := kr.Decode(&s, openapi.JSONSchemaDialect202012, "even")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != true {
+		t.Errorf("expected true, got %v", v)
+	}
+	if err = kr.ValidateSchema(&s, openapi.JSONSchemaDialect202012); err != nil {
+		t.Error(err)
+	}
+	cs, err := s.Compile(context.Background(), openapi.CompileOpts{Keywords: kr})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = cs.Validate(float64(4)); err != nil {
+		t.Errorf("expected 4 to be valid, got %v", err)
+	}
+	if err = cs.Validate(float64(3)); err == nil {
+		t.Error("expected 3 to be invalid")
+	}
+
+	s.Type = openapi.Types{openapi.TypeString}
+	if err = kr.ValidateSchema(&s, openapi.JSONSchemaDialect202012); err == nil {
+		t.Error("expected schema validation to fail for a string type")
+	}
+}
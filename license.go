@@ -3,7 +3,6 @@ package openapi
 import (
 	"encoding/json"
 
-	"github.com/chanced/transcode"
 	"github.com/chanced/uri"
 	"gopkg.in/yaml.v3"
 )
@@ -32,6 +31,13 @@ func (*License) Kind() Kind      { return KindLicense }
 func (*License) sliceKind() Kind { return KindUndefined }
 func (*License) mapKind() Kind   { return KindUndefined }
 
+func (l *License) Nodes() []Node {
+	if l == nil {
+		return nil
+	}
+	return downcastNodes(l.nodes())
+}
+
 func (*License) nodes() []node        { return nil }
 func (l *License) isNil() bool        { return l == nil }
 func (l *License) location() Location { return l.Location }
@@ -88,11 +94,7 @@ func (l License) MarshalYAML() (interface{}, error) {
 
 // UnmarshalYAML implements yaml.Unmarshaler
 func (l *License) UnmarshalYAML(value *yaml.Node) error {
-	v, err := yaml.Marshal(value)
-	if err != nil {
-		return err
-	}
-	j, err := transcode.JSONFromYAML(v)
+	j, err := jsonFromYAMLNode(value)
 	if err != nil {
 		return err
 	}
@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 
 	"github.com/chanced/jsonx"
-	"github.com/chanced/transcode"
 	"gopkg.in/yaml.v3"
 )
 
@@ -138,11 +137,7 @@ func (l Link) MarshalYAML() (interface{}, error) {
 
 // UnmarshalYAML satisfies gopkg.in/yaml.v3 Unmarshaler interface
 func (l *Link) UnmarshalYAML(value *yaml.Node) error {
-	v, err := yaml.Marshal(value)
-	if err != nil {
-		return err
-	}
-	j, err := transcode.JSONFromYAML(v)
+	j, err := jsonFromYAMLNode(value)
 	if err != nil {
 		return err
 	}
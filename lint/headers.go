@@ -0,0 +1,83 @@
+package lint
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/chanced/openapi"
+)
+
+var (
+	// HeaderNameCase reports headers of a Response or Encoding whose names
+	// differ only by case. Header names are case-insensitive (RFC 7230), so
+	// only one of them is applicable.
+	HeaderNameCase = NewRule(
+		"header-name-case",
+		"Header names must be unique, regardless of case.",
+		SeverityWarn,
+		checkHeaderNameCase,
+	)
+
+	// HeaderContentType reports "Content-Type" headers of a Response or
+	// Encoding, which are ignored as the content type is described
+	// separately.
+	HeaderContentType = NewRule(
+		"header-content-type",
+		"Content-Type must not be defined as a header.",
+		SeverityWarn,
+		checkHeaderContentType,
+	)
+)
+
+// headerMaps calls fn with the headers of each Response and Encoding of doc
+// which has them, along with a description of the owner
+func headerMaps(doc *openapi.Document, fn func(owner string, hm *openapi.HeaderMap)) {
+	walk(doc, func(n openapi.Node) {
+		switch v := n.(type) {
+		case *openapi.Response:
+			if v.Headers != nil {
+				fn("response", v.Headers)
+			}
+		case *openapi.Encoding:
+			if v.Headers != nil {
+				fn("encoding", v.Headers)
+			}
+		}
+	})
+}
+
+func checkHeaderNameCase(doc *openapi.Document) []Issue {
+	var issues []Issue
+	headerMaps(doc, func(owner string, hm *openapi.HeaderMap) {
+		first := map[string]openapi.Text{}
+		for _, item := range hm.Items {
+			folded := strings.ToLower(item.Key.String())
+			prev, ok := first[folded]
+			if !ok {
+				first[folded] = item.Key
+				continue
+			}
+			issues = append(issues, Issue{
+				Message:  fmt.Sprintf("%s header %q differs from header %q only by case", owner, item.Key, prev),
+				Location: hm.Location.AppendLocation(item.Key.String()).AbsoluteLocation(),
+			})
+		}
+	})
+	return issues
+}
+
+func checkHeaderContentType(doc *openapi.Document) []Issue {
+	var issues []Issue
+	headerMaps(doc, func(owner string, hm *openapi.HeaderMap) {
+		for _, item := range hm.Items {
+			if !strings.EqualFold(item.Key.String(), "Content-Type") {
+				continue
+			}
+			issues = append(issues, Issue{
+				Message:  fmt.Sprintf("%s header %q is ignored; the content type is described by the media type", owner, item.Key),
+				Location: hm.Location.AppendLocation(item.Key.String()).AbsoluteLocation(),
+			})
+		}
+	})
+	return issues
+}
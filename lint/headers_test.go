@@ -0,0 +1,66 @@
+package lint_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/chanced/openapi"
+	"github.com/chanced/openapi/lint"
+)
+
+func TestHeaderRules(t *testing.T) {
+	data := []byte(`{
+		"openapi": "3.1.0",
+		"info": { "title": "headers", "version": "1.0.0" },
+		"paths": {
+			"/pets": {
+				"get": {
+					"operationId": "listPets",
+					"responses": {
+						"200": {
+							"description": "ok",
+							"headers": {
+								"X-Rate-Limit": { "schema": { "type": "integer" } },
+								"x-rate-limit": { "schema": { "type": "integer" } },
+								"content-type": { "schema": { "type": "string" } }
+							}
+						}
+					}
+				}
+			}
+		}
+	}`)
+	var doc openapi.Document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	l, err := lint.NewLinter(lint.Config{}, lint.HeaderNameCase, lint.HeaderContentType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	issues := l.Lint(&doc)
+	if len(issues) != 2 {
+		t.Fatalf("expected 2 issues, got %d: %v", len(issues), issues)
+	}
+	rules := map[string]bool{}
+	for _, i := range issues {
+		rules[i.Rule] = true
+		if i.Severity != lint.SeverityWarn {
+			t.Errorf("expected a warning, got %v", i)
+		}
+	}
+	if !rules["header-name-case"] || !rules["header-content-type"] {
+		t.Errorf("unexpected issues: %v", issues)
+	}
+
+	res := doc.Paths.Get("/pets").Get.Responses.Get("200").Object
+	if c := res.Header("X-RATE-LIMIT"); c == nil || c != res.Headers.Get("X-Rate-Limit") {
+		t.Error("expected a case-insensitive match")
+	}
+	if c := res.Header("x-rate-limit"); c != res.Headers.Get("x-rate-limit") {
+		t.Error("expected an exact match to be preferred")
+	}
+	if res.HasHeader("X-Request-Id") {
+		t.Error("expected X-Request-Id to be missing")
+	}
+}
@@ -0,0 +1,229 @@
+// Package lint provides configurable, rule-based linting of OpenAPI
+// Documents.
+//
+// A Linter evaluates a set of Rules against an *openapi.Document and returns
+// the Issues found. The Severity of each Rule can be configured, or the Rule
+// disabled entirely, with Config.
+package lint
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/chanced/openapi"
+	"github.com/chanced/uri"
+)
+
+// Severity is the severity of an Issue.
+type Severity uint8
+
+const (
+	// SeverityOff disables a Rule.
+	SeverityOff Severity = iota
+	// SeverityHint indicates a suggestion.
+	SeverityHint
+	// SeverityInfo indicates an informational Issue.
+	SeverityInfo
+	// SeverityWarn indicates an Issue which should be addressed.
+	SeverityWarn
+	// SeverityError indicates an Issue which must be addressed.
+	SeverityError
+)
+
+var severityNames = [...]string{
+	SeverityOff:   "off",
+	SeverityHint:  "hint",
+	SeverityInfo:  "info",
+	SeverityWarn:  "warn",
+	SeverityError: "error",
+}
+
+func (s Severity) String() string {
+	if int(s) < len(severityNames) {
+		return severityNames[s]
+	}
+	return fmt.Sprintf("Severity(%d)", s)
+}
+
+// ParseSeverity parses the name of a Severity (e.g. "warn").
+func ParseSeverity(name string) (Severity, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for i, n := range severityNames {
+		if n == name {
+			return Severity(i), nil
+		}
+	}
+	if name == "warning" {
+		return SeverityWarn, nil
+	}
+	return SeverityOff, fmt.Errorf("lint: unknown severity %q", name)
+}
+
+// MarshalText satisfies encoding.TextMarshaler
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText satisfies encoding.TextUnmarshaler
+func (s *Severity) UnmarshalText(data []byte) error {
+	v, err := ParseSeverity(string(data))
+	if err != nil {
+		return err
+	}
+	*s = v
+	return nil
+}
+
+// Issue is a violation of a Rule.
+type Issue struct {
+	// Rule is the name of the Rule which reported the Issue.
+	Rule string `json:"rule"`
+	// Severity of the Issue.
+	Severity Severity `json:"severity"`
+	// Message describes the Issue.
+	Message string `json:"message"`
+	// Location is the absolute location of the offending node.
+	Location uri.URI `json:"location"`
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf("%s [%s] %s: %s", i.Location.String(), i.Severity, i.Rule, i.Message)
+}
+
+// Issues is a list of Issue
+type Issues []Issue
+
+// HasErrors reports whether any of the Issues have a Severity of
+// SeverityError.
+func (is Issues) HasErrors() bool {
+	for _, i := range is {
+		if i.Severity >= SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// AtLeast returns the Issues with a Severity greater than or equal to s.
+func (is Issues) AtLeast(s Severity) Issues {
+	var res Issues
+	for _, i := range is {
+		if i.Severity >= s {
+			res = append(res, i)
+		}
+	}
+	return res
+}
+
+// Rule is a check performed against a Document.
+type Rule interface {
+	// Name uniquely identifies the Rule (e.g. "operation-operationId").
+	Name() string
+	// Description is a short, human readable description of the Rule.
+	Description() string
+	// Severity is the default Severity of Issues reported by the Rule.
+	Severity() Severity
+	// Check evaluates doc, returning any violations. The Rule and Severity
+	// of the returned Issues are assigned by the Linter.
+	Check(doc *openapi.Document) []Issue
+}
+
+// NewRule creates a new Rule which calls check to evaluate a Document.
+func NewRule(name, description string, severity Severity, check func(doc *openapi.Document) []Issue) Rule {
+	return &rule{
+		name:        name,
+		description: description,
+		severity:    severity,
+		check:       check,
+	}
+}
+
+type rule struct {
+	name        string
+	description string
+	severity    Severity
+	check       func(doc *openapi.Document) []Issue
+}
+
+func (r *rule) Name() string                        { return r.name }
+func (r *rule) Description() string                 { return r.description }
+func (r *rule) Severity() Severity                  { return r.severity }
+func (r *rule) Check(doc *openapi.Document) []Issue { return r.check(doc) }
+
+// Config configures a Linter.
+type Config struct {
+	// Severities overrides the default Severity of Rules, keyed by Rule name.
+	// A Severity of SeverityOff disables the Rule.
+	Severities map[string]Severity `json:"severities,omitempty"`
+}
+
+// Linter evaluates Rules against Documents.
+type Linter struct {
+	rules  []Rule
+	config Config
+}
+
+// NewLinter creates a new Linter with rules. If rules is empty, the Rules
+// returned from DefaultRules are used.
+//
+// An error is returned if two Rules share the same name or if config
+// references a Rule which is not present.
+func NewLinter(config Config, rules ...Rule) (*Linter, error) {
+	if len(rules) == 0 {
+		rules = DefaultRules()
+	}
+	names := make(map[string]struct{}, len(rules))
+	for _, r := range rules {
+		if _, ok := names[r.Name()]; ok {
+			return nil, fmt.Errorf("lint: duplicate rule %q", r.Name())
+		}
+		names[r.Name()] = struct{}{}
+	}
+	for name := range config.Severities {
+		if _, ok := names[name]; !ok {
+			return nil, fmt.Errorf("lint: unknown rule %q", name)
+		}
+	}
+	return &Linter{rules: rules, config: config}, nil
+}
+
+// Rules returns the Rules of the Linter.
+func (l *Linter) Rules() []Rule {
+	return append([]Rule(nil), l.rules...)
+}
+
+// Severity returns the configured Severity of the Rule r.
+func (l *Linter) Severity(r Rule) Severity {
+	if s, ok := l.config.Severities[r.Name()]; ok {
+		return s
+	}
+	return r.Severity()
+}
+
+// Lint evaluates each enabled Rule against doc. The returned Issues are
+// ordered by Severity, from highest to lowest, and then by Location.
+func (l *Linter) Lint(doc *openapi.Document) Issues {
+	if doc == nil {
+		return nil
+	}
+	var issues Issues
+	for _, r := range l.rules {
+		sev := l.Severity(r)
+		if sev == SeverityOff {
+			continue
+		}
+		for _, i := range r.Check(doc) {
+			i.Rule = r.Name()
+			i.Severity = sev
+			issues = append(issues, i)
+		}
+	}
+	sort.SliceStable(issues, func(a, b int) bool {
+		if issues[a].Severity != issues[b].Severity {
+			return issues[a].Severity > issues[b].Severity
+		}
+		return issues[a].Location.String() < issues[b].Location.String()
+	})
+	return issues
+}
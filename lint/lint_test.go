@@ -0,0 +1,156 @@
+package lint_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/chanced/openapi"
+	"github.com/chanced/openapi/lint"
+)
+
+func TestLinter(t *testing.T) {
+	data := []byte(`{
+		"openapi": "3.1.0",
+		"info": { "title": "lint", "version": "1.0.0" },
+		"tags": [{ "name": "pets" }],
+		"paths": {
+			"/pets/{petId}": {
+				"get": {
+					"operationId": "getPet",
+					"description": "Returns a pet",
+					"tags": ["pets"],
+					"responses": {
+						"200": { "description": "ok" },
+						"404": { "description": "not found" }
+					}
+				}
+			},
+			"/petOwners": {
+				"post": {
+					"tags": ["owners"],
+					"x-internal": true,
+					"responses": {
+						"201": { "description": "created" }
+					}
+				}
+			}
+		}
+	}`)
+	var doc openapi.Document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	l, err := lint.NewLinter(lint.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	issues := l.Lint(&doc)
+	expected := map[string]lint.Severity{
+		"operation-operationId":  lint.SeverityError,
+		"paths-kebab-case":       lint.SeverityWarn,
+		"operation-4xx-response": lint.SeverityWarn,
+		"operation-tag-defined":  lint.SeverityWarn,
+	}
+	if len(issues) != len(expected) {
+		t.Fatalf("expected %d issues, got %d: %v", len(expected), len(issues), issues)
+	}
+	for _, i := range issues {
+		if sev, ok := expected[i.Rule]; !ok || sev != i.Severity {
+			t.Errorf("unexpected issue: %v", i)
+		}
+	}
+	if !issues.HasErrors() {
+		t.Error("expected issues to contain errors")
+	}
+
+	l, err = lint.NewLinter(lint.Config{
+		Severities: map[string]lint.Severity{
+			"operation-operationId": lint.SeverityOff,
+			"paths-kebab-case":      lint.SeverityError,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	issues = l.Lint(&doc)
+	if len(issues) != 3 {
+		t.Fatalf("expected 3 issues, got %d: %v", len(issues), issues)
+	}
+	if issues[0].Rule != "paths-kebab-case" || issues[0].Severity != lint.SeverityError {
+		t.Errorf("expected paths-kebab-case to be reported as an error, got %v", issues[0])
+	}
+
+	if _, err = lint.NewLinter(lint.Config{Severities: map[string]lint.Severity{"unknown": lint.SeverityWarn}}); err == nil {
+		t.Error("expected an error for an unknown rule")
+	}
+}
+
+func TestPatternPortable(t *testing.T) {
+	data := []byte(`{
+		"openapi": "3.1.0",
+		"info": { "title": "patterns", "version": "1.0.0" },
+		"components": {
+			"schemas": {
+				"Slug": { "type": "string", "pattern": "^[a-z0-9-]+$" },
+				"Name": { "type": "string", "pattern": "^(?!admin)[a-z]+$" },
+				"Labels": {
+					"type": "object",
+					"patternProperties": { "^\\p{L}+\\z": { "type": "string" } }
+				}
+			}
+		}
+	}`)
+	var doc openapi.Document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	l, err := lint.NewLinter(lint.Config{}, lint.PatternPortable)
+	if err != nil {
+		t.Fatal(err)
+	}
+	issues := l.Lint(&doc)
+	if len(issues) != 2 {
+		t.Fatalf("expected 2 issues, got %d: %v", len(issues), issues)
+	}
+	for _, i := range issues {
+		if i.Rule != "pattern-portable" || i.Severity != lint.SeverityWarn {
+			t.Errorf("unexpected issue: %v", i)
+		}
+	}
+}
+
+func TestSchemaSatisfiable(t *testing.T) {
+	data := []byte(`{
+		"openapi": "3.1.0",
+		"info": { "title": "schemas", "version": "1.0.0" },
+		"components": {
+			"schemas": {
+				"Age": { "type": "integer", "minimum": 18, "maximum": 12 },
+				"Pet": {
+					"type": "object",
+					"required": ["name"],
+					"properties": { "id": { "type": "integer" } },
+					"additionalProperties": false
+				},
+				"Name": { "type": "string", "maxLength": 20 }
+			}
+		}
+	}`)
+	var doc openapi.Document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	l, err := lint.NewLinter(lint.Config{}, lint.SchemaSatisfiable)
+	if err != nil {
+		t.Fatal(err)
+	}
+	issues := l.Lint(&doc)
+	if len(issues) != 2 {
+		t.Fatalf("expected 2 issues, got %d: %v", len(issues), issues)
+	}
+	for _, i := range issues {
+		if i.Rule != "schema-satisfiable" || i.Severity != lint.SeverityError {
+			t.Errorf("unexpected issue: %v", i)
+		}
+	}
+}
@@ -0,0 +1,269 @@
+package lint
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/chanced/openapi"
+	"github.com/chanced/uri"
+)
+
+// Case is a naming convention.
+type Case uint8
+
+const (
+	// CaseDefault indicates that the default Case should be used.
+	CaseDefault Case = iota
+	// CaseCamel is camelCase
+	CaseCamel
+	// CasePascal is PascalCase
+	CasePascal
+	// CaseSnake is snake_case
+	CaseSnake
+	// CaseKebab is kebab-case
+	CaseKebab
+)
+
+func (c Case) String() string {
+	switch c {
+	case CaseCamel:
+		return "camelCase"
+	case CasePascal:
+		return "PascalCase"
+	case CaseSnake:
+		return "snake_case"
+	case CaseKebab:
+		return "kebab-case"
+	default:
+		return "default"
+	}
+}
+
+// Convert returns t converted to the Case c. If c is CaseDefault, t is
+// returned unmodified.
+func (c Case) Convert(t openapi.Text) openapi.Text {
+	switch c {
+	case CaseCamel:
+		return t.ToLowerCamel()
+	case CasePascal:
+		return t.ToCamel()
+	case CaseSnake:
+		return t.ToSnake()
+	case CaseKebab:
+		return t.ToKebab()
+	default:
+		return t
+	}
+}
+
+// Is reports whether t is in the Case c.
+func (c Case) Is(t openapi.Text) bool {
+	return c.Convert(t) == t
+}
+
+// Naming configures the Rules returned from NamingRules.
+type Naming struct {
+	// Properties is the Case of Schema property names.
+	//
+	// Defaults to CaseCamel
+	Properties Case
+	// Components is the Case of the names of components (e.g.
+	// "#/components/schemas/Pet").
+	//
+	// Defaults to CasePascal
+	Components Case
+	// Uncountable is a list of path segments which are not required to be
+	// plural (e.g. "media"). These are in addition to a small set of common
+	// uncountable nouns.
+	Uncountable []openapi.Text
+}
+
+var uncountable = []openapi.Text{
+	"data", "metadata", "media", "information", "info", "news", "equipment",
+	"feedback", "software", "people", "children",
+}
+
+// NamingRules returns Rules which enforce the naming conventions of n:
+//   - "schema-property-case": Schema property names are in n.Properties
+//   - "component-name-case": component names are in n.Components
+//   - "paths-plural-resources": path segments which are followed by a
+//     template (e.g. "/pets/{petId}") are plural
+//
+// The Rules are not included in DefaultRules and must be explicitly passed to
+// NewLinter.
+func NamingRules(n Naming) []Rule {
+	if n.Properties == CaseDefault {
+		n.Properties = CaseCamel
+	}
+	if n.Components == CaseDefault {
+		n.Components = CasePascal
+	}
+	return []Rule{
+		NewRule(
+			"schema-property-case",
+			fmt.Sprintf("Schema properties must be %s.", n.Properties),
+			SeverityWarn,
+			func(doc *openapi.Document) []Issue { return checkPropertyCase(doc, n.Properties) },
+		),
+		NewRule(
+			"component-name-case",
+			fmt.Sprintf("Component names must be %s.", n.Components),
+			SeverityWarn,
+			func(doc *openapi.Document) []Issue { return checkComponentCase(doc, n.Components) },
+		),
+		NewRule(
+			"paths-plural-resources",
+			"Collection resources in paths must be plural.",
+			SeverityHint,
+			func(doc *openapi.Document) []Issue { return checkPluralResources(doc, n.Uncountable) },
+		),
+	}
+}
+
+func checkPropertyCase(doc *openapi.Document, c Case) []Issue {
+	var issues []Issue
+	walk(doc, func(n openapi.Node) {
+		s, ok := n.(*openapi.Schema)
+		if !ok || s.Properties == nil {
+			return
+		}
+		for _, item := range s.Properties.Items {
+			if c.Is(item.Key) {
+				continue
+			}
+			issues = append(issues, Issue{
+				Message:  fmt.Sprintf("property %q is not %s (expected %q)", item.Key, c, c.Convert(item.Key)),
+				Location: s.Properties.Location.AppendLocation(item.Key.String()).AbsoluteLocation(),
+			})
+		}
+	})
+	return issues
+}
+
+// componentName is the name of a component along with its location.
+type componentName struct {
+	Kind     string
+	Name     openapi.Text
+	Location uri.URI
+}
+
+// componentNames returns the names of each component of c.
+func componentNames(c *openapi.Components) []componentName {
+	if c == nil {
+		return nil
+	}
+	var names []componentName
+	add := func(kind string, name openapi.Text, loc openapi.Location) {
+		names = append(names, componentName{Kind: kind, Name: name, Location: loc.AbsoluteLocation()})
+	}
+	if c.Schemas != nil {
+		for _, item := range c.Schemas.Items {
+			add("schema", item.Key, c.Schemas.Location.AppendLocation(item.Key.String()))
+		}
+	}
+	if c.Responses != nil {
+		for _, e := range c.Responses.Items {
+			add("response", e.Key, c.Responses.Location.AppendLocation(e.Key.String()))
+		}
+	}
+	if c.Parameters != nil {
+		for _, e := range c.Parameters.Items {
+			add("parameter", e.Key, c.Parameters.Location.AppendLocation(e.Key.String()))
+		}
+	}
+	if c.Examples != nil {
+		for _, e := range c.Examples.Items {
+			add("example", e.Key, c.Examples.Location.AppendLocation(e.Key.String()))
+		}
+	}
+	if c.RequestBodies != nil {
+		for _, e := range c.RequestBodies.Items {
+			add("request body", e.Key, c.RequestBodies.Location.AppendLocation(e.Key.String()))
+		}
+	}
+	if c.Headers != nil {
+		for _, e := range c.Headers.Items {
+			add("header", e.Key, c.Headers.Location.AppendLocation(e.Key.String()))
+		}
+	}
+	if c.SecuritySchemes != nil {
+		for _, e := range c.SecuritySchemes.Items {
+			add("security scheme", e.Key, c.SecuritySchemes.Location.AppendLocation(e.Key.String()))
+		}
+	}
+	if c.Links != nil {
+		for _, e := range c.Links.Items {
+			add("link", e.Key, c.Links.Location.AppendLocation(e.Key.String()))
+		}
+	}
+	if c.Callbacks != nil {
+		for _, e := range c.Callbacks.Items {
+			add("callback", e.Key, c.Callbacks.Location.AppendLocation(e.Key.String()))
+		}
+	}
+	if c.PathItems != nil {
+		for _, e := range c.PathItems.Items {
+			add("path item", e.Key, c.PathItems.Location.AppendLocation(e.Key.String()))
+		}
+	}
+	return names
+}
+
+func checkComponentCase(doc *openapi.Document, c Case) []Issue {
+	var issues []Issue
+	for _, cn := range componentNames(doc.Components) {
+		if c.Is(cn.Name) {
+			continue
+		}
+		issues = append(issues, Issue{
+			Message:  fmt.Sprintf("%s %q is not %s (expected %q)", cn.Kind, cn.Name, c, c.Convert(cn.Name)),
+			Location: cn.Location,
+		})
+	}
+	return issues
+}
+
+func checkPluralResources(doc *openapi.Document, exceptions []openapi.Text) []Issue {
+	if doc.Paths == nil {
+		return nil
+	}
+	var issues []Issue
+	for _, item := range doc.Paths.Items {
+		segs := strings.Split(item.Key.String(), "/")
+		for i := 0; i < len(segs)-1; i++ {
+			seg := segs[i]
+			if seg == "" || strings.HasPrefix(seg, "{") || !strings.HasPrefix(segs[i+1], "{") {
+				continue
+			}
+			if isPlural(seg, exceptions) {
+				continue
+			}
+			issues = append(issues, Issue{
+				Message:  fmt.Sprintf("resource %q of path %q is not plural", seg, item.Key),
+				Location: item.AbsoluteLocation(),
+			})
+		}
+	}
+	return issues
+}
+
+// isPlural reports whether the last word of seg is plural. This is a
+// heuristic; words ending in "s" are considered plural.
+func isPlural(seg string, exceptions []openapi.Text) bool {
+	word := seg
+	if i := strings.LastIndexAny(word, "-_"); i >= 0 {
+		word = word[i+1:]
+	}
+	word = strings.ToLower(word)
+	for _, u := range uncountable {
+		if u.String() == word {
+			return true
+		}
+	}
+	for _, e := range exceptions {
+		if strings.EqualFold(e.String(), seg) || strings.EqualFold(e.String(), word) {
+			return true
+		}
+	}
+	return strings.HasSuffix(word, "s")
+}
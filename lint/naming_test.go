@@ -0,0 +1,65 @@
+package lint_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/chanced/openapi"
+	"github.com/chanced/openapi/lint"
+)
+
+func TestNamingRules(t *testing.T) {
+	data := []byte(`{
+		"openapi": "3.1.0",
+		"info": { "title": "naming", "version": "1.0.0" },
+		"paths": {
+			"/pet/{petId}": {},
+			"/media/{mediaId}": {},
+			"/owners/{ownerId}/pets": {}
+		},
+		"components": {
+			"schemas": {
+				"Pet": {
+					"type": "object",
+					"properties": {
+						"petName": { "type": "string" },
+						"owner_id": { "type": "string" }
+					}
+				},
+				"pet_owner": { "type": "object" }
+			}
+		}
+	}`)
+	var doc openapi.Document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	l, err := lint.NewLinter(lint.Config{}, lint.NamingRules(lint.Naming{})...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	issues := l.Lint(&doc)
+	expected := map[string]int{
+		"schema-property-case":   1,
+		"component-name-case":    1,
+		"paths-plural-resources": 1,
+	}
+	for _, i := range issues {
+		expected[i.Rule]--
+	}
+	for rule, n := range expected {
+		if n != 0 {
+			t.Errorf("unexpected number of issues for %q: %v", rule, issues)
+		}
+	}
+
+	l, err = lint.NewLinter(lint.Config{}, lint.NamingRules(lint.Naming{Properties: lint.CaseSnake})...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, i := range l.Lint(&doc) {
+		if i.Rule == "schema-property-case" && i.Message != `property "petName" is not snake_case (expected "pet_name")` {
+			t.Errorf("unexpected issue: %v", i)
+		}
+	}
+}
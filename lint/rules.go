@@ -0,0 +1,295 @@
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/chanced/openapi"
+)
+
+var kebabSegmentRegexp = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*(\.[a-z0-9]+)?$`)
+
+var (
+	// OperationIDRequired reports Operations without an operationId.
+	OperationIDRequired = NewRule(
+		"operation-operationId",
+		"Operations must have an operationId.",
+		SeverityError,
+		checkOperationID,
+	)
+
+	// OperationDescriptionRequired reports public Operations without a
+	// description. An Operation is considered internal, and thus exempt, if
+	// it has the extension "x-internal" set to true.
+	OperationDescriptionRequired = NewRule(
+		"operation-description",
+		"Public operations must have a description.",
+		SeverityWarn,
+		checkOperationDescription,
+	)
+
+	// PathsKebabCase reports paths with literal segments which are not
+	// kebab-case (e.g. "/pet-owners/{ownerId}"). Path templates are ignored.
+	PathsKebabCase = NewRule(
+		"paths-kebab-case",
+		"Path segments must be kebab-case.",
+		SeverityWarn,
+		checkPathsKebabCase,
+	)
+
+	// Operation4xxResponse reports Operations which do not define at least
+	// one 4xx response. Webhooks are exempt.
+	Operation4xxResponse = NewRule(
+		"operation-4xx-response",
+		"Operations must define at least one 4xx response.",
+		SeverityWarn,
+		checkOperation4xxResponse,
+	)
+
+	// OperationTagDefined reports tags of Operations which are not declared
+	// in the Document's tags.
+	OperationTagDefined = NewRule(
+		"operation-tag-defined",
+		"Operation tags must be declared in the document's tags.",
+		SeverityWarn,
+		checkOperationTagDefined,
+	)
+
+	// PatternPortable reports patterns of Schemas, including the keys of
+	// patternProperties, which are not interpreted identically as ECMA-262
+	// regular expressions, as JSON Schema specifies, and by Go's RE2-based
+	// regexp package. See openapi.CheckPatternPortability.
+	PatternPortable = NewRule(
+		"pattern-portable",
+		"Patterns must be portable between ECMA-262 and RE2.",
+		SeverityWarn,
+		checkPatternPortable,
+	)
+
+	// SchemaSatisfiable reports keywords of Schemas which contradict one
+	// another, such as a minimum greater than the maximum or a required
+	// property which is prohibited. See openapi.Schema.Contradictions.
+	SchemaSatisfiable = NewRule(
+		"schema-satisfiable",
+		"Schema keywords must not contradict one another.",
+		SeverityError,
+		checkSchemaSatisfiable,
+	)
+)
+
+// DefaultRules returns the built-in Rules:
+//   - OperationIDRequired
+//   - OperationDescriptionRequired
+//   - PathsKebabCase
+//   - Operation4xxResponse
+//   - OperationTagDefined
+//   - PatternPortable
+//   - SchemaSatisfiable
+//   - HeaderNameCase
+//   - HeaderContentType
+func DefaultRules() []Rule {
+	return []Rule{
+		OperationIDRequired,
+		OperationDescriptionRequired,
+		PathsKebabCase,
+		Operation4xxResponse,
+		OperationTagDefined,
+		PatternPortable,
+		SchemaSatisfiable,
+		HeaderNameCase,
+		HeaderContentType,
+	}
+}
+
+// operation is an openapi.OperationEntry which formats as either "METHOD
+// /path" or "METHOD webhook \"name\"".
+type operation struct {
+	openapi.OperationEntry
+}
+
+func (o operation) String() string {
+	if o.Webhook {
+		return fmt.Sprintf("%s webhook %q", o.Method, o.Key)
+	}
+	return fmt.Sprintf("%s %s", o.Method, o.Key)
+}
+
+// operations returns all Operations of doc's Paths and Webhooks in document
+// order.
+func operations(doc *openapi.Document) []operation {
+	entries := doc.Operations()
+	ops := make([]operation, len(entries))
+	for i, e := range entries {
+		ops[i] = operation{e}
+	}
+	return ops
+}
+
+// walk calls fn for each node of doc, depth-first. Resolved references are
+// followed and each node is visited at most once.
+func walk(doc *openapi.Document, fn func(n openapi.Node)) {
+	type noder interface {
+		Nodes() []openapi.Node
+	}
+	seen := map[noder]struct{}{}
+	var visit func(n openapi.Node)
+	visit = func(n openapi.Node) {
+		nn, ok := n.(noder)
+		if ok {
+			if _, ok := seen[nn]; ok {
+				return
+			}
+			seen[nn] = struct{}{}
+		}
+		fn(n)
+		if ok {
+			for _, c := range nn.Nodes() {
+				visit(c)
+			}
+		}
+	}
+	visit(doc)
+}
+
+func checkOperationID(doc *openapi.Document) []Issue {
+	var issues []Issue
+	for _, o := range operations(doc) {
+		if o.Operation.OperationID == "" {
+			issues = append(issues, Issue{
+				Message:  fmt.Sprintf("operation %s is missing an operationId", o),
+				Location: o.Operation.AbsoluteLocation(),
+			})
+		}
+	}
+	return issues
+}
+
+func checkOperationDescription(doc *openapi.Document) []Issue {
+	var issues []Issue
+	for _, o := range operations(doc) {
+		if internal, err := openapi.GetExtension[bool](o.Operation, "x-internal"); err == nil && internal {
+			continue
+		}
+		if strings.TrimSpace(o.Operation.Description.String()) == "" {
+			issues = append(issues, Issue{
+				Message:  fmt.Sprintf("operation %s is missing a description", o),
+				Location: o.Operation.AbsoluteLocation(),
+			})
+		}
+	}
+	return issues
+}
+
+func checkPathsKebabCase(doc *openapi.Document) []Issue {
+	if doc.Paths == nil {
+		return nil
+	}
+	var issues []Issue
+	for _, item := range doc.Paths.Items {
+		for _, seg := range strings.Split(item.Key.String(), "/") {
+			if seg == "" || strings.HasPrefix(seg, "{") {
+				continue
+			}
+			if !kebabSegmentRegexp.MatchString(seg) {
+				issues = append(issues, Issue{
+					Message:  fmt.Sprintf("segment %q of path %q is not kebab-case", seg, item.Key),
+					Location: item.AbsoluteLocation(),
+				})
+				break
+			}
+		}
+	}
+	return issues
+}
+
+func checkOperation4xxResponse(doc *openapi.Document) []Issue {
+	var issues []Issue
+	for _, o := range operations(doc) {
+		if !o.Webhook && !has4xxResponse(o.Operation) {
+			issues = append(issues, Issue{
+				Message:  fmt.Sprintf("operation %s does not define a 4xx response", o),
+				Location: o.Operation.AbsoluteLocation(),
+			})
+		}
+	}
+	return issues
+}
+
+func has4xxResponse(op *openapi.Operation) bool {
+	for _, c := range openapi.ResponseClasses(op.Responses) {
+		if c == openapi.ResponseClassClientError {
+			return true
+		}
+	}
+	return false
+}
+
+func checkOperationTagDefined(doc *openapi.Document) []Issue {
+	declared := map[openapi.Text]struct{}{}
+	if doc.Tags != nil {
+		for _, t := range doc.Tags.Items {
+			declared[t.Name] = struct{}{}
+		}
+	}
+	var issues []Issue
+	for _, o := range operations(doc) {
+		for i, tag := range o.Operation.Tags {
+			if _, ok := declared[tag]; ok {
+				continue
+			}
+			issues = append(issues, Issue{
+				Message:  fmt.Sprintf("tag %q of operation %s is not declared", tag, o),
+				Location: o.Operation.Location.AppendLocation("tags").AppendLocation(strconv.Itoa(i)).AbsoluteLocation(),
+			})
+		}
+	}
+	return issues
+}
+
+func checkPatternPortable(doc *openapi.Document) []Issue {
+	var issues []Issue
+	walk(doc, func(n openapi.Node) {
+		s, ok := n.(*openapi.Schema)
+		if !ok {
+			return
+		}
+		if !s.Pattern.IsNil() {
+			if err := openapi.CheckPatternPortability(s.Pattern.Source()); err != nil {
+				issues = append(issues, Issue{
+					Message:  err.Error(),
+					Location: s.Location.AppendLocation("pattern").AbsoluteLocation(),
+				})
+			}
+		}
+		if s.PatternProperties != nil {
+			for _, item := range s.PatternProperties.Items {
+				if err := openapi.CheckPatternPortability(item.Key.String()); err != nil {
+					issues = append(issues, Issue{
+						Message:  err.Error(),
+						Location: s.Location.AppendLocation("patternProperties").AppendLocation(item.Key.String()).AbsoluteLocation(),
+					})
+				}
+			}
+		}
+	})
+	return issues
+}
+
+func checkSchemaSatisfiable(doc *openapi.Document) []Issue {
+	var issues []Issue
+	walk(doc, func(n openapi.Node) {
+		s, ok := n.(*openapi.Schema)
+		if !ok {
+			return
+		}
+		for _, c := range s.Contradictions() {
+			issues = append(issues, Issue{
+				Message:  fmt.Sprintf("%s can not be satisfied: %s", c.Keyword, c.Reason),
+				Location: c.Location.AppendLocation(c.Keyword.String()).AbsoluteLocation(),
+			})
+		}
+	})
+	return issues
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/Masterminds/semver"
 	"github.com/chanced/transcode"
@@ -41,6 +42,97 @@ func TryGetOpenAPIVersion(data []byte) (string, bool) {
 
 type LoadOpts struct {
 	DefaultSchemaDialect *uri.URI
+	// Keywords, if set, is used to decode and validate custom keywords of
+	// each Schema as it is loaded.
+	Keywords *KeywordRegistry
+	// Dialects, if set, restricts the JSON Schema dialects which may be
+	// referenced by $schema or jsonSchemaDialect to those registered.
+	Dialects *DialectRegistry
+	// ExternalValues, if true, fetches the data of each Example's
+	// externalValue with the loader's fn. See Example.LoadExternalValue.
+	ExternalValues bool
+	// Timeout, if greater than 0, limits the total duration of Load,
+	// including fetching resources, resolving references, and validation.
+	// If the Timeout elapses, Load returns an error which wraps
+	// context.DeadlineExceeded.
+	Timeout time.Duration
+
+	// The following limits guard against excessive resource consumption when
+	// loading untrusted documents. A limit of 0 is unlimited. If a limit is
+	// exceeded, Load returns an error wrapping ErrLimitExceeded.
+
+	// MaxResources is the maximum number of resources, other than the
+	// Document itself, which may be fetched with fn. This includes the data
+	// of Examples' externalValue.
+	MaxResources int
+	// MaxBytes is the maximum total size, in bytes, of the data returned by
+	// fn, including that of the Document itself.
+	MaxBytes int64
+	// MaxRefDepth is the maximum number of references which may be followed
+	// in succession (e.g. a $ref to a Schema which contains a $ref to a
+	// Schema, and so on).
+	MaxRefDepth int
+	// MaxAnchors is the maximum number of anchors ($anchor, $dynamicAnchor,
+	// and $recursiveAnchor) across all loaded resources.
+	MaxAnchors int
+
+	// AllowRef, if set, is called with the Location of each $ref,
+	// $dynamicRef, $recursiveRef, or operationRef to another resource and
+	// the absolute URI of its target. If AllowRef returns an error, loading
+	// stops and the error is returned, wrapped with ErrRefNotAllowed.
+	//
+	// See SameOriginOnly and FileOnly.
+	AllowRef func(from Location, target uri.URI) error
+
+	// Hooks, if set, are called as Load progresses. See LoadHooks.
+	Hooks *LoadHooks
+
+	// Tolerant, if true, loads Documents whose version does not satisfy
+	// SupportedVersions (e.g. "3.2.0") rather than failing, provided the
+	// version is registered with Versions. Such Documents are validated and
+	// interpreted as the version's VersionCapabilities.ValidateAs; validation
+	// errors are reported to Hooks.OnWarning rather than returned. Fields
+	// which are not modeled are preserved in the Extensions of their object.
+	Tolerant bool
+	// Versions is consulted when Tolerant is true. Defaults to
+	// NewVersionRegistry().
+	Versions *VersionRegistry
+
+	// Patterns determines how the pattern of each Schema is compiled. If a
+	// pattern can not be compiled, Load returns an error wrapping
+	// ErrInvalidPattern.
+	//
+	// Defaults to PatternRE2
+	Patterns PatternMode
+
+	// Profile, if set, is assigned a report of the time Load spends in each
+	// of its phases, whether or not Load succeeds.
+	Profile *LoadProfile
+
+	// Cache, if set, is consulted before fn is called to fetch an external
+	// resource and is populated with the data fn returns. The Document
+	// itself is always fetched with fn. Cached resources are subject to the
+	// same limits as those fetched with fn.
+	//
+	// Resources which can not be read from or written to Cache, or whose
+	// hash does not match their data, are reported to Hooks.OnWarning and
+	// fetched with fn.
+	Cache ResourceCache
+
+	// Refreshable, if true, retains the data and versions of the resources
+	// of the Document, along with fn, validator, and these options, so that
+	// external resources can later be re-checked with Document.Refresh.
+	Refreshable bool
+
+	// Lockfile, if set, is checked against the data of each external
+	// resource, whether fetched with fn or read from Cache, according to
+	// LockMode. If a resource is missing from Lockfile or does not match
+	// it, Load returns an error wrapping ErrLockMismatch.
+	//
+	// To generate a Lockfile, Load with an empty Lockfile and LockUpdate.
+	Lockfile *Lockfile
+	// LockMode determines how Lockfile is treated. Defaults to LockVerify.
+	LockMode LockMode
 }
 
 func mergeLoadOpts(opts []LoadOpts) LoadOpts {
@@ -49,6 +141,60 @@ func mergeLoadOpts(opts []LoadOpts) LoadOpts {
 		if o.DefaultSchemaDialect != nil {
 			l.DefaultSchemaDialect = o.DefaultSchemaDialect
 		}
+		if o.Keywords != nil {
+			l.Keywords = o.Keywords
+		}
+		if o.Dialects != nil {
+			l.Dialects = o.Dialects
+		}
+		if o.ExternalValues {
+			l.ExternalValues = true
+		}
+		if o.Timeout > 0 {
+			l.Timeout = o.Timeout
+		}
+		if o.MaxResources > 0 {
+			l.MaxResources = o.MaxResources
+		}
+		if o.MaxBytes > 0 {
+			l.MaxBytes = o.MaxBytes
+		}
+		if o.MaxRefDepth > 0 {
+			l.MaxRefDepth = o.MaxRefDepth
+		}
+		if o.MaxAnchors > 0 {
+			l.MaxAnchors = o.MaxAnchors
+		}
+		if o.AllowRef != nil {
+			l.AllowRef = o.AllowRef
+		}
+		if o.Hooks != nil {
+			l.Hooks = o.Hooks
+		}
+		if o.Tolerant {
+			l.Tolerant = true
+		}
+		if o.Versions != nil {
+			l.Versions = o.Versions
+		}
+		if o.Patterns != PatternRE2 {
+			l.Patterns = o.Patterns
+		}
+		if o.Profile != nil {
+			l.Profile = o.Profile
+		}
+		if o.Cache != nil {
+			l.Cache = o.Cache
+		}
+		if o.Refreshable {
+			l.Refreshable = true
+		}
+		if o.Lockfile != nil {
+			l.Lockfile = o.Lockfile
+		}
+		if o.LockMode != LockVerify {
+			l.LockMode = o.LockMode
+		}
 	}
 	return l
 }
@@ -93,6 +239,9 @@ func mergeLoadOpts(opts []LoadOpts) LoadOpts {
 // resources. For example, if we have a reference to "example.json#/foo/bar"
 // which has an anchor "#baz", that is located at the root of "example.json", it
 // would not be found if example.json were not parsed entirely.
+//
+// Load stops and returns an error wrapping ctx.Err() once ctx is done, even
+// if fn does not respect ctx. See LoadOpts.Timeout.
 func Load(ctx context.Context, documentURI string, validator Validator, fn func(ctx context.Context, uri uri.URI, kind Kind) (Kind, []byte, error), opts ...LoadOpts) (*Document, error) {
 	if fn == nil {
 		panic("fn cannot be nil")
@@ -108,12 +257,36 @@ func Load(ctx context.Context, documentURI string, validator Validator, fn func(
 	if docURI.Fragment != "" {
 		return nil, NewError(fmt.Errorf("documentURI may not contain a fragment: received \"%s\"", docURI), *docURI)
 	}
-	l := newLoader(validator, fn, mergeLoadOpts(opts))
+	lo := mergeLoadOpts(opts)
+	if lo.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, lo.Timeout)
+		defer cancel()
+	}
+	l := newLoader(validator, fn, lo)
+	if p := lo.Profile; p != nil {
+		*p = LoadProfile{}
+		defer func(start time.Time) {
+			p.Total = time.Since(start)
+			p.Resources = l.fetched
+			p.Bytes = l.bytes
+		}(time.Now())
+	}
 	n, err := l.load(ctx, *docURI, KindDocument, nil, nil)
 	if err != nil {
-		return nil, err
+		return nil, withLocation(err, *docURI)
+	}
+	doc := n.(*Document)
+	if lo.Refreshable {
+		doc.source = &loadSource{
+			uri:       *docURI,
+			validator: validator,
+			fn:        fn,
+			opts:      lo,
+			resources: l.resources,
+		}
 	}
-	return n.(*Document), nil
+	return doc, nil
 }
 
 func newLoader(v Validator, fn func(context.Context, uri.URI, Kind) (Kind, []byte, error), opts LoadOpts) *loader {
@@ -135,12 +308,26 @@ type loader struct {
 	dynamicRefs []refctx
 	refs        []refctx
 	dialect     *uri.URI
+	// fetched is the number of calls to fn
+	fetched int
+	// bytes is the total length of the data returned by fn
+	bytes int64
+	// anchors is the number of anchors of loaded resources
+	anchors int
+	// depth is the depth of the nodes of resources loaded while resolving
+	// the current reference
+	depth int
+	// resources are the resources fetched, in order, if opts.Refreshable
+	resources []*loadedResource
 }
 
 func (l *loader) load(ctx context.Context, location uri.URI, ek Kind, openapi *semver.Version, dialect *uri.URI) (Node, error) {
 	if n, ok := l.nodes[location.String()]; ok {
 		return n.node, nil
 	}
+	if err := checkContext(ctx, location); err != nil {
+		return nil, err
+	}
 	k, data, err := l.loadData(ctx, location, ek)
 	if err != nil {
 		return nil, err
@@ -156,19 +343,110 @@ func (l *loader) load(ctx context.Context, location uri.URI, ek Kind, openapi *s
 		return l.loadSchema(ctx, data, location, *openapi)
 	case KindCallbacks, KindExample, KindHeader, KindPathItem, KindOperation,
 		KindRequestBody, KindResponse, KindLink, KindSecurityScheme:
-		return l.loadNode(ctx, k, data, *openapi, *dialect)
+		return l.loadNode(ctx, k, data, location)
 	default:
-		return nil, NewError(fmt.Errorf("loading %s as an external resource is not currently supported", k), location)
+		return nil, NewError(&UnsupportedKindError{Kind: k, URI: location}, location)
+	}
+}
+
+// checkContext returns an error wrapping ctx.Err() if ctx is done
+func checkContext(ctx context.Context, u uri.URI) error {
+	if err := ctx.Err(); err != nil {
+		return NewError(fmt.Errorf("openapi: loading aborted: %w", err), u)
+	}
+	return nil
+}
+
+// fetch calls l.fn, or reads from l.opts.Cache, enforcing the MaxResources
+// and MaxBytes limits.
+func (l *loader) fetch(ctx context.Context, u uri.URI, ek Kind) (Kind, []byte, error) {
+	k, d, _, err := l.fetchResource(ctx, u, ek)
+	return k, d, err
+}
+
+// fetchResource is fetch, additionally reporting whether the data was read
+// from the cache.
+func (l *loader) fetchResource(ctx context.Context, u uri.URI, ek Kind) (k Kind, d []byte, cached bool, err error) {
+	// the first fetch is of the Document itself
+	if l.opts.MaxResources > 0 && l.fetched > l.opts.MaxResources {
+		return 0, nil, false, NewError(fmt.Errorf("%w: more than %d external resources", ErrLimitExceeded, l.opts.MaxResources), u)
+	}
+	external := l.fetched > 0
+	cacheable := l.opts.Cache != nil && external
+	l.fetched++
+	start := time.Now()
+	if cacheable {
+		if res, ok := l.cached(ctx, u); ok {
+			k, d, cached = res.Kind, res.Data, true
+		}
+	}
+	var fs fetchState
+	if !cached {
+		k, d, err = l.fetchWithContext(withFetchState(ctx, &fs), u, ek)
+		if err == nil && cacheable {
+			l.cache(ctx, u, k, d)
+		}
+	}
+	if err == nil && external && l.opts.Lockfile != nil {
+		err = l.opts.Lockfile.check(u, d, l.opts.LockMode)
+	}
+	if err == nil && l.opts.Refreshable {
+		l.resources = append(l.resources, &loadedResource{uri: u, kind: k, data: d, version: fs.version})
+	}
+	l.opts.Profile.add(loadPhaseFetch, start)
+	if err != nil {
+		return k, d, false, err
+	}
+	l.bytes += int64(len(d))
+	if l.opts.MaxBytes > 0 && l.bytes > l.opts.MaxBytes {
+		return 0, nil, cached, NewError(fmt.Errorf("%w: more than %d bytes", ErrLimitExceeded, l.opts.MaxBytes), u)
+	}
+	return k, d, cached, nil
+}
+
+// fetchWithContext calls l.fn, returning early if ctx is done before fn
+// returns.
+func (l *loader) fetchWithContext(ctx context.Context, u uri.URI, ek Kind) (Kind, []byte, error) {
+	if ctx.Done() == nil {
+		return l.fn(ctx, u, ek)
+	}
+	type result struct {
+		kind Kind
+		data []byte
+		err  error
+	}
+	// buffered so that the goroutine is not blocked if ctx is done first
+	ch := make(chan result, 1)
+	go func() {
+		k, d, err := l.fn(ctx, u, ek)
+		ch <- result{kind: k, data: d, err: err}
+	}()
+	select {
+	case <-ctx.Done():
+		return 0, nil, checkContext(ctx, u)
+	case res := <-ch:
+		return res.kind, res.data, res.err
 	}
 }
 
 func (l *loader) loadData(ctx context.Context, u uri.URI, ek Kind) (Kind, []byte, error) {
-	k, d, err := l.fn(ctx, u, ek)
+	start := time.Now()
+	k, d, cached, err := l.fetchResource(ctx, u, ek)
+	l.opts.Hooks.resourceLoaded(ResourceLoadedEvent{
+		URI:      u,
+		Kind:     k,
+		Size:     len(d),
+		Duration: time.Since(start),
+		Cached:   cached,
+		Err:      err,
+	})
 	if err != nil {
 		return k, d, err
 	}
 
+	start = time.Now()
 	d, err = transcode.JSONFromYAML(d)
+	l.opts.Profile.add(loadPhaseUnmarshal, start)
 	if err != nil {
 		return 0, nil, fmt.Errorf("failed to transcode data: %w", err)
 	}
@@ -196,6 +474,14 @@ func (l *loader) loadDocument(ctx context.Context, data []byte, u uri.URI) (*Doc
 	if v == nil {
 		return nil, NewError(fmt.Errorf("failed to determine OpenAPI version; ensure that the OpenAPI document has an openapi field"), u)
 	}
+	caps, tolerated, err := l.versionCapabilities(v, u)
+	if err != nil {
+		return nil, err
+	}
+	if tolerated {
+		// the document is interpreted as the version it is validated as
+		v = &caps.ValidateAs
+	}
 
 	sd, err := l.getJSONSchemaDialect(data, v)
 	if err != nil {
@@ -207,8 +493,11 @@ func (l *loader) loadDocument(ctx context.Context, data []byte, u uri.URI) (*Doc
 		return nil, NewError(fmt.Errorf("failed to determine OpenAPI schema dialect"), u)
 	}
 
-	if err = l.validator.Validate(data, u, KindDocument, *v, *sd); err != nil {
-		return nil, NewValidationError(err, KindDocument, u)
+	if err = checkContext(ctx, u); err != nil {
+		return nil, err
+	}
+	if err = l.validateData(data, u, *v, *sd, tolerated); err != nil {
+		return nil, err
 	}
 
 	var doc Document
@@ -216,35 +505,62 @@ func (l *loader) loadDocument(ctx context.Context, data []byte, u uri.URI) (*Doc
 	if err != nil {
 		return nil, NewError(err, u)
 	}
-	if err := doc.UnmarshalJSON(data); err != nil {
+	start := time.Now()
+	err = doc.UnmarshalJSON(data)
+	l.opts.Profile.add(loadPhaseUnmarshal, start)
+	if err != nil {
 		return nil, NewError(fmt.Errorf("failed to unmarshal OpenAPI Document: %w", err), u)
 	}
-	if err = doc.setLocation(loc); err != nil {
+	start = time.Now()
+	err = doc.setLocation(loc)
+	l.opts.Profile.add(loadPhaseSetLocation, start)
+	if err != nil {
 		return nil, NewError(err, u)
 	}
+	if tolerated {
+		if err = l.preserveUnknownFields(&doc, data, caps); err != nil {
+			return nil, NewError(err, u)
+		}
+	}
 
+	return l.resolveDocument(ctx, &doc, u, *v, *sd, tolerated)
+}
+
+// resolveDocument resolves the references of doc, which is located at u,
+// and, if doc is the primary Document, those of the resources it references
+// before validating it.
+func (l *loader) resolveDocument(ctx context.Context, doc *Document, u uri.URI, v semver.Version, sd uri.URI, tolerated bool) (*Document, error) {
 	dc := nodectx{
-		node:       &doc,
-		openapi:    *doc.OpenAPI,
-		jsonschema: *sd,
+		node:       doc,
+		openapi:    v,
+		jsonschema: sd,
+		depth:      l.depth,
 	}
 	dc.root = &dc
+	start := time.Now()
 	anchors, err := doc.Anchors()
+	l.opts.Profile.add(loadPhaseAnchors, start)
 	if err != nil {
 		return nil, NewError(fmt.Errorf("failed to get anchors: %w", err), u)
 	}
 	dc.anchors = anchors
+	if err = l.countAnchors(anchors, u); err != nil {
+		return nil, err
+	}
 
 	l.nodes[u.String()] = dc
-	if err = l.traverse(&dc, &dc, doc.nodes(), *v, *sd); err != nil {
+	start = time.Now()
+	err = l.traverse(&dc, &dc, doc.nodes(), v, sd)
+	l.opts.Profile.add(loadPhaseResolve, start)
+	if err != nil {
 		return nil, err
 	}
 	// we only traverse the references after the top-level document is fully
 	// materialized.
 	if l.doc == nil {
-		l.doc = &doc
+		l.doc = doc
 	} else {
-		return &doc, nil
+		return doc, nil
 	}
 
 	var r refctx
@@ -253,28 +569,138 @@ func (l *loader) loadDocument(ctx context.Context, data []byte, u uri.URI) (*Doc
 		for len(l.refs) > 0 {
 			// r, l.refs = l.refs[len(l.refs)-1], l.refs[:len(l.refs)-1]
 			r, l.refs = l.refs[0], l.refs[1:]
+			if err = checkContext(ctx, u); err != nil {
+				return nil, err
+			}
+			start := time.Now()
+			elapsed := l.opts.Profile.elapsed()
+			l.depth = r.depth + 1
 			n, err := l.resolveRef(ctx, r)
+			l.opts.Profile.resolved(start, elapsed)
+			if l.opts.Hooks != nil {
+				e := RefResolvedEvent{Ref: r.ref, Duration: time.Since(start), Err: err}
+				if err == nil && n != nil && n.node != nil {
+					e.Target = n.AbsoluteLocation()
+				}
+				l.opts.Hooks.refResolved(e)
+			}
 			if err != nil {
-				return nil, err
+				return nil, withLocation(err, r.AbsoluteLocation())
 			}
 			if n != nil {
+				n.depth = r.depth + 1
+				if l.opts.MaxRefDepth > 0 && n.depth > l.opts.MaxRefDepth {
+					return nil, NewError(fmt.Errorf("%w: references nested more than %d deep", ErrLimitExceeded, l.opts.MaxRefDepth), r.AbsoluteLocation())
+				}
 				nodes = append(nodes, *n)
 				r.resolved = n
 			}
 
 			r.root.resolvedRefs = append(r.root.resolvedRefs, r)
 		}
-		for _, n := range nodes {
-			if err = l.traverse(&dc, n.root, n.nodes(), n.openapi, n.jsonschema); err != nil {
+		start := time.Now()
+		for i := range nodes {
+			n := &nodes[i]
+			if err = l.traverse(n, n.root, n.nodes(), n.openapi, n.jsonschema); err != nil {
 				return nil, err
 			}
 		}
+		l.opts.Profile.add(loadPhaseResolve, start)
 		nodes = nil
 	}
-	if err = l.validator.ValidateDocument(&doc); err != nil {
+	if l.opts.ExternalValues {
+		if err = l.loadExternalValues(ctx, doc); err != nil {
+			return nil, err
+		}
+	}
+	if err = checkContext(ctx, u); err != nil {
+		return nil, err
+	}
+	if err = l.validateDocument(doc, u, v, tolerated); err != nil {
+		return nil, err
+	}
+	if err = checkContext(ctx, u); err != nil {
 		return nil, err
 	}
-	return &doc, nil
+	return doc, nil
+}
+
+// validateData validates the raw data of the Document located at u. If the
+// Document's version is tolerated, errors are reported as warnings. Data is
+// not validated if the loader does not have a Validator.
+func (l *loader) validateData(data []byte, u uri.URI, v semver.Version, sd uri.URI, tolerated bool) error {
+	if l.validator == nil {
+		return nil
+	}
+	start := time.Now()
+	err := l.validator.Validate(data, u, KindDocument, v, sd)
+	l.opts.Profile.add(loadPhaseValidation, start)
+	l.opts.Hooks.validation(ValidationEvent{
+		URI:      u,
+		Kind:     KindDocument,
+		Duration: time.Since(start),
+		Err:      err,
+	})
+	if err == nil {
+		return nil
+	}
+	if !tolerated {
+		return NewValidationError(err, KindDocument, u)
+	}
+	l.opts.Hooks.warning(WarningEvent{
+		Location: u,
+		Message:  fmt.Sprintf("document is not valid OpenAPI %s", &v),
+		Err:      NewValidationError(err, KindDocument, u),
+	})
+	return nil
+}
+
+// validateDocument validates the resolved primary Document. If the
+// Document's version is tolerated, errors are reported as warnings. The
+// Document is not validated if the loader does not have a Validator.
+func (l *loader) validateDocument(doc *Document, u uri.URI, v semver.Version, tolerated bool) error {
+	if l.validator == nil {
+		return nil
+	}
+	start := time.Now()
+	var err error
+	if tolerated {
+		err = l.validateAs(doc, v)
+	} else {
+		err = l.validator.ValidateDocument(doc)
+	}
+	l.opts.Profile.add(loadPhaseValidation, start)
+	l.opts.Hooks.validation(ValidationEvent{
+		URI:      u,
+		Kind:     KindDocument,
+		Document: true,
+		Duration: time.Since(start),
+		Err:      err,
+	})
+	if err == nil {
+		return nil
+	}
+	if !tolerated {
+		return withLocation(err, u)
+	}
+	l.opts.Hooks.warning(WarningEvent{
+		Location: u,
+		Message:  fmt.Sprintf("document is not valid OpenAPI %s", &v),
+		Err:      withLocation(err, u),
+	})
+	return nil
+}
+
+func (l *loader) loadExternalValues(ctx context.Context, doc *Document) error {
+	return walkNodes(doc, func(n node) error {
+		if e, ok := n.(*Example); ok && len(e.Value) == 0 {
+			if err := checkContext(ctx, e.AbsoluteLocation()); err != nil {
+				return err
+			}
+			return e.LoadExternalValue(ctx, l.fetch)
+		}
+		return nil
+	})
 }
 
 func (l *loader) resolveRef(ctx context.Context, r refctx) (*nodectx, error) {
@@ -293,6 +719,9 @@ func (l *loader) resolveRef(ctx context.Context, r refctx) (*nodectx, error) {
 
 func (l *loader) resolveRemoteRef(ctx context.Context, r refctx) (*nodectx, error) {
 	u := r.URI()
+	if err := l.allowRef(r); err != nil {
+		return nil, err
+	}
 	// let's see if we've already loaded this node
 	rooturi := *u
 	au := r.AbsoluteLocation()
@@ -319,15 +748,19 @@ func (l *loader) resolveRemoteRef(ctx context.Context, r refctx) (*nodectx, erro
 			return &n, r.resolve(n.node)
 		} else if u.Fragment == "" || strings.HasPrefix(u.Fragment, "/") {
 			// something went sideways
-			return nil, NewError(fmt.Errorf("openapi: ref URI not found: %s", u), r.AbsoluteLocation())
+			return nil, newRefNotFoundError(r)
 		}
 	} else {
 		rus := rooturi.String()
 
 		// if this is ref points to the root of a file, we need to load it
-		if u.String() == rus {
+		target := *u
+		if u.Host == "" {
+			target = *au.ResolveReference(u)
+		}
+		if target.String() == rus {
 			// the ref is the root so we need to load it
-			if _, err := l.load(ctx, *u, r.RefKind(), nil, nil); err != nil {
+			if _, err := l.load(ctx, rooturi, r.RefKind(), nil, nil); err != nil {
 				return nil, err
 			}
 		} else {
@@ -354,7 +787,7 @@ func (l *loader) resolveRemoteRef(ctx context.Context, r refctx) (*nodectx, erro
 		_, ok := l.nodes[rooturi.String()]
 		if !ok {
 			// otherwise we return an error
-			return nil, NewError(fmt.Errorf("openapi: ref URI not found: %s", u), r.AbsoluteLocation())
+			return nil, newRefNotFoundError(r)
 		}
 	}
 
@@ -378,7 +811,7 @@ func (l *loader) resolveRemoteRef(ctx context.Context, r refctx) (*nodectx, erro
 		return &n, r.resolve(n.node)
 	}
 	if u.Fragment == "" {
-		return nil, NewError(fmt.Errorf("openapi: ref URI not found: %s", u), r.AbsoluteLocation())
+		return nil, newRefNotFoundError(r)
 	}
 
 	// otherwise we may be dealing with an anchor
@@ -386,7 +819,7 @@ func (l *loader) resolveRemoteRef(ctx context.Context, r refctx) (*nodectx, erro
 
 	rn, ok := l.nodes[rooturi.String()]
 	if !ok {
-		return nil, NewError(fmt.Errorf("openapi: ref URI not found: %s", u), r.AbsoluteLocation())
+		return nil, newRefNotFoundError(r)
 	}
 
 	if a == "" {
@@ -399,7 +832,7 @@ func (l *loader) resolveRemoteRef(ctx context.Context, r refctx) (*nodectx, erro
 
 	if a.HasPrefix("/") {
 		// we aren't dealing with an anchor
-		return nil, NewError(fmt.Errorf("openapi: ref URI not found: %s", u), r.AbsoluteLocation())
+		return nil, newRefNotFoundError(r)
 	}
 
 	as, err := rn.Anchors()
@@ -409,12 +842,12 @@ func (l *loader) resolveRemoteRef(ctx context.Context, r refctx) (*nodectx, erro
 
 	an := as.StandardAnchor(a)
 	if an == nil {
-		return nil, NewError(fmt.Errorf("openapi: ref URI not found: %s", u), r.AbsoluteLocation())
+		return nil, newRefNotFoundError(r)
 	}
 
 	x, ok := l.nodes[an.AbsoluteLocation().String()]
 	if !ok {
-		return nil, NewError(fmt.Errorf("openapi: ref URI not found: %s", u), r.AbsoluteLocation())
+		return nil, newRefNotFoundError(r)
 	}
 	if err := r.resolve(x.node); err != nil {
 		return nil, err
@@ -422,6 +855,31 @@ func (l *loader) resolveRemoteRef(ctx context.Context, r refctx) (*nodectx, erro
 	return &x, nil
 }
 
+// allowRef checks the remote reference r against the AllowRef policy
+func (l *loader) allowRef(r refctx) error {
+	if l.opts.AllowRef == nil {
+		return nil
+	}
+	from := r.AbsoluteLocation()
+	target := *r.URI()
+	if target.Host == "" {
+		target = *from.ResolveReference(r.URI())
+	}
+	var loc Location
+	if n, ok := r.ref.(node); ok {
+		loc = n.location()
+	} else {
+		var err error
+		if loc, err = NewLocation(from); err != nil {
+			return NewError(err, from)
+		}
+	}
+	if err := l.opts.AllowRef(loc, target); err != nil {
+		return NewError(fmt.Errorf("%w: %s: %v", ErrRefNotAllowed, target.String(), err), from)
+	}
+	return nil
+}
+
 func (l *loader) resolveLocalRef(ctx context.Context, r refctx) (*nodectx, error) {
 	u := r.AbsoluteLocation()
 	u.Fragment = r.URI().Fragment
@@ -444,7 +902,7 @@ func (l *loader) resolveLocalRef(ctx context.Context, r refctx) (*nodectx, error
 		return &n, nil
 	} else if strings.HasPrefix(u.Fragment, "/") || r.ref.RefKind() != KindSchema {
 		// otherwise something went awry
-		return nil, NewError(fmt.Errorf("openapi: ref URI not found: %s", u), r.AbsoluteLocation())
+		return nil, newRefNotFoundError(r)
 	}
 
 	// we are dealing with an anchor
@@ -456,7 +914,7 @@ func (l *loader) resolveLocalRef(ctx context.Context, r refctx) (*nodectx, error
 			a = r.root.anchors.StandardAnchor(Text(r.URI().Fragment))
 		}
 		if a == nil {
-			return nil, NewError(fmt.Errorf("openapi: ref URI not found: %s", u), r.AbsoluteLocation())
+			return nil, newRefNotFoundError(r)
 		}
 		err := r.resolve(a.In)
 		if err != nil {
@@ -503,35 +961,52 @@ func (l *loader) resolveLocalRef(ctx context.Context, r refctx) (*nodectx, error
 	}
 }
 
-func (l *loader) getDocumentSchemaDialect(doc *Document) (*uri.URI, error) {
-	if doc.JSONSchemaDialect != nil {
-		return doc.JSONSchemaDialect, nil
-	}
-	if l.opts.DefaultSchemaDialect != nil {
-		return l.opts.DefaultSchemaDialect, nil
+// getDocumentSchemaDialect returns the JSON Schema dialect of doc, which is
+// interpreted as OpenAPI version v.
+func (l *loader) getDocumentSchemaDialect(doc *Document, v *semver.Version) (*uri.URI, error) {
+	var sd *uri.URI
+	switch {
+	case doc.JSONSchemaDialect != nil:
+		sd = doc.JSONSchemaDialect
+	case l.opts.DefaultSchemaDialect != nil:
+		sd = l.opts.DefaultSchemaDialect
+	case checkVersion(VersionConstraints3_1, v):
+		sd = &JSONSchemaDialect202012
+	default:
+		return nil, fmt.Errorf("failed to determine OpenAPI schema dialect")
 	}
-	if VersionConstraints3_1.Check(doc.OpenAPI) {
-		return &JSONSchemaDialect202012, nil
+	if err := l.checkDialect(*sd); err != nil {
+		return nil, err
 	}
-	// if VersionConstraints3_0.Check(doc.OpenAPI) {
-	// 	return &JSONSchemaDialect201909, nil
-	// }
-	return nil, fmt.Errorf("failed to determine OpenAPI schema dialect")
+	return sd, nil
 }
 
 func (l *loader) traverse(node *nodectx, root *nodectx, nodes []node, openapi semver.Version, jsonschema uri.URI) error {
 	for _, n := range nodes {
 		nc, err := newNodeCtx(n, root, &openapi, &jsonschema)
 		if err != nil {
-			return err
+			return NewError(err, n.AbsoluteLocation())
 		}
+		nc.depth = node.depth
 
 		l.nodes[n.AbsoluteLocation().String()] = nc
 
+		if s, ok := n.(*Schema); ok {
+			if err = l.checkDialect(nc.jsonschema); err != nil {
+				return NewError(err, s.AbsoluteLocation())
+			}
+			if err = l.opts.Keywords.ValidateSchema(s, nc.jsonschema); err != nil {
+				return NewValidationError(err, KindSchema, s.AbsoluteLocation())
+			}
+			if err = l.compilePattern(s); err != nil {
+				return err
+			}
+		}
+
 		if IsRef(n) {
 			r := n.(ref)
 			if !r.IsResolved() {
-				l.refs = append(l.refs, refctx{root: root, in: node, ref: r, openapi: nc.openapi, jsonschema: nc.jsonschema})
+				l.refs = append(l.refs, refctx{root: root, in: node, ref: r, openapi: nc.openapi, jsonschema: nc.jsonschema, depth: node.depth})
 			}
 			continue
 		}
@@ -544,22 +1019,32 @@ func (l *loader) traverse(node *nodectx, root *nodectx, nodes []node, openapi se
 
 func (l *loader) loadSchema(ctx context.Context, data []byte, u uri.URI, v semver.Version) (*Schema, error) {
 	var s Schema
-	if err := s.UnmarshalJSON(data); err != nil {
+	start := time.Now()
+	err := s.UnmarshalJSON(data)
+	l.opts.Profile.add(loadPhaseUnmarshal, start)
+	if err != nil {
 		return nil, NewError(fmt.Errorf("failed to unmarshal JSON Schema: %w", err), u)
 	}
 	loc, err := NewLocation(u)
 	if err != nil {
-		return nil, err
+		return nil, NewError(err, u)
 	}
+	start = time.Now()
 	s.setLocation(loc)
-	nc := nodectx{node: &s, openapi: v, jsonschema: u}
+	l.opts.Profile.add(loadPhaseSetLocation, start)
+	nc := nodectx{node: &s, openapi: v, jsonschema: u, depth: l.depth}
 	nc.root = &nc
 
+	start = time.Now()
 	a, err := s.Anchors()
+	l.opts.Profile.add(loadPhaseAnchors, start)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load anchors: %w", err)
+		return nil, NewError(fmt.Errorf("failed to load anchors: %w", err), u)
 	}
 	nc.anchors = a
+	if err = l.countAnchors(a, u); err != nil {
+		return nil, err
+	}
 
 	if s.ID != nil && s.ID.String() != u.String() {
 		loc, err := NewLocation(*s.ID)
@@ -576,15 +1061,31 @@ func (l *loader) loadSchema(ctx context.Context, data []byte, u uri.URI, v semve
 	if d == nil {
 		d = l.dialect
 	}
-	if err = l.traverse(&nc, &nc, s.nodes(), *l.doc.OpenAPI, *d); err != nil {
+	if err = l.checkDialect(*d); err != nil {
+		return nil, NewError(err, u)
+	}
+	if err = l.opts.Keywords.ValidateSchema(&s, *d); err != nil {
+		return nil, NewValidationError(err, KindSchema, s.AbsoluteLocation())
+	}
+	if err = l.compilePattern(&s); err != nil {
+		return nil, err
+	}
+	start = time.Now()
+	err = l.traverse(&nc, &nc, s.nodes(), *l.doc.OpenAPI, *d)
+	l.opts.Profile.add(loadPhaseResolve, start)
+	if err != nil {
 		return nil, err
 	}
 
 	return &s, nil
 }
 
-func (l *loader) loadNode(ctx context.Context, k Kind, data []byte, v semver.Version, s uri.URI) (nodectx, error) {
-	panic("not impl")
+// loadNode loads a resource of Kind k which is neither a Document nor a
+// Schema.
+//
+// TODO: support loading components as external resources
+func (l *loader) loadNode(ctx context.Context, k Kind, data []byte, u uri.URI) (Node, error) {
+	return nil, NewError(&UnsupportedKindError{Kind: k, URI: u}, u)
 }
 
 // func (l *loader) resolveDynamicRefs(n *nodectx) error {
@@ -631,9 +1132,38 @@ func (l *loader) getJSONSchemaDialect(data []byte, v *semver.Version) (*uri.URI,
 	default:
 		return nil, nil
 	}
+	if err = l.checkDialect(*sd); err != nil {
+		return nil, err
+	}
 	return sd, nil
 }
 
+// compilePattern compiles the pattern of s according to the loader's
+// PatternMode. Patterns are decoded with PatternLenient.
+func (l *loader) compilePattern(s *Schema) error {
+	if s.Pattern.IsNil() || l.opts.Patterns == PatternLenient {
+		return nil
+	}
+	re, err := CompilePattern(s.Pattern.Source(), l.opts.Patterns)
+	if err != nil {
+		return NewError(err, s.AbsoluteLocation())
+	}
+	s.Pattern = re
+	return nil
+}
+
+// checkDialect returns ErrUnsupportedDialect if a DialectRegistry was
+// provided and dialect is not registered with it.
+func (l *loader) checkDialect(dialect uri.URI) error {
+	if l.opts.Dialects == nil {
+		return nil
+	}
+	if _, ok := l.opts.Dialects.Lookup(dialect); !ok {
+		return &UnsupportedDialectError{Dialect: dialect}
+	}
+	return nil
+}
+
 type nodectx struct {
 	node
 	openapi       semver.Version
@@ -643,6 +1173,8 @@ type nodectx struct {
 	recursiveRefs []refctx
 	dynamicRefs   []refctx
 	resolvedRefs  []refctx
+	// depth is the number of references followed to reach the node
+	depth int
 }
 type refctx struct {
 	ref
@@ -651,6 +1183,23 @@ type refctx struct {
 	root       *nodectx
 	openapi    semver.Version
 	jsonschema uri.URI
+	// depth is the depth of the node containing the reference
+	depth int
+}
+
+// countAnchors enforces the MaxAnchors limit
+func (l *loader) countAnchors(a *Anchors, u uri.URI) error {
+	if a == nil {
+		return nil
+	}
+	l.anchors += len(a.Standard) + len(a.Dynamic)
+	if a.Recursive != nil {
+		l.anchors++
+	}
+	if l.opts.MaxAnchors > 0 && l.anchors > l.opts.MaxAnchors {
+		return NewError(fmt.Errorf("%w: more than %d anchors", ErrLimitExceeded, l.opts.MaxAnchors), u)
+	}
+	return nil
 }
 
 func newNodeCtx(n node, root *nodectx, openapi *semver.Version, jsonschema *uri.URI) (nodectx, error) {
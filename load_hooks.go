@@ -0,0 +1,106 @@
+package openapi
+
+import (
+	"time"
+
+	"github.com/chanced/uri"
+)
+
+// LoadHooks are callbacks invoked by Load as it progresses, allowing long
+// multi-resource loads to be observed, timed, and debugged (e.g. by logging
+// each event or recording it as a span).
+//
+// Hooks are called synchronously from the goroutine which called Load. Any
+// hook may be nil.
+type LoadHooks struct {
+	// OnResourceLoaded is called after each resource, including the
+	// Document itself, is fetched.
+	OnResourceLoaded func(ResourceLoadedEvent)
+	// OnRefResolved is called after each reference is resolved.
+	OnRefResolved func(RefResolvedEvent)
+	// OnValidation is called after the Validator validates the Document.
+	OnValidation func(ValidationEvent)
+	// OnWarning is called when Load encounters a problem which it tolerates
+	// (see LoadOpts.Tolerant).
+	OnWarning func(WarningEvent)
+}
+
+// ResourceLoadedEvent describes a resource fetched by Load.
+type ResourceLoadedEvent struct {
+	// URI of the resource
+	URI uri.URI
+	// Kind of the resource, as returned by the fetch function
+	Kind Kind
+	// Size is the length of the data, in bytes
+	Size int
+	// Duration of the fetch
+	Duration time.Duration
+	// Cached indicates that the resource was read from LoadOpts.Cache
+	// rather than fetched
+	Cached bool
+	// Err is the error returned by the fetch, if any
+	Err error
+}
+
+// RefResolvedEvent describes a reference resolved by Load.
+type RefResolvedEvent struct {
+	// Ref is the reference
+	Ref Ref
+	// Target is the absolute location of the resolved node. It is empty if
+	// Err is not nil.
+	Target uri.URI
+	// Duration of the resolution, including the loading of any resources
+	Duration time.Duration
+	// Err is the error encountered while resolving Ref, if any
+	Err error
+}
+
+// ValidationEvent describes validation performed by Load.
+type ValidationEvent struct {
+	// URI of the resource being validated
+	URI uri.URI
+	// Kind of the resource being validated
+	Kind Kind
+	// Document indicates that the fully-resolved Document was validated
+	// with ValidateDocument rather than the data of the resource with
+	// Validate.
+	Document bool
+	// Duration of the validation
+	Duration time.Duration
+	// Err is the result of the validation
+	Err error
+}
+
+// WarningEvent describes a problem tolerated by Load.
+type WarningEvent struct {
+	// Location of the problem
+	Location uri.URI
+	// Message describes the problem
+	Message string
+	// Err is the error which was tolerated, if any
+	Err error
+}
+
+func (h *LoadHooks) resourceLoaded(e ResourceLoadedEvent) {
+	if h != nil && h.OnResourceLoaded != nil {
+		h.OnResourceLoaded(e)
+	}
+}
+
+func (h *LoadHooks) refResolved(e RefResolvedEvent) {
+	if h != nil && h.OnRefResolved != nil {
+		h.OnRefResolved(e)
+	}
+}
+
+func (h *LoadHooks) validation(e ValidationEvent) {
+	if h != nil && h.OnValidation != nil {
+		h.OnValidation(e)
+	}
+}
+
+func (h *LoadHooks) warning(e WarningEvent) {
+	if h != nil && h.OnWarning != nil {
+		h.OnWarning(e)
+	}
+}
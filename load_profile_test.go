@@ -0,0 +1,92 @@
+package openapi_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chanced/openapi"
+	"github.com/chanced/uri"
+)
+
+func TestLoadProfile(t *testing.T) {
+	documents := map[string][]byte{
+		"https://example.com/openapi.json": []byte(`{
+			"openapi": "3.1.0",
+			"info": { "title": "Profile", "version": "1.0.0" },
+			"paths": {
+				"/pets": {
+					"get": {
+						"responses": {
+							"200": {
+								"description": "ok",
+								"content": {
+									"application/json": { "schema": { "$ref": "pet.json" } }
+								}
+							}
+						}
+					}
+				}
+			},
+			"components": {
+				"schemas": {
+					"Pets": { "type": "array", "items": { "$ref": "#/components/schemas/Pet" } },
+					"Pet": { "$ref": "pet.json" }
+				}
+			}
+		}`),
+		"https://example.com/pet.json": []byte(`{
+			"$id": "https://example.com/pet.json",
+			"type": "object",
+			"properties": { "name": { "type": "string" } }
+		}`),
+	}
+	fn := func(ctx context.Context, u uri.URI, kind openapi.Kind) (openapi.Kind, []byte, error) {
+		time.Sleep(time.Millisecond)
+		if u.Path == "/pet.json" {
+			return openapi.KindSchema, documents[u.String()], nil
+		}
+		return openapi.KindDocument, documents[u.String()], nil
+	}
+	v, err := openapi.NewOfflineValidator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := openapi.LoadProfile{Refs: 100}
+	_, err = openapi.Load(context.Background(), "https://example.com/openapi.json", v, fn, openapi.LoadOpts{Profile: &p})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Resources != 2 || p.Refs != 3 {
+		t.Errorf("expected 2 resources and 3 refs, got %d and %d", p.Resources, p.Refs)
+	}
+	if p.Bytes != int64(len(documents["https://example.com/openapi.json"])+len(documents["https://example.com/pet.json"])) {
+		t.Errorf("unexpected bytes: %d", p.Bytes)
+	}
+	if p.Fetch < 2*time.Millisecond {
+		t.Errorf("expected the time spent fetching to be attributed to Fetch, got %s", p.Fetch)
+	}
+	if p.Resolve >= time.Millisecond {
+		t.Errorf("expected the time spent fetching not to be attributed to Resolve, got %s", p.Resolve)
+	}
+	if p.Unmarshal <= 0 || p.SetLocation <= 0 || p.Validation <= 0 {
+		t.Errorf("expected each phase to be profiled, got %s", p)
+	}
+	if p.Other() < 0 || p.Total < p.Fetch+p.Validation {
+		t.Errorf("expected phases to be exclusive, got %s", p)
+	}
+	if !strings.HasPrefix(p.String(), "total ") {
+		t.Errorf("unexpected summary: %s", p)
+	}
+
+	fn = func(ctx context.Context, u uri.URI, kind openapi.Kind) (openapi.Kind, []byte, error) {
+		return openapi.KindDocument, []byte(`{}`), nil
+	}
+	if _, err = openapi.Load(context.Background(), "https://example.com/openapi.json", v, fn, openapi.LoadOpts{Profile: &p}); err == nil {
+		t.Fatal("expected an error")
+	}
+	if p.Resources != 1 || p.Refs != 0 || p.Total <= 0 {
+		t.Errorf("expected the profile to be reset and reported on failure, got %s", p)
+	}
+}
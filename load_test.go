@@ -3,10 +3,13 @@ package openapi_test
 import (
 	"context"
 	"embed"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/fs"
 	"testing"
+	"time"
 
 	"github.com/Masterminds/semver"
 	"github.com/chanced/openapi"
@@ -190,3 +193,321 @@ func TestTryGetOpenAPIVersion(t *testing.T) {
 func (NoopValidator) ValidateDocument(document *openapi.Document) error { return nil }
 
 var _ openapi.Validator = (*NoopValidator)(nil)
+
+// fetchResources returns a function for Load which returns the resource of
+// resources keyed by its URI. Resources with an openapi field are Documents.
+func fetchResources(resources map[string][]byte) func(ctx context.Context, uri uri.URI, kind openapi.Kind) (openapi.Kind, []byte, error) {
+	return func(ctx context.Context, uri uri.URI, kind openapi.Kind) (openapi.Kind, []byte, error) {
+		data, ok := resources[uri.String()]
+		if !ok {
+			return 0, nil, fmt.Errorf("unknown uri %q", uri)
+		}
+		if _, ok := openapi.TryGetOpenAPIVersion(data); ok {
+			return openapi.KindDocument, data, nil
+		}
+		return kind, data, nil
+	}
+}
+
+// loadResources loads the Document at documentURI from resources, keyed by
+// URI, without validation.
+func loadResources(t *testing.T, documentURI string, resources map[string][]byte) *openapi.Document {
+	t.Helper()
+	doc, err := openapi.Load(context.Background(), documentURI, NoopValidator{}, fetchResources(resources))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+// loadDocument loads data as the Document at documentURI without validation.
+func loadDocument(t *testing.T, documentURI string, data []byte) *openapi.Document {
+	t.Helper()
+	return loadResources(t, documentURI, map[string][]byte{documentURI: data})
+}
+
+func TestLoadExternalValues(t *testing.T) {
+	ctx := context.Background()
+	fn := func(ctx context.Context, uri uri.URI, kind openapi.Kind) (openapi.Kind, []byte, error) {
+		switch uri.String() {
+		case "https://example.com/openapi.json":
+			return openapi.KindDocument, []byte(`{
+				"openapi": "3.1.0",
+				"info": { "title": "examples", "version": "1.0.0" },
+				"components": {
+					"examples": {
+						"Pet": { "externalValue": "examples/pet.json" },
+						"Note": { "externalValue": "examples/note.txt" }
+					}
+				}
+			}`), nil
+		case "https://example.com/examples/pet.json":
+			return kind, []byte(`{ "name": "fido" }`), nil
+		case "https://example.com/examples/note.txt":
+			return kind, []byte("good dog"), nil
+		default:
+			return 0, nil, fmt.Errorf("unknown uri %q", uri)
+		}
+	}
+	doc, err := openapi.Load(ctx, "https://example.com/openapi.json", NoopValidator{}, fn, openapi.LoadOpts{ExternalValues: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pet struct {
+		Name string `json:"name"`
+	}
+	if err = doc.Components.Examples.Get("Pet").Object.Decode(&pet); err != nil {
+		t.Fatal(err)
+	}
+	if pet.Name != "fido" {
+		t.Errorf("expected %q, got %q", "fido", pet.Name)
+	}
+	var note string
+	if err = doc.Components.Examples.Get("Note").Object.Decode(&note); err != nil {
+		t.Fatal(err)
+	}
+	if note != "good dog" {
+		t.Errorf("expected %q, got %q", "good dog", note)
+	}
+
+	e, err := openapi.NewExample(map[string]interface{}{"name": 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var s openapi.Schema
+	if err = json.Unmarshal([]byte(`{ "properties": { "name": { "type": "string" } } }`), &s); err != nil {
+		t.Fatal(err)
+	}
+	cs, err := s.Compile(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = e.Validate(cs); err == nil {
+		t.Error("expected example to be invalid")
+	}
+	if err = doc.Components.Examples.Get("Pet").Object.Validate(cs); err != nil {
+		t.Error(err)
+	}
+	if err = (&openapi.Example{}).Decode(&note); !errors.Is(err, openapi.ErrMissingExampleValue) {
+		t.Errorf("expected ErrMissingExampleValue, got %v", err)
+	}
+}
+
+func TestLoadTimeout(t *testing.T) {
+	data := []byte(`{
+		"openapi": "3.1.0",
+		"info": { "title": "timeout", "version": "1.0.0" },
+		"components": {
+			"schemas": {
+				"Pet": { "$ref": "https://example.com/schemas/pet.json" }
+			}
+		}
+	}`)
+	block := make(chan struct{})
+	defer close(block)
+	// fn intentionally ignores ctx when fetching the referenced schema
+	fn := func(_ context.Context, u uri.URI, kind openapi.Kind) (openapi.Kind, []byte, error) {
+		if u.String() == "https://example.com/schemas/pet.json" {
+			<-block
+		}
+		return kind, data, nil
+	}
+	_, err := openapi.Load(context.Background(), "https://example.com/timeout.json", NoopValidator{}, fn, openapi.LoadOpts{
+		Timeout: 10 * time.Millisecond,
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = openapi.Load(ctx, "https://example.com/timeout.json", NoopValidator{}, fn)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestLoadLimits(t *testing.T) {
+	resources := map[string]string{
+		"https://example.com/openapi.json": `{
+			"openapi": "3.1.0",
+			"info": { "title": "limits", "version": "1.0.0" },
+			"components": {
+				"schemas": {
+					"A": { "$ref": "https://example.com/schemas/a.json" },
+					"Named": { "$anchor": "named", "type": "string" },
+					"Other": { "$anchor": "other", "type": "string" }
+				}
+			}
+		}`,
+		"https://example.com/schemas/a.json": `{ "properties": { "b": { "$ref": "https://example.com/schemas/b.json" } } }`,
+		"https://example.com/schemas/b.json": `{ "properties": { "c": { "$ref": "https://example.com/schemas/c.json" } } }`,
+		"https://example.com/schemas/c.json": `{ "type": "string" }`,
+	}
+	fn := func(_ context.Context, u uri.URI, kind openapi.Kind) (openapi.Kind, []byte, error) {
+		u.Fragment = ""
+		data, ok := resources[u.String()]
+		if !ok {
+			return 0, nil, fmt.Errorf("resource not found: %s", u.String())
+		}
+		return kind, []byte(data), nil
+	}
+	load := func(opts openapi.LoadOpts) error {
+		_, err := openapi.Load(context.Background(), "https://example.com/openapi.json", NoopValidator{}, fn, opts)
+		return err
+	}
+	if err := load(openapi.LoadOpts{MaxResources: 3, MaxRefDepth: 3, MaxAnchors: 2, MaxBytes: 1 << 20}); err != nil {
+		t.Fatalf("expected document to load within limits, got %v", err)
+	}
+	for name, opts := range map[string]openapi.LoadOpts{
+		"MaxResources": {MaxResources: 2},
+		"MaxRefDepth":  {MaxRefDepth: 2},
+		"MaxAnchors":   {MaxAnchors: 1},
+		"MaxBytes":     {MaxBytes: 100},
+	} {
+		if err := load(opts); !errors.Is(err, openapi.ErrLimitExceeded) {
+			t.Errorf("%s: expected ErrLimitExceeded, got %v", name, err)
+		}
+	}
+}
+
+func TestLoadAllowRef(t *testing.T) {
+	resources := map[string]string{
+		"https://example.com/openapi.json": `{
+			"openapi": "3.1.0",
+			"info": { "title": "policy", "version": "1.0.0" },
+			"components": {
+				"schemas": {
+					"Local": { "$ref": "schemas/local.json" },
+					"Remote": { "$ref": "https://example.org/schemas/remote.json" }
+				}
+			}
+		}`,
+		"https://example.com/schemas/local.json":  `{ "type": "string" }`,
+		"https://example.org/schemas/remote.json": `{ "type": "string" }`,
+	}
+	fn := func(_ context.Context, u uri.URI, kind openapi.Kind) (openapi.Kind, []byte, error) {
+		u.Fragment = ""
+		data, ok := resources[u.String()]
+		if !ok {
+			return 0, nil, fmt.Errorf("resource not found: %s", u.String())
+		}
+		return kind, []byte(data), nil
+	}
+	load := func(allow func(openapi.Location, uri.URI) error) error {
+		_, err := openapi.Load(context.Background(), "https://example.com/openapi.json", NoopValidator{}, fn, openapi.LoadOpts{AllowRef: allow})
+		return err
+	}
+	var targets []string
+	err := load(func(from openapi.Location, target uri.URI) error {
+		targets = append(targets, target.String())
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(targets) != 2 {
+		t.Errorf("expected AllowRef to be called for 2 references, got %v", targets)
+	}
+	if err = load(openapi.SameOriginOnly); !errors.Is(err, openapi.ErrRefNotAllowed) {
+		t.Errorf("expected ErrRefNotAllowed, got %v", err)
+	}
+	if err = load(openapi.FileOnly); !errors.Is(err, openapi.ErrRefNotAllowed) {
+		t.Errorf("expected ErrRefNotAllowed, got %v", err)
+	}
+	if err = openapi.SameOriginOnly(openapi.Location{}, uri.URI{}); err != nil {
+		t.Errorf("expected relative references to be allowed, got %v", err)
+	}
+}
+
+func TestLoadRelativeRootRef(t *testing.T) {
+	resources := map[string]string{
+		"https://example.com/api/openapi.json": `{
+			"openapi": "3.1.0",
+			"info": { "title": "relative", "version": "1.0.0" },
+			"components": {
+				"schemas": {
+					"Pet": { "$ref": "../schemas/pet.json" }
+				}
+			}
+		}`,
+		"https://example.com/schemas/pet.json": `{ "type": "object" }`,
+	}
+	var loaded []string
+	fn := func(_ context.Context, u uri.URI, kind openapi.Kind) (openapi.Kind, []byte, error) {
+		u.Fragment = ""
+		loaded = append(loaded, u.String())
+		data, ok := resources[u.String()]
+		if !ok {
+			return 0, nil, fmt.Errorf("resource not found: %s", u.String())
+		}
+		return kind, []byte(data), nil
+	}
+	doc, err := openapi.Load(context.Background(), "https://example.com/api/openapi.json", NoopValidator{}, fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pet := doc.Components.Schemas.Items[0].Schema
+	if pet.Ref == nil || !pet.Ref.IsResolved() {
+		t.Fatalf("expected the relative reference to be resolved, loaded %v", loaded)
+	}
+	if !pet.Ref.Resolved.Type.Contains(openapi.TypeObject) {
+		t.Errorf("expected the schema of pet.json, got %v", pet.Ref.Resolved.Type)
+	}
+}
+
+func TestLoadHooks(t *testing.T) {
+	resources := map[string]string{
+		"https://example.com/openapi.json": `{
+			"openapi": "3.1.0",
+			"info": { "title": "hooks", "version": "1.0.0" },
+			"components": {
+				"schemas": {
+					"Pet": { "$ref": "https://example.com/schemas/pet.json" },
+					"Pets": { "type": "array", "items": { "$ref": "#/components/schemas/Pet" } }
+				}
+			}
+		}`,
+		"https://example.com/schemas/pet.json": `{ "type": "object" }`,
+	}
+	fn := func(_ context.Context, u uri.URI, kind openapi.Kind) (openapi.Kind, []byte, error) {
+		u.Fragment = ""
+		data, ok := resources[u.String()]
+		if !ok {
+			return 0, nil, fmt.Errorf("resource not found: %s", u.String())
+		}
+		return kind, []byte(data), nil
+	}
+	var loaded, resolved []string
+	var validations int
+	hooks := &openapi.LoadHooks{
+		OnResourceLoaded: func(e openapi.ResourceLoadedEvent) {
+			if e.Size != len(resources[e.URI.String()]) {
+				t.Errorf("expected size of %s to be %d, got %d", e.URI.String(), len(resources[e.URI.String()]), e.Size)
+			}
+			loaded = append(loaded, e.URI.String())
+		},
+		OnRefResolved: func(e openapi.RefResolvedEvent) {
+			if e.Err != nil {
+				t.Error(e.Err)
+			}
+			resolved = append(resolved, e.Target.String())
+		},
+		OnValidation: func(e openapi.ValidationEvent) {
+			validations++
+		},
+	}
+	_, err := openapi.Load(context.Background(), "https://example.com/openapi.json", NoopValidator{}, fn, openapi.LoadOpts{Hooks: hooks})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded) != 2 || loaded[0] != "https://example.com/openapi.json" {
+		t.Errorf("expected the document and schema to be loaded, got %v", loaded)
+	}
+	if len(resolved) != 2 {
+		t.Errorf("expected 2 references to be resolved, got %v", resolved)
+	}
+	if validations != 2 {
+		t.Errorf("expected 2 validations, got %d", validations)
+	}
+}
@@ -38,6 +38,77 @@ func (l Location) RelativeLocation() jsonpointer.Pointer {
 	return l.relative
 }
 
+// DocumentURI returns the URI of the resource (e.g. the Document) which
+// contains the Location; that is, the AbsoluteLocation without its fragment.
+func (l Location) DocumentURI() uri.URI {
+	u := l.absolute
+	u.Fragment = ""
+	u.RawFragment = ""
+	return u
+}
+
+// Tokens returns the decoded tokens of the RelativeLocation (e.g.
+// ["paths", "/pets", "get"] for "/paths/~1pets/get").
+func (l Location) Tokens() []string {
+	if l.relative == "" {
+		return []string{}
+	}
+	// the pointer starts with "/" and so the first token is empty
+	return l.relative.Tokens()[1:]
+}
+
+// Parent returns the Location which contains l (e.g.
+// "https://example.com/openapi.json#/paths/~1pets" for
+// "https://example.com/openapi.json#/paths/~1pets/get"). If l is the root of
+// its resource, l and false are returned.
+func (l Location) Parent() (Location, bool) {
+	ptr, _, ok := l.relative.Pop()
+	if !ok {
+		return l, false
+	}
+	l.relative = ptr
+	l.absolute.Fragment = ptr.String()
+	l.absolute.RawFragment = ptr.String()
+	return l, true
+}
+
+// IsInComponents reports whether the Location is within the Components of
+// its Document (e.g. "#/components/schemas/Pet/properties/name").
+func (l Location) IsInComponents() bool {
+	t := l.Tokens()
+	return len(t) > 0 && t[0] == "components"
+}
+
+// IsInPath reports whether the Location is within a PathItem of the Paths of
+// its Document (e.g. "#/paths/~1pets/get").
+func (l Location) IsInPath() bool {
+	_, ok := l.Path()
+	return ok
+}
+
+// Component returns the type (e.g. "schemas") and name (e.g. "Pet") of the
+// component which contains the Location (e.g.
+// "#/components/schemas/Pet/properties/name"). If the Location is not within
+// a component, ok is false.
+func (l Location) Component() (typ Text, name Text, ok bool) {
+	t := l.Tokens()
+	if len(t) < 3 || t[0] != "components" {
+		return "", "", false
+	}
+	return Text(t[1]), Text(t[2]), true
+}
+
+// Path returns the path (e.g. "/pets/{petId}") of the PathItem which
+// contains the Location (e.g. "#/paths/~1pets~1{petId}/get"). If the Location
+// is not within the Paths of its Document, ok is false.
+func (l Location) Path() (path Text, ok bool) {
+	t := l.Tokens()
+	if len(t) < 2 || t[0] != "paths" {
+		return "", false
+	}
+	return Text(t[1]), true
+}
+
 func (l Location) AppendLocation(p string) Location {
 	l.relative = l.relative.AppendString(p)
 	l.absolute.Fragment = l.relative.String()
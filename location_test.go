@@ -39,3 +39,46 @@ func TestLocationAppend(t *testing.T) {
 		t.Errorf("expected %q, got %s", expected, loc.String())
 	}
 }
+
+func TestLocationDecomposition(t *testing.T) {
+	root, err := openapi.NewLocation(*uri.MustParse("https://example.com/openapi.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	op := root.AppendLocation("paths").AppendLocation("/pets/{petId}").AppendLocation("get")
+	if u := op.DocumentURI(); u.String() != "https://example.com/openapi.json" {
+		t.Errorf("expected the document URI, got %q", u.String())
+	}
+	tokens := op.Tokens()
+	if len(tokens) != 3 || tokens[0] != "paths" || tokens[1] != "/pets/{petId}" || tokens[2] != "get" {
+		t.Errorf("unexpected tokens: %v", tokens)
+	}
+	if path, ok := op.Path(); !ok || path != "/pets/{petId}" {
+		t.Errorf("expected path %q, got %q", "/pets/{petId}", path)
+	}
+	if !op.IsInPath() || op.IsInComponents() {
+		t.Error("expected the location to be in a path and not in components")
+	}
+	parent, ok := op.Parent()
+	if !ok || parent.RelativeLocation() != "/paths/~1pets~1{petId}" {
+		t.Errorf("unexpected parent: %q", parent.RelativeLocation())
+	}
+	if _, ok := root.Parent(); ok {
+		t.Error("expected the root to not have a parent")
+	}
+	if len(root.Tokens()) != 0 {
+		t.Error("expected the root to not have tokens")
+	}
+
+	prop := root.AppendLocation("components").AppendLocation("schemas").AppendLocation("Pet").AppendLocation("properties").AppendLocation("name")
+	typ, name, ok := prop.Component()
+	if !ok || typ != "schemas" || name != "Pet" {
+		t.Errorf("expected schemas/Pet, got %q/%q", typ, name)
+	}
+	if !prop.IsInComponents() || prop.IsInPath() {
+		t.Error("expected the location to be in components and not in a path")
+	}
+	if _, _, ok := root.AppendLocation("components").AppendLocation("schemas").Component(); ok {
+		t.Error("expected #/components/schemas to not be a component")
+	}
+}
@@ -0,0 +1,169 @@
+package openapi
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/chanced/uri"
+)
+
+// LockMode determines how Load treats a Lockfile. See LoadOpts.Lockfile.
+type LockMode uint8
+
+const (
+	// LockVerify requires each external resource to have an entry in the
+	// Lockfile which matches its data.
+	LockVerify LockMode = iota
+	// LockUpdate adds an entry to the Lockfile for each external resource
+	// which does not have one. Resources which have an entry must match it.
+	LockUpdate
+)
+
+const lockHashPrefix = "sha256:"
+
+// Lockfile records the content hash of each external resource referenced by
+// a Document, analogous to go.sum, so that changes to upstream resources are
+// detected rather than silently loaded. See LoadOpts.Lockfile.
+//
+// The text encoding of a Lockfile consists of a line for each resource,
+// sorted by URI, of the form:
+//
+//	https://example.com/schemas/pet.json sha256:<hex-encoded hash>
+//
+// Blank lines and lines beginning with "#" are ignored.
+//
+// A Lockfile is safe for concurrent use.
+type Lockfile struct {
+	mu     sync.RWMutex
+	hashes map[string]string
+}
+
+// ParseLockfile parses the text encoding of a Lockfile.
+func ParseLockfile(data []byte) (*Lockfile, error) {
+	var lf Lockfile
+	if err := lf.UnmarshalText(data); err != nil {
+		return nil, err
+	}
+	return &lf, nil
+}
+
+// Get returns the hash recorded for the resource at u.
+func (lf *Lockfile) Get(u uri.URI) (string, bool) {
+	lf.mu.RLock()
+	defer lf.mu.RUnlock()
+	h, ok := lf.hashes[resourceURI(u)]
+	return h, ok
+}
+
+// Set records the hash of data as that of the resource at u.
+func (lf *Lockfile) Set(u uri.URI, data []byte) {
+	lf.mu.Lock()
+	defer lf.mu.Unlock()
+	if lf.hashes == nil {
+		lf.hashes = make(map[string]string)
+	}
+	lf.hashes[resourceURI(u)] = lockHashPrefix + hashResource(data)
+}
+
+// Del removes the entry for the resource at u.
+func (lf *Lockfile) Del(u uri.URI) {
+	lf.mu.Lock()
+	defer lf.mu.Unlock()
+	delete(lf.hashes, resourceURI(u))
+}
+
+// Len returns the number of entries.
+func (lf *Lockfile) Len() int {
+	lf.mu.RLock()
+	defer lf.mu.RUnlock()
+	return len(lf.hashes)
+}
+
+// URIs returns the URIs of the resources of lf, sorted.
+func (lf *Lockfile) URIs() []string {
+	lf.mu.RLock()
+	defer lf.mu.RUnlock()
+	uris := make([]string, 0, len(lf.hashes))
+	for u := range lf.hashes {
+		uris = append(uris, u)
+	}
+	sort.Strings(uris)
+	return uris
+}
+
+// Verify checks data against the entry for the resource at u. An error
+// wrapping ErrLockMismatch is returned if lf does not have an entry for u or
+// the hash of data does not match it.
+func (lf *Lockfile) Verify(u uri.URI, data []byte) error {
+	h, ok := lf.Get(u)
+	if !ok {
+		return NewError(fmt.Errorf("%w: %s is missing from the lockfile", ErrLockMismatch, resourceURI(u)), u)
+	}
+	if actual := lockHashPrefix + hashResource(data); actual != h {
+		return NewError(fmt.Errorf("%w: %s has hash %s, expected %s", ErrLockMismatch, resourceURI(u), actual, h), u)
+	}
+	return nil
+}
+
+// check verifies data against lf according to mode, adding an entry for u if
+// mode is LockUpdate and lf does not have one
+func (lf *Lockfile) check(u uri.URI, data []byte, mode LockMode) error {
+	if mode == LockUpdate {
+		lf.mu.Lock()
+		if _, ok := lf.hashes[resourceURI(u)]; !ok {
+			if lf.hashes == nil {
+				lf.hashes = make(map[string]string)
+			}
+			lf.hashes[resourceURI(u)] = lockHashPrefix + hashResource(data)
+		}
+		lf.mu.Unlock()
+	}
+	return lf.Verify(u, data)
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (lf *Lockfile) MarshalText() ([]byte, error) {
+	uris := lf.URIs()
+	lf.mu.RLock()
+	defer lf.mu.RUnlock()
+	var b bytes.Buffer
+	for _, u := range uris {
+		b.WriteString(u)
+		b.WriteByte(' ')
+		b.WriteString(lf.hashes[u])
+		b.WriteByte('\n')
+	}
+	return b.Bytes(), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (lf *Lockfile) UnmarshalText(data []byte) error {
+	hashes := make(map[string]string)
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; s.Scan(); n++ {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 || !strings.HasPrefix(fields[1], lockHashPrefix) || len(fields[1]) != len(lockHashPrefix)+64 {
+			return fmt.Errorf("openapi: malformed lockfile entry on line %d", n)
+		}
+		u, err := uri.Parse(fields[0])
+		if err != nil {
+			return fmt.Errorf("openapi: malformed lockfile URI on line %d: %w", n, err)
+		}
+		hashes[resourceURI(*u)] = fields[1]
+	}
+	if err := s.Err(); err != nil {
+		return err
+	}
+	lf.mu.Lock()
+	defer lf.mu.Unlock()
+	lf.hashes = hashes
+	return nil
+}
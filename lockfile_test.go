@@ -0,0 +1,81 @@
+package openapi_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/chanced/openapi"
+	"github.com/chanced/uri"
+)
+
+func TestLockfile(t *testing.T) {
+	resources := map[string][]byte{
+		"https://example.com/lock.json": []byte(`{
+			"openapi": "3.1.0",
+			"info": { "title": "lock", "version": "1.0.0" },
+			"paths": {},
+			"components": {
+				"schemas": {
+					"Pet": { "$ref": "https://upstream.example.com/pet.json" },
+					"Owner": { "$ref": "https://upstream.example.com/owner.json#/$defs/Owner" }
+				}
+			}
+		}`),
+		"https://upstream.example.com/pet.json":   []byte(`{ "type": "object" }`),
+		"https://upstream.example.com/owner.json": []byte(`{ "$defs": { "Owner": { "type": "object" } } }`),
+	}
+	fn := func(ctx context.Context, u uri.URI, kind openapi.Kind) (openapi.Kind, []byte, error) {
+		if u.Host == "upstream.example.com" {
+			return openapi.KindSchema, resources[u.String()], nil
+		}
+		return openapi.KindDocument, resources[u.String()], nil
+	}
+	load := func(opts openapi.LoadOpts) error {
+		_, err := openapi.Load(context.Background(), "https://example.com/lock.json", NoopValidator{}, fn, opts)
+		return err
+	}
+
+	lf := &openapi.Lockfile{}
+	if err := load(openapi.LoadOpts{Lockfile: lf}); !errors.Is(err, openapi.ErrLockMismatch) {
+		t.Fatalf("expected ErrLockMismatch for an empty lockfile, got %v", err)
+	}
+	if err := load(openapi.LoadOpts{Lockfile: lf, LockMode: openapi.LockUpdate}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := lf.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "https://upstream.example.com/owner.json sha256:") || !strings.HasPrefix(lines[1], "https://upstream.example.com/pet.json sha256:") {
+		t.Fatalf("unexpected lockfile:\n%s", data)
+	}
+
+	lf, err = openapi.ParseLockfile(append([]byte("# spec dependencies\n\n"), data...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := load(openapi.LoadOpts{Lockfile: lf}); err != nil {
+		t.Fatalf("expected the lockfile to be satisfied, got %v", err)
+	}
+
+	resources["https://upstream.example.com/pet.json"] = []byte(`{ "type": "string" }`)
+	for _, mode := range []openapi.LockMode{openapi.LockVerify, openapi.LockUpdate} {
+		err := load(openapi.LoadOpts{Lockfile: lf, LockMode: mode})
+		if !errors.Is(err, openapi.ErrLockMismatch) || !strings.Contains(err.Error(), "pet.json") {
+			t.Errorf("expected ErrLockMismatch for pet.json, got %v", err)
+		}
+	}
+
+	for _, malformed := range []string{
+		"https://upstream.example.com/pet.json",
+		"https://upstream.example.com/pet.json md5:abc",
+		"https://upstream.example.com/pet.json sha256:abc",
+	} {
+		if _, err := openapi.ParseLockfile([]byte(malformed)); err == nil {
+			t.Errorf("expected an error for %q", malformed)
+		}
+	}
+}
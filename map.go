@@ -1,7 +1,6 @@
 package openapi
 
 import (
-	"bytes"
 	"encoding/json"
 	"reflect"
 
@@ -63,7 +62,7 @@ func (m *Map[T]) Del(key Text) {
 }
 
 func (m Map[T]) MarshalJSON() ([]byte, error) {
-	b := bytes.Buffer{}
+	b := getBuffer()
 	b.WriteByte('{')
 	var err error
 	var s []byte
@@ -71,16 +70,17 @@ func (m Map[T]) MarshalJSON() ([]byte, error) {
 		if b.Len() > 1 {
 			b.WriteByte(',')
 		}
-		jsonx.EncodeAndWriteString(&b, v.Key.String())
+		jsonx.EncodeAndWriteString(b, v.Key.String())
 		b.WriteByte(':')
 		s, err = json.Marshal(v.Value)
 		if err != nil {
+			putBuffer(b)
 			return nil, err
 		}
 		b.Write(s)
 	}
 	b.WriteByte('}')
-	return b.Bytes(), nil
+	return releaseBuffer(b), nil
 }
 
 func (m *Map[T]) UnmarshalJSON(data []byte) error {
@@ -95,7 +95,7 @@ func (m *Map[T]) UnmarshalJSON(data []byte) error {
 		if err = json.Unmarshal([]byte(value.Raw), &t); err != nil {
 			return false
 		}
-		v = KeyValue[T]{Key: Text(key.String()), Value: t}
+		v = KeyValue[T]{Key: intern(key.String()), Value: t}
 		m.Items = append(m.Items, v)
 		return true
 	})
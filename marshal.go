@@ -0,0 +1,168 @@
+package openapi
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/chanced/transcode"
+	"github.com/tidwall/gjson"
+)
+
+// MarshalOpts configures the output of Document.MarshalJSONWithOpts and
+// Document.MarshalYAMLWithOpts.
+//
+// Regardless of options, fields are emitted in the order defined by the
+// specification and extensions are emitted in alphabetical order, after
+// fields.
+type MarshalOpts struct {
+	// SortComponents orders the entries of each map of the Document's
+	// Components (e.g. "#/components/schemas") by key.
+	SortComponents bool
+
+	// SortPaths orders the entries of the Document's Paths and Webhooks by
+	// key.
+	SortPaths bool
+
+	// SortScopes orders the scopes of each OAuthFlow of the Document's
+	// SecuritySchemes by name.
+	SortScopes bool
+
+	// Less, if set, determines the order of keys when sorting. Keys are
+	// sorted alphabetically by default. Extensions are always sorted
+	// alphabetically and emitted last.
+	Less func(a, b Text) bool
+}
+
+// CanonicalMarshalOpts are MarshalOpts which produce a canonical encoding of
+// a Document: fields in specification order, followed by alphabetized
+// extensions, with the keys of Components sorted.
+var CanonicalMarshalOpts = MarshalOpts{SortComponents: true}
+
+func (opts MarshalOpts) less() func(a, b Text) bool {
+	if opts.Less != nil {
+		return opts.Less
+	}
+	return func(a, b Text) bool { return a < b }
+}
+
+// MarshalJSONWithOpts marshals the Document to JSON, ordering members
+// according to opts.
+func (d Document) MarshalJSONWithOpts(opts MarshalOpts) ([]byte, error) {
+	data, err := d.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	less := opts.less()
+	if opts.SortComponents {
+		components := gjson.GetBytes(data, "components")
+		var keys []string
+		components.ForEach(func(key, value gjson.Result) bool {
+			if value.IsObject() && !strings.HasPrefix(key.String(), "x-") {
+				keys = append(keys, key.String())
+			}
+			return true
+		})
+		for _, key := range keys {
+			if data, err = sortMembersAt(data, "components."+gjsonEscape(key), less); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if opts.SortPaths {
+		for _, path := range []string{"paths", "webhooks"} {
+			if data, err = sortMembersAt(data, path, less); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if opts.SortScopes {
+		for _, path := range scopesPaths(data) {
+			if data, err = sortMembersAt(data, path, less); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return data, nil
+}
+
+// scopesPaths returns the gjson paths of the scopes of each OAuthFlow of the
+// SecuritySchemes of the encoded Document data
+func scopesPaths(data []byte) []string {
+	var paths []string
+	gjson.GetBytes(data, "components.securitySchemes").ForEach(func(name, scheme gjson.Result) bool {
+		scheme.Get("flows").ForEach(func(flow, _ gjson.Result) bool {
+			paths = append(paths, "components.securitySchemes."+gjsonEscape(name.String())+".flows."+gjsonEscape(flow.String())+".scopes")
+			return true
+		})
+		return true
+	})
+	return paths
+}
+
+// MarshalYAMLWithOpts marshals the Document to YAML, ordering members
+// according to opts.
+func (d Document) MarshalYAMLWithOpts(opts MarshalOpts) ([]byte, error) {
+	data, err := d.MarshalJSONWithOpts(opts)
+	if err != nil {
+		return nil, err
+	}
+	return transcode.YAMLFromJSON(data)
+}
+
+// sortMembersAt sorts the members of the JSON object located at path within
+// data. Extensions are sorted alphabetically and placed after all other
+// members. If path does not exist or is not an object, data is returned
+// unmodified.
+func sortMembersAt(data []byte, path string, less func(a, b Text) bool) ([]byte, error) {
+	res := gjson.GetBytes(data, path)
+	if !res.IsObject() {
+		return data, nil
+	}
+	if res.Index <= 0 || res.Index+len(res.Raw) > len(data) {
+		return nil, fmt.Errorf("openapi: failed to locate %q in encoded document", path)
+	}
+	type member struct {
+		key Text
+		raw string
+	}
+	var members, exts []member
+	res.ForEach(func(key, value gjson.Result) bool {
+		m := member{key: Text(key.String()), raw: key.Raw + ":" + value.Raw}
+		if m.key.HasPrefix("x-") {
+			exts = append(exts, m)
+		} else {
+			members = append(members, m)
+		}
+		return true
+	})
+	sort.SliceStable(members, func(i, j int) bool { return less(members[i].key, members[j].key) })
+	sort.SliceStable(exts, func(i, j int) bool { return exts[i].key < exts[j].key })
+
+	b := getBuffer()
+	b.Grow(len(data))
+	b.Write(data[:res.Index])
+	b.WriteByte('{')
+	for i, m := range append(members, exts...) {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(m.raw)
+	}
+	b.WriteByte('}')
+	b.Write(data[res.Index+len(res.Raw):])
+	return releaseBuffer(b), nil
+}
+
+// gjsonEscape escapes the gjson path characters of key
+func gjsonEscape(key string) string {
+	var b strings.Builder
+	for _, r := range key {
+		switch r {
+		case '.', '*', '?', '|', '#', '@', '\\', '!', '=', '<', '>', '%':
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,108 @@
+package openapi
+
+import (
+	"sync"
+
+	"github.com/chanced/uri"
+)
+
+// MarshalCache retains the JSON encoding of nodes, keyed by their absolute
+// location, so that validating a Document repeatedly does not re-marshal
+// nodes which have not changed (see StdValidator.Cache).
+//
+// Nodes are not observed for changes. An entry is only reused for the node
+// it was created from. A Document which is patched (see
+// Document.ApplyJSONPatch and Document.ApplyMergePatch) is marshaled again,
+// along with all of its nodes, but nodes which are otherwise modified in
+// place must be reported with Invalidate or InvalidateNode.
+//
+// A nil *MarshalCache does not retain anything. A MarshalCache is safe for
+// concurrent use.
+type MarshalCache struct {
+	mu      sync.Mutex
+	entries map[string]marshalCacheEntry
+}
+
+type marshalCacheEntry struct {
+	node     Node
+	revision uint64
+	data     []byte
+}
+
+// NewMarshalCache returns an empty MarshalCache.
+func NewMarshalCache() *MarshalCache {
+	return &MarshalCache{entries: map[string]marshalCacheEntry{}}
+}
+
+// marshal returns the JSON encoding of n, located at loc, marshaling n only
+// if it is not retained. The returned data must not be modified.
+func (mc *MarshalCache) marshal(n Node, loc uri.URI) ([]byte, error) {
+	if mc == nil {
+		return n.MarshalJSON()
+	}
+	key := loc.String()
+	mc.mu.Lock()
+	e, ok := mc.entries[key]
+	mc.mu.Unlock()
+	rev := revisionOf(n)
+	if ok && e.node == n && e.revision == rev {
+		return e.data, nil
+	}
+	data, err := n.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	if mc.entries == nil {
+		mc.entries = map[string]marshalCacheEntry{}
+	}
+	mc.entries[key] = marshalCacheEntry{node: n, revision: rev, data: data}
+	return data, nil
+}
+
+// revisionOf returns the revision of n if n is a Document, which is replaced
+// in place when patched, and 0 otherwise.
+func revisionOf(n Node) uint64 {
+	if d, ok := n.(*Document); ok {
+		return d.revision
+	}
+	return 0
+}
+
+// Invalidate reports that the node at loc, an absolute location (e.g.
+// "https://example.com/openapi.json#/paths/~1pets/get"), has been edited.
+// The entries of the nodes which contain loc, along with those within loc,
+// are discarded.
+func (mc *MarshalCache) Invalidate(loc uri.URI) {
+	if mc == nil {
+		return
+	}
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	for key := range mc.entries {
+		u, err := uri.Parse(key)
+		if err != nil || containsLocation(*u, loc) || containsLocation(loc, *u) {
+			delete(mc.entries, key)
+		}
+	}
+}
+
+// InvalidateNode reports that n, or one of its descendants, has been edited.
+// See Invalidate.
+func (mc *MarshalCache) InvalidateNode(n Node) {
+	if n == nil {
+		return
+	}
+	mc.Invalidate(n.AbsoluteLocation())
+}
+
+// Reset discards all entries.
+func (mc *MarshalCache) Reset() {
+	if mc == nil {
+		return
+	}
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	mc.entries = map[string]marshalCacheEntry{}
+}
@@ -0,0 +1,103 @@
+package openapi_test
+
+import (
+	"testing"
+
+	"github.com/chanced/openapi"
+)
+
+func TestMarshalCache(t *testing.T) {
+	doc := loadDocument(t, "https://example.com/openapi.json", []byte(`{
+		"openapi": "3.1.0",
+		"info": { "title": "Pet Store", "version": "1.0.0" },
+		"paths": {
+			"/pets": {
+				"get": {
+					"parameters": [{ "name": "limit", "in": "query", "schema": { "type": "integer" } }],
+					"responses": { "200": { "description": "ok" } }
+				}
+			}
+		}
+	}`))
+	v, err := openapi.NewOfflineValidator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	v.Cache = openapi.NewMarshalCache()
+	if err = v.ValidateDocument(doc); err != nil {
+		t.Fatal(err)
+	}
+
+	param := doc.Paths.Get("/pets").Get.Parameters.Items[0].Object
+	param.In = "body"
+	if err = v.ValidateDocument(doc); err != nil {
+		t.Errorf("expected the retained encoding to be validated, got %v", err)
+	}
+	v.Cache.InvalidateNode(param)
+	if err = v.ValidateDocument(doc); err == nil {
+		t.Error("expected the invalidated Document to be invalid")
+	}
+
+	param.In = openapi.InQuery
+	v.Cache.Reset()
+	if err = v.ValidateDocument(doc); err != nil {
+		t.Errorf("expected the reset cache to be repopulated, got %v", err)
+	}
+
+	iv, err := openapi.NewIncrementalValidator(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = iv.ValidateDocument(doc); err != nil {
+		t.Fatal(err)
+	}
+	param.In = "body"
+	iv.InvalidateNode(param)
+	if err = iv.ValidateDocument(doc); err == nil {
+		t.Error("expected the IncrementalValidator to invalidate the cache")
+	}
+
+	var cache *openapi.MarshalCache
+	cache.InvalidateNode(param)
+	cache.Reset()
+}
+
+func TestMarshalCachePatch(t *testing.T) {
+	data := []byte(`{
+		"openapi": "3.1.0",
+		"info": { "title": "Pet Store", "version": "1.0.0" },
+		"paths": {}
+	}`)
+	for _, tc := range []struct {
+		name  string
+		patch func(*openapi.Document) error
+	}{
+		{"JSONPatch", func(d *openapi.Document) error {
+			return d.ApplyJSONPatch([]byte(`[{"op":"remove","path":"/info"}]`))
+		}},
+		{"MergePatch", func(d *openapi.Document) error {
+			return d.ApplyMergePatch([]byte(`{"info":null}`))
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			doc := loadDocument(t, "https://example.com/openapi.json", data)
+			v, err := openapi.NewOfflineValidator()
+			if err != nil {
+				t.Fatal(err)
+			}
+			v.Cache = openapi.NewMarshalCache()
+			if err = v.ValidateDocument(doc); err != nil {
+				t.Fatal(err)
+			}
+			if err = tc.patch(doc); err != nil {
+				t.Fatal(err)
+			}
+			if doc.Info != nil {
+				t.Fatal("expected info to be removed")
+			}
+			if err = v.ValidateDocument(doc); err == nil {
+				t.Error("expected the patched Document to be marshaled again and be invalid")
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/chanced/openapi"
+	"github.com/tidwall/gjson"
+)
+
+func TestMarshalJSONWithOpts(t *testing.T) {
+	data := []byte(`{
+		"openapi": "3.1.0",
+		"info": { "title": "ordering", "version": "1.0.0" },
+		"paths": {
+			"/zebras": {},
+			"x-paths": true,
+			"/ants": {}
+		},
+		"components": {
+			"schemas": {
+				"Zebra": { "type": "object" },
+				"Ant": { "type": "object" },
+				"Mole": { "type": "object" }
+			}
+		}
+	}`)
+	var doc openapi.Document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	keys := func(data []byte, path string) string {
+		var k []string
+		gjson.GetBytes(data, path).ForEach(func(key, _ gjson.Result) bool {
+			k = append(k, key.String())
+			return true
+		})
+		return strings.Join(k, ",")
+	}
+
+	out, err := doc.MarshalJSONWithOpts(openapi.CanonicalMarshalOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if k := keys(out, "components.schemas"); k != "Ant,Mole,Zebra" {
+		t.Errorf("expected schemas to be sorted, got %s", k)
+	}
+	if k := keys(out, "paths"); k != "/zebras,/ants,x-paths" {
+		t.Errorf("expected paths to retain their order, got %s", k)
+	}
+
+	out, err = doc.MarshalJSONWithOpts(openapi.MarshalOpts{
+		SortComponents: true,
+		SortPaths:      true,
+		Less:           func(a, b openapi.Text) bool { return a > b },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if k := keys(out, "components.schemas"); k != "Zebra,Mole,Ant" {
+		t.Errorf("expected schemas to be sorted by Less, got %s", k)
+	}
+	if k := keys(out, "paths"); k != "/zebras,/ants,x-paths" {
+		t.Errorf("expected paths to be sorted by Less with extensions last, got %s", k)
+	}
+	if !json.Valid(out) {
+		t.Errorf("invalid JSON: %s", out)
+	}
+}
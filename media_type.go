@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 
 	"github.com/chanced/jsonx"
-	"github.com/chanced/transcode"
 	"gopkg.in/yaml.v3"
 )
 
@@ -44,6 +43,36 @@ type MediaType struct {
 	Encoding *EncodingMap `json:"encoding,omitempty"`
 }
 
+// SetExample marshals v and sets it as the example of the MediaType, clearing
+// Examples as the fields are mutually exclusive.
+//
+// If v is a json.RawMessage or jsonx.RawMessage, it is used as is.
+func (mt *MediaType) SetExample(v interface{}) error {
+	b, err := encodeExample(v)
+	if err != nil {
+		return err
+	}
+	mt.Example = b
+	mt.Examples = nil
+	return nil
+}
+
+// SetExamples sets the examples of the MediaType, clearing Example as the fields
+// are mutually exclusive.
+func (mt *MediaType) SetExamples(examples *ExampleMap) {
+	mt.Examples = examples
+	mt.Example = nil
+}
+
+// ValidateExamples returns an error wrapping ErrExampleAndExamples if both
+// Example and Examples are set.
+func (mt *MediaType) ValidateExamples() error {
+	if mt == nil {
+		return nil
+	}
+	return validateExampleExclusivity(mt.Example, mt.Examples, KindMediaType, mt.AbsoluteLocation())
+}
+
 func (mt *MediaType) Nodes() []Node {
 	if mt == nil {
 		return nil
@@ -154,11 +183,7 @@ func (mt MediaType) MarshalYAML() (interface{}, error) {
 
 // UnmarshalYAML satisfies gopkg.in/yaml.v3 Unmarshaler interface
 func (mt *MediaType) UnmarshalYAML(value *yaml.Node) error {
-	v, err := yaml.Marshal(value)
-	if err != nil {
-		return err
-	}
-	j, err := transcode.JSONFromYAML(v)
+	j, err := jsonFromYAMLNode(value)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,289 @@
+// Package naming maps the names of an OpenAPI Document's components and the
+// keys of its Schemas' properties to Go identifiers.
+//
+// A Namer assigns each component a unique, exported Go type name and each
+// property a unique field name within its Schema. Names are derived with
+// github.com/chanced/caps, so initialisms (e.g. "Id", "Url") are written in
+// their conventional form ("ID", "URL"). Names set with the "x-go-name"
+// annotation (openapi.AnnotationGoName) take precedence.
+//
+// Names are assigned deterministically from the Document, so generators
+// which share a Namer, or construct one from the same Document and Options,
+// agree on the name of every type and field.
+package naming
+
+import (
+	"go/token"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/chanced/caps"
+	"github.com/chanced/openapi"
+)
+
+// Options configures a Namer.
+type Options struct {
+	// Initialisms are words, in addition to caps.DefaultReplacements, which
+	// are written in their given case when they appear in a name (e.g.
+	// "SKU", "OAuth").
+	Initialisms []string
+	// Reserved are names which are not assigned to any component (e.g. the
+	// names of types the generator emits itself, such as "Client").
+	Reserved []string
+}
+
+// suffixes are appended to the names of components which collide with the
+// name of a component of another Kind
+var suffixes = map[openapi.Kind]string{
+	openapi.KindSchema:      "",
+	openapi.KindRequestBody: "RequestBody",
+	openapi.KindResponse:    "Response",
+	openapi.KindParameter:   "Parameter",
+	openapi.KindHeader:      "Header",
+}
+
+// Namer maps components and properties to Go identifiers. A Namer is
+// immutable once created and is safe for concurrent use.
+type Namer struct {
+	caps caps.Caps
+	// types are the names of components, keyed by Kind and component key
+	types map[openapi.Kind]map[openapi.Text]string
+	// locations are the names of components, keyed by absolute location
+	locations map[string]string
+}
+
+// New returns a Namer with a name assigned to each of the Schemas,
+// RequestBodies, Responses, Parameters, and Headers of doc's Components,
+// in that order of precedence.
+//
+// Names which collide with the name of a component of another Kind are
+// suffixed with the Kind (e.g. "PetParameter"). Names which still collide
+// are suffixed with a number, starting at 2.
+func New(doc *openapi.Document, opts Options) *Namer {
+	replacements := append([]caps.Replacement{}, caps.DefaultReplacements...)
+	for _, i := range opts.Initialisms {
+		if i == "" {
+			continue
+		}
+		replacements = append(replacements, caps.Replacement{
+			Camel:     strings.ToUpper(i[:1]) + strings.ToLower(i[1:]),
+			Screaming: i,
+		})
+	}
+	n := &Namer{
+		caps:      caps.New(caps.Config{Replacements: replacements}),
+		types:     map[openapi.Kind]map[openapi.Text]string{},
+		locations: map[string]string{},
+	}
+	used := map[string]bool{}
+	for _, r := range opts.Reserved {
+		used[r] = true
+	}
+	if doc == nil || doc.Components == nil {
+		return n
+	}
+	for _, c := range components(doc.Components) {
+		name := n.componentName(c)
+		if used[name] {
+			name += suffixes[c.kind]
+		}
+		name = unique(name, used)
+		used[name] = true
+		if n.types[c.kind] == nil {
+			n.types[c.kind] = map[openapi.Text]string{}
+		}
+		n.types[c.kind][c.key] = name
+		if c.location != "" {
+			n.locations[c.location] = name
+		}
+	}
+	return n
+}
+
+// Type returns the name of the component of kind with key.
+func (n *Namer) Type(kind openapi.Kind, key openapi.Text) (string, bool) {
+	name, ok := n.types[kind][key]
+	return name, ok
+}
+
+// TypeOf returns the name of the component located at the absolute location
+// of node.
+func (n *Namer) TypeOf(node openapi.Node) (string, bool) {
+	name, ok := n.locations[node.AbsoluteLocation().String()]
+	return name, ok
+}
+
+// Identifier converts s into an exported Go identifier (e.g. "pet_id"
+// becomes "PetID"). Identifiers which would otherwise begin with a digit
+// are prefixed with "X".
+func (n *Namer) Identifier(s openapi.Text) string {
+	id := n.caps.ToCamel(s.String())
+	if id == "" || !unicode.IsLetter([]rune(id)[0]) {
+		id = "X" + id
+	}
+	return id
+}
+
+// Unexported converts s into an unexported Go identifier (e.g. "PetID"
+// becomes "petID" and "APIKey" becomes "apiKey"). Identifiers which are Go
+// keywords are suffixed with an underscore.
+func (n *Namer) Unexported(s openapi.Text) string {
+	id := n.caps.ToCamel(s.String())
+	if id == "" || !unicode.IsLetter([]rune(id)[0]) {
+		return "x" + id
+	}
+	// a leading initialism is lowercased in its entirety
+	r := []rune(id)
+	i := 0
+	for i < len(r) && unicode.IsUpper(r[i]) {
+		i++
+	}
+	if i > 1 && i < len(r) && unicode.IsLower(r[i]) {
+		i--
+	}
+	for j := 0; j < i; j++ {
+		r[j] = unicode.ToLower(r[j])
+	}
+	id = string(r)
+	if token.IsKeyword(id) {
+		id += "_"
+	}
+	return id
+}
+
+// Field is the Go field name of a property of a Schema.
+type Field struct {
+	// Key of the property.
+	Key openapi.Text
+	// Name of the Go field.
+	Name string
+}
+
+// Fields returns the field names of the properties of s, in the order the
+// properties are defined. A property with the "x-go-name" annotation uses
+// its value; properties whose names collide are suffixed with a number,
+// starting at 2.
+func (n *Namer) Fields(s *openapi.Schema) []Field {
+	if s == nil || s.Properties == nil {
+		return nil
+	}
+	used := map[string]bool{}
+	fields := make([]Field, 0, len(s.Properties.Items))
+	for _, p := range s.Properties.Items {
+		name := n.Identifier(p.Key)
+		if p.Schema != nil {
+			if v, ok, err := openapi.AnnotationGoName.Get(p.Schema); ok && err == nil {
+				name = v.String()
+			}
+		}
+		name = unique(name, used)
+		used[name] = true
+		fields = append(fields, Field{Key: p.Key, Name: name})
+	}
+	return fields
+}
+
+// Field returns the field name of the property key of s.
+func (n *Namer) Field(s *openapi.Schema, key openapi.Text) (string, bool) {
+	for _, f := range n.Fields(s) {
+		if f.Key == key {
+			return f.Name, true
+		}
+	}
+	return "", false
+}
+
+func (n *Namer) componentName(c component) string {
+	if c.node != nil {
+		if v, ok, err := openapi.AnnotationGoName.Get(c.node); ok && err == nil {
+			return v.String()
+		}
+	}
+	return n.Identifier(c.key)
+}
+
+type component struct {
+	kind     openapi.Kind
+	key      openapi.Text
+	node     openapi.Node
+	location string
+}
+
+// components returns the components of c which are named, in order of
+// precedence
+func components(c *openapi.Components) []component {
+	var res []component
+	if c.Schemas != nil {
+		for _, s := range c.Schemas.Items {
+			cmp := component{kind: openapi.KindSchema, key: s.Key}
+			if s.Schema != nil {
+				cmp.node = s.Schema
+				cmp.location = s.Schema.AbsoluteLocation().String()
+			}
+			res = append(res, cmp)
+		}
+	}
+	if c.RequestBodies != nil {
+		for _, e := range c.RequestBodies.Items {
+			cmp := component{kind: openapi.KindRequestBody, key: e.Key}
+			if e.Component != nil {
+				cmp.location = e.Component.AbsoluteLocation().String()
+				if e.Component.Object != nil {
+					cmp.node = e.Component.Object
+				}
+			}
+			res = append(res, cmp)
+		}
+	}
+	if c.Responses != nil {
+		for _, e := range c.Responses.Items {
+			cmp := component{kind: openapi.KindResponse, key: e.Key}
+			if e.Component != nil {
+				cmp.location = e.Component.AbsoluteLocation().String()
+				if e.Component.Object != nil {
+					cmp.node = e.Component.Object
+				}
+			}
+			res = append(res, cmp)
+		}
+	}
+	if c.Parameters != nil {
+		for _, e := range c.Parameters.Items {
+			cmp := component{kind: openapi.KindParameter, key: e.Key}
+			if e.Component != nil {
+				cmp.location = e.Component.AbsoluteLocation().String()
+				if e.Component.Object != nil {
+					cmp.node = e.Component.Object
+				}
+			}
+			res = append(res, cmp)
+		}
+	}
+	if c.Headers != nil {
+		for _, e := range c.Headers.Items {
+			cmp := component{kind: openapi.KindHeader, key: e.Key}
+			if e.Component != nil {
+				cmp.location = e.Component.AbsoluteLocation().String()
+				if e.Component.Object != nil {
+					cmp.node = e.Component.Object
+				}
+			}
+			res = append(res, cmp)
+		}
+	}
+	return res
+}
+
+// unique returns name if it is not used, otherwise name suffixed with the
+// lowest number, starting at 2, which is not used
+func unique(name string, used map[string]bool) string {
+	if !used[name] {
+		return name
+	}
+	for i := 2; ; i++ {
+		if n := name + strconv.Itoa(i); !used[n] {
+			return n
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package naming_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/chanced/openapi"
+	"github.com/chanced/openapi/naming"
+	"github.com/chanced/uri"
+)
+
+func TestNamer(t *testing.T) {
+	data := []byte(`{
+		"openapi": "3.1.0",
+		"info": { "title": "naming", "version": "1.0.0" },
+		"components": {
+			"schemas": {
+				"pet": {
+					"type": "object",
+					"properties": {
+						"id": { "type": "integer" },
+						"user_id": { "type": "integer" },
+						"userId": { "type": "integer" },
+						"type": { "type": "string", "x-go-name": "Kind" }
+					}
+				},
+				"Pet": { "type": "object" },
+				"api_key": { "type": "string" },
+				"order": { "type": "object", "x-go-name": "PurchaseOrder" },
+				"item_sku": { "type": "string" },
+				"client": { "type": "object" },
+				"2fa": { "type": "boolean" }
+			},
+			"parameters": {
+				"pet": { "name": "pet", "in": "query", "schema": { "type": "string" } }
+			}
+		}
+	}`)
+	fn := func(ctx context.Context, u uri.URI, kind openapi.Kind) (openapi.Kind, []byte, error) {
+		return openapi.KindDocument, data, nil
+	}
+	v, err := openapi.NewOfflineValidator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := openapi.Load(context.Background(), "https://example.com/openapi.json", v, fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := naming.New(doc, naming.Options{
+		Initialisms: []string{"SKU"},
+		Reserved:    []string{"Client"},
+	})
+
+	types := []struct {
+		kind     openapi.Kind
+		key      openapi.Text
+		expected string
+	}{
+		{openapi.KindSchema, "pet", "Pet"},
+		{openapi.KindSchema, "Pet", "Pet2"},
+		{openapi.KindSchema, "api_key", "APIKey"},
+		{openapi.KindSchema, "order", "PurchaseOrder"},
+		{openapi.KindSchema, "item_sku", "ItemSKU"},
+		{openapi.KindSchema, "client", "Client2"},
+		{openapi.KindSchema, "2fa", "X2Fa"},
+		{openapi.KindParameter, "pet", "PetParameter"},
+	}
+	for _, tt := range types {
+		name, ok := n.Type(tt.kind, tt.key)
+		if !ok || name != tt.expected {
+			t.Errorf("expected %s %q to be named %q, got %q", tt.kind, tt.key, tt.expected, name)
+		}
+	}
+
+	pet := doc.Components.Schemas.Get("pet")
+	if name, ok := n.TypeOf(pet); !ok || name != "Pet" {
+		t.Errorf("expected TypeOf to return Pet, got %q", name)
+	}
+
+	fields := n.Fields(pet)
+	expected := []string{"ID", "UserID", "UserID2", "Kind"}
+	if len(fields) != len(expected) {
+		t.Fatalf("expected %d fields, got %v", len(expected), fields)
+	}
+	for i, f := range fields {
+		if f.Name != expected[i] {
+			t.Errorf("expected field %q to be named %q, got %q", f.Key, expected[i], f.Name)
+		}
+	}
+	if name, ok := n.Field(pet, "userId"); !ok || name != "UserID2" {
+		t.Errorf("expected UserID2, got %q", name)
+	}
+
+	if id := n.Unexported("type"); id != "type_" {
+		t.Errorf("expected type_, got %q", id)
+	}
+	if id := n.Unexported("Pet_ID"); id != "petID" {
+		t.Errorf("expected petID, got %q", id)
+	}
+	if id := n.Unexported("api_key"); id != "apiKey" {
+		t.Errorf("expected apiKey, got %q", id)
+	}
+}
@@ -99,3 +99,45 @@ func appendEdges(nodes []node, elems ...node) []node {
 	}
 	return nodes
 }
+
+// walkNodes invokes fn for n and each of its descendants, including the
+// targets of resolved references. Each node is visited at most once.
+func walkNodes(n node, fn func(n node) error) error {
+	return walkNodesSeen(n, map[node]bool{}, fn)
+}
+
+func walkNodesSeen(n node, seen map[node]bool, fn func(n node) error) error {
+	if n == nil || n.isNil() || seen[n] {
+		return nil
+	}
+	seen[n] = true
+	if err := fn(n); err != nil {
+		return err
+	}
+	for _, c := range n.nodes() {
+		if err := walkNodesSeen(c, seen, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// walkLocalNodes invokes fn for n and each of its descendants. Unlike
+// walkNodes, the targets of references are not visited.
+func walkLocalNodes(n node, fn func(n node) error) error {
+	if n == nil || n.isNil() {
+		return nil
+	}
+	if err := fn(n); err != nil {
+		return err
+	}
+	if _, ok := n.(Ref); ok {
+		return nil
+	}
+	for _, c := range n.nodes() {
+		if err := walkLocalNodes(c, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/chanced/openapi"
+)
+
+func TestNodes(t *testing.T) {
+	data := []byte(`{
+		"openapi": "3.1.0",
+		"info": {
+			"title": "nodes",
+			"version": "1.0.0",
+			"contact": { "name": "support" },
+			"license": { "name": "MIT" }
+		},
+		"components": {
+			"schemas": { "Pet": { "type": "object" } },
+			"parameters": { "limit": { "name": "limit", "in": "query" } }
+		}
+	}`)
+	var doc openapi.Document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	type nodes interface{ Nodes() []openapi.Node }
+
+	found := map[openapi.Kind]bool{}
+	var walk func(n openapi.Node)
+	walk = func(n openapi.Node) {
+		found[n.Kind()] = true
+		if nn, ok := n.(nodes); ok {
+			for _, c := range nn.Nodes() {
+				walk(c)
+			}
+		}
+	}
+	walk(doc.Info)
+	walk(doc.Components)
+
+	for _, k := range []openapi.Kind{
+		openapi.KindInfo,
+		openapi.KindContact,
+		openapi.KindLicense,
+		openapi.KindComponents,
+		openapi.KindSchemaMap,
+		openapi.KindSchema,
+		openapi.KindParameterMap,
+		openapi.KindParameterComponent,
+		openapi.KindParameter,
+	} {
+		if !found[k] {
+			t.Errorf("expected %s to be reachable through Nodes", k)
+		}
+	}
+
+	var info *openapi.Info
+	if info.Nodes() != nil {
+		t.Error("expected Nodes of a nil *Info to be nil")
+	}
+}
@@ -0,0 +1,159 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+)
+
+// Number is a JSON number. The literal text of the number is retained so
+// that values which can not be represented by a float64, such as large
+// int64 and uint64 values, round-trip without loss of precision.
+type Number json.Number
+
+// ParseNumber returns the Number of the JSON number literal s.
+func ParseNumber(s string) (Number, error) {
+	if !isJSONNumber(s) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidNumber, s)
+	}
+	return Number(s), nil
+}
+
+// IntNumber returns the Number of v.
+func IntNumber(v int64) Number { return Number(strconv.FormatInt(v, 10)) }
+
+// UintNumber returns the Number of v.
+func UintNumber(v uint64) Number { return Number(strconv.FormatUint(v, 10)) }
+
+// FloatNumber returns the Number of v, using the fewest digits necessary to
+// represent v exactly. v must be finite.
+func FloatNumber(v float64) (Number, error) {
+	if math.IsInf(v, 0) || math.IsNaN(v) {
+		return "", fmt.Errorf("%w: %v can not be represented in JSON", ErrInvalidNumber, v)
+	}
+	return Number(strconv.FormatFloat(v, 'g', -1, 64)), nil
+}
+
+// String returns the literal text of the number.
+func (n Number) String() string { return string(n) }
+
+// IsInteger reports whether n is an integer, regardless of how it is
+// written (e.g. 1, 1.0, and 1e2 are all integers).
+func (n Number) IsInteger() bool {
+	r, ok := n.BigRat()
+	return ok && r.IsInt()
+}
+
+// Int64 returns n as an int64. An error wrapping ErrInvalidNumber is
+// returned if n is not an integer or is out of the range of an int64.
+func (n Number) Int64() (int64, error) {
+	if v, err := strconv.ParseInt(string(n), 10, 64); err == nil {
+		return v, nil
+	}
+	i, err := n.integer()
+	if err != nil {
+		return 0, err
+	}
+	if !i.IsInt64() {
+		return 0, fmt.Errorf("%w: %s overflows int64", ErrInvalidNumber, n)
+	}
+	return i.Int64(), nil
+}
+
+// Uint64 returns n as a uint64. An error wrapping ErrInvalidNumber is
+// returned if n is not an integer or is out of the range of a uint64.
+func (n Number) Uint64() (uint64, error) {
+	if v, err := strconv.ParseUint(string(n), 10, 64); err == nil {
+		return v, nil
+	}
+	i, err := n.integer()
+	if err != nil {
+		return 0, err
+	}
+	if !i.IsUint64() {
+		return 0, fmt.Errorf("%w: %s overflows uint64", ErrInvalidNumber, n)
+	}
+	return i.Uint64(), nil
+}
+
+// Float64 returns the float64 nearest to n. The result may not be exact;
+// use BigRat if precision is required.
+func (n Number) Float64() (float64, error) {
+	v, err := strconv.ParseFloat(string(n), 64)
+	if err != nil {
+		return v, fmt.Errorf("%w: %v", ErrInvalidNumber, err)
+	}
+	return v, nil
+}
+
+// BigRat returns the exact value of n.
+func (n Number) BigRat() (*big.Rat, bool) {
+	if !isJSONNumber(string(n)) {
+		return nil, false
+	}
+	return new(big.Rat).SetString(string(n))
+}
+
+// BigInt returns the value of n if it is an integer.
+func (n Number) BigInt() (*big.Int, bool) {
+	i, err := n.integer()
+	return i, err == nil
+}
+
+// BigFloat returns n as a *big.Float with a precision of prec bits, rounded
+// according to mode.
+func (n Number) BigFloat(prec uint, mode big.RoundingMode) (*big.Float, error) {
+	f, _, err := big.ParseFloat(string(n), 10, prec, mode)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidNumber, err)
+	}
+	return f, nil
+}
+
+// Cmp compares n and o, returning -1 if n < o, 0 if n == o, and 1 if n > o.
+// Numbers which are not valid are considered equal.
+func (n Number) Cmp(o Number) int {
+	a, ok := n.BigRat()
+	if !ok {
+		return 0
+	}
+	b, ok := o.BigRat()
+	if !ok {
+		return 0
+	}
+	return a.Cmp(b)
+}
+
+func (n Number) integer() (*big.Int, error) {
+	r, ok := n.BigRat()
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidNumber, string(n))
+	}
+	if !r.IsInt() {
+		return nil, fmt.Errorf("%w: %s is not an integer", ErrInvalidNumber, n)
+	}
+	return r.Num(), nil
+}
+
+// MarshalJSON writes the literal text of n.
+func (n Number) MarshalJSON() ([]byte, error) {
+	if !isJSONNumber(string(n)) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidNumber, string(n))
+	}
+	return []byte(n), nil
+}
+
+// UnmarshalJSON retains the literal text of the number data.
+func (n *Number) UnmarshalJSON(data []byte) error {
+	var jn json.Number
+	if err := json.Unmarshal(data, &jn); err != nil {
+		return err
+	}
+	if !isJSONNumber(string(jn)) {
+		return fmt.Errorf("%w: %s", ErrInvalidNumber, data)
+	}
+	*n = Number(jn)
+	return nil
+}
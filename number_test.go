@@ -0,0 +1,75 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/chanced/openapi"
+)
+
+func TestNumberPrecision(t *testing.T) {
+	var s openapi.Schema
+	err := json.Unmarshal([]byte(`{
+		"type": "integer",
+		"minimum": -9223372036854775808,
+		"maximum": 18446744073709551615,
+		"multipleOf": 9007199254740993
+	}`), &s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, err := s.Minimum.Int64(); err != nil || v != math.MinInt64 {
+		t.Errorf("expected %d, got %d, %v", int64(math.MinInt64), v, err)
+	}
+	if v, err := s.Maximum.Uint64(); err != nil || v != math.MaxUint64 {
+		t.Errorf("expected %d, got %d, %v", uint64(math.MaxUint64), v, err)
+	}
+	if _, err := s.Maximum.Int64(); !errors.Is(err, openapi.ErrInvalidNumber) {
+		t.Errorf("expected ErrInvalidNumber for int64 overflow, got %v", err)
+	}
+	if r, ok := s.MultipleOf.BigRat(); !ok || r.RatString() != "9007199254740993" {
+		t.Errorf("expected exact multipleOf, got %v", r)
+	}
+	b, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var v map[string]json.RawMessage
+	if err = json.Unmarshal(b, &v); err != nil {
+		t.Fatal(err)
+	}
+	for k, expected := range map[string]string{
+		"minimum":    "-9223372036854775808",
+		"maximum":    "18446744073709551615",
+		"multipleOf": "9007199254740993",
+	} {
+		if string(v[k]) != expected {
+			t.Errorf("expected %s to be %s, got %s", k, expected, v[k])
+		}
+	}
+
+	n := openapi.Number("1.0e2")
+	if !n.IsInteger() {
+		t.Error("expected 1.0e2 to be an integer")
+	}
+	if v, err := n.Int64(); err != nil || v != 100 {
+		t.Errorf("expected 100, got %d, %v", v, err)
+	}
+	if _, err := openapi.Number("1.5").Int64(); !errors.Is(err, openapi.ErrInvalidNumber) {
+		t.Errorf("expected ErrInvalidNumber for a fraction, got %v", err)
+	}
+	if openapi.IntNumber(2).Cmp(openapi.Number("2.0")) != 0 || openapi.UintNumber(3).Cmp(openapi.IntNumber(2)) != 1 {
+		t.Error("unexpected comparison")
+	}
+	if _, err := openapi.FloatNumber(math.Inf(1)); !errors.Is(err, openapi.ErrInvalidNumber) {
+		t.Errorf("expected ErrInvalidNumber for +Inf, got %v", err)
+	}
+	if _, err := openapi.ParseNumber("0x10"); !errors.Is(err, openapi.ErrInvalidNumber) {
+		t.Errorf("expected ErrInvalidNumber for 0x10, got %v", err)
+	}
+	if err := json.Unmarshal([]byte(`{"minimum": "abc"}`), &s); err == nil {
+		t.Error("expected an error for a non-numeric minimum")
+	}
+}
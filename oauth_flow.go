@@ -2,8 +2,12 @@ package openapi
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
 
-	"github.com/chanced/transcode"
 	"gopkg.in/yaml.v3"
 )
 
@@ -133,11 +137,7 @@ func (o OAuthFlow) MarshalYAML() (interface{}, error) {
 
 // UnmarshalYAML satisfies gopkg.in/yaml.v3 Unmarshaler interface
 func (o *OAuthFlow) UnmarshalYAML(value *yaml.Node) error {
-	v, err := yaml.Marshal(value)
-	if err != nil {
-		return err
-	}
-	j, err := transcode.JSONFromYAML(v)
+	j, err := jsonFromYAMLNode(value)
 	if err != nil {
 		return err
 	}
@@ -306,11 +306,7 @@ func (f OAuthFlows) MarshalYAML() (interface{}, error) {
 
 // UnmarshalYAML satisfies gopkg.in/yaml.v3 Unmarshaler interface
 func (f *OAuthFlows) UnmarshalYAML(value *yaml.Node) error {
-	v, err := yaml.Marshal(value)
-	if err != nil {
-		return err
-	}
-	j, err := transcode.JSONFromYAML(v)
+	j, err := jsonFromYAMLNode(value)
 	if err != nil {
 		return err
 	}
@@ -322,3 +318,100 @@ var (
 
 	_ node = (*OAuthFlows)(nil)
 )
+
+// Validate checks that each flow of f has the URLs required by its flow type
+// and a map of scopes, which may be empty. The implicit and authorizationCode
+// flows require an authorizationUrl, while the password, clientCredentials,
+// and authorizationCode flows require a tokenUrl. Each URL must be an
+// absolute https URL; http is permitted only for loopback hosts (e.g.
+// "localhost").
+//
+// The error returned is a ValidationError wrapping ErrInvalidOAuthFlow.
+func (f *OAuthFlows) Validate() error {
+	if f == nil {
+		return nil
+	}
+	if err := f.Implicit.validate("implicit", true, false); err != nil {
+		return err
+	}
+	if err := f.Password.validate("password", false, true); err != nil {
+		return err
+	}
+	if err := f.ClientCredentials.validate("clientCredentials", false, true); err != nil {
+		return err
+	}
+	return f.AuthorizationCode.validate("authorizationCode", true, true)
+}
+
+func (f *OAuthFlow) validate(flow string, authorization, token bool) error {
+	if f == nil {
+		return nil
+	}
+	invalid := func(field string, format string, args ...interface{}) error {
+		err := fmt.Errorf("%w: %s "+format, append([]interface{}{ErrInvalidOAuthFlow, flow}, args...)...)
+		loc := f.Location
+		if field != "" {
+			loc = loc.AppendLocation(field)
+		}
+		return NewValidationError(err, KindOAuthFlow, loc.AbsoluteLocation())
+	}
+	for _, u := range []struct {
+		field    string
+		value    Text
+		required bool
+	}{
+		{"authorizationUrl", f.AuthorizationURL, authorization},
+		{"tokenUrl", f.TokenURL, token},
+		{"refreshUrl", f.RefreshURL, false},
+	} {
+		if u.value == "" {
+			if u.required {
+				return invalid("", "flow requires %s", u.field)
+			}
+			continue
+		}
+		if err := checkOAuthURL(u.value); err != nil {
+			return invalid(u.field, "%s %q %v", u.field, u.value, err)
+		}
+	}
+	if f.Scopes == nil {
+		return invalid("", "flow requires scopes")
+	}
+	return nil
+}
+
+// checkOAuthURL checks that v is an absolute https URL or an http URL of a
+// loopback host
+func checkOAuthURL(v Text) error {
+	u, err := url.Parse(v.String())
+	if err != nil || !u.IsAbs() || u.Host == "" {
+		return errors.New("is not an absolute URL")
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "https":
+		return nil
+	case "http":
+		if h := u.Hostname(); h == "localhost" || net.ParseIP(h).IsLoopback() {
+			return nil
+		}
+	}
+	return errors.New("must use https")
+}
+
+// validateOAuthFlows validates the flows of each oauth2 SecurityScheme of
+// the components of doc.
+func validateOAuthFlows(doc *Document) error {
+	if doc.Components == nil || doc.Components.SecuritySchemes == nil {
+		return nil
+	}
+	for _, e := range doc.Components.SecuritySchemes.Items {
+		ss := e.Component.Object
+		if ss == nil || ss.Type != SecuritySchemeTypeOAuth2 {
+			continue
+		}
+		if err := ss.Flows.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package openapi_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/chanced/openapi"
+	"github.com/chanced/uri"
+)
+
+func TestOAuthFlowsValidate(t *testing.T) {
+	scopes := &openapi.Scopes{}
+	tests := []struct {
+		name  string
+		flows openapi.OAuthFlows
+		valid bool
+	}{
+		{"implicit", openapi.OAuthFlows{Implicit: &openapi.OAuthFlow{AuthorizationURL: "https://example.com/auth", Scopes: scopes}}, true},
+		{"implicit missing authorizationUrl", openapi.OAuthFlows{Implicit: &openapi.OAuthFlow{TokenURL: "https://example.com/token", Scopes: scopes}}, false},
+		{"password", openapi.OAuthFlows{Password: &openapi.OAuthFlow{TokenURL: "https://example.com/token", Scopes: scopes}}, true},
+		{"password missing tokenUrl", openapi.OAuthFlows{Password: &openapi.OAuthFlow{Scopes: scopes}}, false},
+		{"clientCredentials missing tokenUrl", openapi.OAuthFlows{ClientCredentials: &openapi.OAuthFlow{AuthorizationURL: "https://example.com/auth", Scopes: scopes}}, false},
+		{"authorizationCode", openapi.OAuthFlows{AuthorizationCode: &openapi.OAuthFlow{AuthorizationURL: "https://example.com/auth", TokenURL: "https://example.com/token", RefreshURL: "https://example.com/refresh", Scopes: scopes}}, true},
+		{"authorizationCode missing tokenUrl", openapi.OAuthFlows{AuthorizationCode: &openapi.OAuthFlow{AuthorizationURL: "https://example.com/auth", Scopes: scopes}}, false},
+		{"missing scopes", openapi.OAuthFlows{ClientCredentials: &openapi.OAuthFlow{TokenURL: "https://example.com/token"}}, false},
+		{"relative url", openapi.OAuthFlows{ClientCredentials: &openapi.OAuthFlow{TokenURL: "/token", Scopes: scopes}}, false},
+		{"http url", openapi.OAuthFlows{ClientCredentials: &openapi.OAuthFlow{TokenURL: "http://example.com/token", Scopes: scopes}}, false},
+		{"http refresh url", openapi.OAuthFlows{ClientCredentials: &openapi.OAuthFlow{TokenURL: "https://example.com/token", RefreshURL: "http://example.com/refresh", Scopes: scopes}}, false},
+		{"http loopback url", openapi.OAuthFlows{ClientCredentials: &openapi.OAuthFlow{TokenURL: "http://localhost:8080/token", Scopes: scopes}}, true},
+		{"http loopback ip", openapi.OAuthFlows{ClientCredentials: &openapi.OAuthFlow{TokenURL: "http://127.0.0.1/token", Scopes: scopes}}, true},
+	}
+	for _, test := range tests {
+		err := test.flows.Validate()
+		if test.valid && err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+		}
+		if !test.valid && !errors.Is(err, openapi.ErrInvalidOAuthFlow) {
+			t.Errorf("%s: expected ErrInvalidOAuthFlow, got %v", test.name, err)
+		}
+	}
+}
+
+func TestValidateOAuthFlows(t *testing.T) {
+	data := []byte(`{
+		"openapi": "3.1.0",
+		"info": { "title": "oauth", "version": "1.0.0" },
+		"paths": {},
+		"components": {
+			"securitySchemes": {
+				"oauth": {
+					"type": "oauth2",
+					"flows": {
+						"authorizationCode": {
+							"authorizationUrl": "https://example.com/auth",
+							"tokenUrl": "http://example.com/token",
+							"scopes": {}
+						}
+					}
+				}
+			}
+		}
+	}`)
+	fn := func(ctx context.Context, uri uri.URI, kind openapi.Kind) (openapi.Kind, []byte, error) {
+		return openapi.KindDocument, data, nil
+	}
+	v, err := openapi.NewOfflineValidator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := openapi.Load(context.Background(), "https://example.com/oauth.json", NoopValidator{}, fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = v.ValidateDocument(doc)
+	if !errors.Is(err, openapi.ErrInvalidOAuthFlow) {
+		t.Fatalf("expected ErrInvalidOAuthFlow, got %v", err)
+	}
+	var ve *openapi.ValidationError
+	if !errors.As(err, &ve) || ve.URI.Fragment != "/components/securitySchemes/oauth/flows/authorizationCode/tokenUrl" {
+		t.Errorf("expected the error to be located at the tokenUrl, got %v", err)
+	}
+}
@@ -1,12 +1,11 @@
 package openapi
 
 import (
-	"bytes"
 	"encoding/json"
 	"reflect"
+	"sort"
 
 	"github.com/chanced/jsonx"
-	"github.com/chanced/transcode"
 	"github.com/tidwall/gjson"
 	"gopkg.in/yaml.v3"
 )
@@ -44,6 +43,13 @@ func (om *ObjMap[T]) Refs() []Ref {
 	return refs
 }
 
+func (om *ObjMap[T]) Nodes() []Node {
+	if om == nil {
+		return nil
+	}
+	return downcastNodes(om.nodes())
+}
+
 func (om *ObjMap[T]) nodes() []node {
 	if om == nil {
 		return nil
@@ -105,6 +111,73 @@ func (om *ObjMap[T]) Set(key Text, obj T) {
 func (om *ObjMap[T]) Del(key Text) {
 }
 
+// NewObjMap returns an ObjMap of the entries of m, ordered by key.
+func NewObjMap[T node](m map[Text]T) *ObjMap[T] {
+	keys := make([]Text, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	om := &ObjMap[T]{Items: make([]Item[T], 0, len(m))}
+	for _, k := range keys {
+		om.Set(k, m[k])
+	}
+	return om
+}
+
+// Len returns the number of entries in the ObjMap.
+func (om *ObjMap[T]) Len() int {
+	if om == nil {
+		return 0
+	}
+	return len(om.Items)
+}
+
+// Keys returns the keys of the ObjMap, in order.
+func (om *ObjMap[T]) Keys() []Text {
+	if om == nil {
+		return nil
+	}
+	keys := make([]Text, len(om.Items))
+	for i, item := range om.Items {
+		keys[i] = item.Key
+	}
+	return keys
+}
+
+// Values returns the values of the ObjMap, in order.
+func (om *ObjMap[T]) Values() []T {
+	if om == nil {
+		return nil
+	}
+	values := make([]T, len(om.Items))
+	for i, item := range om.Items {
+		values[i] = item.Value
+	}
+	return values
+}
+
+// Range calls fn for each entry of the ObjMap, in order, until fn returns
+// false.
+func (om *ObjMap[T]) Range(fn func(key Text, value T) bool) {
+	if om == nil {
+		return
+	}
+	for _, item := range om.Items {
+		if !fn(item.Key, item.Value) {
+			return
+		}
+	}
+}
+
+// SortByKey orders the entries of the ObjMap by key.
+func (om *ObjMap[T]) SortByKey() {
+	if om == nil {
+		return
+	}
+	sort.SliceStable(om.Items, func(i, j int) bool { return om.Items[i].Key < om.Items[j].Key })
+}
+
 func (om *ObjMap[T]) UnmarshalJSON(data []byte) error {
 	var t T
 	var m ObjMap[T]
@@ -117,13 +190,13 @@ func (om *ObjMap[T]) UnmarshalJSON(data []byte) error {
 			Struct: "PathItemMap",
 		}
 	}
-	var pi T
 	var err error
 	gjson.ParseBytes(data).ForEach(func(key, value gjson.Result) bool {
+		var pi T
 		if err = json.Unmarshal([]byte(value.Raw), &pi); err != nil {
 			return false
 		}
-		m.Items = append(m.Items, Item[T]{Key: Text(key.String()), Value: pi})
+		m.Items = append(m.Items, Item[T]{Key: intern(key.String()), Value: pi})
 		return true
 	})
 	*om = m
@@ -132,7 +205,7 @@ func (om *ObjMap[T]) UnmarshalJSON(data []byte) error {
 
 func (om *ObjMap[T]) MarshalJSON() ([]byte, error) {
 	var err error
-	b := bytes.Buffer{}
+	b := getBuffer()
 	var j []byte
 	_ = j
 	b.WriteByte('{')
@@ -140,16 +213,17 @@ func (om *ObjMap[T]) MarshalJSON() ([]byte, error) {
 		if b.Len() > 1 {
 			b.WriteByte(',')
 		}
-		jsonx.EncodeAndWriteString(&b, entry.Key)
+		jsonx.EncodeAndWriteString(b, entry.Key)
 		b.WriteByte(':')
 		j, err = entry.Value.MarshalJSON()
 		if err != nil {
+			putBuffer(b)
 			return nil, err
 		}
 		b.Write(j)
 	}
 	b.WriteByte('}')
-	return b.Bytes(), err
+	return releaseBuffer(b), err
 }
 
 // UnmarshalYAML satisfies gopkg.in/yaml.v3 Marshaler interface
@@ -168,11 +242,7 @@ func (om ObjMap[T]) MarshalYAML() (interface{}, error) {
 
 // UnmarshalYAML satisfies gopkg.in/yaml.v3 Unmarshaler interface
 func (om *ObjMap[T]) UnmarshalYAML(value *yaml.Node) error {
-	v, err := yaml.Marshal(value)
-	if err != nil {
-		return err
-	}
-	j, err := transcode.JSONFromYAML(v)
+	j, err := jsonFromYAMLNode(value)
 	if err != nil {
 		return err
 	}
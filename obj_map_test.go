@@ -0,0 +1,32 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/chanced/openapi"
+)
+
+func TestObjMapUnmarshalJSON(t *testing.T) {
+	var vars openapi.ServerVariableMap
+	err := json.Unmarshal([]byte(`{
+		"env": { "default": "api", "enum": ["api", "staging"] },
+		"version": { "default": "v1" }
+	}`), &vars)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vars.Items) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(vars.Items))
+	}
+	env, version := vars.Items[0].Value, vars.Items[1].Value
+	if env == version {
+		t.Fatal("expected each entry to be decoded into a distinct value")
+	}
+	if env.Default != "api" || len(env.Enum) != 2 {
+		t.Errorf("unexpected value of env: %+v", env)
+	}
+	if version.Default != "v1" || len(version.Enum) != 0 {
+		t.Errorf("unexpected value of version: %+v", version)
+	}
+}
@@ -3,7 +3,6 @@ package openapi
 import (
 	"encoding/json"
 
-	"github.com/chanced/transcode"
 	"gopkg.in/yaml.v3"
 )
 
@@ -100,17 +99,20 @@ func (os ObjSlice[T]) MarshalYAML() (interface{}, error) {
 
 // UnmarshalYAML satisfies gopkg.in/yaml.v3 Unmarshaler interface
 func (os *ObjSlice[T]) UnmarshalYAML(value *yaml.Node) error {
-	v, err := yaml.Marshal(value)
-	if err != nil {
-		return err
-	}
-	j, err := transcode.JSONFromYAML(v)
+	j, err := jsonFromYAMLNode(value)
 	if err != nil {
 		return err
 	}
 	return json.Unmarshal(j, os)
 }
 
+func (os *ObjSlice[T]) Nodes() []Node {
+	if os == nil {
+		return nil
+	}
+	return downcastNodes(os.nodes())
+}
+
 func (os *ObjSlice[T]) nodes() []node {
 	var edges []node
 	for _, x := range os.Items {
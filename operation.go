@@ -3,7 +3,6 @@ package openapi
 import (
 	"encoding/json"
 
-	"github.com/chanced/transcode"
 	"gopkg.in/yaml.v3"
 )
 
@@ -98,7 +97,7 @@ type Operation struct {
 	// an empty security requirement ({}) can be included in the array. This
 	// definition overrides any declared top-level security. To remove a
 	// top-level security declaration, an empty array can be used.
-	Security *SecurityRequirementMap `json:"security,omitempty"`
+	Security *SecurityRequirementSlice `json:"security,omitempty"`
 
 	// An alternative server array to service this operation. If an alternative
 	// server object is specified at the Path Item Object or Root level, it will
@@ -180,6 +179,66 @@ func (o *Operation) Anchors() (*Anchors, error) {
 	return anchors, nil
 }
 
+// EffectiveParameters returns the parameters which apply to the Operation
+// when it is invoked through pathItem.
+//
+// Parameters defined on the PathItem are overridden, but never removed, by
+// Operation parameters sharing the same name and location. References are
+// resolved and the resulting slice contains no duplicates. PathItem
+// parameters retain their order, followed by any parameters introduced by
+// the Operation.
+//
+// An error wrapping ErrUnresolvedReference is returned if a referenced
+// Parameter has not been resolved.
+func (o *Operation) EffectiveParameters(pathItem *PathItem) ([]*Parameter, error) {
+	var params []*Parameter
+	idx := map[parameterKey]int{}
+
+	add := func(ps *ParameterSlice) error {
+		if ps == nil {
+			return nil
+		}
+		for _, c := range ps.Items {
+			if c == nil {
+				continue
+			}
+			if !c.IsResolved() {
+				return newErrUnresolvedReference(c.Reference)
+			}
+			p := c.Object
+			if p == nil {
+				continue
+			}
+			k := parameterKey{name: p.Name, in: p.In}
+			if i, ok := idx[k]; ok {
+				params[i] = p
+				continue
+			}
+			idx[k] = len(params)
+			params = append(params, p)
+		}
+		return nil
+	}
+	if pathItem != nil {
+		if err := add(pathItem.Parameters); err != nil {
+			return nil, err
+		}
+	}
+	if o != nil {
+		if err := add(o.Parameters); err != nil {
+			return nil, err
+		}
+	}
+	return params, nil
+}
+
+// parameterKey is the combination of name and location which uniquely
+// identifies a Parameter.
+type parameterKey struct {
+	name Text
+	in   In
+}
+
 // // ResolveNodeByPointer resolves a Node by a json pointer
 // func (o *Operation) ResolveNodeByPointer(ptr jsonpointer.Pointer) (Node, error) {
 // 	if err := ptr.Validate(); err != nil {
@@ -271,11 +330,7 @@ func (o Operation) MarshalYAML() (interface{}, error) {
 
 // UnmarshalYAML satisfies gopkg.in/yaml.v3 Unmarshaler interface
 func (o *Operation) UnmarshalYAML(value *yaml.Node) error {
-	v, err := yaml.Marshal(value)
-	if err != nil {
-		return err
-	}
-	j, err := transcode.JSONFromYAML(v)
+	j, err := jsonFromYAMLNode(value)
 	if err != nil {
 		return err
 	}
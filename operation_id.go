@@ -0,0 +1,111 @@
+package openapi
+
+import (
+	"strconv"
+	"strings"
+)
+
+// OperationIDStrategy generates an operationId for the Operation of method
+// at path (or, for webhooks, the name of the webhook).
+type OperationIDStrategy func(method Text, path Text) Text
+
+var (
+	// OperationIDCamelCase generates operationIds in lower camel case (e.g.
+	// "getPetsByPetID" for GET /pets/{petId}).
+	OperationIDCamelCase OperationIDStrategy = operationIDCase(func(t Text) Text { return t.ToLowerCamel() })
+	// OperationIDPascalCase generates operationIds in upper camel case (e.g.
+	// "GetPetsByPetID" for GET /pets/{petId}).
+	OperationIDPascalCase OperationIDStrategy = operationIDCase(func(t Text) Text { return t.ToCamel() })
+	// OperationIDSnakeCase generates operationIds in snake case (e.g.
+	// "get_pets_by_pet_id" for GET /pets/{petId}).
+	OperationIDSnakeCase OperationIDStrategy = operationIDCase(func(t Text) Text { return t.ToSnake() })
+	// OperationIDKebabCase generates operationIds in kebab case (e.g.
+	// "get-pets-by-pet-id" for GET /pets/{petId}).
+	OperationIDKebabCase OperationIDStrategy = operationIDCase(func(t Text) Text { return t.ToKebab() })
+)
+
+// operationIDCase returns an OperationIDStrategy which joins the method and
+// the segments of the path, prefixing path parameters with "by", and
+// converts the result with fn
+func operationIDCase(fn func(Text) Text) OperationIDStrategy {
+	return func(method Text, path Text) Text {
+		words := []string{strings.ToLower(method.String())}
+		for _, seg := range strings.Split(path.String(), "/") {
+			if seg == "" {
+				continue
+			}
+			if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+				words = append(words, "by", seg[1:len(seg)-1])
+				continue
+			}
+			words = append(words, seg)
+		}
+		return fn(Text(strings.Join(words, " ")))
+	}
+}
+
+// OperationIDChange is an operationId set by Document.EnsureOperationIDs.
+type OperationIDChange struct {
+	// Key is the path of the PathItem or the name of the webhook which
+	// contains the Operation.
+	Key     Text
+	Webhook bool
+	Method  Text
+	// Previous is the operationId prior to the change. It is empty if the
+	// operationId was generated.
+	Previous Text
+	// OperationID is the new operationId.
+	OperationID Text
+}
+
+// EnsureOperationIDs assigns an operationId to each Operation of the
+// Document's Paths and Webhooks which does not have one, generated by
+// strategy from the Operation's method and path. If strategy is nil,
+// OperationIDCamelCase is used.
+//
+// operationIds must be unique. The first Operation with a given operationId
+// retains it while subsequent Operations, along with generated operationIds
+// which would otherwise collide, are suffixed with the lowest number,
+// starting at 2, which makes them unique.
+//
+// The changes made are returned in the order of the Operations.
+func (d *Document) EnsureOperationIDs(strategy OperationIDStrategy) []OperationIDChange {
+	if strategy == nil {
+		strategy = OperationIDCamelCase
+	}
+	ops := d.Operations()
+	used := map[Text]bool{}
+	// the first Operation with each operationId retains it
+	keep := make([]bool, len(ops))
+	for i, op := range ops {
+		if id := op.Operation.OperationID; id != "" && !used[id] {
+			used[id] = true
+			keep[i] = true
+		}
+	}
+	var changes []OperationIDChange
+	for i, op := range ops {
+		if keep[i] {
+			continue
+		}
+		prev := op.Operation.OperationID
+		base := prev
+		if base == "" {
+			base = strategy(op.Method, op.Key)
+		}
+		id := base
+		for n := 2; used[id]; n++ {
+			id = base + Text(strconv.Itoa(n))
+		}
+		used[id] = true
+		op.Operation.OperationID = id
+		changes = append(changes, OperationIDChange{
+			Key:         op.Key,
+			Webhook:     op.Webhook,
+			Method:      op.Method,
+			Previous:    prev,
+			OperationID: id,
+		})
+	}
+	return changes
+}
@@ -0,0 +1,74 @@
+package openapi_test
+
+import (
+	"testing"
+
+	"github.com/chanced/openapi"
+)
+
+func TestEnsureOperationIDs(t *testing.T) {
+	data := []byte(`{
+		"openapi": "3.1.0",
+		"info": { "title": "operation ids", "version": "1.0.0" },
+		"paths": {
+			"/pets": {
+				"get": { "operationId": "listPets", "responses": { "200": { "description": "ok" } } },
+				"post": { "responses": { "201": { "description": "created" } } }
+			},
+			"/pets/{petId}": {
+				"get": { "responses": { "200": { "description": "ok" } } },
+				"delete": { "operationId": "listPets", "responses": { "204": { "description": "deleted" } } }
+			},
+			"/Pets/{petId}": {
+				"get": { "responses": { "200": { "description": "ok" } } }
+			},
+			"/owners/{ownerId}": {
+				"get": { "responses": { "200": { "description": "ok" } } }
+			}
+		},
+		"webhooks": {
+			"newPet": {
+				"post": { "responses": { "200": { "description": "ok" } } }
+			}
+		}
+	}`)
+	doc := loadDocument(t, "https://example.com/openapi.json", data)
+	changes := doc.EnsureOperationIDs(nil)
+	expected := []openapi.OperationIDChange{
+		{Key: "/pets", Method: openapi.MethodPost, OperationID: "postPets"},
+		{Key: "/pets/{petId}", Method: openapi.MethodGet, OperationID: "getPetsByPetID"},
+		{Key: "/pets/{petId}", Method: openapi.MethodDelete, Previous: "listPets", OperationID: "listPets2"},
+		{Key: "/Pets/{petId}", Method: openapi.MethodGet, OperationID: "getPetsByPetID2"},
+		{Key: "/owners/{ownerId}", Method: openapi.MethodGet, OperationID: "getOwnersByOwnerID"},
+		{Key: "newPet", Webhook: true, Method: openapi.MethodPost, OperationID: "postNewPet"},
+	}
+	if len(changes) != len(expected) {
+		t.Fatalf("expected %d changes, got %+v", len(expected), changes)
+	}
+	for i, c := range changes {
+		if c != expected[i] {
+			t.Errorf("expected %+v, got %+v", expected[i], c)
+		}
+	}
+	if id := doc.Paths.Get("/pets/{petId}").Get.OperationID; id != "getPetsByPetID" {
+		t.Errorf("expected operationId to be set, got %q", id)
+	}
+	if changes = doc.EnsureOperationIDs(nil); len(changes) != 0 {
+		t.Errorf("expected no changes, got %+v", changes)
+	}
+
+	strategies := []struct {
+		strategy openapi.OperationIDStrategy
+		expected openapi.Text
+	}{
+		{openapi.OperationIDCamelCase, "getPetsByPetIDToys"},
+		{openapi.OperationIDPascalCase, "GetPetsByPetIDToys"},
+		{openapi.OperationIDSnakeCase, "get_pets_by_pet_id_toys"},
+		{openapi.OperationIDKebabCase, "get-pets-by-pet-id-toys"},
+	}
+	for _, s := range strategies {
+		if id := s.strategy(openapi.MethodGet, "/pets/{petId}/toys"); id != s.expected {
+			t.Errorf("expected %q, got %q", s.expected, id)
+		}
+	}
+}
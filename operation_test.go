@@ -1,5 +1,65 @@
 package openapi_test
 
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/chanced/openapi"
+)
+
+func TestOperationEffectiveParameters(t *testing.T) {
+	var pi openapi.PathItem
+	err := json.Unmarshal([]byte(`{
+		"parameters": [
+			{ "name": "id", "in": "path", "description": "path item", "required": true },
+			{ "name": "limit", "in": "query", "description": "path item" }
+		],
+		"get": {
+			"parameters": [
+				{ "name": "limit", "in": "query", "description": "operation" },
+				{ "name": "id", "in": "header", "description": "operation" }
+			]
+		}
+	}`), &pi)
+	if err != nil {
+		t.Fatal(err)
+	}
+	params, err := pi.Get.EffectiveParameters(&pi)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []struct {
+		name, in, desc string
+	}{
+		{"id", "path", "path item"},
+		{"limit", "query", "operation"},
+		{"id", "header", "operation"},
+	}
+	if len(params) != len(expected) {
+		t.Fatalf("expected %d parameters, got %d", len(expected), len(params))
+	}
+	for i, e := range expected {
+		p := params[i]
+		if p.Name.String() != e.name || p.In.String() != e.in || p.Description.String() != e.desc {
+			t.Errorf("expected parameter %d to be %v, got {%s %s %s}", i, e, p.Name, p.In, p.Description)
+		}
+	}
+
+	var unresolved openapi.PathItem
+	err = json.Unmarshal([]byte(`{
+		"parameters": [{ "$ref": "#/components/parameters/Missing" }],
+		"get": {}
+	}`), &unresolved)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = unresolved.Get.EffectiveParameters(&unresolved)
+	if !errors.Is(err, openapi.ErrUnresolvedReference) {
+		t.Errorf("expected ErrUnresolvedReference, got %v", err)
+	}
+}
+
 // import (
 // 	"encoding/json"
 // 	"fmt"
@@ -13,6 +73,46 @@ package openapi_test
 // 	yaml "sigs.k8s.io/yaml"
 // )
 
+func TestOperationSecurity(t *testing.T) {
+	var o openapi.Operation
+	err := json.Unmarshal([]byte(`{
+		"security": [
+			{ "oauth": ["read:pets"] },
+			{ "apiKey": [], "session": [] }
+		],
+		"responses": { "200": { "description": "ok" } }
+	}`), &o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o.Security == nil || len(o.Security.Items) != 2 {
+		t.Fatalf("expected 2 security requirements, got %+v", o.Security)
+	}
+	if len(o.Security.Items[1].Items) != 2 {
+		t.Errorf("expected the second requirement to have 2 schemes, got %d", len(o.Security.Items[1].Items))
+	}
+	b, err := json.Marshal(&o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var v struct {
+		Security json.RawMessage `json:"security"`
+	}
+	if err = json.Unmarshal(b, &v); err != nil {
+		t.Fatal(err)
+	}
+	if string(v.Security) != `[{"oauth":["read:pets"]},{"apiKey":[],"session":[]}]` {
+		t.Errorf("expected security to round trip, got %s", v.Security)
+	}
+
+	if err = json.Unmarshal([]byte(`{ "security": [] }`), &o); err != nil {
+		t.Fatal(err)
+	}
+	if o.Security == nil || len(o.Security.Items) != 0 {
+		t.Errorf("expected an empty array to remove top-level security, got %+v", o.Security)
+	}
+}
+
 // func TestOperation(t *testing.T) {
 // 	assert := require.New(t)
 
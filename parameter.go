@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 
 	"github.com/chanced/jsonx"
-	"github.com/chanced/transcode"
 	"gopkg.in/yaml.v3"
 )
 
@@ -187,7 +186,10 @@ type Parameter struct {
 	// map. For other types of parameters this property has no effect. When
 	// style is form, the default value is true. For all other styles, the
 	// default value is false.
-	Explode bool `json:"explode,omitempty"`
+	//
+	// See EffectiveStyle for the style and explode of a Parameter with the
+	// defaults applied.
+	Explode *bool `json:"explode,omitempty"`
 
 	// Determines whether the parameter value SHOULD allow reserved characters,
 	// as defined by RFC3986 :/?#[]@!$&'()*+,;= to be included without
@@ -215,6 +217,57 @@ type Parameter struct {
 	Content *ContentMap `json:"content,omitempty"`
 }
 
+// SetExample marshals v and sets it as the example of the Parameter, clearing
+// Examples as the fields are mutually exclusive.
+//
+// If v is a json.RawMessage or jsonx.RawMessage, it is used as is.
+func (p *Parameter) SetExample(v interface{}) error {
+	b, err := encodeExample(v)
+	if err != nil {
+		return err
+	}
+	p.Example = b
+	p.Examples = nil
+	return nil
+}
+
+// SetExamples sets the examples of the Parameter, clearing Example as the fields
+// are mutually exclusive.
+func (p *Parameter) SetExamples(examples *ExampleMap) {
+	p.Examples = examples
+	p.Example = nil
+}
+
+// ValidateExamples returns an error wrapping ErrExampleAndExamples if both
+// Example and Examples are set.
+func (p *Parameter) ValidateExamples() error {
+	if p == nil {
+		return nil
+	}
+	return validateExampleExclusivity(p.Example, p.Examples, KindParameter, p.AbsoluteLocation())
+}
+
+// EffectiveStyle returns the style of p and whether it is exploded, applying
+// the defaults of the specification where they are not set: the style
+// defaults to form for query and cookie parameters and to simple for path and
+// header parameters, and explode defaults to true for the form style and to
+// false for all others.
+func (p *Parameter) EffectiveStyle() (style Text, explode bool) {
+	style = p.Style
+	if style == "" {
+		switch p.In {
+		case InQuery, InCookie:
+			style = StyleForm
+		default:
+			style = StyleSimple
+		}
+	}
+	if p.Explode != nil {
+		return style, *p.Explode
+	}
+	return style, style == StyleForm
+}
+
 func (p *Parameter) Nodes() []Node {
 	if p == nil {
 		return nil
@@ -335,11 +388,7 @@ func (p Parameter) MarshalYAML() (interface{}, error) {
 
 // UnmarshalYAML satisfies gopkg.in/yaml.v3 Unmarshaler interface
 func (p *Parameter) UnmarshalYAML(value *yaml.Node) error {
-	v, err := yaml.Marshal(value)
-	if err != nil {
-		return err
-	}
-	j, err := transcode.JSONFromYAML(v)
+	j, err := jsonFromYAMLNode(value)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,358 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// paramKind is the kind of a parameterValue
+type paramKind uint8
+
+const (
+	paramPrimitive paramKind = iota
+	paramArray
+	paramObject
+)
+
+// parameterValue is a Parameter value which has been normalized for
+// serialization. Primitives are converted to strings; the items of arrays and the property
+// values of objects which are not primitives are encoded as JSON.
+type parameterValue struct {
+	kind  paramKind
+	prim  string
+	items []string
+	props []parameterProp
+}
+
+type parameterProp struct {
+	key   string
+	value string
+}
+
+// pairs returns the keys and values of the properties of v, interleaved
+func (v parameterValue) pairs() []string {
+	res := make([]string, 0, len(v.props)*2)
+	for _, p := range v.props {
+		res = append(res, p.key, p.value)
+	}
+	return res
+}
+
+// toParameterValue normalizes v. Maps with string keys and structs are converted to
+// objects, with properties sorted by key, and slices and arrays are
+// converted to arrays.
+func toParameterValue(v interface{}) (parameterValue, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return parameterValue{}, nil
+		}
+		rv = rv.Elem()
+	}
+	if isParameterPrimitive(rv) {
+		return parameterValue{prim: fmt.Sprint(rv.Interface())}, nil
+	}
+	switch rv.Kind() {
+	case reflect.Invalid:
+		return parameterValue{}, nil
+	case reflect.Slice, reflect.Array:
+		if b, ok := rv.Interface().([]byte); ok {
+			return parameterValue{prim: string(b)}, nil
+		}
+		res := parameterValue{kind: paramArray, items: make([]string, rv.Len())}
+		for i := 0; i < rv.Len(); i++ {
+			s, err := parameterScalar(rv.Index(i).Interface())
+			if err != nil {
+				return parameterValue{}, err
+			}
+			res.items[i] = s
+		}
+		return res, nil
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			break
+		}
+		res := parameterValue{kind: paramObject}
+		iter := rv.MapRange()
+		for iter.Next() {
+			s, err := parameterScalar(iter.Value().Interface())
+			if err != nil {
+				return parameterValue{}, err
+			}
+			res.props = append(res.props, parameterProp{key: iter.Key().String(), value: s})
+		}
+		sort.Slice(res.props, func(i, j int) bool { return res.props[i].key < res.props[j].key })
+		return res, nil
+	}
+	// everything else (e.g. structs) is converted by way of JSON
+	x, err := parameterJSON(v)
+	if err != nil {
+		return parameterValue{}, err
+	}
+	switch x.(type) {
+	case map[string]interface{}, []interface{}, string, bool, json.Number, nil:
+		return toParameterValue(x)
+	default:
+		return parameterValue{}, fmt.Errorf("openapi: cannot serialize %T", v)
+	}
+}
+
+func isParameterPrimitive(rv reflect.Value) bool {
+	switch rv.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// parameterScalar returns v as a string if it is a primitive or, otherwise,
+// as JSON
+func parameterScalar(v interface{}) (string, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return "", nil
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return "", nil
+	}
+	if isParameterPrimitive(rv) {
+		return fmt.Sprint(rv.Interface()), nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// parameterJSON returns v encoded and decoded as JSON
+func parameterJSON(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var x interface{}
+	if err = dec.Decode(&x); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// Serialize serializes val according to the style and explode of p (see
+// EffectiveStyle) or, if p has content rather than a schema, as the media
+// type of its content.
+//
+// Path parameters are returned as they are to be substituted into the path,
+// query parameters as name=value pairs joined by "&", cookie parameters as
+// name=value pairs joined by "; ", and header parameters as the value of the
+// header. Values are percent-encoded as appropriate for their location.
+//
+// val may be a primitive, a slice or array, a map with string keys, or a
+// value which encodes to one of those as JSON, such as a struct.
+func (p *Parameter) Serialize(val interface{}) (string, error) {
+	name := p.Name.String()
+	var esc func(string) string
+	switch p.In {
+	case InPath:
+		esc = url.PathEscape
+	case InQuery:
+		esc = url.QueryEscape
+		if p.AllowReserved {
+			esc = escapeAllowReserved
+		}
+	default:
+		esc = func(s string) string { return s }
+	}
+
+	if p.Schema == nil && p.Content != nil && len(p.Content.Items) > 0 {
+		// the value is encoded as the media type of the Parameter's content
+		var s string
+		var err error
+		if isJSONMediaType(p.Content.Items[0].Key.String()) {
+			var data []byte
+			data, err = json.Marshal(val)
+			s = string(data)
+		} else {
+			s, err = parameterScalar(val)
+		}
+		if err != nil {
+			return "", err
+		}
+		if p.In == InPath || p.In == InHeader {
+			return esc(s), nil
+		}
+		return esc(name) + "=" + esc(s), nil
+	}
+
+	v, err := toParameterValue(val)
+	if err != nil {
+		return "", err
+	}
+	style, explode := p.EffectiveStyle()
+	join := func(vals []string, sep string) string {
+		res := make([]string, len(vals))
+		for i, s := range vals {
+			res[i] = esc(s)
+		}
+		return strings.Join(res, sep)
+	}
+	assign := func(props []parameterProp, sep string) string {
+		res := make([]string, len(props))
+		for i, p := range props {
+			res[i] = esc(p.key) + "=" + esc(p.value)
+		}
+		return strings.Join(res, sep)
+	}
+
+	switch style {
+	case StyleSimple:
+		switch v.kind {
+		case paramArray:
+			return join(v.items, ","), nil
+		case paramObject:
+			if explode {
+				return assign(v.props, ","), nil
+			}
+			return join(v.pairs(), ","), nil
+		default:
+			return esc(v.prim), nil
+		}
+
+	case StyleLabel:
+		switch v.kind {
+		case paramArray:
+			if explode {
+				return "." + join(v.items, "."), nil
+			}
+			return "." + join(v.items, ","), nil
+		case paramObject:
+			if explode {
+				return "." + assign(v.props, "."), nil
+			}
+			return "." + join(v.pairs(), ","), nil
+		default:
+			return "." + esc(v.prim), nil
+		}
+
+	case StyleMatrix:
+		prefix := ";" + esc(name)
+		switch v.kind {
+		case paramArray:
+			if explode {
+				res := make([]string, len(v.items))
+				for i, item := range v.items {
+					res[i] = matrixPair(prefix, esc(item))
+				}
+				return strings.Join(res, ""), nil
+			}
+			return matrixPair(prefix, join(v.items, ",")), nil
+		case paramObject:
+			if explode {
+				return ";" + assign(v.props, ";"), nil
+			}
+			return matrixPair(prefix, join(v.pairs(), ",")), nil
+		default:
+			return matrixPair(prefix, esc(v.prim)), nil
+		}
+
+	case StyleForm:
+		sep := "&"
+		if p.In == InCookie {
+			sep = "; "
+		}
+		switch v.kind {
+		case paramArray:
+			if explode {
+				res := make([]string, len(v.items))
+				for i, item := range v.items {
+					res[i] = esc(name) + "=" + esc(item)
+				}
+				return strings.Join(res, sep), nil
+			}
+			return esc(name) + "=" + join(v.items, ","), nil
+		case paramObject:
+			if explode {
+				return assign(v.props, sep), nil
+			}
+			return esc(name) + "=" + join(v.pairs(), ","), nil
+		default:
+			return esc(name) + "=" + esc(v.prim), nil
+		}
+
+	case StyleSpaceDelimited, StylePipeDelimited:
+		if p.In != InQuery {
+			return "", fmt.Errorf("openapi: style %q is not supported for %s parameters", style, p.In)
+		}
+		sep := "%20"
+		if style == StylePipeDelimited {
+			sep = "|"
+		}
+		switch v.kind {
+		case paramArray:
+			if explode {
+				res := make([]string, len(v.items))
+				for i, item := range v.items {
+					res[i] = esc(name) + "=" + esc(item)
+				}
+				return strings.Join(res, "&"), nil
+			}
+			return esc(name) + "=" + join(v.items, sep), nil
+		case paramObject:
+			return esc(name) + "=" + join(v.pairs(), sep), nil
+		default:
+			return esc(name) + "=" + esc(v.prim), nil
+		}
+
+	case StyleDeepObject:
+		if p.In != InQuery {
+			return "", fmt.Errorf("openapi: style %q is not supported for %s parameters", style, p.In)
+		}
+		if v.kind != paramObject {
+			return "", fmt.Errorf("openapi: style %q requires an object value", style)
+		}
+		res := make([]string, len(v.props))
+		for i, pr := range v.props {
+			res[i] = esc(name) + "[" + esc(pr.key) + "]=" + esc(pr.value)
+		}
+		return strings.Join(res, "&"), nil
+
+	default:
+		return "", fmt.Errorf("openapi: unsupported style %q", style)
+	}
+}
+
+// matrixPair returns ";name=value" or, if value is empty, ";name"
+func matrixPair(prefix, value string) string {
+	if value == "" {
+		return prefix
+	}
+	return prefix + "=" + value
+}
+
+// escapeAllowReserved percent-encodes s, leaving the reserved characters of
+// RFC 3986 as is
+func escapeAllowReserved(s string) string {
+	const reserved = ":/?#[]@!$&'()*+,;="
+	var b strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune(reserved, r) {
+			b.WriteRune(r)
+			continue
+		}
+		b.WriteString(url.QueryEscape(string(r)))
+	}
+	return b.String()
+}
@@ -1,5 +1,105 @@
 package openapi_test
 
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"testing"
+
+	"github.com/chanced/openapi"
+)
+
+func TestParameterEffectiveStyle(t *testing.T) {
+	tests := []struct {
+		param   string
+		style   openapi.Text
+		explode bool
+	}{
+		{`{"name":"a","in":"query"}`, openapi.StyleForm, true},
+		{`{"name":"a","in":"cookie"}`, openapi.StyleForm, true},
+		{`{"name":"a","in":"path"}`, openapi.StyleSimple, false},
+		{`{"name":"a","in":"header"}`, openapi.StyleSimple, false},
+		{`{"name":"a","in":"query","style":"form"}`, openapi.StyleForm, true},
+		{`{"name":"a","in":"query","style":"form","explode":false}`, openapi.StyleForm, false},
+		{`{"name":"a","in":"query","explode":false}`, openapi.StyleForm, false},
+		{`{"name":"a","in":"query","style":"deepObject"}`, openapi.StyleDeepObject, false},
+		{`{"name":"a","in":"query","style":"pipeDelimited","explode":true}`, openapi.StylePipeDelimited, true},
+		{`{"name":"a","in":"path","style":"matrix"}`, openapi.StyleMatrix, false},
+		{`{"name":"a","in":"path","explode":true}`, openapi.StyleSimple, true},
+	}
+	for _, test := range tests {
+		var p openapi.Parameter
+		if err := json.Unmarshal([]byte(test.param), &p); err != nil {
+			t.Fatal(err)
+		}
+		style, explode := p.EffectiveStyle()
+		if style != test.style || explode != test.explode {
+			t.Errorf("%s: expected %s, %t; got %s, %t", test.param, test.style, test.explode, style, explode)
+		}
+		data, err := json.Marshal(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bytes.Contains(data, []byte(`"explode"`)) != bytes.Contains([]byte(test.param), []byte(`"explode"`)) {
+			t.Errorf("expected explode to round-trip:\n\t%s\ngot\n\t%s", test.param, data)
+		}
+	}
+}
+
+func TestParameterSerialize(t *testing.T) {
+	arr := []string{"blue", "black"}
+	obj := map[string]interface{}{"a": 1, "b": "x y"}
+	tests := []struct {
+		param    string
+		value    interface{}
+		expected string
+	}{
+		{`{"name":"id","in":"path"}`, 5, "5"},
+		{`{"name":"id","in":"path"}`, arr, "blue,black"},
+		{`{"name":"id","in":"path"}`, obj, "a,1,b,x%20y"},
+		{`{"name":"id","in":"path","explode":true}`, obj, "a=1,b=x%20y"},
+		{`{"name":"id","in":"path","style":"label"}`, arr, ".blue,black"},
+		{`{"name":"id","in":"path","style":"label","explode":true}`, arr, ".blue.black"},
+		{`{"name":"id","in":"path","style":"matrix"}`, arr, ";id=blue,black"},
+		{`{"name":"id","in":"path","style":"matrix","explode":true}`, arr, ";id=blue;id=black"},
+		{`{"name":"id","in":"path","style":"matrix","explode":true}`, obj, ";a=1;b=x%20y"},
+		{`{"name":"id","in":"query"}`, arr, "id=blue&id=black"},
+		{`{"name":"id","in":"query","style":"form"}`, arr, "id=blue&id=black"},
+		{`{"name":"id","in":"query","style":"form","explode":false}`, arr, "id=blue,black"},
+		{`{"name":"id","in":"query"}`, obj, "a=1&b=x+y"},
+		{`{"name":"id","in":"query","explode":false}`, obj, "id=a,1,b,x+y"},
+		{`{"name":"id","in":"query","style":"spaceDelimited"}`, arr, "id=blue%20black"},
+		{`{"name":"id","in":"query","style":"pipeDelimited"}`, arr, "id=blue|black"},
+		{`{"name":"id","in":"query","style":"deepObject","explode":true}`, obj, "id[a]=1&id[b]=x+y"},
+		{`{"name":"id","in":"query","allowReserved":true}`, "a/b?c", "id=a/b?c"},
+		{`{"name":"id","in":"query","content":{"application/json":{}}}`, obj, "id=" + url.QueryEscape(`{"a":1,"b":"x y"}`)},
+		{`{"name":"X-Id","in":"header"}`, arr, "blue,black"},
+		{`{"name":"id","in":"cookie"}`, arr, "id=blue; id=black"},
+	}
+	for _, test := range tests {
+		var p openapi.Parameter
+		if err := json.Unmarshal([]byte(test.param), &p); err != nil {
+			t.Fatal(err)
+		}
+		s, err := p.Serialize(test.value)
+		if err != nil {
+			t.Errorf("%s: %v", test.param, err)
+			continue
+		}
+		if s != test.expected {
+			t.Errorf("%s: expected %q, got %q", test.param, test.expected, s)
+		}
+	}
+
+	var p openapi.Parameter
+	if err := json.Unmarshal([]byte(`{"name":"id","in":"path","style":"deepObject"}`), &p); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.Serialize(obj); err == nil {
+		t.Error("expected an error for a deepObject path parameter")
+	}
+}
+
 // import (
 // 	"encoding/json"
 // 	"fmt"
@@ -0,0 +1,609 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PatchOpts configures Document.ApplyJSONPatch and Document.ApplyMergePatch.
+type PatchOpts struct {
+	// Validator, if set, is used to validate the patched Document with
+	// ValidateDocument. If Validator is an IncrementalValidator, the nodes
+	// affected by the patch are invalidated.
+	Validator Validator
+}
+
+func mergePatchOpts(opts []PatchOpts) PatchOpts {
+	var res PatchOpts
+	for _, o := range opts {
+		if o.Validator != nil {
+			res.Validator = o.Validator
+		}
+	}
+	return res
+}
+
+// ApplyJSONPatch applies the RFC 6902 JSON Patch patch to the Document.
+//
+// The Location of each node is retained. References are re-resolved: local
+// references against the patched Document and all others to their
+// previously resolved targets, provided the reference is unchanged.
+//
+// The Document is only modified if the entire patch applies successfully
+// and, if a Validator is provided, the patched Document is valid. Nodes
+// obtained from the Document prior to patching are not updated.
+func (d *Document) ApplyJSONPatch(patch []byte, opts ...PatchOpts) error {
+	var ops []patchOperation
+	if err := json.Unmarshal(patch, &ops); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+	v, err := d.decodeGeneric()
+	if err != nil {
+		return err
+	}
+	var changed []string
+	for i, op := range ops {
+		if v, err = op.apply(v); err != nil {
+			return fmt.Errorf("openapi: failed to apply operation %d (%s %q): %w", i, op.Op, op.Path, err)
+		}
+		changed = append(changed, op.Path)
+		if op.Op == "move" {
+			changed = append(changed, op.From)
+		}
+	}
+	return d.replaceWith(v, changed, mergePatchOpts(opts))
+}
+
+// ApplyMergePatch applies the RFC 7386 JSON Merge Patch patch to the
+// Document.
+//
+// See ApplyJSONPatch for details on how the Document is updated.
+func (d *Document) ApplyMergePatch(patch []byte, opts ...PatchOpts) error {
+	p, err := decodeGeneric(patch)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+	v, err := d.decodeGeneric()
+	if err != nil {
+		return err
+	}
+	return d.replaceWith(mergePatch(v, p), mergePatchPointers(nil, "", p), mergePatchOpts(opts))
+}
+
+// decodeGeneric marshals the Document and decodes it into
+// map[string]interface{}
+func (d *Document) decodeGeneric() (interface{}, error) {
+	data, err := d.MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("openapi: failed to marshal document: %w", err)
+	}
+	return decodeGeneric(data)
+}
+
+// replaceWith replaces d with the Document encoded in v, restoring the
+// Location of nodes and resolving references. changed are the JSON pointers
+// of the nodes which were modified.
+func (d *Document) replaceWith(v interface{}, changed []string, opts PatchOpts) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("openapi: failed to marshal patched document: %w", err)
+	}
+	var nd Document
+	if err = json.Unmarshal(data, &nd); err != nil {
+		return NewError(fmt.Errorf("openapi: failed to unmarshal patched document: %w", err), d.AbsoluteLocation())
+	}
+	if err = nd.setLocation(d.Location); err != nil {
+		return NewError(err, d.AbsoluteLocation())
+	}
+	if err = nd.resolveRefsFrom(d); err != nil {
+		return err
+	}
+	nd.revision = d.revision + 1
+	if opts.Validator != nil {
+		invalidate := func() {}
+		if iv, ok := opts.Validator.(*IncrementalValidator); ok {
+			invalidate = func() {
+				for _, p := range changed {
+					loc := d.AbsoluteLocation()
+					loc.Fragment, loc.RawFragment = p, ""
+					iv.Invalidate(loc)
+				}
+			}
+		}
+		invalidate()
+		if err = opts.Validator.ValidateDocument(&nd); err != nil {
+			// d is retained so the results for the patched nodes no longer
+			// apply
+			invalidate()
+			return err
+		}
+	}
+	*d = nd
+	return nil
+}
+
+// mergePatchPointers appends the JSON pointers of the members of the JSON
+// Merge Patch p, located at path, to ptrs.
+func mergePatchPointers(ptrs []string, path string, p interface{}) []string {
+	m, ok := p.(map[string]interface{})
+	if !ok || len(m) == 0 {
+		return append(ptrs, path)
+	}
+	for k, v := range m {
+		ptrs = mergePatchPointers(ptrs, path+"/"+escapePatchToken(k), v)
+	}
+	return ptrs
+}
+
+// resolveRefsFrom resolves the references of d. Local references are
+// resolved against d while all others are resolved to the target of the
+// reference at the same location in prev, if the URI is unchanged.
+func (d *Document) resolveRefsFrom(prev *Document) error {
+	previous := map[string]Ref{}
+	for _, r := range prev.Refs() {
+		previous[r.AbsoluteLocation().String()] = r
+	}
+	local := map[string]node{}
+	_ = walkLocalNodes(d, func(n node) error {
+		local[n.AbsoluteLocation().String()] = n
+		return nil
+	})
+	for _, r := range d.Refs() {
+		rr, ok := r.(ref)
+		if !ok || r.URI() == nil {
+			continue
+		}
+		u := r.URI()
+		if u.Host == "" && u.Path == "" && strings.HasPrefix(u.Fragment, "/") {
+			target := r.AbsoluteLocation()
+			target.Fragment = u.Fragment
+			target.RawFragment = u.RawFragment
+			if n, ok := local[target.String()]; ok && n.Kind() == r.RefKind() {
+				if err := rr.resolve(n); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		p, ok := previous[r.AbsoluteLocation().String()]
+		if !ok || p.URI() == nil || p.URI().String() != u.String() {
+			continue
+		}
+		if n, ok := resolvedNode(p); ok {
+			if err := rr.resolve(n); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// resolvedNode returns the resolved node of r, if r has been resolved
+func resolvedNode(r Ref) (node, bool) {
+	rn := r.ResolvedNode()
+	if rn == nil {
+		return nil, false
+	}
+	n, ok := rn.(node)
+	if !ok || n.isNil() {
+		return nil, false
+	}
+	return n, true
+}
+
+// EditRecorder records edits made to a Document, whether through setters
+// (e.g. SetExtension) or by assigning fields directly, so that they can be
+// exported as an RFC 6902 JSON Patch relative to the Document at the time
+// recording began.
+type EditRecorder struct {
+	doc      *Document
+	original interface{}
+}
+
+// RecordEdits begins recording edits to the Document. The returned
+// EditRecorder's Patch method produces a JSON Patch which transforms the
+// Document, as it is now, into its state at the time Patch is called.
+func (d *Document) RecordEdits() (*EditRecorder, error) {
+	er := &EditRecorder{doc: d}
+	if err := er.Reset(); err != nil {
+		return nil, err
+	}
+	return er, nil
+}
+
+// Reset discards recorded edits, using the current state of the Document
+// as the new baseline.
+func (er *EditRecorder) Reset() error {
+	v, err := er.doc.decodeGeneric()
+	if err != nil {
+		return err
+	}
+	er.original = v
+	return nil
+}
+
+// Patch returns an RFC 6902 JSON Patch of the edits made to the Document
+// since recording began or Reset was last called.
+func (er *EditRecorder) Patch() ([]byte, error) {
+	v, err := er.doc.decodeGeneric()
+	if err != nil {
+		return nil, err
+	}
+	return encodePatch(er.original, v)
+}
+
+// Diff returns an RFC 6902 JSON Patch which transforms the Document from
+// into the Document to.
+func Diff(from, to *Document) ([]byte, error) {
+	a, err := from.decodeGeneric()
+	if err != nil {
+		return nil, err
+	}
+	b, err := to.decodeGeneric()
+	if err != nil {
+		return nil, err
+	}
+	return encodePatch(a, b)
+}
+
+// encodePatch returns the JSON Patch which transforms a into b
+func encodePatch(a, b interface{}) ([]byte, error) {
+	ops, err := diffGeneric(nil, "", a, b)
+	if err != nil {
+		return nil, err
+	}
+	if ops == nil {
+		ops = []patchOperation{}
+	}
+	return json.Marshal(ops)
+}
+
+type patchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// diffGeneric appends the operations required to transform a into b, both
+// located at path, to ops.
+func diffGeneric(ops []patchOperation, path string, a, b interface{}) ([]patchOperation, error) {
+	switch at := a.(type) {
+	case map[string]interface{}:
+		bt, ok := b.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(at)+len(bt))
+		for k := range at {
+			keys = append(keys, k)
+		}
+		for k := range bt {
+			if _, ok := at[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		var err error
+		for _, k := range keys {
+			p := path + "/" + escapePatchToken(k)
+			av, inA := at[k]
+			bv, inB := bt[k]
+			switch {
+			case !inB:
+				ops = append(ops, patchOperation{Op: "remove", Path: p})
+			case !inA:
+				if ops, err = appendPatchValue(ops, "add", p, bv); err != nil {
+					return nil, err
+				}
+			default:
+				if ops, err = diffGeneric(ops, p, av, bv); err != nil {
+					return nil, err
+				}
+			}
+		}
+		return ops, nil
+	case []interface{}:
+		bt, ok := b.([]interface{})
+		if !ok {
+			break
+		}
+		n := len(at)
+		if len(bt) < n {
+			n = len(bt)
+		}
+		var err error
+		for i := 0; i < n; i++ {
+			if ops, err = diffGeneric(ops, path+"/"+strconv.Itoa(i), at[i], bt[i]); err != nil {
+				return nil, err
+			}
+		}
+		for i := len(at) - 1; i >= n; i-- {
+			ops = append(ops, patchOperation{Op: "remove", Path: path + "/" + strconv.Itoa(i)})
+		}
+		for i := n; i < len(bt); i++ {
+			if ops, err = appendPatchValue(ops, "add", path+"/-", bt[i]); err != nil {
+				return nil, err
+			}
+		}
+		return ops, nil
+	}
+	if reflect.DeepEqual(a, b) {
+		return ops, nil
+	}
+	return appendPatchValue(ops, "replace", path, b)
+}
+
+func appendPatchValue(ops []patchOperation, op string, path string, v interface{}) ([]patchOperation, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return append(ops, patchOperation{Op: op, Path: path, Value: data}), nil
+}
+
+func escapePatchToken(tok string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(tok, "~", "~0"), "/", "~1")
+}
+
+func (op patchOperation) value() (interface{}, error) {
+	if op.Value == nil {
+		return nil, fmt.Errorf("%w: value is required", ErrInvalidPatch)
+	}
+	return decodeGeneric(op.Value)
+}
+
+func (op patchOperation) apply(doc interface{}) (interface{}, error) {
+	path, err := parsePatchPointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+	switch op.Op {
+	case "add":
+		v, err := op.value()
+		if err != nil {
+			return nil, err
+		}
+		return patchAdd(doc, path, v)
+	case "remove":
+		return patchRemove(doc, path)
+	case "replace":
+		v, err := op.value()
+		if err != nil {
+			return nil, err
+		}
+		if len(path) == 0 {
+			return v, nil
+		}
+		if doc, err = patchRemove(doc, path); err != nil {
+			return nil, err
+		}
+		return patchAdd(doc, path, v)
+	case "move", "copy":
+		from, err := parsePatchPointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		v, err := patchGet(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		if op.Op == "move" {
+			if isProperPrefix(from, path) {
+				return nil, fmt.Errorf("%w: cannot move %q into one of its children", ErrInvalidPatch, op.From)
+			}
+			if doc, err = patchRemove(doc, from); err != nil {
+				return nil, err
+			}
+		} else if v, err = deepCopyGeneric(v); err != nil {
+			return nil, err
+		}
+		return patchAdd(doc, path, v)
+	case "test":
+		expected, err := op.value()
+		if err != nil {
+			return nil, err
+		}
+		actual, err := patchGet(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		if !reflect.DeepEqual(expected, actual) {
+			return nil, ErrPatchTestFailed
+		}
+		return doc, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown op %q", ErrInvalidPatch, op.Op)
+	}
+}
+
+func parsePatchPointer(p string) ([]string, error) {
+	if p == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(p, "/") {
+		return nil, fmt.Errorf("%w: malformed pointer %q", ErrInvalidPatch, p)
+	}
+	toks := strings.Split(p[1:], "/")
+	for i, tok := range toks {
+		toks[i] = strings.ReplaceAll(strings.ReplaceAll(tok, "~1", "/"), "~0", "~")
+	}
+	return toks, nil
+}
+
+func isProperPrefix(prefix, path []string) bool {
+	if len(prefix) >= len(path) {
+		return false
+	}
+	for i := range prefix {
+		if prefix[i] != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func patchIndex(tok string, length int, allowEnd bool) (int, error) {
+	if allowEnd && tok == "-" {
+		return length, nil
+	}
+	i, err := strconv.Atoi(tok)
+	if err != nil || i < 0 || (len(tok) > 1 && tok[0] == '0') {
+		return 0, fmt.Errorf("%w: invalid array index %q", ErrInvalidPatch, tok)
+	}
+	last := length - 1
+	if allowEnd {
+		last = length
+	}
+	if i > last {
+		return 0, fmt.Errorf("%w: array index %d out of bounds", ErrInvalidPatch, i)
+	}
+	return i, nil
+}
+
+func patchGet(doc interface{}, path []string) (interface{}, error) {
+	v := doc
+	for _, tok := range path {
+		switch t := v.(type) {
+		case map[string]interface{}:
+			c, ok := t[tok]
+			if !ok {
+				return nil, fmt.Errorf("%w: %q not found", ErrInvalidPatch, tok)
+			}
+			v = c
+		case []interface{}:
+			i, err := patchIndex(tok, len(t), false)
+			if err != nil {
+				return nil, err
+			}
+			v = t[i]
+		default:
+			return nil, fmt.Errorf("%w: %q not found", ErrInvalidPatch, tok)
+		}
+	}
+	return v, nil
+}
+
+// patchParent invokes fn with the parent container of path and the final
+// token, replacing the parent with the value returned from fn.
+func patchParent(doc interface{}, path []string, fn func(parent interface{}, tok string) (interface{}, error)) (interface{}, error) {
+	if len(path) == 1 {
+		return fn(doc, path[0])
+	}
+	switch t := doc.(type) {
+	case map[string]interface{}:
+		c, ok := t[path[0]]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q not found", ErrInvalidPatch, path[0])
+		}
+		nc, err := patchParent(c, path[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		t[path[0]] = nc
+		return t, nil
+	case []interface{}:
+		i, err := patchIndex(path[0], len(t), false)
+		if err != nil {
+			return nil, err
+		}
+		nc, err := patchParent(t[i], path[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		t[i] = nc
+		return t, nil
+	default:
+		return nil, fmt.Errorf("%w: %q not found", ErrInvalidPatch, path[0])
+	}
+}
+
+func patchAdd(doc interface{}, path []string, v interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return v, nil
+	}
+	return patchParent(doc, path, func(parent interface{}, tok string) (interface{}, error) {
+		switch t := parent.(type) {
+		case map[string]interface{}:
+			t[tok] = v
+			return t, nil
+		case []interface{}:
+			i, err := patchIndex(tok, len(t), true)
+			if err != nil {
+				return nil, err
+			}
+			t = append(t, nil)
+			copy(t[i+1:], t[i:])
+			t[i] = v
+			return t, nil
+		default:
+			return nil, fmt.Errorf("%w: cannot add %q to a scalar", ErrInvalidPatch, tok)
+		}
+	})
+}
+
+func patchRemove(doc interface{}, path []string) (interface{}, error) {
+	if len(path) == 0 {
+		return nil, fmt.Errorf("%w: cannot remove the document", ErrInvalidPatch)
+	}
+	return patchParent(doc, path, func(parent interface{}, tok string) (interface{}, error) {
+		switch t := parent.(type) {
+		case map[string]interface{}:
+			if _, ok := t[tok]; !ok {
+				return nil, fmt.Errorf("%w: %q not found", ErrInvalidPatch, tok)
+			}
+			delete(t, tok)
+			return t, nil
+		case []interface{}:
+			i, err := patchIndex(tok, len(t), false)
+			if err != nil {
+				return nil, err
+			}
+			return append(t[:i], t[i+1:]...), nil
+		default:
+			return nil, fmt.Errorf("%w: %q not found", ErrInvalidPatch, tok)
+		}
+	})
+}
+
+func mergePatch(target, patch interface{}) interface{} {
+	p, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	t, ok := target.(map[string]interface{})
+	if !ok {
+		t = map[string]interface{}{}
+	}
+	for k, v := range p {
+		if v == nil {
+			delete(t, k)
+		} else {
+			t[k] = mergePatch(t[k], v)
+		}
+	}
+	return t
+}
+
+func decodeGeneric(data []byte) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func deepCopyGeneric(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return decodeGeneric(data)
+}
@@ -0,0 +1,140 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/chanced/openapi"
+)
+
+func TestApplyPatch(t *testing.T) {
+	data := []byte(`{
+		"openapi": "3.1.0",
+		"info": { "title": "patch", "version": "1.0.0" },
+		"paths": {
+			"/pets": {
+				"get": {
+					"responses": {
+						"200": {
+							"description": "ok",
+							"content": {
+								"application/json": { "schema": { "$ref": "#/components/schemas/Pet" } }
+							}
+						}
+					}
+				}
+			}
+		},
+		"components": {
+			"schemas": {
+				"Pet": { "type": "object" }
+			}
+		}
+	}`)
+	doc := loadDocument(t, "https://example.com/patch.json", data)
+
+	err := doc.ApplyJSONPatch([]byte(`[
+		{ "op": "test", "path": "/info/title", "value": "patch" },
+		{ "op": "replace", "path": "/info/title", "value": "patched" },
+		{ "op": "add", "path": "/components/schemas/Pet/required", "value": ["name"] }
+	]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Info.Title != "patched" {
+		t.Errorf("expected title to be \"patched\", got %q", doc.Info.Title)
+	}
+	pet := doc.Components.Schemas.Get("Pet")
+	if len(pet.Required) != 1 {
+		t.Errorf("expected Pet to have 1 required property, got %v", pet.Required)
+	}
+	if loc := pet.AbsoluteLocation(); loc.String() != "https://example.com/patch.json#/components/schemas/Pet" {
+		t.Errorf("expected location to be retained, got %s", loc.String())
+	}
+	ref := doc.Paths.Get("/pets").Get.Responses.Get("200").Object.Content.Get("application/json").Schema.Ref
+	if ref.Resolved != pet {
+		t.Error("expected $ref to be resolved to the patched schema")
+	}
+
+	err = doc.ApplyJSONPatch([]byte(`[
+		{ "op": "replace", "path": "/info/title", "value": "unapplied" },
+		{ "op": "test", "path": "/info/version", "value": "2.0.0" }
+	]`))
+	if !errors.Is(err, openapi.ErrPatchTestFailed) {
+		t.Errorf("expected ErrPatchTestFailed, got %v", err)
+	}
+	if doc.Info.Title != "patched" {
+		t.Error("expected document to be unmodified after a failed patch")
+	}
+
+	if err = doc.ApplyMergePatch([]byte(`{ "info": { "description": "merged" }, "paths": null }`)); err != nil {
+		t.Fatal(err)
+	}
+	if doc.Info.Description != "merged" || doc.Info.Title != "patched" {
+		t.Errorf("unexpected info after merge patch: %+v", doc.Info)
+	}
+	if doc.Paths != nil {
+		t.Error("expected paths to be removed")
+	}
+}
+
+func TestRecordEdits(t *testing.T) {
+	data := []byte(`{
+		"openapi": "3.1.0",
+		"info": { "title": "edits", "version": "1.0.0" },
+		"tags": [{ "name": "a" }, { "name": "b" }],
+		"components": {
+			"schemas": {
+				"Pet": { "type": "object" },
+				"a/b": { "type": "string" }
+			}
+		}
+	}`)
+	var original, doc openapi.Document
+	if err := json.Unmarshal(data, &original); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	er, err := doc.RecordEdits()
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc.Info.Title = "edited"
+	if err = openapi.SetExtension(doc.Info, "x-owner", "pets"); err != nil {
+		t.Fatal(err)
+	}
+	doc.Tags.Items = doc.Tags.Items[:1]
+	doc.Components.Schemas.Get("a/b").Format = "uuid"
+
+	patch, err := er.Patch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = original.ApplyJSONPatch(patch); err != nil {
+		t.Fatalf("failed to apply patch %s: %v", patch, err)
+	}
+	expected, err := doc.Checksum()
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, err := original.Checksum()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected != actual {
+		t.Errorf("expected patched document to match edited document; patch: %s", patch)
+	}
+
+	if err = er.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	if patch, err = er.Patch(); err != nil {
+		t.Fatal(err)
+	}
+	if string(patch) != "[]" {
+		t.Errorf("expected an empty patch after Reset, got %s", patch)
+	}
+}
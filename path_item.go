@@ -3,7 +3,6 @@ package openapi
 import (
 	"encoding/json"
 
-	"github.com/chanced/transcode"
 	"gopkg.in/yaml.v3"
 )
 
@@ -58,6 +57,36 @@ type PathItem struct {
 	Servers *ServerSlice `json:"servers,omitempty"`
 }
 
+// MethodOperation is an Operation and the HTTP method it is defined for.
+type MethodOperation struct {
+	Method    Text
+	Operation *Operation
+}
+
+// Operations returns the Operations of the PathItem in the order in which
+// they are defined by the specification.
+func (pi *PathItem) Operations() []MethodOperation {
+	if pi == nil {
+		return nil
+	}
+	var ops []MethodOperation
+	for _, mo := range []MethodOperation{
+		{MethodGet, pi.Get},
+		{MethodPut, pi.Put},
+		{MethodPost, pi.Post},
+		{MethodDelete, pi.Delete},
+		{MethodOptions, pi.Options},
+		{MethodHead, pi.Head},
+		{MethodPatch, pi.Patch},
+		{MethodTrace, pi.Trace},
+	} {
+		if mo.Operation != nil {
+			ops = append(ops, mo)
+		}
+	}
+	return ops
+}
+
 func (pi *PathItem) Nodes() []Node {
 	if pi == nil {
 		return nil
@@ -219,11 +248,7 @@ func (p PathItem) MarshalYAML() (interface{}, error) {
 
 // UnmarshalYAML satisfies gopkg.in/yaml.v3 Unmarshaler interface
 func (p *PathItem) UnmarshalYAML(value *yaml.Node) error {
-	v, err := yaml.Marshal(value)
-	if err != nil {
-		return err
-	}
-	j, err := transcode.JSONFromYAML(v)
+	j, err := jsonFromYAMLNode(value)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,238 @@
+package openapi
+
+import (
+	"regexp"
+	"strings"
+)
+
+// PathParameterAction is the action taken, or issue reported, by
+// Document.FixPathParameters.
+type PathParameterAction uint8
+
+const (
+	// PathParameterAdded indicates that a template variable of a path was not
+	// declared as a path Parameter. A required Parameter with a string Schema
+	// was added to the PathItem.
+	PathParameterAdded PathParameterAction = iota + 1
+	// PathParameterRenamed indicates that a template variable of a path was
+	// renamed to match a declared path Parameter.
+	PathParameterRenamed
+	// PathParameterRequired indicates that a path Parameter was not marked
+	// as required and has been.
+	PathParameterRequired
+	// PathParameterUnused indicates that a path Parameter is declared but is
+	// not a variable of the path's template. The Parameter is not modified.
+	PathParameterUnused
+)
+
+var pathParameterActionNames = [...]string{
+	PathParameterAdded:    "added",
+	PathParameterRenamed:  "renamed",
+	PathParameterRequired: "required",
+	PathParameterUnused:   "unused",
+}
+
+func (a PathParameterAction) String() string {
+	if int(a) < len(pathParameterActionNames) && pathParameterActionNames[a] != "" {
+		return pathParameterActionNames[a]
+	}
+	return "unknown"
+}
+
+// PathParameterFix is a change made, or an issue reported, by
+// Document.FixPathParameters.
+type PathParameterFix struct {
+	Action PathParameterAction
+	// Key is the path (e.g. "/pets/{petId}"). If the template was renamed,
+	// Key is the new path.
+	Key Text
+	// Method is the method of the Operation which declares the Parameter. It
+	// is empty if the Parameter is declared by the PathItem.
+	Method Text
+	// Name is the name of the Parameter or template variable.
+	Name Text
+	// Previous is the previous name of a renamed template variable.
+	Previous Text
+}
+
+// PathParameterOpts configures Document.FixPathParameters.
+type PathParameterOpts struct {
+	// Rename indicates that template variables which are not declared should
+	// be renamed to match declared path Parameters which are not in the
+	// template, rather than declaring new Parameters. Variables are paired
+	// with Parameters in order and only if there are an equal number of
+	// each.
+	Rename bool
+}
+
+var pathTemplateVariable = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// PathTemplateVariables returns the names of the template variables of path
+// (e.g. "petId" for "/pets/{petId}"), in order.
+func PathTemplateVariables(path Text) []Text {
+	var vars []Text
+	for _, m := range pathTemplateVariable.FindAllStringSubmatch(path.String(), -1) {
+		vars = append(vars, Text(m[1]))
+	}
+	return vars
+}
+
+// FixPathParameters synchronizes the template of each path of the Document
+// with the path Parameters declared by its PathItem and Operations:
+//
+//   - template variables which are not declared by the PathItem or one of its
+//     Operations are declared on the PathItem as required string Parameters
+//     or, if opts.Rename is set, renamed to match an unused Parameter
+//   - declared path Parameters which are not required are made required
+//   - declared path Parameters which are not in the template are reported
+//
+// References to Parameters must be resolved; unresolved references are
+// ignored. The fixes applied and issues found are returned in order.
+func (d *Document) FixPathParameters(opts PathParameterOpts) ([]PathParameterFix, error) {
+	if d == nil || d.Paths == nil {
+		return nil, nil
+	}
+	var fixes []PathParameterFix
+	for i := range d.Paths.Items {
+		item := &d.Paths.Items[i]
+		if item.Value == nil {
+			continue
+		}
+		if opts.Rename {
+			fixes = append(fixes, d.renamePathVariables(item)...)
+		}
+		fixes = append(fixes, fixPathParameters(item.Key, item.Value)...)
+	}
+	if len(fixes) > 0 {
+		if err := d.Paths.setLocation(d.Paths.Location); err != nil {
+			return fixes, err
+		}
+	}
+	return fixes, nil
+}
+
+// declaredPathParameter is a path Parameter and the method of the Operation
+// which declares it, if any
+type declaredPathParameter struct {
+	method Text
+	param  *Parameter
+}
+
+func declaredPathParameters(pi *PathItem) []declaredPathParameter {
+	var declared []declaredPathParameter
+	add := func(method Text, ps *ParameterSlice) {
+		if ps == nil {
+			return
+		}
+		for _, c := range ps.Items {
+			if c != nil && c.Object != nil && c.Object.In == InPath {
+				declared = append(declared, declaredPathParameter{method: method, param: c.Object})
+			}
+		}
+	}
+	add("", pi.Parameters)
+	for _, mo := range pi.Operations() {
+		add(mo.Method, mo.Operation.Parameters)
+	}
+	return declared
+}
+
+func (d *Document) renamePathVariables(item *Item[*PathItem]) []PathParameterFix {
+	vars := map[Text]bool{}
+	for _, v := range PathTemplateVariables(item.Key) {
+		vars[v] = true
+	}
+	seen := map[Text]bool{}
+	var unused []Text
+	for _, dp := range declaredPathParameters(item.Value) {
+		name := dp.param.Name
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		if !vars[name] {
+			unused = append(unused, name)
+		}
+	}
+	var undeclared []Text
+	for _, v := range PathTemplateVariables(item.Key) {
+		if !seen[v] {
+			undeclared = append(undeclared, v)
+		}
+	}
+	if len(undeclared) == 0 || len(undeclared) != len(unused) {
+		return nil
+	}
+	key := item.Key.String()
+	for i, v := range undeclared {
+		key = strings.Replace(key, "{"+v.String()+"}", "{"+unused[i].String()+"}", 1)
+	}
+	if d.Paths.Get(Text(key)) != nil {
+		return nil
+	}
+	item.Key = Text(key)
+	fixes := make([]PathParameterFix, len(undeclared))
+	for i, v := range undeclared {
+		fixes[i] = PathParameterFix{Action: PathParameterRenamed, Key: item.Key, Name: unused[i], Previous: v}
+	}
+	return fixes
+}
+
+func fixPathParameters(key Text, pi *PathItem) []PathParameterFix {
+	var fixes []PathParameterFix
+	vars := PathTemplateVariables(key)
+	inTemplate := map[Text]bool{}
+	for _, v := range vars {
+		inTemplate[v] = true
+	}
+
+	// a variable is missing if the PathItem does not declare it and any of
+	// its Operations do not either
+	declared := map[Text]map[Text]bool{}
+	for _, dp := range declaredPathParameters(pi) {
+		if declared[dp.method] == nil {
+			declared[dp.method] = map[Text]bool{}
+		}
+		declared[dp.method][dp.param.Name] = true
+	}
+	ops := pi.Operations()
+	for _, v := range vars {
+		missing := len(ops) == 0 && !declared[""][v]
+		for _, mo := range ops {
+			if !declared[""][v] && !declared[mo.Method][v] {
+				missing = true
+				break
+			}
+		}
+		if !missing {
+			continue
+		}
+		required := true
+		if pi.Parameters == nil {
+			pi.Parameters = &ParameterSlice{}
+		}
+		pi.Parameters.Items = append(pi.Parameters.Items, &Component[*Parameter]{
+			Object: &Parameter{
+				Name:     v,
+				In:       InPath,
+				Required: &required,
+				Schema:   &Schema{Type: Types{TypeString}},
+			},
+		})
+		fixes = append(fixes, PathParameterFix{Action: PathParameterAdded, Key: key, Name: v})
+	}
+
+	for _, dp := range declaredPathParameters(pi) {
+		p := dp.param
+		if !inTemplate[p.Name] {
+			fixes = append(fixes, PathParameterFix{Action: PathParameterUnused, Key: key, Method: dp.method, Name: p.Name})
+			continue
+		}
+		if p.Required == nil || !*p.Required {
+			required := true
+			p.Required = &required
+			fixes = append(fixes, PathParameterFix{Action: PathParameterRequired, Key: key, Method: dp.method, Name: p.Name})
+		}
+	}
+	return fixes
+}
@@ -0,0 +1,86 @@
+package openapi_test
+
+import (
+	"testing"
+
+	"github.com/chanced/openapi"
+)
+
+func TestFixPathParameters(t *testing.T) {
+	data := []byte(`{
+		"openapi": "3.1.0",
+		"info": { "title": "path parameters", "version": "1.0.0" },
+		"paths": {
+			"/pets/{petId}": {
+				"get": {
+					"parameters": [{ "name": "petId", "in": "path", "schema": { "type": "integer" } }],
+					"responses": { "200": { "description": "ok" } }
+				},
+				"delete": { "responses": { "204": { "description": "deleted" } } }
+			},
+			"/owners/{id}/pets/{petId}": {
+				"parameters": [
+					{ "name": "ownerId", "in": "path", "required": true, "schema": { "type": "integer" } },
+					{ "name": "petId", "in": "path", "required": true, "schema": { "type": "integer" } }
+				],
+				"get": { "responses": { "200": { "description": "ok" } } }
+			},
+			"/toys": {
+				"get": {
+					"parameters": [{ "name": "toyId", "in": "path", "required": true, "schema": { "type": "string" } }],
+					"responses": { "200": { "description": "ok" } }
+				}
+			}
+		}
+	}`)
+	load := func() *openapi.Document {
+		return loadDocument(t, "https://example.com/openapi.json", data)
+	}
+
+	doc := load()
+	fixes, err := doc.FixPathParameters(openapi.PathParameterOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []openapi.PathParameterFix{
+		{Action: openapi.PathParameterAdded, Key: "/pets/{petId}", Name: "petId"},
+		{Action: openapi.PathParameterRequired, Key: "/pets/{petId}", Method: openapi.MethodGet, Name: "petId"},
+		{Action: openapi.PathParameterAdded, Key: "/owners/{id}/pets/{petId}", Name: "id"},
+		{Action: openapi.PathParameterUnused, Key: "/owners/{id}/pets/{petId}", Name: "ownerId"},
+		{Action: openapi.PathParameterUnused, Key: "/toys", Method: openapi.MethodGet, Name: "toyId"},
+	}
+	if len(fixes) != len(expected) {
+		t.Fatalf("expected %d fixes, got %+v", len(expected), fixes)
+	}
+	for i, f := range fixes {
+		if f != expected[i] {
+			t.Errorf("expected %+v, got %+v", expected[i], f)
+		}
+	}
+	pi := doc.Paths.Get("/pets/{petId}")
+	if pi.Parameters == nil || len(pi.Parameters.Items) != 1 {
+		t.Fatal("expected petId to be declared on the PathItem")
+	}
+	added := pi.Parameters.Items[0].Object
+	if added.In != openapi.InPath || added.Required == nil || !*added.Required || !added.Schema.Type.ContainsString() {
+		t.Errorf("unexpected parameter: %+v", added)
+	}
+	if loc := added.RelativeLocation(); loc != "/paths/~1pets~1{petId}/parameters/0" {
+		t.Errorf("unexpected location: %s", loc)
+	}
+
+	doc = load()
+	if fixes, err = doc.FixPathParameters(openapi.PathParameterOpts{Rename: true}); err != nil {
+		t.Fatal(err)
+	}
+	if doc.Paths.Get("/owners/{ownerId}/pets/{petId}") == nil || doc.Paths.Get("/owners/{id}/pets/{petId}") != nil {
+		t.Error("expected {id} to be renamed to {ownerId}")
+	}
+	renamed := openapi.PathParameterFix{Action: openapi.PathParameterRenamed, Key: "/owners/{ownerId}/pets/{petId}", Name: "ownerId", Previous: "id"}
+	if len(fixes) != 4 || fixes[2] != renamed {
+		t.Errorf("unexpected fixes: %+v", fixes)
+	}
+	if vars := openapi.PathTemplateVariables("/a/{b}/c/{d}"); len(vars) != 2 || vars[0] != "b" || vars[1] != "d" {
+		t.Errorf("unexpected template variables: %v", vars)
+	}
+}
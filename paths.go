@@ -1,12 +1,10 @@
 package openapi
 
 import (
-	"bytes"
 	"encoding/json"
 	"strings"
 
 	"github.com/chanced/caps/text"
-	"github.com/chanced/transcode"
 	"github.com/tidwall/gjson"
 	"gopkg.in/yaml.v3"
 )
@@ -93,10 +91,10 @@ func (p Paths) MarshalJSON() ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
-	b := bytes.Buffer{}
+	b := getBuffer()
 	// removing the last } as marshalExtensionsInto execpts a buffer without it
 	b.Write(j[:len(j)-1])
-	return marshalExtensionsInto(&b, p.Extensions)
+	return marshalExtensionsInto(b, p.Extensions)
 }
 
 // UnmarshalJSON unmarshals JSON data into p
@@ -107,11 +105,11 @@ func (p *Paths) UnmarshalJSON(data []byte) error {
 	var err error
 	gjson.ParseBytes(data).ForEach(func(key, value gjson.Result) bool {
 		if strings.HasPrefix(key.String(), "x-") {
-			p.SetRawExtension(Text(key.String()), []byte(value.Raw))
+			p.SetRawExtension(intern(key.String()), []byte(value.Raw))
 		} else {
 			var v PathItem
 			err = json.Unmarshal([]byte(value.Raw), &v)
-			p.Set(Text(key.String()), &v)
+			p.Set(intern(key.String()), &v)
 		}
 		return err == nil
 	})
@@ -134,11 +132,7 @@ func (p Paths) MarshalYAML() (interface{}, error) {
 
 // UnmarshalYAML satisfies gopkg.in/yaml.v3 Unmarshaler interface
 func (p *Paths) UnmarshalYAML(value *yaml.Node) error {
-	v, err := yaml.Marshal(value)
-	if err != nil {
-		return err
-	}
-	j, err := transcode.JSONFromYAML(v)
+	j, err := jsonFromYAMLNode(value)
 	if err != nil {
 		return err
 	}
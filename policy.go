@@ -0,0 +1,36 @@
+package openapi
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/chanced/uri"
+)
+
+// SameOriginOnly is a policy for LoadOpts.AllowRef which only permits
+// references to resources with the same scheme and host (including port) as
+// the resource containing the reference.
+func SameOriginOnly(from Location, target uri.URI) error {
+	f := from.AbsoluteLocation()
+	if !strings.EqualFold(f.Scheme, target.Scheme) || !strings.EqualFold(f.Host, target.Host) {
+		return fmt.Errorf("origin %q differs from %q", origin(target), origin(f))
+	}
+	return nil
+}
+
+// FileOnly is a policy for LoadOpts.AllowRef which only permits references
+// to local files, i.e. URIs with the "file" scheme or without a scheme or
+// host.
+func FileOnly(from Location, target uri.URI) error {
+	if strings.EqualFold(target.Scheme, "file") || (target.Scheme == "" && target.Host == "") {
+		return nil
+	}
+	return fmt.Errorf("%q is not a file", target.String())
+}
+
+func origin(u uri.URI) string {
+	if u.Scheme == "" {
+		return u.Host
+	}
+	return u.Scheme + "://" + u.Host
+}
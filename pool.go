@@ -0,0 +1,110 @@
+package openapi
+
+import (
+	"bytes"
+	"sync"
+	"sync/atomic"
+
+	"github.com/tidwall/gjson"
+)
+
+// maxPooledBufferSize is the capacity beyond which buffers are not returned
+// to the pool so that the encoding of a single large Document does not pin
+// its memory.
+const maxPooledBufferSize = 1 << 20
+
+// pooling is 1 if pooling is enabled. See SetPooling.
+var pooling int32 = 1
+
+var bufferPool = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+// SetPooling enables or disables the reuse of allocations while encoding and
+// decoding. Pooling is enabled by default.
+//
+// When enabled, the temporary buffers used to marshal and transcode are
+// drawn from a sync.Pool and the entries of a SchemaMap or ComponentMap are
+// allocated together in a single block rather than individually. Disabling
+// pooling allocates each of these separately, which can be useful when
+// debugging or when profiling allocations.
+//
+// SetPooling is safe for concurrent use but only affects subsequent
+// operations.
+func SetPooling(enabled bool) {
+	if enabled {
+		atomic.StoreInt32(&pooling, 1)
+	} else {
+		atomic.StoreInt32(&pooling, 0)
+	}
+}
+
+// Pooling reports whether pooling is enabled. See SetPooling.
+func Pooling() bool {
+	return atomic.LoadInt32(&pooling) == 1
+}
+
+// getBuffer returns an empty buffer, drawn from the pool if pooling is
+// enabled. The buffer should be released with releaseBuffer or putBuffer.
+func getBuffer() *bytes.Buffer {
+	if !Pooling() {
+		return new(bytes.Buffer)
+	}
+	b := bufferPool.Get().(*bytes.Buffer)
+	b.Reset()
+	return b
+}
+
+// putBuffer returns b to the pool. b must not be used afterward.
+func putBuffer(b *bytes.Buffer) {
+	if !Pooling() || b.Cap() > maxPooledBufferSize {
+		return
+	}
+	bufferPool.Put(b)
+}
+
+// releaseBuffer returns the contents of b and releases b. If pooling is
+// enabled, the contents are copied so that b may be reused.
+func releaseBuffer(b *bytes.Buffer) []byte {
+	if !Pooling() {
+		return b.Bytes()
+	}
+	data := make([]byte, b.Len())
+	copy(data, b.Bytes())
+	putBuffer(b)
+	return data
+}
+
+// allocator hands out pointers to values of T, allocated in a single block
+// of n values if pooling is enabled. It must not be used for more than n
+// values.
+type allocator[T any] struct {
+	block []T
+}
+
+func newAllocator[T any](n int) allocator[T] {
+	if !Pooling() || n <= 1 {
+		return allocator[T]{}
+	}
+	return allocator[T]{block: make([]T, 0, n)}
+}
+
+func (a *allocator[T]) new() *T {
+	if len(a.block) == cap(a.block) {
+		return new(T)
+	}
+	var v T
+	a.block = append(a.block, v)
+	return &a.block[len(a.block)-1]
+}
+
+// countMembers returns the number of members of the object or elements of
+// the array obj.
+func countMembers(obj gjson.Result) int {
+	n := 0
+	obj.ForEach(func(_, _ gjson.Result) bool {
+		n++
+		return true
+	})
+	return n
+}
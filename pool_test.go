@@ -0,0 +1,57 @@
+package openapi_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/chanced/openapi"
+	"gopkg.in/yaml.v3"
+)
+
+func TestPooling(t *testing.T) {
+	defer openapi.SetPooling(true)
+	if !openapi.Pooling() {
+		t.Fatal("expected pooling to be enabled by default")
+	}
+	data, err := testdata.ReadFile("testdata/documents/validation/pass/mega.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	encode := func(pooling bool) []byte {
+		t.Helper()
+		openapi.SetPooling(pooling)
+		var doc openapi.Document
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			t.Fatal(err)
+		}
+		a, err := doc.MarshalJSON()
+		if err != nil {
+			t.Fatal(err)
+		}
+		// encoding again must not overwrite a, which would be the case if a
+		// shared memory with a pooled buffer
+		expected := append([]byte(nil), a...)
+		doc.Info.Title = "changed"
+		if _, err = doc.MarshalJSON(); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(a, expected) {
+			t.Error("expected the output of MarshalJSON to be retained")
+		}
+		return a
+	}
+	pooled := encode(true)
+	unpooled := encode(false)
+	if openapi.Pooling() {
+		t.Error("expected pooling to be disabled")
+	}
+	if !bytes.Equal(pooled, unpooled) {
+		t.Errorf("expected pooled and unpooled encodings to be equal:\n%s\n%s", pooled, unpooled)
+	}
+}
+
+func BenchmarkUnmarshalYAMLUnpooled(b *testing.B) {
+	openapi.SetPooling(false)
+	defer openapi.SetPooling(true)
+	BenchmarkUnmarshalYAML(b)
+}
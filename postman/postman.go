@@ -0,0 +1,505 @@
+// Package postman exports OpenAPI Documents as Postman (v2.1) collections.
+//
+// Each Operation of the Document's Paths becomes a request within a folder
+// named after the Operation's first tag. Requests use the "baseUrl"
+// collection variable, which defaults to the first server of the Document.
+// Request bodies are populated from the examples of the request body or,
+// absent any, from an example generated from its Schema. Authentication is
+// derived from the security schemes required by each Operation.
+package postman
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/chanced/openapi"
+)
+
+// SchemaURL is the URL of the Postman v2.1 collection schema.
+const SchemaURL = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+
+// Collection is a Postman collection.
+type Collection struct {
+	Info     Info       `json:"info"`
+	Item     []Item     `json:"item"`
+	Auth     *Auth      `json:"auth,omitempty"`
+	Variable []Variable `json:"variable,omitempty"`
+}
+
+// Info describes a Collection.
+type Info struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version,omitempty"`
+	Schema      string `json:"schema"`
+}
+
+// Item is either a folder, in which case Item is set, or a request.
+type Item struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Item        []Item    `json:"item,omitempty"`
+	Request     *Request  `json:"request,omitempty"`
+	Response    []Example `json:"response,omitempty"`
+}
+
+// Request is a Postman request.
+type Request struct {
+	Method      string     `json:"method"`
+	Description string     `json:"description,omitempty"`
+	Header      []Variable `json:"header"`
+	URL         URL        `json:"url"`
+	Body        *Body      `json:"body,omitempty"`
+	Auth        *Auth      `json:"auth,omitempty"`
+}
+
+// Example is a saved response of a request.
+type Example struct {
+	Name   string     `json:"name"`
+	Code   int        `json:"code,omitempty"`
+	Status string     `json:"status,omitempty"`
+	Header []Variable `json:"header,omitempty"`
+	Body   string     `json:"body,omitempty"`
+}
+
+// URL is the URL of a Request.
+type URL struct {
+	Raw      string     `json:"raw"`
+	Host     []string   `json:"host,omitempty"`
+	Path     []string   `json:"path,omitempty"`
+	Query    []Variable `json:"query,omitempty"`
+	Variable []Variable `json:"variable,omitempty"`
+}
+
+// Variable is a key/value pair, used for collection variables, headers,
+// query parameters, and path variables.
+type Variable struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Type        string `json:"type,omitempty"`
+	Description string `json:"description,omitempty"`
+	Disabled    bool   `json:"disabled,omitempty"`
+}
+
+// Body is the body of a Request.
+type Body struct {
+	Mode    string                 `json:"mode"`
+	Raw     string                 `json:"raw,omitempty"`
+	Options map[string]interface{} `json:"options,omitempty"`
+}
+
+// Auth is the authentication of a Collection or Request. The attributes of
+// the auth type are keyed by Type (e.g. "bearer").
+type Auth struct {
+	Type       string
+	Attributes []Variable
+}
+
+// MarshalJSON satisfies json.Marshaler
+func (a Auth) MarshalJSON() ([]byte, error) {
+	m := map[string]interface{}{"type": a.Type}
+	if a.Type != "noauth" {
+		m[a.Type] = a.Attributes
+	}
+	return json.Marshal(m)
+}
+
+// UnmarshalJSON satisfies json.Unmarshaler
+func (a *Auth) UnmarshalJSON(data []byte) error {
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+	*a = Auth{}
+	if err := json.Unmarshal(m["type"], &a.Type); err != nil {
+		return err
+	}
+	if attrs, ok := m[a.Type]; ok {
+		return json.Unmarshal(attrs, &a.Attributes)
+	}
+	return nil
+}
+
+// Options configures Export.
+type Options struct {
+	// Name of the Collection.
+	//
+	// Defaults to the title of the Document.
+	Name string
+	// BaseURL is the value of the "baseUrl" collection variable.
+	//
+	// Defaults to the URL of the first server of the Document.
+	BaseURL string
+}
+
+// Export returns a Postman collection of the Operations of doc.
+func Export(doc *openapi.Document, opts Options) (*Collection, error) {
+	if doc == nil {
+		return nil, fmt.Errorf("postman: cannot export nil Document")
+	}
+	c := &Collection{Info: Info{Name: opts.Name, Schema: SchemaURL}}
+	if doc.Info != nil {
+		if c.Info.Name == "" {
+			c.Info.Name = doc.Info.Title.String()
+		}
+		c.Info.Description = doc.Info.Description.String()
+		c.Info.Version = doc.Info.Version.String()
+	}
+	if opts.BaseURL == "" {
+		opts.BaseURL = serverURL(doc.Servers)
+	}
+	c.Variable = []Variable{{Key: "baseUrl", Value: opts.BaseURL, Type: "string"}}
+
+	e := exporter{doc: doc, vars: map[string]bool{}}
+	if doc.Security != nil {
+		c.Auth = e.auth(doc.Security.Items)
+	}
+	folders := map[string]int{}
+	for _, op := range doc.Operations() {
+		if op.Webhook || op.Operation == nil {
+			continue
+		}
+		item, err := e.request(op)
+		if err != nil {
+			return nil, err
+		}
+		if len(op.Operation.Tags) == 0 {
+			c.Item = append(c.Item, item)
+			continue
+		}
+		tag := op.Operation.Tags[0].String()
+		i, ok := folders[tag]
+		if !ok {
+			i = len(c.Item)
+			folders[tag] = i
+			c.Item = append(c.Item, Item{Name: tag, Description: tagDescription(doc, tag)})
+		}
+		c.Item[i].Item = append(c.Item[i].Item, item)
+	}
+	for _, v := range e.varOrder {
+		c.Variable = append(c.Variable, Variable{Key: v, Type: "string"})
+	}
+	return c, nil
+}
+
+type exporter struct {
+	doc      *openapi.Document
+	vars     map[string]bool
+	varOrder []string
+}
+
+// variable adds the collection variable name, if it has not already been
+// added, and returns a reference to it
+func (e *exporter) variable(name string) string {
+	if !e.vars[name] {
+		e.vars[name] = true
+		e.varOrder = append(e.varOrder, name)
+	}
+	return "{{" + name + "}}"
+}
+
+func (e *exporter) request(op openapi.OperationEntry) (Item, error) {
+	o := op.Operation
+	item := Item{Name: o.Summary.String()}
+	if item.Name == "" {
+		item.Name = o.OperationID.String()
+	}
+	if item.Name == "" {
+		item.Name = strings.ToUpper(op.Method.String()) + " " + op.Key.String()
+	}
+	req := &Request{
+		Method:      strings.ToUpper(op.Method.String()),
+		Description: o.Description.String(),
+		Header:      []Variable{},
+		URL:         URL{Host: []string{"{{baseUrl}}"}},
+	}
+	params, err := o.EffectiveParameters(op.PathItem)
+	if err != nil {
+		return Item{}, fmt.Errorf("postman: failed to export %s %s: %w", req.Method, op.Key, err)
+	}
+	path := op.Key.String()
+	for _, p := range params {
+		v := Variable{
+			Key:         p.Name.String(),
+			Value:       parameterValue(p),
+			Description: p.Description.String(),
+		}
+		switch p.In {
+		case openapi.InPath:
+			path = strings.ReplaceAll(path, "{"+p.Name.String()+"}", ":"+p.Name.String())
+			req.URL.Variable = append(req.URL.Variable, v)
+		case openapi.InQuery:
+			v.Disabled = p.Required == nil || !*p.Required
+			req.URL.Query = append(req.URL.Query, v)
+		case openapi.InHeader:
+			req.Header = append(req.Header, v)
+		case openapi.InCookie:
+			req.Header = append(req.Header, Variable{Key: "Cookie", Value: v.Key + "=" + v.Value, Description: v.Description})
+		}
+	}
+	for _, seg := range strings.Split(strings.Trim(path, "/"), "/") {
+		if seg != "" {
+			req.URL.Path = append(req.URL.Path, seg)
+		}
+	}
+	req.URL.Raw = "{{baseUrl}}" + path
+	if len(req.URL.Query) > 0 {
+		var q []string
+		for _, v := range req.URL.Query {
+			if !v.Disabled {
+				q = append(q, v.Key+"="+v.Value)
+			}
+		}
+		if len(q) > 0 {
+			req.URL.Raw += "?" + strings.Join(q, "&")
+		}
+	}
+	if rb := o.RequestBody; rb != nil && rb.Object != nil {
+		if mt, m := mediaType(rb.Object.Content); m != nil {
+			req.Header = append(req.Header, Variable{Key: "Content-Type", Value: mt})
+			req.Body = &Body{Mode: "raw", Raw: mediaTypeExample(m)}
+			if isJSON(mt) {
+				req.Body.Options = map[string]interface{}{"raw": map[string]string{"language": "json"}}
+			}
+		}
+	}
+	if o.Security != nil {
+		if len(o.Security.Items) == 0 {
+			req.Auth = &Auth{Type: "noauth"}
+		} else {
+			req.Auth = e.auth(o.Security.Items)
+		}
+	}
+	item.Request = req
+	item.Response = e.responses(o)
+	return item, nil
+}
+
+func (e *exporter) responses(o *openapi.Operation) []Example {
+	if o.Responses == nil {
+		return nil
+	}
+	var res []Example
+	for _, item := range o.Responses.Items {
+		if item.Component == nil || item.Component.Object == nil {
+			continue
+		}
+		r := item.Component.Object
+		mt, m := mediaType(r.Content)
+		if m == nil {
+			continue
+		}
+		ex := Example{
+			Name:   r.Description.String(),
+			Header: []Variable{{Key: "Content-Type", Value: mt}},
+			Body:   mediaTypeExample(m),
+		}
+		if ex.Name == "" {
+			ex.Name = item.Key.String()
+		}
+		if code, err := strconv.Atoi(item.Key.String()); err == nil {
+			ex.Code = code
+		}
+		res = append(res, ex)
+	}
+	return res
+}
+
+// auth returns the Auth of the first security requirement of reqs which
+// can be represented in Postman.
+func (e *exporter) auth(reqs []*openapi.SecurityRequirement) *Auth {
+	for _, r := range reqs {
+		if r == nil {
+			continue
+		}
+		if len(r.Items) == 0 {
+			// an empty requirement indicates that authentication is optional
+			return &Auth{Type: "noauth"}
+		}
+		for _, item := range r.Items {
+			if a := e.schemeAuth(item.Key); a != nil {
+				return a
+			}
+		}
+	}
+	return nil
+}
+
+func (e *exporter) schemeAuth(name openapi.Text) *Auth {
+	if e.doc.Components == nil || e.doc.Components.SecuritySchemes == nil {
+		return nil
+	}
+	c := e.doc.Components.SecuritySchemes.Get(name)
+	if c == nil || c.Object == nil {
+		return nil
+	}
+	ss := c.Object
+	v := name.ToLowerCamel().String()
+	switch ss.Type {
+	case "apiKey":
+		in := ss.In.String()
+		if in != "query" {
+			in = "header"
+		}
+		return &Auth{Type: "apikey", Attributes: []Variable{
+			{Key: "key", Value: ss.Name.String(), Type: "string"},
+			{Key: "value", Value: e.variable(v), Type: "string"},
+			{Key: "in", Value: in, Type: "string"},
+		}}
+	case "http":
+		switch strings.ToLower(ss.Scheme.String()) {
+		case "basic":
+			return &Auth{Type: "basic", Attributes: []Variable{
+				{Key: "username", Value: e.variable(v + "Username"), Type: "string"},
+				{Key: "password", Value: e.variable(v + "Password"), Type: "string"},
+			}}
+		case "bearer":
+			return &Auth{Type: "bearer", Attributes: []Variable{
+				{Key: "token", Value: e.variable(v), Type: "string"},
+			}}
+		}
+	case "oauth2", "openIdConnect":
+		attrs := []Variable{
+			{Key: "accessToken", Value: e.variable(v), Type: "string"},
+			{Key: "addTokenTo", Value: "header", Type: "string"},
+		}
+		if f := oauthFlow(ss.Flows); f != nil {
+			if f.AuthorizationURL != "" {
+				attrs = append(attrs, Variable{Key: "authUrl", Value: f.AuthorizationURL.String(), Type: "string"})
+			}
+			if f.TokenURL != "" {
+				attrs = append(attrs, Variable{Key: "accessTokenUrl", Value: f.TokenURL.String(), Type: "string"})
+			}
+		}
+		return &Auth{Type: "oauth2", Attributes: attrs}
+	}
+	return nil
+}
+
+func oauthFlow(flows *openapi.OAuthFlows) *openapi.OAuthFlow {
+	if flows == nil {
+		return nil
+	}
+	for _, f := range []*openapi.OAuthFlow{flows.AuthorizationCode, flows.ClientCredentials, flows.Password, flows.Implicit} {
+		if f != nil {
+			return f
+		}
+	}
+	return nil
+}
+
+// serverURL returns the URL of the first server, with its variables
+// replaced by their default values
+func serverURL(servers *openapi.ServerSlice) string {
+	if servers == nil || len(servers.Items) == 0 || servers.Items[0] == nil {
+		return ""
+	}
+	s := servers.Items[0]
+	u := s.URL.String()
+	if s.Variables != nil {
+		for _, v := range s.Variables.Items {
+			if v.Value != nil {
+				u = strings.ReplaceAll(u, "{"+v.Key.String()+"}", v.Value.Default.String())
+			}
+		}
+	}
+	return strings.TrimSuffix(u, "/")
+}
+
+func tagDescription(doc *openapi.Document, name string) string {
+	if doc.Tags == nil {
+		return ""
+	}
+	for _, t := range doc.Tags.Items {
+		if t != nil && t.Name.String() == name {
+			return t.Description.String()
+		}
+	}
+	return ""
+}
+
+// mediaType returns the JSON media type of content or, if there is not one,
+// the first media type
+func mediaType(content *openapi.ContentMap) (string, *openapi.MediaType) {
+	if content == nil || len(content.Items) == 0 {
+		return "", nil
+	}
+	for _, item := range content.Items {
+		if item.Value != nil && isJSON(item.Key.String()) {
+			return item.Key.String(), item.Value
+		}
+	}
+	first := content.Items[0]
+	return first.Key.String(), first.Value
+}
+
+func isJSON(mt string) bool {
+	mt = strings.ToLower(strings.TrimSpace(strings.Split(mt, ";")[0]))
+	return mt == "application/json" || strings.HasSuffix(mt, "+json")
+}
+
+func parameterValue(p *openapi.Parameter) string {
+	if p.Example != nil {
+		return rawString(p.Example)
+	}
+	if p.Examples != nil {
+		for _, item := range p.Examples.Items {
+			if item.Component != nil && item.Component.Object != nil && item.Component.Object.Value != nil {
+				return rawString(item.Component.Object.Value)
+			}
+		}
+	}
+	if p.Schema != nil {
+		if v := p.Schema.GenerateExample(); v != nil {
+			data, err := json.Marshal(v)
+			if err == nil {
+				return rawString(data)
+			}
+		}
+	}
+	return ""
+}
+
+// rawString returns the JSON value data as a string, unquoting strings
+func rawString(data []byte) string {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		return s
+	}
+	return string(data)
+}
+
+func mediaTypeExample(m *openapi.MediaType) string {
+	if m.Example != nil {
+		return indent(m.Example)
+	}
+	if m.Examples != nil {
+		for _, item := range m.Examples.Items {
+			if item.Component != nil && item.Component.Object != nil && item.Component.Object.Value != nil {
+				return indent(item.Component.Object.Value)
+			}
+		}
+	}
+	if m.Schema == nil {
+		return ""
+	}
+	data, err := json.MarshalIndent(m.Schema.GenerateExample(), "", "  ")
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+func indent(data []byte) string {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return string(data)
+	}
+	res, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return string(data)
+	}
+	return string(res)
+}
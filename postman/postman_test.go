@@ -0,0 +1,150 @@
+package postman_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/chanced/openapi"
+	"github.com/chanced/openapi/postman"
+	"github.com/chanced/uri"
+)
+
+func TestExport(t *testing.T) {
+	data := []byte(`{
+		"openapi": "3.1.0",
+		"info": { "title": "Pet Store", "version": "1.0.0" },
+		"servers": [
+			{ "url": "https://{env}.example.com/v1/", "variables": { "env": { "default": "api" } } }
+		],
+		"tags": [{ "name": "pets", "description": "Pet operations" }],
+		"security": [{ "token": [] }],
+		"paths": {
+			"/pets/{petId}": {
+				"get": {
+					"tags": ["pets"],
+					"summary": "Get a pet",
+					"parameters": [
+						{ "name": "petId", "in": "path", "required": true, "schema": { "type": "integer" }, "example": 7 },
+						{ "name": "expand", "in": "query", "schema": { "type": "boolean" } }
+					],
+					"responses": {
+						"200": {
+							"description": "ok",
+							"content": {
+								"application/json": { "schema": { "$ref": "#/components/schemas/Pet" } }
+							}
+						}
+					}
+				}
+			},
+			"/pets": {
+				"post": {
+					"tags": ["pets"],
+					"operationId": "createPet",
+					"requestBody": {
+						"content": {
+							"application/json": { "schema": { "$ref": "#/components/schemas/Pet" } }
+						}
+					},
+					"responses": {
+						"201": { "description": "created" }
+					}
+				}
+			},
+			"/health": {
+				"get": {
+					"operationId": "health",
+					"responses": {
+						"204": { "description": "healthy" }
+					}
+				}
+			}
+		},
+		"components": {
+			"schemas": {
+				"Pet": {
+					"type": "object",
+					"properties": {
+						"name": { "type": "string", "examples": ["Fido"] },
+						"born": { "type": "string", "format": "date" }
+					}
+				}
+			},
+			"securitySchemes": {
+				"token": { "type": "http", "scheme": "bearer" }
+			}
+		}
+	}`)
+	fn := func(ctx context.Context, u uri.URI, kind openapi.Kind) (openapi.Kind, []byte, error) {
+		return openapi.KindDocument, data, nil
+	}
+	v, err := openapi.NewOfflineValidator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := openapi.Load(context.Background(), "https://example.com/openapi.json", v, fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := postman.Export(doc, postman.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Info.Name != "Pet Store" || c.Info.Schema != postman.SchemaURL {
+		t.Errorf("unexpected info: %+v", c.Info)
+	}
+	if len(c.Variable) != 2 || c.Variable[0].Value != "https://api.example.com/v1" || c.Variable[1].Key != "token" {
+		t.Errorf("unexpected variables: %+v", c.Variable)
+	}
+	if c.Auth == nil || c.Auth.Type != "bearer" || c.Auth.Attributes[0].Value != "{{token}}" {
+		t.Errorf("unexpected auth: %+v", c.Auth)
+	}
+	if len(c.Item) != 2 {
+		t.Fatalf("expected a folder and a request, got %d items", len(c.Item))
+	}
+	folder := c.Item[0]
+	if folder.Name != "pets" || folder.Description != "Pet operations" || len(folder.Item) != 2 {
+		t.Fatalf("unexpected folder: %+v", folder)
+	}
+	get := folder.Item[0].Request
+	if get.URL.Raw != "{{baseUrl}}/pets/:petId" {
+		t.Errorf("unexpected url: %s", get.URL.Raw)
+	}
+	if len(get.URL.Variable) != 1 || get.URL.Variable[0].Value != "7" {
+		t.Errorf("unexpected path variables: %+v", get.URL.Variable)
+	}
+	if len(get.URL.Query) != 1 || !get.URL.Query[0].Disabled {
+		t.Errorf("expected optional query parameter to be disabled: %+v", get.URL.Query)
+	}
+	if len(folder.Item[0].Response) != 1 || folder.Item[0].Response[0].Code != 200 {
+		t.Errorf("unexpected responses: %+v", folder.Item[0].Response)
+	}
+
+	post := folder.Item[1]
+	if post.Name != "createPet" || post.Request.Body == nil {
+		t.Fatalf("unexpected request: %+v", post)
+	}
+	var body map[string]interface{}
+	if err = json.Unmarshal([]byte(post.Request.Body.Raw), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body["name"] != "Fido" || body["born"] != "2006-01-02" {
+		t.Errorf("unexpected body: %v", body)
+	}
+	if c.Item[1].Name != "health" {
+		t.Errorf("expected untagged request at the root, got %+v", c.Item[1])
+	}
+
+	b, err := json.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rt postman.Collection
+	if err = json.Unmarshal(b, &rt); err != nil {
+		t.Fatal(err)
+	}
+	if rt.Auth == nil || rt.Auth.Type != "bearer" || len(rt.Auth.Attributes) != 1 {
+		t.Errorf("auth did not round trip: %+v", rt.Auth)
+	}
+}
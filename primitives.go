@@ -2,11 +2,9 @@ package openapi
 
 import (
 	"github.com/chanced/caps/text"
-	"github.com/chanced/jsonx"
 )
 
 type (
-	Text   = text.Text
-	Texts  = text.Texts
-	Number = jsonx.Number
+	Text  = text.Text
+	Texts = text.Texts
 )
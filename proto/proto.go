@@ -0,0 +1,695 @@
+// Package proto exports the schemas and operations of an OpenAPI Document as
+// a Protocol Buffers (proto3) definition.
+//
+// Each object Schema of the Document's components becomes a message and each
+// string enum becomes an enum. Schemas consisting of a oneOf become a message
+// with a oneof. The Operations of the Document's Paths become the rpcs of a
+// single service, with a request message composed of the Operation's
+// parameters and request body and a response derived from its first
+// successful (2XX) response.
+//
+// Schemas which can not be represented in proto3, such as nested arrays or
+// schemas without a type, are mapped to the google.protobuf well-known types
+// (e.g. google.protobuf.Value).
+package proto
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/chanced/openapi"
+)
+
+const (
+	typeValue     = "google.protobuf.Value"
+	typeStruct    = "google.protobuf.Struct"
+	typeListValue = "google.protobuf.ListValue"
+	typeEmpty     = "google.protobuf.Empty"
+)
+
+// DefaultFormats maps Schema formats to proto types. Formats set in
+// Options.Formats take precedence.
+var DefaultFormats = map[openapi.Text]string{
+	"int32":     "int32",
+	"int64":     "int64",
+	"uint32":    "uint32",
+	"uint64":    "uint64",
+	"float":     "float",
+	"double":    "double",
+	"byte":      "bytes",
+	"binary":    "bytes",
+	"date-time": "google.protobuf.Timestamp",
+	"duration":  "google.protobuf.Duration",
+}
+
+// wellKnownImports are the import paths of the google.protobuf well-known
+// types
+var wellKnownImports = map[string]string{
+	"google.protobuf.Timestamp":   "google/protobuf/timestamp.proto",
+	"google.protobuf.Duration":    "google/protobuf/duration.proto",
+	"google.protobuf.Empty":       "google/protobuf/empty.proto",
+	"google.protobuf.Value":       "google/protobuf/struct.proto",
+	"google.protobuf.Struct":      "google/protobuf/struct.proto",
+	"google.protobuf.ListValue":   "google/protobuf/struct.proto",
+	"google.protobuf.StringValue": "google/protobuf/wrappers.proto",
+	"google.protobuf.BytesValue":  "google/protobuf/wrappers.proto",
+	"google.protobuf.BoolValue":   "google/protobuf/wrappers.proto",
+	"google.protobuf.Int32Value":  "google/protobuf/wrappers.proto",
+	"google.protobuf.Int64Value":  "google/protobuf/wrappers.proto",
+	"google.protobuf.UInt32Value": "google/protobuf/wrappers.proto",
+	"google.protobuf.UInt64Value": "google/protobuf/wrappers.proto",
+	"google.protobuf.FloatValue":  "google/protobuf/wrappers.proto",
+	"google.protobuf.DoubleValue": "google/protobuf/wrappers.proto",
+}
+
+// wrappers are the google.protobuf wrapper types of scalars
+var wrappers = map[string]string{
+	"string": "google.protobuf.StringValue",
+	"bytes":  "google.protobuf.BytesValue",
+	"bool":   "google.protobuf.BoolValue",
+	"int32":  "google.protobuf.Int32Value",
+	"int64":  "google.protobuf.Int64Value",
+	"uint32": "google.protobuf.UInt32Value",
+	"uint64": "google.protobuf.UInt64Value",
+	"float":  "google.protobuf.FloatValue",
+	"double": "google.protobuf.DoubleValue",
+}
+
+// Options configures Export.
+type Options struct {
+	// Package is the proto package.
+	//
+	// Defaults to the snake_case title of the Document.
+	Package string
+	// GoPackage, if set, is written as the go_package option.
+	GoPackage string
+	// Service is the name of the service containing the rpcs of each
+	// Operation.
+	//
+	// Defaults to the PascalCase title of the Document suffixed with
+	// "Service".
+	Service string
+	// MessageName returns the name of a message or enum for the name of a
+	// component or property.
+	//
+	// Defaults to PascalCase.
+	MessageName func(name openapi.Text) string
+	// FieldName returns the name of a field for the name of a property or
+	// parameter.
+	//
+	// Defaults to snake_case.
+	FieldName func(name openapi.Text) string
+	// RPCName returns the name of the rpc of an Operation.
+	//
+	// Defaults to the PascalCase operationId or, if the Operation does not
+	// have an operationId, the PascalCase method and path, with common
+	// initialisms capitalized (e.g. "DELETE /pets/{petId}" is named
+	// DeletePetsPetID).
+	RPCName func(op openapi.OperationEntry) string
+	// Formats maps Schema formats to proto types (e.g. "uuid": "string"),
+	// taking precedence over DefaultFormats.
+	Formats map[openapi.Text]string
+	// Wrappers indicates that nullable scalars should be mapped to the
+	// google.protobuf wrapper types (e.g. google.protobuf.StringValue) rather
+	// than optional fields.
+	Wrappers bool
+}
+
+// Export returns a proto3 definition of the component schemas and operations
+// of doc.
+func Export(doc *openapi.Document, opts Options) ([]byte, error) {
+	if doc == nil {
+		return nil, fmt.Errorf("proto: cannot export nil Document")
+	}
+	e := newExporter(doc, opts)
+	if err := e.export(); err != nil {
+		return nil, err
+	}
+	return e.render(), nil
+}
+
+type field struct {
+	name       string
+	typ        string
+	number     int
+	repeated   bool
+	optional   bool
+	deprecated bool
+	comment    openapi.Text
+}
+
+// message is either a message or, if values is non-nil, an enum
+type message struct {
+	name       string
+	comment    openapi.Text
+	deprecated bool
+	fields     []field
+	oneof      bool
+	values     []string
+	nested     []*message
+	taken      map[string]bool
+}
+
+func newMessage(name string, comment openapi.Text) *message {
+	return &message{name: name, comment: comment, taken: map[string]bool{}}
+}
+
+func (m *message) isEnum() bool { return m.values != nil }
+
+func (m *message) add(f field) {
+	f.number = len(m.fields) + 1
+	m.fields = append(m.fields, f)
+}
+
+type rpc struct {
+	name       string
+	request    string
+	response   string
+	comment    openapi.Text
+	deprecated bool
+}
+
+type exporter struct {
+	doc      *openapi.Document
+	opts     Options
+	names    map[*openapi.Schema]string
+	taken    map[string]bool
+	messages []*message
+	rpcs     []rpc
+	imports  map[string]bool
+	visiting map[*openapi.Schema]bool
+}
+
+func newExporter(doc *openapi.Document, opts Options) *exporter {
+	if opts.MessageName == nil {
+		opts.MessageName = func(name openapi.Text) string { return name.ToCamel().String() }
+	}
+	if opts.FieldName == nil {
+		opts.FieldName = func(name openapi.Text) string { return name.ToSnake().String() }
+	}
+	if opts.RPCName == nil {
+		opts.RPCName = defaultRPCName
+	}
+	title := openapi.Text("api")
+	if doc.Info != nil && doc.Info.Title != "" {
+		title = doc.Info.Title
+	}
+	if opts.Package == "" {
+		opts.Package = strings.ReplaceAll(title.ToSnake().String(), "-", "_")
+	}
+	if opts.Service == "" {
+		opts.Service = title.ToCamel().String() + "Service"
+	}
+	return &exporter{
+		doc:      doc,
+		opts:     opts,
+		names:    map[*openapi.Schema]string{},
+		taken:    map[string]bool{},
+		imports:  map[string]bool{},
+		visiting: map[*openapi.Schema]bool{},
+	}
+}
+
+func defaultRPCName(op openapi.OperationEntry) string {
+	if op.Operation.OperationID != "" {
+		return op.Operation.OperationID.ToCamel().String()
+	}
+	path := strings.NewReplacer("{", " ", "}", " ", "/", " ").Replace(op.Key.String())
+	return openapi.Text(strings.ToLower(op.Method.String()) + " " + path).ToCamel().String()
+}
+
+func (e *exporter) export() error {
+	var schemas []openapi.SchemaItem
+	if e.doc.Components != nil && e.doc.Components.Schemas != nil {
+		schemas = e.doc.Components.Schemas.Items
+	}
+	// names are assigned before any message is built so that references
+	// between components can be resolved regardless of order
+	for _, item := range schemas {
+		if item.Schema != nil && isDeclared(item.Schema) {
+			e.names[item.Schema] = e.reserve(e.taken, e.opts.MessageName(item.Key))
+		}
+	}
+	for _, item := range schemas {
+		name, ok := e.names[item.Schema]
+		if !ok {
+			continue
+		}
+		e.messages = append(e.messages, e.declare(name, item.Schema))
+	}
+	return e.exportOperations()
+}
+
+// isDeclared reports whether the component schema s is exported as a
+// message or enum. All other component schemas are inlined where referenced.
+func isDeclared(s *openapi.Schema) bool {
+	if s.Ref != nil {
+		return false
+	}
+	if isStringEnum(s) {
+		return true
+	}
+	if s.OneOf != nil && len(s.OneOf.Items) > 0 {
+		return true
+	}
+	if s.AllOf != nil && len(s.AllOf.Items) > 0 {
+		return true
+	}
+	if s.Properties != nil && len(s.Properties.Items) > 0 {
+		return true
+	}
+	return s.Type.IsSingle() && s.Type.ContainsObject() && s.AdditionalProperties == nil
+}
+
+func isStringEnum(s *openapi.Schema) bool {
+	if len(s.Enum) == 0 || (len(s.Type) > 0 && !s.Type.ContainsString()) {
+		return false
+	}
+	_, ok := s.Enum.Texts()
+	return ok
+}
+
+// reserve returns name, suffixed with a number if it is already taken
+func (e *exporter) reserve(taken map[string]bool, name string) string {
+	n := name
+	for i := 2; taken[n]; i++ {
+		n = name + strconv.Itoa(i)
+	}
+	taken[n] = true
+	return n
+}
+
+// declare builds the message or enum name from s
+func (e *exporter) declare(name string, s *openapi.Schema) *message {
+	m := newMessage(name, s.Description)
+	m.deprecated = s.Deprecated != nil && *s.Deprecated
+	if isStringEnum(s) {
+		m.values = e.enumValues(name, s.Enum)
+		return m
+	}
+	if s.OneOf != nil && len(s.OneOf.Items) > 0 {
+		m.oneof = true
+		for i, b := range s.OneOf.Items {
+			fieldName := e.branchName(b, i)
+			typ, repeated := e.fieldType(b, openapi.Text(fieldName), m)
+			if repeated || strings.HasPrefix(typ, "map<") {
+				// oneof fields may not be repeated or maps
+				typ = e.wellKnown(typeListValue)
+				if !repeated {
+					typ = e.wellKnown(typeStruct)
+				}
+			}
+			m.add(field{name: e.reserve(m.taken, e.opts.FieldName(openapi.Text(fieldName))), typ: typ, comment: b.Description})
+		}
+		return m
+	}
+	e.addProperties(m, s)
+	return m
+}
+
+// branchName returns the name of the field of a oneof branch
+func (e *exporter) branchName(b *openapi.Schema, i int) string {
+	if b.Ref != nil && b.Ref.Resolved != nil {
+		if n, ok := e.names[b.Ref.Resolved]; ok {
+			return n
+		}
+	}
+	if b.Title != "" {
+		return b.Title.String()
+	}
+	if len(b.Type) > 0 {
+		return b.Type[0].String()
+	}
+	return "option" + strconv.Itoa(i+1)
+}
+
+func (e *exporter) addProperties(m *message, s *openapi.Schema) {
+	if s.AllOf != nil && len(s.AllOf.Items) > 0 {
+		s, _ = s.MergeAllOf()
+	}
+	if s.Properties == nil {
+		return
+	}
+	for _, p := range s.Properties.Items {
+		if p.Schema == nil {
+			continue
+		}
+		e.addField(m, p.Key, p.Schema, p.Schema.Description)
+	}
+}
+
+func (e *exporter) addField(m *message, name openapi.Text, s *openapi.Schema, comment openapi.Text) {
+	typ, repeated := e.fieldType(s, name, m)
+	f := field{
+		name:       e.reserve(m.taken, e.opts.FieldName(name)),
+		typ:        typ,
+		repeated:   repeated,
+		comment:    comment,
+		deprecated: s.Deprecated != nil && *s.Deprecated,
+	}
+	if !repeated && isScalar(typ) && s.IsNullable() {
+		f.optional = true
+	}
+	m.add(f)
+}
+
+// fieldType returns the proto type of s for a field named name of parent.
+// Inline objects and enums are added as nested types of parent.
+func (e *exporter) fieldType(s *openapi.Schema, name openapi.Text, parent *message) (string, bool) {
+	if s == nil {
+		return e.wellKnown(typeValue), false
+	}
+	if n, ok := e.names[s]; ok {
+		return n, false
+	}
+	if s.Ref != nil {
+		if s.Ref.Resolved == nil || e.visiting[s.Ref.Resolved] {
+			return e.wellKnown(typeValue), false
+		}
+		e.visiting[s.Ref.Resolved] = true
+		defer delete(e.visiting, s.Ref.Resolved)
+		return e.fieldType(s.Ref.Resolved, name, parent)
+	}
+	if s.AllOf != nil && len(s.AllOf.Items) > 0 {
+		s, _ = s.MergeAllOf()
+	}
+	if isStringEnum(s) {
+		n := newMessage(e.reserve(parent.taken, e.opts.MessageName(name)), "")
+		n.values = e.enumValues(n.name, s.Enum)
+		parent.nested = append(parent.nested, n)
+		return n.name, false
+	}
+	var types openapi.Types
+	for _, t := range s.Type {
+		if t != openapi.TypeNull {
+			types = append(types, t)
+		}
+	}
+	if len(types) != 1 {
+		if len(types) == 0 && s.Properties != nil && len(s.Properties.Items) > 0 {
+			types = openapi.Types{openapi.TypeObject}
+		} else {
+			return e.wellKnown(typeValue), false
+		}
+	}
+	var typ string
+	switch types[0] {
+	case openapi.TypeString:
+		typ = e.format(s.Format, "string")
+	case openapi.TypeInteger:
+		typ = e.format(s.Format, "int64")
+	case openapi.TypeNumber:
+		typ = e.format(s.Format, "double")
+	case openapi.TypeBoolean:
+		typ = "bool"
+	case openapi.TypeArray:
+		item, repeated := e.fieldType(s.Items, name, parent)
+		if repeated || strings.HasPrefix(item, "map<") {
+			// repeated fields of repeated fields or maps are not permitted
+			return e.wellKnown(typeListValue), false
+		}
+		return item, true
+	case openapi.TypeObject:
+		return e.objectType(s, name, parent), false
+	default:
+		return e.wellKnown(typeValue), false
+	}
+	if e.opts.Wrappers && s.IsNullable() {
+		if w, ok := wrappers[typ]; ok {
+			return e.wellKnown(w), false
+		}
+	}
+	return e.wellKnown(typ), false
+}
+
+func (e *exporter) objectType(s *openapi.Schema, name openapi.Text, parent *message) string {
+	if s.Properties == nil || len(s.Properties.Items) == 0 {
+		if s.AdditionalProperties == nil {
+			return e.wellKnown(typeStruct)
+		}
+		v, repeated := e.fieldType(s.AdditionalProperties, name.Append("Value"), parent)
+		if repeated || strings.HasPrefix(v, "map<") {
+			return e.wellKnown(typeStruct)
+		}
+		return "map<string, " + v + ">"
+	}
+	n := newMessage(e.reserve(parent.taken, e.opts.MessageName(name)), s.Description)
+	parent.nested = append(parent.nested, n)
+	e.addProperties(n, s)
+	return n.name
+}
+
+func (e *exporter) format(format openapi.Text, def string) string {
+	if t, ok := e.opts.Formats[format]; ok {
+		return t
+	}
+	if t, ok := DefaultFormats[format]; ok {
+		if isScalar(t) && isScalar(def) && isNumeric(t) != isNumeric(def) {
+			// e.g. an integer with a format of "byte"
+			return def
+		}
+		return t
+	}
+	return def
+}
+
+// wellKnown records the import of typ, if it is a well-known type, and
+// returns typ.
+func (e *exporter) wellKnown(typ string) string {
+	if imp, ok := wellKnownImports[typ]; ok {
+		e.imports[imp] = true
+	}
+	return typ
+}
+
+func (e *exporter) enumValues(name string, enum openapi.Enum) []string {
+	prefix := openapi.Text(name).ToScreamingSnake().String() + "_"
+	taken := map[string]bool{}
+	values := []string{e.reserve(taken, prefix+"UNSPECIFIED")}
+	texts, _ := enum.Texts()
+	for _, v := range texts {
+		v = v.ToScreamingSnake()
+		if v == "" || v == "UNSPECIFIED" {
+			continue
+		}
+		values = append(values, e.reserve(taken, prefix+v.String()))
+	}
+	return values
+}
+
+func (e *exporter) exportOperations() error {
+	for _, op := range e.doc.Operations() {
+		if op.Webhook || op.Operation == nil {
+			continue
+		}
+		name := e.reserve(e.taken, e.opts.RPCName(op))
+		r := rpc{
+			name:       name,
+			comment:    op.Operation.Summary,
+			deprecated: op.Operation.Deprecated,
+		}
+		if r.comment == "" {
+			r.comment = op.Operation.Description
+		}
+		req, err := e.request(op, name)
+		if err != nil {
+			return err
+		}
+		r.request = req
+		r.response = e.response(op, name)
+		e.rpcs = append(e.rpcs, r)
+	}
+	return nil
+}
+
+func (e *exporter) request(op openapi.OperationEntry, name string) (string, error) {
+	m := newMessage(e.reserve(e.taken, name+"Request"), "")
+	params, err := op.Operation.EffectiveParameters(op.PathItem)
+	if err != nil {
+		return "", fmt.Errorf("proto: failed to export %s %s: %w", strings.ToUpper(op.Method.String()), op.Key, err)
+	}
+	for _, p := range params {
+		if p.Schema == nil {
+			continue
+		}
+		e.addField(m, p.Name, p.Schema, p.Description)
+		m.fields[len(m.fields)-1].deprecated = p.Deprecated
+	}
+	if rb := op.Operation.RequestBody; rb != nil && rb.Object != nil {
+		if s := contentSchema(rb.Object.Content); s != nil {
+			e.addField(m, "body", s, rb.Object.Description)
+		}
+	}
+	e.messages = append(e.messages, m)
+	return m.name, nil
+}
+
+func (e *exporter) response(op openapi.OperationEntry, name string) string {
+	var res *openapi.Response
+	if op.Operation.Responses != nil {
+		for _, item := range op.Operation.Responses.Items {
+			if strings.HasPrefix(item.Key.String(), "2") && item.Component != nil && item.Component.Object != nil {
+				res = item.Component.Object
+				break
+			}
+		}
+	}
+	var s *openapi.Schema
+	if res != nil {
+		s = contentSchema(res.Content)
+	}
+	if s == nil {
+		return e.wellKnown(typeEmpty)
+	}
+	if s.Ref != nil && s.Ref.Resolved != nil {
+		if n, ok := e.names[s.Ref.Resolved]; ok && !isStringEnum(s.Ref.Resolved) {
+			return n
+		}
+	}
+	m := newMessage(e.reserve(e.taken, name+"Response"), res.Description)
+	e.addField(m, "value", s, "")
+	e.messages = append(e.messages, m)
+	return m.name
+}
+
+// contentSchema returns the Schema of the JSON media type of content or, if
+// there is not one, the first media type with a Schema.
+func contentSchema(content *openapi.ContentMap) *openapi.Schema {
+	if content == nil {
+		return nil
+	}
+	var first *openapi.Schema
+	for _, item := range content.Items {
+		if item.Value == nil || item.Value.Schema == nil {
+			continue
+		}
+		mt := item.Key.String()
+		if mt == "application/json" || strings.HasSuffix(mt, "+json") {
+			return item.Value.Schema
+		}
+		if first == nil {
+			first = item.Value.Schema
+		}
+	}
+	return first
+}
+
+func isScalar(typ string) bool {
+	switch typ {
+	case "string", "bytes", "bool", "int32", "int64", "uint32", "uint64", "float", "double":
+		return true
+	default:
+		return false
+	}
+}
+
+func isNumeric(typ string) bool {
+	return isScalar(typ) && typ != "string" && typ != "bytes" && typ != "bool"
+}
+
+func (e *exporter) render() []byte {
+	b := &bytes.Buffer{}
+	b.WriteString("syntax = \"proto3\";\n\n")
+	fmt.Fprintf(b, "package %s;\n", e.opts.Package)
+	if len(e.imports) > 0 {
+		b.WriteByte('\n')
+		imports := make([]string, 0, len(e.imports))
+		for imp := range e.imports {
+			imports = append(imports, imp)
+		}
+		sort.Strings(imports)
+		for _, imp := range imports {
+			fmt.Fprintf(b, "import %q;\n", imp)
+		}
+	}
+	if e.opts.GoPackage != "" {
+		fmt.Fprintf(b, "\noption go_package = %q;\n", e.opts.GoPackage)
+	}
+	if len(e.rpcs) > 0 {
+		b.WriteByte('\n')
+		fmt.Fprintf(b, "service %s {\n", e.opts.Service)
+		for i, r := range e.rpcs {
+			if i > 0 {
+				b.WriteByte('\n')
+			}
+			writeComment(b, "  ", r.comment)
+			fmt.Fprintf(b, "  rpc %s(%s) returns (%s)", r.name, r.request, r.response)
+			if r.deprecated {
+				b.WriteString(" {\n    option deprecated = true;\n  }\n")
+			} else {
+				b.WriteString(";\n")
+			}
+		}
+		b.WriteString("}\n")
+	}
+	for _, m := range e.messages {
+		b.WriteByte('\n')
+		writeMessage(b, "", m)
+	}
+	return b.Bytes()
+}
+
+func writeMessage(b *bytes.Buffer, indent string, m *message) {
+	writeComment(b, indent, m.comment)
+	if m.isEnum() {
+		fmt.Fprintf(b, "%senum %s {\n", indent, m.name)
+		if m.deprecated {
+			fmt.Fprintf(b, "%s  option deprecated = true;\n", indent)
+		}
+		for i, v := range m.values {
+			fmt.Fprintf(b, "%s  %s = %d;\n", indent, v, i)
+		}
+		fmt.Fprintf(b, "%s}\n", indent)
+		return
+	}
+	fmt.Fprintf(b, "%smessage %s {\n", indent, m.name)
+	if m.deprecated {
+		fmt.Fprintf(b, "%s  option deprecated = true;\n", indent)
+	}
+	fi := indent + "  "
+	if m.oneof {
+		fmt.Fprintf(b, "%soneof value {\n", fi)
+		fi += "  "
+	}
+	for _, f := range m.fields {
+		writeComment(b, fi, f.comment)
+		b.WriteString(fi)
+		switch {
+		case f.repeated:
+			b.WriteString("repeated ")
+		case f.optional:
+			b.WriteString("optional ")
+		}
+		fmt.Fprintf(b, "%s %s = %d", f.typ, f.name, f.number)
+		if f.deprecated {
+			b.WriteString(" [deprecated = true]")
+		}
+		b.WriteString(";\n")
+	}
+	if m.oneof {
+		fmt.Fprintf(b, "%s  }\n", indent)
+	}
+	for _, n := range m.nested {
+		b.WriteByte('\n')
+		writeMessage(b, indent+"  ", n)
+	}
+	fmt.Fprintf(b, "%s}\n", indent)
+}
+
+func writeComment(b *bytes.Buffer, indent string, comment openapi.Text) {
+	comment = comment.TrimSpace()
+	if comment == "" {
+		return
+	}
+	for _, line := range strings.Split(comment.String(), "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		if line == "" {
+			fmt.Fprintf(b, "%s//\n", indent)
+			continue
+		}
+		fmt.Fprintf(b, "%s// %s\n", indent, line)
+	}
+}
@@ -0,0 +1,140 @@
+package proto_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/chanced/openapi"
+	"github.com/chanced/openapi/proto"
+	"github.com/chanced/uri"
+)
+
+func TestExport(t *testing.T) {
+	data := []byte(`{
+		"openapi": "3.1.0",
+		"info": { "title": "Pet Store", "version": "1.0.0" },
+		"paths": {
+			"/pets/{petId}": {
+				"parameters": [
+					{ "name": "petId", "in": "path", "required": true, "schema": { "type": "string", "format": "uuid" } }
+				],
+				"get": {
+					"operationId": "getPet",
+					"summary": "Returns a pet",
+					"responses": {
+						"200": {
+							"description": "ok",
+							"content": {
+								"application/json": { "schema": { "$ref": "#/components/schemas/Pet" } }
+							}
+						}
+					}
+				},
+				"delete": {
+					"responses": {
+						"204": { "description": "deleted" }
+					}
+				}
+			},
+			"/pets": {
+				"get": {
+					"operationId": "list_pets",
+					"parameters": [
+						{ "name": "limit", "in": "query", "schema": { "type": "integer", "format": "int32" } }
+					],
+					"responses": {
+						"200": {
+							"description": "ok",
+							"content": {
+								"application/json": {
+									"schema": { "type": "array", "items": { "$ref": "#/components/schemas/Pet" } }
+								}
+							}
+						}
+					}
+				}
+			}
+		},
+		"components": {
+			"schemas": {
+				"Pet": {
+					"type": "object",
+					"description": "A pet",
+					"required": ["name"],
+					"properties": {
+						"name": { "type": "string" },
+						"nickName": { "type": ["string", "null"] },
+						"status": { "$ref": "#/components/schemas/PetStatus" },
+						"born": { "type": "string", "format": "date-time" },
+						"tags": { "type": "array", "items": { "type": "string" } },
+						"owner": {
+							"type": "object",
+							"properties": { "id": { "type": "integer" } }
+						},
+						"attributes": { "type": "object", "additionalProperties": { "type": "number" } },
+						"extra": {}
+					}
+				},
+				"PetStatus": { "type": "string", "enum": ["available", "sold"] }
+			}
+		}
+	}`)
+	fn := func(ctx context.Context, u uri.URI, kind openapi.Kind) (openapi.Kind, []byte, error) {
+		return openapi.KindDocument, data, nil
+	}
+	v, err := openapi.NewOfflineValidator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := openapi.Load(context.Background(), "https://example.com/openapi.json", v, fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := proto.Export(doc, proto.Options{
+		GoPackage: "example.com/petstore",
+		Formats:   map[openapi.Text]string{"uuid": "bytes"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := string(out)
+	for _, expected := range []string{
+		`syntax = "proto3";`,
+		"package pet_store;",
+		`import "google/protobuf/empty.proto";`,
+		`import "google/protobuf/struct.proto";`,
+		`import "google/protobuf/timestamp.proto";`,
+		`option go_package = "example.com/petstore";`,
+		"service PetStoreService {",
+		"  // Returns a pet\n  rpc GetPet(GetPetRequest) returns (Pet);",
+		"  rpc DeletePetsPetID(DeletePetsPetIDRequest) returns (google.protobuf.Empty);",
+		"  rpc ListPets(ListPetsRequest) returns (ListPetsResponse);",
+		"// A pet\nmessage Pet {",
+		"  string name = 1;",
+		"  optional string nick_name = 2;",
+		"  PetStatus status = 3;",
+		"  google.protobuf.Timestamp born = 4;",
+		"  repeated string tags = 5;",
+		"  Owner owner = 6;",
+		"  map<string, double> attributes = 7;",
+		"  google.protobuf.Value extra = 8;",
+		"  message Owner {\n    int64 id = 1;\n  }",
+		"enum PetStatus {\n  PET_STATUS_UNSPECIFIED = 0;\n  PET_STATUS_AVAILABLE = 1;\n  PET_STATUS_SOLD = 2;\n}",
+		"message GetPetRequest {\n  bytes pet_id = 1;\n}",
+		"message ListPetsRequest {\n  int32 limit = 1;\n}",
+		"message ListPetsResponse {\n  repeated Pet value = 1;\n}",
+	} {
+		if !strings.Contains(res, expected) {
+			t.Errorf("expected output to contain:\n%s\n\ngot:\n%s", expected, res)
+		}
+	}
+
+	out, err = proto.Export(doc, proto.Options{Wrappers: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), "google.protobuf.StringValue nick_name = 2;") {
+		t.Errorf("expected nullable string to be wrapped:\n%s", out)
+	}
+}
@@ -0,0 +1,70 @@
+package openapi
+
+import "strings"
+
+// Provenance returns the Location of r followed by the Location of each
+// reference subsequently followed to reach the node r ultimately resolves to
+// and, last, the Location of that node. A resolved Schema which has a $ref
+// is followed through its $ref.
+//
+// If a reference of the chain is not resolved, the chain ends with its
+// Location. If the chain is cyclic, it ends with the first reference which
+// is repeated.
+func (r *Reference[T]) Provenance() []Location { return provenance(r) }
+
+// Provenance returns the chain of Locations followed to reach the Schema sr
+// ultimately resolves to. See Reference.Provenance.
+func (sr *SchemaRef) Provenance() []Location { return provenance(sr) }
+
+// Provenance returns the Location of or followed by the Location of the
+// Operation it resolves to, if resolved.
+func (or *OperationRef) Provenance() []Location { return provenance(or) }
+
+func provenance(r Ref) []Location {
+	var locs []Location
+	seen := map[Ref]bool{}
+	for r != nil && !seen[r] {
+		seen[r] = true
+		n, ok := r.(node)
+		if !ok || n.isNil() {
+			break
+		}
+		locs = append(locs, n.location())
+		res, ok := r.ResolvedNode().(node)
+		if !ok || res.isNil() {
+			break
+		}
+		next := chainedRef(res)
+		if next == nil {
+			return append(locs, res.location())
+		}
+		r = next
+	}
+	return locs
+}
+
+// chainedRef returns the reference through which n is defined, if any
+func chainedRef(n node) Ref {
+	if s, ok := n.(*Schema); ok && s.Ref != nil {
+		return s.Ref
+	}
+	return nil
+}
+
+// FormatProvenance describes a chain of Locations returned from Provenance
+// (e.g. "common.yaml#/components/schemas/Pet via
+// petstore.yaml#/components/schemas/Pet/$ref"), naming the defining
+// Location first followed by the references through which it was reached,
+// nearest first.
+func FormatProvenance(locs []Location) string {
+	if len(locs) == 0 {
+		return ""
+	}
+	b := strings.Builder{}
+	b.WriteString(locs[len(locs)-1].String())
+	for i := len(locs) - 2; i >= 0; i-- {
+		b.WriteString(" via ")
+		b.WriteString(locs[i].String())
+	}
+	return b.String()
+}
@@ -0,0 +1,99 @@
+package openapi_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/chanced/openapi"
+	"github.com/chanced/uri"
+)
+
+func TestProvenance(t *testing.T) {
+	files := map[string][]byte{
+		"https://example.com/petstore.json": []byte(`{
+			"openapi": "3.1.0",
+			"info": { "title": "Pet Store", "version": "1.0.0" },
+			"paths": {
+				"/pets": {
+					"get": {
+						"responses": {
+							"200": { "$ref": "#/components/responses/Pet" }
+						}
+					}
+				}
+			},
+			"components": {
+				"responses": {
+					"Pet": {
+						"description": "a pet",
+						"content": {
+							"application/json": { "schema": { "$ref": "#/components/schemas/Pet" } }
+						}
+					}
+				},
+				"schemas": {
+					"Pet": { "$ref": "common.json#/components/schemas/Pet" }
+				}
+			}
+		}`),
+		"https://example.com/common.json": []byte(`{
+			"openapi": "3.1.0",
+			"info": { "title": "Common", "version": "1.0.0" },
+			"components": {
+				"schemas": {
+					"Pet": { "type": "object" }
+				}
+			}
+		}`),
+	}
+	fn := func(ctx context.Context, u uri.URI, kind openapi.Kind) (openapi.Kind, []byte, error) {
+		data, ok := files[u.String()]
+		if !ok {
+			return openapi.KindUndefined, nil, fmt.Errorf("not found: %s", u)
+		}
+		return openapi.KindDocument, data, nil
+	}
+	doc, err := openapi.Load(context.Background(), "https://example.com/petstore.json", NoopValidator{}, fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := doc.Paths.Get("/pets").Get.Responses.Get("200")
+	if !res.IsReference() {
+		t.Fatal("expected the response to be a reference")
+	}
+	locs := res.Reference.Provenance()
+	expected := []string{
+		"https://example.com/petstore.json#/paths/~1pets/get/responses/200",
+		"https://example.com/petstore.json#/components/responses/Pet",
+	}
+	assertLocations(t, locs, expected)
+
+	schema := res.Object.Content.Get("application/json").Schema
+	locs = schema.Ref.Provenance()
+	expected = []string{
+		"https://example.com/petstore.json#/components/responses/Pet/content/application~1json/schema/$ref",
+		"https://example.com/petstore.json#/components/schemas/Pet/$ref",
+		"https://example.com/common.json#/components/schemas/Pet",
+	}
+	assertLocations(t, locs, expected)
+
+	expectedMsg := "https://example.com/common.json#/components/schemas/Pet via " +
+		"https://example.com/petstore.json#/components/schemas/Pet/$ref via " +
+		"https://example.com/petstore.json#/components/responses/Pet/content/application~1json/schema/$ref"
+	if msg := openapi.FormatProvenance(locs); msg != expectedMsg {
+		t.Errorf("expected %q, got %q", expectedMsg, msg)
+	}
+}
+
+func assertLocations(t *testing.T, locs []openapi.Location, expected []string) {
+	t.Helper()
+	if len(locs) != len(expected) {
+		t.Fatalf("expected %d locations, got %d: %v", len(expected), len(locs), locs)
+	}
+	for i, l := range locs {
+		if l.String() != expected[i] {
+			t.Errorf("expected location %d to be %q, got %q", i, expected[i], l.String())
+		}
+	}
+}
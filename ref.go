@@ -39,6 +39,10 @@ type Ref interface {
 	RefKind() Kind
 	// RefType returns the RefType for the reference
 	RefType() RefType
+	// Provenance returns the Locations of the chain of references followed
+	// to reach the node the reference ultimately resolves to, ending with
+	// the Location of that node.
+	Provenance() []Location
 }
 
 type ref interface {
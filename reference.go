@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"reflect"
 
-	"github.com/chanced/transcode"
 	"github.com/chanced/uri"
 	"github.com/tidwall/gjson"
 	"gopkg.in/yaml.v3"
@@ -69,6 +68,13 @@ func (r *Reference[T]) nodes() []node {
 }
 func (r *Reference[T]) RefKind() Kind { return r.ReferencedKind }
 
+// objectKind returns the Kind of T, which is the ReferencedKind of References
+// which are unmarshaled rather than constructed
+func (*Reference[T]) objectKind() Kind {
+	var t T
+	return t.Kind()
+}
+
 func (r *Reference[T]) URI() *uri.URI {
 	if r == nil {
 		return nil
@@ -164,11 +170,7 @@ func (r Reference[T]) MarshalYAML() (interface{}, error) {
 
 // UnmarshalYAML satisfies gopkg.in/yaml.v3 Unmarshaler interface
 func (r *Reference[T]) UnmarshalYAML(value *yaml.Node) error {
-	v, err := yaml.Marshal(value)
-	if err != nil {
-		return err
-	}
-	j, err := transcode.JSONFromYAML(v)
+	j, err := jsonFromYAMLNode(value)
 	if err != nil {
 		return err
 	}
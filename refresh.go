@@ -0,0 +1,241 @@
+package openapi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/chanced/uri"
+)
+
+// ResourceVersion is the version of a resource as reported by its origin
+// (e.g. by the ETag and Last-Modified headers of an HTTP response). It is
+// used to make conditional requests when refreshing a Document. See
+// Document.Refresh.
+type ResourceVersion struct {
+	ETag         string
+	LastModified time.Time
+}
+
+// IsZero reports whether v is empty.
+func (v ResourceVersion) IsZero() bool {
+	return v.ETag == "" && v.LastModified.IsZero()
+}
+
+// ResourceVersionFromHeader returns the ResourceVersion indicated by the ETag
+// and Last-Modified headers of h.
+func ResourceVersionFromHeader(h http.Header) ResourceVersion {
+	v := ResourceVersion{ETag: h.Get("ETag")}
+	if lm := h.Get("Last-Modified"); lm != "" {
+		v.LastModified, _ = http.ParseTime(lm)
+	}
+	return v
+}
+
+// SetConditionalHeaders sets the If-None-Match and If-Modified-Since headers
+// of h according to v.
+func (v ResourceVersion) SetConditionalHeaders(h http.Header) {
+	if v.ETag != "" {
+		h.Set("If-None-Match", v.ETag)
+	}
+	if !v.LastModified.IsZero() {
+		h.Set("If-Modified-Since", v.LastModified.UTC().Format(http.TimeFormat))
+	}
+}
+
+type fetchStateKey struct{}
+
+// fetchState is carried by the context passed to the fn of Load
+type fetchState struct {
+	// conditional indicates that fn was called by Document.Refresh
+	conditional bool
+	// previous is the version of the resource which was last loaded
+	previous ResourceVersion
+	// version is the version reported by fn with SetResourceVersion
+	version ResourceVersion
+}
+
+func withFetchState(ctx context.Context, fs *fetchState) context.Context {
+	return context.WithValue(ctx, fetchStateKey{}, fs)
+}
+
+// ConditionalRequest returns the version of the resource last loaded, if the
+// fn passed to Load is called by Document.Refresh to re-check a resource. fn
+// should make a conditional request (see
+// ResourceVersion.SetConditionalHeaders) and return an error wrapping
+// ErrNotModified if the resource has not changed.
+func ConditionalRequest(ctx context.Context) (ResourceVersion, bool) {
+	fs, ok := ctx.Value(fetchStateKey{}).(*fetchState)
+	if !ok || !fs.conditional {
+		return ResourceVersion{}, false
+	}
+	return fs.previous, true
+}
+
+// SetResourceVersion records the version of the resource being fetched, as
+// reported by its origin, for use in later conditional requests (see
+// ConditionalRequest). It should be called by the fn passed to Load; it has
+// no effect if ctx was not passed to fn by Load or Document.Refresh.
+func SetResourceVersion(ctx context.Context, v ResourceVersion) {
+	if fs, ok := ctx.Value(fetchStateKey{}).(*fetchState); ok {
+		fs.version = v
+	}
+}
+
+// loadSource is retained by a Document loaded with LoadOpts.Refreshable
+type loadSource struct {
+	uri       uri.URI
+	validator Validator
+	fn        func(context.Context, uri.URI, Kind) (Kind, []byte, error)
+	opts      LoadOpts
+	// resources are the resources fetched by Load, beginning with the
+	// Document itself
+	resources []*loadedResource
+}
+
+type loadedResource struct {
+	uri     uri.URI
+	kind    Kind
+	data    []byte
+	version ResourceVersion
+}
+
+// RefreshResult is the result of Document.Refresh.
+type RefreshResult struct {
+	// Resources are the URIs of the external resources which changed.
+	Resources []uri.URI
+	// Refs are the references of the Document which were resolved anew
+	// because their targets are within a changed resource.
+	Refs []Ref
+}
+
+// Changed reports whether any resource changed.
+func (r *RefreshResult) Changed() bool {
+	return r != nil && len(r.Resources) > 0
+}
+
+// Refresh re-checks each external resource of d by calling the fn passed to
+// Load, which may make a conditional request with the version of the
+// resource last loaded (see ConditionalRequest) and return ErrNotModified.
+// Resources whose data is unchanged are also considered not modified. The
+// Document itself is not re-checked.
+//
+// If any resource changed, the Document is loaded again, using the retained
+// data of unchanged resources, and validated. Only the references of d whose
+// targets are within a changed resource are then updated to the nodes of the
+// newly loaded resources; the remainder of d is left intact. If loading or
+// validation fails, d is not modified.
+//
+// Refresh returns an error wrapping ErrNotRefreshable if d was not loaded with
+// LoadOpts.Refreshable. Refresh is not safe for concurrent use with other
+// access to d.
+func (d *Document) Refresh(ctx context.Context) (*RefreshResult, error) {
+	src := d.source
+	if src == nil || len(src.resources) == 0 {
+		return nil, ErrNotRefreshable
+	}
+	res := &RefreshResult{}
+	changed := map[string]bool{}
+	resources := make([]*loadedResource, len(src.resources))
+	resources[0] = src.resources[0]
+	for i, r := range src.resources[1:] {
+		i++
+		if err := checkContext(ctx, r.uri); err != nil {
+			return nil, err
+		}
+		fs := fetchState{conditional: true, previous: r.version}
+		k, data, err := src.fn(withFetchState(ctx, &fs), r.uri, r.kind)
+		if errors.Is(err, ErrNotModified) {
+			resources[i] = r
+			continue
+		}
+		if err != nil {
+			return nil, NewError(fmt.Errorf("openapi: failed to refresh resource: %w", err), r.uri)
+		}
+		if k == KindUndefined {
+			k = r.kind
+		}
+		resources[i] = &loadedResource{uri: r.uri, kind: k, data: data, version: fs.version}
+		if !bytes.Equal(data, r.data) {
+			changed[r.uri.String()] = true
+			res.Resources = append(res.Resources, r.uri)
+		}
+	}
+	if len(changed) == 0 {
+		src.resources = resources
+		return res, nil
+	}
+
+	retained := make(map[string]*loadedResource, len(resources))
+	for _, r := range resources {
+		retained[r.uri.String()] = r
+	}
+	fn := func(ctx context.Context, u uri.URI, k Kind) (Kind, []byte, error) {
+		if r, ok := retained[u.String()]; ok {
+			SetResourceVersion(ctx, r.version)
+			return r.kind, r.data, nil
+		}
+		// changed resources may reference resources not previously loaded
+		return src.fn(ctx, u, k)
+	}
+	opts := src.opts
+	opts.Cache = nil
+	opts.Profile = nil
+	nd, err := Load(ctx, src.uri.String(), src.validator, fn, opts)
+	if err != nil {
+		return nil, err
+	}
+	if res.Refs, err = d.graft(nd, changed); err != nil {
+		return nil, err
+	}
+	nd.source.fn = src.fn
+	d.source = nd.source
+	return res, nil
+}
+
+// graft resolves each reference of d whose target is within a changed
+// resource to the target of the reference at the same location in nd.
+// References located within changed resources are discarded along with
+// them.
+func (d *Document) graft(nd *Document, changed map[string]bool) ([]Ref, error) {
+	refs := map[string]ref{}
+	walkNodes(nd, func(n node) error {
+		if r, ok := n.(ref); ok {
+			refs[r.AbsoluteLocation().String()] = r
+		}
+		return nil
+	})
+	var stale []ref
+	walkNodes(d, func(n node) error {
+		r, ok := n.(ref)
+		if !ok || !r.IsResolved() || changed[resourceURI(r.AbsoluteLocation())] {
+			return nil
+		}
+		if changed[resourceURI(r.ResolvedNode().AbsoluteLocation())] {
+			stale = append(stale, r)
+		}
+		return nil
+	})
+	res := make([]Ref, len(stale))
+	for i, r := range stale {
+		nr, ok := refs[r.AbsoluteLocation().String()]
+		if !ok || !nr.IsResolved() {
+			return nil, newRefNotFoundError(r)
+		}
+		if err := r.resolve(nr.ResolvedNode()); err != nil {
+			return nil, err
+		}
+		res[i] = r
+	}
+	return res, nil
+}
+
+// resourceURI returns u, without its fragment, as a string
+func resourceURI(u uri.URI) string {
+	u.Fragment = ""
+	u.RawFragment = ""
+	return u.String()
+}
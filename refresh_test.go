@@ -0,0 +1,91 @@
+package openapi_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/chanced/openapi"
+	"github.com/chanced/uri"
+)
+
+func TestRefresh(t *testing.T) {
+	doc := []byte(`{
+		"openapi": "3.1.0",
+		"info": { "title": "refresh", "version": "1.0.0" },
+		"paths": {},
+		"components": {
+			"schemas": {
+				"Pet": { "$ref": "https://upstream.example.com/pet.json" },
+				"Owner": { "$ref": "https://upstream.example.com/owner.json" }
+			}
+		}
+	}`)
+	resources := map[string][]byte{
+		"https://example.com/refresh.json":        doc,
+		"https://upstream.example.com/pet.json":   []byte(`{ "type": "object", "properties": { "name": { "type": "string" } } }`),
+		"https://upstream.example.com/owner.json": []byte(`{ "type": "object" }`),
+	}
+	revisions := map[string]int{}
+	conditional := 0
+	fn := func(ctx context.Context, u uri.URI, kind openapi.Kind) (openapi.Kind, []byte, error) {
+		etag := fmt.Sprintf(`"%d"`, revisions[u.String()])
+		if prev, ok := openapi.ConditionalRequest(ctx); ok {
+			conditional++
+			if prev.ETag == etag {
+				return 0, nil, openapi.ErrNotModified
+			}
+		}
+		openapi.SetResourceVersion(ctx, openapi.ResourceVersion{ETag: etag})
+		if u.Host == "upstream.example.com" {
+			return openapi.KindSchema, resources[u.String()], nil
+		}
+		return openapi.KindDocument, resources[u.String()], nil
+	}
+	d, err := openapi.Load(context.Background(), "https://example.com/refresh.json", NoopValidator{}, fn, openapi.LoadOpts{Refreshable: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	owner := d.Components.Schemas.Get("Owner").Ref.Resolved
+
+	res, err := d.Refresh(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Changed() || conditional != 2 {
+		t.Fatalf("expected 2 conditional requests and no changes, got %d and %v", conditional, res.Resources)
+	}
+
+	resources["https://upstream.example.com/pet.json"] = []byte(`{ "type": "object", "properties": { "name": { "type": "string" }, "age": { "type": "integer" } } }`)
+	revisions["https://upstream.example.com/pet.json"]++
+	res, err = d.Refresh(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Resources) != 1 || res.Resources[0].String() != "https://upstream.example.com/pet.json" {
+		t.Fatalf("expected pet.json to have changed, got %v", res.Resources)
+	}
+	if len(res.Refs) != 1 || res.Refs[0].AbsoluteLocation().Fragment != "/components/schemas/Pet/$ref" {
+		t.Errorf("expected the Pet reference to be updated, got %v", res.Refs)
+	}
+	if d.Components.Schemas.Get("Pet").Ref.Resolved.Properties.Get("age") == nil {
+		t.Error("expected Pet to have been updated")
+	}
+	if d.Components.Schemas.Get("Owner").Ref.Resolved != owner {
+		t.Error("expected Owner to be left intact")
+	}
+
+	conditional = 0
+	if res, err = d.Refresh(context.Background()); err != nil || res.Changed() || conditional != 2 {
+		t.Errorf("expected the new version of pet.json to be retained, got %v, %v, %d", res, err, conditional)
+	}
+
+	d, err = openapi.Load(context.Background(), "https://example.com/refresh.json", NoopValidator{}, fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Refresh(context.Background()); !errors.Is(err, openapi.ErrNotRefreshable) {
+		t.Errorf("expected ErrNotRefreshable, got %v", err)
+	}
+}
@@ -2,12 +2,143 @@ package openapi
 
 import (
 	"encoding/json"
+	"fmt"
 	"regexp"
+	"strconv"
+	"strings"
 )
 
+// PatternMode determines how the pattern of a Schema is compiled when it is
+// loaded.
+//
+// JSON Schema patterns are ECMA-262 regular expressions while Go's regexp
+// package implements RE2. Most patterns are interpreted identically by both,
+// but some ECMA-262 constructs, such as lookarounds and backreferences, are
+// not supported by RE2 while others, such as \u0041, are written
+// differently.
+type PatternMode uint8
+
+const (
+	// PatternRE2 compiles patterns as written with Go's regexp package. A
+	// pattern which fails to compile is an error.
+	PatternRE2 PatternMode = iota
+	// PatternECMA interprets patterns as ECMA-262 regular expressions,
+	// translating them to RE2 before compiling them. A pattern which uses
+	// constructs RE2 does not support is an error.
+	PatternECMA
+	// PatternLenient compiles patterns as written if possible, then as
+	// ECMA-262. A pattern which can not be compiled either way is retained
+	// without being compiled (see Regexp.IsCompiled).
+	PatternLenient
+)
+
+var patternModeNames = [...]string{
+	PatternRE2:     "re2",
+	PatternECMA:    "ecma",
+	PatternLenient: "lenient",
+}
+
+func (m PatternMode) String() string {
+	if int(m) < len(patternModeNames) {
+		return patternModeNames[m]
+	}
+	return "PatternMode(" + strconv.Itoa(int(m)) + ")"
+}
+
 // Regexp is a wrapper around *regexp.Regexp to allow for marshinaling/unmarshaling
+//
+// The pattern is retained as written so that it is marshaled unchanged, even
+// if it was translated from ECMA-262 or could not be compiled, in which case
+// the embedded *regexp.Regexp is nil.
 type Regexp struct {
 	*regexp.Regexp
+	source string
+}
+
+// CompilePattern compiles the pattern expr according to mode. Errors wrap
+// ErrInvalidPattern.
+func CompilePattern(expr string, mode PatternMode) (*Regexp, error) {
+	switch mode {
+	case PatternRE2:
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPattern, err)
+		}
+		return &Regexp{Regexp: re, source: expr}, nil
+	case PatternECMA:
+		translated, _, err := translateECMAPattern(expr)
+		if err != nil {
+			return nil, err
+		}
+		re, err := regexp.Compile(translated)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPattern, err)
+		}
+		return &Regexp{Regexp: re, source: expr}, nil
+	case PatternLenient:
+		for _, m := range []PatternMode{PatternRE2, PatternECMA} {
+			if re, err := CompilePattern(expr, m); err == nil {
+				return re, nil
+			}
+		}
+		return &Regexp{source: expr}, nil
+	default:
+		return nil, fmt.Errorf("openapi: unknown pattern mode %s", mode)
+	}
+}
+
+// TranslateECMAPattern translates the ECMA-262 regular expression expr to
+// the equivalent RE2 syntax of Go's regexp package. Errors wrap
+// ErrInvalidPattern.
+func TranslateECMAPattern(expr string) (string, error) {
+	res, _, err := translateECMAPattern(expr)
+	return res, err
+}
+
+// CheckPatternPortability returns an error wrapping ErrNonPortablePattern
+// if expr is not interpreted identically as an ECMA-262 regular expression
+// and by Go's regexp package.
+func CheckPatternPortability(expr string) error {
+	_, issues, err := translateECMAPattern(expr)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrNonPortablePattern, err)
+	}
+	if _, err = regexp.Compile(expr); err != nil {
+		return fmt.Errorf("%w: %v", ErrNonPortablePattern, err)
+	}
+	if len(issues) > 0 {
+		return fmt.Errorf("%w: %s", ErrNonPortablePattern, strings.Join(issues, "; "))
+	}
+	return nil
+}
+
+// Source returns the pattern as written.
+func (sr Regexp) Source() string {
+	if sr.source == "" && sr.Regexp != nil {
+		return sr.Regexp.String()
+	}
+	return sr.source
+}
+
+// String returns the pattern as written.
+func (sr Regexp) String() string { return sr.Source() }
+
+// IsCompiled reports whether the pattern was compiled. Patterns loaded with
+// PatternLenient may not be.
+func (sr *Regexp) IsCompiled() bool {
+	return sr != nil && sr.Regexp != nil
+}
+
+// Clone returns a copy of sr.
+func (sr *Regexp) Clone() *Regexp {
+	if sr == nil {
+		return nil
+	}
+	c := &Regexp{source: sr.source}
+	if sr.Regexp != nil {
+		c.Regexp = sr.Regexp.Copy()
+	}
+	return c
 }
 
 // MarshalJSON unmarshals data into sr
@@ -15,21 +146,190 @@ func (sr Regexp) MarshalJSON() ([]byte, error) {
 	if sr.IsNil() {
 		return []byte{}, nil
 	}
-	return json.Marshal(sr.Regexp.String())
+	return json.Marshal(sr.Source())
 }
 
-// UnmarshalJSON unmarshals data into sr
+// UnmarshalJSON unmarshals data into sr. The pattern is compiled with
+// PatternLenient; Load enforces LoadOpts.Patterns.
 func (sr *Regexp) UnmarshalJSON(data []byte) error {
 	var expr string
-	var err error
-	if err = json.Unmarshal(data, &expr); err != nil {
+	if err := json.Unmarshal(data, &expr); err != nil {
 		return err
 	}
-	sr.Regexp, err = regexp.Compile(expr)
-	return err
+	re, err := CompilePattern(expr, PatternLenient)
+	if err != nil {
+		return err
+	}
+	*sr = *re
+	return nil
 }
 
-// IsNil returns true if either sr or sr.Regexp is nil
+// IsNil returns true if either sr is nil or sr has neither a pattern nor a
+// compiled *regexp.Regexp
 func (sr *Regexp) IsNil() bool {
-	return sr == nil || sr.Regexp == nil
+	return sr == nil || (sr.Regexp == nil && sr.source == "")
+}
+
+// ecmaWhitespace is the set of characters matched by \s in ECMA-262
+const ecmaWhitespace = `\t\n\v\f\r \x{a0}\x{1680}\x{2000}-\x{200a}\x{2028}\x{2029}\x{202f}\x{205f}\x{3000}\x{feff}`
+
+// translateECMAPattern translates expr from ECMA-262 to RE2, returning a
+// description of each construct of expr which is not portable
+func translateECMAPattern(expr string) (string, []string, error) {
+	var b strings.Builder
+	var issues []string
+	note := func(issue string) {
+		for _, v := range issues {
+			if v == issue {
+				return
+			}
+		}
+		issues = append(issues, issue)
+	}
+	invalid := func(format string, args ...interface{}) (string, []string, error) {
+		return "", nil, fmt.Errorf("%w: %q: %s", ErrInvalidPattern, expr, fmt.Sprintf(format, args...))
+	}
+	inClass := false
+	for i := 0; i < len(expr); i++ {
+		c := expr[i]
+		switch {
+		case c == '\\':
+			i++
+			if i >= len(expr) {
+				return invalid("trailing backslash")
+			}
+			e := expr[i]
+			switch {
+			case e == 'u':
+				var hex string
+				if strings.HasPrefix(expr[i+1:], "{") {
+					end := strings.IndexByte(expr[i:], '}')
+					if end < 0 {
+						return invalid("unterminated \\u{ escape")
+					}
+					hex = expr[i+2 : i+end]
+					i += end
+				} else if i+5 <= len(expr) && isHex(expr[i+1:i+5]) {
+					hex = expr[i+1 : i+5]
+					i += 4
+				} else {
+					return invalid("invalid \\u escape")
+				}
+				if !isHex(hex) {
+					return invalid("invalid \\u escape")
+				}
+				note(`\u escapes are not supported by RE2`)
+				b.WriteString(`\x{` + hex + `}`)
+			case e == 'c':
+				if i+1 >= len(expr) || !isASCIILetter(expr[i+1]) {
+					return invalid("invalid \\c escape")
+				}
+				i++
+				note(`\c escapes are not supported by RE2`)
+				fmt.Fprintf(&b, `\x{%x}`, expr[i]%32)
+			case e == '0':
+				b.WriteString(`\x{0}`)
+			case e >= '1' && e <= '9':
+				return invalid("backreferences are not supported")
+			case e == 'k' && strings.HasPrefix(expr[i+1:], "<"):
+				return invalid("named backreferences are not supported")
+			case e == 'b' && inClass:
+				// a backspace within a character class
+				b.WriteString(`\x{8}`)
+			case e == 's':
+				if inClass {
+					b.WriteString(ecmaWhitespace)
+				} else {
+					b.WriteString("[" + ecmaWhitespace + "]")
+				}
+			case e == 'S':
+				if inClass {
+					return invalid("\\S within a character class is not supported")
+				}
+				b.WriteString("[^" + ecmaWhitespace + "]")
+			case strings.IndexByte(`dDwWbBtnrfvxpP`, e) >= 0:
+				b.WriteByte('\\')
+				b.WriteByte(e)
+			case isASCIILetter(e):
+				// an identity escape; RE2 gives some letters (e.g. \A, \z)
+				// a meaning
+				note(fmt.Sprintf(`\%c is interpreted differently by RE2`, e))
+				b.WriteByte(e)
+			default:
+				b.WriteByte('\\')
+				b.WriteByte(e)
+			}
+		case inClass:
+			switch {
+			case c == ']':
+				inClass = false
+				b.WriteByte(c)
+			case c == '[':
+				if strings.HasPrefix(expr[i+1:], ":") {
+					note(`"[:" within a character class is a POSIX class in RE2`)
+				}
+				b.WriteString(`\[`)
+			default:
+				b.WriteByte(c)
+			}
+		case c == '[':
+			switch {
+			case strings.HasPrefix(expr[i+1:], "]"):
+				// matches nothing
+				b.WriteString(`[^\x{0}-\x{10FFFF}]`)
+				i++
+			case strings.HasPrefix(expr[i+1:], "^]"):
+				// matches anything
+				b.WriteString(`[\x{0}-\x{10FFFF}]`)
+				i += 2
+			default:
+				inClass = true
+				b.WriteByte(c)
+				if strings.HasPrefix(expr[i+1:], "^") {
+					b.WriteByte('^')
+					i++
+				}
+			}
+		case c == '(' && strings.HasPrefix(expr[i+1:], "?"):
+			rest := expr[i+2:]
+			switch {
+			case strings.HasPrefix(rest, ":"):
+				b.WriteString("(?:")
+				i += 2
+			case strings.HasPrefix(rest, "=") || strings.HasPrefix(rest, "!") ||
+				strings.HasPrefix(rest, "<=") || strings.HasPrefix(rest, "<!"):
+				return invalid("lookarounds are not supported")
+			case strings.HasPrefix(rest, "<"):
+				b.WriteString("(?P<")
+				i += 2
+			default:
+				return invalid("unsupported group syntax")
+			}
+		case c == '.':
+			b.WriteString(`[^\n\r\x{2028}\x{2029}]`)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	if inClass {
+		return invalid("unterminated character class")
+	}
+	return b.String(), issues, nil
+}
+
+func isHex(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F') {
+			return false
+		}
+	}
+	return true
+}
+
+func isASCIILetter(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
 }
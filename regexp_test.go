@@ -0,0 +1,98 @@
+package openapi_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/chanced/openapi"
+	"github.com/chanced/uri"
+)
+
+func TestCompilePattern(t *testing.T) {
+	re, err := openapi.CompilePattern(`^A\d+$`, openapi.PatternECMA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !re.MatchString("A12") || re.MatchString("B12") {
+		t.Errorf("unexpected matches for %s", re)
+	}
+	if re.String() != `^A\d+$` {
+		t.Errorf("expected the source to be retained, got %q", re.String())
+	}
+	if _, err = openapi.CompilePattern(`^\u0041$`, openapi.PatternRE2); !errors.Is(err, openapi.ErrInvalidPattern) {
+		t.Errorf("expected ErrInvalidPattern, got %v", err)
+	}
+	if _, err = openapi.CompilePattern(`^(?!admin)\w+$`, openapi.PatternECMA); !errors.Is(err, openapi.ErrInvalidPattern) {
+		t.Errorf("expected ErrInvalidPattern for a lookahead, got %v", err)
+	}
+	re, err = openapi.CompilePattern(`^(?!admin)\w+$`, openapi.PatternLenient)
+	if err != nil || re.IsCompiled() {
+		t.Errorf("expected the pattern to be retained without compilation, got %v, %v", re, err)
+	}
+
+	for expr, expected := range map[string]string{
+		`^(?<year>\d{4})$`: `^(?P<year>\d{4})$`,
+		`a.b`:              `a[^\n\r\x{2028}\x{2029}]b`,
+		`[^]`:              `[\x{0}-\x{10FFFF}]`,
+		`\cJ`:              `\x{a}`,
+	} {
+		if res, err := openapi.TranslateECMAPattern(expr); err != nil || res != expected {
+			t.Errorf("expected %q to translate to %q, got %q, %v", expr, expected, res, err)
+		}
+	}
+
+	if err = openapi.CheckPatternPortability(`^[a-z0-9-]+$`); err != nil {
+		t.Error(err)
+	}
+	for _, expr := range []string{`^(?=a)`, `\z`, `(?P<n>a)`, `[[:alpha:]]`, `\A`, `(a)\1`} {
+		if err = openapi.CheckPatternPortability(expr); !errors.Is(err, openapi.ErrNonPortablePattern) {
+			t.Errorf("expected %q to be non-portable, got %v", expr, err)
+		}
+	}
+}
+
+func TestLoadPatterns(t *testing.T) {
+	data := []byte(`{
+		"openapi": "3.1.0",
+		"info": { "title": "patterns", "version": "1.0.0" },
+		"components": {
+			"schemas": {
+				"Code": { "type": "string", "pattern": "^\\u0041-\\d+$" },
+				"Name": { "type": "string", "pattern": "^(?!admin)[a-z]+$" }
+			}
+		}
+	}`)
+	fn := func(ctx context.Context, u uri.URI, kind openapi.Kind) (openapi.Kind, []byte, error) {
+		return openapi.KindDocument, data, nil
+	}
+	load := func(mode openapi.PatternMode) (*openapi.Document, error) {
+		return openapi.Load(context.Background(), "https://example.com/openapi.json", NoopValidator{}, fn, openapi.LoadOpts{Patterns: mode})
+	}
+	if _, err := load(openapi.PatternRE2); !errors.Is(err, openapi.ErrInvalidPattern) {
+		t.Errorf("expected ErrInvalidPattern with PatternRE2, got %v", err)
+	}
+	if _, err := load(openapi.PatternECMA); !errors.Is(err, openapi.ErrInvalidPattern) {
+		t.Errorf("expected ErrInvalidPattern for a lookahead with PatternECMA, got %v", err)
+	}
+	doc, err := load(openapi.PatternLenient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := doc.Components.Schemas.Get("Code")
+	if !code.Pattern.IsCompiled() || !code.Pattern.MatchString("A-1") {
+		t.Error("expected the Code pattern to be compiled as ECMA-262")
+	}
+	name := doc.Components.Schemas.Get("Name")
+	if name.Pattern.IsCompiled() {
+		t.Error("expected the Name pattern not to be compiled")
+	}
+	b, err := json.Marshal(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"type":"string","pattern":"^(?!admin)[a-z]+$"}` {
+		t.Errorf("expected the pattern to be marshaled as written, got %s", b)
+	}
+}
@@ -0,0 +1,288 @@
+package registry
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/chanced/transcode"
+)
+
+const (
+	contentTypeJSON = "application/json"
+	contentTypeYAML = "application/yaml"
+)
+
+// Handler returns an http.Handler which serves the Documents of the
+// Registry. Only GET and HEAD requests are permitted. Paths are relative to
+// the root of the Handler (see http.StripPrefix):
+//
+//	/                                   the names and versions of each Document
+//	/{name}                             the versions of a Document
+//	/{name}/{version}                   a version of a Document, bundled
+//	/{name}/{version}/validation        the result of validating a version
+//	/{name}/{version}/files             the files of the split form of a version
+//	/{name}/{version}/files/{file}      a file of the split form of a version
+//
+// version may be "latest" (see Latest). Documents are bundled, so that the
+// nodes they reference from other resources are served within their
+// Components (see openapi.Document.Bundle). They are encoded as JSON or YAML
+// according to the Accept header of the request, which may be overridden
+// with the "format" query parameter ("json" or "yaml"), and default to JSON.
+// Files of the split form are encoded as determined by Options.Layout.
+// Documents and files are served with an ETag derived from their checksum so
+// that clients may make conditional requests.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(r.serveHTTP)
+}
+
+func (r *Registry) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	segments := strings.SplitN(strings.Trim(req.URL.Path, "/"), "/", 4)
+	if segments[0] == "" {
+		segments = nil
+	}
+	switch {
+	case len(segments) == 0:
+		r.serveIndex(w, req)
+	case len(segments) == 1:
+		r.serveVersions(w, req, segments[0])
+	default:
+		e, ok := r.get(segments[0], segments[1])
+		if !ok {
+			http.NotFound(w, req)
+			return
+		}
+		switch {
+		case len(segments) == 2:
+			r.serveDocument(w, req, e)
+		case len(segments) == 3 && segments[2] == "validation":
+			writeJSON(w, req, http.StatusOK, newValidationResponse(e))
+		case len(segments) == 3 && segments[2] == "files":
+			r.serveFiles(w, req, e)
+		case len(segments) == 4 && segments[2] == "files":
+			r.serveFile(w, req, e, segments[3])
+		default:
+			http.NotFound(w, req)
+		}
+	}
+}
+
+type indexResponse struct {
+	Name     string          `json:"name"`
+	Latest   string          `json:"latest"`
+	Versions []entryResponse `json:"versions"`
+}
+
+type entryResponse struct {
+	Version  string `json:"version"`
+	Checksum string `json:"checksum"`
+	Added    string `json:"added"`
+	Valid    bool   `json:"valid"`
+}
+
+func newIndexResponse(name string, versions []Entry) indexResponse {
+	res := indexResponse{Name: name, Versions: make([]entryResponse, len(versions))}
+	for i, e := range versions {
+		res.Versions[i] = entryResponse{
+			Version:  e.Version,
+			Checksum: e.Checksum,
+			Added:    e.Added.UTC().Format("2006-01-02T15:04:05Z07:00"),
+			Valid:    e.Valid,
+		}
+	}
+	if len(versions) > 0 {
+		res.Latest = versions[len(versions)-1].Version
+	}
+	return res
+}
+
+func (r *Registry) serveIndex(w http.ResponseWriter, req *http.Request) {
+	r.mu.RLock()
+	res := make([]indexResponse, 0, len(r.specs))
+	for name := range r.specs {
+		res = append(res, newIndexResponse(name, r.versions(name)))
+	}
+	r.mu.RUnlock()
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+	writeJSON(w, req, http.StatusOK, res)
+}
+
+func (r *Registry) serveVersions(w http.ResponseWriter, req *http.Request, name string) {
+	versions := r.Versions(name)
+	if len(versions) == 0 {
+		http.NotFound(w, req)
+		return
+	}
+	writeJSON(w, req, http.StatusOK, newIndexResponse(name, versions))
+}
+
+func (r *Registry) serveDocument(w http.ResponseWriter, req *http.Request, e *entry) {
+	_, data, err := r.bundle(e)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	contentType := negotiate(req)
+	if contentType == contentTypeYAML {
+		if data, err = transcode.YAMLFromJSON(data); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	w.Header().Add("Vary", "Accept")
+	serveContent(w, req, contentType, etag(e.Checksum, contentType), data)
+}
+
+type fileResponse struct {
+	Path string `json:"path"`
+	Size int    `json:"size"`
+}
+
+func (r *Registry) serveFiles(w http.ResponseWriter, req *http.Request, e *entry) {
+	files, err := r.split(e)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	res := make([]fileResponse, 0, len(files))
+	for p, data := range files {
+		res = append(res, fileResponse{Path: p, Size: len(data)})
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Path < res[j].Path })
+	writeJSON(w, req, http.StatusOK, res)
+}
+
+func (r *Registry) serveFile(w http.ResponseWriter, req *http.Request, e *entry, file string) {
+	files, err := r.split(e)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	data, ok := files[file]
+	if !ok {
+		http.NotFound(w, req)
+		return
+	}
+	contentType := contentTypeYAML
+	if path.Ext(file) == ".json" {
+		contentType = contentTypeJSON
+	}
+	serveContent(w, req, contentType, etag(e.Checksum, file), data)
+}
+
+type validationErrorResponse struct {
+	URI     string `json:"uri,omitempty"`
+	Pointer string `json:"pointer,omitempty"`
+	Message string `json:"message"`
+}
+
+type validationResponse struct {
+	Name      string                    `json:"name"`
+	Version   string                    `json:"version"`
+	Validated bool                      `json:"validated"`
+	Valid     bool                      `json:"valid"`
+	Errors    []validationErrorResponse `json:"errors"`
+}
+
+func newValidationResponse(e *entry) validationResponse {
+	res := validationResponse{
+		Name:      e.Name,
+		Version:   e.Version,
+		Validated: e.validation.Validated,
+		Valid:     e.validation.Valid,
+		Errors:    []validationErrorResponse{},
+	}
+	for _, f := range e.validation.Report.Files {
+		for _, err := range f.Errors {
+			res.Errors = append(res.Errors, validationErrorResponse{
+				URI:     f.URI.String(),
+				Pointer: err.Pointer,
+				Message: err.Message,
+			})
+		}
+	}
+	return res
+}
+
+// negotiate returns the content type of a Document for req
+func negotiate(req *http.Request) string {
+	switch strings.ToLower(req.URL.Query().Get("format")) {
+	case "json":
+		return contentTypeJSON
+	case "yaml", "yml":
+		return contentTypeYAML
+	}
+	best, bestQ := contentTypeJSON, 0.0
+	for _, part := range strings.Split(req.Header.Get("Accept"), ",") {
+		mt, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		var contentType string
+		switch {
+		case mt == "application/json" || strings.HasSuffix(mt, "+json"):
+			contentType = contentTypeJSON
+		case mt == "application/yaml" || mt == "application/x-yaml" || mt == "text/yaml" || mt == "text/x-yaml" || strings.HasSuffix(mt, "+yaml"):
+			contentType = contentTypeYAML
+		default:
+			continue
+		}
+		if q > bestQ {
+			best, bestQ = contentType, q
+		}
+	}
+	return best
+}
+
+func etag(checksum string, variant string) string {
+	return strconv.Quote(checksum + "-" + strings.NewReplacer("/", "-", "\"", "").Replace(variant))
+}
+
+// serveContent writes data, honoring If-None-Match
+func serveContent(w http.ResponseWriter, req *http.Request, contentType string, tag string, data []byte) {
+	w.Header().Set("ETag", tag)
+	if match := req.Header.Get("If-None-Match"); match != "" {
+		for _, t := range strings.Split(match, ",") {
+			t = strings.TrimPrefix(strings.TrimSpace(t), "W/")
+			if t == tag || t == "*" {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		}
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.WriteHeader(http.StatusOK)
+	if req.Method != http.MethodHead {
+		_, _ = w.Write(data)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, req *http.Request, status int, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", contentTypeJSON)
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.WriteHeader(status)
+	if req.Method != http.MethodHead {
+		_, _ = w.Write(data)
+	}
+}
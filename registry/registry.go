@@ -0,0 +1,284 @@
+// Package registry serves OpenAPI Documents over HTTP.
+//
+// A Registry holds read-only snapshots (see openapi.Document.Freeze) of
+// Documents, keyed by name and by the version of their Info. Its Handler
+// serves each version as a single, bundled document in JSON or YAML (see
+// openapi.Document.Bundle), as the files of its split form (see
+// openapi.Document.Split), and along with the results of validating it, which
+// makes it simple to stand up an internal registry of specifications:
+//
+//	reg := registry.New(registry.Options{Validator: v})
+//	if _, err := reg.Put("petstore", doc); err != nil {
+//		// ...
+//	}
+//	http.Handle("/specs/", http.StripPrefix("/specs", reg.Handler()))
+package registry
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Masterminds/semver"
+	"github.com/chanced/openapi"
+)
+
+var (
+	// ErrInvalidName is returned when the name of a Document is empty or
+	// contains a slash.
+	ErrInvalidName = errors.New("registry: invalid name")
+
+	// ErrMissingVersion is returned when a Document does not have an
+	// info.version.
+	ErrMissingVersion = errors.New("registry: missing info.version")
+)
+
+// Latest is an alias for the greatest version of a Document (see
+// Registry.Versions).
+const Latest = "latest"
+
+// Options configures a Registry.
+type Options struct {
+	// Validator, if set, validates each Document as it is added. The
+	// results are available from Registry.Validation and the Handler;
+	// invalid Documents are added regardless.
+	Validator openapi.Validator
+	// Layout determines the files of the split form of Documents.
+	Layout openapi.SplitLayout
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// Registry is a collection of Documents, keyed by name and version. It is
+// safe for concurrent use.
+type Registry struct {
+	opts Options
+
+	mu    sync.RWMutex
+	specs map[string]map[string]*entry
+}
+
+// Entry describes a version of a Document held by a Registry.
+type Entry struct {
+	Name     string
+	Version  string
+	Checksum string
+	Added    time.Time
+	Valid    bool
+}
+
+type entry struct {
+	Entry
+	doc        *openapi.FrozenDocument
+	validation Validation
+
+	bundleOnce sync.Once
+	bundled    *openapi.Document
+	data       []byte
+	bundleErr  error
+
+	splitOnce sync.Once
+	files     map[string][]byte
+	splitErr  error
+}
+
+// Validation is the result of validating a Document when it was added to a
+// Registry.
+type Validation struct {
+	// Validated is false if the Registry does not have a Validator.
+	Validated bool
+	// Valid reports whether the Document is valid. It is true if the
+	// Document was not validated.
+	Valid  bool
+	Report openapi.ErrorReport
+}
+
+// New returns an empty Registry.
+func New(opts Options) *Registry {
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	return &Registry{opts: opts, specs: map[string]map[string]*entry{}}
+}
+
+// Put adds a snapshot of doc to the Registry as name, keyed by the version
+// of its Info, replacing the existing snapshot of the version, if any.
+//
+// doc is validated with the Validator of the Registry, if set. The
+// returned Entry describes the snapshot.
+func (r *Registry) Put(name string, doc *openapi.Document) (Entry, error) {
+	if name == "" || strings.Contains(name, "/") {
+		return Entry{}, fmt.Errorf("%w: %q", ErrInvalidName, name)
+	}
+	if doc == nil {
+		return Entry{}, fmt.Errorf("registry: cannot add a nil Document")
+	}
+	if doc.Info == nil || doc.Info.Version == "" {
+		return Entry{}, ErrMissingVersion
+	}
+	version := doc.Info.Version.String()
+	if version == Latest || strings.Contains(version, "/") {
+		return Entry{}, fmt.Errorf("registry: invalid version %q", version)
+	}
+	frozen, err := doc.Freeze()
+	if err != nil {
+		return Entry{}, err
+	}
+	validation := Validation{Valid: true}
+	if r.opts.Validator != nil {
+		validation.Validated = true
+		if err = r.opts.Validator.ValidateDocument(frozen.Document()); err != nil {
+			validation.Valid = false
+			validation.Report = openapi.NewErrorReport(err)
+		}
+	}
+	e := &entry{
+		Entry: Entry{
+			Name:     name,
+			Version:  version,
+			Checksum: frozen.Checksum(),
+			Added:    r.opts.Now(),
+			Valid:    validation.Valid,
+		},
+		doc:        frozen,
+		validation: validation,
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.specs[name] == nil {
+		r.specs[name] = map[string]*entry{}
+	}
+	r.specs[name][version] = e
+	return e.Entry, nil
+}
+
+// Delete removes version of name from the Registry, reporting whether it
+// was present. version may be Latest.
+func (r *Registry) Delete(name string, version string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e, ok := r.lookup(name, version)
+	if !ok {
+		return false
+	}
+	delete(r.specs[name], e.Version)
+	if len(r.specs[name]) == 0 {
+		delete(r.specs, name)
+	}
+	return true
+}
+
+// Get returns the snapshot of version of name. version may be Latest.
+func (r *Registry) Get(name string, version string) (*openapi.FrozenDocument, bool) {
+	e, ok := r.get(name, version)
+	if !ok {
+		return nil, false
+	}
+	return e.doc, true
+}
+
+// Validation returns the result of validating version of name. version
+// may be Latest.
+func (r *Registry) Validation(name string, version string) (Validation, bool) {
+	e, ok := r.get(name, version)
+	if !ok {
+		return Validation{}, false
+	}
+	return e.validation, true
+}
+
+// Names returns the names of the Documents of the Registry, sorted.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.specs))
+	for name := range r.specs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Versions returns an Entry for each version of name, in ascending order.
+// Versions are compared as semantic versions; those which are not valid
+// semantic versions are ordered lexically before those which are.
+func (r *Registry) Versions(name string) []Entry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.versions(name)
+}
+
+func (r *Registry) versions(name string) []Entry {
+	res := make([]Entry, 0, len(r.specs[name]))
+	for _, e := range r.specs[name] {
+		res = append(res, e.Entry)
+	}
+	sort.Slice(res, func(i, j int) bool { return versionLess(res[i].Version, res[j].Version) })
+	return res
+}
+
+func (r *Registry) get(name string, version string) (*entry, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.lookup(name, version)
+}
+
+func (r *Registry) lookup(name string, version string) (*entry, bool) {
+	if version == Latest {
+		versions := r.versions(name)
+		if len(versions) == 0 {
+			return nil, false
+		}
+		version = versions[len(versions)-1].Version
+	}
+	e, ok := r.specs[name][version]
+	return e, ok
+}
+
+// bundle returns a copy of the Document of e with the nodes it references
+// from other resources moved into its Components (see
+// openapi.Document.Bundle), along with its JSON encoding
+func (r *Registry) bundle(e *entry) (*openapi.Document, []byte, error) {
+	e.bundleOnce.Do(func() {
+		doc, err := e.doc.Thaw()
+		if err == nil {
+			err = doc.Bundle()
+		}
+		if err == nil {
+			e.data, err = doc.MarshalJSON()
+		}
+		e.bundled, e.bundleErr = doc, err
+	})
+	return e.bundled, e.data, e.bundleErr
+}
+
+// split returns the files of the split form of the bundled Document of e
+func (r *Registry) split(e *entry) (map[string][]byte, error) {
+	e.splitOnce.Do(func() {
+		var doc *openapi.Document
+		if doc, _, e.splitErr = r.bundle(e); e.splitErr == nil {
+			e.files, e.splitErr = doc.Split(r.opts.Layout)
+		}
+	})
+	return e.files, e.splitErr
+}
+
+func versionLess(a, b string) bool {
+	va, errA := semver.NewVersion(a)
+	vb, errB := semver.NewVersion(b)
+	switch {
+	case errA != nil && errB != nil:
+		return a < b
+	case errA != nil:
+		return true
+	case errB != nil:
+		return false
+	case va.Equal(vb):
+		return a < b
+	default:
+		return va.LessThan(vb)
+	}
+}
@@ -0,0 +1,275 @@
+package registry_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/chanced/openapi"
+	"github.com/chanced/openapi/registry"
+	"github.com/chanced/uri"
+)
+
+func loadDocument(t *testing.T, version string) *openapi.Document {
+	t.Helper()
+	data := []byte(`{
+		"openapi": "3.1.0",
+		"info": { "title": "Pet Store", "version": "` + version + `" },
+		"paths": {
+			"/pets": {
+				"get": {
+					"operationId": "listPets",
+					"responses": {
+						"200": { "$ref": "#/components/responses/Pets" }
+					}
+				}
+			}
+		},
+		"components": {
+			"responses": {
+				"Pets": { "description": "ok" }
+			}
+		}
+	}`)
+	fn := func(ctx context.Context, u uri.URI, kind openapi.Kind) (openapi.Kind, []byte, error) {
+		return openapi.KindDocument, data, nil
+	}
+	v, err := openapi.NewOfflineValidator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := openapi.Load(context.Background(), "https://example.com/openapi.json", v, fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+func get(t *testing.T, srv *httptest.Server, path string, header http.Header) (*http.Response, string) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, srv.URL+path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	res, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res, string(body)
+}
+
+func TestRegistry(t *testing.T) {
+	v, err := openapi.NewOfflineValidator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	reg := registry.New(registry.Options{Validator: v})
+	for _, version := range []string{"1.10.0", "1.2.0"} {
+		e, err := reg.Put("petstore", loadDocument(t, version))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !e.Valid || e.Checksum == "" {
+			t.Errorf("unexpected entry: %+v", e)
+		}
+	}
+	invalid := loadDocument(t, "0.1.0")
+	invalid.Paths = nil
+	invalid.Components = nil
+	if _, err = reg.Put("petstore", invalid); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = reg.Put("a/b", invalid); err == nil {
+		t.Error("expected an error for an invalid name")
+	}
+
+	if names := reg.Names(); len(names) != 1 || names[0] != "petstore" {
+		t.Errorf("unexpected names: %v", names)
+	}
+	var versions []string
+	for _, e := range reg.Versions("petstore") {
+		versions = append(versions, e.Version)
+	}
+	if strings.Join(versions, ",") != "0.1.0,1.2.0,1.10.0" {
+		t.Errorf("unexpected versions: %v", versions)
+	}
+	latest, ok := reg.Get("petstore", registry.Latest)
+	if !ok || latest.Document().Info.Version != "1.10.0" {
+		t.Errorf("expected latest to be 1.10.0")
+	}
+	if validation, _ := reg.Validation("petstore", "0.1.0"); !validation.Validated || validation.Valid {
+		t.Errorf("expected 0.1.0 to be invalid, got %+v", validation)
+	}
+
+	srv := httptest.NewServer(http.StripPrefix("/specs", reg.Handler()))
+	defer srv.Close()
+
+	res, body := get(t, srv, "/specs/petstore", nil)
+	if res.StatusCode != http.StatusOK || !strings.Contains(body, `"latest":"1.10.0"`) {
+		t.Errorf("unexpected versions response: %d %s", res.StatusCode, body)
+	}
+
+	res, body = get(t, srv, "/specs/petstore/latest", nil)
+	if ct := res.Header.Get("Content-Type"); ct != "application/json" {
+		t.Errorf("expected application/json, got %q", ct)
+	}
+	var doc map[string]interface{}
+	if err = json.Unmarshal([]byte(body), &doc); err != nil {
+		t.Fatal(err)
+	}
+	tag := res.Header.Get("ETag")
+	if tag == "" {
+		t.Error("expected an ETag")
+	}
+	res, _ = get(t, srv, "/specs/petstore/1.10.0", http.Header{"If-None-Match": {tag}})
+	if res.StatusCode != http.StatusNotModified {
+		t.Errorf("expected %d, got %d", http.StatusNotModified, res.StatusCode)
+	}
+
+	res, body = get(t, srv, "/specs/petstore/1.2.0", http.Header{"Accept": {"application/json;q=0.5, application/yaml"}})
+	if ct := res.Header.Get("Content-Type"); ct != "application/yaml" || !strings.Contains(body, "openapi: 3.1.0") {
+		t.Errorf("expected YAML, got %q: %s", ct, body)
+	}
+	res, _ = get(t, srv, "/specs/petstore/1.2.0?format=json", http.Header{"Accept": {"application/yaml"}})
+	if ct := res.Header.Get("Content-Type"); ct != "application/json" {
+		t.Errorf("expected format to override Accept, got %q", ct)
+	}
+
+	_, body = get(t, srv, "/specs/petstore/1.2.0/files", nil)
+	var files []struct{ Path string }
+	if err = json.Unmarshal([]byte(body), &files); err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	for _, f := range files {
+		paths = append(paths, f.Path)
+	}
+	expected := "components/responses/Pets.yaml,openapi.yaml,paths/pets.yaml"
+	if strings.Join(paths, ",") != expected {
+		t.Errorf("expected files %s, got %v", expected, paths)
+	}
+	res, body = get(t, srv, "/specs/petstore/1.2.0/files/components/responses/Pets.yaml", nil)
+	if res.StatusCode != http.StatusOK || !strings.Contains(body, "description: ok") {
+		t.Errorf("unexpected file response: %d %s", res.StatusCode, body)
+	}
+
+	_, body = get(t, srv, "/specs/petstore/0.1.0/validation", nil)
+	var validation struct {
+		Valid  bool
+		Errors []struct{ Message string }
+	}
+	if err = json.Unmarshal([]byte(body), &validation); err != nil {
+		t.Fatal(err)
+	}
+	if validation.Valid || len(validation.Errors) == 0 {
+		t.Errorf("expected validation errors, got %s", body)
+	}
+
+	for _, p := range []string{"/specs/unknown", "/specs/petstore/9.9.9", "/specs/petstore/1.2.0/other"} {
+		if res, _ = get(t, srv, p, nil); res.StatusCode != http.StatusNotFound {
+			t.Errorf("%s: expected %d, got %d", p, http.StatusNotFound, res.StatusCode)
+		}
+	}
+	if !reg.Delete("petstore", registry.Latest) {
+		t.Error("expected latest to be deleted")
+	}
+	if latest, _ = reg.Get("petstore", registry.Latest); latest.Document().Info.Version != "1.2.0" {
+		t.Errorf("expected latest to be 1.2.0")
+	}
+}
+
+func TestRegistryBundle(t *testing.T) {
+	resources := map[string]string{
+		"https://example.com/api/openapi.json": `{
+			"openapi": "3.1.0",
+			"info": { "title": "Pet Store", "version": "1.0.0" },
+			"paths": {
+				"/pets": {
+					"get": {
+						"responses": {
+							"200": {
+								"description": "ok",
+								"content": { "application/json": { "schema": { "$ref": "schemas/pet.json" } } }
+							}
+						}
+					}
+				}
+			}
+		}`,
+		"https://example.com/api/schemas/pet.json": `{
+			"type": "object",
+			"properties": { "owner": { "$ref": "owner.json" } }
+		}`,
+		"https://example.com/api/schemas/owner.json": `{ "type": "string" }`,
+	}
+	fn := func(ctx context.Context, u uri.URI, kind openapi.Kind) (openapi.Kind, []byte, error) {
+		data, ok := resources[u.String()]
+		if !ok {
+			return 0, nil, fmt.Errorf("unknown uri %q", u)
+		}
+		if _, ok = openapi.TryGetOpenAPIVersion([]byte(data)); ok {
+			return openapi.KindDocument, []byte(data), nil
+		}
+		return openapi.KindSchema, []byte(data), nil
+	}
+	v, err := openapi.NewOfflineValidator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := openapi.Load(context.Background(), "https://example.com/api/openapi.json", v, fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reg := registry.New(registry.Options{})
+	if _, err = reg.Put("petstore", doc); err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(reg.Handler())
+	defer srv.Close()
+
+	_, body := get(t, srv, "/petstore/1.0.0", nil)
+	if strings.Contains(body, ".json") {
+		t.Errorf("expected the external references to be bundled: %s", body)
+	}
+	var bundled struct {
+		Components struct {
+			Schemas map[string]struct {
+				Properties map[string]struct {
+					Ref string `json:"$ref"`
+				}
+			}
+		}
+	}
+	if err = json.Unmarshal([]byte(body), &bundled); err != nil {
+		t.Fatal(err)
+	}
+	pet, ok := bundled.Components.Schemas["pet"]
+	if !ok || pet.Properties["owner"].Ref != "#/components/schemas/owner" {
+		t.Errorf("expected pet and owner to be components: %s", body)
+	}
+	if _, ok = bundled.Components.Schemas["owner"]; !ok {
+		t.Errorf("expected owner to be a component: %s", body)
+	}
+
+	_, body = get(t, srv, "/petstore/1.0.0/files", nil)
+	if !strings.Contains(body, "components/schemas/pet.yaml") || !strings.Contains(body, "components/schemas/owner.yaml") {
+		t.Errorf("expected the split form to include the bundled schemas: %s", body)
+	}
+
+	if latest, _ := reg.Get("petstore", registry.Latest); latest.Document().Components != nil {
+		t.Error("expected the snapshot not to be modified")
+	}
+}
@@ -0,0 +1,271 @@
+// Package render renders documentation, such as Markdown or HTML, for OpenAPI
+// Documents with text/template.
+//
+// Templates are executed with the *openapi.Document as dot and have access
+// to the functions of Funcs, which include:
+//
+//	byTag          the Operations of the Document's Paths, grouped by tag
+//	operations     each Operation of the Document, including webhooks
+//	schemaExample  a JSON example of a Schema
+//	refName        the name of the component a reference refers to
+//	json           v encoded as indented JSON
+//	anchor         a URL fragment (slug) for a heading
+//	isRequired     whether a property of a Schema is required
+//	isTrue         whether a *bool is set and true
+//	upper, lower   case conversion
+//
+// Markdown renders a Document with a built-in Markdown template.
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/chanced/openapi"
+	"github.com/chanced/uri"
+)
+
+// TagGroup is a tag and the Operations which list it as their first tag.
+type TagGroup struct {
+	// Name of the tag. Name is empty for the group of untagged Operations.
+	Name        openapi.Text
+	Description openapi.Text
+	Operations  []openapi.OperationEntry
+}
+
+// New returns a new template named name with the functions of Funcs added.
+func New(name string, doc *openapi.Document) *template.Template {
+	return template.New(name).Funcs(Funcs(doc))
+}
+
+// Render executes tmpl with doc as dot, writing the output to w.
+//
+// tmpl should be created with New or have the functions of Funcs added.
+func Render(w io.Writer, doc *openapi.Document, tmpl *template.Template) error {
+	if err := tmpl.Execute(w, doc); err != nil {
+		return fmt.Errorf("render: %w", err)
+	}
+	return nil
+}
+
+// Markdown renders doc as Markdown with the built-in template.
+func Markdown(w io.Writer, doc *openapi.Document) error {
+	tmpl, err := New("markdown", doc).Parse(markdownTemplate)
+	if err != nil {
+		return fmt.Errorf("render: %w", err)
+	}
+	return Render(w, doc, tmpl)
+}
+
+// Funcs returns the template functions for doc.
+func Funcs(doc *openapi.Document) template.FuncMap {
+	return template.FuncMap{
+		"byTag":         func() []TagGroup { return ByTag(doc) },
+		"operations":    doc.Operations,
+		"schemaExample": SchemaExample,
+		"refName":       RefName,
+		"json":          jsonIndent,
+		"anchor":        Anchor,
+		"isRequired":    isRequired,
+		"isTrue":        func(b *bool) bool { return b != nil && *b },
+		"upper":         func(v interface{}) string { return strings.ToUpper(fmt.Sprint(v)) },
+		"lower":         func(v interface{}) string { return strings.ToLower(fmt.Sprint(v)) },
+	}
+}
+
+// ByTag groups the Operations of doc's Paths by their first tag. Groups are
+// ordered by the Document's tags followed by undeclared tags in the order
+// they are first used. Untagged Operations are grouped last, under an empty
+// Name.
+func ByTag(doc *openapi.Document) []TagGroup {
+	var groups []TagGroup
+	index := map[openapi.Text]int{}
+	if doc.Tags != nil {
+		for _, t := range doc.Tags.Items {
+			if t == nil {
+				continue
+			}
+			index[t.Name] = len(groups)
+			groups = append(groups, TagGroup{Name: t.Name, Description: t.Description})
+		}
+	}
+	var untagged []openapi.OperationEntry
+	for _, op := range doc.Operations() {
+		if op.Webhook || op.Operation == nil {
+			continue
+		}
+		if len(op.Operation.Tags) == 0 {
+			untagged = append(untagged, op)
+			continue
+		}
+		tag := op.Operation.Tags[0]
+		i, ok := index[tag]
+		if !ok {
+			i = len(groups)
+			index[tag] = i
+			groups = append(groups, TagGroup{Name: tag})
+		}
+		groups[i].Operations = append(groups[i].Operations, op)
+	}
+	// declared tags which are not used by any Operation are omitted
+	res := groups[:0]
+	for _, g := range groups {
+		if len(g.Operations) > 0 {
+			res = append(res, g)
+		}
+	}
+	if len(untagged) > 0 {
+		res = append(res, TagGroup{Operations: untagged})
+	}
+	return res
+}
+
+// SchemaExample returns an example of s, encoded as indented JSON. See
+// openapi.Schema.GenerateExample.
+func SchemaExample(s *openapi.Schema) (string, error) {
+	v := s.GenerateExample()
+	if v == nil {
+		return "", nil
+	}
+	return jsonIndent(v)
+}
+
+// RefName returns the name of the component referenced by v, which may be a
+// *openapi.Schema, an openapi.Ref, a Component (e.g.
+// *openapi.Component[*openapi.Response]), or a reference URI. If v is not a
+// reference, an empty string is returned.
+//
+// The name is the last token of the reference's JSON pointer (e.g. "Pet"
+// for "#/components/schemas/Pet") or, if the reference does not have a
+// fragment, the base name of its path without an extension.
+func RefName(v interface{}) string {
+	var u *uri.URI
+	switch v := v.(type) {
+	case *openapi.Schema:
+		if v != nil && v.Ref != nil {
+			u = v.Ref.Ref
+		}
+	case interface{ URI() *uri.URI }:
+		u = v.URI()
+	case *uri.URI:
+		u = v
+	case uri.URI:
+		u = &v
+	case openapi.Text:
+		return refName(v.String())
+	case string:
+		return refName(v)
+	}
+	if u == nil {
+		return ""
+	}
+	return refName(u.String())
+}
+
+func refName(ref string) string {
+	if i := strings.IndexByte(ref, '#'); i >= 0 {
+		if frag := ref[i+1:]; frag != "" {
+			name := frag[strings.LastIndexByte(frag, '/')+1:]
+			return strings.NewReplacer("~1", "/", "~0", "~").Replace(name)
+		}
+		ref = ref[:i]
+	}
+	base := path.Base(ref)
+	if base == "." || base == "/" {
+		return ""
+	}
+	return strings.TrimSuffix(base, path.Ext(base))
+}
+
+// Anchor returns a URL fragment for the heading v, as generated by GitHub
+// for Markdown headings (e.g. "GET /pets/{petId}" becomes "get-petspetid").
+func Anchor(v interface{}) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(fmt.Sprint(v)) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_':
+			b.WriteRune(r)
+		case r == ' ':
+			b.WriteByte('-')
+		}
+	}
+	return b.String()
+}
+
+func isRequired(s *openapi.Schema, key openapi.Text) bool {
+	if s == nil {
+		return false
+	}
+	for _, r := range s.EffectiveRequired() {
+		if r == key {
+			return true
+		}
+	}
+	return false
+}
+
+func jsonIndent(v interface{}) (string, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+const markdownTemplate = `# {{ .Info.Title }}
+{{ with .Info.Version }}
+Version: {{ . }}
+{{ end }}{{ with .Info.Description }}
+{{ . }}
+{{ end }}{{ range byTag }}
+## {{ if .Name }}{{ .Name }}{{ else }}Other{{ end }}
+{{ with .Description }}
+{{ . }}
+{{ end }}{{ range .Operations }}{{ $op := .Operation }}
+### {{ upper .Method }} {{ .Key }}
+{{ with $op.Summary }}
+{{ . }}
+{{ end }}{{ with $op.Description }}
+{{ . }}
+{{ end }}{{ if $op.Deprecated }}
+**Deprecated**
+{{ end }}{{ with $op.Parameters }}{{ if .Items }}
+| Name | In | Required | Description |
+| ---- | -- | -------- | ----------- |
+{{ range .Items }}{{ with .Object }}| {{ .Name }} | {{ .In }} | {{ if or (eq .In "path") (isTrue .Required) }}yes{{ else }}no{{ end }} | {{ .Description }} |
+{{ end }}{{ end }}{{ end }}{{ end }}{{ with $op.RequestBody }}{{ with .Object }}
+#### Request Body
+{{ with .Description }}
+{{ . }}
+{{ end }}{{ with .Content }}{{ range .Items }}
+` + "`{{ .Key }}`" + `
+{{ with .Value }}{{ with .Schema }}{{ with refName . }}
+Schema: [{{ . }}](#{{ anchor . }})
+{{ end }}{{ with schemaExample . }}
+` + "```json" + `
+{{ . }}
+` + "```" + `
+{{ end }}{{ end }}{{ end }}{{ end }}{{ end }}{{ end }}{{ end }}{{ with $op.Responses }}
+#### Responses
+{{ range .Items }}{{ $code := .Key }}{{ with .Component }}{{ with .Object }}
+- **{{ $code }}**{{ with .Description }} {{ . }}{{ end }}
+{{ end }}{{ end }}{{ end }}{{ end }}{{ end }}{{ end }}{{ with .Components }}{{ with .Schemas }}
+## Schemas
+{{ range .Items }}{{ $key := .Key }}{{ with .Schema }}{{ $schema := . }}
+### {{ $key }}
+{{ with .Description }}
+{{ . }}
+{{ end }}{{ with .Properties }}
+| Property | Type | Required | Description |
+| -------- | ---- | -------- | ----------- |
+{{ range .Items }}{{ $prop := .Key }}{{ with .Schema }}| {{ $prop }} | {{ with refName . }}[{{ . }}](#{{ anchor . }}){{ else }}{{ range $i, $t := .Type }}{{ if $i }}, {{ end }}{{ $t }}{{ end }}{{ end }} | {{ if isRequired $schema $prop }}yes{{ else }}no{{ end }} | {{ .Description }} |
+{{ end }}{{ end }}{{ end }}{{ with schemaExample $schema }}
+` + "```json" + `
+{{ . }}
+` + "```" + `
+{{ end }}{{ end }}{{ end }}{{ end }}{{ end }}`
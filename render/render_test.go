@@ -0,0 +1,114 @@
+package render_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/chanced/openapi"
+	"github.com/chanced/openapi/render"
+	"github.com/chanced/uri"
+)
+
+func TestRender(t *testing.T) {
+	data := []byte(`{
+		"openapi": "3.1.0",
+		"info": { "title": "Pet Store", "version": "1.0.0" },
+		"tags": [{ "name": "pets", "description": "Pet operations" }, { "name": "unused" }],
+		"paths": {
+			"/pets/{petId}": {
+				"get": {
+					"tags": ["pets"],
+					"summary": "Get a pet",
+					"parameters": [
+						{ "name": "petId", "in": "path", "required": true, "schema": { "type": "integer" } },
+						{ "name": "expand", "in": "query", "schema": { "type": "boolean" } }
+					],
+					"responses": {
+						"200": {
+							"description": "ok",
+							"content": {
+								"application/json": { "schema": { "$ref": "#/components/schemas/Pet" } }
+							}
+						}
+					}
+				}
+			},
+			"/health": {
+				"get": {
+					"responses": { "204": { "description": "healthy" } }
+				}
+			}
+		},
+		"components": {
+			"schemas": {
+				"Pet": {
+					"type": "object",
+					"required": ["name"],
+					"properties": {
+						"name": { "type": "string", "examples": ["Fido"] },
+						"owner": { "$ref": "#/components/schemas/Owner" }
+					}
+				},
+				"Owner": {
+					"type": "object",
+					"properties": { "email": { "type": "string", "format": "email" } }
+				}
+			}
+		}
+	}`)
+	fn := func(ctx context.Context, u uri.URI, kind openapi.Kind) (openapi.Kind, []byte, error) {
+		return openapi.KindDocument, data, nil
+	}
+	v, err := openapi.NewOfflineValidator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := openapi.Load(context.Background(), "https://example.com/openapi.json", v, fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	groups := render.ByTag(doc)
+	if len(groups) != 2 || groups[0].Name != "pets" || groups[1].Name != "" {
+		t.Errorf("unexpected groups: %+v", groups)
+	}
+
+	var b bytes.Buffer
+	if err = render.Markdown(&b, doc); err != nil {
+		t.Fatal(err)
+	}
+	md := b.String()
+	for _, s := range []string{
+		"# Pet Store",
+		"## pets\n\nPet operations",
+		"### GET /pets/{petId}",
+		"| petId | path | yes |  |",
+		"| expand | query | no |  |",
+		"- **200** ok",
+		"## Other",
+		"### GET /health",
+		"### Pet",
+		"| name | string | yes |  |",
+		"| owner | [Owner](#owner) | no |  |",
+		`"email": "user@example.com"`,
+		`"name": "Fido"`,
+	} {
+		if !strings.Contains(md, s) {
+			t.Errorf("expected output to contain %q:\n%s", s, md)
+		}
+	}
+
+	tmpl, err := render.New("custom", doc).Parse(`{{ range byTag }}{{ range .Operations }}{{ anchor (printf "%s %s" .Method .Key) }}{{ with .Operation.Responses }}{{ range .Items }}:{{ with .Component.Object }}{{ with .Content }}{{ range .Items }}{{ refName .Value.Schema }}{{ end }}{{ end }}{{ end }}{{ end }}{{ end }};{{ end }}{{ end }}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.Reset()
+	if err = render.Render(&b, doc, tmpl); err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != "get-petspetid:Pet;get-health:;" {
+		t.Errorf("unexpected output: %q", b.String())
+	}
+}
@@ -0,0 +1,162 @@
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/chanced/uri"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// ErrorReport is a human-readable report of the errors contained in an
+// error returned from Load or a Validator, grouped by the resource (file) in
+// which they occurred.
+type ErrorReport struct {
+	Files []FileErrors
+}
+
+// FileErrors are the errors which occurred within a single resource.
+type FileErrors struct {
+	// URI of the resource, without a fragment. It is empty for errors
+	// without a location.
+	URI uri.URI
+	// Errors are ordered by Pointer.
+	Errors []ReportedError
+}
+
+// ReportedError is an individual error of an ErrorReport.
+type ReportedError struct {
+	// Pointer is the JSON Pointer of the offending node within the resource
+	// (e.g. "/paths/~1pets/get").
+	Pointer string
+	// Message describes the error.
+	Message string
+	// Err is the underlying error.
+	Err error
+}
+
+// NewErrorReport creates an ErrorReport from err.
+//
+// Errors which aggregate others (e.g. ExtensionErrors) are expanded, as are
+// the causes of each ValidationError (see ValidationError.Causes).
+func NewErrorReport(err error) ErrorReport {
+	files := map[string]*FileErrors{}
+	var order []string
+	for _, e := range flattenErrors(err) {
+		loc := errorLocation(e)
+		ptr := loc.Fragment
+		loc.Fragment = ""
+		loc.RawFragment = ""
+		key := loc.String()
+		f, ok := files[key]
+		if !ok {
+			f = &FileErrors{URI: loc}
+			files[key] = f
+			order = append(order, key)
+		}
+		f.Errors = append(f.Errors, ReportedError{
+			Pointer: ptr,
+			Message: errorMessage(e),
+			Err:     e,
+		})
+	}
+	sort.Strings(order)
+	report := ErrorReport{Files: make([]FileErrors, 0, len(order))}
+	for _, key := range order {
+		f := files[key]
+		sort.SliceStable(f.Errors, func(i, j int) bool { return f.Errors[i].Pointer < f.Errors[j].Pointer })
+		report.Files = append(report.Files, *f)
+	}
+	return report
+}
+
+// String renders the report, with each resource followed by its errors:
+//
+//	https://example.com/openapi.yaml
+//	  #/info: missing properties: 'version'
+func (r ErrorReport) String() string {
+	b := strings.Builder{}
+	for i, f := range r.Files {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		if f.URI.String() == "" {
+			b.WriteString("(unknown)\n")
+		} else {
+			b.WriteString(f.URI.String() + "\n")
+		}
+		for _, e := range f.Errors {
+			b.WriteString(fmt.Sprintf("  #%s: %s\n", e.Pointer, e.Message))
+		}
+	}
+	return b.String()
+}
+
+// flattenErrors expands err into its individual errors
+func flattenErrors(err error) []error {
+	if err == nil {
+		return nil
+	}
+	var ee ExtensionErrors
+	if errors.As(err, &ee) {
+		var res []error
+		for _, e := range ee {
+			res = append(res, flattenErrors(e)...)
+		}
+		return res
+	}
+	var uve *UnsupportedVersionError
+	if errors.As(err, &uve) {
+		var res []error
+		for _, e := range uve.Errs {
+			res = append(res, flattenErrors(e)...)
+		}
+		return res
+	}
+	var ve *ValidationError
+	if errors.As(err, &ve) {
+		causes := ve.Causes()
+		res := make([]error, len(causes))
+		for i, c := range causes {
+			res[i] = c
+		}
+		return res
+	}
+	return []error{err}
+}
+
+// errorLocation returns the location of err, if known
+func errorLocation(err error) uri.URI {
+	var ve *ValidationError
+	if errors.As(err, &ve) {
+		return ve.URI
+	}
+	var rnf *RefNotFoundError
+	if errors.As(err, &rnf) {
+		return rnf.Location
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return e.ResourceURI
+	}
+	return uri.URI{}
+}
+
+// errorMessage returns the message of err without its location
+func errorMessage(err error) string {
+	var jve *jsonschema.ValidationError
+	if errors.As(err, &jve) && len(jve.Causes) == 0 {
+		return jve.Message
+	}
+	var ve *ValidationError
+	if errors.As(err, &ve) && ve.Err != nil {
+		return ve.Err.Error()
+	}
+	var e *Error
+	if errors.As(err, &e) && e.Err != nil {
+		return e.Err.Error()
+	}
+	return err.Error()
+}
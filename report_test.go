@@ -0,0 +1,56 @@
+package openapi_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/chanced/openapi"
+	"github.com/chanced/uri"
+)
+
+func TestErrorReport(t *testing.T) {
+	err := openapi.ExtensionErrors{
+		openapi.NewValidationError(errors.New("bad a"), openapi.KindSchema, *uri.MustParse("https://example.com/a.json#/x-a")),
+		openapi.NewValidationError(errors.New("bad b"), openapi.KindInfo, *uri.MustParse("https://example.com/openapi.json#/info/x-b")),
+		openapi.NewValidationError(errors.New("bad c"), openapi.KindComponents, *uri.MustParse("https://example.com/openapi.json#/components/x-c")),
+	}
+	report := openapi.NewErrorReport(err)
+	if len(report.Files) != 2 {
+		t.Fatalf("expected 2 files, got %d", len(report.Files))
+	}
+	if report.Files[0].URI.String() != "https://example.com/a.json" {
+		t.Errorf("expected first file to be a.json, got %s", report.Files[0].URI.String())
+	}
+	f := report.Files[1]
+	if len(f.Errors) != 2 || f.Errors[0].Pointer != "/components/x-c" || f.Errors[1].Pointer != "/info/x-b" {
+		t.Errorf("expected errors of openapi.json to be sorted by pointer, got %+v", f.Errors)
+	}
+	expected := `https://example.com/a.json
+  #/x-a: bad a
+
+https://example.com/openapi.json
+  #/components/x-c: bad c
+  #/info/x-b: bad b
+`
+	if report.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, report.String())
+	}
+
+	v, err2 := openapi.NewOfflineValidator()
+	if err2 != nil {
+		t.Fatal(err2)
+	}
+	fn := func(_ context.Context, _ uri.URI, kind openapi.Kind) (openapi.Kind, []byte, error) {
+		return openapi.KindDocument, []byte(`{ "openapi": "3.1.0", "info": { "title": "report" }, "paths": {} }`), nil
+	}
+	_, err2 = openapi.Load(context.Background(), "https://example.com/openapi.json", v, fn)
+	if err2 == nil {
+		t.Fatal("expected an error")
+	}
+	s := openapi.NewErrorReport(err2).String()
+	if !strings.HasPrefix(s, "https://example.com/openapi.json\n") || !strings.Contains(s, "#/info") {
+		t.Errorf("unexpected report:\n%s", s)
+	}
+}
@@ -0,0 +1,84 @@
+package openapi
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chanced/uri"
+)
+
+// Resolve resolves the references of a Document which was constructed in
+// code rather than loaded, in the same manner as Load: the Locations of the
+// Document's nodes are assigned relative to documentURI, each reference is
+// resolved, and resources other than the Document are loaded with fn. Unlike
+// Load, the Document is not validated.
+//
+// fn may be nil if the Document does not reference other resources, in
+// which case references to them fail with ErrOffline.
+//
+// The Document's OpenAPI version must be set. Limits, hooks, and other
+// options of opts apply as they do to Load.
+func (d *Document) Resolve(ctx context.Context, documentURI string, fn func(ctx context.Context, uri uri.URI, kind Kind) (Kind, []byte, error), opts ...LoadOpts) error {
+	if d == nil {
+		return nil
+	}
+	if documentURI == "" {
+		return fmt.Errorf("documentURI cannot be empty")
+	}
+	docURI, err := uri.Parse(documentURI)
+	if err != nil {
+		return fmt.Errorf("failed to parse documentURI: %w", err)
+	}
+	if docURI.Fragment != "" {
+		return NewError(fmt.Errorf("documentURI may not contain a fragment: received \"%s\"", docURI), *docURI)
+	}
+	u := *docURI
+	if fn == nil {
+		fn = func(ctx context.Context, u uri.URI, kind Kind) (Kind, []byte, error) {
+			return KindUndefined, nil, NewError(ErrOffline, u)
+		}
+	}
+	lo := mergeLoadOpts(opts)
+	if lo.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, lo.Timeout)
+		defer cancel()
+	}
+	l := newLoader(nil, fn, lo)
+	// the Document itself counts against MaxResources when loaded
+	l.fetched = 1
+
+	if d.OpenAPI == nil {
+		return NewError(ErrMissingOpenAPIVersion, u)
+	}
+	v := *d.OpenAPI
+	caps, tolerated, err := l.versionCapabilities(&v, u)
+	if err != nil {
+		return err
+	}
+	if tolerated {
+		v = caps.ValidateAs
+	} else if !SupportedVersions.Check(&v) {
+		return NewError(&UnsupportedVersionError{Version: v.String()}, u)
+	}
+	sd, err := l.getDocumentSchemaDialect(d, &v)
+	if err != nil {
+		return NewError(err, u)
+	}
+	l.dialect = sd
+
+	loc, err := NewLocation(u)
+	if err != nil {
+		return NewError(err, u)
+	}
+	if err = d.setLocation(loc); err != nil {
+		return NewError(err, u)
+	}
+	if err = checkContext(ctx, u); err != nil {
+		return err
+	}
+	if _, err = l.resolveDocument(ctx, d, u, v, *sd, tolerated); err != nil {
+		return withLocation(err, u)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package openapi_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Masterminds/semver"
+	"github.com/chanced/openapi"
+	"github.com/chanced/uri"
+)
+
+func newResolveDocument() *openapi.Document {
+	doc := &openapi.Document{
+		OpenAPI: semver.MustParse("3.1.0"),
+		Info:    &openapi.Info{Title: "resolve", Version: "1.0.0"},
+		Components: &openapi.Components{
+			Responses: &openapi.ResponseMap{},
+			Schemas:   &openapi.SchemaMap{},
+		},
+	}
+	doc.Components.Responses.SetObject("Error", &openapi.Response{Description: "an error"})
+	doc.Components.Responses.SetRef("NotFound", *uri.MustParse("#/components/responses/Error"))
+	doc.Components.Schemas.Set("Pet", &openapi.Schema{
+		Ref: &openapi.SchemaRef{
+			Ref:           uri.MustParse("common.json#/components/schemas/Pet"),
+			SchemaRefKind: openapi.SchemaRefTypeRef,
+		},
+	})
+	return doc
+}
+
+func TestDocumentResolve(t *testing.T) {
+	common := []byte(`{
+		"openapi": "3.1.0",
+		"info": { "title": "common", "version": "1.0.0" },
+		"components": { "schemas": { "Pet": { "type": "object" } } }
+	}`)
+	var fetched []string
+	fn := func(ctx context.Context, u uri.URI, kind openapi.Kind) (openapi.Kind, []byte, error) {
+		fetched = append(fetched, u.String())
+		return openapi.KindDocument, common, nil
+	}
+	doc := newResolveDocument()
+	if err := doc.Resolve(context.Background(), "https://example.com/openapi.json", fn); err != nil {
+		t.Fatal(err)
+	}
+	if len(fetched) != 1 || fetched[0] != "https://example.com/common.json" {
+		t.Errorf("expected common.json to be fetched, got %v", fetched)
+	}
+	notFound := doc.Components.Responses.Get("NotFound")
+	if notFound.Object == nil || notFound.Object.Description != "an error" {
+		t.Error("expected the NotFound response to be resolved")
+	}
+	if l := notFound.Reference.AbsoluteLocation(); l.String() != "https://example.com/openapi.json#/components/responses/NotFound" {
+		t.Errorf("expected the location of the reference to be set, got %q", l.String())
+	}
+	pet := doc.Components.Schemas.Get("Pet")
+	if pet.Ref.Resolved == nil {
+		t.Fatal("expected the Pet schema to be resolved")
+	}
+	if l := pet.Ref.Resolved.AbsoluteLocation(); l.String() != "https://example.com/common.json#/components/schemas/Pet" {
+		t.Errorf("expected the resolved schema to be located in common.json, got %q", l.String())
+	}
+
+	doc = newResolveDocument()
+	if err := doc.Resolve(context.Background(), "https://example.com/openapi.json", nil); !errors.Is(err, openapi.ErrOffline) {
+		t.Errorf("expected ErrOffline, got %v", err)
+	}
+
+	doc = newResolveDocument()
+	doc.OpenAPI = nil
+	if err := doc.Resolve(context.Background(), "https://example.com/openapi.json", fn); !errors.Is(err, openapi.ErrMissingOpenAPIVersion) {
+		t.Errorf("expected ErrMissingOpenAPIVersion, got %v", err)
+	}
+}
@@ -0,0 +1,207 @@
+package openapi
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/chanced/uri"
+)
+
+// ResourceCache caches the data of external resources fetched by Load (see
+// LoadOpts.Cache), allowing resolved resources to be persisted across runs
+// (e.g. to disk or a key-value store) so that stable upstream resources need
+// not be fetched again.
+//
+// Implementations must be safe for concurrent use if shared between
+// concurrent calls to Load.
+type ResourceCache interface {
+	// Get returns the cached resource at u. If the resource is not cached,
+	// Get returns nil and a nil error.
+	Get(ctx context.Context, u uri.URI) (*CachedResource, error)
+	// Put caches res as the resource at u.
+	Put(ctx context.Context, u uri.URI, res CachedResource) error
+}
+
+// CachedResource is a resource held by a ResourceCache.
+type CachedResource struct {
+	// Kind of the resource, as returned by the fetch function
+	Kind Kind
+	// Data of the resource, as returned by the fetch function
+	Data []byte
+	// Hash is the hex-encoded SHA-256 hash of Data.
+	Hash string
+}
+
+// NewCachedResource returns a CachedResource of data with its Hash.
+func NewCachedResource(kind Kind, data []byte) CachedResource {
+	return CachedResource{Kind: kind, Data: data, Hash: hashResource(data)}
+}
+
+// Verify reports whether the Hash of r matches its Data.
+func (r CachedResource) Verify() bool {
+	return r.Hash != "" && r.Hash == hashResource(r.Data)
+}
+
+func hashResource(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// cached returns the resource at u from the Cache of l. Resources which can
+// not be retrieved or fail verification are reported to Hooks.OnWarning and
+// treated as absent.
+func (l *loader) cached(ctx context.Context, u uri.URI) (*CachedResource, bool) {
+	res, err := l.opts.Cache.Get(ctx, u)
+	if err == nil && res != nil && !res.Verify() {
+		err = errors.New("hash mismatch")
+	}
+	if err != nil {
+		l.opts.Hooks.warning(WarningEvent{Location: u, Message: "failed to read cached resource", Err: err})
+		return nil, false
+	}
+	return res, res != nil
+}
+
+// cache puts the resource at u into the Cache of l. Errors are reported to
+// Hooks.OnWarning.
+func (l *loader) cache(ctx context.Context, u uri.URI, k Kind, data []byte) {
+	if err := l.opts.Cache.Put(ctx, u, NewCachedResource(k, data)); err != nil {
+		l.opts.Hooks.warning(WarningEvent{Location: u, Message: "failed to cache resource", Err: err})
+	}
+}
+
+// MemoryResourceCache is a ResourceCache held in memory. The zero value is
+// ready to use.
+type MemoryResourceCache struct {
+	mu        sync.RWMutex
+	resources map[string]CachedResource
+}
+
+// Get implements ResourceCache
+func (c *MemoryResourceCache) Get(_ context.Context, u uri.URI) (*CachedResource, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	res, ok := c.resources[u.String()]
+	if !ok {
+		return nil, nil
+	}
+	return &res, nil
+}
+
+// Put implements ResourceCache
+func (c *MemoryResourceCache) Put(_ context.Context, u uri.URI, res CachedResource) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.resources == nil {
+		c.resources = make(map[string]CachedResource)
+	}
+	c.resources[u.String()] = res
+	return nil
+}
+
+// Len returns the number of cached resources.
+func (c *MemoryResourceCache) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.resources)
+}
+
+// DirResourceCache is a ResourceCache which stores each resource as a file
+// within a directory, named by the SHA-256 hash of its URI.
+type DirResourceCache struct {
+	// Dir is the directory in which resources are stored. It is created, if
+	// necessary, when a resource is first cached.
+	Dir string
+}
+
+// NewDirResourceCache returns a DirResourceCache which stores resources in
+// dir.
+func NewDirResourceCache(dir string) *DirResourceCache {
+	return &DirResourceCache{Dir: dir}
+}
+
+type dirCacheEntry struct {
+	URI  string `json:"uri"`
+	Kind string `json:"kind"`
+	Data []byte `json:"data"`
+	Hash string `json:"hash"`
+}
+
+func (c *DirResourceCache) path(u uri.URI) string {
+	return filepath.Join(c.Dir, hashResource([]byte(u.String()))+".json")
+}
+
+// Get implements ResourceCache
+func (c *DirResourceCache) Get(_ context.Context, u uri.URI) (*CachedResource, error) {
+	data, err := os.ReadFile(c.path(u))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var e dirCacheEntry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, fmt.Errorf("openapi: failed to decode cached resource %q: %w", u, err)
+	}
+	if e.URI != u.String() {
+		return nil, nil
+	}
+	k, ok := parseKind(e.Kind)
+	if !ok {
+		return nil, fmt.Errorf("openapi: unknown kind %q of cached resource %q", e.Kind, u)
+	}
+	return &CachedResource{Kind: k, Data: e.Data, Hash: e.Hash}, nil
+}
+
+// Put implements ResourceCache. The file is written atomically.
+func (c *DirResourceCache) Put(_ context.Context, u uri.URI, res CachedResource) error {
+	data, err := json.Marshal(dirCacheEntry{
+		URI:  u.String(),
+		Kind: res.Kind.String(),
+		Data: res.Data,
+		Hash: res.Hash,
+	})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(c.Dir, 0o755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(c.Dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), c.path(u))
+}
+
+// parseKind returns the Kind whose String is s
+func parseKind(s string) (Kind, bool) {
+	for k := KindUndefined; k <= KindScopes; k++ {
+		if k.String() == s {
+			return k, true
+		}
+	}
+	return KindUndefined, false
+}
+
+var (
+	_ ResourceCache = (*MemoryResourceCache)(nil)
+	_ ResourceCache = (*DirResourceCache)(nil)
+)
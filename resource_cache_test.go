@@ -0,0 +1,110 @@
+package openapi_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/chanced/openapi"
+	"github.com/chanced/uri"
+)
+
+func TestResourceCache(t *testing.T) {
+	doc := []byte(`{
+		"openapi": "3.1.0",
+		"info": { "title": "cache", "version": "1.0.0" },
+		"paths": {},
+		"components": {
+			"schemas": {
+				"Pet": { "$ref": "https://upstream.example.com/pet.json" }
+			}
+		}
+	}`)
+	pet := []byte(`{ "type": "object", "properties": { "name": { "type": "string" } } }`)
+	fetched := map[string]int{}
+	fn := func(ctx context.Context, u uri.URI, kind openapi.Kind) (openapi.Kind, []byte, error) {
+		fetched[u.String()]++
+		if u.Host == "upstream.example.com" {
+			return openapi.KindSchema, pet, nil
+		}
+		return openapi.KindDocument, doc, nil
+	}
+
+	for name, cache := range map[string]openapi.ResourceCache{
+		"memory": &openapi.MemoryResourceCache{},
+		"dir":    openapi.NewDirResourceCache(filepath.Join(t.TempDir(), "cache")),
+	} {
+		fetched = map[string]int{}
+		var cached []string
+		var warnings []openapi.WarningEvent
+		opts := openapi.LoadOpts{
+			Cache: cache,
+			Hooks: &openapi.LoadHooks{
+				OnResourceLoaded: func(e openapi.ResourceLoadedEvent) {
+					if e.Cached {
+						cached = append(cached, e.URI.String())
+					}
+				},
+				OnWarning: func(e openapi.WarningEvent) { warnings = append(warnings, e) },
+			},
+		}
+		for i := 0; i < 2; i++ {
+			d, err := openapi.Load(context.Background(), "https://example.com/cache.json", NoopValidator{}, fn, opts)
+			if err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+			if d.Components.Schemas.Get("Pet").Ref.Resolved.Properties.Get("name") == nil {
+				t.Errorf("%s: expected Pet to be resolved", name)
+			}
+		}
+		if fetched["https://example.com/cache.json"] != 2 {
+			t.Errorf("%s: expected the document to be fetched each time, got %d", name, fetched["https://example.com/cache.json"])
+		}
+		if fetched["https://upstream.example.com/pet.json"] != 1 {
+			t.Errorf("%s: expected pet.json to be fetched once, got %d", name, fetched["https://upstream.example.com/pet.json"])
+		}
+		if len(cached) != 1 || cached[0] != "https://upstream.example.com/pet.json" {
+			t.Errorf("%s: expected pet.json to be read from the cache, got %v", name, cached)
+		}
+		if len(warnings) != 0 {
+			t.Errorf("%s: unexpected warnings: %v", name, warnings)
+		}
+	}
+}
+
+func TestDirResourceCacheCorrupt(t *testing.T) {
+	dir := t.TempDir()
+	cache := openapi.NewDirResourceCache(dir)
+	u := uri.MustParse("https://upstream.example.com/pet.json")
+	res := openapi.NewCachedResource(openapi.KindSchema, []byte(`{"type":"object"}`))
+	if err := cache.Put(context.Background(), *u, res); err != nil {
+		t.Fatal(err)
+	}
+	got, err := cache.Get(context.Background(), *u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got == nil || got.Kind != openapi.KindSchema || !got.Verify() {
+		t.Fatalf("expected the cached schema, got %+v", got)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("expected a single file, got %v (%v)", entries, err)
+	}
+	got.Data = []byte(`{"type":"string"}`)
+	if got.Verify() {
+		t.Error("expected modified data to fail verification")
+	}
+	if got, err := cache.Get(context.Background(), *uri.MustParse("https://upstream.example.com/other.json")); err != nil || got != nil {
+		t.Errorf("expected a miss, got %v, %v", got, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, entries[0].Name()), []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.Get(context.Background(), *u); err == nil {
+		t.Error("expected an error for a corrupt entry")
+	}
+}
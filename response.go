@@ -3,7 +3,6 @@ package openapi
 import (
 	"encoding/json"
 
-	"github.com/chanced/transcode"
 	"gopkg.in/yaml.v3"
 )
 
@@ -49,6 +48,21 @@ type Response struct {
 	Location `json:"-"`
 }
 
+// Header returns the header name of the Response, matched case-insensitively
+// (e.g. "X-Rate-Limit" matches "x-rate-limit"). An exact match is preferred.
+func (r *Response) Header(name Text) *Component[*Header] {
+	if r == nil {
+		return nil
+	}
+	return lookupHeader(r.Headers, name)
+}
+
+// HasHeader reports whether the Response has the header name, matched
+// case-insensitively.
+func (r *Response) HasHeader(name Text) bool {
+	return r.Header(name) != nil
+}
+
 func (r *Response) Nodes() []Node {
 	if r == nil {
 		return nil
@@ -123,11 +137,7 @@ func (r Response) MarshalYAML() (interface{}, error) {
 
 // UnmarshalYAML satisfies gopkg.in/yaml.v3 Unmarshaler interface
 func (r *Response) UnmarshalYAML(value *yaml.Node) error {
-	v, err := yaml.Marshal(value)
-	if err != nil {
-		return err
-	}
-	j, err := transcode.JSONFromYAML(v)
+	j, err := jsonFromYAMLNode(value)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,188 @@
+package openapi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ResponseClass is the class of an HTTP status code, indicated by its first
+// digit.
+type ResponseClass int
+
+const (
+	ResponseClassInformational ResponseClass = 1
+	ResponseClassSuccess       ResponseClass = 2
+	ResponseClassRedirection   ResponseClass = 3
+	ResponseClassClientError   ResponseClass = 4
+	ResponseClassServerError   ResponseClass = 5
+)
+
+// String returns the range of the class (e.g. "4XX")
+func (c ResponseClass) String() string {
+	return strconv.Itoa(int(c)) + "XX"
+}
+
+// ResponseCode is a parsed key of a ResponseMap: an HTTP status code (e.g.
+// "404"), a range of status codes (e.g. "4XX"), or "default".
+type ResponseCode struct {
+	// Code is the status code or, for a range or the default, 0.
+	Code int
+	// Class is the class of the status code or range or, for the default,
+	// 0.
+	Class ResponseClass
+}
+
+// ResponseCodeDefault is the ResponseCode of the key "default".
+var ResponseCodeDefault = ResponseCode{}
+
+// ParseResponseCode parses the key of a ResponseMap. The "X" of a range may
+// be lowercase, though the specification requires it to be uppercase.
+//
+// An error wrapping ErrInvalidResponseCode is returned if key is not a status
+// code between 100 and 599, a range between "1XX" and "5XX", or "default".
+func ParseResponseCode(key Text) (ResponseCode, error) {
+	k := key.String()
+	if k == "default" {
+		return ResponseCodeDefault, nil
+	}
+	if len(k) != 3 || k[0] < '1' || k[0] > '5' {
+		return ResponseCode{}, fmt.Errorf("%w: %q", ErrInvalidResponseCode, k)
+	}
+	class := ResponseClass(k[0] - '0')
+	if strings.EqualFold(k[1:], "XX") {
+		return ResponseCode{Class: class}, nil
+	}
+	code, err := strconv.Atoi(k)
+	if err != nil || k[1] < '0' || k[1] > '9' || k[2] < '0' || k[2] > '9' {
+		return ResponseCode{}, fmt.Errorf("%w: %q", ErrInvalidResponseCode, k)
+	}
+	return ResponseCode{Code: code, Class: class}, nil
+}
+
+// IsDefault reports whether rc is "default".
+func (rc ResponseCode) IsDefault() bool { return rc == ResponseCodeDefault }
+
+// IsRange reports whether rc is a range of status codes (e.g. "4XX").
+func (rc ResponseCode) IsRange() bool { return rc.Code == 0 && rc.Class != 0 }
+
+// Matches reports whether status is described by rc. The default matches
+// every status.
+func (rc ResponseCode) Matches(status int) bool {
+	switch {
+	case rc.IsDefault():
+		return true
+	case rc.IsRange():
+		return status/100 == int(rc.Class)
+	default:
+		return status == rc.Code
+	}
+}
+
+// String returns the canonical key of rc (e.g. "404", "4XX", or "default").
+func (rc ResponseCode) String() string {
+	switch {
+	case rc.IsDefault():
+		return "default"
+	case rc.IsRange():
+		return rc.Class.String()
+	default:
+		return strconv.Itoa(rc.Code)
+	}
+}
+
+// MatchResponse returns the entry of responses which describes status,
+// following the precedence of the specification: an entry for the status
+// code takes precedence over an entry for its range (e.g. "4XX"), which takes
+// precedence over "default". Keys which can not be parsed are ignored. If no
+// entry matches, nil is returned.
+func MatchResponse(responses *ResponseMap, status int) *ComponentEntry[*Response] {
+	if responses == nil {
+		return nil
+	}
+	var rng, def *ComponentEntry[*Response]
+	for _, e := range responses.Items {
+		rc, err := ParseResponseCode(e.Key)
+		if err != nil || !rc.Matches(status) {
+			continue
+		}
+		switch {
+		case rc.IsDefault():
+			if def == nil {
+				def = e
+			}
+		case rc.IsRange():
+			if rng == nil {
+				rng = e
+			}
+		default:
+			return e
+		}
+	}
+	if rng != nil {
+		return rng
+	}
+	return def
+}
+
+// ResponseClasses returns the classes of status codes documented by
+// responses, either by status code or by range, in ascending order. The
+// default is not considered to document any class.
+func ResponseClasses(responses *ResponseMap) []ResponseClass {
+	if responses == nil {
+		return nil
+	}
+	var documented [ResponseClassServerError + 1]bool
+	for _, e := range responses.Items {
+		if rc, err := ParseResponseCode(e.Key); err == nil && !rc.IsDefault() {
+			documented[rc.Class] = true
+		}
+	}
+	var classes []ResponseClass
+	for c := ResponseClassInformational; c <= ResponseClassServerError; c++ {
+		if documented[c] {
+			classes = append(classes, c)
+		}
+	}
+	return classes
+}
+
+// UndocumentedResponseClasses returns which of the success (2XX), client
+// error (4XX), and server error (5XX) classes of status codes are not
+// documented by responses, either by status code or by range. A default
+// Response is not considered to document any class.
+func UndocumentedResponseClasses(responses *ResponseMap) []ResponseClass {
+	documented := ResponseClasses(responses)
+	var classes []ResponseClass
+	for _, c := range []ResponseClass{ResponseClassSuccess, ResponseClassClientError, ResponseClassServerError} {
+		found := false
+		for _, d := range documented {
+			found = found || d == c
+		}
+		if !found {
+			classes = append(classes, c)
+		}
+	}
+	return classes
+}
+
+// validateResponseCodes ensures that each key of the responses of each
+// Operation is a legal, canonical response code.
+func validateResponseCodes(doc *Document) error {
+	for _, op := range doc.Operations() {
+		if op.Operation.Responses == nil {
+			continue
+		}
+		for _, e := range op.Operation.Responses.Items {
+			rc, err := ParseResponseCode(e.Key)
+			if err == nil && rc.String() != e.Key.String() {
+				err = fmt.Errorf("%w: %q must be %q", ErrInvalidResponseCode, e.Key, rc.String())
+			}
+			if err != nil {
+				loc := op.Operation.Responses.Location.AppendLocation(e.Key.String()).AbsoluteLocation()
+				return NewValidationError(err, KindResponseMap, loc)
+			}
+		}
+	}
+	return nil
+}
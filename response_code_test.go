@@ -0,0 +1,108 @@
+package openapi_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/chanced/openapi"
+	"github.com/chanced/uri"
+)
+
+func TestParseResponseCode(t *testing.T) {
+	tests := []struct {
+		key      openapi.Text
+		expected openapi.ResponseCode
+		str      string
+	}{
+		{"200", openapi.ResponseCode{Code: 200, Class: openapi.ResponseClassSuccess}, "200"},
+		{"599", openapi.ResponseCode{Code: 599, Class: openapi.ResponseClassServerError}, "599"},
+		{"4XX", openapi.ResponseCode{Class: openapi.ResponseClassClientError}, "4XX"},
+		{"4xx", openapi.ResponseCode{Class: openapi.ResponseClassClientError}, "4XX"},
+		{"default", openapi.ResponseCodeDefault, "default"},
+	}
+	for _, test := range tests {
+		rc, err := openapi.ParseResponseCode(test.key)
+		if err != nil {
+			t.Errorf("%s: %v", test.key, err)
+			continue
+		}
+		if rc != test.expected || rc.String() != test.str {
+			t.Errorf("%s: expected %v (%s), got %v (%s)", test.key, test.expected, test.str, rc, rc)
+		}
+	}
+	for _, key := range []openapi.Text{"", "600", "099", "20", "2000", "2X", "20X", "-20", "+20", "Default"} {
+		if _, err := openapi.ParseResponseCode(key); !errors.Is(err, openapi.ErrInvalidResponseCode) {
+			t.Errorf("%q: expected ErrInvalidResponseCode, got %v", key, err)
+		}
+	}
+}
+
+func TestMatchResponse(t *testing.T) {
+	responses := openapi.ResponseMap{}
+	for _, key := range []openapi.Text{"default", "4XX", "404", "2XX", "201"} {
+		responses.SetObject(key, &openapi.Response{Description: key})
+	}
+	for status, expected := range map[int]openapi.Text{
+		201: "201",
+		200: "2XX",
+		404: "404",
+		400: "4XX",
+		500: "default",
+		302: "default",
+	} {
+		if e := openapi.MatchResponse(&responses, status); e == nil || e.Key != expected {
+			t.Errorf("%d: expected %s, got %v", status, expected, e)
+		}
+	}
+	if e := openapi.MatchResponse(nil, 200); e != nil {
+		t.Errorf("expected no match, got %v", e)
+	}
+
+	classes := openapi.UndocumentedResponseClasses(&responses)
+	if len(classes) != 1 || classes[0] != openapi.ResponseClassServerError {
+		t.Errorf("expected 5XX to be undocumented, got %v", classes)
+	}
+	responses.Del("4XX")
+	responses.Del("404")
+	classes = openapi.UndocumentedResponseClasses(&responses)
+	if len(classes) != 2 || classes[0] != openapi.ResponseClassClientError || classes[1].String() != "5XX" {
+		t.Errorf("expected 4XX and 5XX to be undocumented, got %v", classes)
+	}
+}
+
+func TestValidateResponseCodes(t *testing.T) {
+	data := []byte(`{
+		"openapi": "3.1.0",
+		"info": { "title": "codes", "version": "1.0.0" },
+		"paths": {
+			"/pets": {
+				"get": {
+					"responses": {
+						"200": { "description": "ok" },
+						"4xx": { "description": "error" }
+					}
+				}
+			}
+		}
+	}`)
+	fn := func(ctx context.Context, uri uri.URI, kind openapi.Kind) (openapi.Kind, []byte, error) {
+		return openapi.KindDocument, data, nil
+	}
+	v, err := openapi.NewOfflineValidator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := openapi.Load(context.Background(), "https://example.com/codes.json", NoopValidator{}, fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = v.ValidateDocument(doc)
+	if !errors.Is(err, openapi.ErrInvalidResponseCode) {
+		t.Fatalf("expected ErrInvalidResponseCode, got %v", err)
+	}
+	var ve *openapi.ValidationError
+	if !errors.As(err, &ve) || ve.URI.Fragment != "/paths/~1pets/get/responses/4xx" {
+		t.Errorf("expected the error to be located at the response, got %v", err)
+	}
+}
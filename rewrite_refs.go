@@ -0,0 +1,179 @@
+package openapi
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/chanced/uri"
+)
+
+// RewriteRefs rebases the reference URIs of doc for when the document is
+// moved, or served, from fromBase to toBase. Relative references, which were
+// resolved against fromBase, are rewritten so that they resolve to the same
+// resources relative to toBase.
+//
+// The following are rewritten:
+//   - the $ref of each Reference, SchemaRef, and OperationRef
+//   - the $id of each Schema which is not nested within a Schema with an $id
+//   - the externalValue of each Example
+//   - the values of each Discriminator mapping which are references
+//
+// References consisting solely of a fragment (e.g. "#/components/schemas/Pet"
+// or "#anchor") are left as is, as are absolute URIs and references within
+// Schemas which have an $id, as they are resolved relative to the $id.
+// References to the document itself (e.g. "openapi.yaml#/components") are
+// rewritten as fragments.
+//
+// Only the nodes of doc are modified; referenced resources are not.
+func RewriteRefs(doc *Document, fromBase, toBase uri.URI) error {
+	if doc == nil {
+		return nil
+	}
+	from, err := url.Parse(fromBase.String())
+	if err != nil {
+		return fmt.Errorf("openapi: invalid base %q: %w", fromBase.String(), err)
+	}
+	to, err := url.Parse(toBase.String())
+	if err != nil {
+		return fmt.Errorf("openapi: invalid base %q: %w", toBase.String(), err)
+	}
+	rebase := func(u *uri.URI, loc uri.URI) error {
+		if u == nil {
+			return nil
+		}
+		ref, err := url.Parse(u.String())
+		if err != nil {
+			return NewError(fmt.Errorf("openapi: failed to parse reference %q: %w", u.String(), err), loc)
+		}
+		s, ok := rebaseRef(ref, from, to)
+		if !ok {
+			return nil
+		}
+		nu, err := uri.Parse(s)
+		if err != nil {
+			return NewError(fmt.Errorf("openapi: failed to parse rebased reference %q: %w", s, err), loc)
+		}
+		*u = *nu
+		return nil
+	}
+
+	// nodes within Schemas which have an $id are resolved relative to the
+	// $id rather than the document
+	scoped := map[node]bool{}
+	_ = walkLocalNodes(doc, func(n node) error {
+		s, ok := n.(*Schema)
+		if !ok || s.ID == nil || scoped[s] {
+			return nil
+		}
+		return walkLocalNodes(s, func(c node) error {
+			if c != n {
+				scoped[c] = true
+			}
+			return nil
+		})
+	})
+
+	return walkLocalNodes(doc, func(n node) error {
+		if scoped[n] {
+			return nil
+		}
+		switch v := n.(type) {
+		case *Schema:
+			return rebase(v.ID, v.AbsoluteLocation())
+		case *Example:
+			return rebase(v.ExternalValue, v.AbsoluteLocation())
+		case *Discriminator:
+			if v.Mapping == nil {
+				return nil
+			}
+			for i, kv := range v.Mapping.Items {
+				if !kv.Value.Contains("/") && !kv.Value.Contains("#") {
+					// the value is the name of a component
+					continue
+				}
+				u, err := uri.Parse(kv.Value.String())
+				if err != nil {
+					return NewError(fmt.Errorf("openapi: failed to parse discriminator mapping %q: %w", kv.Value, err), v.AbsoluteLocation())
+				}
+				if err = rebase(u, v.AbsoluteLocation()); err != nil {
+					return err
+				}
+				v.Mapping.Items[i].Value = Text(u.String())
+			}
+		case Ref:
+			return rebase(v.URI(), v.AbsoluteLocation())
+		}
+		return nil
+	})
+}
+
+// rebaseRef returns ref, which is relative to from, relative to to. If ref
+// does not need to be rewritten, false is returned.
+func rebaseRef(ref, from, to *url.URL) (string, bool) {
+	if ref.Scheme != "" || ref.Host != "" || ref.Opaque != "" {
+		return "", false
+	}
+	if ref.Path == "" && ref.RawQuery == "" && !ref.ForceQuery {
+		return "", false
+	}
+	target := from.ResolveReference(ref)
+	if sameResource(target, from) {
+		return "#" + target.EscapedFragment(), true
+	}
+	return relativeURL(to, target), true
+}
+
+func sameResource(a, b *url.URL) bool {
+	return a.Scheme == b.Scheme && a.Host == b.Host && a.EscapedPath() == b.EscapedPath() && a.RawQuery == b.RawQuery
+}
+
+// relativeURL returns target as a reference relative to base or, if target
+// is not on the same host as base, target as is
+func relativeURL(base, target *url.URL) string {
+	if target.Scheme != base.Scheme || target.Host != base.Host || base.Opaque != "" || target.Opaque != "" {
+		return target.String()
+	}
+	var suffix string
+	if target.RawQuery != "" || target.ForceQuery {
+		suffix = "?" + target.RawQuery
+	}
+	if target.Fragment != "" {
+		suffix += "#" + target.EscapedFragment()
+	}
+	bp, tp := base.EscapedPath(), target.EscapedPath()
+	if strings.HasPrefix(tp, "/") != strings.HasPrefix(bp, "/") {
+		return target.String()
+	}
+	if tp == bp {
+		if target.RawQuery == base.RawQuery {
+			return "#" + target.EscapedFragment()
+		}
+		return lastPathSegment(tp) + suffix
+	}
+	dir := strings.Split(bp, "/")
+	dir = dir[:len(dir)-1]
+	segs := strings.Split(tp, "/")
+	i := 0
+	for i < len(dir) && i < len(segs)-1 && dir[i] == segs[i] {
+		i++
+	}
+	rel := strings.Repeat("../", len(dir)-i) + strings.Join(segs[i:], "/")
+	if rel == "" {
+		rel = "./"
+	} else if first := strings.SplitN(rel, "/", 2)[0]; strings.Contains(first, ":") {
+		// a colon in the first segment would be mistaken for a scheme
+		rel = "./" + rel
+	}
+	return rel + suffix
+}
+
+func lastPathSegment(p string) string {
+	if i := strings.LastIndexByte(p, '/'); i >= 0 {
+		p = p[i+1:]
+	}
+	if p == "" {
+		return "./"
+	}
+	return p
+}
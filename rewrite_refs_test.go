@@ -0,0 +1,64 @@
+package openapi_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/chanced/openapi"
+	"github.com/chanced/uri"
+)
+
+func TestRewriteRefs(t *testing.T) {
+	data := []byte(`{
+		"openapi": "3.1.0",
+		"info": { "title": "rewrite refs", "version": "1.0.0" },
+		"paths": {},
+		"components": {
+			"schemas": {
+				"Pet": {
+					"type": "object",
+					"properties": {
+						"self": { "$ref": "#/components/schemas/Pet" },
+						"anchored": { "$ref": "#pet" },
+						"owner": { "$ref": "schemas/owner.json#/properties/name" },
+						"same": { "$ref": "openapi.json#/components/schemas/Pet" },
+						"remote": { "$ref": "https://other.example.com/tag.json" }
+					},
+					"$anchor": "pet"
+				}
+			}
+		}
+	}`)
+	schema := []byte(`{ "type": "object", "properties": { "name": { "type": "string" } } }`)
+	fn := func(ctx context.Context, uri uri.URI, kind openapi.Kind) (openapi.Kind, []byte, error) {
+		if uri.String() == "https://example.com/api/openapi.json" {
+			return openapi.KindDocument, data, nil
+		}
+		return openapi.KindSchema, schema, nil
+	}
+	doc, err := openapi.Load(context.Background(), "https://example.com/api/openapi.json", NoopValidator{}, fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	from := uri.MustParse("https://example.com/api/openapi.json")
+	to := uri.MustParse("https://example.com/v2/spec/openapi.json")
+	if err = openapi.RewriteRefs(doc, *from, *to); err != nil {
+		t.Fatal(err)
+	}
+	props := doc.Components.Schemas.Get("Pet").Properties
+	expected := map[openapi.Text]string{
+		"self":     "#/components/schemas/Pet",
+		"anchored": "#pet",
+		"owner":    "../../api/schemas/owner.json#/properties/name",
+		"same":     "#/components/schemas/Pet",
+		"remote":   "https://other.example.com/tag.json",
+	}
+	for key, ref := range expected {
+		if s := props.Get(key).Ref.Ref.String(); s != ref {
+			t.Errorf("expected $ref of %s to be %q, got %q", key, ref, s)
+		}
+	}
+	if props.Get("owner").Ref.Resolved == nil {
+		t.Error("expected the resolved schema to be retained")
+	}
+}
@@ -1,7 +1,6 @@
 package openapi
 
 import (
-	"bytes"
 	"encoding/json"
 	"errors"
 	"reflect"
@@ -10,8 +9,8 @@ import (
 	"github.com/chanced/caps/text"
 	"github.com/chanced/jsonx"
 	"github.com/chanced/maps"
-	"github.com/chanced/transcode"
 	"github.com/chanced/uri"
+	"github.com/tidwall/gjson"
 	"gopkg.in/yaml.v3"
 )
 
@@ -133,9 +132,10 @@ type Schema struct {
 
 	// The enum keyword is used to restrict a value to a fixed set of values. It
 	// must be an array with at least one element, where each element is unique.
+	// Elements may be of any type.
 	//
 	// https://json-schema.org/understanding-json-schema/reference/generic.html?highlight=const#enumerated-values
-	Enum Texts `json:"enum,omitempty"`
+	Enum Enum `json:"enum,omitempty"`
 
 	// The $comment keyword is strictly intended for adding comments to a
 	// schema. Its value must always be a string. Unlike the annotations title,
@@ -316,6 +316,10 @@ type Schema struct {
 	Definitions *SchemaMap `json:"$defs,omitempty"`
 
 	Keywords map[Text]jsonx.RawMessage `json:"-"`
+
+	// boolean is set if the Schema was decoded from, or constructed as, a
+	// boolean Schema. See Bool.
+	boolean *bool
 }
 
 func (s *Schema) Nodes() []Node {
@@ -498,41 +502,39 @@ func (s *Schema) Anchors() (*Anchors, error) {
 // MarshalJSON marshals JSON
 func (s Schema) MarshalJSON() ([]byte, error) {
 	type schema Schema
-	b := bytes.Buffer{}
 	data, err := json.Marshal(schema(s))
 	if err != nil {
 		return nil, err
 	}
+	b := getBuffer()
 	// trimming the last }
 	b.Write(data[:len(data)-1])
 
-	if len(s.Keywords) == 0 && len(s.Extensions) == 0 && b.Len() < 10 {
-		bs := b.String()
-		switch bs {
-		case "{":
+	if s.boolean != nil && len(s.Keywords) == 0 && len(s.Extensions) == 0 && b.String() == "{" {
+		putBuffer(b)
+		if *s.boolean {
 			return []byte("true"), nil
-		case `{"not":true`:
-			return []byte("false"), nil
 		}
+		return []byte("false"), nil
 	}
 	if s.Keywords != nil {
 		for _, kv := range maps.SortByKeys(s.Keywords) {
 			if b.Len() > 2 {
 				b.WriteString(",")
 			}
-			jsonx.EncodeAndWriteString(&b, kv.Key)
+			jsonx.EncodeAndWriteString(b, kv.Key)
 			b.WriteByte(':')
 			if kv.Value != nil {
 				bb, err := json.Marshal(kv.Value)
 				if err != nil {
+					putBuffer(b)
 					return nil, err
 				}
 				b.Write(bb)
 			}
 		}
 	}
-	b.WriteByte('}')
-	return b.Bytes(), err
+	return marshalExtensionsInto(b, s.Extensions)
 }
 
 // UnmarshalJSON unmarshals JSON
@@ -549,45 +551,47 @@ func (s *Schema) UnmarshalJSON(data []byte) error {
 }
 
 func (s *Schema) unmarshalJSONBool(data []byte) error {
-	if jsonx.IsTrue(data) {
-		*s = Schema{}
-		return nil
-	} else {
-		*s = Schema{Not: &Schema{}}
-		return nil
-	}
+	*s = *NewBoolSchema(jsonx.IsTrue(data))
+	return nil
 }
 
 func (s *Schema) unmarshalJSONObj(data []byte) error {
-	res := Schema{}
-
-	d := map[Text]jsonx.RawMessage{}
-	err := json.Unmarshal(data, &d)
-	if err != nil {
-		return err
+	if !gjson.ValidBytes(data) {
+		// gjson parses malformed input without error; encoding/json reports
+		// the syntax error
+		var v interface{}
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
 	}
-	fields := res.fields()
-	for k, v := range d {
-		if f, ok := fields[k.String()]; ok {
-			err = json.Unmarshal(v, f)
-			if err != nil {
-				return err
-			}
-		} else if strings.HasPrefix(k.String(), "x-") {
+	res := Schema{}
+	var err error
+	gjson.ParseBytes(data).ForEach(func(key, value gjson.Result) bool {
+		k := key.String()
+		if res.unmarshalJSONScalar(k, value) {
+			return true
+		}
+		if f := res.field(k); f != nil {
+			err = json.Unmarshal([]byte(value.Raw), f)
+			return err == nil
+		}
+		if strings.HasPrefix(k, "x-") {
 			if res.Extensions == nil {
 				res.Extensions = Extensions{}
 			}
-			res.Extensions[k] = v
+			res.Extensions[intern(k)] = jsonx.RawMessage(value.Raw)
 		} else {
 			if res.Keywords == nil {
 				res.Keywords = make(map[Text]jsonx.RawMessage)
 			}
-			res.Keywords[k] = v
+			res.Keywords[intern(k)] = jsonx.RawMessage(value.Raw)
 		}
-	}
+		return true
+	})
 	if err != nil {
 		return err
 	}
+	internTexts(res.Required)
 	if res.Ref != nil {
 		res.Ref.SchemaRefKind = SchemaRefTypeRef
 	}
@@ -602,6 +606,66 @@ func (s *Schema) unmarshalJSONObj(data []byte) error {
 	return nil
 }
 
+// unmarshalJSONScalar assigns string and boolean valued keywords directly
+// from value, avoiding a round trip through encoding/json. It reports whether
+// key was handled.
+func (s *Schema) unmarshalJSONScalar(key string, value gjson.Result) bool {
+	switch value.Type {
+	case gjson.String:
+		var dst *Text
+		switch key {
+		case "type":
+			s.Type = Types{Type(intern(value.String()))}
+			return true
+		case "$anchor":
+			dst = &s.Anchor
+		case "$dynamicAnchor":
+			dst = &s.DynamicAnchor
+		case "format":
+			dst = &s.Format
+		case "$comment":
+			dst = &s.Comments
+		case "contentEncoding":
+			dst = &s.ContentEncoding
+		case "contentMediaType":
+			dst = &s.ContentMediaType
+		case "title":
+			dst = &s.Title
+		case "description":
+			dst = &s.Description
+		case "externalDocs":
+			dst = &s.ExternalDocs
+		default:
+			return false
+		}
+		*dst = intern(value.String())
+		return true
+	case gjson.True, gjson.False:
+		var dst **bool
+		switch key {
+		case "$recursiveAnchor":
+			dst = &s.RecursiveAnchor
+		case "regexProperties":
+			dst = &s.RegexProperties
+		case "uniqueItems":
+			dst = &s.UniqueItems
+		case "readOnly":
+			dst = &s.ReadOnly
+		case "writeOnly":
+			dst = &s.WriteOnly
+		case "deprecated":
+			dst = &s.Deprecated
+		default:
+			return false
+		}
+		b := value.Bool()
+		*dst = &b
+		return true
+	default:
+		return false
+	}
+}
+
 // SetKeyword marshals value and sets the encoded json to key in Keywords
 //
 // If setting the value as []byte, it should be in the form of json.RawMessage
@@ -637,69 +701,134 @@ func (s *Schema) DecodeKeywords(dst interface{}) error {
 	return json.Unmarshal(data, dst)
 }
 
-func (s *Schema) fields() map[string]interface{} {
-	return map[string]interface{}{
-		"$schema":               &s.Schema,
-		"$id":                   &s.ID,
-		"type":                  &s.Type,
-		"$ref":                  &s.Ref,
-		"$defs":                 &s.Definitions,
-		"format":                &s.Format,
-		"$dynamicAnchor":        &s.DynamicAnchor,
-		"$dynamicRef":           &s.DynamicRef,
-		"$anchor":               &s.Anchor,
-		"const":                 &s.Const,
-		"enum":                  &s.Enum,
-		"$comment":              &s.Comments,
-		"not":                   &s.Not,
-		"allOf":                 &s.AllOf,
-		"anyOf":                 &s.AnyOf,
-		"oneOf":                 &s.OneOf,
-		"if":                    &s.If,
-		"then":                  &s.Then,
-		"else":                  &s.Else,
-		"minProperties":         &s.MinProperties,
-		"maxProperties":         &s.MaxProperties,
-		"required":              &s.Required,
-		"properties":            &s.Properties,
-		"propertyNames":         &s.PropertyNames,
-		"regexProperties":       &s.RegexProperties,
-		"patternProperties":     &s.PatternProperties,
-		"additionalProperties":  &s.AdditionalProperties,
-		"dependentRequired":     &s.DependentRequired,
-		"dependentSchemas":      &s.DependentSchemas,
-		"unevaluatedProperties": &s.UnevaluatedProperties,
-		"uniqueItems":           &s.UniqueItems,
-		"items":                 &s.Items,
-		"unevaluatedItems":      &s.UnevaluatedItems,
-		"additionalItems":       &s.AdditionalItems,
-		"prefixItems":           &s.PrefixItems,
-		"contains":              &s.Contains,
-		"minContains":           &s.MinContains,
-		"maxContains":           &s.MaxContains,
-		"minLength":             &s.MinLength,
-		"maxLength":             &s.MaxLength,
-		"pattern":               &s.Pattern,
-		"contentEncoding":       &s.ContentEncoding,
-		"contentMediaType":      &s.ContentMediaType,
-		"minimum":               &s.Minimum,
-		"exclusiveMinimum":      &s.ExclusiveMinimum,
-		"maximum":               &s.Maximum,
-		"exclusiveMaximum":      &s.ExclusiveMaximum,
-		"multipleOf":            &s.MultipleOf,
-		"title":                 &s.Title,
-		"description":           &s.Description,
-		"default":               &s.Default,
-		"readOnly":              &s.ReadOnly,
-		"writeOnly":             &s.WriteOnly,
-		"examples":              &s.Examples,
-		"example":               &s.Example,
-		"deprecated":            &s.Deprecated,
-		"externalDocs":          &s.ExternalDocs,
-		"$recursiveAnchor":      &s.RecursiveAnchor,
-		"$recursiveRef":         &s.RecursiveRef,
-		"discriminator":         &s.Discriminator,
-		"xml":                   &s.XML,
+// field returns a pointer to the field of s which corresponds to the
+// keyword key or nil if key is not a keyword defined by Schema.
+func (s *Schema) field(key string) interface{} {
+	switch key {
+	case "$schema":
+		return &s.Schema
+	case "$id":
+		return &s.ID
+	case "type":
+		return &s.Type
+	case "$ref":
+		return &s.Ref
+	case "$defs":
+		return &s.Definitions
+	case "format":
+		return &s.Format
+	case "$dynamicAnchor":
+		return &s.DynamicAnchor
+	case "$dynamicRef":
+		return &s.DynamicRef
+	case "$anchor":
+		return &s.Anchor
+	case "const":
+		return &s.Const
+	case "enum":
+		return &s.Enum
+	case "$comment":
+		return &s.Comments
+	case "not":
+		return &s.Not
+	case "allOf":
+		return &s.AllOf
+	case "anyOf":
+		return &s.AnyOf
+	case "oneOf":
+		return &s.OneOf
+	case "if":
+		return &s.If
+	case "then":
+		return &s.Then
+	case "else":
+		return &s.Else
+	case "minProperties":
+		return &s.MinProperties
+	case "maxProperties":
+		return &s.MaxProperties
+	case "required":
+		return &s.Required
+	case "properties":
+		return &s.Properties
+	case "propertyNames":
+		return &s.PropertyNames
+	case "regexProperties":
+		return &s.RegexProperties
+	case "patternProperties":
+		return &s.PatternProperties
+	case "additionalProperties":
+		return &s.AdditionalProperties
+	case "dependentRequired":
+		return &s.DependentRequired
+	case "dependentSchemas":
+		return &s.DependentSchemas
+	case "unevaluatedProperties":
+		return &s.UnevaluatedProperties
+	case "uniqueItems":
+		return &s.UniqueItems
+	case "items":
+		return &s.Items
+	case "unevaluatedItems":
+		return &s.UnevaluatedItems
+	case "additionalItems":
+		return &s.AdditionalItems
+	case "prefixItems":
+		return &s.PrefixItems
+	case "contains":
+		return &s.Contains
+	case "minContains":
+		return &s.MinContains
+	case "maxContains":
+		return &s.MaxContains
+	case "minLength":
+		return &s.MinLength
+	case "maxLength":
+		return &s.MaxLength
+	case "pattern":
+		return &s.Pattern
+	case "contentEncoding":
+		return &s.ContentEncoding
+	case "contentMediaType":
+		return &s.ContentMediaType
+	case "minimum":
+		return &s.Minimum
+	case "exclusiveMinimum":
+		return &s.ExclusiveMinimum
+	case "maximum":
+		return &s.Maximum
+	case "exclusiveMaximum":
+		return &s.ExclusiveMaximum
+	case "multipleOf":
+		return &s.MultipleOf
+	case "title":
+		return &s.Title
+	case "description":
+		return &s.Description
+	case "default":
+		return &s.Default
+	case "readOnly":
+		return &s.ReadOnly
+	case "writeOnly":
+		return &s.WriteOnly
+	case "examples":
+		return &s.Examples
+	case "example":
+		return &s.Example
+	case "deprecated":
+		return &s.Deprecated
+	case "externalDocs":
+		return &s.ExternalDocs
+	case "$recursiveAnchor":
+		return &s.RecursiveAnchor
+	case "$recursiveRef":
+		return &s.RecursiveRef
+	case "discriminator":
+		return &s.Discriminator
+	case "xml":
+		return &s.XML
+	default:
+		return nil
 	}
 }
 
@@ -805,11 +934,7 @@ func (s Schema) MarshalYAML() (interface{}, error) {
 
 // UnmarshalYAML satisfies gopkg.in/yaml.v3 Unmarshaler interface
 func (s *Schema) UnmarshalYAML(value *yaml.Node) error {
-	v, err := yaml.Marshal(value)
-	if err != nil {
-		return err
-	}
-	j, err := transcode.JSONFromYAML(v)
+	j, err := jsonFromYAMLNode(value)
 	if err != nil {
 		return err
 	}
@@ -847,17 +972,13 @@ func (s *Schema) Clone() *Schema {
 		examples = make([]jsonx.RawMessage, len(s.Examples))
 		copy(examples, s.Examples)
 	}
-	var enum text.Texts
-	if s.Enum != nil {
-		enum = make(text.Texts, len(s.Enum))
-		copy(enum, s.Enum)
-	}
-	var minprops *jsonx.Number
+	enum := s.Enum.Clone()
+	var minprops *Number
 	if s.MinProperties != nil {
 		v := *s.MinProperties
 		minprops = &v
 	}
-	var maxprops *jsonx.Number
+	var maxprops *Number
 	if s.MaxProperties != nil {
 		v := *s.MaxProperties
 		maxprops = &v
@@ -878,48 +999,48 @@ func (s *Schema) Clone() *Schema {
 		v := *s.UniqueItems
 		uniqItems = &v
 	}
-	var minContains *jsonx.Number
+	var minContains *Number
 	if s.MinContains != nil {
 		v := *s.MinContains
 		minContains = &v
 	}
-	var maxContains *jsonx.Number
+	var maxContains *Number
 	if s.MaxContains != nil {
 		v := *s.MaxContains
 		maxContains = &v
 	}
-	var minLen *jsonx.Number
+	var minLen *Number
 	if s.MinLength != nil {
 		v := *s.MinLength
 		minLen = &v
 	}
-	var maxLen *jsonx.Number
+	var maxLen *Number
 	if s.MaxLength != nil {
 		v := *s.MaxLength
 		maxLen = &v
 	}
-	var min *jsonx.Number
+	var min *Number
 	if s.Minimum != nil {
 		v := *s.Minimum
 		min = &v
 	}
-	var max *jsonx.Number
+	var max *Number
 	if s.Maximum != nil {
 		v := *s.Maximum
 		max = &v
 	}
 
-	var exclMin *jsonx.Number
+	var exclMin *Number
 	if s.ExclusiveMinimum != nil {
 		v := *s.ExclusiveMinimum
 		exclMin = &v
 	}
-	var exclMax *jsonx.Number
+	var exclMax *Number
 	if s.ExclusiveMaximum != nil {
 		v := *s.ExclusiveMaximum
 		exclMax = &v
 	}
-	var multipleOf *jsonx.Number
+	var multipleOf *Number
 	if s.MultipleOf != nil {
 		v := *s.MultipleOf
 		multipleOf = &v
@@ -950,9 +1071,11 @@ func (s *Schema) Clone() *Schema {
 	if s.ID != nil {
 		id = s.ID.Clone()
 	}
-	var pattern *Regexp
-	if s.Pattern != nil {
-		pattern = &Regexp{s.Pattern.Copy()}
+	pattern := s.Pattern.Clone()
+	var boolean *bool
+	if s.boolean != nil {
+		v := *s.boolean
+		boolean = &v
 	}
 	cloned := &Schema{
 		RecursiveAnchor:       recAnc,
@@ -979,6 +1102,7 @@ func (s *Schema) Clone() *Schema {
 		WriteOnly:             writeOnly,
 		Deprecated:            deprecated,
 		Keywords:              k,
+		boolean:               boolean,
 		Schema:                s.Schema,
 		ID:                    id,
 		Title:                 s.Title,
@@ -0,0 +1,68 @@
+package openapi
+
+import "strconv"
+
+// AdditionalPropertiesKind is the form of the additionalProperties keyword of
+// a Schema.
+type AdditionalPropertiesKind uint8
+
+const (
+	// AdditionalPropertiesAbsent indicates that additionalProperties is not
+	// set. Additional properties are permitted.
+	AdditionalPropertiesAbsent AdditionalPropertiesKind = iota
+	// AdditionalPropertiesAllowed indicates that additionalProperties is
+	// true (or {}). Additional properties of any value are permitted.
+	AdditionalPropertiesAllowed
+	// AdditionalPropertiesDisallowed indicates that additionalProperties is
+	// false (or {"not": {}}). Additional properties are not permitted.
+	AdditionalPropertiesDisallowed
+	// AdditionalPropertiesConstrained indicates that additionalProperties is
+	// a Schema which the values of additional properties must satisfy.
+	AdditionalPropertiesConstrained
+)
+
+var additionalPropertiesKindNames = [...]string{
+	AdditionalPropertiesAbsent:      "absent",
+	AdditionalPropertiesAllowed:     "allowed",
+	AdditionalPropertiesDisallowed:  "disallowed",
+	AdditionalPropertiesConstrained: "constrained",
+}
+
+func (k AdditionalPropertiesKind) String() string {
+	if int(k) < len(additionalPropertiesKindNames) {
+		return additionalPropertiesKindNames[k]
+	}
+	return "AdditionalPropertiesKind(" + strconv.Itoa(int(k)) + ")"
+}
+
+// AdditionalPropertiesKind returns the form of the additionalProperties of s.
+func (s *Schema) AdditionalPropertiesKind() AdditionalPropertiesKind {
+	if s == nil || s.AdditionalProperties == nil {
+		return AdditionalPropertiesAbsent
+	}
+	switch ap := s.AdditionalProperties; {
+	case ap.IsAlways():
+		return AdditionalPropertiesAllowed
+	case ap.IsNever():
+		return AdditionalPropertiesDisallowed
+	default:
+		return AdditionalPropertiesConstrained
+	}
+}
+
+// AllowsAdditionalProperties reports whether s permits properties other than
+// those matched by its properties and patternProperties. Additional
+// properties are permitted unless additionalProperties is false.
+func (s *Schema) AllowsAdditionalProperties() bool {
+	return s.AdditionalPropertiesKind() != AdditionalPropertiesDisallowed
+}
+
+// DisallowAdditionalProperties sets the additionalProperties of s to false.
+func (s *Schema) DisallowAdditionalProperties() {
+	s.AdditionalProperties = NewBoolSchema(false)
+}
+
+// AllowAdditionalProperties sets the additionalProperties of s to true.
+func (s *Schema) AllowAdditionalProperties() {
+	s.AdditionalProperties = NewBoolSchema(true)
+}
@@ -0,0 +1,61 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/chanced/openapi"
+)
+
+func TestAdditionalProperties(t *testing.T) {
+	tests := []struct {
+		schema   string
+		kind     openapi.AdditionalPropertiesKind
+		allows   bool
+		expected string
+	}{
+		{`{"type":"object"}`, openapi.AdditionalPropertiesAbsent, true, ""},
+		{`{"type":"object","additionalProperties":true}`, openapi.AdditionalPropertiesAllowed, true, "true"},
+		{`{"type":"object","additionalProperties":{}}`, openapi.AdditionalPropertiesAllowed, true, "{}"},
+		{`{"type":"object","additionalProperties":false}`, openapi.AdditionalPropertiesDisallowed, false, "false"},
+		{`{"type":"object","additionalProperties":{"not":{}}}`, openapi.AdditionalPropertiesDisallowed, false, `{"not":{}}`},
+		{`{"type":"object","additionalProperties":{"type":"string"}}`, openapi.AdditionalPropertiesConstrained, true, `{"type":"string"}`},
+	}
+	for _, test := range tests {
+		var s openapi.Schema
+		if err := json.Unmarshal([]byte(test.schema), &s); err != nil {
+			t.Fatal(err)
+		}
+		if k := s.AdditionalPropertiesKind(); k != test.kind {
+			t.Errorf("%s: expected %s, got %s", test.schema, test.kind, k)
+		}
+		if s.AllowsAdditionalProperties() != test.allows {
+			t.Errorf("%s: expected AllowsAdditionalProperties to be %t", test.schema, test.allows)
+		}
+		b, err := json.Marshal(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var v map[string]json.RawMessage
+		if err = json.Unmarshal(b, &v); err != nil {
+			t.Fatal(err)
+		}
+		if ap := string(v["additionalProperties"]); ap != test.expected {
+			t.Errorf("%s: expected additionalProperties to round-trip as %q, got %q", test.schema, test.expected, ap)
+		}
+	}
+
+	s := &openapi.Schema{Type: openapi.Types{openapi.TypeObject}}
+	s.DisallowAdditionalProperties()
+	b, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"type":"object","additionalProperties":false}` {
+		t.Errorf("unexpected schema %s", b)
+	}
+	s.AllowAdditionalProperties()
+	if s.AdditionalPropertiesKind() != openapi.AdditionalPropertiesAllowed {
+		t.Errorf("expected additionalProperties to be allowed, got %s", s.AdditionalPropertiesKind())
+	}
+}
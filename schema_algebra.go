@@ -0,0 +1,955 @@
+package openapi
+
+import (
+	"encoding/json"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/chanced/jsonx"
+)
+
+// Decision is the answer to a question about Schemas, such as whether one is
+// a subschema of another, which can not always be decided.
+type Decision uint8
+
+const (
+	// DecisionUnknown indicates that the answer could not be determined.
+	DecisionUnknown Decision = iota
+	// DecisionYes indicates that the answer is yes.
+	DecisionYes
+	// DecisionNo indicates that the answer is no.
+	DecisionNo
+)
+
+var decisionNames = [...]string{
+	DecisionUnknown: "unknown",
+	DecisionYes:     "yes",
+	DecisionNo:      "no",
+}
+
+func (d Decision) String() string {
+	if int(d) < len(decisionNames) {
+		return decisionNames[d]
+	}
+	return "Decision(" + strconv.Itoa(int(d)) + ")"
+}
+
+func decide(b bool) Decision {
+	if b {
+		return DecisionYes
+	}
+	return DecisionNo
+}
+
+// and returns the conjunction of d and o
+func (d Decision) and(o Decision) Decision {
+	switch {
+	case d == DecisionNo || o == DecisionNo:
+		return DecisionNo
+	case d == DecisionUnknown || o == DecisionUnknown:
+		return DecisionUnknown
+	default:
+		return DecisionYes
+	}
+}
+
+// or returns the disjunction of d and o
+func (d Decision) or(o Decision) Decision {
+	switch {
+	case d == DecisionYes || o == DecisionYes:
+		return DecisionYes
+	case d == DecisionUnknown || o == DecisionUnknown:
+		return DecisionUnknown
+	default:
+		return DecisionNo
+	}
+}
+
+func (d Decision) not() Decision {
+	switch d {
+	case DecisionYes:
+		return DecisionNo
+	case DecisionNo:
+		return DecisionYes
+	default:
+		return DecisionUnknown
+	}
+}
+
+// maxEffectiveDepth bounds the chain of $ref followed by effectiveSchema
+const maxEffectiveDepth = 32
+
+// IntersectSchemas returns a Schema which permits exactly the values
+// permitted by both a and b, without resorting to allOf. A nil Schema
+// permits any value. If no value is permitted by both, the result is the
+// boolean Schema false.
+//
+// Resolved references of a and b are inlined and their allOf branches are
+// merged as by MergeAllOf. ok is false if the intersection can not be
+// expressed exactly, e.g. because a and b have differing patterns, in which
+// case {"allOf": [a, b]} remains the only accurate description.
+func IntersectSchemas(a, b *Schema) (res *Schema, ok bool) {
+	switch {
+	case a.IsNever() || b.IsNever():
+		return NewBoolSchema(false), true
+	case a == nil || a.IsAlways():
+		if b == nil {
+			return NewBoolSchema(true), true
+		}
+		return b.shallowClone(), true
+	case b == nil || b.IsAlways():
+		return a.shallowClone(), true
+	}
+	ea, ok := effectiveSchema(a)
+	if !ok {
+		return nil, false
+	}
+	eb, ok := effectiveSchema(b)
+	if !ok {
+		return nil, false
+	}
+	if schemasDisjoint(ea, eb) == DecisionYes {
+		return NewBoolSchema(false), true
+	}
+	return intersectEffective(ea, eb)
+}
+
+// intersectEffective merges the effective Schemas a and b
+func intersectEffective(a, b *Schema) (*Schema, bool) {
+	if a.UnevaluatedProperties != nil || b.UnevaluatedProperties != nil ||
+		a.UnevaluatedItems != nil || b.UnevaluatedItems != nil {
+		// the evaluated locations of the merger differ from those of a and b
+		return nil, false
+	}
+	if a.Contains != nil && b.Contains != nil && !sameJSON(a.Contains, b.Contains) {
+		// an array containing a value matching each is not the same as an
+		// array containing a value matching both
+		return nil, false
+	}
+	if !prefixItemsMergeable(a, b) {
+		return nil, false
+	}
+	a, ok := expandProperties(a, b)
+	if !ok {
+		return nil, false
+	}
+	b, ok = expandProperties(b, a)
+	if !ok {
+		return nil, false
+	}
+	m := newSchemaMerger()
+	res := a.shallowClone()
+	m.merge(res, b)
+	m.checkBounds(res)
+	for _, c := range m.conflicts {
+		if !boundsConflict(c) {
+			return nil, false
+		}
+	}
+	return res, true
+}
+
+// boundsConflict reports whether c is due to a constraint which can not be
+// satisfied once merged. The merged Schema remains an exact intersection as
+// the constraint applies only to values of a particular type.
+func boundsConflict(c SchemaConflict) bool {
+	switch c.Keyword {
+	case "minimum", "minLength", "minProperties", "minContains", "required":
+		return true
+	}
+	// extensions are annotations
+	return strings.HasPrefix(c.Keyword.String(), "x-")
+}
+
+// prefixItemsMergeable reports whether merging the prefixItems and items of a
+// and b yields their intersection, which is the case if both have the same
+// number of prefixItems or neither constrains items beyond those of the
+// other's prefixItems
+func prefixItemsMergeable(a, b *Schema) bool {
+	la, lb := 0, 0
+	if a.PrefixItems != nil {
+		la = len(a.PrefixItems.Items)
+	}
+	if b.PrefixItems != nil {
+		lb = len(b.PrefixItems.Items)
+	}
+	switch {
+	case la == lb:
+		return true
+	case la < lb:
+		return a.Items == nil && a.AdditionalItems == nil
+	default:
+		return b.Items == nil && b.AdditionalItems == nil
+	}
+}
+
+// expandProperties returns s with an entry in properties for each property
+// of o which s constrains only through additionalProperties, so that merging
+// s and o retains the constraint
+func expandProperties(s, o *Schema) (*Schema, bool) {
+	if s.AdditionalProperties == nil {
+		return s, true
+	}
+	if o.PatternProperties != nil && len(o.PatternProperties.Items) > 0 {
+		// properties matching the patterns of o would no longer be subject
+		// to the additionalProperties of s
+		return nil, false
+	}
+	if o.Properties == nil {
+		return s, true
+	}
+	var missing []SchemaItem
+	for _, item := range o.Properties.Items {
+		if s.Properties == nil || s.Properties.Get(item.Key) == nil {
+			missing = append(missing, item)
+		}
+	}
+	if len(missing) == 0 {
+		return s, true
+	}
+	if s.PatternProperties != nil && len(s.PatternProperties.Items) > 0 {
+		// the property may match a pattern rather than additionalProperties
+		return nil, false
+	}
+	res := s.shallowClone()
+	props := &SchemaMap{}
+	if s.Properties != nil {
+		props.Location = s.Properties.Location
+		props.Items = append(props.Items, s.Properties.Items...)
+	}
+	for _, item := range missing {
+		props.Items = append(props.Items, SchemaItem{Key: item.Key, Schema: s.AdditionalProperties})
+	}
+	res.Properties = props
+	return res, true
+}
+
+// UnionSchemas returns a Schema which permits exactly the values permitted by
+// either a or b, without resorting to anyOf. A nil Schema permits any value.
+//
+// The union can be expressed when one Schema is a subschema of the other
+// (see Schema.IsSubschemaOf), when both consist only of enumerated values,
+// and when their types are disjoint and each consists only of keywords
+// specific to its types (e.g. {"type": "string", "minLength": 1} and
+// {"type": "integer", "minimum": 0}). ok is false otherwise, in which case
+// {"anyOf": [a, b]} remains the only accurate description.
+func UnionSchemas(a, b *Schema) (res *Schema, ok bool) {
+	switch {
+	case a == nil || a.IsAlways() || b == nil || b.IsAlways():
+		return NewBoolSchema(true), true
+	case a.IsNever():
+		return b.shallowClone(), true
+	case b.IsNever():
+		return a.shallowClone(), true
+	case a.IsSubschemaOf(b) == DecisionYes:
+		return b.shallowClone(), true
+	case b.IsSubschemaOf(a) == DecisionYes:
+		return a.shallowClone(), true
+	}
+	ea, ok := effectiveSchema(a)
+	if !ok {
+		return nil, false
+	}
+	eb, ok := effectiveSchema(b)
+	if !ok {
+		return nil, false
+	}
+	if res, ok := unionEnums(ea, eb); ok {
+		return res, true
+	}
+	return unionTyped(ea, eb)
+}
+
+// unionEnums returns the union of a and b if both consist only of
+// enumerated values
+func unionEnums(a, b *Schema) (*Schema, bool) {
+	va, ok := enumeratedValues(a)
+	if !ok {
+		return nil, false
+	}
+	vb, ok := enumeratedValues(b)
+	if !ok {
+		return nil, false
+	}
+	res := &Schema{Location: a.Location}
+	seen := map[string]bool{}
+	for _, v := range append(va, vb...) {
+		if k := canonicalJSON(v); !seen[k] {
+			seen[k] = true
+			res.Enum = append(res.Enum, v)
+		}
+	}
+	return res, true
+}
+
+// enumeratedValues returns the values permitted by s if s consists only of
+// enum or const, along with type and annotations
+func enumeratedValues(s *Schema) (Enum, bool) {
+	c := *stripAnnotations(s)
+	c.Type, c.Enum, c.Const = nil, nil, nil
+	if !c.isBlank() || (len(s.Enum) == 0 && s.Const == nil) {
+		return nil, false
+	}
+	var res Enum
+	values := s.Enum
+	if s.Const != nil {
+		values = Enum{s.Const}
+	}
+	for _, data := range values {
+		v, ok := decodeInstance(data)
+		if !ok {
+			return nil, false
+		}
+		switch permits(s, v, 0) {
+		case DecisionYes:
+			res = append(res, data)
+		case DecisionUnknown:
+			return nil, false
+		}
+	}
+	return res, true
+}
+
+// unionTyped returns the union of a and b if their types are disjoint and
+// each consists only of keywords specific to its types
+func unionTyped(a, b *Schema) (*Schema, bool) {
+	if len(a.Type) == 0 || len(b.Type) == 0 || len(intersectTypes(a.Type, b.Type)) > 0 {
+		return nil, false
+	}
+	if !typeSpecific(a) || !typeSpecific(b) {
+		return nil, false
+	}
+	a, b = restrictToTypes(a), restrictToTypes(b)
+	m := newSchemaMerger()
+	res := a.shallowClone()
+	res.Type = nil
+	m.merge(res, b)
+	for _, c := range m.conflicts {
+		if !strings.HasPrefix(c.Keyword.String(), "x-") {
+			return nil, false
+		}
+	}
+	res.Type = unionTypes(a.Type, b.Type)
+	return res, true
+}
+
+func unionTypes(a, b Types) Types {
+	res := a.Clone()
+	for _, t := range b {
+		if !res.Contains(t) {
+			res = append(res, t)
+		}
+	}
+	return res
+}
+
+// typeSpecific reports whether s consists only of type, annotations, and
+// keywords which apply to values of a particular type
+func typeSpecific(s *Schema) bool {
+	c := *restrictToTypes(stripAnnotations(s))
+	c.Type = nil
+	c.MinLength, c.MaxLength, c.Pattern = nil, nil, nil
+	c.Minimum, c.ExclusiveMinimum, c.Maximum, c.ExclusiveMaximum, c.MultipleOf = nil, nil, nil, nil, nil
+	c.Properties, c.PatternProperties, c.AdditionalProperties, c.PropertyNames = nil, nil, nil, nil
+	c.Required, c.MinProperties, c.MaxProperties, c.DependentRequired = nil, nil, nil, nil
+	c.DependentSchemas, c.UnevaluatedProperties = nil, nil
+	c.Items, c.PrefixItems, c.AdditionalItems, c.UnevaluatedItems = nil, nil, nil, nil
+	c.Contains, c.MinContains, c.MaxContains, c.UniqueItems = nil, nil, nil, nil
+	c.Keywords = nil
+	for k := range s.Keywords {
+		if k != "minItems" && k != "maxItems" {
+			return false
+		}
+	}
+	return c.isBlank()
+}
+
+// restrictToTypes returns s without the keywords which apply only to types
+// s does not permit
+func restrictToTypes(s *Schema) *Schema {
+	if len(s.Type) == 0 {
+		return s
+	}
+	c := *s
+	if !c.Type.ContainsString() {
+		c.MinLength, c.MaxLength, c.Pattern = nil, nil, nil
+	}
+	if !c.Type.ContainsNumber() && !c.Type.ContainsInteger() {
+		c.Minimum, c.ExclusiveMinimum, c.Maximum, c.ExclusiveMaximum, c.MultipleOf = nil, nil, nil, nil, nil
+	}
+	if !c.Type.ContainsObject() {
+		c.Properties, c.PatternProperties, c.AdditionalProperties, c.PropertyNames = nil, nil, nil, nil
+		c.Required, c.MinProperties, c.MaxProperties, c.DependentRequired = nil, nil, nil, nil
+		c.DependentSchemas, c.UnevaluatedProperties = nil, nil
+	}
+	if !c.Type.ContainsArray() {
+		c.Items, c.PrefixItems, c.AdditionalItems, c.UnevaluatedItems = nil, nil, nil, nil
+		c.Contains, c.MinContains, c.MaxContains, c.UniqueItems = nil, nil, nil, nil
+		if c.Keywords != nil {
+			c.Keywords = cloneKeywords(c.Keywords)
+			delete(c.Keywords, "minItems")
+			delete(c.Keywords, "maxItems")
+		}
+	}
+	return &c
+}
+
+func cloneKeywords(kw map[Text]jsonx.RawMessage) map[Text]jsonx.RawMessage {
+	res := make(map[Text]jsonx.RawMessage, len(kw))
+	for k, v := range kw {
+		res[k] = v
+	}
+	return res
+}
+
+// stripAnnotations returns s without annotations, which do not affect the
+// values s permits
+func stripAnnotations(s *Schema) *Schema {
+	c := *s
+	c.Location = Location{}
+	c.Schema, c.ID, c.Anchor, c.DynamicAnchor, c.RecursiveAnchor = nil, nil, "", "", nil
+	c.Title, c.Description, c.Comments, c.ExternalDocs = "", "", "", ""
+	c.Default, c.Example, c.Examples = nil, nil, nil
+	c.ReadOnly, c.WriteOnly, c.Deprecated = nil, nil, nil
+	c.Format, c.ContentEncoding, c.ContentMediaType = "", "", ""
+	c.Discriminator, c.XML, c.Extensions, c.Definitions = nil, nil, nil, nil
+	if _, ok := c.Keywords[keywordContentSchema]; ok {
+		c.Keywords = cloneKeywords(c.Keywords)
+		delete(c.Keywords, keywordContentSchema)
+	}
+	return &c
+}
+
+// IsSubschemaOf reports whether every value permitted by s is permitted by t;
+// that is, whether s is a subtype of t. A nil Schema permits any value.
+//
+// DecisionYes is returned if the keywords of s are shown to imply those of t
+// (e.g. a larger minimum, additional required properties, or a subset of
+// types or enumerated values). DecisionNo is returned only if a value
+// permitted by s but not by t is found, drawn from the enumerated values,
+// defaults, and examples of s along with a value of each type s permits.
+// Otherwise, the result is DecisionUnknown.
+//
+// format and the content keywords are treated as annotations, as they are by
+// default in JSON Schema 2020-12.
+func (s *Schema) IsSubschemaOf(t *Schema) Decision {
+	if t == nil || t.IsAlways() || s.IsNever() {
+		return DecisionYes
+	}
+	for _, w := range schemaWitnesses(s) {
+		if permits(s, w, 0) == DecisionYes && permits(t, w, 0) == DecisionNo {
+			return DecisionNo
+		}
+	}
+	es, ok := effectiveSchema(s)
+	if !ok {
+		return DecisionUnknown
+	}
+	if values, ok := enumeratedValues(es); ok {
+		d := DecisionYes
+		for _, data := range values {
+			v, _ := decodeInstance(data)
+			d = d.and(permits(t, v, 0))
+		}
+		if d == DecisionYes {
+			return d
+		}
+		return DecisionUnknown
+	}
+	et, ok := effectiveSchema(t)
+	if !ok {
+		return DecisionUnknown
+	}
+	return implies(es, et, 0)
+}
+
+// effectiveSchema returns s with each resolved $ref inlined and the branches
+// of allOf merged. ok is false if the result is not exact.
+func effectiveSchema(s *Schema) (*Schema, bool) {
+	res, conflicts, ok := mergeEffective(s)
+	if !ok {
+		return nil, false
+	}
+	for _, c := range conflicts {
+		if !boundsConflict(c) {
+			return nil, false
+		}
+	}
+	return res, true
+}
+
+// mergeEffective returns s with each resolved $ref inlined and the branches
+// of allOf merged, along with the conflicts encountered. ok is false if the
+// chain of references is too deep.
+func mergeEffective(s *Schema) (res *Schema, conflicts []SchemaConflict, ok bool) {
+	if s == nil {
+		return &Schema{}, nil, true
+	}
+	if _, ok := s.Bool(); ok {
+		return s, nil, true
+	}
+	for i := 0; i < maxEffectiveDepth; i++ {
+		if (s.AllOf == nil || len(s.AllOf.Items) == 0) && (s.Ref == nil || s.Ref.Resolved == nil) {
+			return s, conflicts, true
+		}
+		m := newSchemaMerger()
+		s = m.branch(s)
+		conflicts = append(conflicts, m.conflicts...)
+	}
+	return nil, conflicts, false
+}
+
+// schemaTypes returns the types of the values permitted by the effective
+// Schema s, or nil if s permits values of any type
+func schemaTypes(s *Schema) Types {
+	var types Types
+	add := func(t Type) {
+		if !types.Contains(t) && typesAllow(s.Type, t) {
+			types = append(types, t)
+		}
+	}
+	switch {
+	case s.Const != nil:
+		add(jsonType(s.Const))
+	case len(s.Enum) > 0:
+		for _, v := range s.Enum {
+			add(jsonType(v))
+		}
+	default:
+		return s.Type
+	}
+	if types == nil {
+		types = Types{}
+	}
+	return types
+}
+
+// schemaPermitsType reports whether the effective Schema s may permit values
+// of Type t
+func schemaPermitsType(s *Schema, t Type) bool {
+	types := schemaTypes(s)
+	if types == nil {
+		return true
+	}
+	for _, x := range types {
+		if x == t || (x == TypeNumber && t == TypeInteger) || (x == TypeInteger && t == TypeNumber) {
+			return true
+		}
+	}
+	return false
+}
+
+// schemasDisjoint decides whether no value is permitted by both of the
+// effective Schemas a and b. Only DecisionYes and DecisionUnknown are
+// returned.
+func schemasDisjoint(a, b *Schema) Decision {
+	if a.IsNever() || b.IsNever() {
+		return DecisionYes
+	}
+	ta, tb := schemaTypes(a), schemaTypes(b)
+	if ta != nil && tb != nil && len(intersectTypes(ta, tb)) == 0 {
+		return DecisionYes
+	}
+	for _, pair := range [][2]*Schema{{a, b}, {b, a}} {
+		values, ok := enumeratedValues(pair[0])
+		if !ok {
+			continue
+		}
+		d := DecisionYes
+		for _, data := range values {
+			v, _ := decodeInstance(data)
+			d = d.and(permits(pair[1], v, 0).not())
+		}
+		if d == DecisionYes {
+			return d
+		}
+	}
+	return DecisionUnknown
+}
+
+// implies decides whether the effective Schema s implies each keyword of the
+// effective Schema t. Only DecisionYes and DecisionUnknown are returned.
+func implies(s, t *Schema, depth int) Decision {
+	if t == nil || t.IsAlways() || s.IsNever() {
+		return DecisionYes
+	}
+	if depth > maxInstanceDepth || t.IsNever() {
+		return DecisionUnknown
+	}
+	if s.Ref != nil || s.DynamicRef != nil || s.RecursiveRef != nil ||
+		t.Ref != nil || t.DynamicRef != nil || t.RecursiveRef != nil {
+		// unresolved
+		return DecisionUnknown
+	}
+	sub := func(a, b *Schema) Decision {
+		ea, ok := effectiveSchema(a)
+		if !ok {
+			return DecisionUnknown
+		}
+		eb, ok := effectiveSchema(b)
+		if !ok {
+			return DecisionUnknown
+		}
+		return implies(ea, eb, depth+1)
+	}
+
+	// each branch of s, combined with the remainder of s, must imply t
+	for _, branches := range []func(*Schema) **SchemaSlice{
+		func(x *Schema) **SchemaSlice { return &x.AnyOf },
+		func(x *Schema) **SchemaSlice { return &x.OneOf },
+	} {
+		ss := *branches(s)
+		if ss == nil || len(ss.Items) == 0 {
+			continue
+		}
+		rest := *s
+		*branches(&rest) = nil
+		for _, b := range ss.Items {
+			eb, ok := effectiveSchema(b)
+			if !ok {
+				return DecisionUnknown
+			}
+			if schemasDisjoint(&rest, eb) == DecisionYes {
+				continue
+			}
+			combined, ok := intersectEffective(&rest, eb)
+			if !ok || implies(combined, t, depth+1) != DecisionYes {
+				return DecisionUnknown
+			}
+		}
+		return DecisionYes
+	}
+
+	checks := []func() Decision{
+		func() Decision { return impliesTypes(s, t) },
+		func() Decision { return impliesValues(s, t) },
+		func() Decision { return impliesString(s, t) },
+		func() Decision { return impliesNumber(s, t) },
+		func() Decision { return impliesObject(s, t, sub) },
+		func() Decision { return impliesArray(s, t, sub) },
+		func() Decision { return impliesApplicators(s, t, sub) },
+	}
+	for _, check := range checks {
+		if check() != DecisionYes {
+			return DecisionUnknown
+		}
+	}
+	return DecisionYes
+}
+
+func impliesTypes(s, t *Schema) Decision {
+	if len(t.Type) == 0 {
+		return DecisionYes
+	}
+	types := schemaTypes(s)
+	if types == nil {
+		return DecisionUnknown
+	}
+	for _, x := range types {
+		if !typesAllow(t.Type, x) {
+			return DecisionUnknown
+		}
+	}
+	return DecisionYes
+}
+
+func impliesValues(s, t *Schema) Decision {
+	if t.Const == nil && len(t.Enum) == 0 {
+		return DecisionYes
+	}
+	var values Enum
+	switch {
+	case s.Const != nil:
+		values = Enum{s.Const}
+	case len(s.Enum) > 0:
+		values = s.Enum
+	default:
+		return DecisionUnknown
+	}
+	for _, v := range values {
+		if t.Const != nil && !jsonEqual(t.Const, v) {
+			return DecisionUnknown
+		}
+		if len(t.Enum) > 0 && !enumContainsCanonical(t.Enum, canonicalJSON(v)) {
+			return DecisionUnknown
+		}
+	}
+	return DecisionYes
+}
+
+func impliesString(s, t *Schema) Decision {
+	if !schemaPermitsType(s, TypeString) {
+		return DecisionYes
+	}
+	if t.MinLength != nil && (s.MinLength == nil || s.MinLength.Cmp(*t.MinLength) < 0) {
+		return DecisionUnknown
+	}
+	if t.MaxLength != nil && (s.MaxLength == nil || s.MaxLength.Cmp(*t.MaxLength) > 0) {
+		return DecisionUnknown
+	}
+	if !t.Pattern.IsNil() && (s.Pattern.IsNil() || s.Pattern.String() != t.Pattern.String()) {
+		return DecisionUnknown
+	}
+	return DecisionYes
+}
+
+func impliesNumber(s, t *Schema) Decision {
+	if !schemaPermitsType(s, TypeNumber) {
+		return DecisionYes
+	}
+	switch {
+	case t.Minimum != nil && !boundedBelow(s, *t.Minimum, false),
+		t.ExclusiveMinimum != nil && !boundedBelow(s, *t.ExclusiveMinimum, true),
+		t.Maximum != nil && !boundedAbove(s, *t.Maximum, false),
+		t.ExclusiveMaximum != nil && !boundedAbove(s, *t.ExclusiveMaximum, true),
+		t.MultipleOf != nil && !impliesMultipleOf(s, *t.MultipleOf):
+		return DecisionUnknown
+	}
+	return DecisionYes
+}
+
+// boundedBelow reports whether the numbers permitted by s are bounded below
+// by n, exclusively if excl
+func boundedBelow(s *Schema, n Number, excl bool) bool {
+	if s.ExclusiveMinimum != nil && s.ExclusiveMinimum.Cmp(n) >= 0 {
+		return true
+	}
+	if s.Minimum != nil {
+		c := s.Minimum.Cmp(n)
+		return c > 0 || (c == 0 && !excl)
+	}
+	return false
+}
+
+// boundedAbove reports whether the numbers permitted by s are bounded above
+// by n, exclusively if excl
+func boundedAbove(s *Schema, n Number, excl bool) bool {
+	if s.ExclusiveMaximum != nil && s.ExclusiveMaximum.Cmp(n) <= 0 {
+		return true
+	}
+	if s.Maximum != nil {
+		c := s.Maximum.Cmp(n)
+		return c < 0 || (c == 0 && !excl)
+	}
+	return false
+}
+
+// impliesMultipleOf reports whether the numbers permitted by s are multiples
+// of n
+func impliesMultipleOf(s *Schema, n Number) bool {
+	nr, ok := n.BigRat()
+	if !ok || nr.Sign() == 0 {
+		return false
+	}
+	if s.MultipleOf != nil {
+		if sr, ok := s.MultipleOf.BigRat(); ok && new(big.Rat).Quo(sr, nr).IsInt() {
+			return true
+		}
+	}
+	integers := len(s.Type) > 0 && !s.Type.ContainsNumber()
+	return integers && new(big.Rat).Inv(nr).IsInt()
+}
+
+func impliesObject(s, t *Schema, sub func(a, b *Schema) Decision) Decision {
+	if !schemaPermitsType(s, TypeObject) {
+		return DecisionYes
+	}
+	for _, r := range t.Required {
+		if !textsContain(s.Required, r) {
+			return DecisionUnknown
+		}
+	}
+	if t.MinProperties != nil {
+		if s.MinProperties == nil || s.MinProperties.Cmp(*t.MinProperties) < 0 {
+			if IntNumber(int64(len(s.Required))).Cmp(*t.MinProperties) < 0 {
+				return DecisionUnknown
+			}
+		}
+	}
+	if t.MaxProperties != nil && (s.MaxProperties == nil || s.MaxProperties.Cmp(*t.MaxProperties) > 0) {
+		return DecisionUnknown
+	}
+	// propertySchema returns the Schema of s which applies to the property k
+	propertySchema := func(k Text) (*Schema, bool) {
+		if s.Properties != nil {
+			if ps := s.Properties.Get(k); ps != nil {
+				return ps, true
+			}
+		}
+		if s.PatternProperties != nil && len(s.PatternProperties.Items) > 0 {
+			return nil, false
+		}
+		return s.AdditionalProperties, true
+	}
+	if t.Properties != nil {
+		for _, item := range t.Properties.Items {
+			ps, ok := propertySchema(item.Key)
+			if !ok {
+				return DecisionUnknown
+			}
+			if !ps.IsNever() && sub(ps, item.Schema) != DecisionYes {
+				return DecisionUnknown
+			}
+		}
+	}
+	if t.AdditionalProperties != nil {
+		if t.PatternProperties != nil && len(t.PatternProperties.Items) > 0 {
+			return DecisionUnknown
+		}
+		if s.PatternProperties != nil && len(s.PatternProperties.Items) > 0 {
+			return DecisionUnknown
+		}
+		if s.Properties != nil {
+			for _, item := range s.Properties.Items {
+				if t.Properties != nil && t.Properties.Get(item.Key) != nil {
+					continue
+				}
+				if !item.Schema.IsNever() && sub(item.Schema, t.AdditionalProperties) != DecisionYes {
+					return DecisionUnknown
+				}
+			}
+		}
+		if !s.AdditionalProperties.IsNever() && sub(s.AdditionalProperties, t.AdditionalProperties) != DecisionYes {
+			return DecisionUnknown
+		}
+	}
+	switch {
+	case !sameJSON(s.PatternProperties, t.PatternProperties) && t.PatternProperties != nil,
+		!sameJSON(s.PropertyNames, t.PropertyNames) && t.PropertyNames != nil,
+		!sameJSON(s.DependentRequired, t.DependentRequired) && t.DependentRequired != nil,
+		!sameJSON(s.DependentSchemas, t.DependentSchemas) && t.DependentSchemas != nil,
+		!sameJSON(s.UnevaluatedProperties, t.UnevaluatedProperties) && t.UnevaluatedProperties != nil:
+		return DecisionUnknown
+	}
+	return DecisionYes
+}
+
+func impliesArray(s, t *Schema, sub func(a, b *Schema) Decision) Decision {
+	if !schemaPermitsType(s, TypeArray) {
+		return DecisionYes
+	}
+	if min, ok := t.keywordNumber("minItems"); ok {
+		if smin, ok := s.keywordNumber("minItems"); !ok || smin.Cmp(min) < 0 {
+			return DecisionUnknown
+		}
+	}
+	if max, ok := t.keywordNumber("maxItems"); ok {
+		if smax, ok := s.keywordNumber("maxItems"); !ok || smax.Cmp(max) > 0 {
+			return DecisionUnknown
+		}
+	}
+	if t.UniqueItems != nil && *t.UniqueItems && (s.UniqueItems == nil || !*s.UniqueItems) {
+		return DecisionUnknown
+	}
+	var sp, tp []*Schema
+	if s.PrefixItems != nil {
+		sp = s.PrefixItems.Items
+	}
+	if t.PrefixItems != nil {
+		tp = t.PrefixItems.Items
+	}
+	// item returns the Schema of x which applies to the item at index i
+	item := func(x *Schema, prefix []*Schema, i int) *Schema {
+		if i < len(prefix) {
+			return prefix[i]
+		}
+		return x.Items
+	}
+	n := len(sp)
+	if len(tp) > n {
+		n = len(tp)
+	}
+	for i := 0; i <= n; i++ {
+		ti := item(t, tp, i)
+		if ti == nil {
+			continue
+		}
+		if si := item(s, sp, i); !si.IsNever() && sub(si, ti) != DecisionYes {
+			return DecisionUnknown
+		}
+	}
+	switch {
+	case !sameJSON(s.AdditionalItems, t.AdditionalItems) && t.AdditionalItems != nil,
+		!sameJSON(s.Contains, t.Contains) && t.Contains != nil,
+		!sameJSON(s.MinContains, t.MinContains) && t.MinContains != nil,
+		!sameJSON(s.MaxContains, t.MaxContains) && t.MaxContains != nil,
+		!sameJSON(s.UnevaluatedItems, t.UnevaluatedItems) && t.UnevaluatedItems != nil:
+		return DecisionUnknown
+	}
+	return DecisionYes
+}
+
+func impliesApplicators(s, t *Schema, sub func(a, b *Schema) Decision) Decision {
+	if t.AnyOf != nil && len(t.AnyOf.Items) > 0 {
+		d := DecisionUnknown
+		for _, b := range t.AnyOf.Items {
+			if sub(s, b) == DecisionYes {
+				d = DecisionYes
+				break
+			}
+		}
+		if d != DecisionYes {
+			return d
+		}
+	}
+	if t.OneOf != nil && len(t.OneOf.Items) > 0 {
+		// s must imply exactly one branch and be disjoint from the others
+		matched := 0
+		for _, b := range t.OneOf.Items {
+			if sub(s, b) == DecisionYes {
+				matched++
+				continue
+			}
+			eb, ok := effectiveSchema(b)
+			if !ok || schemasDisjoint(s, eb) != DecisionYes {
+				return DecisionUnknown
+			}
+		}
+		if matched != 1 {
+			return DecisionUnknown
+		}
+	}
+	if t.Not != nil && !sameJSON(s.Not, t.Not) {
+		en, ok := effectiveSchema(t.Not)
+		if !ok || schemasDisjoint(s, en) != DecisionYes {
+			return DecisionUnknown
+		}
+	}
+	if (t.If != nil || t.Then != nil || t.Else != nil) &&
+		!(sameJSON(s.If, t.If) && sameJSON(s.Then, t.Then) && sameJSON(s.Else, t.Else)) {
+		return DecisionUnknown
+	}
+	for k, v := range t.Keywords {
+		switch k {
+		case "minItems", "maxItems", keywordContentSchema:
+			continue
+		}
+		if x, ok := s.Keywords[k]; !ok || !jsonEqual(x, v) {
+			return DecisionUnknown
+		}
+	}
+	return DecisionYes
+}
+
+func textsContain(ts Texts, t Text) bool {
+	for _, x := range ts {
+		if x == t {
+			return true
+		}
+	}
+	return false
+}
+
+// sameJSON reports whether a and b have the same JSON encoding
+func sameJSON(a, b interface{}) bool {
+	da, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	db, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return jsonEqual(da, db)
+}
@@ -0,0 +1,217 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/chanced/openapi"
+)
+
+func mustSchema(t *testing.T, data string) *openapi.Schema {
+	t.Helper()
+	var s openapi.Schema
+	if err := json.Unmarshal([]byte(data), &s); err != nil {
+		t.Fatal(err)
+	}
+	return &s
+}
+
+func TestSchemaIsSubschemaOf(t *testing.T) {
+	tests := []struct {
+		name     string
+		s, t     string
+		expected openapi.Decision
+	}{
+		{"tighter minimum", `{ "type": "integer", "minimum": 5 }`, `{ "type": "number", "minimum": 0 }`, openapi.DecisionYes},
+		{"number is not integer", `{ "type": "number" }`, `{ "type": "integer" }`, openapi.DecisionNo},
+		{"looser minimum", `{ "type": "integer", "minimum": 0 }`, `{ "type": "integer", "minimum": 5 }`, openapi.DecisionNo},
+		{
+			"additional required",
+			`{ "type": "object", "required": ["a", "b"], "properties": { "a": { "type": "string", "maxLength": 5 } } }`,
+			`{ "type": "object", "required": ["a"], "properties": { "a": { "type": "string" } } }`,
+			openapi.DecisionYes,
+		},
+		{
+			"missing required",
+			`{ "type": "object", "required": ["a"], "properties": { "a": { "type": "string" } } }`,
+			`{ "type": "object", "required": ["a", "b"] }`,
+			openapi.DecisionNo,
+		},
+		{
+			"closed object",
+			`{ "type": "object", "properties": { "a": { "type": "integer" } }, "additionalProperties": false }`,
+			`{ "type": "object", "additionalProperties": { "type": "number" } }`,
+			openapi.DecisionYes,
+		},
+		{"enum", `{ "enum": ["a", "b"] }`, `{ "type": "string" }`, openapi.DecisionYes},
+		{"enum of mixed types", `{ "enum": ["a", 1] }`, `{ "type": "string" }`, openapi.DecisionNo},
+		{"anyOf", `{ "anyOf": [{ "type": "string" }, { "type": "integer" }] }`, `{ "type": ["string", "number"] }`, openapi.DecisionYes},
+		{"items", `{ "type": "array", "items": { "type": "integer" } }`, `{ "type": "array", "items": { "type": "number" } }`, openapi.DecisionYes},
+		{"differing patterns", `{ "type": "string", "pattern": "^a" }`, `{ "type": "string", "pattern": "^[a-z]" }`, openapi.DecisionUnknown},
+		{"anything", `{}`, `{ "type": "string" }`, openapi.DecisionNo},
+		{"true", `{ "type": "string" }`, `true`, openapi.DecisionYes},
+		{"shorter maxLength", `{ "type": "string", "maxLength": 3 }`, `{ "type": "string", "maxLength": 5 }`, openapi.DecisionYes},
+		{"longer maxLength", `{ "type": "string", "maxLength": 5 }`, `{ "type": "string", "maxLength": 3 }`, openapi.DecisionNo},
+		{"multipleOf of a multiple", `{ "type": "integer", "multipleOf": 4 }`, `{ "type": "integer", "multipleOf": 2 }`, openapi.DecisionYes},
+		// 2 is permitted by s but not by t; the least multiple, 0, is the
+		// only number synthesized for s
+		{"multipleOf of a divisor", `{ "type": "number", "multipleOf": 2 }`, `{ "type": "number", "multipleOf": 4 }`, openapi.DecisionUnknown},
+		{"exclusiveMinimum", `{ "type": "integer", "exclusiveMinimum": 5 }`, `{ "type": "integer", "minimum": 5 }`, openapi.DecisionYes},
+		{"minimum as exclusiveMinimum", `{ "type": "integer", "minimum": 5 }`, `{ "type": "integer", "exclusiveMinimum": 5 }`, openapi.DecisionNo},
+		{"exclusiveMaximum", `{ "type": "number", "exclusiveMaximum": 1 }`, `{ "type": "number", "maximum": 1 }`, openapi.DecisionYes},
+		{"const in enum", `{ "const": "a" }`, `{ "enum": ["a", "b"] }`, openapi.DecisionYes},
+		{"enum beyond const", `{ "enum": ["a", "b"] }`, `{ "const": "a" }`, openapi.DecisionNo},
+		{"not", `{ "type": "string", "not": { "const": "a" } }`, `{ "not": { "const": "a" } }`, openapi.DecisionYes},
+		{"not a disjoint type", `{ "type": "string" }`, `{ "not": { "type": "integer" } }`, openapi.DecisionYes},
+		{"not the same type", `{ "type": "string" }`, `{ "not": { "type": "string" } }`, openapi.DecisionNo},
+		{"allOf", `{ "allOf": [{ "type": "integer" }, { "minimum": 1 }] }`, `{ "type": "number", "minimum": 0 }`, openapi.DecisionYes},
+		{"allOf in t", `{ "type": "integer" }`, `{ "allOf": [{ "type": "number" }, { "minimum": 0 }] }`, openapi.DecisionNo},
+		{"oneOf branches", `{ "oneOf": [{ "type": "string" }, { "type": "integer" }] }`, `{ "type": ["string", "integer"] }`, openapi.DecisionYes},
+		{"oneOf of identical branches", `{ "type": "string" }`, `{ "oneOf": [{ "type": "string" }, { "type": "string" }] }`, openapi.DecisionNo},
+		{"uniqueItems", `{ "type": "array", "uniqueItems": true }`, `{ "type": "array" }`, openapi.DecisionYes},
+		// [0, 0] is permitted by s but not by t; arrays are synthesized
+		// with the fewest items permitted
+		{"missing uniqueItems", `{ "type": "array" }`, `{ "type": "array", "uniqueItems": true }`, openapi.DecisionUnknown},
+		{
+			"tuple",
+			`{ "type": "array", "prefixItems": [{ "type": "integer" }], "items": false }`,
+			`{ "type": "array", "prefixItems": [{ "type": "number" }] }`,
+			openapi.DecisionYes,
+		},
+		// ["a"] is permitted by s but not by t; arrays are synthesized with
+		// the fewest items permitted
+		{
+			"disjoint prefixItems",
+			`{ "type": "array", "prefixItems": [{ "type": "string" }] }`,
+			`{ "type": "array", "prefixItems": [{ "type": "integer" }] }`,
+			openapi.DecisionUnknown,
+		},
+		{
+			"same patternProperties",
+			`{ "type": "object", "patternProperties": { "^a": { "type": "string" } } }`,
+			`{ "type": "object", "patternProperties": { "^a": { "type": "string" } } }`,
+			openapi.DecisionYes,
+		},
+		// patternProperties are only compared for equality, as the
+		// properties matched by each pattern are not known
+		{
+			"narrower patternProperties",
+			`{ "type": "object", "patternProperties": { "^a": { "type": "integer" } } }`,
+			`{ "type": "object", "patternProperties": { "^a": { "type": "number" } } }`,
+			openapi.DecisionUnknown,
+		},
+	}
+	for _, test := range tests {
+		s, o := mustSchema(t, test.s), mustSchema(t, test.t)
+		if d := s.IsSubschemaOf(o); d != test.expected {
+			t.Errorf("%s: expected %s, got %s", test.name, test.expected, d)
+		}
+	}
+}
+
+func TestIntersectSchemas(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     string
+		expected string
+	}{
+		{"types", `{ "type": ["string", "null"] }`, `{ "type": "string", "minLength": 1 }`, `{ "type": "string", "minLength": 1 }`},
+		{"disjoint", `{ "type": "string" }`, `{ "type": "integer" }`, `false`},
+		{"disjoint enums", `{ "enum": ["a"] }`, `{ "enum": ["b"] }`, `false`},
+		{
+			"closed object",
+			`{ "properties": { "a": { "type": "string" } }, "additionalProperties": false }`,
+			`{ "properties": { "b": { "type": "string" } }, "required": ["b"] }`,
+			`{ "properties": { "a": { "type": "string" }, "b": { "type": "string", "not": {} } }, "additionalProperties": false, "required": ["b"] }`,
+		},
+		{"bounds", `{ "minimum": 5 }`, `{ "maximum": 1 }`, `{ "minimum": 5, "maximum": 1 }`},
+		{"differing patterns", `{ "pattern": "^a" }`, `{ "pattern": "^b" }`, ``},
+		{"const and enum", `{ "const": "a" }`, `{ "enum": ["a", "b"] }`, `{ "const": "a", "enum": ["a", "b"] }`},
+		{"const of a disjoint type", `{ "const": "a" }`, `{ "type": "integer" }`, `false`},
+		{"maxLength", `{ "type": "string", "maxLength": 5 }`, `{ "type": "string", "maxLength": 3 }`, `{ "type": "string", "maxLength": 3 }`},
+		{
+			"exclusive bounds",
+			`{ "exclusiveMinimum": 1 }`,
+			`{ "minimum": 3, "exclusiveMaximum": 10 }`,
+			`{ "minimum": 3, "exclusiveMinimum": 1, "exclusiveMaximum": 10 }`,
+		},
+		// the least common multiple is not computed
+		{"differing multipleOf", `{ "type": "integer", "multipleOf": 4 }`, `{ "multipleOf": 6 }`, ``},
+		{"allOf", `{ "allOf": [{ "type": "integer" }, { "minimum": 1 }] }`, `{ "maximum": 5 }`, `{ "type": "integer", "minimum": 1, "maximum": 5 }`},
+		{"not", `{ "not": { "type": "null" } }`, `{ "type": "string" }`, `{ "type": "string", "not": { "type": "null" } }`},
+		{
+			"uniqueItems",
+			`{ "type": "array", "uniqueItems": true }`,
+			`{ "type": "array", "maxItems": 3 }`,
+			`{ "type": "array", "uniqueItems": true, "maxItems": 3 }`,
+		},
+		{
+			"prefixItems",
+			`{ "type": "array", "prefixItems": [{ "type": "string" }] }`,
+			`{ "type": "array", "prefixItems": [{ "minLength": 1 }, { "type": "integer" }] }`,
+			`{ "type": "array", "prefixItems": [{ "type": "string", "minLength": 1 }, { "type": "integer" }] }`,
+		},
+		{
+			"patternProperties",
+			`{ "type": "object", "patternProperties": { "^a": { "type": "string" } } }`,
+			`{ "type": "object", "patternProperties": { "^b": { "type": "integer" } } }`,
+			`{ "type": "object", "patternProperties": { "^a": { "type": "string" }, "^b": { "type": "integer" } } }`,
+		},
+	}
+	for _, test := range tests {
+		res, ok := openapi.IntersectSchemas(mustSchema(t, test.a), mustSchema(t, test.b))
+		if test.expected == "" {
+			if ok {
+				t.Errorf("%s: expected the intersection to be unknown", test.name)
+			}
+			continue
+		}
+		if !ok {
+			t.Errorf("%s: expected an intersection", test.name)
+			continue
+		}
+		assertJSONEqual(t, test.name, test.expected, res)
+	}
+}
+
+func TestUnionSchemas(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     string
+		expected string
+	}{
+		{"enums", `{ "enum": ["a", "c"] }`, `{ "enum": ["b", "a"] }`, `{ "enum": ["a", "c", "b"] }`},
+		{"enum subset", `{ "enum": ["a"] }`, `{ "enum": ["b", "a"] }`, `{ "enum": ["b", "a"] }`},
+		{
+			"disjoint types",
+			`{ "type": "string", "minLength": 1 }`,
+			`{ "type": "integer", "minimum": 0, "maxLength": 3 }`,
+			`{ "type": ["string", "integer"], "minLength": 1, "minimum": 0 }`,
+		},
+		{"subschema", `{ "type": "integer" }`, `{ "type": "number" }`, `{ "type": "number" }`},
+		{"never", `false`, `{ "type": "string" }`, `{ "type": "string" }`},
+		{"unknown", `{ "type": "string", "pattern": "^a" }`, `{ "type": "string", "maxLength": 3 }`, ``},
+		{"const in enum", `{ "const": "a" }`, `{ "enum": ["a", "b"] }`, `{ "enum": ["a", "b"] }`},
+		{"maxLength", `{ "type": "string", "maxLength": 5 }`, `{ "type": "string", "maxLength": 3 }`, `{ "type": "string", "maxLength": 5 }`},
+		{"exclusive bounds", `{ "exclusiveMinimum": 1 }`, `{ "minimum": 3, "exclusiveMaximum": 10 }`, `{ "exclusiveMinimum": 1 }`},
+		{"not", `{ "not": { "type": "null" } }`, `{ "type": "string" }`, `{ "not": { "type": "null" } }`},
+		{"oneOf", `{ "oneOf": [{ "type": "string" }, { "type": "integer" }] }`, `{ "type": "string" }`, `{ "oneOf": [{ "type": "string" }, { "type": "integer" }] }`},
+		// neither is a subschema of the other and both are arrays
+		{"uniqueItems", `{ "type": "array", "uniqueItems": true }`, `{ "type": "array", "maxItems": 3 }`, ``},
+		{"differing multipleOf", `{ "type": "integer", "multipleOf": 4 }`, `{ "multipleOf": 6 }`, ``},
+	}
+	for _, test := range tests {
+		res, ok := openapi.UnionSchemas(mustSchema(t, test.a), mustSchema(t, test.b))
+		if test.expected == "" {
+			if ok {
+				t.Errorf("%s: expected the union to be unknown, got %v", test.name, res)
+			}
+			continue
+		}
+		if !ok {
+			t.Errorf("%s: expected a union", test.name)
+			continue
+		}
+		assertJSONEqual(t, test.name, test.expected, res)
+	}
+}
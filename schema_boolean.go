@@ -0,0 +1,79 @@
+package openapi
+
+// NewBoolSchema returns the boolean Schema b. The Schema true permits any
+// value while false permits none. It is encoded as a boolean unless keywords
+// are added to it.
+func NewBoolSchema(b bool) *Schema {
+	return &Schema{boolean: &b}
+}
+
+// Bool returns the value of s and true if s is a boolean Schema, i.e. it was
+// decoded from true or false or constructed with NewBoolSchema, and has not
+// had keywords added to it.
+//
+// Objects equivalent to boolean Schemas, such as {} and {"not": {}}, are not
+// boolean Schemas; see IsAlways and IsNever.
+func (s *Schema) Bool() (value bool, ok bool) {
+	if s == nil || s.boolean == nil || !s.isBlank() {
+		return false, false
+	}
+	return *s.boolean, true
+}
+
+// IsAlways reports whether s permits any value, i.e. it is either the
+// boolean Schema true or an object without keywords ({}).
+func (s *Schema) IsAlways() bool {
+	if s == nil {
+		return false
+	}
+	if v, ok := s.Bool(); ok {
+		return v
+	}
+	return s.isBlank()
+}
+
+// IsNever reports whether s permits no value, i.e. it is either the boolean
+// Schema false or an object consisting only of a "not" which permits any
+// value ({"not": {}} or {"not": true}).
+func (s *Schema) IsNever() bool {
+	if s == nil {
+		return false
+	}
+	if v, ok := s.Bool(); ok {
+		return !v
+	}
+	if !s.Not.IsAlways() {
+		return false
+	}
+	c := *s
+	c.Not = nil
+	return c.isBlank()
+}
+
+// isBlank reports whether s has no keywords, disregarding whether it is a
+// boolean Schema. It is equivalent to s encoding as {}, without marshaling s.
+func (s *Schema) isBlank() bool {
+	return len(s.Extensions) == 0 && len(s.Keywords) == 0 &&
+		s.Schema == nil && s.ID == nil && s.Anchor == "" && s.DynamicAnchor == "" &&
+		s.RecursiveAnchor == nil && len(s.Type) == 0 &&
+		s.Ref == nil && s.DynamicRef == nil && s.RecursiveRef == nil &&
+		s.Format == "" && len(s.Const) == 0 && len(s.Required) == 0 &&
+		s.Properties == nil && len(s.Enum) == 0 && s.Comments == "" &&
+		s.Not == nil && s.AllOf == nil && s.AnyOf == nil && s.OneOf == nil &&
+		s.If == nil && s.Then == nil && s.Else == nil &&
+		s.MinProperties == nil && s.MaxProperties == nil && s.PropertyNames == nil &&
+		s.RegexProperties == nil && s.PatternProperties == nil &&
+		s.AdditionalProperties == nil && s.DependentRequired == nil &&
+		s.DependentSchemas == nil && s.UnevaluatedProperties == nil &&
+		s.UniqueItems == nil && s.Items == nil && s.UnevaluatedItems == nil &&
+		s.AdditionalItems == nil && s.PrefixItems == nil && s.Contains == nil &&
+		s.MinContains == nil && s.MaxContains == nil &&
+		s.MinLength == nil && s.MaxLength == nil && s.Pattern == nil &&
+		s.ContentEncoding == "" && s.ContentMediaType == "" &&
+		s.Minimum == nil && s.ExclusiveMinimum == nil &&
+		s.Maximum == nil && s.ExclusiveMaximum == nil && s.MultipleOf == nil &&
+		s.Title == "" && s.Description == "" && len(s.Default) == 0 &&
+		s.ReadOnly == nil && s.WriteOnly == nil && len(s.Examples) == 0 &&
+		len(s.Example) == 0 && s.Deprecated == nil && s.ExternalDocs == "" &&
+		s.Discriminator == nil && s.XML == nil && s.Definitions == nil
+}
@@ -0,0 +1,103 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/chanced/openapi"
+)
+
+func TestBoolSchema(t *testing.T) {
+	tests := []struct {
+		data   string
+		bool   bool
+		isBool bool
+		always bool
+		never  bool
+	}{
+		{data: `true`, bool: true, isBool: true, always: true},
+		{data: `false`, isBool: true, never: true},
+		{data: `{}`, always: true},
+		{data: `{"not":{}}`, never: true},
+		{data: `{"not":true}`, never: true},
+		{data: `{"type":"string"}`},
+	}
+	for _, test := range tests {
+		var s openapi.Schema
+		if err := json.Unmarshal([]byte(test.data), &s); err != nil {
+			t.Fatal(err)
+		}
+		if v, ok := s.Bool(); v != test.bool || ok != test.isBool {
+			t.Errorf("%s: expected Bool to return %t, %t; got %t, %t", test.data, test.bool, test.isBool, v, ok)
+		}
+		if s.IsAlways() != test.always {
+			t.Errorf("%s: expected IsAlways to be %t", test.data, test.always)
+		}
+		if s.IsNever() != test.never {
+			t.Errorf("%s: expected IsNever to be %t", test.data, test.never)
+		}
+		b, err := json.Marshal(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != test.data {
+			t.Errorf("expected %s to round-trip, got %s", test.data, b)
+		}
+	}
+
+	s := openapi.NewBoolSchema(false)
+	if s.Not != nil {
+		t.Error("expected false not to be represented with not")
+	}
+	s.Type = openapi.Types{openapi.TypeString}
+	if _, ok := s.Bool(); ok {
+		t.Error("expected a boolean schema with keywords not to be boolean")
+	}
+	if b, _ := json.Marshal(s); string(b) != `{"type":"string"}` {
+		t.Errorf("expected a boolean schema with keywords to be encoded as an object, got %s", b)
+	}
+
+	var obj openapi.Schema
+	err := json.Unmarshal([]byte(`{"allOf":[{"type":"string"},false]}`), &obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	merged, _ := obj.MergeAllOf()
+	if merged == nil || merged.Not == nil || !merged.Not.IsAlways() {
+		t.Errorf("expected merging false to produce a schema which permits nothing, got %v", merged)
+	}
+}
+
+// TestSchemaIsAlwaysFields ensures that setting any field of a Schema which
+// is encoded makes it no longer equivalent to {}.
+func TestSchemaIsAlwaysFields(t *testing.T) {
+	typ := reflect.TypeOf(openapi.Schema{})
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if !f.IsExported() || f.Name == "Location" {
+			continue
+		}
+		s := openapi.NewBoolSchema(true)
+		v := reflect.ValueOf(s).Elem().Field(i)
+		switch v.Kind() {
+		case reflect.Ptr:
+			v.Set(reflect.New(f.Type.Elem()))
+		case reflect.String:
+			v.SetString("x")
+		case reflect.Slice:
+			v.Set(reflect.MakeSlice(f.Type, 1, 1))
+		case reflect.Map:
+			v.Set(reflect.MakeMap(f.Type))
+			v.SetMapIndex(reflect.ValueOf("x-a").Convert(f.Type.Key()), reflect.Zero(f.Type.Elem()))
+		default:
+			t.Fatalf("unexpected kind %s of field %s", v.Kind(), f.Name)
+		}
+		if s.IsAlways() {
+			t.Errorf("expected a Schema with %s to not be equivalent to {}", f.Name)
+		}
+		if _, ok := s.Bool(); ok {
+			t.Errorf("expected a Schema with %s to not be a boolean Schema", f.Name)
+		}
+	}
+}
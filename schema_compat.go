@@ -0,0 +1,427 @@
+package openapi
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/chanced/jsonpointer"
+)
+
+// CompatibilityMode is the direction in which values described by a Schema
+// flow between the provider of an API and its consumers, which determines
+// how the provider's Schema may change without breaking consumers. See
+// CheckCompatible.
+type CompatibilityMode uint8
+
+const (
+	// CompatibilityResponse is for values written by the provider and read
+	// by consumers, such as response bodies. Every value permitted by the
+	// provider's Schema must be permitted by the consumer's; the provider
+	// may narrow (e.g. add a required property) but not widen (e.g. add an
+	// enum value).
+	CompatibilityResponse CompatibilityMode = iota
+	// CompatibilityRequest is for values written by consumers and read by
+	// the provider, such as request bodies and parameters. Every value
+	// permitted by the consumer's Schema must be permitted by the provider's;
+	// the provider may widen but not narrow.
+	CompatibilityRequest
+	// CompatibilityBoth is for values which are both written and read by
+	// each party. The Schemas must be equivalent.
+	CompatibilityBoth
+)
+
+var compatibilityModeNames = [...]string{
+	CompatibilityResponse: "response",
+	CompatibilityRequest:  "request",
+	CompatibilityBoth:     "both",
+}
+
+func (m CompatibilityMode) String() string {
+	if int(m) < len(compatibilityModeNames) {
+		return compatibilityModeNames[m]
+	}
+	return "CompatibilityMode(" + strconv.Itoa(int(m)) + ")"
+}
+
+// Incompatibility describes a difference between the Schemas of a provider
+// and consumer by which a value written by one may be rejected by the other.
+type Incompatibility struct {
+	// Pointer is the JSON pointer of the subschema, relative to the Schemas
+	// passed to CheckCompatible (e.g. "/properties/status").
+	Pointer jsonpointer.Pointer
+	// Keyword which is incompatible (e.g. "required", "enum", "maximum")
+	Keyword Text
+	// Mode is the direction in which the incompatibility occurs, either
+	// CompatibilityResponse or CompatibilityRequest.
+	Mode CompatibilityMode
+	// Reason describes the incompatibility.
+	Reason string
+}
+
+func (i Incompatibility) String() string {
+	ptr := i.Pointer.String()
+	if ptr == "" {
+		ptr = "/"
+	}
+	return fmt.Sprintf("%s: %s (%s): %s", ptr, i.Keyword, i.Mode, i.Reason)
+}
+
+// Compatibility is the result of CheckCompatible.
+type Compatibility struct {
+	// Compatible is DecisionYes if the provider's Schema is shown to satisfy
+	// the consumer's, DecisionNo if any Incompatibility was found, and
+	// otherwise DecisionUnknown.
+	Compatible Decision
+	// Incompatibilities found, in the order they were encountered
+	Incompatibilities []Incompatibility
+	// Undecided are the pointers of the subschemas whose compatibility could
+	// not be determined.
+	Undecided []jsonpointer.Pointer
+}
+
+// CheckCompatible reports whether the provider Schema of an API still
+// satisfies the Schema an existing consumer was built against, in the
+// direction given by mode, with an Incompatibility for each way in which
+// the provider breaks the consumer.
+//
+// The Schemas are compared structurally, by the rules of subtyping: the
+// reader of a value must require no more properties than the writer
+// (required subsets), accept each enumerated value the writer may produce
+// (enum narrowing), and accept the full numeric and length ranges the
+// writer permits (range containment), recursively through properties,
+// additionalProperties, and items. Resolved references are followed and
+// allOf branches are merged. Subschemas which use other keywords, such as
+// anyOf, are compared with Schema.IsSubschemaOf.
+//
+// As with IsSubschemaOf, format and the content keywords are treated as
+// annotations and do not affect compatibility.
+func CheckCompatible(provider, consumer *Schema, mode CompatibilityMode) Compatibility {
+	c := &compatChecker{}
+	if mode == CompatibilityResponse || mode == CompatibilityBoth {
+		c.run(CompatibilityResponse, provider, consumer)
+	}
+	if mode == CompatibilityRequest || mode == CompatibilityBoth {
+		c.run(CompatibilityRequest, consumer, provider)
+	}
+	switch {
+	case len(c.res.Incompatibilities) > 0:
+		c.res.Compatible = DecisionNo
+	case len(c.res.Undecided) > 0:
+		c.res.Compatible = DecisionUnknown
+	default:
+		c.res.Compatible = DecisionYes
+	}
+	return c.res
+}
+
+type compatChecker struct {
+	res Compatibility
+	// mode of the current pass
+	mode CompatibilityMode
+	// writer and reader are the roles of the parties in the current pass
+	writer, reader string
+	visiting       map[[2]*Schema]bool
+}
+
+func (c *compatChecker) run(mode CompatibilityMode, writer, reader *Schema) {
+	c.mode = mode
+	c.writer, c.reader = "provider", "consumer"
+	if mode == CompatibilityRequest {
+		c.writer, c.reader = "consumer", "provider"
+	}
+	c.visiting = map[[2]*Schema]bool{}
+	c.check("", writer, reader, 0)
+}
+
+func (c *compatChecker) incompatible(ptr jsonpointer.Pointer, keyword Text, format string, args ...interface{}) {
+	c.res.Incompatibilities = append(c.res.Incompatibilities, Incompatibility{
+		Pointer: ptr,
+		Keyword: keyword,
+		Mode:    c.mode,
+		Reason:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (c *compatChecker) undecided(ptr jsonpointer.Pointer) {
+	for _, p := range c.res.Undecided {
+		if p == ptr {
+			return
+		}
+	}
+	c.res.Undecided = append(c.res.Undecided, ptr)
+}
+
+// check compares the Schema w of the writer with the Schema r of the reader
+func (c *compatChecker) check(ptr jsonpointer.Pointer, w, r *Schema, depth int) {
+	if r == nil || r.IsAlways() || w.IsNever() {
+		return
+	}
+	key := [2]*Schema{w, r}
+	if c.visiting[key] {
+		// recursive Schemas are compatible if they are at each level
+		return
+	}
+	if depth > maxInstanceDepth {
+		c.undecided(ptr)
+		return
+	}
+	c.visiting[key] = true
+	defer delete(c.visiting, key)
+
+	if r.IsNever() {
+		c.incompatible(ptr, "not", "the %s permits values but the %s permits none", c.writer, c.reader)
+		return
+	}
+	ew, ok := effectiveSchema(w)
+	if !ok {
+		c.undecided(ptr)
+		return
+	}
+	er, ok := effectiveSchema(r)
+	if !ok {
+		c.undecided(ptr)
+		return
+	}
+	if !structural(ew) || !structural(er) {
+		switch w.IsSubschemaOf(r) {
+		case DecisionNo:
+			c.incompatible(ptr, "", "the %s permits values which the %s does not", c.writer, c.reader)
+		case DecisionUnknown:
+			c.undecided(ptr)
+		}
+		return
+	}
+
+	if values, ok := finiteValues(ew); ok {
+		// the values of the writer are known; each must be accepted
+		for _, data := range values {
+			v, _ := decodeInstance(data)
+			switch permits(er, v, 0) {
+			case DecisionNo:
+				c.incompatible(ptr, valuesKeyword(er), "the %s permits %s which the %s does not", c.writer, data, c.reader)
+			case DecisionUnknown:
+				c.undecided(ptr)
+			}
+		}
+		return
+	}
+	c.checkTypes(ptr, ew, er)
+	c.checkValues(ptr, ew, er)
+	if schemaPermitsType(ew, TypeString) {
+		c.checkString(ptr, ew, er)
+	}
+	if schemaPermitsType(ew, TypeNumber) {
+		c.checkNumber(ptr, ew, er)
+	}
+	if schemaPermitsType(ew, TypeObject) {
+		c.checkObject(ptr, ew, er, depth)
+	}
+	if schemaPermitsType(ew, TypeArray) {
+		c.checkArray(ptr, ew, er, depth)
+	}
+}
+
+// finiteValues returns the values permitted by the effective Schema s if
+// they are enumerated or s permits only booleans and null
+func finiteValues(s *Schema) (Enum, bool) {
+	if values, ok := enumeratedValues(s); ok {
+		return values, true
+	}
+	types := schemaTypes(s)
+	if len(types) == 0 {
+		return nil, false
+	}
+	for _, t := range types {
+		if t != TypeBoolean && t != TypeNull {
+			return nil, false
+		}
+	}
+	var values Enum
+	for _, data := range []string{"true", "false", "null"} {
+		v, _ := decodeInstance([]byte(data))
+		switch permits(s, v, 0) {
+		case DecisionYes:
+			values = append(values, []byte(data))
+		case DecisionUnknown:
+			return nil, false
+		}
+	}
+	return values, true
+}
+
+// structural reports whether the effective Schema s consists only of the
+// keywords compared structurally by CheckCompatible
+func structural(s *Schema) bool {
+	if _, ok := s.Bool(); ok {
+		return true
+	}
+	c := *stripAnnotations(s)
+	c.Type, c.Enum, c.Const = nil, nil, nil
+	c.MinLength, c.MaxLength, c.Pattern = nil, nil, nil
+	c.Minimum, c.ExclusiveMinimum, c.Maximum, c.ExclusiveMaximum, c.MultipleOf = nil, nil, nil, nil, nil
+	c.Properties, c.AdditionalProperties, c.Required = nil, nil, nil
+	c.MinProperties, c.MaxProperties = nil, nil
+	c.Items, c.UniqueItems = nil, nil
+	c.Keywords = nil
+	for k := range s.Keywords {
+		if k != "minItems" && k != "maxItems" {
+			return false
+		}
+	}
+	return c.isBlank()
+}
+
+func valuesKeyword(s *Schema) Text {
+	switch {
+	case s.Const != nil:
+		return "const"
+	case len(s.Enum) > 0:
+		return "enum"
+	default:
+		return "type"
+	}
+}
+
+func (c *compatChecker) checkTypes(ptr jsonpointer.Pointer, w, r *Schema) {
+	if len(r.Type) == 0 {
+		return
+	}
+	types := schemaTypes(w)
+	if types == nil {
+		c.incompatible(ptr, "type", "the %s permits any type but the %s permits only %s", c.writer, c.reader, typesString(r.Type))
+		return
+	}
+	for _, t := range types {
+		if !typesAllow(r.Type, t) {
+			c.incompatible(ptr, "type", "the %s permits %s which the %s does not", c.writer, t, c.reader)
+		}
+	}
+}
+
+func typesString(types Types) string {
+	if len(types) == 1 {
+		return string(types[0])
+	}
+	return fmt.Sprint([]Type(types))
+}
+
+func (c *compatChecker) checkValues(ptr jsonpointer.Pointer, w, r *Schema) {
+	switch {
+	case r.Const != nil:
+		c.incompatible(ptr, "const", "the %s permits only %s but the %s is not restricted to it", c.reader, r.Const, c.writer)
+	case len(r.Enum) > 0:
+		c.incompatible(ptr, "enum", "the %s permits only %s but the %s is not restricted to them", c.reader, r.Enum, c.writer)
+	}
+}
+
+func (c *compatChecker) checkString(ptr jsonpointer.Pointer, w, r *Schema) {
+	if r.MinLength != nil && (w.MinLength == nil || w.MinLength.Cmp(*r.MinLength) < 0) {
+		c.incompatible(ptr, "minLength", "the %s requires a length of at least %s but the %s permits shorter strings", c.reader, r.MinLength, c.writer)
+	}
+	if r.MaxLength != nil && (w.MaxLength == nil || w.MaxLength.Cmp(*r.MaxLength) > 0) {
+		c.incompatible(ptr, "maxLength", "the %s requires a length of at most %s but the %s permits longer strings", c.reader, r.MaxLength, c.writer)
+	}
+	switch {
+	case r.Pattern.IsNil():
+	case w.Pattern.IsNil():
+		c.incompatible(ptr, "pattern", "the %s requires the pattern %q but the %s does not", c.reader, r.Pattern, c.writer)
+	case w.Pattern.String() != r.Pattern.String():
+		c.undecided(ptr.AppendString("pattern"))
+	}
+}
+
+func (c *compatChecker) checkNumber(ptr jsonpointer.Pointer, w, r *Schema) {
+	if r.Minimum != nil && !boundedBelow(w, *r.Minimum, false) {
+		c.incompatible(ptr, "minimum", "the %s requires a minimum of %s but the %s permits smaller numbers", c.reader, r.Minimum, c.writer)
+	}
+	if r.ExclusiveMinimum != nil && !boundedBelow(w, *r.ExclusiveMinimum, true) {
+		c.incompatible(ptr, "exclusiveMinimum", "the %s requires numbers greater than %s but the %s does not", c.reader, r.ExclusiveMinimum, c.writer)
+	}
+	if r.Maximum != nil && !boundedAbove(w, *r.Maximum, false) {
+		c.incompatible(ptr, "maximum", "the %s requires a maximum of %s but the %s permits larger numbers", c.reader, r.Maximum, c.writer)
+	}
+	if r.ExclusiveMaximum != nil && !boundedAbove(w, *r.ExclusiveMaximum, true) {
+		c.incompatible(ptr, "exclusiveMaximum", "the %s requires numbers less than %s but the %s does not", c.reader, r.ExclusiveMaximum, c.writer)
+	}
+	if r.MultipleOf != nil && !impliesMultipleOf(w, *r.MultipleOf) {
+		c.incompatible(ptr, "multipleOf", "the %s requires multiples of %s but the %s does not", c.reader, r.MultipleOf, c.writer)
+	}
+}
+
+func (c *compatChecker) checkObject(ptr jsonpointer.Pointer, w, r *Schema, depth int) {
+	for _, req := range r.Required {
+		if !textsContain(w.Required, req) {
+			c.incompatible(ptr, "required", "the %s requires the property %q but the %s does not", c.reader, req, c.writer)
+		}
+	}
+	if r.MinProperties != nil && (w.MinProperties == nil || w.MinProperties.Cmp(*r.MinProperties) < 0) &&
+		IntNumber(int64(len(w.Required))).Cmp(*r.MinProperties) < 0 {
+		c.incompatible(ptr, "minProperties", "the %s requires at least %s properties but the %s permits fewer", c.reader, r.MinProperties, c.writer)
+	}
+	if r.MaxProperties != nil && (w.MaxProperties == nil || w.MaxProperties.Cmp(*r.MaxProperties) > 0) {
+		c.incompatible(ptr, "maxProperties", "the %s permits at most %s properties but the %s permits more", c.reader, r.MaxProperties, c.writer)
+	}
+
+	// property returns the Schema of s which applies to the property k
+	property := func(s *Schema, k Text) *Schema {
+		if s.Properties != nil {
+			if ps := s.Properties.Get(k); ps != nil {
+				return ps
+			}
+		}
+		return s.AdditionalProperties
+	}
+	if r.Properties != nil {
+		for _, item := range r.Properties.Items {
+			wp := property(w, item.Key)
+			if wp.IsNever() {
+				continue
+			}
+			c.check(ptr.AppendString("properties").AppendString(item.Key.String()), wp, item.Schema, depth+1)
+		}
+	}
+	if r.AdditionalProperties == nil {
+		return
+	}
+	if w.Properties != nil {
+		for _, item := range w.Properties.Items {
+			if (r.Properties != nil && r.Properties.Get(item.Key) != nil) || item.Schema.IsNever() {
+				continue
+			}
+			p := ptr.AppendString("properties").AppendString(item.Key.String())
+			if r.AdditionalProperties.IsNever() {
+				c.incompatible(p, "additionalProperties", "the %s permits the property %q but the %s does not", c.writer, item.Key, c.reader)
+				continue
+			}
+			c.check(p, item.Schema, r.AdditionalProperties, depth+1)
+		}
+	}
+	if w.AdditionalProperties.IsNever() {
+		return
+	}
+	p := ptr.AppendString("additionalProperties")
+	if r.AdditionalProperties.IsNever() {
+		c.incompatible(p, "additionalProperties", "the %s permits additional properties but the %s does not", c.writer, c.reader)
+		return
+	}
+	c.check(p, w.AdditionalProperties, r.AdditionalProperties, depth+1)
+}
+
+func (c *compatChecker) checkArray(ptr jsonpointer.Pointer, w, r *Schema, depth int) {
+	if min, ok := r.keywordNumber("minItems"); ok {
+		if wmin, ok := w.keywordNumber("minItems"); !ok || wmin.Cmp(min) < 0 {
+			c.incompatible(ptr, "minItems", "the %s requires at least %s items but the %s permits fewer", c.reader, min, c.writer)
+		}
+	}
+	if max, ok := r.keywordNumber("maxItems"); ok {
+		if wmax, ok := w.keywordNumber("maxItems"); !ok || wmax.Cmp(max) > 0 {
+			c.incompatible(ptr, "maxItems", "the %s permits at most %s items but the %s permits more", c.reader, max, c.writer)
+		}
+	}
+	if r.UniqueItems != nil && *r.UniqueItems && (w.UniqueItems == nil || !*w.UniqueItems) {
+		c.incompatible(ptr, "uniqueItems", "the %s requires unique items but the %s does not", c.reader, c.writer)
+	}
+	if r.Items != nil {
+		c.check(ptr.AppendString("items"), w.Items, r.Items, depth+1)
+	}
+}
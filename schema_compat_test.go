@@ -0,0 +1,124 @@
+package openapi_test
+
+import (
+	"testing"
+
+	"github.com/chanced/openapi"
+)
+
+func TestCheckCompatible(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider string
+		consumer string
+		mode     openapi.CompatibilityMode
+		expected openapi.Decision
+		// incompatibilities are given as "<pointer> <keyword>"
+		incompatibilities []string
+	}{
+		{
+			name:     "narrowed response",
+			provider: `{ "type": "object", "required": ["id", "name"], "properties": { "id": { "type": "integer", "minimum": 1 }, "name": { "type": "string", "maxLength": 10 } } }`,
+			consumer: `{ "type": "object", "required": ["id"], "properties": { "id": { "type": "number" }, "name": { "type": "string" } } }`,
+			mode:     openapi.CompatibilityResponse,
+			expected: openapi.DecisionYes,
+		},
+		{
+			name:              "widened enum",
+			provider:          `{ "type": "object", "properties": { "status": { "enum": ["active", "inactive", "pending"] } } }`,
+			consumer:          `{ "type": "object", "properties": { "status": { "enum": ["active", "inactive"] } } }`,
+			mode:              openapi.CompatibilityResponse,
+			expected:          openapi.DecisionNo,
+			incompatibilities: []string{"/properties/status enum"},
+		},
+		{
+			name:              "removed required",
+			provider:          `{ "type": "object", "required": ["id"] }`,
+			consumer:          `{ "type": "object", "required": ["id", "name"] }`,
+			mode:              openapi.CompatibilityResponse,
+			expected:          openapi.DecisionNo,
+			incompatibilities: []string{" required"},
+		},
+		{
+			name:              "narrowed request",
+			provider:          `{ "type": "integer", "minimum": 1, "maximum": 100 }`,
+			consumer:          `{ "type": "integer", "minimum": 0, "maximum": 100 }`,
+			mode:              openapi.CompatibilityRequest,
+			expected:          openapi.DecisionNo,
+			incompatibilities: []string{" minimum"},
+		},
+		{
+			name:     "widened request",
+			provider: `{ "type": ["string", "null"], "maxLength": 20 }`,
+			consumer: `{ "type": "string", "maxLength": 10 }`,
+			mode:     openapi.CompatibilityRequest,
+			expected: openapi.DecisionYes,
+		},
+		{
+			name:              "closed object",
+			provider:          `{ "type": "object", "properties": { "id": { "type": "integer" }, "extra": { "type": "string" } } , "additionalProperties": false }`,
+			consumer:          `{ "type": "object", "properties": { "id": { "type": "integer" } }, "additionalProperties": false }`,
+			mode:              openapi.CompatibilityBoth,
+			expected:          openapi.DecisionNo,
+			incompatibilities: []string{"/properties/extra additionalProperties"},
+		},
+		{
+			name:              "items",
+			provider:          `{ "type": "array", "items": { "type": "number" } }`,
+			consumer:          `{ "type": "array", "items": { "type": "integer" } }`,
+			mode:              openapi.CompatibilityResponse,
+			expected:          openapi.DecisionNo,
+			incompatibilities: []string{"/items type"},
+		},
+		{
+			name:     "boolean",
+			provider: `{ "type": "boolean" }`,
+			consumer: `{ "enum": [true, false] }`,
+			mode:     openapi.CompatibilityResponse,
+			expected: openapi.DecisionYes,
+		},
+		{
+			name:     "differing patterns",
+			provider: `{ "type": "string", "pattern": "^[a-z]+$" }`,
+			consumer: `{ "type": "string", "pattern": "^[a-z0-9]+$" }`,
+			mode:     openapi.CompatibilityResponse,
+			expected: openapi.DecisionUnknown,
+		},
+		{
+			name:     "anyOf",
+			provider: `{ "anyOf": [{ "type": "string" }, { "type": "integer" }] }`,
+			consumer: `{ "type": ["string", "integer"] }`,
+			mode:     openapi.CompatibilityResponse,
+			expected: openapi.DecisionYes,
+		},
+	}
+	for _, test := range tests {
+		res := openapi.CheckCompatible(mustSchema(t, test.provider), mustSchema(t, test.consumer), test.mode)
+		if res.Compatible != test.expected {
+			t.Errorf("%s: expected %s, got %s (%v)", test.name, test.expected, res.Compatible, res.Incompatibilities)
+		}
+		var found []string
+		for _, i := range res.Incompatibilities {
+			found = append(found, i.Pointer.String()+" "+i.Keyword.String())
+		}
+		if len(found) != len(test.incompatibilities) {
+			t.Errorf("%s: expected incompatibilities %q, got %q", test.name, test.incompatibilities, found)
+			continue
+		}
+		for i := range found {
+			if found[i] != test.incompatibilities[i] {
+				t.Errorf("%s: expected incompatibilities %q, got %q", test.name, test.incompatibilities, found)
+				break
+			}
+		}
+	}
+
+	res := openapi.CheckCompatible(
+		mustSchema(t, `{ "type": "string" }`),
+		mustSchema(t, `{ "type": "string", "maxLength": 5 }`),
+		openapi.CompatibilityBoth,
+	)
+	if len(res.Incompatibilities) != 1 || res.Incompatibilities[0].Mode != openapi.CompatibilityResponse {
+		t.Errorf("expected a single response incompatibility, got %v", res.Incompatibilities)
+	}
+}
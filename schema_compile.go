@@ -0,0 +1,259 @@
+package openapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/chanced/uri"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// Compile compiles s into a CompiledSchema which can be used to validate
+// arbitrary instances (e.g. the result of json.Unmarshal into an
+// interface{}).
+//
+// The Schema graph must be resolved, typically by Load. Each $ref,
+// $dynamicRef, and $recursiveRef is compiled against the Schema it was
+// resolved to, including those referencing anchors and remote resources.
+// Consequently, no resources are fetched during compilation.
+//
+// The dialect is determined by s.Schema and defaults to JSON Schema 2020-12.
+// Dialects other than those registered by NewDialectRegistry must be
+// registered with CompileOpts.Dialects.
+func (s *Schema) Compile(ctx context.Context, opts ...CompileOpts) (CompiledSchema, error) {
+	if s == nil {
+		return nil, fmt.Errorf("openapi: cannot compile a nil schema")
+	}
+	o := mergeCompileOpts(opts)
+	sc := newSchemaCompiler()
+	dialect := JSONSchemaDialect202012
+	if s.Schema != nil {
+		dialect = *s.Schema
+	}
+	if o.Dialects == nil {
+		o.Dialects = NewDialectRegistry()
+	}
+	d, ok := o.Dialects.Lookup(dialect)
+	if !ok {
+		return nil, NewError(&UnsupportedDialectError{Dialect: dialect}, s.AbsoluteLocation())
+	}
+	switch dialectKey(d.Base()) {
+	case dialectKey(JSONSchemaDialect202012):
+		sc.compiler.Draft = jsonschema.Draft2020
+	case dialectKey(JSONSchemaDialect201909):
+		sc.compiler.Draft = jsonschema.Draft2019
+	case dialectKey(JSONSchemaDialect07):
+		sc.compiler.Draft = jsonschema.Draft7
+	default:
+		base := d.Base()
+		return nil, NewError(&UnsupportedDialectError{Dialect: dialect, Base: &base}, s.AbsoluteLocation())
+	}
+	sc.refOverride = d.RefSemantics() == RefSemanticsOverride
+	o.Keywords.register(sc.compiler, dialect)
+	if o.AssertFormat {
+		if o.Formats == nil {
+			o.Formats = NewFormatRegistry()
+		}
+		o.Formats.AssertFormats(sc.compiler)
+	}
+	if o.AssertContent {
+		assertContent(sc.compiler)
+	}
+	root := sc.add(s)
+	for len(sc.pending) > 0 {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		var x *Schema
+		x, sc.pending = sc.pending[0], sc.pending[1:]
+		if err := sc.addResource(x); err != nil {
+			return nil, NewError(err, x.AbsoluteLocation())
+		}
+	}
+	compiled, err := sc.compiler.Compile(root)
+	if err != nil {
+		return nil, NewError(fmt.Errorf("openapi: failed to compile schema: %w", err), s.AbsoluteLocation())
+	}
+	return compiled, nil
+}
+
+// CompileOpts are options for Schema.Compile
+type CompileOpts struct {
+	// Keywords, if set, registers the custom keywords which have
+	// ValidateInstance set so that they are applied during validation.
+	Keywords *KeywordRegistry
+	// AssertFormat, if true, treats the format keyword as an assertion
+	// rather than an annotation.
+	AssertFormat bool
+	// Formats used to validate the format keyword if AssertFormat is true.
+	// Defaults to NewFormatRegistry().
+	Formats *FormatRegistry
+	// AssertContent, if true, asserts that string instances are encoded
+	// according to contentEncoding and, if contentMediaType is JSON, that
+	// their decoded content is valid JSON which matches contentSchema. See
+	// Schema.ValidateContent.
+	//
+	// Regardless, the content keywords are asserted for the encodings and
+	// media types registered with github.com/santhosh-tekuri/jsonschema/v5
+	// (base64 and application/json by default).
+	AssertContent bool
+	// Dialects used to determine how schemas are compiled. Defaults to
+	// NewDialectRegistry().
+	Dialects *DialectRegistry
+}
+
+func mergeCompileOpts(opts []CompileOpts) CompileOpts {
+	var c CompileOpts
+	for _, o := range opts {
+		if o.Keywords != nil {
+			c.Keywords = o.Keywords
+		}
+		if o.AssertFormat {
+			c.AssertFormat = true
+		}
+		if o.Formats != nil {
+			c.Formats = o.Formats
+		}
+		if o.AssertContent {
+			c.AssertContent = true
+		}
+		if o.Dialects != nil {
+			c.Dialects = o.Dialects
+		}
+	}
+	return c
+}
+
+// schemaCompiler bundles a resolved Schema graph into in-memory resources,
+// one for each distinct Schema which is the root of the graph or the target of
+// a reference.
+type schemaCompiler struct {
+	compiler    *jsonschema.Compiler
+	ids         map[*Schema]string
+	pending     []*Schema
+	refOverride bool
+}
+
+func newSchemaCompiler() *schemaCompiler {
+	return &schemaCompiler{
+		compiler: newOfflineCompiler(),
+		ids:      map[*Schema]string{},
+	}
+}
+
+// add returns the resource URI for s, queueing s to be added to the compiler
+// if it has not yet been seen.
+func (sc *schemaCompiler) add(s *Schema) string {
+	if id, ok := sc.ids[s]; ok {
+		return id
+	}
+	id := fmt.Sprintf("urn:openapi:schema:%d", len(sc.ids))
+	sc.ids[s] = id
+	sc.pending = append(sc.pending, s)
+	return id
+}
+
+func (sc *schemaCompiler) addResource(s *Schema) error {
+	data, err := s.MarshalJSON()
+	if err != nil {
+		return fmt.Errorf("openapi: failed to marshal schema: %w", err)
+	}
+	var v interface{}
+	if err = json.Unmarshal(data, &v); err != nil {
+		return fmt.Errorf("openapi: failed to unmarshal schema: %w", err)
+	}
+	if m, ok := v.(map[string]interface{}); ok {
+		// references are rewritten to the bundled resources so neither the
+		// dialect nor the base URI of the original resource are relevant.
+		delete(m, "$schema")
+		delete(m, "$id")
+		for _, r := range s.Refs() {
+			sr, ok := r.(*SchemaRef)
+			if !ok {
+				continue
+			}
+			if err = sc.rewriteRef(s, m, sr); err != nil {
+				return err
+			}
+		}
+	}
+	data, err = json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("openapi: failed to marshal schema: %w", err)
+	}
+	return sc.compiler.AddResource(sc.ids[s], bytes.NewReader(data))
+}
+
+// rewriteRef replaces the reference r, located within s, with the URI of the
+// resource for the Schema it resolved to.
+//
+// $dynamicRef and $recursiveRef are applied as they were resolved by Load and
+// are therefore rewritten as an allOf branch with a $ref.
+func (sc *schemaCompiler) rewriteRef(s *Schema, m map[string]interface{}, r *SchemaRef) error {
+	if r.Resolved == nil {
+		return newErrUnresolvedReference(r)
+	}
+	toks, err := relativeTokens(s.AbsoluteLocation(), r.AbsoluteLocation())
+	if err != nil {
+		return err
+	}
+	if len(toks) == 0 {
+		return fmt.Errorf("openapi: invalid location for %s: %s", r.RefType(), r.AbsoluteLocation().String())
+	}
+	var v interface{} = m
+	for _, tok := range toks[:len(toks)-1] {
+		switch t := v.(type) {
+		case map[string]interface{}:
+			v = t[tok]
+		case []interface{}:
+			i, err := strconv.Atoi(tok)
+			if err != nil || i < 0 || i >= len(t) {
+				return fmt.Errorf("openapi: invalid location for %s: %s", r.RefType(), r.AbsoluteLocation().String())
+			}
+			v = t[i]
+		default:
+			return fmt.Errorf("openapi: invalid location for %s: %s", r.RefType(), r.AbsoluteLocation().String())
+		}
+	}
+	parent, ok := v.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("openapi: invalid location for %s: %s", r.RefType(), r.AbsoluteLocation().String())
+	}
+	id := sc.add(r.Resolved)
+	kw := toks[len(toks)-1]
+	if kw == "$ref" {
+		if sc.refOverride {
+			// siblings of $ref are ignored
+			for k := range parent {
+				delete(parent, k)
+			}
+		}
+		parent[kw] = id
+		return nil
+	}
+	delete(parent, kw)
+	allOf, _ := parent["allOf"].([]interface{})
+	parent["allOf"] = append(allOf, map[string]interface{}{"$ref": id})
+	return nil
+}
+
+// relativeTokens returns the unescaped JSON pointer tokens of the fragment of
+// u relative to the fragment of base.
+func relativeTokens(base uri.URI, u uri.URI) ([]string, error) {
+	if !(Location{absolute: u}).IsRelativeTo(&base) {
+		return nil, fmt.Errorf("openapi: %s is not relative to %s", u.String(), base.String())
+	}
+	bp := strings.TrimSuffix(base.Fragment, "/")
+	if !strings.HasPrefix(u.Fragment, bp+"/") {
+		return nil, fmt.Errorf("openapi: %s is not relative to %s", u.String(), base.String())
+	}
+	toks := strings.Split(strings.TrimPrefix(u.Fragment, bp+"/"), "/")
+	for i, tok := range toks {
+		toks[i] = strings.ReplaceAll(strings.ReplaceAll(tok, "~1", "/"), "~0", "~")
+	}
+	return toks, nil
+}
@@ -0,0 +1,219 @@
+package openapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// keywordContentSchema is the JSON Schema keyword which describes the
+// decoded content of a string. Schema does not define it, so it is retained
+// in the Keywords of a Schema.
+const keywordContentSchema Text = "contentSchema"
+
+// contentDecoders decodes strings for each supported contentEncoding, keyed
+// by the lowercase name of the encoding
+var contentDecoders = map[string]func(string) ([]byte, error){
+	"base64":    base64.StdEncoding.DecodeString,
+	"base64url": base64.URLEncoding.DecodeString,
+	"base32":    base32.StdEncoding.DecodeString,
+	"base32hex": base32.HexEncoding.DecodeString,
+	"base16":    hex.DecodeString,
+	"7bit":      identityContent,
+	"8bit":      identityContent,
+	"binary":    identityContent,
+}
+
+func identityContent(value string) ([]byte, error) { return []byte(value), nil }
+
+// ContentSchema decodes the contentSchema keyword of s, which describes the
+// content of string instances once decoded (see DecodeContent) and parsed
+// according to contentMediaType. If s does not have one, nil is returned.
+func (s *Schema) ContentSchema() (*Schema, error) {
+	if s == nil {
+		return nil, nil
+	}
+	data, ok := s.Keywords[keywordContentSchema]
+	if !ok {
+		return nil, nil
+	}
+	var cs Schema
+	if err := json.Unmarshal(data, &cs); err != nil {
+		return nil, NewError(fmt.Errorf("openapi: failed to decode keyword %q: %w", keywordContentSchema, err), s.AbsoluteLocation())
+	}
+	return &cs, nil
+}
+
+// DecodeContent decodes the string instance value according to the
+// contentEncoding of s. Supported encodings are "base64", "base64url",
+// "base32", "base32hex", "base16", "7bit", "8bit", and "binary", compared
+// case-insensitively. If s does not have a contentEncoding or it is not
+// supported, value is returned as is.
+//
+// Errors wrap ErrInvalidContent.
+func (s *Schema) DecodeContent(value string) ([]byte, error) {
+	if s == nil {
+		return []byte(value), nil
+	}
+	return decodeContent(s.ContentEncoding.String(), value)
+}
+
+// ValidateContent validates the string instance value against the content
+// keywords of s: value is decoded according to contentEncoding and, if
+// contentMediaType is JSON (e.g. "application/json" or a type with a "+json"
+// suffix), parsed and validated against contentSchema. Media types other than
+// JSON are not parsed, nor is content of an unsupported encoding (see
+// DecodeContent).
+//
+// Errors wrap ErrInvalidContent. contentSchema is compiled with opts; see
+// Schema.Compile.
+func (s *Schema) ValidateContent(ctx context.Context, value string, opts ...CompileOpts) error {
+	if s == nil {
+		return nil
+	}
+	var compiled CompiledSchema
+	cs, err := s.ContentSchema()
+	if err != nil {
+		return err
+	}
+	if cs != nil && isJSONMediaType(s.ContentMediaType.String()) {
+		if cs.Schema == nil {
+			cs.Schema = s.Schema
+		}
+		if compiled, err = cs.Compile(ctx, opts...); err != nil {
+			return err
+		}
+	}
+	if _, err = checkContent(s.ContentEncoding.String(), s.ContentMediaType.String(), compiled, value); err != nil {
+		return NewError(err, s.AbsoluteLocation())
+	}
+	return nil
+}
+
+// checkContent validates value against the content keywords, returning the
+// keyword which failed along with an error wrapping ErrInvalidContent.
+func checkContent(encoding, mediaType string, schema CompiledSchema, value string) (string, error) {
+	if _, ok := contentDecoders[strings.ToLower(encoding)]; !ok && encoding != "" {
+		// the content can not be decoded
+		return "", nil
+	}
+	data, err := decodeContent(encoding, value)
+	if err != nil {
+		return "contentEncoding", err
+	}
+	if !isJSONMediaType(mediaType) {
+		return "", nil
+	}
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	var v interface{}
+	if err = d.Decode(&v); err == nil && d.More() {
+		err = fmt.Errorf("unexpected data after top-level value")
+	}
+	if err != nil {
+		return "contentMediaType", fmt.Errorf("%w: content is not valid %s: %v", ErrInvalidContent, mediaType, err)
+	}
+	if schema == nil {
+		return "", nil
+	}
+	if err = schema.Validate(v); err != nil {
+		return keywordContentSchema.String(), fmt.Errorf("%w: content does not match contentSchema: %v", ErrInvalidContent, err)
+	}
+	return "", nil
+}
+
+func decodeContent(encoding, value string) ([]byte, error) {
+	decode, ok := contentDecoders[strings.ToLower(encoding)]
+	if !ok {
+		return []byte(value), nil
+	}
+	data, err := decode(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: content is not %s encoded: %v", ErrInvalidContent, encoding, err)
+	}
+	return data, nil
+}
+
+// isJSONMediaType reports whether mediaType is "application/json" or has a
+// "+json" suffix, disregarding parameters
+func isJSONMediaType(mediaType string) bool {
+	if mediaType == "" {
+		return false
+	}
+	mt, _, err := mime.ParseMediaType(mediaType)
+	if err != nil {
+		return false
+	}
+	return mt == "application/json" || strings.HasSuffix(mt, "+json")
+}
+
+// assertContent configures compiler to assert the content keywords,
+// including contentSchema, with checkContent.
+func assertContent(compiler *jsonschema.Compiler) {
+	compiler.RegisterExtension("openapi-content", nil, contentExtension{})
+}
+
+// contentExtension asserts the content keywords of a Schema. It complements
+// the content assertions built into github.com/santhosh-tekuri/jsonschema/v5,
+// which are limited to the encodings and media types of jsonschema.Decoders
+// and jsonschema.MediaTypes and do not apply contentSchema.
+type contentExtension struct{}
+
+func (contentExtension) Compile(ctx jsonschema.CompilerContext, m map[string]interface{}) (jsonschema.ExtSchema, error) {
+	encoding, _ := m["contentEncoding"].(string)
+	mediaType, _ := m["contentMediaType"].(string)
+	if encoding == "" && mediaType == "" {
+		return nil, nil
+	}
+	cs := contentSchema{encoding: encoding, mediaType: mediaType}
+	if _, ok := m[keywordContentSchema.String()]; ok && isJSONMediaType(mediaType) {
+		sch, err := ctx.Compile(keywordContentSchema.String(), false)
+		if err != nil {
+			return nil, err
+		}
+		cs.schema = sch
+	}
+	return cs, nil
+}
+
+type contentSchema struct {
+	encoding  string
+	mediaType string
+	schema    *jsonschema.Schema
+}
+
+func (cs contentSchema) Validate(ctx jsonschema.ValidationContext, v interface{}) error {
+	value, ok := v.(string)
+	if !ok {
+		return nil
+	}
+	var schema CompiledSchema
+	if cs.schema != nil {
+		schema = cs.schema
+	}
+	keyword, err := checkContent(cs.encoding, cs.mediaType, schema, value)
+	if err == nil {
+		return nil
+	}
+	// failures already reported by the compiler's own assertions
+	switch keyword {
+	case "contentEncoding":
+		if _, ok := jsonschema.Decoders[cs.encoding]; ok {
+			return nil
+		}
+	case "contentMediaType":
+		_, decoded := jsonschema.Decoders[cs.encoding]
+		if _, ok := jsonschema.MediaTypes[cs.mediaType]; ok && (decoded || cs.encoding == "") {
+			return nil
+		}
+	}
+	return ctx.Error(keyword, "%v", err)
+}
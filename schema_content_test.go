@@ -0,0 +1,141 @@
+package openapi_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/chanced/openapi"
+)
+
+func TestSchemaDecodeContent(t *testing.T) {
+	tests := []struct {
+		encoding openapi.Text
+		value    string
+		expected string
+		valid    bool
+	}{
+		{"", "plain", "plain", true},
+		{"base64", "aGk/Pz8=", "hi???", true},
+		{"base64url", "aGk_Pz8=", "hi???", true},
+		{"BASE64", "aGk=", "hi", true},
+		{"base32", "NBUQ====", "hi", true},
+		{"base16", "6869", "hi", true},
+		{"8bit", "hi", "hi", true},
+		{"x-unknown", "%%", "%%", true},
+		{"base64", "not base64!", "", false},
+		{"base16", "zz", "", false},
+	}
+	for _, test := range tests {
+		s := openapi.Schema{ContentEncoding: test.encoding}
+		data, err := s.DecodeContent(test.value)
+		if !test.valid {
+			if !errors.Is(err, openapi.ErrInvalidContent) {
+				t.Errorf("%s %q: expected ErrInvalidContent, got %v", test.encoding, test.value, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s %q: unexpected error: %v", test.encoding, test.value, err)
+			continue
+		}
+		if string(data) != test.expected {
+			t.Errorf("%s %q: expected %q, got %q", test.encoding, test.value, test.expected, string(data))
+		}
+	}
+}
+
+func TestSchemaValidateContent(t *testing.T) {
+	var s openapi.Schema
+	err := json.Unmarshal([]byte(`{
+		"type": "string",
+		"contentEncoding": "base64url",
+		"contentMediaType": "application/vnd.token+json",
+		"contentSchema": {
+			"type": "object",
+			"required": ["sub"],
+			"properties": { "sub": { "type": "string" } }
+		}
+	}`), &s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cs, err := s.ContentSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cs == nil || len(cs.Required) != 1 {
+		t.Fatalf("expected contentSchema to be decoded, got %v", cs)
+	}
+
+	encode := func(v string) string { return base64.URLEncoding.EncodeToString([]byte(v)) }
+	tests := []struct {
+		name  string
+		value string
+		valid bool
+	}{
+		{"valid", encode(`{"sub":"abc"}`), true},
+		{"invalid encoding", "%%%", false},
+		{"invalid json", encode(`{"sub":`), false},
+		{"invalid content", encode(`{"sub":1}`), false},
+		{"missing property", encode(`{}`), false},
+	}
+
+	ctx := context.Background()
+	for _, test := range tests {
+		err := s.ValidateContent(ctx, test.value)
+		if test.valid && err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+		}
+		if !test.valid && !errors.Is(err, openapi.ErrInvalidContent) {
+			t.Errorf("%s: expected ErrInvalidContent, got %v", test.name, err)
+		}
+	}
+
+	compiled, err := s.Compile(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = compiled.Validate(encode(`{"sub":1}`)); err != nil {
+		t.Errorf("expected contentSchema not to be asserted by default, got %v", err)
+	}
+	compiled, err = s.Compile(ctx, openapi.CompileOpts{AssertContent: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range tests {
+		err := compiled.Validate(test.value)
+		if test.valid && err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+		}
+		if !test.valid && err == nil {
+			t.Errorf("%s: expected an error", test.name)
+		}
+	}
+}
+
+func TestExampleValidateContent(t *testing.T) {
+	var s openapi.Schema
+	err := json.Unmarshal([]byte(`{
+		"type": "string",
+		"contentMediaType": "application/json",
+		"contentSchema": { "type": "array", "items": { "type": "integer" } }
+	}`), &s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	compiled, err := s.Compile(context.Background(), openapi.CompileOpts{AssertContent: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	valid := openapi.Example{Value: []byte(`"[1, 2, 3]"`)}
+	if err = valid.Validate(compiled); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	invalid := openapi.Example{Value: []byte(`"[1, \"two\"]"`)}
+	if err = invalid.Validate(compiled); err == nil {
+		t.Error("expected an error")
+	}
+}
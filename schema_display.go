@@ -0,0 +1,105 @@
+package openapi
+
+import "strings"
+
+// DescriptionPolicy determines how Schema.DisplayMetadata combines the
+// descriptions of a Schema and the Schemas it inherits from.
+type DescriptionPolicy uint8
+
+const (
+	// DescriptionFirst uses the first non-empty description.
+	DescriptionFirst DescriptionPolicy = iota
+	// DescriptionConcat concatenates each distinct, non-empty description,
+	// in order, separated by DisplayOpts.Separator.
+	DescriptionConcat
+)
+
+// DisplayOpts configures Schema.DisplayMetadata.
+type DisplayOpts struct {
+	// Descriptions determines how descriptions are combined.
+	//
+	// Defaults to DescriptionFirst
+	Descriptions DescriptionPolicy
+	// Separator is placed between descriptions when Descriptions is
+	// DescriptionConcat.
+	//
+	// Defaults to "\n\n"
+	Separator string
+}
+
+// DisplayMetadata is the title and description with which a Schema should
+// be presented (e.g. by a documentation renderer).
+type DisplayMetadata struct {
+	Title       Text
+	Description Text
+}
+
+// DisplayMetadata computes the title and description of s, taking into
+// account the Schemas it inherits them from: the branches of its allOf and
+// the Schema referenced by its resolved $ref, recursively.
+//
+// Schemas are consulted depth-first, starting with s, then each branch of its
+// allOf, in order, and then the Schema it references. As a result, keywords
+// adjacent to an allOf or $ref take precedence over those of the Schemas
+// they inherit from. The title is the first non-empty title; descriptions are
+// combined according to opts.
+func (s *Schema) DisplayMetadata(opts ...DisplayOpts) DisplayMetadata {
+	var o DisplayOpts
+	for _, v := range opts {
+		o.Descriptions = v.Descriptions
+		if v.Separator != "" {
+			o.Separator = v.Separator
+		}
+	}
+	if o.Separator == "" {
+		o.Separator = "\n\n"
+	}
+
+	var md DisplayMetadata
+	sources := s.displaySources()
+	for _, src := range sources {
+		if src.Title != "" {
+			md.Title = src.Title
+			break
+		}
+	}
+	var descriptions []string
+	seen := map[Text]bool{}
+	for _, src := range sources {
+		if src.Description == "" || seen[src.Description] {
+			continue
+		}
+		seen[src.Description] = true
+		descriptions = append(descriptions, src.Description.String())
+		if o.Descriptions == DescriptionFirst {
+			break
+		}
+	}
+	md.Description = Text(strings.Join(descriptions, o.Separator))
+	return md
+}
+
+// displaySources returns s followed by the Schemas it inherits display
+// metadata from, in order of precedence. Each Schema is included once.
+func (s *Schema) displaySources() []*Schema {
+	var res []*Schema
+	seen := map[*Schema]bool{}
+	var visit func(s *Schema)
+	visit = func(s *Schema) {
+		if s == nil || seen[s] {
+			return
+		}
+		seen[s] = true
+		res = append(res, s)
+		if s.AllOf != nil {
+			for _, b := range s.AllOf.Items {
+				visit(b)
+			}
+		}
+		if s.Ref != nil {
+			visit(s.Ref.Resolved)
+		}
+	}
+	visit(s)
+	return res
+}
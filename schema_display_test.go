@@ -0,0 +1,39 @@
+package openapi_test
+
+import (
+	"testing"
+
+	"github.com/chanced/openapi"
+)
+
+func TestSchemaDisplayMetadata(t *testing.T) {
+	pet := &openapi.Schema{Title: "Pet", Description: "A pet."}
+	cat := &openapi.Schema{
+		Description: "A cat.",
+		AllOf: &openapi.SchemaSlice{Items: []*openapi.Schema{
+			{Ref: &openapi.SchemaRef{Resolved: pet}},
+			{Description: "Cats meow."},
+			{Description: "A cat."},
+		}},
+	}
+	ref := &openapi.Schema{Ref: &openapi.SchemaRef{Resolved: cat}}
+
+	md := ref.DisplayMetadata()
+	if md.Title != "Pet" {
+		t.Errorf("expected the title to be inherited, got %q", md.Title)
+	}
+	if md.Description != "A cat." {
+		t.Errorf("expected the first description, got %q", md.Description)
+	}
+
+	md = ref.DisplayMetadata(openapi.DisplayOpts{Descriptions: openapi.DescriptionConcat, Separator: " "})
+	if md.Description != "A cat. A pet. Cats meow." {
+		t.Errorf("expected the descriptions to be concatenated, got %q", md.Description)
+	}
+
+	pet.Ref = &openapi.SchemaRef{Resolved: ref}
+	cat.Title = "Cat"
+	if md = ref.DisplayMetadata(); md.Title != "Cat" {
+		t.Errorf("expected the nearest title with a cycle, got %q", md.Title)
+	}
+}
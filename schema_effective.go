@@ -0,0 +1,88 @@
+package openapi
+
+// effective returns s with its resolved $ref and allOf branches merged.
+func (s *Schema) effective() *Schema {
+	if s == nil {
+		return nil
+	}
+	return newSchemaMerger().branch(s)
+}
+
+// EffectiveTypes returns the types which s permits once its allOf branches
+// and resolved $ref have been accounted for. A nil result indicates that s
+// does not constrain the type.
+func (s *Schema) EffectiveTypes() Types {
+	e := s.effective()
+	if e == nil {
+		return nil
+	}
+	return e.Type
+}
+
+// IsNullable reports whether s permits null, either through its effective
+// type, an OpenAPI 3.0 "nullable" keyword, or an anyOf / oneOf branch which is
+// nullable. An enum which does not contain null prevents s from being
+// nullable, regardless of representation.
+//
+// See SetNullable.
+func (s *Schema) IsNullable() bool {
+	return s.isNullable(map[*Schema]bool{})
+}
+
+func (s *Schema) isNullable(seen map[*Schema]bool) bool {
+	if s == nil || seen[s] {
+		return false
+	}
+	seen[s] = true
+	e := s.effective()
+	if len(e.Enum) > 0 && !e.Enum.Contains(jsonNull) {
+		return false
+	}
+	if e.Type.ContainsNull() {
+		return true
+	}
+	if v, ok := e.Keywords[keywordNullable]; ok && jsonEqual(v, []byte("true")) {
+		return true
+	}
+	if e.Const != nil && jsonEqual(e.Const, []byte("null")) {
+		return true
+	}
+	for _, ss := range []*SchemaSlice{e.AnyOf, e.OneOf} {
+		if ss == nil {
+			continue
+		}
+		for _, b := range ss.Items {
+			if b.isNullable(seen) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// EffectiveRequired returns the union of the required properties of s, its
+// allOf branches, and its resolved $ref.
+func (s *Schema) EffectiveRequired() Texts {
+	e := s.effective()
+	if e == nil {
+		return nil
+	}
+	return e.Required
+}
+
+// EffectivePropertyNames returns the names of the properties defined by s, its
+// allOf branches, and its resolved $ref, in the order they are first
+// encountered.
+//
+// Note that PropertyNames is the propertyNames keyword of the Schema.
+func (s *Schema) EffectivePropertyNames() []Text {
+	e := s.effective()
+	if e == nil || e.Properties == nil {
+		return nil
+	}
+	names := make([]Text, len(e.Properties.Items))
+	for i, item := range e.Properties.Items {
+		names[i] = item.Key
+	}
+	return names
+}
@@ -0,0 +1,119 @@
+package openapi
+
+import (
+	"encoding/json"
+
+	"github.com/chanced/jsonx"
+)
+
+// maxExampleDepth limits the depth of generated examples, preventing
+// recursive schemas from expanding indefinitely
+const maxExampleDepth = 8
+
+// GenerateExample returns an example value of s, suitable for encoding as
+// JSON. The example, examples, default, const, and enum of s are preferred,
+// in that order, before a value is generated from its type (e.g. "string"
+// or a placeholder for the format, 0, true). References are followed, allOf
+// branches are merged, and the first branch of a oneOf or anyOf is used.
+//
+// nil is returned if s is nil or no example can be determined.
+func (s *Schema) GenerateExample() interface{} {
+	return schemaExample(s, 0)
+}
+
+func schemaExample(s *Schema, depth int) interface{} {
+	if s == nil || depth > maxExampleDepth {
+		return nil
+	}
+	if s.Ref != nil && s.Ref.Resolved != nil {
+		return schemaExample(s.Ref.Resolved, depth+1)
+	}
+	for _, raw := range [][]byte{s.Example, firstRaw(s.Examples), s.Default, s.Const, firstRaw(s.Enum)} {
+		if raw == nil {
+			continue
+		}
+		var v interface{}
+		if err := json.Unmarshal(raw, &v); err == nil {
+			return v
+		}
+	}
+	if s.AllOf != nil && len(s.AllOf.Items) > 0 {
+		if m, _ := s.MergeAllOf(); m != nil {
+			m.AllOf = nil
+			return schemaExample(m, depth+1)
+		}
+	}
+	for _, ss := range []*SchemaSlice{s.OneOf, s.AnyOf} {
+		if ss != nil && len(ss.Items) > 0 {
+			return schemaExample(ss.Items[0], depth+1)
+		}
+	}
+	var typ Type
+	for _, t := range s.Type {
+		if t != TypeNull {
+			typ = t
+			break
+		}
+	}
+	if typ == "" && s.Properties != nil {
+		typ = TypeObject
+	}
+	switch typ {
+	case TypeString:
+		return stringExample(s.Format)
+	case TypeInteger:
+		return 0
+	case TypeNumber:
+		return 0.0
+	case TypeBoolean:
+		return true
+	case TypeArray:
+		if v := schemaExample(s.Items, depth+1); v != nil {
+			return []interface{}{v}
+		}
+		return []interface{}{}
+	case TypeObject:
+		obj := map[string]interface{}{}
+		if s.Properties != nil {
+			for _, p := range s.Properties.Items {
+				if v := schemaExample(p.Schema, depth+1); v != nil {
+					obj[p.Key.String()] = v
+				}
+			}
+		}
+		return obj
+	}
+	return nil
+}
+
+func firstRaw(raws []jsonx.RawMessage) []byte {
+	if len(raws) == 0 {
+		return nil
+	}
+	return raws[0]
+}
+
+func stringExample(format Text) string {
+	switch format {
+	case "date-time":
+		return "2006-01-02T15:04:05Z"
+	case "date":
+		return "2006-01-02"
+	case "time":
+		return "15:04:05Z"
+	case "email":
+		return "user@example.com"
+	case "uri", "url":
+		return "https://example.com"
+	case "uuid":
+		return "00000000-0000-0000-0000-000000000000"
+	case "ipv4":
+		return "192.0.2.1"
+	case "ipv6":
+		return "2001:db8::1"
+	case "hostname":
+		return "example.com"
+	default:
+		return "string"
+	}
+}
@@ -0,0 +1,182 @@
+package openapi
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/chanced/jsonpointer"
+	"github.com/chanced/uri"
+)
+
+// SchemaDocument is a standalone JSON Schema document of a component Schema,
+// created with Document.ExportSchemas.
+type SchemaDocument struct {
+	// Name is the key of the Schema within the Document's components.
+	Name Text
+	// ID is the $id of the Schema.
+	ID uri.URI
+	// Schema is a copy of the component Schema with $id and $schema set and
+	// its references rewritten.
+	Schema *Schema
+}
+
+// ExportSchemas returns a standalone JSON Schema document for each Schema of
+// the Document's components, in order, for use by tooling which is not aware
+// of OpenAPI.
+//
+// The $schema of each document is set to dialect or, if dialect is empty, the
+// Document's jsonSchemaDialect, falling back to JSON Schema 2020-12. The $id
+// of each document is "<name>.json", resolved against the location of the
+// Document.
+//
+// References to other component schemas are rewritten relative to the $id of
+// the referenced document (e.g. "#/components/schemas/Pet" becomes
+// "Pet.json") and references to subschemas of the same component become
+// local (e.g. "#/properties/name"). References to schemas elsewhere are rewritten as
+// absolute URIs.
+func (d *Document) ExportSchemas(dialect uri.URI) ([]SchemaDocument, error) {
+	if d == nil || d.Components == nil || d.Components.Schemas == nil {
+		return nil, nil
+	}
+	if dialect == (uri.URI{}) {
+		dialect = JSONSchemaDialect202012
+		if d.JSONSchemaDialect != nil {
+			dialect = *d.JSONSchemaDialect
+		}
+	}
+	base := d.AbsoluteLocation()
+	base.Fragment = ""
+	base.RawFragment = ""
+
+	var res []SchemaDocument
+	for _, item := range d.Components.Schemas.Items {
+		if item.Schema == nil {
+			continue
+		}
+		id, err := schemaDocumentID(base, item.Key)
+		if err != nil {
+			return nil, err
+		}
+		s, err := exportSchema(base, item.Key, item.Schema)
+		if err != nil {
+			return nil, err
+		}
+		dia := dialect
+		s.Schema = &dia
+		s.ID = &id
+		res = append(res, SchemaDocument{Name: item.Key, ID: id, Schema: s})
+	}
+	return res, nil
+}
+
+// schemaDocumentID returns the $id of the SchemaDocument of the component
+// name
+func schemaDocumentID(base uri.URI, name Text) (uri.URI, error) {
+	ref, err := uri.Parse(url.PathEscape(name.String()) + ".json")
+	if err != nil {
+		return uri.URI{}, NewError(fmt.Errorf("openapi: invalid schema name %q: %w", name, err), base)
+	}
+	if base.String() == "" {
+		return *ref, nil
+	}
+	return *base.ResolveReference(ref), nil
+}
+
+// exportSchema returns a copy of the component schema s with its references
+// rewritten
+func exportSchema(base uri.URI, name Text, s *Schema) (*Schema, error) {
+	// the target of each reference, keyed by the location of the reference
+	targets := map[string]uri.URI{}
+	err := walkLocalNodes(s, func(n node) error {
+		sr, ok := n.(*SchemaRef)
+		if !ok || sr.Ref == nil {
+			return nil
+		}
+		if sr.Resolved != nil {
+			targets[sr.AbsoluteLocation().String()] = sr.Resolved.AbsoluteLocation()
+			return nil
+		}
+		from := sr.AbsoluteLocation()
+		if from.String() == "" {
+			targets[sr.AbsoluteLocation().String()] = *sr.Ref
+		} else {
+			targets[sr.AbsoluteLocation().String()] = *from.ResolveReference(sr.Ref)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	data, err := s.MarshalJSON()
+	if err != nil {
+		return nil, NewError(fmt.Errorf("openapi: failed to marshal schema %q: %w", name, err), s.AbsoluteLocation())
+	}
+	var c Schema
+	if err = c.UnmarshalJSON(data); err != nil {
+		return nil, NewError(fmt.Errorf("openapi: failed to unmarshal schema %q: %w", name, err), s.AbsoluteLocation())
+	}
+	if err = c.setLocation(s.location()); err != nil {
+		return nil, NewError(err, s.AbsoluteLocation())
+	}
+	err = walkLocalNodes(&c, func(n node) error {
+		sr, ok := n.(*SchemaRef)
+		if !ok || sr.Ref == nil {
+			return nil
+		}
+		target, ok := targets[sr.AbsoluteLocation().String()]
+		if !ok {
+			return nil
+		}
+		u, err := exportedRef(base, name, target)
+		if err != nil {
+			return NewError(err, sr.AbsoluteLocation())
+		}
+		if u != nil {
+			sr.Ref = u
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// exportedRef returns the rewritten reference to target from the
+// SchemaDocument of the component name. A nil result indicates that the
+// reference should be left as is.
+func exportedRef(base uri.URI, name Text, target uri.URI) (*uri.URI, error) {
+	resource := target
+	resource.Fragment = ""
+	resource.RawFragment = ""
+	if resource.String() != base.String() {
+		return &target, nil
+	}
+	ptr, err := jsonpointer.Parse(target.Fragment)
+	if err != nil {
+		// a reference to an anchor
+		if base.String() == "" {
+			return nil, nil
+		}
+		return &target, nil
+	}
+	tokens := ptr.Tokens()
+	if len(tokens) > 0 && tokens[0] == "" {
+		tokens = tokens[1:]
+	}
+	if len(tokens) < 3 || tokens[0] != "components" || tokens[1] != "schemas" {
+		if base.String() == "" {
+			return nil, nil
+		}
+		return &target, nil
+	}
+	rest := jsonpointer.NewFromStrings(tokens[3:]).String()
+	var ref string
+	if Text(tokens[2]) != name || rest == "" {
+		ref = url.PathEscape(tokens[2]) + ".json"
+	}
+	if rest != "" {
+		ref += "#" + rest
+	}
+	return uri.Parse(ref)
+}
@@ -0,0 +1,97 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/chanced/openapi"
+	"github.com/chanced/uri"
+)
+
+func TestDocumentExportSchemas(t *testing.T) {
+	data := []byte(`{
+		"openapi": "3.1.0",
+		"info": { "title": "export", "version": "1.0.0" },
+		"paths": {},
+		"components": {
+			"schemas": {
+				"Pet": {
+					"type": "object",
+					"properties": {
+						"name": { "type": "string" },
+						"owner": { "$ref": "#/components/schemas/Owner" },
+						"alias": { "$ref": "#/components/schemas/Pet/properties/name" },
+						"friends": { "type": "array", "items": { "$ref": "#/components/schemas/Pet" } }
+					}
+				},
+				"Owner": {
+					"type": "object",
+					"properties": {
+						"pets": { "type": "array", "items": { "$ref": "#/components/schemas/Pet" } }
+					}
+				}
+			}
+		}
+	}`)
+	doc := loadDocument(t, "https://example.com/api/openapi.json", data)
+	schemas, err := doc.ExportSchemas(uri.URI{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(schemas) != 2 {
+		t.Fatalf("expected 2 schemas, got %d", len(schemas))
+	}
+	pet := schemas[0]
+	if pet.Name != "Pet" {
+		t.Errorf("expected Pet, got %s", pet.Name)
+	}
+	if pet.ID.String() != "https://example.com/api/Pet.json" {
+		t.Errorf("expected $id of https://example.com/api/Pet.json, got %s", pet.ID.String())
+	}
+	b, err := json.Marshal(pet.Schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var res struct {
+		Schema     string `json:"$schema"`
+		ID         string `json:"$id"`
+		Properties map[string]struct {
+			Ref   string `json:"$ref"`
+			Items struct {
+				Ref string `json:"$ref"`
+			} `json:"items"`
+		} `json:"properties"`
+	}
+	if err = json.Unmarshal(b, &res); err != nil {
+		t.Fatal(err)
+	}
+	if res.Schema != openapi.JSON_SCHEMA_2020_12 {
+		t.Errorf("expected $schema of %s, got %s", openapi.JSON_SCHEMA_2020_12, res.Schema)
+	}
+	if res.ID != "https://example.com/api/Pet.json" {
+		t.Errorf("expected $id of https://example.com/api/Pet.json, got %s", res.ID)
+	}
+	if ref := res.Properties["owner"].Ref; ref != "Owner.json" {
+		t.Errorf("expected owner $ref of Owner.json, got %s", ref)
+	}
+	if ref := res.Properties["alias"].Ref; ref != "#/properties/name" {
+		t.Errorf("expected alias $ref of #/properties/name, got %s", ref)
+	}
+	if ref := res.Properties["friends"].Items.Ref; ref != "Pet.json" {
+		t.Errorf("expected friends $ref of Pet.json, got %s", ref)
+	}
+
+	// an explicit dialect takes precedence
+	schemas, err = doc.ExportSchemas(openapi.JSONSchemaDialect201909)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := schemas[0].Schema.Schema; s == nil || *s != openapi.JSONSchemaDialect201909 {
+		t.Errorf("expected $schema of %s, got %v", openapi.JSONSchemaDialect201909.String(), s)
+	}
+
+	// the Document should not be modified
+	if ref := doc.Components.Schemas.Get("Pet").Properties.Get("owner").Ref.Ref.String(); ref != "#/components/schemas/Owner" {
+		t.Errorf("expected the $ref of the Document to be unchanged, got %s", ref)
+	}
+}
@@ -0,0 +1,892 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"regexp/syntax"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// FormatGenerator returns a pseudo-random value for the format of s. The
+// value is encoded as JSON, so any value which encoding/json can marshal may
+// be returned.
+type FormatGenerator func(r *rand.Rand, s *Schema) interface{}
+
+// GenerateOpts configures an InstanceGenerator.
+type GenerateOpts struct {
+	// Seed of the pseudo-random source. InstanceGenerators with the same Seed
+	// produce the same instances when given the same sequence of Schemas.
+	Seed int64
+
+	// MaxDepth is the depth of nested objects and arrays beyond which
+	// optional properties are omitted and arrays have as few items as
+	// permitted, bounding the size of instances of recursive Schemas.
+	//
+	// Defaults to 4.
+	MaxDepth int
+
+	// MaxItems is the greatest number of items of arrays which do not
+	// specify maxItems, beyond minItems.
+	//
+	// Defaults to 5.
+	MaxItems int
+
+	// MaxLength is the greatest length of strings which do not specify
+	// maxLength, beyond minLength, and the greatest number of repetitions of
+	// unbounded quantifiers in patterns (e.g. "a+").
+	//
+	// Defaults to 16.
+	MaxLength int
+
+	// Attempts is the number of instances generated for a Schema before
+	// giving up on finding one which is valid (or invalid).
+	//
+	// Defaults to 32.
+	Attempts int
+
+	// Formats are used to generate values of Schemas with the keyed format,
+	// in place of those built in for date-time, date, time, email, uri, url,
+	// uuid, ipv4, ipv6, and hostname. They may be used to bias the
+	// distribution of values (e.g. toward edge cases such as leap days) or
+	// to generate values of custom formats.
+	Formats map[Text]FormatGenerator
+}
+
+func (opts GenerateOpts) maxDepth() int {
+	if opts.MaxDepth <= 0 {
+		return 4
+	}
+	return opts.MaxDepth
+}
+
+func (opts GenerateOpts) maxItems() int {
+	if opts.MaxItems <= 0 {
+		return 5
+	}
+	return opts.MaxItems
+}
+
+func (opts GenerateOpts) maxLength() int {
+	if opts.MaxLength <= 0 {
+		return 16
+	}
+	return opts.MaxLength
+}
+
+func (opts GenerateOpts) attempts() int {
+	if opts.Attempts <= 0 {
+		return 32
+	}
+	return opts.Attempts
+}
+
+// InstanceGenerator produces pseudo-random instances of Schemas, valid or
+// deliberately invalid, for property-based and fuzz testing. As the source
+// is seeded, the instances which exposed a failure can be reproduced.
+//
+// Instances are values as decoded by encoding/json with
+// json.Decoder.UseNumber: nil, bool, json.Number, string, []interface{}, and
+// map[string]interface{}.
+//
+// An InstanceGenerator is not safe for concurrent use.
+type InstanceGenerator struct {
+	opts GenerateOpts
+	rand *rand.Rand
+}
+
+// NewInstanceGenerator returns an InstanceGenerator configured with opts.
+func NewInstanceGenerator(opts GenerateOpts) *InstanceGenerator {
+	return &InstanceGenerator{
+		opts: opts,
+		rand: rand.New(rand.NewSource(opts.Seed)),
+	}
+}
+
+// Generate returns a pseudo-random instance which is valid according to s.
+//
+// Resolved references are followed, allOf branches are merged, and a branch
+// of each anyOf and oneOf is chosen at random. Each instance is checked
+// against s before it is returned; instances are regenerated up to
+// GenerateOpts.Attempts times. As with Schema.IsSubschemaOf, format and the
+// content keywords are treated as annotations.
+//
+// If s uses keywords which can not be checked, such as unevaluatedProperties
+// or unresolved references, an instance which is not shown to be invalid is
+// returned in the absence of one shown to be valid. An error wrapping
+// ErrInstanceNotGenerated is returned if no such instance is found.
+func (g *InstanceGenerator) Generate(s *Schema) (interface{}, error) {
+	if s.IsNever() {
+		return nil, fmt.Errorf("%w: schema is false", ErrInstanceNotGenerated)
+	}
+	var fallback interface{}
+	found := false
+	for i := 0; i < g.opts.attempts(); i++ {
+		v := g.generate(s, 0)
+		switch permits(s, v, 0) {
+		case DecisionYes:
+			return v, nil
+		case DecisionUnknown:
+			if !found {
+				fallback, found = v, true
+			}
+		}
+	}
+	if found {
+		return fallback, nil
+	}
+	if d, _ := s.IsSatisfiable(); d == DecisionNo {
+		return nil, fmt.Errorf("%w: schema can not be satisfied", ErrInstanceNotGenerated)
+	}
+	return nil, fmt.Errorf("%w: no valid instance was found in %d attempts", ErrInstanceNotGenerated, g.opts.attempts())
+}
+
+// GenerateInvalid returns a pseudo-random instance which is invalid according
+// to s, such as a value of the wrong type, a number outside of the permitted
+// range, an object missing a required property, or an otherwise valid
+// instance with an invalid property or item.
+//
+// An error wrapping ErrInstanceNotGenerated is returned if s permits every
+// value or no instance shown to be invalid is found.
+func (g *InstanceGenerator) GenerateInvalid(s *Schema) (interface{}, error) {
+	if s == nil || s.IsAlways() {
+		return nil, fmt.Errorf("%w: schema permits every value", ErrInstanceNotGenerated)
+	}
+	for i := 0; i < g.opts.attempts(); i++ {
+		candidates := g.invalid(s, 0)
+		g.rand.Shuffle(len(candidates), func(i, j int) {
+			candidates[i], candidates[j] = candidates[j], candidates[i]
+		})
+		for _, v := range candidates {
+			if permits(s, v, 0) == DecisionNo {
+				return v, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("%w: no invalid instance was found in %d attempts", ErrInstanceNotGenerated, g.opts.attempts())
+}
+
+// generateValid returns an instance of s, regenerating it a few times if it
+// is shown to be invalid and depth is within GenerateOpts.MaxDepth
+func (g *InstanceGenerator) generateValid(s *Schema, depth int) interface{} {
+	var v interface{}
+	for i := 0; i < 4; i++ {
+		v = g.generate(s, depth)
+		if depth >= g.opts.maxDepth() || permits(s, v, 0) != DecisionNo {
+			return v
+		}
+	}
+	return v
+}
+
+func (g *InstanceGenerator) generate(s *Schema, depth int) interface{} {
+	// required properties and minItems of recursive Schemas may otherwise
+	// nest indefinitely
+	if depth > 4*g.opts.maxDepth() || s.IsNever() {
+		return nil
+	}
+	if s == nil || s.IsAlways() {
+		return g.anyValue()
+	}
+	merged, _, ok := mergeEffective(s)
+	if !ok || merged.IsNever() {
+		return nil
+	}
+	merged = g.chooseBranches(merged)
+
+	if merged.Const != nil {
+		v, _ := decodeInstance(merged.Const)
+		return v
+	}
+	if len(merged.Enum) > 0 {
+		var values []interface{}
+		for _, data := range merged.Enum {
+			if v, ok := decodeInstance(data); ok && permits(merged, v, 0) != DecisionNo {
+				values = append(values, v)
+			}
+		}
+		if len(values) == 0 {
+			v, _ := decodeInstance(merged.Enum[g.rand.Intn(len(merged.Enum))])
+			return v
+		}
+		return values[g.rand.Intn(len(values))]
+	}
+	if fn, ok := g.opts.Formats[merged.Format]; ok && merged.Format != "" {
+		data, err := json.Marshal(fn(g.rand, merged))
+		if err != nil {
+			return nil
+		}
+		v, _ := decodeInstance(data)
+		return v
+	}
+
+	switch g.chooseType(merged) {
+	case TypeNull:
+		return nil
+	case TypeBoolean:
+		return g.rand.Intn(2) == 0
+	case TypeInteger:
+		return g.number(merged, true)
+	case TypeNumber:
+		return g.number(merged, false)
+	case TypeString:
+		return g.string(merged)
+	case TypeObject:
+		return g.object(merged, depth)
+	case TypeArray:
+		return g.array(merged, depth)
+	default:
+		return nil
+	}
+}
+
+// chooseBranches returns the effective Schema s with each anyOf and oneOf
+// replaced by a branch chosen at random
+func (g *InstanceGenerator) chooseBranches(s *Schema) *Schema {
+	for i := 0; i < maxEffectiveDepth; i++ {
+		rest := s.shallowClone()
+		var branches []*Schema
+		switch {
+		case s.AnyOf != nil && len(s.AnyOf.Items) > 0:
+			branches, rest.AnyOf = s.AnyOf.Items, nil
+		case s.OneOf != nil && len(s.OneOf.Items) > 0:
+			branches, rest.OneOf = s.OneOf.Items, nil
+		default:
+			return s
+		}
+		var possible []*Schema
+		for _, b := range branches {
+			if !b.IsNever() {
+				possible = append(possible, b)
+			}
+		}
+		if len(possible) == 0 {
+			return NewBoolSchema(false)
+		}
+		b := possible[g.rand.Intn(len(possible))]
+		if res, ok := IntersectSchemas(rest, b); ok {
+			s = res
+			continue
+		}
+		res, _, ok := mergeEffective(&Schema{
+			Location: s.Location,
+			AllOf:    &SchemaSlice{Location: s.Location, Items: []*Schema{rest, b}},
+		})
+		if !ok {
+			return rest
+		}
+		s = res
+	}
+	return s
+}
+
+// chooseType returns a Type permitted by the effective Schema s, chosen at
+// random. If s does not specify type, the types are implied by the keywords
+// specific to them.
+func (g *InstanceGenerator) chooseType(s *Schema) Type {
+	types := schemaTypes(s)
+	if types == nil {
+		types = impliedTypes(s)
+	}
+	if len(types) == 0 {
+		return ""
+	}
+	return types[g.rand.Intn(len(types))]
+}
+
+// impliedTypes returns the types to which the keywords of s apply, or each
+// Type if s has no keywords specific to a type
+func impliedTypes(s *Schema) Types {
+	var types Types
+	if s.Properties != nil || s.PatternProperties != nil || s.AdditionalProperties != nil ||
+		len(s.Required) > 0 || s.MinProperties != nil || s.MaxProperties != nil || s.PropertyNames != nil {
+		types = append(types, TypeObject)
+	}
+	_, minItems := s.keywordNumber("minItems")
+	_, maxItems := s.keywordNumber("maxItems")
+	if s.Items != nil || s.PrefixItems != nil || s.Contains != nil || minItems || maxItems {
+		types = append(types, TypeArray)
+	}
+	if s.MinLength != nil || s.MaxLength != nil || !s.Pattern.IsNil() {
+		types = append(types, TypeString)
+	}
+	if s.Minimum != nil || s.ExclusiveMinimum != nil || s.Maximum != nil || s.ExclusiveMaximum != nil || s.MultipleOf != nil {
+		types = append(types, TypeNumber)
+	}
+	if len(types) == 0 {
+		return Types{TypeNull, TypeBoolean, TypeInteger, TypeNumber, TypeString, TypeObject, TypeArray}
+	}
+	return types
+}
+
+// anyValue returns a scalar chosen at random
+func (g *InstanceGenerator) anyValue() interface{} {
+	switch g.rand.Intn(4) {
+	case 0:
+		return nil
+	case 1:
+		return g.rand.Intn(2) == 0
+	case 2:
+		return json.Number(strconv.Itoa(g.rand.Intn(2001) - 1000))
+	default:
+		return g.letters(g.rand.Intn(g.opts.maxLength() + 1))
+	}
+}
+
+// numberSpread is the distance, in multiples of the step or in units, which
+// unbounded numbers extend from their bound or from zero
+const numberSpread = 1000
+
+func (g *InstanceGenerator) number(s *Schema, integer bool) json.Number {
+	r, ok := newNumberRange(s, integer)
+	if !ok {
+		return "0"
+	}
+	if r.step != nil {
+		min, max := r.multiples()
+		spread := big.NewInt(numberSpread)
+		switch {
+		case min == nil && max == nil:
+			min, max = new(big.Int).Neg(spread), spread
+		case min == nil:
+			min = new(big.Int).Sub(max, spread)
+		case max == nil:
+			max = new(big.Int).Add(min, spread)
+		}
+		var k *big.Int
+		switch {
+		case min.Cmp(max) > 0:
+			k = min
+		case g.rand.Intn(4) == 0:
+			// bias toward the bounds
+			k = min
+			if g.rand.Intn(2) == 0 {
+				k = max
+			}
+		default:
+			n := new(big.Int).Sub(max, min)
+			k = new(big.Int).Add(min, n.Rand(g.rand, n.Add(n, big.NewInt(1))))
+		}
+		return ratNumber(new(big.Rat).Mul(new(big.Rat).SetInt(k), r.step))
+	}
+
+	lo, hi := r.lo, r.hi
+	spread := big.NewRat(numberSpread, 1)
+	switch {
+	case lo == nil && hi == nil:
+		lo, hi = new(big.Rat).Neg(spread), spread
+	case lo == nil:
+		lo = new(big.Rat).Sub(hi, spread)
+	case hi == nil:
+		hi = new(big.Rat).Add(lo, spread)
+	}
+	if g.rand.Intn(4) == 0 {
+		// bias toward the bounds
+		if r.lo != nil && !r.loExcl && (r.hi == nil || g.rand.Intn(2) == 0) {
+			return ratNumber(r.lo)
+		}
+		if r.hi != nil && !r.hiExcl {
+			return ratNumber(r.hi)
+		}
+	}
+	const scale = 1000000
+	u := big.NewRat(g.rand.Int63n(scale-1)+1, scale)
+	v := new(big.Rat).Sub(hi, lo)
+	v.Mul(v, u).Add(v, lo)
+	return ratNumber(v)
+}
+
+func (g *InstanceGenerator) string(s *Schema) string {
+	if fn, ok := formatGenerators[s.Format]; ok {
+		return fn(g.rand)
+	}
+	min, max := 0, -1
+	if s.MinLength != nil {
+		if n, err := s.MinLength.Int64(); err == nil && n <= maxWitnessLength {
+			min = int(n)
+		}
+	}
+	if s.MaxLength != nil {
+		if n, err := s.MaxLength.Int64(); err == nil && n < int64(min+g.opts.maxLength()) {
+			max = int(n)
+		}
+	}
+	if max < 0 {
+		max = min + g.opts.maxLength()
+	}
+
+	if !s.Pattern.IsNil() && s.Pattern.IsCompiled() {
+		if re, err := syntax.Parse(s.Pattern.Regexp.String(), syntax.Perl); err == nil {
+			var res string
+			for i := 0; i < 8; i++ {
+				var sb strings.Builder
+				g.pattern(&sb, re, min+g.opts.maxLength())
+				res = sb.String()
+				if n := utf8.RuneCountInString(res); n >= min && n <= max {
+					break
+				}
+			}
+			return res
+		}
+	}
+	if max < min {
+		max = min
+	}
+	return g.letters(min + g.rand.Intn(max-min+1))
+}
+
+const generatedLetters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+func (g *InstanceGenerator) letters(n int) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = generatedLetters[g.rand.Intn(len(generatedLetters))]
+	}
+	return string(b)
+}
+
+// pattern writes a string matching re to sb. Quantifiers without an upper
+// bound repeat at most maxRepeat times beyond their lower bound.
+func (g *InstanceGenerator) pattern(sb *strings.Builder, re *syntax.Regexp, maxRepeat int) {
+	switch re.Op {
+	case syntax.OpLiteral:
+		for _, r := range re.Rune {
+			if re.Flags&syntax.FoldCase != 0 && g.rand.Intn(2) == 0 {
+				r = unicode.SimpleFold(r)
+			}
+			sb.WriteRune(r)
+		}
+	case syntax.OpCharClass:
+		sb.WriteRune(g.classRune(re.Rune))
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		sb.WriteByte(generatedLetters[g.rand.Intn(len(generatedLetters))])
+	case syntax.OpCapture:
+		g.pattern(sb, re.Sub[0], maxRepeat)
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		min, max := 0, maxRepeat
+		switch re.Op {
+		case syntax.OpPlus:
+			min, max = 1, 1+maxRepeat
+		case syntax.OpQuest:
+			max = 1
+		case syntax.OpRepeat:
+			min, max = re.Min, re.Max
+			if max < 0 {
+				max = min + maxRepeat
+			}
+		}
+		for n := min + g.rand.Intn(max-min+1); n > 0; n-- {
+			g.pattern(sb, re.Sub[0], maxRepeat)
+		}
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			g.pattern(sb, sub, maxRepeat)
+		}
+	case syntax.OpAlternate:
+		g.pattern(sb, re.Sub[g.rand.Intn(len(re.Sub))], maxRepeat)
+	}
+}
+
+// classRune returns a rune of the character class ranges, preferring
+// printable ASCII
+func (g *InstanceGenerator) classRune(ranges []rune) rune {
+	var printable []rune
+	for i := 0; i+1 < len(ranges); i += 2 {
+		lo, hi := ranges[i], ranges[i+1]
+		if lo < 0x20 {
+			lo = 0x20
+		}
+		if hi > 0x7e {
+			hi = 0x7e
+		}
+		if lo <= hi {
+			printable = append(printable, lo, hi)
+		}
+	}
+	if len(printable) > 0 {
+		ranges = printable
+	}
+	if len(ranges) < 2 {
+		return 'a'
+	}
+	i := g.rand.Intn(len(ranges)/2) * 2
+	lo, hi := ranges[i], ranges[i+1]
+	r := lo + rune(g.rand.Int63n(int64(hi-lo)+1))
+	if !utf8.ValidRune(r) {
+		return lo
+	}
+	return r
+}
+
+// formatGenerators are the built-in generators of string formats
+var formatGenerators = map[Text]func(r *rand.Rand) string{
+	"date-time": func(r *rand.Rand) string {
+		return randomTime(r).Format(time.RFC3339)
+	},
+	"date": func(r *rand.Rand) string {
+		return randomTime(r).Format("2006-01-02")
+	},
+	"time": func(r *rand.Rand) string {
+		return randomTime(r).Format("15:04:05Z")
+	},
+	"email": func(r *rand.Rand) string {
+		return randomLabel(r) + "@example.com"
+	},
+	"uri": func(r *rand.Rand) string {
+		return "https://example.com/" + randomLabel(r)
+	},
+	"url": func(r *rand.Rand) string {
+		return "https://example.com/" + randomLabel(r)
+	},
+	"uuid": func(r *rand.Rand) string {
+		return fmt.Sprintf("%08x-%04x-4%03x-%04x-%012x",
+			r.Uint32(), r.Intn(1<<16), r.Intn(1<<12), 0x8000|r.Intn(1<<14), r.Int63n(1<<48))
+	},
+	"ipv4": func(r *rand.Rand) string {
+		return fmt.Sprintf("%d.%d.%d.%d", r.Intn(256), r.Intn(256), r.Intn(256), r.Intn(256))
+	},
+	"ipv6": func(r *rand.Rand) string {
+		groups := make([]string, 8)
+		for i := range groups {
+			groups[i] = strconv.FormatInt(int64(r.Intn(1<<16)), 16)
+		}
+		return strings.Join(groups, ":")
+	},
+	"hostname": func(r *rand.Rand) string {
+		return randomLabel(r) + ".example.com"
+	},
+}
+
+func randomTime(r *rand.Rand) time.Time {
+	// between 1970 and 2100
+	return time.Unix(r.Int63n(4102444800), 0).UTC()
+}
+
+func randomLabel(r *rand.Rand) string {
+	b := make([]byte, 1+r.Intn(8))
+	for i := range b {
+		b[i] = byte('a' + r.Intn(26))
+	}
+	return string(b)
+}
+
+func (g *InstanceGenerator) object(s *Schema, depth int) map[string]interface{} {
+	obj := map[string]interface{}{}
+	max := -1
+	if s.MaxProperties != nil {
+		if n, err := s.MaxProperties.Int64(); err == nil && n < maxWitnessLength {
+			max = int(n)
+		}
+	}
+	min := 0
+	if s.MinProperties != nil {
+		if n, err := s.MinProperties.Int64(); err == nil && n < maxWitnessLength {
+			min = int(n)
+		}
+	}
+	set := func(name string) {
+		obj[name] = g.generateValid(propertySchema(s, name), depth+1)
+	}
+	for _, r := range s.Required {
+		set(r.String())
+	}
+	if s.Properties != nil {
+		for _, item := range s.Properties.Items {
+			name := item.Key.String()
+			if _, ok := obj[name]; ok || item.Schema.IsNever() || (max >= 0 && len(obj) >= max) {
+				continue
+			}
+			if len(obj) < min || (depth < g.opts.maxDepth() && g.rand.Intn(2) == 0) {
+				set(name)
+			}
+		}
+	}
+	for i := 0; len(obj) < min && i < min+g.opts.attempts(); i++ {
+		if name, ok := g.propertyName(s, depth); ok {
+			if _, exists := obj[name]; !exists {
+				set(name)
+			}
+		}
+	}
+	if s.DependentRequired != nil {
+		for _, kv := range s.DependentRequired.Items {
+			if _, ok := obj[kv.Key.String()]; !ok {
+				continue
+			}
+			for _, r := range kv.Value {
+				if _, ok := obj[r.String()]; !ok {
+					set(r.String())
+				}
+			}
+		}
+	}
+	return obj
+}
+
+// propertySchema returns the Schema which applies to the property name of
+// the effective Schema s, or nil if any value is permitted
+func propertySchema(s *Schema, name string) *Schema {
+	var applicable []*Schema
+	if s.Properties != nil {
+		if ps := s.Properties.Get(Text(name)); ps != nil {
+			applicable = append(applicable, ps)
+		}
+	}
+	if s.PatternProperties != nil {
+		for _, item := range s.PatternProperties.Items {
+			re, err := CompilePattern(item.Key.String(), PatternLenient)
+			if err == nil && re.IsCompiled() && re.MatchString(name) {
+				applicable = append(applicable, item.Schema)
+			}
+		}
+	}
+	switch len(applicable) {
+	case 0:
+		return s.AdditionalProperties
+	case 1:
+		return applicable[0]
+	default:
+		return &Schema{Location: s.Location, AllOf: &SchemaSlice{Location: s.Location, Items: applicable}}
+	}
+}
+
+// propertyName returns the name of a property, other than those of
+// properties, which may be added to an object permitted by s
+func (g *InstanceGenerator) propertyName(s *Schema, depth int) (string, bool) {
+	if s.PatternProperties != nil && len(s.PatternProperties.Items) > 0 && (s.AdditionalProperties.IsNever() || g.rand.Intn(2) == 0) {
+		item := s.PatternProperties.Items[g.rand.Intn(len(s.PatternProperties.Items))]
+		re, err := CompilePattern(item.Key.String(), PatternLenient)
+		if err != nil || !re.IsCompiled() {
+			return "", false
+		}
+		sre, err := syntax.Parse(re.Regexp.String(), syntax.Perl)
+		if err != nil {
+			return "", false
+		}
+		var sb strings.Builder
+		g.pattern(&sb, sre, g.opts.maxLength())
+		// patterns need not be anchored, in which case a suffix makes the
+		// name distinct
+		if name := sb.String() + g.letters(1+g.rand.Intn(8)); re.MatchString(name) {
+			return name, true
+		}
+		return sb.String(), true
+	}
+	if s.AdditionalProperties.IsNever() {
+		return "", false
+	}
+	if s.PropertyNames != nil {
+		name, ok := g.generate(s.PropertyNames, depth+1).(string)
+		return name, ok
+	}
+	return g.letters(1 + g.rand.Intn(g.opts.maxLength())), true
+}
+
+func (g *InstanceGenerator) array(s *Schema, depth int) []interface{} {
+	var prefix []*Schema
+	if s.PrefixItems != nil {
+		prefix = s.PrefixItems.Items
+	}
+	min, max := 0, -1
+	if n, ok := s.keywordNumber("minItems"); ok {
+		if v, err := n.Int64(); err == nil && v <= maxWitnessLength {
+			min = int(v)
+		}
+	}
+	if n, ok := s.keywordNumber("maxItems"); ok {
+		if v, err := n.Int64(); err == nil && v < int64(min+g.opts.maxItems()) {
+			max = int(v)
+		}
+	}
+	if max < 0 {
+		max = min + g.opts.maxItems()
+	}
+	if s.Items.IsNever() && max > len(prefix) {
+		max = len(prefix)
+	}
+	n := min
+	if depth < g.opts.maxDepth() && max > min {
+		n += g.rand.Intn(max - min + 1)
+	}
+
+	unique := s.UniqueItems != nil && *s.UniqueItems
+	seen := map[string]bool{}
+	arr := make([]interface{}, 0, n)
+	add := func(is *Schema) bool {
+		for i := 0; i < g.opts.attempts(); i++ {
+			v := g.generateValid(is, depth+1)
+			cv := canonicalInstance(v)
+			if !unique || !seen[cv] {
+				seen[cv] = true
+				arr = append(arr, v)
+				return true
+			}
+		}
+		return false
+	}
+	itemSchema := func(i int) *Schema {
+		if i < len(prefix) {
+			return prefix[i]
+		}
+		return s.Items
+	}
+	for i := 0; i < n; i++ {
+		if !add(itemSchema(i)) {
+			break
+		}
+	}
+	if s.Contains != nil {
+		need := 1
+		if s.MinContains != nil {
+			if v, err := s.MinContains.Int64(); err == nil && v <= maxWitnessLength {
+				need = int(v)
+			}
+		}
+		for _, v := range arr {
+			if permits(s.Contains, v, 0) == DecisionYes {
+				need--
+			}
+		}
+		for ; need > 0 && len(arr) < max; need-- {
+			is := itemSchema(len(arr))
+			both := &Schema{Location: s.Location, AllOf: &SchemaSlice{Location: s.Location, Items: []*Schema{s.Contains}}}
+			if is != nil {
+				both.AllOf.Items = append(both.AllOf.Items, is)
+			}
+			if !add(both) {
+				break
+			}
+		}
+	}
+	return arr
+}
+
+// invalid returns candidates for instances which are invalid according to
+// s. Not every candidate is necessarily invalid.
+func (g *InstanceGenerator) invalid(s *Schema, depth int) []interface{} {
+	merged, _, ok := mergeEffective(s)
+	if !ok || merged.IsNever() {
+		merged = s
+	}
+	var res []interface{}
+	add := func(vs ...interface{}) {
+		res = append(res, vs...)
+	}
+	// values of each type
+	add(nil, g.rand.Intn(2) == 0, json.Number(strconv.Itoa(g.rand.Intn(2001)-1000)), json.Number("0.5"),
+		g.letters(1+g.rand.Intn(g.opts.maxLength())), map[string]interface{}{}, []interface{}{})
+	if merged.Const != nil || len(merged.Enum) > 0 {
+		add("invalid-"+g.letters(8), json.Number(strconv.Itoa(g.rand.Intn(1<<30))))
+	}
+	if r, ok := newNumberRange(merged, false); ok {
+		one := big.NewRat(1, 1)
+		if r.lo != nil {
+			add(ratNumber(new(big.Rat).Sub(r.lo, one)))
+			if r.loExcl {
+				add(ratNumber(r.lo))
+			}
+		}
+		if r.hi != nil {
+			add(ratNumber(new(big.Rat).Add(r.hi, one)))
+			if r.hiExcl {
+				add(ratNumber(r.hi))
+			}
+		}
+		if r.step != nil {
+			add(ratNumber(new(big.Rat).Quo(r.step, big.NewRat(2, 1))))
+		}
+	}
+	if merged.MinLength != nil {
+		if n, err := merged.MinLength.Int64(); err == nil && n > 0 && n <= maxWitnessLength {
+			add(g.letters(int(n) - 1))
+		}
+	}
+	if merged.MaxLength != nil {
+		if n, err := merged.MaxLength.Int64(); err == nil && n < maxWitnessLength {
+			add(g.letters(int(n) + 1))
+		}
+	}
+	if !merged.Pattern.IsNil() {
+		add("", " "+g.letters(1+g.rand.Intn(g.opts.maxLength())), "!")
+	}
+
+	if depth >= g.opts.maxDepth() {
+		return res
+	}
+	switch v := g.generateValid(merged, depth).(type) {
+	case map[string]interface{}:
+		for _, r := range merged.Required {
+			c := copyObject(v)
+			delete(c, r.String())
+			add(c)
+		}
+		c := copyObject(v)
+		c["invalid-"+g.letters(8)] = g.anyValue()
+		add(c)
+		if merged.MaxProperties != nil {
+			if n, err := merged.MaxProperties.Int64(); err == nil && n < maxWitnessLength {
+				c := copyObject(v)
+				for i := 0; int64(len(c)) <= n; i++ {
+					c["invalid-"+strconv.Itoa(i)] = g.anyValue()
+				}
+				add(c)
+			}
+		}
+		if len(v) > 0 {
+			keys := make([]string, 0, len(v))
+			for k := range v {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			k := keys[g.rand.Intn(len(keys))]
+			for _, x := range g.invalid(propertySchema(merged, k), depth+1) {
+				c := copyObject(v)
+				c[k] = x
+				add(c)
+			}
+		}
+	case []interface{}:
+		if n, ok := merged.keywordNumber("minItems"); ok {
+			if min, err := n.Int64(); err == nil && min > 0 && int(min) <= len(v) {
+				add(append([]interface{}{}, v[:min-1]...))
+			}
+		}
+		if n, ok := merged.keywordNumber("maxItems"); ok {
+			if max, err := n.Int64(); err == nil && max < maxWitnessLength {
+				c := append([]interface{}{}, v...)
+				for int64(len(c)) <= max {
+					c = append(c, g.generate(merged.Items, depth+1))
+				}
+				add(c)
+			}
+		}
+		if len(v) > 0 {
+			add(append(append([]interface{}{}, v...), v[0]))
+			i := g.rand.Intn(len(v))
+			is := merged.Items
+			if merged.PrefixItems != nil && i < len(merged.PrefixItems.Items) {
+				is = merged.PrefixItems.Items[i]
+			}
+			for _, x := range g.invalid(is, depth+1) {
+				c := append([]interface{}{}, v...)
+				c[i] = x
+				add(c)
+			}
+		}
+	}
+	return res
+}
+
+func copyObject(obj map[string]interface{}) map[string]interface{} {
+	c := make(map[string]interface{}, len(obj)+1)
+	for k, v := range obj {
+		c[k] = v
+	}
+	return c
+}
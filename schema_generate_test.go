@@ -0,0 +1,116 @@
+package openapi_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/chanced/openapi"
+)
+
+var generateSchemas = []string{
+	`{ "type": "integer", "minimum": 3, "exclusiveMaximum": 40, "multipleOf": 3 }`,
+	`{ "type": "number", "exclusiveMinimum": 0.5, "maximum": 0.75 }`,
+	`{ "type": "string", "minLength": 2, "maxLength": 4 }`,
+	`{ "type": "string", "pattern": "^[A-Z]{2}-[0-9]{3,5}$" }`,
+	`{ "type": "string", "format": "date-time" }`,
+	`{ "enum": ["red", "green", "blue"] }`,
+	`{
+		"type": "object",
+		"required": ["id", "tags"],
+		"properties": {
+			"id": { "type": "string", "format": "uuid" },
+			"age": { "type": "integer", "minimum": 0, "maximum": 150 },
+			"tags": { "type": "array", "items": { "type": "string" }, "minItems": 1, "uniqueItems": true }
+		},
+		"additionalProperties": false
+	}`,
+	`{ "type": "object", "minProperties": 2, "patternProperties": { "^x-": { "type": "boolean" } }, "additionalProperties": false }`,
+	`{ "oneOf": [{ "type": "string", "maxLength": 3 }, { "type": "integer", "minimum": 10 }] }`,
+	`{ "type": "array", "prefixItems": [{ "const": "id" }], "items": { "type": "integer" }, "contains": { "type": "integer", "minimum": 5 } }`,
+	`{ "allOf": [{ "type": "object", "required": ["a"] }, { "properties": { "a": { "type": "null" } } }] }`,
+}
+
+func TestInstanceGenerator(t *testing.T) {
+	g := openapi.NewInstanceGenerator(openapi.GenerateOpts{Seed: 1})
+	for _, data := range generateSchemas {
+		s := mustSchema(t, data)
+		cs, err := s.Compile(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 50; i++ {
+			v, err := g.Generate(s)
+			if err != nil {
+				t.Fatalf("%s: %v", data, err)
+			}
+			if err := cs.Validate(v); err != nil {
+				t.Errorf("%s: expected %v to be valid, got %v", data, v, err)
+			}
+			v, err = g.GenerateInvalid(s)
+			if err != nil {
+				t.Fatalf("%s: %v", data, err)
+			}
+			if err := cs.Validate(v); err == nil {
+				t.Errorf("%s: expected %v to be invalid", data, v)
+			}
+		}
+	}
+}
+
+func TestInstanceGeneratorSeed(t *testing.T) {
+	s := mustSchema(t, generateSchemas[6])
+	generate := func(seed int64) string {
+		g := openapi.NewInstanceGenerator(openapi.GenerateOpts{Seed: seed})
+		var instances []interface{}
+		for i := 0; i < 10; i++ {
+			v, err := g.Generate(s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			instances = append(instances, v)
+		}
+		data, err := json.Marshal(instances)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+	if a, b := generate(7), generate(7); a != b {
+		t.Errorf("expected instances of the same seed to be equal, got %s and %s", a, b)
+	}
+	if a, b := generate(7), generate(8); a == b {
+		t.Errorf("expected instances of differing seeds to differ, got %s", a)
+	}
+}
+
+func TestInstanceGeneratorFormats(t *testing.T) {
+	leap := time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)
+	g := openapi.NewInstanceGenerator(openapi.GenerateOpts{
+		Formats: map[openapi.Text]openapi.FormatGenerator{
+			"date": func(r *rand.Rand, s *openapi.Schema) interface{} {
+				return leap.Format("2006-01-02")
+			},
+		},
+	})
+	v, err := g.Generate(mustSchema(t, `{ "type": "string", "format": "date" }`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != "2024-02-29" {
+		t.Errorf("expected the date format to be generated by the hook, got %v", v)
+	}
+}
+
+func TestInstanceGeneratorErrors(t *testing.T) {
+	g := openapi.NewInstanceGenerator(openapi.GenerateOpts{})
+	if _, err := g.Generate(mustSchema(t, `{ "type": "integer", "minimum": 5, "maximum": 1 }`)); !errors.Is(err, openapi.ErrInstanceNotGenerated) {
+		t.Errorf("expected ErrInstanceNotGenerated, got %v", err)
+	}
+	if _, err := g.GenerateInvalid(mustSchema(t, `{}`)); !errors.Is(err, openapi.ErrInstanceNotGenerated) {
+		t.Errorf("expected ErrInstanceNotGenerated, got %v", err)
+	}
+}
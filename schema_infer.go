@@ -0,0 +1,297 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"sort"
+	"time"
+)
+
+// InferOpts configures InferSchemaWithOpts.
+type InferOpts struct {
+	// RequiredThreshold is the fraction of object samples in which a
+	// property must be present for it to be required.
+	//
+	// Defaults to 1, requiring a property to be present in every sample.
+	RequiredThreshold float64
+
+	// MaxEnum is the maximum number of distinct strings which are inferred as
+	// an enum. Strings are only inferred as an enum if each distinct value
+	// occurs, on average, at least twice.
+	//
+	// Defaults to 5.
+	MaxEnum int
+
+	// DisableEnums prevents enums from being inferred.
+	DisableEnums bool
+
+	// DisableFormats prevents formats (e.g. "date-time", "uuid") from being
+	// inferred from strings.
+	DisableFormats bool
+
+	// AnyOf indicates that samples of differing types should be described by
+	// an anyOf with a branch per type rather than a single Schema with
+	// multiple types. null is always merged into the type of the Schema.
+	AnyOf bool
+}
+
+func (opts InferOpts) requiredThreshold() float64 {
+	if opts.RequiredThreshold <= 0 || opts.RequiredThreshold > 1 {
+		return 1
+	}
+	return opts.RequiredThreshold
+}
+
+func (opts InferOpts) maxEnum() int {
+	if opts.DisableEnums {
+		return 0
+	}
+	if opts.MaxEnum <= 0 {
+		return 5
+	}
+	return opts.MaxEnum
+}
+
+// InferSchema returns a Schema describing each of the JSON samples, inferring
+// types, required properties, formats, and enums. Samples which are not
+// valid JSON are ignored. If there are no valid samples, nil is returned.
+//
+// Objects are merged across samples; a property is required if it is
+// present in every sample. Arrays are described by a single items Schema
+// inferred from the elements of every sample.
+func InferSchema(samples ...[]byte) *Schema {
+	return InferSchemaWithOpts(InferOpts{}, samples...)
+}
+
+// InferSchemaWithOpts is InferSchema configured with opts.
+func InferSchemaWithOpts(opts InferOpts, samples ...[]byte) *Schema {
+	var values []interface{}
+	for _, sample := range samples {
+		d := json.NewDecoder(bytes.NewReader(sample))
+		d.UseNumber()
+		var v interface{}
+		if err := d.Decode(&v); err == nil {
+			values = append(values, v)
+		}
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	return (&inferrer{opts: opts}).infer(values)
+}
+
+type inferrer struct {
+	opts InferOpts
+}
+
+// inferTypes is the order in which inferred types are listed
+var inferTypes = []Type{TypeObject, TypeArray, TypeString, TypeInteger, TypeNumber, TypeBoolean}
+
+// infer returns a Schema describing values, which are the result of decoding
+// JSON with json.Decoder.UseNumber.
+func (i *inferrer) infer(values []interface{}) *Schema {
+	byType := map[Type][]interface{}{}
+	null := false
+	for _, v := range values {
+		switch v := v.(type) {
+		case nil:
+			null = true
+		case bool:
+			byType[TypeBoolean] = append(byType[TypeBoolean], v)
+		case string:
+			byType[TypeString] = append(byType[TypeString], v)
+		case json.Number:
+			byType[TypeNumber] = append(byType[TypeNumber], v)
+		case []interface{}:
+			byType[TypeArray] = append(byType[TypeArray], v)
+		case map[string]interface{}:
+			byType[TypeObject] = append(byType[TypeObject], v)
+		}
+	}
+	if nums, ok := byType[TypeNumber]; ok && allIntegers(nums) {
+		byType[TypeInteger] = nums
+		delete(byType, TypeNumber)
+	}
+
+	var schemas []*Schema
+	for _, t := range inferTypes {
+		vs, ok := byType[t]
+		if !ok {
+			continue
+		}
+		schemas = append(schemas, i.inferType(t, vs))
+	}
+	var s *Schema
+	switch {
+	case len(schemas) == 0:
+		s = &Schema{}
+	case len(schemas) == 1:
+		s = schemas[0]
+	case i.opts.AnyOf:
+		s = &Schema{AnyOf: &SchemaSlice{Items: schemas}}
+	default:
+		s = &Schema{}
+		for _, b := range schemas {
+			s.Type = append(s.Type, b.Type...)
+			mergeInferred(s, b)
+		}
+	}
+	if null {
+		if s.AnyOf != nil {
+			s.AnyOf.Items = append(s.AnyOf.Items, &Schema{Type: Types{TypeNull}})
+		} else {
+			s.Type = append(s.Type, TypeNull)
+		}
+	}
+	return s
+}
+
+// mergeInferred copies the type-specific keywords of src into dst
+func mergeInferred(dst, src *Schema) {
+	switch {
+	case src.Type.ContainsObject():
+		dst.Properties = src.Properties
+		dst.Required = src.Required
+	case src.Type.ContainsArray():
+		dst.Items = src.Items
+	case src.Type.ContainsString():
+		dst.Format = src.Format
+		dst.Enum = src.Enum
+	}
+}
+
+func (i *inferrer) inferType(t Type, values []interface{}) *Schema {
+	s := &Schema{Type: Types{t}}
+	switch t {
+	case TypeString:
+		strs := make([]string, len(values))
+		for n, v := range values {
+			strs[n] = v.(string)
+		}
+		if !i.opts.DisableFormats {
+			s.Format = inferFormat(strs)
+		}
+		if s.Format == "" {
+			s.Enum = i.inferEnum(strs)
+		}
+	case TypeArray:
+		var items []interface{}
+		for _, v := range values {
+			items = append(items, v.([]interface{})...)
+		}
+		if len(items) > 0 {
+			s.Items = i.infer(items)
+		}
+	case TypeObject:
+		objs := make([]map[string]interface{}, len(values))
+		for n, v := range values {
+			objs[n] = v.(map[string]interface{})
+		}
+		i.inferProperties(s, objs)
+	}
+	return s
+}
+
+func (i *inferrer) inferProperties(s *Schema, objs []map[string]interface{}) {
+	counts := map[string]int{}
+	values := map[string][]interface{}{}
+	for _, obj := range objs {
+		for k, v := range obj {
+			counts[k]++
+			values[k] = append(values[k], v)
+		}
+	}
+	if len(values) == 0 {
+		return
+	}
+	// decoding into a map loses the order of keys; properties are sorted
+	// for stable output
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	threshold := i.opts.requiredThreshold()
+	s.Properties = &SchemaMap{}
+	for _, k := range keys {
+		s.Properties.Set(Text(k), i.infer(values[k]))
+		if float64(counts[k])/float64(len(objs)) >= threshold {
+			s.Required = append(s.Required, Text(k))
+		}
+	}
+}
+
+func (i *inferrer) inferEnum(strs []string) Enum {
+	max := i.opts.maxEnum()
+	if max == 0 {
+		return nil
+	}
+	seen := map[string]bool{}
+	var distinct []string
+	for _, s := range strs {
+		if !seen[s] {
+			seen[s] = true
+			distinct = append(distinct, s)
+			if len(distinct) > max {
+				return nil
+			}
+		}
+	}
+	if len(strs) < 2*len(distinct) {
+		return nil
+	}
+	sort.Strings(distinct)
+	enum := make(Texts, len(distinct))
+	for n, s := range distinct {
+		enum[n] = Text(s)
+	}
+	return StringEnum(enum...)
+}
+
+func allIntegers(nums []interface{}) bool {
+	for _, n := range nums {
+		if _, err := n.(json.Number).Int64(); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+var (
+	inferUUIDPattern  = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	inferEmailPattern = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+	inferURIPattern   = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9+.-]*://\S+$`)
+)
+
+// inferFormats are the formats which may be inferred, in order of precedence
+var inferFormats = []struct {
+	format Text
+	match  func(string) bool
+}{
+	{"date-time", func(s string) bool { _, err := time.Parse(time.RFC3339, s); return err == nil }},
+	{"date", func(s string) bool { _, err := time.Parse("2006-01-02", s); return err == nil }},
+	{"uuid", inferUUIDPattern.MatchString},
+	{"email", inferEmailPattern.MatchString},
+	{"uri", inferURIPattern.MatchString},
+}
+
+// inferFormat returns the format shared by each of strs, if any
+func inferFormat(strs []string) Text {
+	if len(strs) == 0 {
+		return ""
+	}
+	for _, f := range inferFormats {
+		ok := true
+		for _, s := range strs {
+			if !f.match(s) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return f.format
+		}
+	}
+	return ""
+}
@@ -0,0 +1,94 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/chanced/openapi"
+)
+
+func TestInferSchema(t *testing.T) {
+	samples := [][]byte{
+		[]byte(`{"id": 1, "status": "active", "email": "a@example.com", "score": 1, "tags": ["a"], "owner": null, "value": "1"}`),
+		[]byte(`{"id": 2, "status": "inactive", "email": "b@example.com", "score": 2.5, "tags": [], "created": "2020-01-02T03:04:05Z", "value": 2}`),
+		[]byte(`{"id": 3, "status": "active", "email": "c@example.com", "score": 3, "owner": {"name": "x"}}`),
+		[]byte(`{"id": 4, "status": "active", "email": "d@example.com", "score": 4}`),
+		[]byte(`not json`),
+	}
+	tests := []struct {
+		name     string
+		opts     openapi.InferOpts
+		expected string
+	}{
+		{
+			name: "default",
+			expected: `{
+				"type": "object",
+				"required": ["email", "id", "score", "status"],
+				"properties": {
+					"created": { "type": "string", "format": "date-time" },
+					"email": { "type": "string", "format": "email" },
+					"id": { "type": "integer" },
+					"owner": {
+						"type": ["object", "null"],
+						"required": ["name"],
+						"properties": { "name": { "type": "string" } }
+					},
+					"score": { "type": "number" },
+					"status": { "type": "string", "enum": ["active", "inactive"] },
+					"tags": { "type": "array", "items": { "type": "string" } },
+					"value": { "type": ["string", "integer"] }
+				}
+			}`,
+		},
+		{
+			name: "opts",
+			opts: openapi.InferOpts{RequiredThreshold: 0.5, DisableEnums: true, DisableFormats: true, AnyOf: true},
+			expected: `{
+				"required": ["email", "id", "owner", "score", "status", "tags", "value"],
+				"type": "object",
+				"properties": {
+					"created": { "type": "string" },
+					"email": { "type": "string" },
+					"id": { "type": "integer" },
+					"owner": {
+						"type": ["object", "null"],
+						"required": ["name"],
+						"properties": { "name": { "type": "string" } }
+					},
+					"score": { "type": "number" },
+					"status": { "type": "string" },
+					"value": { "anyOf": [{ "type": "string" }, { "type": "integer" }] },
+					"tags": { "type": "array", "items": { "type": "string" } }
+				}
+			}`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := openapi.InferSchemaWithOpts(test.opts, samples...)
+			if s == nil {
+				t.Fatal("expected a schema")
+			}
+			data, err := json.Marshal(s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var a, e interface{}
+			if err = json.Unmarshal(data, &a); err != nil {
+				t.Fatal(err)
+			}
+			if err = json.Unmarshal([]byte(test.expected), &e); err != nil {
+				t.Fatal(err)
+			}
+			ab, _ := json.Marshal(a)
+			eb, _ := json.Marshal(e)
+			if string(ab) != string(eb) {
+				t.Errorf("expected:\n%s\ngot:\n%s", eb, ab)
+			}
+		})
+	}
+	if s := openapi.InferSchema([]byte("{")); s != nil {
+		t.Errorf("expected nil for invalid samples, got %v", s)
+	}
+}
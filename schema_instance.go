@@ -0,0 +1,405 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"sort"
+	"unicode/utf8"
+)
+
+// maxInstanceDepth bounds the number of $ref indirections followed while
+// deciding whether a Schema permits a value, guarding against cycles
+const maxInstanceDepth = 64
+
+// decodeInstance decodes the JSON value data with json.Decoder.UseNumber
+func decodeInstance(data []byte) (interface{}, bool) {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	var v interface{}
+	if err := d.Decode(&v); err != nil || d.More() {
+		return nil, false
+	}
+	return v, true
+}
+
+// instanceType returns the Type of v, a value decoded with
+// json.Decoder.UseNumber. Numbers which are integers are TypeInteger.
+func instanceType(v interface{}) Type {
+	switch v := v.(type) {
+	case nil:
+		return TypeNull
+	case bool:
+		return TypeBoolean
+	case string:
+		return TypeString
+	case json.Number:
+		if r, ok := Number(v).BigRat(); ok && r.IsInt() {
+			return TypeInteger
+		}
+		return TypeNumber
+	case []interface{}:
+		return TypeArray
+	case map[string]interface{}:
+		return TypeObject
+	default:
+		return ""
+	}
+}
+
+// canonicalInstance returns the canonical JSON encoding of v (see
+// canonicalJSON)
+func canonicalInstance(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return canonicalJSON(data)
+}
+
+// permits decides whether v, a value decoded with json.Decoder.UseNumber, is
+// permitted by s.
+//
+// format, contentEncoding, contentMediaType, and contentSchema are treated
+// as annotations, as they are by default in JSON Schema 2020-12. Keywords
+// which are not evaluated, such as unevaluatedProperties, unresolved
+// references, and custom keywords, result in DecisionUnknown unless another
+// keyword rejects v.
+func (s *Schema) permits(v interface{}) Decision {
+	return permits(s, v, 0)
+}
+
+func permits(s *Schema, v interface{}, depth int) Decision {
+	if s == nil {
+		return DecisionYes
+	}
+	if b, ok := s.Bool(); ok {
+		return decide(b)
+	}
+	if depth > maxInstanceDepth {
+		return DecisionUnknown
+	}
+	d := DecisionYes
+	// and adds x to d, reporting whether d is decided as DecisionNo
+	and := func(x Decision) bool {
+		d = d.and(x)
+		return d == DecisionNo
+	}
+
+	if len(s.Type) > 0 && !typesAllow(s.Type, instanceType(v)) {
+		return DecisionNo
+	}
+	if s.Const != nil || len(s.Enum) > 0 {
+		cv := canonicalInstance(v)
+		if s.Const != nil && canonicalJSON(s.Const) != cv {
+			return DecisionNo
+		}
+		if len(s.Enum) > 0 && !enumContainsCanonical(s.Enum, cv) {
+			return DecisionNo
+		}
+	}
+
+	switch x := v.(type) {
+	case string:
+		if and(s.permitsString(x)) {
+			return d
+		}
+	case json.Number:
+		if and(s.permitsNumber(Number(x))) {
+			return d
+		}
+	case map[string]interface{}:
+		if and(s.permitsObject(x, depth)) {
+			return d
+		}
+	case []interface{}:
+		if and(s.permitsArray(x, depth)) {
+			return d
+		}
+	}
+
+	if s.Ref != nil {
+		if s.Ref.Resolved == nil {
+			and(DecisionUnknown)
+		} else if and(permits(s.Ref.Resolved, v, depth+1)) {
+			return d
+		}
+	}
+	if s.DynamicRef != nil || s.RecursiveRef != nil {
+		// the targets depend upon the dynamic scope
+		and(DecisionUnknown)
+	}
+	if s.AllOf != nil {
+		for _, b := range s.AllOf.Items {
+			if and(permits(b, v, depth+1)) {
+				return d
+			}
+		}
+	}
+	if s.AnyOf != nil && len(s.AnyOf.Items) > 0 {
+		r := DecisionNo
+		for _, b := range s.AnyOf.Items {
+			if r = r.or(permits(b, v, depth+1)); r == DecisionYes {
+				break
+			}
+		}
+		if and(r) {
+			return d
+		}
+	}
+	if s.OneOf != nil && len(s.OneOf.Items) > 0 {
+		matched, unknown := 0, false
+		for _, b := range s.OneOf.Items {
+			switch permits(b, v, depth+1) {
+			case DecisionYes:
+				matched++
+			case DecisionUnknown:
+				unknown = true
+			}
+		}
+		switch {
+		case matched > 1:
+			return DecisionNo
+		case unknown:
+			and(DecisionUnknown)
+		case matched == 0:
+			return DecisionNo
+		}
+	}
+	if s.Not != nil {
+		if and(permits(s.Not, v, depth+1).not()) {
+			return d
+		}
+	}
+	if s.If != nil && (s.Then != nil || s.Else != nil) {
+		var r Decision
+		switch permits(s.If, v, depth+1) {
+		case DecisionYes:
+			r = permits(s.Then, v, depth+1)
+		case DecisionNo:
+			r = permits(s.Else, v, depth+1)
+		default:
+			r = DecisionUnknown
+		}
+		if and(r) {
+			return d
+		}
+	}
+	for k := range s.Keywords {
+		switch k {
+		case "minItems", "maxItems", keywordContentSchema:
+		default:
+			and(DecisionUnknown)
+		}
+	}
+	return d
+}
+
+func enumContainsCanonical(e Enum, cv string) bool {
+	for _, x := range e {
+		if canonicalJSON(x) == cv {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Schema) permitsString(x string) Decision {
+	n := IntNumber(int64(utf8.RuneCountInString(x)))
+	if s.MinLength != nil && n.Cmp(*s.MinLength) < 0 {
+		return DecisionNo
+	}
+	if s.MaxLength != nil && n.Cmp(*s.MaxLength) > 0 {
+		return DecisionNo
+	}
+	if !s.Pattern.IsNil() {
+		if !s.Pattern.IsCompiled() {
+			return DecisionUnknown
+		}
+		return decide(s.Pattern.MatchString(x))
+	}
+	return DecisionYes
+}
+
+func (s *Schema) permitsNumber(x Number) Decision {
+	if s.Minimum != nil && x.Cmp(*s.Minimum) < 0 {
+		return DecisionNo
+	}
+	if s.ExclusiveMinimum != nil && x.Cmp(*s.ExclusiveMinimum) <= 0 {
+		return DecisionNo
+	}
+	if s.Maximum != nil && x.Cmp(*s.Maximum) > 0 {
+		return DecisionNo
+	}
+	if s.ExclusiveMaximum != nil && x.Cmp(*s.ExclusiveMaximum) >= 0 {
+		return DecisionNo
+	}
+	if s.MultipleOf != nil {
+		xr, ok := x.BigRat()
+		mr, mok := s.MultipleOf.BigRat()
+		if !ok || !mok || mr.Sign() == 0 {
+			return DecisionUnknown
+		}
+		return decide(new(big.Rat).Quo(xr, mr).IsInt())
+	}
+	return DecisionYes
+}
+
+func (s *Schema) permitsObject(x map[string]interface{}, depth int) Decision {
+	for _, r := range s.Required {
+		if _, ok := x[r.String()]; !ok {
+			return DecisionNo
+		}
+	}
+	n := IntNumber(int64(len(x)))
+	if s.MinProperties != nil && n.Cmp(*s.MinProperties) < 0 {
+		return DecisionNo
+	}
+	if s.MaxProperties != nil && n.Cmp(*s.MaxProperties) > 0 {
+		return DecisionNo
+	}
+	if s.DependentRequired != nil {
+		for _, kv := range s.DependentRequired.Items {
+			if _, ok := x[kv.Key.String()]; !ok {
+				continue
+			}
+			for _, r := range kv.Value {
+				if _, ok := x[r.String()]; !ok {
+					return DecisionNo
+				}
+			}
+		}
+	}
+
+	d := DecisionYes
+	keys := make([]string, 0, len(x))
+	for k := range x {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		pv := x[k]
+		matched := false
+		if s.Properties != nil {
+			if ps := s.Properties.Get(Text(k)); ps != nil {
+				matched = true
+				d = d.and(permits(ps, pv, depth+1))
+			}
+		}
+		if s.PatternProperties != nil {
+			for _, item := range s.PatternProperties.Items {
+				re, err := CompilePattern(item.Key.String(), PatternLenient)
+				if err != nil || !re.IsCompiled() {
+					d = d.and(DecisionUnknown)
+					continue
+				}
+				if re.MatchString(k) {
+					matched = true
+					d = d.and(permits(item.Schema, pv, depth+1))
+				}
+			}
+		}
+		if !matched && s.AdditionalProperties != nil {
+			d = d.and(permits(s.AdditionalProperties, pv, depth+1))
+		}
+		if s.PropertyNames != nil {
+			d = d.and(permits(s.PropertyNames, k, depth+1))
+		}
+		if d == DecisionNo {
+			return d
+		}
+	}
+	if s.DependentSchemas != nil {
+		for _, item := range s.DependentSchemas.Items {
+			if _, ok := x[item.Key.String()]; ok {
+				d = d.and(permits(item.Schema, x, depth+1))
+			}
+		}
+	}
+	if s.UnevaluatedProperties != nil && len(x) > 0 {
+		d = d.and(DecisionUnknown)
+	}
+	return d
+}
+
+func (s *Schema) permitsArray(x []interface{}, depth int) Decision {
+	n := IntNumber(int64(len(x)))
+	if min, ok := s.keywordNumber("minItems"); ok && n.Cmp(min) < 0 {
+		return DecisionNo
+	}
+	if max, ok := s.keywordNumber("maxItems"); ok && n.Cmp(max) > 0 {
+		return DecisionNo
+	}
+	if s.UniqueItems != nil && *s.UniqueItems {
+		seen := make(map[string]bool, len(x))
+		for _, v := range x {
+			cv := canonicalInstance(v)
+			if seen[cv] {
+				return DecisionNo
+			}
+			seen[cv] = true
+		}
+	}
+
+	d := DecisionYes
+	prefix := 0
+	if s.PrefixItems != nil {
+		prefix = len(s.PrefixItems.Items)
+		for i, p := range s.PrefixItems.Items {
+			if i < len(x) {
+				d = d.and(permits(p, x[i], depth+1))
+			}
+		}
+	}
+	for i := prefix; i < len(x); i++ {
+		if s.Items != nil {
+			d = d.and(permits(s.Items, x[i], depth+1))
+		}
+		if s.AdditionalItems != nil {
+			d = d.and(DecisionUnknown)
+		}
+	}
+	if d == DecisionNo {
+		return d
+	}
+	if s.Contains != nil {
+		min, max := IntNumber(1), s.MaxContains
+		if s.MinContains != nil {
+			min = *s.MinContains
+		}
+		matched, unknown := 0, 0
+		for _, v := range x {
+			switch permits(s.Contains, v, depth+1) {
+			case DecisionYes:
+				matched++
+			case DecisionUnknown:
+				unknown++
+			}
+		}
+		lo, hi := IntNumber(int64(matched)), IntNumber(int64(matched+unknown))
+		switch {
+		case hi.Cmp(min) < 0, max != nil && lo.Cmp(*max) > 0:
+			return DecisionNo
+		case lo.Cmp(min) >= 0 && (max == nil || hi.Cmp(*max) <= 0):
+		default:
+			d = d.and(DecisionUnknown)
+		}
+	}
+	if s.UnevaluatedItems != nil && len(x) > 0 {
+		d = d.and(DecisionUnknown)
+	}
+	return d
+}
+
+// keywordNumber returns the value of the custom keyword k of s as a Number
+func (s *Schema) keywordNumber(k Text) (Number, bool) {
+	data, ok := s.Keywords[k]
+	if !ok {
+		return "", false
+	}
+	n, err := ParseNumber(string(bytes.TrimSpace(data)))
+	if err != nil {
+		return "", false
+	}
+	return n, true
+}
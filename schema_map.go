@@ -1,12 +1,10 @@
 package openapi
 
 import (
-	"bytes"
 	"encoding/json"
 	"reflect"
 
 	"github.com/chanced/jsonx"
-	"github.com/chanced/transcode"
 	"github.com/tidwall/gjson"
 	"gopkg.in/yaml.v3"
 )
@@ -74,7 +72,7 @@ func (sm *SchemaMap) Anchors() (*Anchors, error) {
 	var anchors *Anchors
 	var err error
 	for _, e := range sm.Items {
-		if anchors, err = e.Schema.Anchors(); err != nil {
+		if anchors, err = anchors.merge(e.Schema.Anchors()); err != nil {
 			return nil, err
 		}
 	}
@@ -139,7 +137,7 @@ func (sm SchemaMap) Get(key Text) *Schema {
 }
 
 func (sm SchemaMap) MarshalJSON() ([]byte, error) {
-	b := bytes.Buffer{}
+	b := getBuffer()
 	b.WriteByte('{')
 	var err error
 	var s []byte
@@ -147,16 +145,17 @@ func (sm SchemaMap) MarshalJSON() ([]byte, error) {
 		if b.Len() > 1 {
 			b.WriteByte(',')
 		}
-		jsonx.EncodeAndWriteString(&b, v.Key.String())
+		jsonx.EncodeAndWriteString(b, v.Key.String())
 		b.WriteByte(':')
 		s, err = v.Schema.MarshalJSON()
 		if err != nil {
+			putBuffer(b)
 			return nil, err
 		}
 		b.Write(s)
 	}
 	b.WriteByte('}')
-	return b.Bytes(), nil
+	return releaseBuffer(b), nil
 }
 
 func (sm *SchemaMap) UnmarshalJSON(data []byte) error {
@@ -166,10 +165,20 @@ func (sm *SchemaMap) UnmarshalJSON(data []byte) error {
 	}
 	*sm = SchemaMap{}
 	var err error
-	gjson.ParseBytes(data).ForEach(func(key, value gjson.Result) bool {
-		var s Schema
-		err = json.Unmarshal([]byte(value.Raw), &s)
-		sm.Items = append(sm.Items, SchemaItem{Key: Text(key.String()), Schema: &s})
+	obj := gjson.ParseBytes(data)
+	n := countMembers(obj)
+	sm.Items = make([]SchemaItem, 0, n)
+	schemas := newAllocator[Schema](n)
+	// Schema.UnmarshalJSON does not retain data so a single buffer is
+	// reused for the members
+	b := getBuffer()
+	defer putBuffer(b)
+	obj.ForEach(func(key, value gjson.Result) bool {
+		s := schemas.new()
+		b.Reset()
+		b.WriteString(value.Raw)
+		err = s.UnmarshalJSON(b.Bytes())
+		sm.Items = append(sm.Items, SchemaItem{Key: intern(key.String()), Schema: s})
 		return err == nil
 	})
 	return err
@@ -191,11 +200,7 @@ func (sm SchemaMap) MarshalYAML() (interface{}, error) {
 
 // UnmarshalYAML satisfies gopkg.in/yaml.v3 Unmarshaler interface
 func (sm *SchemaMap) UnmarshalYAML(value *yaml.Node) error {
-	v, err := yaml.Marshal(value)
-	if err != nil {
-		return err
-	}
-	j, err := transcode.JSONFromYAML(v)
+	j, err := jsonFromYAMLNode(value)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,610 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/chanced/jsonx"
+)
+
+// SchemaConflict describes a keyword which could not be combined while
+// merging the allOf branches of a Schema.
+type SchemaConflict struct {
+	// Location of the Schema whose keyword could not be merged
+	Location
+	// Keyword which could not be merged
+	Keyword Text
+	// Reason the keyword could not be merged
+	Reason string
+}
+
+func (c SchemaConflict) Error() string {
+	return fmt.Sprintf("openapi: unable to merge %q for %s: %s", c.Keyword, c.AbsoluteLocation(), c.Reason)
+}
+
+// MergeAllOf returns a new Schema which combines s with each of the branches
+// of s's allOf. s is not modified.
+//
+// Branches which reference another Schema through a resolved $ref are replaced
+// by the referenced Schema. Properties and the other keyed subschemas are
+// merged by key, required is the union of all branches, types and enums are
+// intersected, and numeric constraints use the tightest value (e.g. the
+// largest minimum and smallest maximum). Annotations, such as title and
+// description, are taken from s when present and otherwise from the first
+// branch which defines them.
+//
+// Keywords which can not be combined into a single value, such as differing
+// patterns or formats, retain the first value encountered and are reported as
+// a SchemaConflict. Constraints which can not be satisfied once merged (e.g. a
+// minimum greater than the maximum) are also reported.
+func (s *Schema) MergeAllOf() (*Schema, []SchemaConflict) {
+	m := newSchemaMerger()
+	return m.mergeAllOf(s), m.conflicts
+}
+
+// Flatten is similar to MergeAllOf, except that the allOf branches of each
+// inline subschema (e.g. properties, items) are merged as well.
+//
+// Subschemas which are referenced via $ref are left in place.
+func (s *Schema) Flatten() (*Schema, []SchemaConflict) {
+	m := newSchemaMerger()
+	return m.flatten(s), m.conflicts
+}
+
+type schemaMerger struct {
+	merging    map[*Schema]bool
+	flattening map[*Schema]bool
+	conflicts  []SchemaConflict
+}
+
+func newSchemaMerger() *schemaMerger {
+	return &schemaMerger{
+		merging:    map[*Schema]bool{},
+		flattening: map[*Schema]bool{},
+	}
+}
+
+func (m *schemaMerger) conflict(s *Schema, keyword Text, format string, args ...interface{}) {
+	m.conflicts = append(m.conflicts, SchemaConflict{
+		Location: s.Location,
+		Keyword:  keyword,
+		Reason:   fmt.Sprintf(format, args...),
+	})
+}
+
+func (m *schemaMerger) mergeAllOf(s *Schema) *Schema {
+	if s == nil {
+		return nil
+	}
+	res := s.shallowClone()
+	if s.AllOf == nil || len(s.AllOf.Items) == 0 {
+		return res
+	}
+	if m.merging[s] {
+		m.conflict(s, "allOf", "allOf is circular")
+		return res
+	}
+	m.merging[s] = true
+	defer delete(m.merging, s)
+
+	res.AllOf = nil
+	for _, b := range s.AllOf.Items {
+		m.merge(res, m.branch(b))
+	}
+	m.checkBounds(res)
+	return res
+}
+
+// branch returns the effective Schema for an allOf branch b.
+func (m *schemaMerger) branch(b *Schema) *Schema {
+	if b == nil {
+		return nil
+	}
+	res := m.mergeAllOf(b)
+	if b.Ref == nil || b.Ref.Resolved == nil {
+		return res
+	}
+	if m.merging[b.Ref.Resolved] {
+		m.conflict(b, "$ref", "$ref is circular")
+		return res
+	}
+	res.Ref = nil
+	m.merge(res, m.mergeAllOf(b.Ref.Resolved))
+	return res
+}
+
+// combine returns the merger of a and b.
+func (m *schemaMerger) combine(a, b *Schema) *Schema {
+	switch {
+	case a == nil:
+		return b
+	case b == nil:
+		return a
+	}
+	return m.mergeAllOf(&Schema{
+		Location: a.Location,
+		AllOf:    &SchemaSlice{Location: a.Location, Items: []*Schema{a, b}},
+	})
+}
+
+func (m *schemaMerger) merge(dst, src *Schema) {
+	if src == nil {
+		return
+	}
+	if src.IsNever() {
+		// false is merged as its equivalent, {"not": {}}, so that dst
+		// becomes false as well
+		src = &Schema{Location: src.Location, Not: &Schema{}}
+	}
+	m.mergeRefs(dst, src)
+	m.mergeAssertions(dst, src)
+	m.mergeApplicators(dst, src)
+	m.mergeAnnotations(dst, src)
+}
+
+func (m *schemaMerger) mergeRefs(dst, src *Schema) {
+	mergeRef := func(keyword Text, d **SchemaRef, s *SchemaRef) {
+		switch {
+		case s == nil:
+		case *d == nil:
+			*d = s
+		case (*d).Ref != nil && s.Ref != nil && (*d).Ref.String() != s.Ref.String():
+			m.conflict(src, keyword, "%s conflicts with %s", s.Ref, (*d).Ref)
+		}
+	}
+	mergeRef("$ref", &dst.Ref, src.Ref)
+	mergeRef("$dynamicRef", &dst.DynamicRef, src.DynamicRef)
+	mergeRef("$recursiveRef", &dst.RecursiveRef, src.RecursiveRef)
+}
+
+func (m *schemaMerger) mergeAssertions(dst, src *Schema) {
+	if len(src.Type) > 0 {
+		if len(dst.Type) == 0 {
+			dst.Type = src.Type.Clone()
+		} else if t := intersectTypes(dst.Type, src.Type); len(t) > 0 {
+			dst.Type = t
+		} else {
+			m.conflict(src, "type", "type %v does not intersect with %v", src.Type, dst.Type)
+		}
+	}
+	if len(src.Enum) > 0 {
+		if len(dst.Enum) == 0 {
+			dst.Enum = src.Enum.Clone()
+		} else if e := intersectEnums(dst.Enum, src.Enum); len(e) > 0 {
+			dst.Enum = e
+		} else {
+			m.conflict(src, "enum", "enum %v does not intersect with %v", src.Enum, dst.Enum)
+		}
+	}
+	if src.Const != nil {
+		if dst.Const == nil {
+			dst.Const = src.Const
+		} else if !jsonEqual(dst.Const, src.Const) {
+			m.conflict(src, "const", "const %s conflicts with %s", src.Const, dst.Const)
+		}
+	}
+	if src.Format != "" {
+		if dst.Format == "" {
+			dst.Format = src.Format
+		} else if dst.Format != src.Format {
+			m.conflict(src, "format", "format %q conflicts with %q", src.Format, dst.Format)
+		}
+	}
+	if !src.Pattern.IsNil() {
+		if dst.Pattern.IsNil() {
+			dst.Pattern = src.Pattern
+		} else if dst.Pattern.String() != src.Pattern.String() {
+			m.conflict(src, "pattern", "pattern %q conflicts with %q", src.Pattern, dst.Pattern)
+		}
+	}
+	if src.ContentEncoding != "" {
+		if dst.ContentEncoding == "" {
+			dst.ContentEncoding = src.ContentEncoding
+		} else if dst.ContentEncoding != src.ContentEncoding {
+			m.conflict(src, "contentEncoding", "contentEncoding %q conflicts with %q", src.ContentEncoding, dst.ContentEncoding)
+		}
+	}
+	if src.ContentMediaType != "" {
+		if dst.ContentMediaType == "" {
+			dst.ContentMediaType = src.ContentMediaType
+		} else if dst.ContentMediaType != src.ContentMediaType {
+			m.conflict(src, "contentMediaType", "contentMediaType %q conflicts with %q", src.ContentMediaType, dst.ContentMediaType)
+		}
+	}
+
+	dst.Minimum = maxNumber(dst.Minimum, src.Minimum)
+	dst.ExclusiveMinimum = maxNumber(dst.ExclusiveMinimum, src.ExclusiveMinimum)
+	dst.Maximum = minNumber(dst.Maximum, src.Maximum)
+	dst.ExclusiveMaximum = minNumber(dst.ExclusiveMaximum, src.ExclusiveMaximum)
+	dst.MinLength = maxNumber(dst.MinLength, src.MinLength)
+	dst.MaxLength = minNumber(dst.MaxLength, src.MaxLength)
+	dst.MinProperties = maxNumber(dst.MinProperties, src.MinProperties)
+	dst.MaxProperties = minNumber(dst.MaxProperties, src.MaxProperties)
+	dst.MinContains = maxNumber(dst.MinContains, src.MinContains)
+	dst.MaxContains = minNumber(dst.MaxContains, src.MaxContains)
+
+	if src.MultipleOf != nil {
+		if mo, ok := mergeMultipleOf(dst.MultipleOf, src.MultipleOf); ok {
+			dst.MultipleOf = mo
+		} else {
+			m.conflict(src, "multipleOf", "multipleOf %s can not be combined with %s", src.MultipleOf, dst.MultipleOf)
+		}
+	}
+
+	dst.UniqueItems = orTrue(dst.UniqueItems, src.UniqueItems)
+	dst.Required = unionTexts(dst.Required, src.Required)
+
+	if src.DependentRequired != nil {
+		if dst.DependentRequired == nil {
+			dst.DependentRequired = &Map[Texts]{}
+		}
+		for _, kv := range src.DependentRequired.Items {
+			v, _ := dst.DependentRequired.Get(kv.Key)
+			dst.DependentRequired.Set(kv.Key, unionTexts(v, kv.Value))
+		}
+	}
+}
+
+func (m *schemaMerger) mergeApplicators(dst, src *Schema) {
+	dst.Properties = m.mergeSchemaMaps(dst.Properties, src.Properties)
+	dst.PatternProperties = m.mergeSchemaMaps(dst.PatternProperties, src.PatternProperties)
+	dst.DependentSchemas = m.mergeSchemaMaps(dst.DependentSchemas, src.DependentSchemas)
+	dst.Definitions = m.mergeSchemaMaps(dst.Definitions, src.Definitions)
+
+	dst.AdditionalProperties = m.combine(dst.AdditionalProperties, src.AdditionalProperties)
+	dst.PropertyNames = m.combine(dst.PropertyNames, src.PropertyNames)
+	dst.UnevaluatedProperties = m.combine(dst.UnevaluatedProperties, src.UnevaluatedProperties)
+	dst.Items = m.combine(dst.Items, src.Items)
+	dst.AdditionalItems = m.combine(dst.AdditionalItems, src.AdditionalItems)
+	dst.UnevaluatedItems = m.combine(dst.UnevaluatedItems, src.UnevaluatedItems)
+	dst.Contains = m.combine(dst.Contains, src.Contains)
+
+	if src.PrefixItems != nil {
+		if dst.PrefixItems == nil {
+			dst.PrefixItems = src.PrefixItems
+		} else {
+			items := make([]*Schema, len(dst.PrefixItems.Items))
+			copy(items, dst.PrefixItems.Items)
+			for i, s := range src.PrefixItems.Items {
+				if i < len(items) {
+					items[i] = m.combine(items[i], s)
+				} else {
+					items = append(items, s)
+				}
+			}
+			dst.PrefixItems = &SchemaSlice{Location: dst.PrefixItems.Location, Items: items}
+		}
+	}
+
+	if src.Not != nil {
+		if dst.Not == nil {
+			dst.Not = src.Not
+		} else {
+			// not A and not B is equivalent to not (A or B)
+			dst.Not = &Schema{
+				Location: dst.Not.Location,
+				AnyOf:    &SchemaSlice{Location: dst.Not.Location, Items: []*Schema{dst.Not, src.Not}},
+			}
+		}
+	}
+
+	mergeSlice := func(keyword Text, d **SchemaSlice, s *SchemaSlice) {
+		switch {
+		case s == nil:
+		case *d == nil:
+			*d = s
+		default:
+			m.conflict(src, keyword, "%s is defined by multiple branches", keyword)
+		}
+	}
+	mergeSlice("anyOf", &dst.AnyOf, src.AnyOf)
+	mergeSlice("oneOf", &dst.OneOf, src.OneOf)
+
+	if src.If != nil || src.Then != nil || src.Else != nil {
+		if dst.If == nil && dst.Then == nil && dst.Else == nil {
+			dst.If, dst.Then, dst.Else = src.If, src.Then, src.Else
+		} else {
+			m.conflict(src, "if", "if/then/else is defined by multiple branches")
+		}
+	}
+}
+
+func (m *schemaMerger) mergeAnnotations(dst, src *Schema) {
+	if dst.Title == "" {
+		dst.Title = src.Title
+	}
+	if dst.Description == "" {
+		dst.Description = src.Description
+	}
+	if dst.Comments == "" {
+		dst.Comments = src.Comments
+	}
+	if dst.ExternalDocs == "" {
+		dst.ExternalDocs = src.ExternalDocs
+	}
+	if dst.Default == nil {
+		dst.Default = src.Default
+	}
+	if dst.Example == nil {
+		dst.Example = src.Example
+	}
+	if len(src.Examples) > 0 {
+		dst.Examples = append(dst.Examples, src.Examples...)
+	}
+	if dst.Discriminator == nil {
+		dst.Discriminator = src.Discriminator
+	}
+	if dst.XML == nil {
+		dst.XML = src.XML
+	}
+	dst.ReadOnly = orTrue(dst.ReadOnly, src.ReadOnly)
+	dst.WriteOnly = orTrue(dst.WriteOnly, src.WriteOnly)
+	dst.Deprecated = orTrue(dst.Deprecated, src.Deprecated)
+
+	for k, v := range src.Keywords {
+		if dst.Keywords == nil {
+			dst.Keywords = map[Text]jsonx.RawMessage{}
+		}
+		if x, ok := dst.Keywords[k]; ok && !jsonEqual(x, v) {
+			m.conflict(src, k, "%s conflicts with %s", v, x)
+			continue
+		}
+		dst.Keywords[k] = v
+	}
+	for k, v := range src.Extensions {
+		if dst.Extensions == nil {
+			dst.Extensions = Extensions{}
+		}
+		if x, ok := dst.Extensions[k]; ok && !jsonEqual(x, v) {
+			m.conflict(src, k, "%s conflicts with %s", v, x)
+			continue
+		}
+		dst.Extensions[k] = v
+	}
+}
+
+func (m *schemaMerger) mergeSchemaMaps(dst, src *SchemaMap) *SchemaMap {
+	if src == nil {
+		return dst
+	}
+	if dst == nil {
+		return src
+	}
+	res := &SchemaMap{Location: dst.Location, Items: make([]SchemaItem, len(dst.Items))}
+	copy(res.Items, dst.Items)
+	for _, item := range src.Items {
+		res.Set(item.Key, m.combine(res.Get(item.Key), item.Schema))
+	}
+	return res
+}
+
+func (m *schemaMerger) checkBounds(s *Schema) {
+	check := func(keyword Text, min, max *Number) {
+		if min == nil || max == nil {
+			return
+		}
+		if min.Cmp(*max) > 0 {
+			m.conflict(s, keyword, "%s is greater than %s", min, max)
+		}
+	}
+	check("minimum", s.Minimum, s.Maximum)
+	check("minLength", s.MinLength, s.MaxLength)
+	check("minProperties", s.MinProperties, s.MaxProperties)
+	check("minContains", s.MinContains, s.MaxContains)
+	for _, r := range s.Required {
+		if s.Properties == nil {
+			break
+		}
+		if p := s.Properties.Get(r); p.IsNever() {
+			m.conflict(s, "required", "property %q is required but its schema is false", r)
+		}
+	}
+}
+
+func (m *schemaMerger) flatten(s *Schema) *Schema {
+	if s == nil {
+		return nil
+	}
+	if m.flattening[s] {
+		return s
+	}
+	m.flattening[s] = true
+	defer delete(m.flattening, s)
+
+	res := m.mergeAllOf(s)
+
+	flattenMap := func(sm *SchemaMap) *SchemaMap {
+		if sm == nil {
+			return nil
+		}
+		c := &SchemaMap{Location: sm.Location, Items: make([]SchemaItem, len(sm.Items))}
+		for i, item := range sm.Items {
+			c.Items[i] = SchemaItem{Key: item.Key, Schema: m.flatten(item.Schema)}
+		}
+		return c
+	}
+	flattenSlice := func(ss *SchemaSlice) *SchemaSlice {
+		if ss == nil {
+			return nil
+		}
+		c := &SchemaSlice{Location: ss.Location, Items: make([]*Schema, len(ss.Items))}
+		for i, item := range ss.Items {
+			c.Items[i] = m.flatten(item)
+		}
+		return c
+	}
+	res.Properties = flattenMap(res.Properties)
+	res.PatternProperties = flattenMap(res.PatternProperties)
+	res.DependentSchemas = flattenMap(res.DependentSchemas)
+	res.Definitions = flattenMap(res.Definitions)
+	res.AnyOf = flattenSlice(res.AnyOf)
+	res.OneOf = flattenSlice(res.OneOf)
+	res.PrefixItems = flattenSlice(res.PrefixItems)
+	res.AdditionalProperties = m.flatten(res.AdditionalProperties)
+	res.PropertyNames = m.flatten(res.PropertyNames)
+	res.UnevaluatedProperties = m.flatten(res.UnevaluatedProperties)
+	res.Items = m.flatten(res.Items)
+	res.AdditionalItems = m.flatten(res.AdditionalItems)
+	res.UnevaluatedItems = m.flatten(res.UnevaluatedItems)
+	res.Contains = m.flatten(res.Contains)
+	res.Not = m.flatten(res.Not)
+	res.If = m.flatten(res.If)
+	res.Then = m.flatten(res.Then)
+	res.Else = m.flatten(res.Else)
+	return res
+}
+
+// shallowClone returns a copy of s where slices and maps which are modified
+// while merging are copied. Subschemas are not cloned.
+func (s *Schema) shallowClone() *Schema {
+	c := *s
+	c.Type = s.Type.Clone()
+	if s.Required != nil {
+		c.Required = append(Texts(nil), s.Required...)
+	}
+	if s.Enum != nil {
+		c.Enum = s.Enum.Clone()
+	}
+	if s.Examples != nil {
+		c.Examples = append([]jsonx.RawMessage(nil), s.Examples...)
+	}
+	if s.DependentRequired != nil {
+		c.DependentRequired = &Map[Texts]{Items: append([]KeyValue[Texts](nil), s.DependentRequired.Items...)}
+	}
+	if s.Keywords != nil {
+		c.Keywords = make(map[Text]jsonx.RawMessage, len(s.Keywords))
+		for k, v := range s.Keywords {
+			c.Keywords[k] = v
+		}
+	}
+	c.Extensions = cloneExtensions(s.Extensions)
+	return &c
+}
+
+func intersectTypes(a, b Types) Types {
+	var res Types
+	add := func(t Type) {
+		if !res.Contains(t) {
+			res = append(res, t)
+		}
+	}
+	for _, t := range a {
+		switch {
+		case b.Contains(t):
+			add(t)
+		case t == TypeInteger && b.Contains(TypeNumber):
+			add(TypeInteger)
+		case t == TypeNumber && b.Contains(TypeInteger):
+			add(TypeInteger)
+		}
+	}
+	return res
+}
+
+func intersectEnums(a, b Enum) Enum {
+	var res Enum
+	for _, v := range a {
+		if b.Contains(v) {
+			res = append(res, append(jsonx.RawMessage(nil), v...))
+		}
+	}
+	return res
+}
+
+func unionTexts(a, b Texts) Texts {
+	if len(b) == 0 {
+		return a
+	}
+	res := append(Texts(nil), a...)
+	for _, v := range b {
+		found := false
+		for _, x := range res {
+			if x == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+func orTrue(a, b *bool) *bool {
+	if a != nil && *a {
+		return a
+	}
+	if b != nil && *b {
+		return b
+	}
+	if a != nil {
+		return a
+	}
+	return b
+}
+
+func jsonEqual(a, b []byte) bool {
+	var ab, bb bytes.Buffer
+	if err := json.Compact(&ab, a); err != nil {
+		return bytes.Equal(a, b)
+	}
+	if err := json.Compact(&bb, b); err != nil {
+		return bytes.Equal(a, b)
+	}
+	return bytes.Equal(ab.Bytes(), bb.Bytes())
+}
+
+func maxNumber(a, b *Number) *Number {
+	switch {
+	case a == nil:
+		return b
+	case b == nil:
+		return a
+	case b.Cmp(*a) > 0:
+		return b
+	default:
+		return a
+	}
+}
+
+func minNumber(a, b *Number) *Number {
+	switch {
+	case a == nil:
+		return b
+	case b == nil:
+		return a
+	case b.Cmp(*a) < 0:
+		return b
+	default:
+		return a
+	}
+}
+
+// mergeMultipleOf returns the stricter of a and b if one is a multiple of the
+// other.
+func mergeMultipleOf(a, b *Number) (*Number, bool) {
+	if a == nil {
+		return b, true
+	}
+	if b == nil {
+		return a, true
+	}
+	ar, ok := a.BigRat()
+	if !ok {
+		return a, false
+	}
+	br, ok := b.BigRat()
+	if !ok || ar.Sign() == 0 || br.Sign() == 0 {
+		return a, false
+	}
+	if new(big.Rat).Quo(ar, br).IsInt() {
+		return a, true
+	}
+	if new(big.Rat).Quo(br, ar).IsInt() {
+		return b, true
+	}
+	return a, false
+}
@@ -0,0 +1,109 @@
+package openapi
+
+import "github.com/chanced/jsonx"
+
+// keywordNullable is the OpenAPI 3.0 keyword which permits null. JSON Schema
+// 2020-12, as used by OpenAPI 3.1, does not define it, so it is retained in
+// the Keywords of a Schema.
+const keywordNullable Text = "nullable"
+
+var jsonNull = jsonx.RawMessage("null")
+
+// SetNullable sets whether s permits null, using the representation s
+// already uses:
+//
+//   - if s has an OpenAPI 3.0 "nullable" keyword, it is set
+//   - otherwise, if s has a type, "null" is added to or removed from it
+//   - otherwise, if s has an anyOf or oneOf, a branch of {"type": "null"} is
+//     added to or removed from it
+//   - otherwise, if s has a $ref, it is moved into an anyOf alongside a
+//     branch of {"type": "null"}
+//
+// If s has an enum, null is added to or removed from it. When nullable is
+// false, inline anyOf and oneOf branches are made non-nullable as well;
+// Schemas which s references, either by $ref or through a branch, are not
+// modified, so s may remain nullable (see IsNullable).
+func (s *Schema) SetNullable(nullable bool) {
+	if s == nil {
+		return
+	}
+	if nullable {
+		s.setNullable()
+	} else {
+		s.unsetNullable()
+	}
+}
+
+func (s *Schema) setNullable() {
+	switch {
+	case len(s.Enum) > 0:
+		if !s.Enum.Contains(jsonNull) {
+			s.Enum = append(s.Enum, jsonNull)
+		}
+	case s.Const != nil && !jsonEqual(s.Const, jsonNull):
+		s.Enum = Enum{s.Const, jsonNull}
+		s.Const = nil
+	}
+	if _, ok := s.Keywords[keywordNullable]; ok {
+		s.Keywords[keywordNullable] = jsonx.RawMessage("true")
+		return
+	}
+	if len(s.Type) > 0 {
+		s.Type.Add(TypeNull)
+		return
+	}
+	for _, ss := range []*SchemaSlice{s.AnyOf, s.OneOf} {
+		if ss == nil || len(ss.Items) == 0 {
+			continue
+		}
+		for _, b := range ss.Items {
+			if b.IsNullable() {
+				return
+			}
+		}
+		ss.Items = append(ss.Items, &Schema{Type: Types{TypeNull}})
+		return
+	}
+	if s.Ref != nil {
+		s.AnyOf = &SchemaSlice{Items: []*Schema{
+			{Ref: s.Ref},
+			{Type: Types{TypeNull}},
+		}}
+		s.Ref = nil
+	}
+}
+
+func (s *Schema) unsetNullable() {
+	delete(s.Keywords, keywordNullable)
+	s.Type.Remove(TypeNull)
+	if i := s.Enum.Index(jsonNull); i >= 0 {
+		s.Enum = append(s.Enum[:i], s.Enum[i+1:]...)
+	}
+	for _, ss := range []**SchemaSlice{&s.AnyOf, &s.OneOf} {
+		if *ss == nil {
+			continue
+		}
+		items := (*ss).Items[:0]
+		for _, b := range (*ss).Items {
+			if isNullSchema(b) {
+				continue
+			}
+			if b != nil && b.Ref == nil {
+				b.unsetNullable()
+			}
+			items = append(items, b)
+		}
+		(*ss).Items = items
+		if len(items) == 0 {
+			*ss = nil
+		}
+	}
+}
+
+// isNullSchema reports whether s is an inline Schema which only permits null
+func isNullSchema(s *Schema) bool {
+	if s == nil || s.Ref != nil {
+		return false
+	}
+	return (len(s.Type) == 1 && s.Type[0] == TypeNull) || (s.Const != nil && jsonEqual(s.Const, jsonNull))
+}
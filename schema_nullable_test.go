@@ -0,0 +1,97 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/chanced/openapi"
+)
+
+func TestSetNullable(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   string
+		nullable string
+		cleared  string
+	}{
+		{
+			name:     "type",
+			schema:   `{"type":"string"}`,
+			nullable: `{"type":["string","null"]}`,
+			cleared:  `{"type":"string"}`,
+		},
+		{
+			name:     "nullable keyword",
+			schema:   `{"type":"string","nullable":false}`,
+			nullable: `{"type":"string","nullable":true}`,
+			cleared:  `{"type":"string"}`,
+		},
+		{
+			name:     "anyOf",
+			schema:   `{"anyOf":[{"type":"string"},{"type":"integer"}]}`,
+			nullable: `{"anyOf":[{"type":"string"},{"type":"integer"},{"type":"null"}]}`,
+			cleared:  `{"anyOf":[{"type":"string"},{"type":"integer"}]}`,
+		},
+		{
+			name:     "enum",
+			schema:   `{"type":"string","enum":["a","b"]}`,
+			nullable: `{"type":["string","null"],"enum":["a","b",null]}`,
+			cleared:  `{"type":"string","enum":["a","b"]}`,
+		},
+		{
+			name:     "ref",
+			schema:   `{"$ref":"#/components/schemas/Pet"}`,
+			nullable: `{"anyOf":[{"$ref":"#/components/schemas/Pet"},{"type":"null"}]}`,
+			cleared:  `{"anyOf":[{"$ref":"#/components/schemas/Pet"}]}`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var s openapi.Schema
+			if err := json.Unmarshal([]byte(test.schema), &s); err != nil {
+				t.Fatal(err)
+			}
+			if s.IsNullable() {
+				t.Error("expected schema not to be nullable")
+			}
+			s.SetNullable(true)
+			assertSchemaJSON(t, &s, test.nullable)
+			if !s.IsNullable() {
+				t.Error("expected schema to be nullable")
+			}
+			s.SetNullable(false)
+			assertSchemaJSON(t, &s, test.cleared)
+			if s.IsNullable() {
+				t.Error("expected schema not to be nullable")
+			}
+		})
+	}
+
+	var s openapi.Schema
+	if err := json.Unmarshal([]byte(`{"type":["string","null"],"enum":["a"]}`), &s); err != nil {
+		t.Fatal(err)
+	}
+	if s.IsNullable() {
+		t.Error("expected an enum without null to prevent the schema from being nullable")
+	}
+}
+
+func assertSchemaJSON(t *testing.T, s *openapi.Schema, expected string) {
+	t.Helper()
+	b, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var a, e interface{}
+	if err = json.Unmarshal(b, &a); err != nil {
+		t.Fatal(err)
+	}
+	if err = json.Unmarshal([]byte(expected), &e); err != nil {
+		t.Fatal(err)
+	}
+	ab, _ := json.Marshal(a)
+	eb, _ := json.Marshal(e)
+	if string(ab) != string(eb) {
+		t.Errorf("expected %s, got %s", eb, ab)
+	}
+}
@@ -0,0 +1,447 @@
+package openapi
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/chanced/jsonpointer"
+)
+
+// SchemaContradiction describes keywords of a Schema which can not be
+// satisfied together, such as a minimum greater than the maximum or a
+// required property which is prohibited.
+type SchemaContradiction struct {
+	// Location of the Schema containing the contradiction
+	Location
+	// Pointer is the JSON pointer of the Schema, relative to the Schema
+	// IsSatisfiable was called on (e.g. "/properties/age").
+	Pointer jsonpointer.Pointer
+	// Keyword which can not be satisfied (e.g. "minimum", "required", "type")
+	Keyword Text
+	// Reason describes the contradiction.
+	Reason string
+}
+
+func (c SchemaContradiction) Error() string {
+	ptr := c.Pointer.String()
+	if ptr == "" {
+		ptr = "/"
+	}
+	return fmt.Sprintf("openapi: %q of %s can not be satisfied: %s", c.Keyword, ptr, c.Reason)
+}
+
+// IsSatisfiable decides whether any value is permitted by s and reports the
+// contradictions found in s and each of its inline subschemas (see
+// Schema.Contradictions).
+//
+// The result is DecisionNo if s is false, if the types, enums, or consts of s
+// and its resolved references and allOf branches do not intersect, if none
+// of the types s permits can satisfy the keywords specific to the type, or
+// if no branch of its anyOf or oneOf can be satisfied. The result is
+// DecisionYes if a value permitted by s is found, drawn from its enumerated
+// values, defaults, and examples along with a value synthesized for each
+// type s permits. Otherwise, the result is DecisionUnknown.
+//
+// As contradictions within subschemas, such as an optional property which
+// can not be satisfied, need not prevent s from being satisfied, s may be
+// satisfiable while contradictions are reported.
+func (s *Schema) IsSatisfiable() (Decision, []SchemaContradiction) {
+	c := newSatisfiabilityChecker()
+	res := c.analyze(s)
+	contradictions := append([]SchemaContradiction(nil), res.contradictions...)
+	_ = s.WalkSubschemas(func(ptr jsonpointer.Pointer, sub *Schema) error {
+		for _, x := range c.analyze(sub).contradictions {
+			x.Pointer = ptr
+			contradictions = append(contradictions, x)
+		}
+		return nil
+	})
+	return res.decision, contradictions
+}
+
+// Contradictions returns the contradictions between the keywords of s, along
+// with those of its resolved references and allOf branches, which are:
+//
+//   - types, enums, or consts which do not intersect
+//   - a const which is not one of the values of enum
+//   - an enum or const whose values are each rejected by the other keywords
+//   - a minimum greater than the maximum, or a range which contains no
+//     multiple of multipleOf (or no integer, if s permits only integers)
+//   - a minLength, minProperties, minItems, or minContains greater than its
+//     maximum
+//   - a required property whose schema can not be satisfied, which is
+//     prohibited by additionalProperties, or which is rejected by
+//     propertyNames
+//   - more required properties than maxProperties
+//   - a minItems or minContains which requires items that can not be
+//     satisfied
+//   - an anyOf or oneOf without any branch which can be satisfied
+//
+// Contradictions which are reported by a resolved reference or allOf branch
+// of s are not repeated. Contradictions of keywords specific to a type, such
+// as minimum, are reported even if s permits values of other types.
+//
+// The subschemas of s are not checked; see IsSatisfiable.
+func (s *Schema) Contradictions() []SchemaContradiction {
+	return newSatisfiabilityChecker().analyze(s).contradictions
+}
+
+type satisfiabilityChecker struct {
+	results  map[*Schema]*satisfiability
+	visiting map[*Schema]bool
+}
+
+type satisfiability struct {
+	decision       Decision
+	contradictions []SchemaContradiction
+	// unsatisfiable is true if any contradiction prevents every value
+	unsatisfiable bool
+}
+
+func newSatisfiabilityChecker() *satisfiabilityChecker {
+	return &satisfiabilityChecker{
+		results:  map[*Schema]*satisfiability{},
+		visiting: map[*Schema]bool{},
+	}
+}
+
+func (c *satisfiabilityChecker) analyze(s *Schema) *satisfiability {
+	switch {
+	case s == nil || s.IsAlways():
+		return &satisfiability{decision: DecisionYes}
+	case s.IsNever():
+		return &satisfiability{decision: DecisionNo}
+	}
+	if r, ok := c.results[s]; ok {
+		return r
+	}
+	if c.visiting[s] {
+		// s is circular; the result is not recorded so that it may be
+		// decided once the cycle unwinds
+		return &satisfiability{decision: DecisionUnknown}
+	}
+	c.visiting[s] = true
+	defer delete(c.visiting, s)
+
+	r := &satisfiability{decision: DecisionUnknown}
+	c.contradict(s, r)
+	if r.unsatisfiable {
+		r.decision = DecisionNo
+	} else {
+		for _, w := range schemaWitnesses(s) {
+			if permits(s, w, 0) == DecisionYes {
+				r.decision = DecisionYes
+				break
+			}
+		}
+	}
+	c.results[s] = r
+	return r
+}
+
+// impossible reports whether s is shown to permit no value
+func (c *satisfiabilityChecker) impossible(s *Schema) bool {
+	return s.IsNever() || c.analyze(s).decision == DecisionNo
+}
+
+// describe returns the reason the impossible Schema s permits no value
+func describe(s *Schema) string {
+	if s.IsNever() {
+		return "is false"
+	}
+	return "can not be satisfied"
+}
+
+func (c *satisfiabilityChecker) contradict(s *Schema, r *satisfiability) {
+	merged, conflicts, ok := mergeEffective(s)
+	if !ok {
+		return
+	}
+	var found []SchemaContradiction
+	add := func(loc Location, keyword Text, unsatisfiable bool, format string, args ...interface{}) {
+		reason := fmt.Sprintf(format, args...)
+		for _, x := range found {
+			if x.Keyword == keyword && x.Reason == reason {
+				return
+			}
+		}
+		found = append(found, SchemaContradiction{Location: loc, Keyword: keyword, Reason: reason})
+		if unsatisfiable {
+			r.unsatisfiable = true
+		}
+	}
+	for _, x := range conflicts {
+		switch x.Keyword {
+		case "type", "enum", "const":
+			add(x.Location, x.Keyword, true, "%s", x.Reason)
+		}
+	}
+	if merged.IsNever() {
+		// a reference or allOf branch is false, which is not a contradiction
+		r.unsatisfiable = true
+	} else {
+		c.contradictValues(s, merged, add)
+		if !c.contradictTypes(s, merged, add) {
+			r.unsatisfiable = true
+		}
+		c.contradictBranches(s, merged, add)
+	}
+
+	// contradictions of the parts of s are reported by the parts
+	var parts []*Schema
+	if s.Ref != nil && s.Ref.Resolved != nil {
+		parts = append(parts, s.Ref.Resolved)
+	}
+	if s.AllOf != nil {
+		parts = append(parts, s.AllOf.Items...)
+	}
+	reported := map[string]bool{}
+	for _, p := range parts {
+		for _, x := range c.analyze(p).contradictions {
+			reported[x.Keyword.String()+"\x00"+x.Reason] = true
+		}
+	}
+	for _, x := range found {
+		if !reported[x.Keyword.String()+"\x00"+x.Reason] {
+			r.contradictions = append(r.contradictions, x)
+		}
+	}
+}
+
+type addContradiction func(loc Location, keyword Text, unsatisfiable bool, format string, args ...interface{})
+
+func (c *satisfiabilityChecker) contradictValues(s, merged *Schema, add addContradiction) {
+	if merged.Const != nil && len(merged.Enum) > 0 && !enumContainsCanonical(merged.Enum, canonicalJSON(merged.Const)) {
+		add(s.Location, "const", true, "const %s is not one of the values of enum", merged.Const)
+		return
+	}
+	if merged.Const == nil && len(merged.Enum) == 0 {
+		return
+	}
+	values := merged.Enum
+	if merged.Const != nil {
+		values = Enum{merged.Const}
+	}
+	for _, data := range values {
+		v, ok := decodeInstance(data)
+		if !ok || permits(merged, v, 0) != DecisionNo {
+			return
+		}
+	}
+	keyword := valuesKeyword(merged)
+	if len(values) == 1 {
+		add(s.Location, keyword, true, "%s is rejected by the other keywords", values[0])
+	} else {
+		add(s.Location, keyword, true, "each value of %s is rejected by the other keywords", keyword)
+	}
+}
+
+// contradictTypes reports the keywords which can not be satisfied by values
+// of each type permitted by merged, returning whether any of the types may be
+// satisfied
+func (c *satisfiabilityChecker) contradictTypes(s, merged *Schema, add addContradiction) bool {
+	types := schemaTypes(merged)
+	if types == nil {
+		types = Types{TypeNull, TypeBoolean, TypeObject, TypeArray, TypeNumber, TypeString}
+	}
+	type contradiction struct {
+		keyword Text
+		reason  string
+	}
+	var found []contradiction
+	open := false
+	for _, t := range types {
+		if t == TypeInteger && types.ContainsNumber() {
+			continue
+		}
+		var keyword Text
+		var reason string
+		switch t {
+		case TypeNumber, TypeInteger:
+			keyword, reason = numberContradiction(merged, t == TypeInteger)
+		case TypeString:
+			keyword, reason = stringContradiction(merged)
+		case TypeObject:
+			keyword, reason = c.objectContradiction(merged)
+		case TypeArray:
+			keyword, reason = c.arrayContradiction(merged)
+		}
+		if keyword == "" {
+			open = true
+			continue
+		}
+		found = append(found, contradiction{keyword, reason})
+	}
+	for _, x := range found {
+		add(s.Location, x.keyword, !open, "%s", x.reason)
+	}
+	return open
+}
+
+func numberContradiction(s *Schema, integer bool) (Text, string) {
+	if _, d := numberWitness(s, integer); d != DecisionNo {
+		return "", ""
+	}
+	var parts []string
+	for _, kw := range []struct {
+		keyword Text
+		n       *Number
+	}{
+		{"minimum", s.Minimum},
+		{"exclusiveMinimum", s.ExclusiveMinimum},
+		{"maximum", s.Maximum},
+		{"exclusiveMaximum", s.ExclusiveMaximum},
+		{"multipleOf", s.MultipleOf},
+	} {
+		if kw.n != nil {
+			parts = append(parts, fmt.Sprintf("%s %s", kw.keyword, *kw.n))
+		}
+	}
+	noun := "number"
+	if integer {
+		noun = "integer"
+	}
+	desc := parts[len(parts)-1]
+	if len(parts) > 1 {
+		desc = strings.Join(parts[:len(parts)-1], ", ") + " and " + desc
+	}
+	reason := fmt.Sprintf("no %s satisfies %s", noun, desc)
+
+	r := *s
+	r.MultipleOf = nil
+	switch _, d := numberWitness(&r, false); {
+	case d == DecisionNo && s.Minimum != nil:
+		return "minimum", reason
+	case d == DecisionNo:
+		return "exclusiveMinimum", reason
+	case s.MultipleOf != nil:
+		return "multipleOf", reason
+	default:
+		return "type", reason
+	}
+}
+
+func stringContradiction(s *Schema) (Text, string) {
+	if s.MinLength != nil && s.MaxLength != nil && s.MinLength.Cmp(*s.MaxLength) > 0 {
+		return "minLength", fmt.Sprintf("minLength %s is greater than maxLength %s", *s.MinLength, *s.MaxLength)
+	}
+	return "", ""
+}
+
+func (c *satisfiabilityChecker) objectContradiction(s *Schema) (Text, string) {
+	if s.MinProperties != nil && s.MaxProperties != nil && s.MinProperties.Cmp(*s.MaxProperties) > 0 {
+		return "minProperties", fmt.Sprintf("minProperties %s is greater than maxProperties %s", *s.MinProperties, *s.MaxProperties)
+	}
+	if s.MaxProperties != nil && IntNumber(int64(len(s.Required))).Cmp(*s.MaxProperties) > 0 {
+		return "required", fmt.Sprintf("%d properties are required but maxProperties is %s", len(s.Required), *s.MaxProperties)
+	}
+	for _, name := range s.Required {
+		if reason := c.requiredContradiction(s, name); reason != "" {
+			return "required", reason
+		}
+	}
+	return "", ""
+}
+
+// requiredContradiction returns the reason the required property name of s
+// can not be present, if any
+func (c *satisfiabilityChecker) requiredContradiction(s *Schema, name Text) string {
+	if s.PropertyNames != nil && permits(s.PropertyNames, name.String(), 0) == DecisionNo {
+		return fmt.Sprintf("property %q is required but is rejected by propertyNames", name)
+	}
+	if s.Properties != nil {
+		if ps := s.Properties.Get(name); ps != nil {
+			if c.impossible(ps) {
+				return fmt.Sprintf("property %q is required but its schema %s", name, describe(ps))
+			}
+			return ""
+		}
+	}
+	matched := false
+	if s.PatternProperties != nil {
+		for _, item := range s.PatternProperties.Items {
+			re, err := CompilePattern(item.Key.String(), PatternLenient)
+			if err != nil || !re.IsCompiled() || !re.MatchString(name.String()) {
+				continue
+			}
+			matched = true
+			if c.impossible(item.Schema) {
+				return fmt.Sprintf("property %q is required but the schema of patternProperties %q %s", name, item.Key, describe(item.Schema))
+			}
+		}
+	}
+	if !matched && s.AdditionalProperties != nil && c.impossible(s.AdditionalProperties) {
+		return fmt.Sprintf("property %q is required but additionalProperties %s", name, describe(s.AdditionalProperties))
+	}
+	return ""
+}
+
+func (c *satisfiabilityChecker) arrayContradiction(s *Schema) (Text, string) {
+	minItems, hasMin := s.keywordNumber("minItems")
+	maxItems, hasMax := s.keywordNumber("maxItems")
+	if hasMin && hasMax && minItems.Cmp(maxItems) > 0 {
+		return "minItems", fmt.Sprintf("minItems %s is greater than maxItems %s", minItems, maxItems)
+	}
+	if s.Contains != nil {
+		minContains := IntNumber(1)
+		if s.MinContains != nil {
+			minContains = *s.MinContains
+		}
+		if s.MaxContains != nil && minContains.Cmp(*s.MaxContains) > 0 {
+			return "minContains", fmt.Sprintf("minContains %s is greater than maxContains %s", minContains, *s.MaxContains)
+		}
+		if hasMax && minContains.Cmp(maxItems) > 0 {
+			return "minContains", fmt.Sprintf("minContains %s is greater than maxItems %s", minContains, maxItems)
+		}
+		if minContains.Cmp(IntNumber(0)) > 0 && c.impossible(s.Contains) {
+			return "contains", fmt.Sprintf("contains %s", describe(s.Contains))
+		}
+	}
+	if !hasMin {
+		return "", ""
+	}
+	var prefix []*Schema
+	if s.PrefixItems != nil {
+		prefix = s.PrefixItems.Items
+	}
+	for i, p := range prefix {
+		if IntNumber(int64(i)).Cmp(minItems) >= 0 {
+			return "", ""
+		}
+		if c.impossible(p) {
+			return "prefixItems", fmt.Sprintf("minItems is %s but prefixItems/%d %s", minItems, i, describe(p))
+		}
+	}
+	if s.Items != nil && IntNumber(int64(len(prefix))).Cmp(minItems) < 0 && c.impossible(s.Items) {
+		return "items", fmt.Sprintf("minItems is %s but items %s", minItems, describe(s.Items))
+	}
+	return "", ""
+}
+
+func (c *satisfiabilityChecker) contradictBranches(s, merged *Schema, add addContradiction) {
+	for _, kw := range []struct {
+		keyword  Text
+		branches *SchemaSlice
+	}{
+		{"anyOf", merged.AnyOf},
+		{"oneOf", merged.OneOf},
+	} {
+		if kw.branches == nil || len(kw.branches.Items) == 0 {
+			continue
+		}
+		possible := false
+		for _, b := range kw.branches.Items {
+			if c.impossible(b) {
+				continue
+			}
+			if eb, ok := effectiveSchema(b); ok && schemasDisjoint(merged, eb) == DecisionYes {
+				continue
+			}
+			possible = true
+			break
+		}
+		if !possible {
+			add(s.Location, kw.keyword, true, "no branch of %s can be satisfied", kw.keyword)
+		}
+	}
+}
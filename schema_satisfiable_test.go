@@ -0,0 +1,131 @@
+package openapi_test
+
+import (
+	"testing"
+
+	"github.com/chanced/jsonpointer"
+	"github.com/chanced/openapi"
+)
+
+func TestSchemaIsSatisfiable(t *testing.T) {
+	type contradiction struct {
+		ptr     jsonpointer.Pointer
+		keyword openapi.Text
+	}
+	tests := []struct {
+		name           string
+		schema         string
+		expected       openapi.Decision
+		contradictions []contradiction
+	}{
+		{
+			name:     "satisfiable",
+			schema:   `{ "type": "object", "required": ["id"], "properties": { "id": { "type": "integer", "minimum": 1 } } }`,
+			expected: openapi.DecisionYes,
+		},
+		{
+			name:           "minimum greater than maximum",
+			schema:         `{ "type": "number", "minimum": 10, "maximum": 5 }`,
+			expected:       openapi.DecisionNo,
+			contradictions: []contradiction{{"", "minimum"}},
+		},
+		{
+			name:           "untyped minimum greater than maximum",
+			schema:         `{ "minimum": 10, "maximum": 5 }`,
+			expected:       openapi.DecisionYes,
+			contradictions: []contradiction{{"", "minimum"}},
+		},
+		{
+			name:           "no integer in range",
+			schema:         `{ "type": "integer", "exclusiveMinimum": 1, "exclusiveMaximum": 2 }`,
+			expected:       openapi.DecisionNo,
+			contradictions: []contradiction{{"", "type"}},
+		},
+		{
+			name:     "number in range",
+			schema:   `{ "type": "number", "exclusiveMinimum": 1, "exclusiveMaximum": 2 }`,
+			expected: openapi.DecisionYes,
+		},
+		{
+			name:           "no multiple in range",
+			schema:         `{ "type": "integer", "minimum": 1, "maximum": 4, "multipleOf": 5 }`,
+			expected:       openapi.DecisionNo,
+			contradictions: []contradiction{{"", "multipleOf"}},
+		},
+		{
+			name:           "required property rejected by propertyNames",
+			schema:         `{ "type": "object", "required": ["identifier"], "propertyNames": { "maxLength": 4 } }`,
+			expected:       openapi.DecisionNo,
+			contradictions: []contradiction{{"", "required"}},
+		},
+		{
+			name:           "required property prohibited by additionalProperties",
+			schema:         `{ "type": "object", "required": ["b"], "properties": { "a": {} }, "additionalProperties": false }`,
+			expected:       openapi.DecisionNo,
+			contradictions: []contradiction{{"", "required"}},
+		},
+		{
+			name:           "required property can not be satisfied",
+			schema:         `{ "type": "object", "required": ["a"], "properties": { "a": { "type": "string", "minLength": 3, "maxLength": 1 } } }`,
+			expected:       openapi.DecisionNo,
+			contradictions: []contradiction{{"", "required"}, {"/properties/a", "minLength"}},
+		},
+		{
+			name:           "optional property can not be satisfied",
+			schema:         `{ "type": "object", "properties": { "a": { "type": "string", "minLength": 3, "maxLength": 1 } } }`,
+			expected:       openapi.DecisionYes,
+			contradictions: []contradiction{{"/properties/a", "minLength"}},
+		},
+		{
+			name:           "const not in enum",
+			schema:         `{ "const": "c", "enum": ["a", "b"] }`,
+			expected:       openapi.DecisionNo,
+			contradictions: []contradiction{{"", "const"}},
+		},
+		{
+			name:           "enum rejected",
+			schema:         `{ "type": "string", "enum": [1, 2] }`,
+			expected:       openapi.DecisionNo,
+			contradictions: []contradiction{{"", "enum"}},
+		},
+		{
+			name:           "allOf of disjoint types",
+			schema:         `{ "allOf": [{ "type": "string" }, { "type": "integer" }] }`,
+			expected:       openapi.DecisionNo,
+			contradictions: []contradiction{{"", "type"}},
+		},
+		{
+			name:           "nested allOf of disjoint types",
+			schema:         `{ "allOf": [{ "allOf": [{ "type": "string" }, { "type": "integer" }] }] }`,
+			expected:       openapi.DecisionNo,
+			contradictions: []contradiction{{"/allOf/0", "type"}},
+		},
+		{
+			name:           "anyOf without a satisfiable branch",
+			schema:         `{ "type": "object", "anyOf": [{ "type": "string" }, { "type": "array", "minItems": 2, "maxItems": 1 }] }`,
+			expected:       openapi.DecisionNo,
+			contradictions: []contradiction{{"", "anyOf"}, {"/anyOf/1", "minItems"}},
+		},
+		{
+			name:     "false",
+			schema:   `false`,
+			expected: openapi.DecisionNo,
+		},
+	}
+	for _, test := range tests {
+		s := mustSchema(t, test.schema)
+		d, contradictions := s.IsSatisfiable()
+		if d != test.expected {
+			t.Errorf("%s: expected %s, got %s", test.name, test.expected, d)
+		}
+		if len(contradictions) != len(test.contradictions) {
+			t.Errorf("%s: expected contradictions %v, got %v", test.name, test.contradictions, contradictions)
+			continue
+		}
+		for i, c := range contradictions {
+			if c.Pointer != test.contradictions[i].ptr || c.Keyword != test.contradictions[i].keyword {
+				t.Errorf("%s: expected contradiction %d to be %v, got %v", test.name, i, test.contradictions[i], c)
+			}
+		}
+	}
+}
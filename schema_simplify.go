@@ -0,0 +1,237 @@
+package openapi
+
+import (
+	"fmt"
+)
+
+// SchemaRewrite describes a redundant construct which was removed by
+// Schema.Simplify.
+type SchemaRewrite struct {
+	// Location of the Schema which was rewritten
+	Location
+	// Keyword which was rewritten
+	Keyword Text
+	// Reason describes the rewrite
+	Reason string
+}
+
+func (r SchemaRewrite) String() string {
+	return fmt.Sprintf("%s: %s: %s", r.AbsoluteLocation(), r.Keyword, r.Reason)
+}
+
+// Simplify returns a new Schema, equivalent to s, with redundant constructs
+// removed from s and each of its inline subschemas. s is not modified. Each
+// rewrite is reported as a SchemaRewrite, in the order it was made.
+//
+// The following rewrites are made:
+//
+//   - an allOf with a single branch is merged into the Schema containing it,
+//     unless doing so would result in a SchemaConflict (see MergeAllOf)
+//   - duplicate entries of enum are removed
+//   - branches of anyOf and oneOf which can not be satisfied, either because
+//     they are false or because their type does not intersect with the type
+//     of the Schema containing them, are removed, provided at least one
+//     branch remains
+//   - a branch of anyOf or oneOf which consists only of a nested anyOf or
+//     oneOf, respectively, is replaced by the nested branches
+//
+// Flattening a nested oneOf assumes that the nested branches are mutually
+// exclusive, as is the case for discriminated unions; otherwise a value
+// matching both a nested branch and an outer branch would be treated
+// differently.
+//
+// Subschemas which are referenced via $ref are left in place, though the
+// type of a resolved $ref is considered when removing branches.
+func (s *Schema) Simplify() (*Schema, []SchemaRewrite) {
+	sm := &schemaSimplifier{visiting: map[*Schema]bool{}}
+	return sm.simplify(s), sm.rewrites
+}
+
+type schemaSimplifier struct {
+	visiting map[*Schema]bool
+	rewrites []SchemaRewrite
+}
+
+func (sm *schemaSimplifier) rewrite(s *Schema, keyword Text, format string, args ...interface{}) {
+	sm.rewrites = append(sm.rewrites, SchemaRewrite{
+		Location: s.Location,
+		Keyword:  keyword,
+		Reason:   fmt.Sprintf(format, args...),
+	})
+}
+
+func (sm *schemaSimplifier) simplify(s *Schema) *Schema {
+	if s == nil {
+		return nil
+	}
+	if sm.visiting[s] {
+		return s
+	}
+	sm.visiting[s] = true
+	defer delete(sm.visiting, s)
+
+	res := s.shallowClone()
+
+	simplifyMap := func(m *SchemaMap) *SchemaMap {
+		if m == nil {
+			return nil
+		}
+		c := &SchemaMap{Location: m.Location, Items: make([]SchemaItem, len(m.Items))}
+		for i, item := range m.Items {
+			c.Items[i] = SchemaItem{Key: item.Key, Schema: sm.simplify(item.Schema)}
+		}
+		return c
+	}
+	simplifySlice := func(ss *SchemaSlice) *SchemaSlice {
+		if ss == nil {
+			return nil
+		}
+		c := &SchemaSlice{Location: ss.Location, Items: make([]*Schema, len(ss.Items))}
+		for i, item := range ss.Items {
+			c.Items[i] = sm.simplify(item)
+		}
+		return c
+	}
+	res.Properties = simplifyMap(res.Properties)
+	res.PatternProperties = simplifyMap(res.PatternProperties)
+	res.DependentSchemas = simplifyMap(res.DependentSchemas)
+	res.Definitions = simplifyMap(res.Definitions)
+	res.AllOf = simplifySlice(res.AllOf)
+	res.AnyOf = simplifySlice(res.AnyOf)
+	res.OneOf = simplifySlice(res.OneOf)
+	res.PrefixItems = simplifySlice(res.PrefixItems)
+	res.AdditionalProperties = sm.simplify(res.AdditionalProperties)
+	res.PropertyNames = sm.simplify(res.PropertyNames)
+	res.UnevaluatedProperties = sm.simplify(res.UnevaluatedProperties)
+	res.Items = sm.simplify(res.Items)
+	res.AdditionalItems = sm.simplify(res.AdditionalItems)
+	res.UnevaluatedItems = sm.simplify(res.UnevaluatedItems)
+	res.Contains = sm.simplify(res.Contains)
+	res.Not = sm.simplify(res.Not)
+	res.If = sm.simplify(res.If)
+	res.Then = sm.simplify(res.Then)
+	res.Else = sm.simplify(res.Else)
+
+	res = sm.collapseAllOf(res)
+	res.AnyOf = sm.simplifyBranches(res, "anyOf", res.AnyOf)
+	res.OneOf = sm.simplifyBranches(res, "oneOf", res.OneOf)
+	res.Enum = sm.dedupeEnum(res, res.Enum)
+	return res
+}
+
+// collapseAllOf merges the branch of s's allOf into s if it is the only one
+func (sm *schemaSimplifier) collapseAllOf(s *Schema) *Schema {
+	if s.AllOf == nil || len(s.AllOf.Items) != 1 {
+		return s
+	}
+	b := s.AllOf.Items[0]
+	if b == nil {
+		return s
+	}
+	m := newSchemaMerger()
+	res := s.shallowClone()
+	res.AllOf = nil
+	// the branch is merged as is, rather than through schemaMerger.branch,
+	// so that a $ref is retained rather than replaced by its target
+	m.merge(res, b)
+	m.checkBounds(res)
+	if len(m.conflicts) > 0 {
+		return s
+	}
+	sm.rewrite(s, "allOf", "merged single-element allOf")
+	return res
+}
+
+// simplifyBranches removes the unsatisfiable branches of the anyOf or oneOf
+// ss of s and flattens nested branches of the same keyword
+func (sm *schemaSimplifier) simplifyBranches(s *Schema, keyword Text, ss *SchemaSlice) *SchemaSlice {
+	if ss == nil || len(ss.Items) == 0 {
+		return ss
+	}
+	items := make([]*Schema, 0, len(ss.Items))
+	for _, b := range ss.Items {
+		if nested := nestedBranches(b, keyword); nested != nil {
+			sm.rewrite(b, keyword, "flattened nested %s", keyword)
+			items = append(items, nested.Items...)
+			continue
+		}
+		items = append(items, b)
+	}
+
+	possible := make([]*Schema, 0, len(items))
+	var impossible []*Schema
+	var reasons []string
+	for _, b := range items {
+		if reason := impossibleBranch(s, b); reason != "" {
+			impossible = append(impossible, b)
+			reasons = append(reasons, reason)
+			continue
+		}
+		possible = append(possible, b)
+	}
+	if len(possible) > 0 {
+		for i, b := range impossible {
+			sm.rewrite(b, keyword, "removed impossible branch: %s", reasons[i])
+		}
+		items = possible
+	}
+	return &SchemaSlice{Location: ss.Location, Items: items}
+}
+
+// nestedBranches returns the anyOf or oneOf, per keyword, of b if b consists
+// of nothing else
+func nestedBranches(b *Schema, keyword Text) *SchemaSlice {
+	if b == nil {
+		return nil
+	}
+	c := *b
+	var nested *SchemaSlice
+	switch keyword {
+	case "anyOf":
+		nested, c.AnyOf = c.AnyOf, nil
+	case "oneOf":
+		nested, c.OneOf = c.OneOf, nil
+	}
+	if nested == nil || len(nested.Items) == 0 || !c.isBlank() {
+		return nil
+	}
+	return nested
+}
+
+// impossibleBranch returns the reason b can not be satisfied as a branch of
+// s, if any
+func impossibleBranch(s, b *Schema) string {
+	if b == nil {
+		return ""
+	}
+	if b.IsNever() {
+		return "schema is false"
+	}
+	types := b.Type
+	if len(types) == 0 && b.Ref != nil && b.Ref.Resolved != nil {
+		types = b.Ref.Resolved.Type
+	}
+	if len(s.Type) > 0 && len(types) > 0 && len(intersectTypes(s.Type, types)) == 0 {
+		return fmt.Sprintf("type %v does not intersect with %v", types, s.Type)
+	}
+	return ""
+}
+
+// dedupeEnum returns e without duplicate values
+func (sm *schemaSimplifier) dedupeEnum(s *Schema, e Enum) Enum {
+	if len(e) < 2 {
+		return e
+	}
+	seen := make(map[string]bool, len(e))
+	res := make(Enum, 0, len(e))
+	for _, v := range e {
+		k := canonicalJSON(v)
+		if seen[k] {
+			sm.rewrite(s, "enum", "removed duplicate value %s", v)
+			continue
+		}
+		seen[k] = true
+		res = append(res, v)
+	}
+	return res
+}
@@ -0,0 +1,118 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/chanced/openapi"
+)
+
+func TestSchemaSimplify(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   string
+		expected string
+		rewrites []openapi.Text
+	}{
+		{
+			name:     "single allOf",
+			schema:   `{ "description": "pet", "allOf": [{ "type": "object", "required": ["name"] }] }`,
+			expected: `{ "type": "object", "description": "pet", "required": ["name"] }`,
+			rewrites: []openapi.Text{"allOf"},
+		},
+		{
+			name:     "single allOf with $ref",
+			schema:   `{ "allOf": [{ "$ref": "#/$defs/pet" }], "$defs": { "pet": { "type": "object" } } }`,
+			expected: `{ "$ref": "#/$defs/pet", "$defs": { "pet": { "type": "object" } } }`,
+			rewrites: []openapi.Text{"allOf"},
+		},
+		{
+			name:     "conflicting allOf",
+			schema:   `{ "type": "string", "allOf": [{ "type": "integer" }] }`,
+			expected: `{ "type": "string", "allOf": [{ "type": "integer" }] }`,
+		},
+		{
+			name:     "duplicate enum",
+			schema:   `{ "enum": ["a", "b", "a", 1, 1.0] }`,
+			expected: `{ "enum": ["a", "b", 1] }`,
+			rewrites: []openapi.Text{"enum", "enum"},
+		},
+		{
+			name:     "impossible branches",
+			schema:   `{ "type": "object", "oneOf": [{ "type": "string" }, { "type": "object" }, false] }`,
+			expected: `{ "type": "object", "oneOf": [{ "type": "object" }] }`,
+			rewrites: []openapi.Text{"oneOf", "oneOf"},
+		},
+		{
+			name:     "all branches impossible",
+			schema:   `{ "type": "object", "anyOf": [{ "type": "string" }] }`,
+			expected: `{ "type": "object", "anyOf": [{ "type": "string" }] }`,
+		},
+		{
+			name:     "nested oneOf",
+			schema:   `{ "oneOf": [{ "oneOf": [{ "type": "string" }, { "type": "integer" }] }, { "type": "boolean" }] }`,
+			expected: `{ "oneOf": [{ "type": "string" }, { "type": "integer" }, { "type": "boolean" }] }`,
+			rewrites: []openapi.Text{"oneOf"},
+		},
+		{
+			name:     "nested oneOf with keywords",
+			schema:   `{ "oneOf": [{ "title": "n", "oneOf": [{ "type": "string" }] }, { "type": "boolean" }] }`,
+			expected: `{ "oneOf": [{ "title": "n", "oneOf": [{ "type": "string" }] }, { "type": "boolean" }] }`,
+		},
+		{
+			name:     "subschemas",
+			schema:   `{ "properties": { "tags": { "items": { "allOf": [{ "enum": ["x", "x"] }] } } } }`,
+			expected: `{ "properties": { "tags": { "items": { "enum": ["x"] } } } }`,
+			rewrites: []openapi.Text{"enum", "allOf"},
+		},
+	}
+	for _, test := range tests {
+		var s openapi.Schema
+		if err := json.Unmarshal([]byte(test.schema), &s); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		original, err := json.Marshal(&s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, rewrites := s.Simplify()
+		if len(rewrites) != len(test.rewrites) {
+			t.Errorf("%s: expected rewrites %v, got %v", test.name, test.rewrites, rewrites)
+		} else {
+			for i, r := range rewrites {
+				if r.Keyword != test.rewrites[i] {
+					t.Errorf("%s: expected rewrite %d to be of %q, got %v", test.name, i, test.rewrites[i], r)
+				}
+			}
+		}
+		assertJSONEqual(t, test.name, test.expected, res)
+
+		after, err := json.Marshal(&s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(after) != string(original) {
+			t.Errorf("%s: expected s to be unmodified, got %s", test.name, after)
+		}
+	}
+}
+
+func assertJSONEqual(t *testing.T, name string, expected string, v interface{}) {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	var e, a interface{}
+	if err := json.Unmarshal([]byte(expected), &e); err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	if err := json.Unmarshal(data, &a); err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	ed, _ := json.Marshal(e)
+	ad, _ := json.Marshal(a)
+	if string(ed) != string(ad) {
+		t.Errorf("%s: expected %s, got %s", name, ed, ad)
+	}
+}
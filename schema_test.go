@@ -1,7 +1,11 @@
 package openapi_test
 
 import (
+	"context"
 	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/chanced/openapi"
@@ -62,3 +66,228 @@ func TestClone(t *testing.T) {
 		t.Error("expected resolved schema, got nil")
 	}
 }
+
+func TestSchemaMergeAllOf(t *testing.T) {
+	var s openapi.Schema
+	err := json.Unmarshal([]byte(`{
+		"title": "Combined",
+		"allOf": [
+			{ "type": ["number", "string"], "minimum": 1, "maximum": 10, "required": ["a"],
+			  "properties": { "a": { "type": "string", "minLength": 2 } } },
+			{ "type": "integer", "minimum": 3, "maximum": 20, "required": ["b"],
+			  "properties": { "a": { "maxLength": 5 }, "b": { "type": "boolean" } } }
+		]
+	}`), &s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, conflicts := s.MergeAllOf()
+	if len(conflicts) != 0 {
+		t.Errorf("expected no conflicts, got %v", conflicts)
+	}
+	if m.AllOf != nil {
+		t.Error("expected allOf to be removed")
+	}
+	if len(m.Type) != 1 || m.Type[0] != openapi.TypeInteger {
+		t.Errorf("expected type [integer], got %v", m.Type)
+	}
+	if m.Minimum.String() != "3" || m.Maximum.String() != "10" {
+		t.Errorf("expected minimum 3 and maximum 10, got %s and %s", m.Minimum, m.Maximum)
+	}
+	if len(m.Required) != 2 {
+		t.Errorf("expected 2 required, got %v", m.Required)
+	}
+	a := m.Properties.Get("a")
+	if a == nil || a.MinLength.String() != "2" || a.MaxLength.String() != "5" {
+		t.Errorf("expected property a to be merged, got %+v", a)
+	}
+	if m.Properties.Get("b") == nil {
+		t.Error("expected property b")
+	}
+	if m.Title != "Combined" {
+		t.Errorf("expected title to be retained, got %q", m.Title)
+	}
+	if s.AllOf == nil || s.Properties != nil {
+		t.Error("expected receiver to be unmodified")
+	}
+
+	var c openapi.Schema
+	err = json.Unmarshal([]byte(`{
+		"allOf": [
+			{ "type": "string", "format": "email", "minLength": 10 },
+			{ "type": "number", "format": "uri", "maxLength": 5 }
+		]
+	}`), &c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, conflicts = c.MergeAllOf()
+	kw := map[openapi.Text]bool{}
+	for _, c := range conflicts {
+		kw[c.Keyword] = true
+	}
+	for _, k := range []openapi.Text{"type", "format", "minLength"} {
+		if !kw[k] {
+			t.Errorf("expected conflict for %q, got %v", k, conflicts)
+		}
+	}
+
+	var r openapi.Schema
+	err = json.Unmarshal([]byte(`{
+		"allOf": [
+			{ "required": ["a"] },
+			{ "properties": { "a": { "not": {} } } }
+		]
+	}`), &r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, conflicts = r.MergeAllOf(); len(conflicts) != 1 || conflicts[0].Keyword != "required" {
+		t.Errorf("expected a conflict for a required property which is false, got %v", conflicts)
+	}
+}
+
+func TestSchemaFlatten(t *testing.T) {
+	var s openapi.Schema
+	err := json.Unmarshal([]byte(`{
+		"properties": {
+			"nested": { "allOf": [ { "required": ["x"] }, { "required": ["y"] } ] }
+		}
+	}`), &s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, conflicts := s.Flatten()
+	if len(conflicts) != 0 {
+		t.Errorf("expected no conflicts, got %v", conflicts)
+	}
+	n := f.Properties.Get("nested")
+	if n.AllOf != nil || len(n.Required) != 2 {
+		t.Errorf("expected nested allOf to be flattened, got %+v", n)
+	}
+}
+
+func TestSchemaEffective(t *testing.T) {
+	var s openapi.Schema
+	err := json.Unmarshal([]byte(`{
+		"allOf": [
+			{ "type": ["object", "null"], "required": ["id"], "properties": { "id": { "type": "string" } } },
+			{ "required": ["name"], "properties": { "name": { "type": "string" } } }
+		],
+		"properties": { "age": { "type": "integer" } }
+	}`), &s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if types := s.EffectiveTypes(); len(types) != 2 {
+		t.Errorf("expected [object null], got %v", types)
+	}
+	if !s.IsNullable() {
+		t.Error("expected schema to be nullable")
+	}
+	if req := s.EffectiveRequired(); len(req) != 2 {
+		t.Errorf("expected 2 required, got %v", req)
+	}
+	names := s.EffectivePropertyNames()
+	if len(names) != 3 || names[0] != "age" || names[1] != "id" || names[2] != "name" {
+		t.Errorf("expected [age id name], got %v", names)
+	}
+
+	var n openapi.Schema
+	if err = json.Unmarshal([]byte(`{ "type": "string", "nullable": true }`), &n); err != nil {
+		t.Fatal(err)
+	}
+	if !n.IsNullable() {
+		t.Error("expected nullable keyword to be honored")
+	}
+}
+
+func TestSchemaCompile(t *testing.T) {
+	ctx := context.Background()
+	f, err := testdata.Open("testdata/documents/petstore.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := openapi.Load(ctx, "https://documents/petstore.yaml", NoopValidator{}, func(ctx context.Context, uri uri.URI, kind openapi.Kind) (openapi.Kind, []byte, error) {
+		b, err := io.ReadAll(f)
+		return openapi.KindDocument, b, err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cs, err := doc.Components.Schemas.Get("Pet").Compile(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		instance string
+		valid    bool
+	}{
+		{`{ "name": "fido", "_id": "1" }`, true},
+		{`{ "_id": "1" }`, false},
+		{`{ "name": "fido" }`, false},
+		{`{ "name": "fido", "_id": "1", "age": 3 }`, false},
+	}
+	for _, test := range tests {
+		var v interface{}
+		if err = json.Unmarshal([]byte(test.instance), &v); err != nil {
+			t.Fatal(err)
+		}
+		err = cs.Validate(v)
+		if test.valid && err != nil {
+			t.Errorf("expected %s to be valid, got %v", test.instance, err)
+		}
+		if !test.valid && err == nil {
+			t.Errorf("expected %s to be invalid", test.instance)
+		}
+	}
+}
+
+func BenchmarkSchemaUnmarshalJSON(b *testing.B) {
+	files, err := filepath.Glob("testdata/schemas/*.json")
+	if err != nil {
+		b.Fatal(err)
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run(filepath.Base(file), func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(data)))
+			for i := 0; i < b.N; i++ {
+				var s openapi.Schema
+				if err := json.Unmarshal(data, &s); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestSchemaExtensionsRoundTrip(t *testing.T) {
+	data := []byte(`{"type":"string","x-go-type":"Name","x-order":{"index":1,"tags":["a","b"]}}`)
+	var s openapi.Schema
+	if err := json.Unmarshal(data, &s); err != nil {
+		t.Fatal(err)
+	}
+	b, err := s.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var expected, actual map[string]interface{}
+	if err = json.Unmarshal(data, &expected); err != nil {
+		t.Fatal(err)
+	}
+	if err = json.Unmarshal(b, &actual); err != nil {
+		t.Fatalf("expected valid JSON, got %v: %s", err, b)
+	}
+	for _, key := range []string{"x-go-type", "x-order"} {
+		e, _ := json.Marshal(expected[key])
+		a, _ := json.Marshal(actual[key])
+		if string(e) != string(a) {
+			t.Errorf("expected %s to be %s, got %s", key, e, a)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package openapi
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/chanced/jsonx"
+)
+
+// unmarshalSchemaJSONObj is the encoding/json based decoder which
+// Schema.unmarshalJSONObj replaced. The results of both are compared by
+// TestSchemaUnmarshalJSONObj.
+func unmarshalSchemaJSONObj(data []byte) (Schema, error) {
+	res := Schema{}
+	d := map[Text]jsonx.RawMessage{}
+	if err := json.Unmarshal(data, &d); err != nil {
+		return Schema{}, err
+	}
+	for k, v := range d {
+		if f := res.field(k.String()); f != nil {
+			if err := json.Unmarshal(v, f); err != nil {
+				return Schema{}, err
+			}
+		} else if strings.HasPrefix(k.String(), "x-") {
+			if res.Extensions == nil {
+				res.Extensions = Extensions{}
+			}
+			res.Extensions[k] = v
+		} else {
+			if res.Keywords == nil {
+				res.Keywords = make(map[Text]jsonx.RawMessage)
+			}
+			res.Keywords[k] = v
+		}
+	}
+	if res.Ref != nil {
+		res.Ref.SchemaRefKind = SchemaRefTypeRef
+	}
+	if res.DynamicRef != nil {
+		res.DynamicRef.SchemaRefKind = SchemaRefTypeDynamic
+	}
+	if res.RecursiveRef != nil {
+		res.RecursiveRef.SchemaRefKind = SchemaRefTypeRecursive
+	}
+	return res, nil
+}
+
+func TestSchemaUnmarshalJSONObj(t *testing.T) {
+	files, err := filepath.Glob("testdata/schemas/*.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	inputs := map[string][]byte{
+		"scalars":    []byte(`{"type":"string","title":"t","description":"d","format":"uuid","$comment":"c","readOnly":true,"deprecated":false,"uniqueItems":true}`),
+		"types":      []byte(`{"type":["string","null"],"minLength":1,"maxLength":5,"pattern":"^a"}`),
+		"nested":     []byte(`{"type":"object","properties":{"a":{"type":"integer","minimum":1.5},"b":{"$ref":"#/$defs/b"}},"$defs":{"b":true},"required":["a"]}`),
+		"keywords":   []byte(`{"x-order":1,"x-go-type":{"name":"T"},"unknown":[1,2],"$dynamicRef":"#node","$recursiveRef":"#"}`),
+		"escaped":    []byte(`{"title":"a\"bé","x-a":"x"}`),
+		"whitespace": []byte(" {\n\t\"type\" : \"number\" ,\"multipleOf\" : 0.5 }\n"),
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		inputs[filepath.Base(file)] = data
+	}
+	for name, data := range inputs {
+		t.Run(name, func(t *testing.T) {
+			want, err := unmarshalSchemaJSONObj(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got Schema
+			if err = got.unmarshalJSONObj(data); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				gb, _ := json.Marshal(got)
+				wb, _ := json.Marshal(want)
+				t.Errorf("expected:\n%s\ngot:\n%s", wb, gb)
+			}
+		})
+	}
+}
+
+func TestSchemaUnmarshalJSONObjErrors(t *testing.T) {
+	inputs := map[string][]byte{
+		"missing value":     []byte(`{"type":"string","minLength": }`),
+		"trailing comma":    []byte(`{"type":"string",}`),
+		"unterminated":      []byte(`{"type":"string"`),
+		"unquoted key":      []byte(`{type:"string"}`),
+		"trailing data":     []byte(`{"type":"string"} {}`),
+		"invalid field":     []byte(`{"minLength":"five"}`),
+		"invalid nested":    []byte(`{"properties":{"a":{"type":7}}}`),
+		"invalid ref":       []byte(`{"$ref":1}`),
+		"invalid type list": []byte(`{"type":[1]}`),
+	}
+	for name, data := range inputs {
+		t.Run(name, func(t *testing.T) {
+			_, want := unmarshalSchemaJSONObj(data)
+			if want == nil {
+				t.Fatal("expected the encoding/json decoder to fail")
+			}
+			var s Schema
+			got := s.unmarshalJSONObj(data)
+			if got == nil {
+				t.Fatalf("expected error %q", want)
+			}
+			if got.Error() != want.Error() {
+				t.Errorf("expected error %q, got %q", want, got)
+			}
+			if reflect.TypeOf(got) != reflect.TypeOf(want) {
+				t.Errorf("expected error of type %T, got %T", want, got)
+			}
+		})
+	}
+}
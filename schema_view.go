@@ -0,0 +1,202 @@
+package openapi
+
+import "fmt"
+
+// refChain returns s followed by the Schemas of its chain of resolved $refs.
+// The chain ends at a Schema without a $ref, an unresolved $ref, or a cycle.
+func (s *Schema) refChain() []*Schema {
+	var chain []*Schema
+	seen := map[*Schema]bool{}
+	for s != nil && !seen[s] {
+		seen[s] = true
+		chain = append(chain, s)
+		if s.Ref == nil {
+			break
+		}
+		s = s.Ref.Resolved
+	}
+	return chain
+}
+
+// ResolvedRef returns the Schema referenced by the $ref of s, following
+// chains of $refs until reaching a Schema without one. If a chain of $refs
+// forms a cycle, the last Schema before the cycle is returned.
+//
+// If s does not have a $ref or the $ref has not been resolved, nil is
+// returned.
+func (s *Schema) ResolvedRef() *Schema {
+	chain := s.refChain()
+	if len(chain) < 2 {
+		return nil
+	}
+	return chain[len(chain)-1]
+}
+
+// MustResolve returns the Schema referenced by the $ref of s, as
+// ResolvedRef does, or s if it does not have a $ref.
+//
+// MustResolve panics if s, or a Schema it references, has a $ref which has
+// not been resolved.
+func (s *Schema) MustResolve() *Schema {
+	chain := s.refChain()
+	if len(chain) == 0 {
+		return nil
+	}
+	last := chain[len(chain)-1]
+	if last.Ref != nil && last.Ref.Resolved == nil {
+		panic(fmt.Sprintf("openapi: $ref %q of %s has not been resolved", last.Ref.Ref.String(), last.AbsoluteLocation().String()))
+	}
+	return last
+}
+
+// SchemaView is a read-only view of a Schema which transparently follows
+// resolved $refs. Each accessor consults the Schema and then, in order, the
+// Schemas of its chain of $refs, returning the first value found. As a
+// result, keywords adjacent to a $ref take precedence over those of the
+// referenced Schema.
+//
+// The zero value is an empty view.
+type SchemaView struct {
+	schema *Schema
+	parent *SchemaView
+}
+
+// SchemaViewProperty is a property of a SchemaView.
+type SchemaViewProperty struct {
+	Key    Text
+	Schema SchemaView
+}
+
+// View returns a SchemaView of s.
+func (s *Schema) View() SchemaView {
+	return SchemaView{schema: s}
+}
+
+func (v SchemaView) child(s *Schema) SchemaView {
+	if s == nil {
+		return SchemaView{}
+	}
+	p := v
+	return SchemaView{schema: s, parent: &p}
+}
+
+// IsNil reports whether the view is of a nil Schema.
+func (v SchemaView) IsNil() bool { return v.schema == nil }
+
+// Schema returns the Schema of the view, which may have a $ref.
+func (v SchemaView) Schema() *Schema { return v.schema }
+
+// Resolved returns the Schema of the view with its $refs followed (see
+// Schema.ResolvedRef). If the Schema does not have a resolved $ref, it is
+// returned as is.
+func (v SchemaView) Resolved() *Schema {
+	if r := v.schema.ResolvedRef(); r != nil {
+		return r
+	}
+	return v.schema
+}
+
+// Parent returns the view from which v was obtained, if any.
+func (v SchemaView) Parent() (SchemaView, bool) {
+	if v.parent == nil {
+		return SchemaView{}, false
+	}
+	return *v.parent, true
+}
+
+// IsCyclic reports whether the resolved Schema of v is also the resolved
+// Schema of one of its ancestors, indicating that descending further would
+// revisit the same Schemas.
+func (v SchemaView) IsCyclic() bool {
+	if v.schema == nil {
+		return false
+	}
+	r := v.Resolved()
+	for p := v.parent; p != nil; p = p.parent {
+		if p.Resolved() == r {
+			return true
+		}
+	}
+	return false
+}
+
+// Type returns the types of the first Schema of the view's chain which
+// specifies any.
+func (v SchemaView) Type() Types {
+	for _, s := range v.schema.refChain() {
+		if len(s.Type) > 0 {
+			return s.Type
+		}
+	}
+	return nil
+}
+
+// Property returns a view of the property key.
+func (v SchemaView) Property(key Text) SchemaView {
+	for _, s := range v.schema.refChain() {
+		if s.Properties == nil {
+			continue
+		}
+		if p := s.Properties.Get(key); p != nil {
+			return v.child(p)
+		}
+	}
+	return SchemaView{}
+}
+
+// Properties returns views of the properties of the view's chain of Schemas.
+// Properties of a Schema take precedence over, and are ordered before, those
+// of the Schemas it references.
+func (v SchemaView) Properties() []SchemaViewProperty {
+	var props []SchemaViewProperty
+	seen := map[Text]bool{}
+	for _, s := range v.schema.refChain() {
+		if s.Properties == nil {
+			continue
+		}
+		for _, item := range s.Properties.Items {
+			if seen[item.Key] {
+				continue
+			}
+			seen[item.Key] = true
+			props = append(props, SchemaViewProperty{Key: item.Key, Schema: v.child(item.Schema)})
+		}
+	}
+	return props
+}
+
+// Items returns a view of the items of the view.
+func (v SchemaView) Items() SchemaView {
+	for _, s := range v.schema.refChain() {
+		if s.Items != nil {
+			return v.child(s.Items)
+		}
+	}
+	return SchemaView{}
+}
+
+// PrefixItems returns views of the prefixItems of the view.
+func (v SchemaView) PrefixItems() []SchemaView {
+	for _, s := range v.schema.refChain() {
+		if s.PrefixItems == nil {
+			continue
+		}
+		res := make([]SchemaView, len(s.PrefixItems.Items))
+		for i, item := range s.PrefixItems.Items {
+			res[i] = v.child(item)
+		}
+		return res
+	}
+	return nil
+}
+
+// AdditionalProperties returns a view of the additionalProperties of the
+// view.
+func (v SchemaView) AdditionalProperties() SchemaView {
+	for _, s := range v.schema.refChain() {
+		if s.AdditionalProperties != nil {
+			return v.child(s.AdditionalProperties)
+		}
+	}
+	return SchemaView{}
+}
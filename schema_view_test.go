@@ -0,0 +1,78 @@
+package openapi_test
+
+import (
+	"testing"
+
+	"github.com/chanced/openapi"
+	"github.com/chanced/uri"
+)
+
+func TestSchemaView(t *testing.T) {
+	data := []byte(`{
+		"openapi": "3.1.0",
+		"info": { "title": "schema view", "version": "1.0.0" },
+		"paths": {},
+		"components": {
+			"schemas": {
+				"Node": {
+					"type": "object",
+					"properties": {
+						"name": { "type": "string" },
+						"children": { "type": "array", "items": { "$ref": "#/components/schemas/Node" } }
+					}
+				},
+				"Alias": { "$ref": "#/components/schemas/Node" },
+				"Root": {
+					"$ref": "#/components/schemas/Alias",
+					"properties": { "name": { "type": "integer" } }
+				}
+			}
+		}
+	}`)
+	doc := loadDocument(t, "https://example.com/openapi.json", data)
+	schemas := doc.Components.Schemas
+	node, root := schemas.Get("Node"), schemas.Get("Root")
+
+	if r := root.ResolvedRef(); r != node {
+		t.Errorf("expected Root to resolve to Node, got %+v", r)
+	}
+	if r := node.ResolvedRef(); r != nil {
+		t.Errorf("expected nil, got %+v", r)
+	}
+	if r := node.MustResolve(); r != node {
+		t.Error("expected MustResolve to return Node")
+	}
+
+	v := root.View()
+	if !v.Type().ContainsObject() {
+		t.Errorf("expected object, got %v", v.Type())
+	}
+	if !v.Property("name").Type().ContainsInteger() {
+		t.Error("expected the adjacent property to take precedence")
+	}
+	if props := v.Properties(); len(props) != 2 || props[0].Key != "name" || props[1].Key != "children" {
+		t.Errorf("unexpected properties: %+v", props)
+	}
+	child := v.Property("children").Items()
+	if child.Resolved() != node {
+		t.Error("expected items to resolve to Node")
+	}
+	if !child.IsCyclic() {
+		t.Error("expected items of children to be cyclic")
+	}
+	if grandchild := child.Property("children").Items(); grandchild.Resolved() != node {
+		t.Error("expected views to follow cycles")
+	}
+	if missing := v.Property("missing"); !missing.IsNil() {
+		t.Error("expected a nil view")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected MustResolve to panic for an unresolved $ref")
+		}
+	}()
+	ref := uri.MustParse("#/components/schemas/Missing")
+	s := &openapi.Schema{Ref: &openapi.SchemaRef{Ref: ref}}
+	s.MustResolve()
+}
@@ -0,0 +1,131 @@
+package openapi
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/chanced/jsonpointer"
+)
+
+// SkipSubschemas may be returned by the function passed to
+// Schema.WalkSubschemas to skip the subschemas of the Schema it was called
+// with. It is not returned as an error by WalkSubschemas.
+var SkipSubschemas = errors.New("openapi: skip subschemas")
+
+// WalkSubschemas calls fn with each subschema of s, depth-first, along with
+// the JSON pointer of its keyword location relative to s (e.g.
+// "/properties/name", "/allOf/0/items"). s itself is not visited, nor are
+// the targets of $ref, $dynamicRef, and $recursiveRef.
+//
+// The subschemas of each Schema are visited in the following order: $defs,
+// properties, patternProperties, additionalProperties, propertyNames,
+// dependentSchemas, unevaluatedProperties, prefixItems, items,
+// additionalItems, unevaluatedItems, contains, allOf, anyOf, oneOf, not, if,
+// then, and else. Entries of keyed and indexed keywords are visited in order.
+//
+// If fn returns SkipSubschemas, the subschemas of the Schema passed to fn are
+// skipped. Any other error stops the walk and is returned.
+func (s *Schema) WalkSubschemas(fn func(ptr jsonpointer.Pointer, s *Schema) error) error {
+	if s == nil {
+		return nil
+	}
+	return s.walkSubschemas("", fn)
+}
+
+func (s *Schema) walkSubschemas(ptr jsonpointer.Pointer, fn func(ptr jsonpointer.Pointer, s *Schema) error) error {
+	visit := func(p jsonpointer.Pointer, sub *Schema) error {
+		if sub == nil {
+			return nil
+		}
+		if err := fn(p, sub); err != nil {
+			if errors.Is(err, SkipSubschemas) {
+				return nil
+			}
+			return err
+		}
+		return sub.walkSubschemas(p, fn)
+	}
+	visitMap := func(keyword string, sm *SchemaMap) error {
+		if sm == nil {
+			return nil
+		}
+		p := ptr.AppendString(keyword)
+		for _, item := range sm.Items {
+			if err := visit(p.AppendString(item.Key.String()), item.Schema); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	visitSlice := func(keyword string, ss *SchemaSlice) error {
+		if ss == nil {
+			return nil
+		}
+		p := ptr.AppendString(keyword)
+		for i, sub := range ss.Items {
+			if err := visit(p.AppendString(strconv.Itoa(i)), sub); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	visitSchema := func(keyword string, sub *Schema) error {
+		return visit(ptr.AppendString(keyword), sub)
+	}
+
+	if err := visitMap("$defs", s.Definitions); err != nil {
+		return err
+	}
+	if err := visitMap("properties", s.Properties); err != nil {
+		return err
+	}
+	if err := visitMap("patternProperties", s.PatternProperties); err != nil {
+		return err
+	}
+	if err := visitSchema("additionalProperties", s.AdditionalProperties); err != nil {
+		return err
+	}
+	if err := visitSchema("propertyNames", s.PropertyNames); err != nil {
+		return err
+	}
+	if err := visitMap("dependentSchemas", s.DependentSchemas); err != nil {
+		return err
+	}
+	if err := visitSchema("unevaluatedProperties", s.UnevaluatedProperties); err != nil {
+		return err
+	}
+	if err := visitSlice("prefixItems", s.PrefixItems); err != nil {
+		return err
+	}
+	if err := visitSchema("items", s.Items); err != nil {
+		return err
+	}
+	if err := visitSchema("additionalItems", s.AdditionalItems); err != nil {
+		return err
+	}
+	if err := visitSchema("unevaluatedItems", s.UnevaluatedItems); err != nil {
+		return err
+	}
+	if err := visitSchema("contains", s.Contains); err != nil {
+		return err
+	}
+	if err := visitSlice("allOf", s.AllOf); err != nil {
+		return err
+	}
+	if err := visitSlice("anyOf", s.AnyOf); err != nil {
+		return err
+	}
+	if err := visitSlice("oneOf", s.OneOf); err != nil {
+		return err
+	}
+	if err := visitSchema("not", s.Not); err != nil {
+		return err
+	}
+	if err := visitSchema("if", s.If); err != nil {
+		return err
+	}
+	if err := visitSchema("then", s.Then); err != nil {
+		return err
+	}
+	return visitSchema("else", s.Else)
+}
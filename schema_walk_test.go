@@ -0,0 +1,84 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/chanced/jsonpointer"
+	"github.com/chanced/openapi"
+)
+
+func TestSchemaWalkSubschemas(t *testing.T) {
+	var s openapi.Schema
+	err := json.Unmarshal([]byte(`{
+		"allOf": [{ "$ref": "#/$defs/named" }, { "type": "object" }],
+		"properties": {
+			"a/b": { "type": "string" },
+			"tags": { "type": "array", "items": { "type": "string" } }
+		},
+		"$defs": {
+			"named": { "properties": { "name": { "type": "string" } } }
+		}
+	}`), &s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ptrs []string
+	err = s.WalkSubschemas(func(ptr jsonpointer.Pointer, sub *openapi.Schema) error {
+		ptrs = append(ptrs, ptr.String())
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"/$defs/named",
+		"/$defs/named/properties/name",
+		"/properties/a~1b",
+		"/properties/tags",
+		"/properties/tags/items",
+		"/allOf/0",
+		"/allOf/1",
+	}
+	assertPointers(t, ptrs, expected)
+
+	ptrs = nil
+	err = s.WalkSubschemas(func(ptr jsonpointer.Pointer, sub *openapi.Schema) error {
+		ptrs = append(ptrs, ptr.String())
+		if sub.Properties != nil {
+			return openapi.SkipSubschemas
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertPointers(t, ptrs, []string{"/$defs/named", "/properties/a~1b", "/properties/tags", "/properties/tags/items", "/allOf/0", "/allOf/1"})
+
+	errStop := errors.New("stop")
+	ptrs = nil
+	err = s.WalkSubschemas(func(ptr jsonpointer.Pointer, sub *openapi.Schema) error {
+		ptrs = append(ptrs, ptr.String())
+		if sub.Type.ContainsString() {
+			return errStop
+		}
+		return nil
+	})
+	if !errors.Is(err, errStop) {
+		t.Errorf("expected the error to be returned, got %v", err)
+	}
+	assertPointers(t, ptrs, expected[:2])
+}
+
+func assertPointers(t *testing.T, ptrs []string, expected []string) {
+	t.Helper()
+	if len(ptrs) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, ptrs)
+	}
+	for i, p := range ptrs {
+		if p != expected[i] {
+			t.Errorf("expected pointer %d to be %q, got %q", i, expected[i], p)
+		}
+	}
+}
@@ -0,0 +1,346 @@
+package openapi
+
+import (
+	"encoding/json"
+	"math/big"
+	"strings"
+)
+
+// maxWitnessDepth bounds the nesting of values synthesized as witnesses
+const maxWitnessDepth = 8
+
+// maxWitnessLength bounds the length of strings and arrays synthesized as
+// witnesses
+const maxWitnessLength = 1 << 10
+
+// schemaWitnesses returns candidate values, decoded with
+// json.Decoder.UseNumber, which may be permitted by s: its enumerated values,
+// default, and examples, along with a value synthesized for each type and a
+// value of each type which s may not permit
+func schemaWitnesses(s *Schema) []interface{} {
+	var res []interface{}
+	add := func(data []byte) {
+		if v, ok := decodeInstance(data); ok {
+			res = append(res, v)
+		}
+	}
+	es, ok := effectiveSchema(s)
+	if !ok {
+		es = s
+	}
+	if es.Const != nil {
+		add(es.Const)
+	}
+	for _, v := range es.Enum {
+		add(v)
+	}
+	if es.Default != nil {
+		add(es.Default)
+	}
+	if es.Example != nil {
+		add(es.Example)
+	}
+	for _, v := range es.Examples {
+		add(v)
+	}
+	res = append(res, synthesize(es, 0)...)
+	res = append(res, nil, true, false, json.Number("0"), json.Number("0.5"), json.Number("-1"), "", map[string]interface{}{}, []interface{}{})
+	return res
+}
+
+// witnessFor returns a value permitted by s, if one is readily found, or nil
+func witnessFor(s *Schema, depth int) interface{} {
+	if s == nil {
+		return nil
+	}
+	es, ok := effectiveSchema(s)
+	if !ok {
+		es = s
+	}
+	var candidates []interface{}
+	if es.Const != nil {
+		if v, ok := decodeInstance(es.Const); ok {
+			candidates = append(candidates, v)
+		}
+	}
+	for _, data := range es.Enum {
+		if v, ok := decodeInstance(data); ok {
+			candidates = append(candidates, v)
+		}
+	}
+	candidates = append(candidates, synthesize(es, depth)...)
+	for _, v := range candidates {
+		if permits(s, v, 0) == DecisionYes {
+			return v
+		}
+	}
+	return nil
+}
+
+// synthesize returns a value of each type permitted by the effective Schema
+// s which satisfies the keywords specific to the type, where one is readily
+// found. Keywords which apply to values of any type, such as not, are not
+// considered.
+func synthesize(s *Schema, depth int) []interface{} {
+	if depth > maxWitnessDepth {
+		return nil
+	}
+	var res []interface{}
+	permitted := func(t Type) bool { return schemaPermitsType(s, t) }
+	if permitted(TypeObject) {
+		if v, ok := synthesizeObject(s, depth); ok {
+			res = append(res, v)
+		}
+	}
+	if permitted(TypeArray) {
+		if v, ok := synthesizeArray(s, depth); ok {
+			res = append(res, v)
+		}
+	}
+	if permitted(TypeString) {
+		for _, v := range synthesizeStrings(s) {
+			res = append(res, v)
+		}
+	}
+	if permitted(TypeInteger) {
+		if v, d := numberWitness(s, true); d == DecisionYes {
+			res = append(res, ratNumber(v))
+		}
+	}
+	if permitted(TypeNumber) && (len(s.Type) == 0 || s.Type.ContainsNumber()) {
+		if v, d := numberWitness(s, false); d == DecisionYes {
+			res = append(res, ratNumber(v))
+		}
+	}
+	if permitted(TypeBoolean) {
+		res = append(res, true, false)
+	}
+	if permitted(TypeNull) {
+		res = append(res, nil)
+	}
+	return res
+}
+
+func synthesizeObject(s *Schema, depth int) (map[string]interface{}, bool) {
+	obj := map[string]interface{}{}
+	for _, r := range s.Required {
+		ps := s.AdditionalProperties
+		if s.Properties != nil {
+			if p := s.Properties.Get(r); p != nil {
+				ps = p
+			}
+		}
+		if ps.IsNever() {
+			return nil, false
+		}
+		obj[r.String()] = witnessFor(ps, depth+1)
+	}
+	if s.MinProperties != nil {
+		if n, err := s.MinProperties.Int64(); err == nil && n > int64(len(obj)) && s.Properties != nil {
+			for _, item := range s.Properties.Items {
+				if int64(len(obj)) >= n {
+					break
+				}
+				if _, ok := obj[item.Key.String()]; !ok && !item.Schema.IsNever() {
+					obj[item.Key.String()] = witnessFor(item.Schema, depth+1)
+				}
+			}
+		}
+	}
+	return obj, true
+}
+
+func synthesizeArray(s *Schema, depth int) ([]interface{}, bool) {
+	n := int64(0)
+	if min, ok := s.keywordNumber("minItems"); ok {
+		v, err := min.Int64()
+		if err != nil || v > maxWitnessLength {
+			return nil, false
+		}
+		n = v
+	}
+	var prefix []*Schema
+	if s.PrefixItems != nil {
+		prefix = s.PrefixItems.Items
+	}
+	arr := make([]interface{}, 0, n)
+	for i := int64(0); i < n; i++ {
+		is := s.Items
+		if i < int64(len(prefix)) {
+			is = prefix[i]
+		}
+		if is.IsNever() {
+			return nil, false
+		}
+		arr = append(arr, witnessFor(is, depth+1))
+	}
+	return arr, true
+}
+
+// synthesizeStrings returns the shortest and the longest strings permitted
+// by the length keywords of s, where the longest is readily synthesized
+func synthesizeStrings(s *Schema) []string {
+	n := int64(0)
+	if s.MinLength != nil {
+		v, err := s.MinLength.Int64()
+		if err != nil || v > maxWitnessLength {
+			return nil
+		}
+		n = v
+	}
+	if s.MaxLength != nil && IntNumber(n).Cmp(*s.MaxLength) > 0 {
+		return nil
+	}
+	res := []string{strings.Repeat("a", int(n))}
+	if s.MaxLength != nil {
+		if m, err := s.MaxLength.Int64(); err == nil && m > n && m <= maxWitnessLength {
+			res = append(res, strings.Repeat("a", int(m)))
+		}
+	}
+	return res
+}
+
+// numberRange is the range of numbers permitted by the numeric keywords of
+// a Schema
+type numberRange struct {
+	// lo and hi are the bounds of the range, if any
+	lo, hi         *big.Rat
+	loExcl, hiExcl bool
+	// step is the number of which each permitted number is a multiple, if
+	// any
+	step *big.Rat
+}
+
+// newNumberRange returns the range of numbers permitted by the numeric
+// keywords of s, restricted to integers if integer. ok is false if the
+// keywords are not valid numbers.
+func newNumberRange(s *Schema, integer bool) (r numberRange, ok bool) {
+	for _, b := range []struct {
+		n     *Number
+		lower bool
+		excl  bool
+	}{
+		{s.Minimum, true, false},
+		{s.ExclusiveMinimum, true, true},
+		{s.Maximum, false, false},
+		{s.ExclusiveMaximum, false, true},
+	} {
+		if b.n == nil {
+			continue
+		}
+		v, ok := b.n.BigRat()
+		if !ok {
+			return r, false
+		}
+		switch {
+		case b.lower && (r.lo == nil || v.Cmp(r.lo) > 0 || (v.Cmp(r.lo) == 0 && b.excl)):
+			r.lo, r.loExcl = v, b.excl
+		case !b.lower && (r.hi == nil || v.Cmp(r.hi) < 0 || (v.Cmp(r.hi) == 0 && b.excl)):
+			r.hi, r.hiExcl = v, b.excl
+		}
+	}
+	if s.MultipleOf != nil {
+		v, ok := s.MultipleOf.BigRat()
+		if !ok || v.Sign() <= 0 {
+			return r, false
+		}
+		r.step = v
+	}
+	if integer {
+		if r.step == nil {
+			r.step = big.NewRat(1, 1)
+		} else {
+			// the integer multiples of p/q are the multiples of p
+			r.step = new(big.Rat).SetInt(r.step.Num())
+		}
+	}
+	return r, true
+}
+
+// contains reports whether v is within the bounds of r
+func (r numberRange) contains(v *big.Rat) bool {
+	if r.lo != nil && (v.Cmp(r.lo) < 0 || (r.loExcl && v.Cmp(r.lo) == 0)) {
+		return false
+	}
+	if r.hi != nil && (v.Cmp(r.hi) > 0 || (r.hiExcl && v.Cmp(r.hi) == 0)) {
+		return false
+	}
+	return true
+}
+
+// multiples returns the least and greatest k for which k*step is within r.
+// Unbounded ends are nil.
+func (r numberRange) multiples() (min, max *big.Int) {
+	if r.lo != nil {
+		min = ceilRat(new(big.Rat).Quo(r.lo, r.step)).Num()
+		if r.loExcl && new(big.Rat).Mul(new(big.Rat).SetInt(min), r.step).Cmp(r.lo) == 0 {
+			min.Add(min, big.NewInt(1))
+		}
+	}
+	if r.hi != nil {
+		max = floorRat(new(big.Rat).Quo(r.hi, r.step)).Num()
+		if r.hiExcl && new(big.Rat).Mul(new(big.Rat).SetInt(max), r.step).Cmp(r.hi) == 0 {
+			max.Sub(max, big.NewInt(1))
+		}
+	}
+	return min, max
+}
+
+// numberWitness returns a number permitted by the numeric keywords of s,
+// restricted to integers if integer. DecisionNo is returned if there is
+// none and DecisionUnknown if the keywords are not valid numbers.
+func numberWitness(s *Schema, integer bool) (*big.Rat, Decision) {
+	r, ok := newNumberRange(s, integer)
+	if !ok {
+		return nil, DecisionUnknown
+	}
+	var v *big.Rat
+	switch {
+	case r.step != nil:
+		min, max := r.multiples()
+		k := new(big.Int)
+		switch {
+		case min != nil:
+			k = min
+		case max != nil:
+			k = max
+		}
+		v = new(big.Rat).Mul(new(big.Rat).SetInt(k), r.step)
+	case r.lo != nil && r.loExcl && r.hi != nil:
+		v = new(big.Rat).Quo(new(big.Rat).Add(r.lo, r.hi), big.NewRat(2, 1))
+	case r.lo != nil && r.loExcl:
+		v = new(big.Rat).Add(r.lo, big.NewRat(1, 1))
+	case r.lo != nil:
+		v = new(big.Rat).Set(r.lo)
+	case r.hi != nil && r.hiExcl:
+		v = new(big.Rat).Sub(r.hi, big.NewRat(1, 1))
+	case r.hi != nil:
+		v = new(big.Rat).Set(r.hi)
+	default:
+		v = new(big.Rat)
+	}
+	if !r.contains(v) {
+		return nil, DecisionNo
+	}
+	return v, DecisionYes
+}
+
+func floorRat(r *big.Rat) *big.Rat {
+	// big.Int.Div rounds toward negative infinity for positive divisors,
+	// which the denominator of a big.Rat always is
+	return new(big.Rat).SetInt(new(big.Int).Div(r.Num(), r.Denom()))
+}
+
+func ceilRat(r *big.Rat) *big.Rat {
+	return new(big.Rat).Neg(floorRat(new(big.Rat).Neg(r)))
+}
+
+// ratNumber returns r as a json.Number. Numbers which are not integers are
+// rounded to 12 decimal places.
+func ratNumber(r *big.Rat) json.Number {
+	if r.IsInt() {
+		return json.Number(r.Num().String())
+	}
+	s := strings.TrimRight(r.FloatString(12), "0")
+	return json.Number(strings.TrimSuffix(s, "."))
+}
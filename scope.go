@@ -4,10 +4,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 
 	"github.com/chanced/jsonx"
-	"github.com/chanced/transcode"
 	"github.com/tidwall/gjson"
 	"gopkg.in/yaml.v3"
 )
@@ -99,11 +99,7 @@ func (s Scope) MarshalYAML() (interface{}, error) {
 
 // UnmarshalYAML satisfies gopkg.in/yaml.v3 Unmarshaler interface
 func (s *Scope) UnmarshalYAML(value *yaml.Node) error {
-	v, err := yaml.Marshal(value)
-	if err != nil {
-		return err
-	}
-	j, err := transcode.JSONFromYAML(v)
+	j, err := jsonFromYAMLNode(value)
 	if err != nil {
 		return err
 	}
@@ -247,6 +243,73 @@ func (s *Scopes) Map() map[Text]Text {
 	return m
 }
 
+// Len returns the number of scopes.
+func (s *Scopes) Len() int {
+	if s == nil {
+		return 0
+	}
+	return len(s.Items)
+}
+
+// Keys returns the names of the scopes, in order.
+func (s *Scopes) Keys() []Text {
+	if s == nil {
+		return nil
+	}
+	keys := make([]Text, len(s.Items))
+	for i, v := range s.Items {
+		keys[i] = v.Key
+	}
+	return keys
+}
+
+// Del removes the scope key, if present.
+func (s *Scopes) Del(key Text) {
+	if s == nil {
+		return
+	}
+	for i, v := range s.Items {
+		if v.Key == key {
+			s.Items = append(s.Items[:i], s.Items[i+1:]...)
+			return
+		}
+	}
+}
+
+// Range calls fn for each scope, in order, until fn returns false.
+func (s *Scopes) Range(fn func(key Text, value Text) bool) {
+	if s == nil {
+		return
+	}
+	for _, v := range s.Items {
+		if !fn(v.Key, v.Value) {
+			return
+		}
+	}
+}
+
+// SortByKey orders the scopes by name.
+func (s *Scopes) SortByKey() {
+	if s == nil {
+		return
+	}
+	sort.SliceStable(s.Items, func(i, j int) bool { return s.Items[i].Key < s.Items[j].Key })
+}
+
+// Merge adds each scope of other which s does not have, in order. The
+// descriptions of scopes s already has are kept.
+func (s *Scopes) Merge(other *Scopes) {
+	if s == nil {
+		return
+	}
+	other.Range(func(key, value Text) bool {
+		if !s.Has(key) {
+			s.Items = append(s.Items, &Scope{Key: key, Value: value})
+		}
+		return true
+	})
+}
+
 func (s Scopes) MarshalJSON() ([]byte, error) {
 	b := strings.Builder{}
 	b.WriteByte('{')
@@ -290,12 +353,12 @@ func (s *Scopes) UnmarshalJSON(data []byte) error {
 			return false
 		}
 		s.Items = append(s.Items, &Scope{
-			Key:   Text(key.String()),
+			Key:   intern(key.String()),
 			Value: Text(v),
 		})
 		return true
 	})
-	return nil
+	return err
 }
 
 // UnmarshalYAML satisfies gopkg.in/yaml.v3 Marshaler interface
@@ -314,11 +377,7 @@ func (s Scopes) MarshalYAML() (interface{}, error) {
 
 // UnmarshalYAML satisfies gopkg.in/yaml.v3 Unmarshaler interface
 func (s *Scopes) UnmarshalYAML(value *yaml.Node) error {
-	v, err := yaml.Marshal(value)
-	if err != nil {
-		return err
-	}
-	j, err := transcode.JSONFromYAML(v)
+	j, err := jsonFromYAMLNode(value)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,115 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/chanced/openapi"
+)
+
+func TestScopes(t *testing.T) {
+	var scopes openapi.Scopes
+	if err := json.Unmarshal([]byte(`{"write":"write pets","read":"read pets","admin":"administer"}`), &scopes); err != nil {
+		t.Fatal(err)
+	}
+	if scopes.Len() != 3 {
+		t.Fatalf("expected 3 scopes, got %d", scopes.Len())
+	}
+	scopes.Del("admin")
+	scopes.Del("missing")
+	if keys := scopes.Keys(); !reflect.DeepEqual(keys, []openapi.Text{"write", "read"}) {
+		t.Errorf("expected [write read], got %v", keys)
+	}
+
+	var visited []openapi.Text
+	scopes.Range(func(key, value openapi.Text) bool {
+		visited = append(visited, key)
+		return false
+	})
+	if len(visited) != 1 || visited[0] != "write" {
+		t.Errorf("expected Range to stop after write, got %v", visited)
+	}
+
+	other := openapi.Scopes{}
+	other.Set("read", "overridden")
+	other.Set("delete", "delete pets")
+	scopes.Merge(&other)
+	scopes.SortByKey()
+	data, err := json.Marshal(scopes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := `{"delete":"delete pets","read":"read pets","write":"write pets"}`; string(data) != expected {
+		t.Errorf("expected %s, got %s", expected, data)
+	}
+
+	var nilScopes *openapi.Scopes
+	if nilScopes.Len() != 0 || nilScopes.Keys() != nil {
+		t.Error("expected a nil Scopes to be empty")
+	}
+
+	if err := json.Unmarshal([]byte(`{"read":1}`), &scopes); err == nil {
+		t.Error("expected an error for a non-string scope")
+	}
+}
+
+func TestRequiredScopes(t *testing.T) {
+	data := []byte(`{
+		"openapi": "3.1.0",
+		"info": { "title": "scopes", "version": "1.0.0" },
+		"security": [{ "oauth": ["read"] }],
+		"paths": {
+			"/pets": {
+				"get": { "responses": { "200": { "description": "ok" } } },
+				"post": {
+					"security": [{ "oauth": ["write", "read"] }, { "oauth": ["admin", "write"], "apiKey": [] }],
+					"responses": { "200": { "description": "ok" } }
+				},
+				"head": {
+					"security": [],
+					"responses": { "200": { "description": "ok" } }
+				}
+			}
+		},
+		"components": {
+			"securitySchemes": {
+				"oauth": {
+					"type": "oauth2",
+					"flows": {
+						"clientCredentials": {
+							"tokenUrl": "https://example.com/token",
+							"scopes": { "write": "write pets", "read": "read pets", "admin": "administer" }
+						}
+					}
+				},
+				"apiKey": { "type": "apiKey", "name": "key", "in": "header" }
+			}
+		}
+	}`)
+	doc := loadDocument(t, "https://example.com/scopes.json", data)
+	pets := doc.Paths.Get("/pets")
+
+	if scopes := doc.RequiredScopes(pets.Get); !reflect.DeepEqual(scopes, map[openapi.Text]openapi.Texts{"oauth": {"read"}}) {
+		t.Errorf("expected the top-level security to apply, got %v", scopes)
+	}
+	expected := map[openapi.Text]openapi.Texts{
+		"oauth":  {"write", "read", "admin"},
+		"apiKey": {},
+	}
+	if scopes := doc.RequiredScopes(pets.Post); !reflect.DeepEqual(scopes, expected) {
+		t.Errorf("expected %v, got %v", expected, scopes)
+	}
+	if scopes := doc.RequiredScopes(pets.Head); len(scopes) != 0 {
+		t.Errorf("expected no scopes, got %v", scopes)
+	}
+
+	res, err := doc.MarshalJSONWithOpts(openapi.MarshalOpts{SortScopes: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(res), `{"admin":"administer","read":"read pets","write":"write pets"}`) {
+		t.Errorf("expected the scopes to be sorted, got %s", res)
+	}
+}
@@ -0,0 +1,109 @@
+package openapi
+
+// Credentials are the credentials presented by a client, keyed by the name
+// of the SecurityScheme each satisfies, along with the scopes (or, for
+// schemes other than oauth2 and openIdConnect, roles) granted by each.
+type Credentials map[Text]Texts
+
+// Has reports whether c includes a credential for scheme which grants each of
+// scopes.
+func (c Credentials) Has(scheme Text, scopes ...Text) bool {
+	granted, ok := c[scheme]
+	if !ok {
+		return false
+	}
+	return len(missingScopes(granted, scopes)) == 0
+}
+
+// SecurityEvaluation is the result of EvaluateSecurity.
+type SecurityEvaluation struct {
+	// Satisfied indicates that no security is required or that at least one
+	// of the security requirements is satisfied.
+	Satisfied bool
+
+	// Requirement is the first security requirement which was satisfied. It
+	// is nil if no security is required or none was satisfied.
+	Requirement *SecurityRequirement
+
+	// Authenticated indicates that a credential was presented for each
+	// scheme of at least one of the security requirements, regardless of
+	// scopes. An unsatisfied, unauthenticated evaluation typically warrants
+	// a 401 response while an unsatisfied, authenticated evaluation warrants
+	// a 403.
+	Authenticated bool
+
+	// MissingScopes are the scopes, keyed by the name of the scheme, which
+	// were not granted by the credentials of the first security requirement
+	// to have been authenticated but not satisfied. It is nil if the
+	// evaluation is satisfied or was not authenticated.
+	MissingScopes map[Text]Texts
+}
+
+// EvaluateSecurity evaluates whether creds satisfy reqs. Each scheme of a
+// security requirement must have a credential in creds which grants each of
+// the required scopes for the requirement to be satisfied, while only one of
+// reqs needs to be satisfied. An empty security requirement ({}), which makes
+// security optional, is always satisfied, as is a nil or empty reqs.
+func EvaluateSecurity(reqs *SecurityRequirementSlice, creds Credentials) SecurityEvaluation {
+	if reqs == nil || len(reqs.Items) == 0 {
+		return SecurityEvaluation{Satisfied: true, Authenticated: true}
+	}
+	var res SecurityEvaluation
+	for _, req := range reqs.Items {
+		authenticated := true
+		var missing map[Text]Texts
+		req.Range(func(scheme Text, item *SecurityRequirementItem) bool {
+			granted, ok := creds[scheme]
+			if !ok {
+				authenticated = false
+				return false
+			}
+			var required Texts
+			if item != nil {
+				required = item.Value
+			}
+			if m := missingScopes(granted, required); len(m) > 0 {
+				if missing == nil {
+					missing = map[Text]Texts{}
+				}
+				missing[scheme] = m
+			}
+			return true
+		})
+		if !authenticated {
+			continue
+		}
+		if missing == nil {
+			return SecurityEvaluation{Satisfied: true, Requirement: req, Authenticated: true}
+		}
+		if !res.Authenticated {
+			res.Authenticated = true
+			res.MissingScopes = missing
+		}
+	}
+	return res
+}
+
+// EvaluateSecurity evaluates whether creds satisfy the effective security of
+// op (see Document.EffectiveSecurity and EvaluateSecurity).
+func (d *Document) EvaluateSecurity(op *Operation, creds Credentials) SecurityEvaluation {
+	return EvaluateSecurity(d.EffectiveSecurity(op), creds)
+}
+
+// missingScopes returns the scopes of required which are not in granted
+func missingScopes(granted, required Texts) Texts {
+	var missing Texts
+	for _, r := range required {
+		found := false
+		for _, g := range granted {
+			if g == r {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, r)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,70 @@
+package openapi_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/chanced/openapi"
+)
+
+func TestEvaluateSecurity(t *testing.T) {
+	data := []byte(`{
+		"openapi": "3.1.0",
+		"info": { "title": "security", "version": "1.0.0" },
+		"security": [{ "oauth": ["read"] }],
+		"paths": {
+			"/pets": {
+				"get": { "responses": { "200": { "description": "ok" } } },
+				"post": {
+					"security": [{ "oauth": ["write"], "apiKey": [] }, { "mtls": [] }],
+					"responses": { "200": { "description": "ok" } }
+				},
+				"put": {
+					"security": [{ "oauth": ["write"] }, {}],
+					"responses": { "200": { "description": "ok" } }
+				},
+				"head": {
+					"security": [],
+					"responses": { "200": { "description": "ok" } }
+				}
+			}
+		}
+	}`)
+	doc := loadDocument(t, "https://example.com/security.json", data)
+	pets := doc.Paths.Get("/pets")
+
+	tests := []struct {
+		name          string
+		op            *openapi.Operation
+		creds         openapi.Credentials
+		satisfied     bool
+		authenticated bool
+		missing       map[openapi.Text]openapi.Texts
+	}{
+		{"inherited", pets.Get, openapi.Credentials{"oauth": {"read"}}, true, true, nil},
+		{"inherited missing scope", pets.Get, openapi.Credentials{"oauth": {"write"}}, false, true, map[openapi.Text]openapi.Texts{"oauth": {"read"}}},
+		{"unauthenticated", pets.Get, openapi.Credentials{"apiKey": nil}, false, false, nil},
+		{"and", pets.Post, openapi.Credentials{"oauth": {"read", "write"}, "apiKey": nil}, true, true, nil},
+		{"and partial", pets.Post, openapi.Credentials{"oauth": {"write"}}, false, false, nil},
+		{"or", pets.Post, openapi.Credentials{"mtls": nil}, true, true, nil},
+		{"optional", pets.Put, nil, true, true, nil},
+		{"none", pets.Head, nil, true, true, nil},
+	}
+	for _, test := range tests {
+		res := doc.EvaluateSecurity(test.op, test.creds)
+		if res.Satisfied != test.satisfied || res.Authenticated != test.authenticated {
+			t.Errorf("%s: expected satisfied=%t authenticated=%t, got %+v", test.name, test.satisfied, test.authenticated, res)
+		}
+		if !reflect.DeepEqual(res.MissingScopes, test.missing) {
+			t.Errorf("%s: expected missing scopes %v, got %v", test.name, test.missing, res.MissingScopes)
+		}
+	}
+
+	res := doc.EvaluateSecurity(pets.Post, openapi.Credentials{"mtls": nil})
+	if res.Requirement == nil || res.Requirement.Get("mtls") == nil {
+		t.Errorf("expected the mtls requirement to be satisfied, got %v", res.Requirement)
+	}
+	if !(openapi.Credentials{"oauth": {"read", "write"}}).Has("oauth", "write") {
+		t.Error("expected the credentials to grant write")
+	}
+}
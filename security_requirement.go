@@ -5,7 +5,6 @@ import (
 	"fmt"
 
 	"github.com/chanced/jsonx"
-	"github.com/chanced/transcode"
 	"gopkg.in/yaml.v3"
 )
 
@@ -74,7 +73,7 @@ func (sri *SecurityRequirementItem) UnmarshalJSON(data []byte) error {
 	}
 	t := jsonx.TypeOf(data)
 	switch t {
-	case jsonx.TypeString:
+	case jsonx.TypeString, jsonx.TypeArray:
 		var v Texts
 		err := json.Unmarshal(data, &v)
 		if err != nil {
@@ -116,11 +115,7 @@ func (sri SecurityRequirementItem) MarshalYAML() (interface{}, error) {
 
 // UnmarshalYAML satisfies gopkg.in/yaml.v3 Unmarshaler interface
 func (sri *SecurityRequirementItem) UnmarshalYAML(value *yaml.Node) error {
-	v, err := yaml.Marshal(value)
-	if err != nil {
-		return err
-	}
-	j, err := transcode.JSONFromYAML(v)
+	j, err := jsonFromYAMLNode(value)
 	if err != nil {
 		return err
 	}
@@ -149,6 +144,61 @@ func (sri *SecurityRequirementItem) UnmarshalYAML(value *yaml.Node) error {
 // not otherwise defined or exchanged in-band.
 type SecurityRequirement = ObjMap[*SecurityRequirementItem]
 
+// MergeSecurityScopes returns the union of the scopes of each security scheme
+// named by reqs, keyed by the name of the scheme. Scopes are listed in the
+// order in which they first appear. A scheme which is named but requires no
+// scopes has an empty, non-nil list.
+func MergeSecurityScopes(reqs ...*SecurityRequirement) map[Text]Texts {
+	merged := map[Text]Texts{}
+	seen := map[Text]map[Text]bool{}
+	for _, req := range reqs {
+		req.Range(func(scheme Text, item *SecurityRequirementItem) bool {
+			if seen[scheme] == nil {
+				seen[scheme] = map[Text]bool{}
+				merged[scheme] = Texts{}
+			}
+			if item == nil {
+				return true
+			}
+			for _, scope := range item.Value {
+				if !seen[scheme][scope] {
+					seen[scheme][scope] = true
+					merged[scheme] = append(merged[scheme], scope)
+				}
+			}
+			return true
+		})
+	}
+	return merged
+}
+
+// EffectiveSecurity returns the security requirements which apply to op: the
+// security of op, if declared, or otherwise the top-level security of d. An
+// empty, non-nil result indicates that op does not require security.
+func (d *Document) EffectiveSecurity(op *Operation) *SecurityRequirementSlice {
+	if op != nil && op.Security != nil {
+		return op.Security
+	}
+	if d == nil {
+		return nil
+	}
+	return d.Security
+}
+
+// RequiredScopes returns the union of the scopes, keyed by the name of the
+// security scheme, of the effective security of op (see EffectiveSecurity).
+//
+// As only one of the alternative security requirements needs to be
+// satisfied, the result is the set of scopes a client may need to request in
+// order to call op by any of them.
+func (d *Document) RequiredScopes(op *Operation) map[Text]Texts {
+	sec := d.EffectiveSecurity(op)
+	if sec == nil {
+		return map[Text]Texts{}
+	}
+	return MergeSecurityScopes(sec.Items...)
+}
+
 var (
 	_ node = (*SecuritySchemeMap)(nil)
 
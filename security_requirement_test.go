@@ -0,0 +1,32 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/chanced/openapi"
+)
+
+func TestSecurityRequirementUnmarshalJSON(t *testing.T) {
+	var sr openapi.SecurityRequirement
+	if err := json.Unmarshal([]byte(`{ "oauth": ["read:pets", "write:pets"], "apiKey": [] }`), &sr); err != nil {
+		t.Fatal(err)
+	}
+	if len(sr.Items) != 2 {
+		t.Fatalf("expected 2 schemes, got %d", len(sr.Items))
+	}
+	oauth, apiKey := sr.Items[0], sr.Items[1]
+	if oauth.Key != "oauth" || len(oauth.Value.Value) != 2 || oauth.Value.Value[1] != "write:pets" {
+		t.Errorf("expected the scopes of oauth, got %v", oauth.Value.Value)
+	}
+	if apiKey.Key != "apiKey" || len(apiKey.Value.Value) != 0 {
+		t.Errorf("expected no scopes for apiKey, got %v", apiKey.Value.Value)
+	}
+	b, err := json.Marshal(&sr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"oauth":["read:pets","write:pets"],"apiKey":[]}` {
+		t.Errorf("unexpected JSON %s", b)
+	}
+}
@@ -2,8 +2,11 @@ package openapi
 
 import (
 	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
 
-	"github.com/chanced/transcode"
 	"gopkg.in/yaml.v3"
 )
 
@@ -125,11 +128,7 @@ func (s Server) MarshalYAML() (interface{}, error) {
 
 // UnmarshalYAML satisfies gopkg.in/yaml.v3 Unmarshaler interface
 func (s *Server) UnmarshalYAML(value *yaml.Node) error {
-	v, err := yaml.Marshal(value)
-	if err != nil {
-		return err
-	}
-	j, err := transcode.JSONFromYAML(v)
+	j, err := jsonFromYAMLNode(value)
 	if err != nil {
 		return err
 	}
@@ -139,3 +138,88 @@ func (s *Server) UnmarshalYAML(value *yaml.Node) error {
 func (s *Server) isNil() bool { return s == nil }
 
 var _ node = (*Server)(nil)
+
+// ServerURLOpts configures Document.ServerURL.
+type ServerURLOpts struct {
+	// Server, if set, is used in place of the servers of the Operation, its
+	// PathItem, and the Document. It may contain server variables (e.g.
+	// "https://{region}.example.com").
+	Server string
+	// Variables are the values of server variables, keyed by name. Variables
+	// which are not set use their default value.
+	Variables map[string]string
+	// Base, if set, is used to resolve relative server URLs (e.g. "/v1").
+	// It is typically the URL from which the Document was retrieved.
+	Base *url.URL
+}
+
+var serverVariable = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// ServerURL returns the URL of the server of the Operation e, without a
+// trailing slash. The server is the first of the servers of the Operation,
+// its PathItem, or the Document, in that order, unless opts.Server is set.
+//
+// Server variables are substituted with their value in opts.Variables or,
+// if not set, their default. An error wrapping ErrInvalidServerVariable is
+// returned if a variable has neither or if its value is not one of the enum
+// of the variable.
+func (d *Document) ServerURL(e OperationEntry, opts ServerURLOpts) (string, error) {
+	tmpl := opts.Server
+	var vars *ServerVariableMap
+	if tmpl == "" {
+		candidates := []*ServerSlice{nil, nil, d.Servers}
+		if e.Operation != nil {
+			candidates[0] = e.Operation.Servers
+		}
+		if e.PathItem != nil {
+			candidates[1] = e.PathItem.Servers
+		}
+		for _, servers := range candidates {
+			if servers != nil && len(servers.Items) > 0 && servers.Items[0] != nil {
+				tmpl = servers.Items[0].URL.String()
+				vars = servers.Items[0].Variables
+				break
+			}
+		}
+	}
+	var err error
+	u := serverVariable.ReplaceAllStringFunc(tmpl, func(m string) string {
+		name := m[1 : len(m)-1]
+		var sv *ServerVariable
+		if vars != nil {
+			sv = vars.Get(Text(name))
+		}
+		val, ok := opts.Variables[name]
+		if !ok {
+			if sv == nil {
+				err = fmt.Errorf("%w: no value for %q", ErrInvalidServerVariable, name)
+				return m
+			}
+			return sv.Default.String()
+		}
+		if sv != nil && len(sv.Enum) > 0 && !containsText(sv.Enum, val) {
+			err = fmt.Errorf("%w: value %q of %q is not one of %v", ErrInvalidServerVariable, val, name, sv.Enum)
+		}
+		return val
+	})
+	if err != nil {
+		return "", err
+	}
+	if opts.Base != nil {
+		ref, err := url.Parse(u)
+		if err != nil {
+			return "", fmt.Errorf("openapi: invalid server URL %q: %w", u, err)
+		}
+		u = opts.Base.ResolveReference(ref).String()
+	}
+	return strings.TrimSuffix(u, "/"), nil
+}
+
+func containsText(ts Texts, s string) bool {
+	for _, t := range ts {
+		if t.String() == s {
+			return true
+		}
+	}
+	return false
+}
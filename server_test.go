@@ -0,0 +1,87 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/chanced/openapi"
+)
+
+func TestServerVariableMarshalJSON(t *testing.T) {
+	b, err := json.Marshal(openapi.ServerVariable{Default: "api"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"default":"api"}` {
+		t.Errorf("expected enum to be omitted when empty, got %s", b)
+	}
+}
+
+func TestDocumentServerURL(t *testing.T) {
+	doc := loadDocument(t, "https://example.com/openapi.json", []byte(`{
+		"openapi": "3.1.0",
+		"info": { "title": "Pet Store", "version": "1.0.0" },
+		"servers": [
+			{
+				"url": "https://{env}.example.com:{port}/v1/",
+				"variables": {
+					"env": { "default": "api", "enum": ["api", "staging"] },
+					"port": { "default": "443" }
+				}
+			},
+			{ "url": "https://other.example.com" }
+		],
+		"paths": {
+			"/pets": {
+				"get": { "operationId": "listPets", "responses": { "200": { "description": "ok" } } },
+				"post": {
+					"operationId": "createPet",
+					"servers": [{ "url": "/write" }],
+					"responses": { "201": { "description": "created" } }
+				}
+			},
+			"/owners": {
+				"servers": [{ "url": "https://owners.example.com/" }],
+				"get": { "operationId": "listOwners", "responses": { "200": { "description": "ok" } } }
+			}
+		}
+	}`))
+	ops := map[openapi.Text]openapi.OperationEntry{}
+	for _, e := range doc.Operations() {
+		ops[e.Operation.OperationID] = e
+	}
+	base, _ := url.Parse("https://example.com/openapi.json")
+	tests := []struct {
+		op       openapi.Text
+		opts     openapi.ServerURLOpts
+		expected string
+		err      error
+	}{
+		{"listPets", openapi.ServerURLOpts{}, "https://api.example.com:443/v1", nil},
+		{"listPets", openapi.ServerURLOpts{Variables: map[string]string{"env": "staging", "port": "8443"}}, "https://staging.example.com:8443/v1", nil},
+		{"listPets", openapi.ServerURLOpts{Variables: map[string]string{"env": "prod"}}, "", openapi.ErrInvalidServerVariable},
+		{"listPets", openapi.ServerURLOpts{Server: "https://{region}.example.com"}, "", openapi.ErrInvalidServerVariable},
+		{"listPets", openapi.ServerURLOpts{Server: "https://{region}.example.com", Variables: map[string]string{"region": "eu"}}, "https://eu.example.com", nil},
+		{"listOwners", openapi.ServerURLOpts{}, "https://owners.example.com", nil},
+		{"createPet", openapi.ServerURLOpts{}, "/write", nil},
+		{"createPet", openapi.ServerURLOpts{Base: base}, "https://example.com/write", nil},
+	}
+	for _, test := range tests {
+		u, err := doc.ServerURL(ops[test.op], test.opts)
+		if test.err != nil {
+			if !errors.Is(err, test.err) {
+				t.Errorf("%s %+v: expected %v, got %v", test.op, test.opts, test.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s %+v: %v", test.op, test.opts, err)
+			continue
+		}
+		if u != test.expected {
+			t.Errorf("%s %+v: expected %q, got %q", test.op, test.opts, test.expected, u)
+		}
+	}
+}
@@ -3,7 +3,6 @@ package openapi
 import (
 	"encoding/json"
 
-	"github.com/chanced/transcode"
 	"gopkg.in/yaml.v3"
 )
 
@@ -11,7 +10,7 @@ import (
 type ServerVariable struct {
 	// An enumeration of string values to be used if the substitution options
 	// are from a limited set. The array MUST NOT be empty.
-	Enum Texts `json:"enum"`
+	Enum Texts `json:"enum,omitempty"`
 	// The default value to use for substitution, which SHALL be sent if an
 	// alternate value is not supplied. Note this behavior is different than the
 	// Schema Object's treatment of default values, because in those cases
@@ -96,11 +95,7 @@ func (sv ServerVariable) MarshalYAML() (interface{}, error) {
 
 // UnmarshalYAML satisfies gopkg.in/yaml.v3 Unmarshaler interface
 func (sv *ServerVariable) UnmarshalYAML(value *yaml.Node) error {
-	v, err := yaml.Marshal(value)
-	if err != nil {
-		return err
-	}
-	j, err := transcode.JSONFromYAML(v)
+	j, err := jsonFromYAMLNode(value)
 	if err != nil {
 		return err
 	}
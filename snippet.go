@@ -0,0 +1,278 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"unicode/utf8"
+)
+
+// Languages of the snippet templates registered by NewSnippetRegistry.
+const (
+	// SnippetCurl renders a curl command.
+	SnippetCurl Text = "curl"
+	// SnippetGo renders Go using net/http.
+	SnippetGo Text = "go"
+	// SnippetJavaScript renders JavaScript using fetch.
+	SnippetJavaScript Text = "javascript"
+)
+
+// SnippetRequest is the request of an Operation, as provided to the templates
+// of a SnippetRegistry.
+type SnippetRequest struct {
+	// OperationID is the operationId of the Operation, if any.
+	OperationID Text
+	// Method is the HTTP method of the request (e.g. "GET").
+	Method string
+	// URL is the URL of the request, including its query. It is relative if
+	// the server of the Operation is relative.
+	URL string
+	// Headers of the request, including Content-Type if the request has a
+	// body and Cookie if the Operation has cookie parameters.
+	Headers map[string]string
+	// Body of the request, if any. JSON bodies are indented.
+	Body string
+	// JSON indicates that Body is JSON encoded.
+	JSON bool
+}
+
+// SnippetOpts configures Document.Snippet and Document.SnippetRequest.
+type SnippetOpts struct {
+	// Registry of the snippet templates.
+	//
+	// Defaults to a registry created by NewSnippetRegistry
+	Registry *SnippetRegistry
+	// Server is the URL of the server, overriding the servers of the
+	// Operation, its PathItem, and the Document.
+	Server string
+	// ServerVariables are the values of server variables, keyed by name.
+	// Variables which are not set use their default value.
+	ServerVariables map[string]string
+	// Fixture configures the values of parameters and bodies, which are
+	// chosen as they are for Document.Fixtures.
+	Fixture FixtureOpts
+}
+
+var defaultSnippetRegistry = NewSnippetRegistry()
+
+func (opts SnippetOpts) registry() *SnippetRegistry {
+	if opts.Registry == nil {
+		return defaultSnippetRegistry
+	}
+	return opts.Registry
+}
+
+// SnippetRegistry is a set of text/template templates which render a
+// SnippetRequest, keyed by language. Templates have access to the functions
+// shellQuote, goString, goRaw, and jsString, which quote a string for a POSIX
+// shell, Go (as an interpreted or raw string literal), and JavaScript
+// respectively. It is safe for concurrent use.
+type SnippetRegistry struct {
+	mu        sync.RWMutex
+	templates map[Text]*template.Template
+}
+
+// NewSnippetRegistry returns a SnippetRegistry with templates for
+// SnippetCurl, SnippetGo, and SnippetJavaScript registered.
+func NewSnippetRegistry() *SnippetRegistry {
+	r := &SnippetRegistry{templates: map[Text]*template.Template{}}
+	for lang, text := range snippetTemplates {
+		if err := r.Register(lang, text); err != nil {
+			panic(err)
+		}
+	}
+	return r
+}
+
+// Register parses text as the template of lang, replacing the template
+// previously registered for lang, if any.
+func (r *SnippetRegistry) Register(lang Text, text string) error {
+	tmpl, err := template.New(lang.String()).Funcs(snippetFuncs).Parse(text)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.templates == nil {
+		r.templates = map[Text]*template.Template{}
+	}
+	r.templates[lang] = tmpl
+	return nil
+}
+
+// Languages returns the registered languages, sorted.
+func (r *SnippetRegistry) Languages() []Text {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	langs := make([]Text, 0, len(r.templates))
+	for lang := range r.templates {
+		langs = append(langs, lang)
+	}
+	sort.Slice(langs, func(i, j int) bool { return langs[i] < langs[j] })
+	return langs
+}
+
+// Render executes the template of lang with req. An error wrapping
+// ErrUnknownSnippetLanguage is returned if lang is not registered.
+func (r *SnippetRegistry) Render(lang Text, req *SnippetRequest) (string, error) {
+	r.mu.RLock()
+	tmpl, ok := r.templates[lang]
+	r.mu.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrUnknownSnippetLanguage, lang)
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, req); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// Snippet renders the request of the Operation e in lang with the templates
+// of opts.Registry (see SnippetRequest).
+func (d *Document) Snippet(lang Text, e OperationEntry, opts SnippetOpts) (string, error) {
+	req, err := d.SnippetRequest(e, opts)
+	if err != nil {
+		return "", err
+	}
+	return opts.registry().Render(lang, req)
+}
+
+// SnippetRequest returns the request of the Operation e, with the URL of the
+// first server of the Operation, its PathItem, or the Document, and the
+// parameters and body taken from examples or generated as they are for
+// Document.Fixtures.
+//
+// An error is returned if e is a webhook, as webhooks do not have a path, or
+// if a server variable has neither a value nor a default.
+func (d *Document) SnippetRequest(e OperationEntry, opts SnippetOpts) (*SnippetRequest, error) {
+	if e.Webhook {
+		return nil, fmt.Errorf("openapi: webhook %q does not have a path", e.Key)
+	}
+	op := e.Operation
+	params, err := op.EffectiveParameters(e.PathItem)
+	if err != nil {
+		return nil, err
+	}
+	var body *RequestBody
+	if op.RequestBody != nil {
+		if !op.RequestBody.IsResolved() {
+			return nil, newErrUnresolvedReference(op.RequestBody.Reference)
+		}
+		body = op.RequestBody.Object
+	}
+	server, err := d.ServerURL(e, ServerURLOpts{Server: opts.Server, Variables: opts.ServerVariables})
+	if err != nil {
+		return nil, err
+	}
+	fe := &fixtureEmitter{opts: opts.Fixture, gen: NewInstanceGenerator(opts.Fixture.Generate)}
+	r := FixtureRequest{Headers: map[string]string{}}
+	path, err := fe.request(&r, e.Key, params, body, "")
+	if err != nil {
+		return nil, err
+	}
+	req := &SnippetRequest{
+		OperationID: op.OperationID,
+		Method:      strings.ToUpper(e.Method.String()),
+		URL:         server + path,
+		Headers:     r.Headers,
+	}
+	if r.Body != nil {
+		req.Body, req.JSON = snippetBody(r.Body, r.Headers["Content-Type"])
+	}
+	return req, nil
+}
+
+// snippetBody returns data indented if contentType is JSON, or otherwise the
+// value of data if it is a string
+func snippetBody(data []byte, contentType string) (string, bool) {
+	if !isJSONMediaType(contentType) {
+		var s string
+		if err := json.Unmarshal(data, &s); err == nil {
+			return s, false
+		}
+	}
+	var b bytes.Buffer
+	if err := json.Indent(&b, data, "", "  "); err != nil {
+		return string(data), true
+	}
+	return b.String(), true
+}
+
+var snippetFuncs = template.FuncMap{
+	"shellQuote": shellQuote,
+	"goString":   strconv.Quote,
+	"goRaw":      goRawString,
+	"jsString":   jsString,
+}
+
+// shellQuote quotes s in single quotes for a POSIX shell
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// goRawString returns s as a Go raw string literal, so that multi-line
+// bodies remain legible, or as an interpreted string literal if s contains
+// characters which can not be represented within one
+func goRawString(s string) string {
+	if strings.ContainsAny(s, "`\r") || !utf8.ValidString(s) {
+		return strconv.Quote(s)
+	}
+	return "`" + s + "`"
+}
+
+// jsString returns s as a JavaScript string literal
+func jsString(s string) string {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return strconv.Quote(s)
+	}
+	return string(data)
+}
+
+var snippetTemplates = map[Text]string{
+	SnippetCurl: `curl -X {{ .Method }} {{ shellQuote .URL }}
+{{- range $k, $v := .Headers }} \
+  -H {{ shellQuote (printf "%s: %s" $k $v) }}
+{{- end }}
+{{- if .Body }} \
+  --data {{ shellQuote .Body }}
+{{- end }}
+`,
+	SnippetGo: `req, err := http.NewRequest({{ goString .Method }}, {{ goString .URL }}, {{ if .Body }}strings.NewReader({{ goRaw .Body }}){{ else }}nil{{ end }})
+if err != nil {
+	return err
+}
+{{- range $k, $v := .Headers }}
+req.Header.Set({{ goString $k }}, {{ goString $v }})
+{{- end }}
+res, err := http.DefaultClient.Do(req)
+if err != nil {
+	return err
+}
+defer res.Body.Close()
+`,
+	SnippetJavaScript: `const response = await fetch({{ jsString .URL }}, {
+  method: {{ jsString .Method }},
+{{- if .Headers }}
+  headers: {
+{{- range $k, $v := .Headers }}
+    {{ jsString $k }}: {{ jsString $v }},
+{{- end }}
+  },
+{{- end }}
+{{- if .Body }}
+{{- if .JSON }}
+  body: JSON.stringify({{ .Body }}),
+{{- else }}
+  body: {{ jsString .Body }},
+{{- end }}
+{{- end }}
+});
+`,
+}
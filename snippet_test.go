@@ -0,0 +1,136 @@
+package openapi_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/chanced/openapi"
+)
+
+var snippetData = []byte(`{
+	"openapi": "3.1.0",
+	"info": { "title": "Pet Store", "version": "1.0.0" },
+	"servers": [{
+		"url": "https://{env}.example.com/v1/",
+		"variables": { "env": { "default": "api", "enum": ["api", "staging"] } }
+	}],
+	"paths": {
+		"/pets/{petId}": {
+			"put": {
+				"operationId": "updatePet",
+				"parameters": [
+					{ "name": "petId", "in": "path", "required": true, "schema": { "type": "integer" }, "example": 42 },
+					{ "name": "dryRun", "in": "query", "schema": { "type": "boolean" }, "example": true }
+				],
+				"requestBody": {
+					"content": {
+						"application/json": { "example": { "name": "Tom's" } }
+					}
+				},
+				"responses": { "204": { "description": "updated" } }
+			}
+		}
+	},
+	"webhooks": {
+		"newPet": { "post": { "responses": { "200": { "description": "ok" } } } }
+	}
+}`)
+
+func TestDocumentSnippet(t *testing.T) {
+	doc := loadDocument(t, "https://example.com/openapi.json", snippetData)
+	var e, webhook openapi.OperationEntry
+	for _, op := range doc.Operations() {
+		if op.Webhook {
+			webhook = op
+		} else {
+			e = op
+		}
+	}
+
+	req, err := doc.SnippetRequest(e, openapi.SnippetOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.URL != "https://api.example.com/v1/pets/42?dryRun=true" {
+		t.Errorf("expected the URL to be resolved, got %q", req.URL)
+	}
+	if req.Method != "PUT" || !req.JSON || req.Headers["Content-Type"] != "application/json" {
+		t.Errorf("unexpected request %+v", req)
+	}
+
+	tests := []struct {
+		lang     openapi.Text
+		expected []string
+	}{
+		{openapi.SnippetCurl, []string{
+			`curl -X PUT 'https://api.example.com/v1/pets/42?dryRun=true'`,
+			`-H 'Content-Type: application/json'`,
+			`--data '{`,
+			`"name": "Tom'\''s"`,
+		}},
+		{openapi.SnippetGo, []string{
+			"http.NewRequest(\"PUT\", \"https://api.example.com/v1/pets/42?dryRun=true\", strings.NewReader(`{",
+			`req.Header.Set("Content-Type", "application/json")`,
+		}},
+		{openapi.SnippetJavaScript, []string{
+			`fetch("https://api.example.com/v1/pets/42?dryRun=true", {`,
+			`method: "PUT",`,
+			`"Content-Type": "application/json",`,
+			`body: JSON.stringify({`,
+		}},
+	}
+	for _, test := range tests {
+		snippet, err := doc.Snippet(test.lang, e, openapi.SnippetOpts{ServerVariables: map[string]string{"env": "api"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, s := range test.expected {
+			if !strings.Contains(snippet, s) {
+				t.Errorf("%s: expected the snippet to contain %q:\n%s", test.lang, s, snippet)
+			}
+		}
+	}
+
+	snippet, err := doc.Snippet(openapi.SnippetCurl, e, openapi.SnippetOpts{ServerVariables: map[string]string{"env": "staging"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(snippet, "https://staging.example.com/v1/pets/42") {
+		t.Errorf("expected the server variable to be applied:\n%s", snippet)
+	}
+
+	if _, err = doc.Snippet(openapi.SnippetCurl, e, openapi.SnippetOpts{ServerVariables: map[string]string{"env": "prod"}}); !errors.Is(err, openapi.ErrInvalidServerVariable) {
+		t.Errorf("expected ErrInvalidServerVariable for a value not in the enum, got %v", err)
+	}
+
+	if _, err := doc.Snippet("python", e, openapi.SnippetOpts{}); !errors.Is(err, openapi.ErrUnknownSnippetLanguage) {
+		t.Errorf("expected ErrUnknownSnippetLanguage, got %v", err)
+	}
+	if _, err := doc.SnippetRequest(webhook, openapi.SnippetOpts{}); err == nil {
+		t.Error("expected an error for a webhook")
+	}
+}
+
+func TestSnippetRegistry(t *testing.T) {
+	doc := loadDocument(t, "https://example.com/openapi.json", snippetData)
+	e := doc.Operations()[0]
+	r := openapi.NewSnippetRegistry()
+	if err := r.Register("httpie", `http {{ .Method }} {{ shellQuote .URL }}`); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Register("invalid", `{{ .Method `); err == nil {
+		t.Error("expected an error for an invalid template")
+	}
+	langs := r.Languages()
+	if len(langs) != 4 || langs[2] != "httpie" {
+		t.Errorf("expected the registered languages, got %v", langs)
+	}
+	snippet, err := doc.Snippet("httpie", e, openapi.SnippetOpts{Registry: r, Server: "http://localhost:8080"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snippet != "http PUT 'http://localhost:8080/pets/42?dryRun=true'" {
+		t.Errorf("unexpected snippet %q", snippet)
+	}
+}
@@ -0,0 +1,361 @@
+package openapi
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SPDXLicense is a license of the SPDX License List.
+type SPDXLicense struct {
+	// ID is the SPDX identifier of the license (e.g. "Apache-2.0").
+	ID Text
+	// Name is the full name of the license.
+	Name Text
+	// OSIApproved indicates that the license is approved by the Open Source
+	// Initiative.
+	OSIApproved bool
+}
+
+// spdxLicenses are the commonly used licenses of the SPDX License List
+var spdxLicenses = []SPDXLicense{
+	{"0BSD", "BSD Zero Clause License", true},
+	{"AFL-3.0", "Academic Free License v3.0", true},
+	{"AGPL-3.0-only", "GNU Affero General Public License v3.0 only", true},
+	{"AGPL-3.0-or-later", "GNU Affero General Public License v3.0 or later", true},
+	{"Apache-1.1", "Apache License 1.1", true},
+	{"Apache-2.0", "Apache License 2.0", true},
+	{"APSL-2.0", "Apple Public Source License 2.0", true},
+	{"Artistic-2.0", "Artistic License 2.0", true},
+	{"BlueOak-1.0.0", "Blue Oak Model License 1.0.0", true},
+	{"BSD-1-Clause", "BSD 1-Clause License", true},
+	{"BSD-2-Clause", "BSD 2-Clause \"Simplified\" License", true},
+	{"BSD-2-Clause-Patent", "BSD-2-Clause Plus Patent License", true},
+	{"BSD-3-Clause", "BSD 3-Clause \"New\" or \"Revised\" License", true},
+	{"BSD-3-Clause-Clear", "BSD 3-Clause Clear License", false},
+	{"BSD-4-Clause", "BSD 4-Clause \"Original\" or \"Old\" License", false},
+	{"BSL-1.0", "Boost Software License 1.0", true},
+	{"BUSL-1.1", "Business Source License 1.1", false},
+	{"CAL-1.0", "Cryptographic Autonomy License 1.0", true},
+	{"CC-BY-3.0", "Creative Commons Attribution 3.0 Unported", false},
+	{"CC-BY-4.0", "Creative Commons Attribution 4.0 International", false},
+	{"CC-BY-NC-4.0", "Creative Commons Attribution Non Commercial 4.0 International", false},
+	{"CC-BY-NC-ND-4.0", "Creative Commons Attribution Non Commercial No Derivatives 4.0 International", false},
+	{"CC-BY-NC-SA-4.0", "Creative Commons Attribution Non Commercial Share Alike 4.0 International", false},
+	{"CC-BY-ND-4.0", "Creative Commons Attribution No Derivatives 4.0 International", false},
+	{"CC-BY-SA-3.0", "Creative Commons Attribution Share Alike 3.0 Unported", false},
+	{"CC-BY-SA-4.0", "Creative Commons Attribution Share Alike 4.0 International", false},
+	{"CC0-1.0", "Creative Commons Zero v1.0 Universal", false},
+	{"CDDL-1.0", "Common Development and Distribution License 1.0", true},
+	{"CDDL-1.1", "Common Development and Distribution License 1.1", false},
+	{"CECILL-2.1", "CeCILL Free Software License Agreement v2.1", true},
+	{"CPAL-1.0", "Common Public Attribution License 1.0", true},
+	{"CPL-1.0", "Common Public License 1.0", true},
+	{"ECL-2.0", "Educational Community License v2.0", true},
+	{"EFL-2.0", "Eiffel Forum License v2.0", true},
+	{"Elastic-2.0", "Elastic License 2.0", false},
+	{"EPL-1.0", "Eclipse Public License 1.0", true},
+	{"EPL-2.0", "Eclipse Public License 2.0", true},
+	{"EUPL-1.1", "European Union Public License 1.1", true},
+	{"EUPL-1.2", "European Union Public License 1.2", true},
+	{"FSFAP", "FSF All Permissive License", false},
+	{"GFDL-1.3-only", "GNU Free Documentation License v1.3 only", false},
+	{"GFDL-1.3-or-later", "GNU Free Documentation License v1.3 or later", false},
+	{"GPL-2.0-only", "GNU General Public License v2.0 only", true},
+	{"GPL-2.0-or-later", "GNU General Public License v2.0 or later", true},
+	{"GPL-3.0-only", "GNU General Public License v3.0 only", true},
+	{"GPL-3.0-or-later", "GNU General Public License v3.0 or later", true},
+	{"HPND", "Historical Permission Notice and Disclaimer", true},
+	{"ICU", "ICU License", true},
+	{"IPL-1.0", "IBM Public License v1.0", true},
+	{"ISC", "ISC License", true},
+	{"LGPL-2.0-only", "GNU Library General Public License v2 only", true},
+	{"LGPL-2.0-or-later", "GNU Library General Public License v2 or later", true},
+	{"LGPL-2.1-only", "GNU Lesser General Public License v2.1 only", true},
+	{"LGPL-2.1-or-later", "GNU Lesser General Public License v2.1 or later", true},
+	{"LGPL-3.0-only", "GNU Lesser General Public License v3.0 only", true},
+	{"LGPL-3.0-or-later", "GNU Lesser General Public License v3.0 or later", true},
+	{"LPL-1.02", "Lucent Public License v1.02", true},
+	{"LPPL-1.3c", "LaTeX Project Public License v1.3c", true},
+	{"MIT", "MIT License", true},
+	{"MIT-0", "MIT No Attribution", true},
+	{"MPL-1.1", "Mozilla Public License 1.1", true},
+	{"MPL-2.0", "Mozilla Public License 2.0", true},
+	{"MPL-2.0-no-copyleft-exception", "Mozilla Public License 2.0 (no copyleft exception)", true},
+	{"MS-PL", "Microsoft Public License", true},
+	{"MS-RL", "Microsoft Reciprocal License", true},
+	{"MulanPSL-2.0", "Mulan Permissive Software License, Version 2", true},
+	{"NCSA", "University of Illinois/NCSA Open Source License", true},
+	{"ODbL-1.0", "Open Data Commons Open Database License v1.0", false},
+	{"OFL-1.1", "SIL Open Font License 1.1", true},
+	{"OpenSSL", "OpenSSL License", false},
+	{"OSL-3.0", "Open Software License 3.0", true},
+	{"PDDL-1.0", "Open Data Commons Public Domain Dedication & License 1.0", false},
+	{"PHP-3.01", "PHP License v3.01", true},
+	{"PostgreSQL", "PostgreSQL License", true},
+	{"Python-2.0", "Python License 2.0", true},
+	{"QPL-1.0", "Q Public License 1.0", true},
+	{"RPL-1.5", "Reciprocal Public License 1.5", true},
+	{"Ruby", "Ruby License", false},
+	{"SSPL-1.0", "Server Side Public License, v 1", false},
+	{"Unicode-DFS-2016", "Unicode License Agreement - Data Files and Software (2016)", true},
+	{"Unlicense", "The Unlicense", true},
+	{"UPL-1.0", "Universal Permissive License v1.0", true},
+	{"Vim", "Vim License", false},
+	{"W3C", "W3C Software Notice and License (2002-12-31)", true},
+	{"WTFPL", "Do What The F*ck You Want To Public License", false},
+	{"X11", "X11 License", false},
+	{"Zlib", "zlib License", true},
+	{"ZPL-2.1", "Zope Public License 2.1", true},
+}
+
+// spdxExceptions are the commonly used exceptions of the SPDX License
+// Exceptions List, which follow "WITH" in a license expression
+var spdxExceptions = map[string]bool{
+	"Autoconf-exception-3.0":         true,
+	"Bison-exception-2.2":            true,
+	"Classpath-exception-2.0":        true,
+	"GCC-exception-3.1":              true,
+	"LLVM-exception":                 true,
+	"OpenJDK-assembly-exception-1.0": true,
+	"Qt-GPL-exception-1.0":           true,
+	"Qt-LGPL-exception-1.1":          true,
+	"Universal-FOSS-exception-1.0":   true,
+	"WxWindows-exception-3.1":        true,
+}
+
+var spdxLicensesByID = func() map[string]SPDXLicense {
+	m := make(map[string]SPDXLicense, len(spdxLicenses))
+	for _, l := range spdxLicenses {
+		m[strings.ToLower(l.ID.String())] = l
+	}
+	return m
+}()
+
+// LookupSPDXLicense returns the SPDXLicense with the identifier id. As with
+// SPDX license expressions, identifiers are matched case-insensitively.
+//
+// Only the commonly used licenses of the SPDX License List are known.
+func LookupSPDXLicense(id Text) (SPDXLicense, bool) {
+	l, ok := spdxLicensesByID[strings.ToLower(id.String())]
+	return l, ok
+}
+
+// SuggestSPDXLicenses returns the identifiers of the known SPDX licenses
+// which id is likely a misspelling of (e.g. "Apache-2.0" for "Apache 2"),
+// ordered from the closest.
+func SuggestSPDXLicenses(id Text) []Text {
+	norm := normalizeSPDX(id.String())
+	type suggestion struct {
+		id   Text
+		dist int
+	}
+	var suggestions []suggestion
+	for _, l := range spdxLicenses {
+		n := normalizeSPDX(l.ID.String())
+		d := levenshtein(norm, n)
+		// identifiers often omit the version (e.g. "MPL") or the minor
+		// version (e.g. "Apache 2")
+		if strings.HasPrefix(n, norm) && len(norm) >= 3 {
+			d = minInt(d, 1)
+		}
+		if d <= 2 && d < len(norm) {
+			suggestions = append(suggestions, suggestion{l.ID, d})
+		}
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool { return suggestions[i].dist < suggestions[j].dist })
+	res := make([]Text, len(suggestions))
+	for i, s := range suggestions {
+		res[i] = s.id
+	}
+	return res
+}
+
+// normalizeSPDX lowercases s and removes its punctuation and a trailing
+// ".0" so that near-misses (e.g. "apache 2", "Apache-2.0") compare closely
+func normalizeSPDX(s string) string {
+	s = strings.ToLower(s)
+	s = strings.TrimSuffix(s, ".0")
+	return strings.NewReplacer("-", "", ".", "", " ", "", "_", "", "v", "").Replace(s)
+}
+
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = minInt(minInt(prev[j]+1, cur[j-1]+1), prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// CheckSPDXExpression checks that expr is a valid SPDX license expression
+// (e.g. "MIT OR Apache-2.0") composed of known licenses and exceptions.
+// Identifiers beginning with "LicenseRef-" or "DocumentRef-" are permitted.
+//
+// An error wrapping ErrInvalidLicense is returned if expr is malformed or
+// contains an unknown identifier, suggesting the known licenses it may be a
+// misspelling of.
+func CheckSPDXExpression(expr Text) error {
+	return checkSPDXExpression(expr, true)
+}
+
+// checkSPDXExpression checks the grammar of expr and, if known is true, that
+// its identifiers are known
+func checkSPDXExpression(expr Text, known bool) error {
+	tokens := tokenizeSPDX(expr.String())
+	if len(tokens) == 0 {
+		return fmt.Errorf("%w: empty license expression", ErrInvalidLicense)
+	}
+	p := spdxParser{tokens: tokens, known: known}
+	if err := p.expression(); err != nil {
+		return err
+	}
+	if p.pos < len(p.tokens) {
+		return fmt.Errorf("%w: unexpected %q in license expression %q", ErrInvalidLicense, p.tokens[p.pos], expr)
+	}
+	return nil
+}
+
+func tokenizeSPDX(s string) []string {
+	var tokens []string
+	for _, f := range strings.Fields(s) {
+		for f != "" {
+			i := strings.IndexAny(f, "()")
+			switch {
+			case i < 0:
+				tokens = append(tokens, f)
+				f = ""
+			case i == 0:
+				tokens = append(tokens, f[:1])
+				f = f[1:]
+			default:
+				tokens = append(tokens, f[:i])
+				f = f[i:]
+			}
+		}
+	}
+	return tokens
+}
+
+// spdxParser parses the grammar of SPDX license expressions:
+//
+//	expression = and ("OR" and)*
+//	and        = term ("AND" term)*
+//	term       = "(" expression ")" | license ["WITH" exception]
+type spdxParser struct {
+	tokens []string
+	pos    int
+	known  bool
+}
+
+func (p *spdxParser) next() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	t := p.tokens[p.pos]
+	p.pos++
+	return t
+}
+
+func (p *spdxParser) peek() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *spdxParser) expression() error {
+	if err := p.and(); err != nil {
+		return err
+	}
+	for strings.EqualFold(p.peek(), "OR") {
+		p.next()
+		if err := p.and(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *spdxParser) and() error {
+	if err := p.term(); err != nil {
+		return err
+	}
+	for strings.EqualFold(p.peek(), "AND") {
+		p.next()
+		if err := p.term(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *spdxParser) term() error {
+	t := p.next()
+	switch {
+	case t == "":
+		return fmt.Errorf("%w: incomplete license expression", ErrInvalidLicense)
+	case t == "(":
+		if err := p.expression(); err != nil {
+			return err
+		}
+		if p.next() != ")" {
+			return fmt.Errorf("%w: unbalanced parentheses in license expression", ErrInvalidLicense)
+		}
+		return nil
+	case isSPDXOperator(t) || t == ")":
+		return fmt.Errorf("%w: unexpected %q in license expression", ErrInvalidLicense, t)
+	}
+	if p.known {
+		if err := checkSPDXLicense(t); err != nil {
+			return err
+		}
+	}
+	if strings.EqualFold(p.peek(), "WITH") {
+		p.next()
+		exc := p.next()
+		if exc == "" || exc == "(" || exc == ")" || isSPDXOperator(exc) {
+			return fmt.Errorf("%w: missing license exception", ErrInvalidLicense)
+		}
+		if p.known && !spdxExceptions[exc] && !strings.HasPrefix(exc, "AdditionRef-") {
+			return fmt.Errorf("%w: unknown SPDX license exception %q", ErrInvalidLicense, exc)
+		}
+	}
+	return nil
+}
+
+func isSPDXOperator(t string) bool {
+	return strings.EqualFold(t, "AND") || strings.EqualFold(t, "OR") || strings.EqualFold(t, "WITH")
+}
+
+func checkSPDXLicense(id string) error {
+	if strings.HasPrefix(id, "LicenseRef-") || strings.HasPrefix(id, "DocumentRef-") {
+		return nil
+	}
+	if _, ok := LookupSPDXLicense(Text(strings.TrimSuffix(id, "+"))); ok {
+		return nil
+	}
+	err := fmt.Errorf("%w: unknown SPDX license identifier %q", ErrInvalidLicense, id)
+	if s := SuggestSPDXLicenses(Text(id)); len(s) > 0 {
+		err = fmt.Errorf("%w (did you mean %q?)", err, s[0])
+	}
+	return err
+}
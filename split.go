@@ -0,0 +1,355 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/chanced/transcode"
+	"github.com/tidwall/gjson"
+)
+
+// SplitLayout determines the files produced by Document.Split.
+type SplitLayout struct {
+	// Root is the name of the file containing the root of the Document. The
+	// default is "openapi.yaml" or, if JSON is set, "openapi.json".
+	Root string
+
+	// JSON indicates that files should be encoded as JSON rather than YAML.
+	JSON bool
+
+	// PathItem returns the name of the file for the PathItem of key, which is
+	// either a path or, if webhook is set, the name of a webhook. If an empty
+	// string is returned, the PathItem remains in the root file.
+	//
+	// By default, PathItems are written to "paths/<path>" and
+	// "webhooks/<name>", with characters which are not safe for file names
+	// replaced (e.g. "paths/pets_petId.yaml" for "/pets/{petId}").
+	PathItem func(key Text, webhook bool) string
+
+	// Component returns the name of the file for the component of key within
+	// the Components map of kind (e.g. "schemas"). If an empty string is
+	// returned, the component remains in the root file.
+	//
+	// By default, components are written to "components/<kind>/<key>"
+	// (e.g. "components/schemas/Pet.yaml").
+	Component func(kind Text, key Text) string
+}
+
+func (l SplitLayout) ext() string {
+	if l.JSON {
+		return ".json"
+	}
+	return ".yaml"
+}
+
+func (l SplitLayout) root() string {
+	if l.Root != "" {
+		return path.Clean(l.Root)
+	}
+	return "openapi" + l.ext()
+}
+
+func (l SplitLayout) pathItem(key Text, webhook bool) string {
+	if l.PathItem != nil {
+		return l.PathItem(key, webhook)
+	}
+	if webhook {
+		return "webhooks/" + splitFileName(key.String()) + l.ext()
+	}
+	return "paths/" + splitFileName(key.String()) + l.ext()
+}
+
+func (l SplitLayout) component(kind Text, key Text) string {
+	if l.Component != nil {
+		return l.Component(kind, key)
+	}
+	return "components/" + kind.String() + "/" + splitFileName(key.String()) + l.ext()
+}
+
+var unsafeFileNameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// splitFileName returns a file name, without extension, for the path or key s
+func splitFileName(s string) string {
+	s = strings.Trim(strings.NewReplacer("{", "", "}", "").Replace(s), "/")
+	s = strings.Trim(unsafeFileNameChars.ReplaceAllString(s, "_"), "_")
+	if s == "" || strings.HasPrefix(s, ".") {
+		return "root" + s
+	}
+	return s
+}
+
+// Split is the inverse of bundling: it divides the Document into multiple
+// files according to layout, with each PathItem of Paths and Webhooks and
+// each entry of Components written to its own file. The entries in the root
+// file are replaced with References to their files and each $ref is
+// rewritten as a URI relative to the file which contains it.
+//
+// Relative $refs to external resources are assumed to be relative to the
+// root file. $refs within "example", "default", "const", and "enum" values,
+// as well as the values of Example objects, are not rewritten.
+//
+// Split returns the encoded files, keyed by their slash-separated path
+// relative to the directory of the root file.
+func (d *Document) Split(layout SplitLayout) (map[string][]byte, error) {
+	data, err := d.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	s := splitter{
+		layout: layout,
+		root:   layout.root(),
+		files:  map[string]string{},
+		taken:  map[string]bool{},
+	}
+	s.taken[s.root] = true
+
+	var entries []splitEntry
+	for _, m := range []struct {
+		name    string
+		webhook bool
+	}{{"paths", false}, {"webhooks", true}} {
+		gjson.GetBytes(data, m.name).ForEach(func(key, value gjson.Result) bool {
+			if k := Text(key.String()); !k.HasPrefix("x-") {
+				entries = append(entries, splitEntry{
+					ptr:  "/" + m.name + "/" + escapePointerToken(key.String()),
+					file: layout.pathItem(k, m.webhook),
+					raw:  value,
+				})
+			}
+			return true
+		})
+	}
+	gjson.GetBytes(data, "components").ForEach(func(kind, m gjson.Result) bool {
+		if Text(kind.String()).HasPrefix("x-") || !m.IsObject() {
+			return true
+		}
+		m.ForEach(func(key, value gjson.Result) bool {
+			if k := Text(key.String()); !k.HasPrefix("x-") {
+				entries = append(entries, splitEntry{
+					ptr:  "/components/" + escapePointerToken(kind.String()) + "/" + escapePointerToken(key.String()),
+					file: layout.component(Text(kind.String()), k),
+					raw:  value,
+				})
+			}
+			return true
+		})
+		return true
+	})
+
+	for i, e := range entries {
+		if e.file == "" {
+			continue
+		}
+		file := s.unique(path.Clean(e.file))
+		entries[i].file = file
+		s.files[e.ptr] = file
+	}
+
+	res := make(map[string][]byte, len(s.files)+1)
+	root := bytes.Buffer{}
+	if err = s.write(&root, s.root, gjson.ParseBytes(data), nil); err != nil {
+		return nil, err
+	}
+	if res[s.root], err = s.encode(root.Bytes()); err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		if e.file == "" {
+			continue
+		}
+		b := bytes.Buffer{}
+		if err = s.write(&b, e.file, e.raw, splitPointerTokens(e.ptr)); err != nil {
+			return nil, err
+		}
+		if res[e.file], err = s.encode(b.Bytes()); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+type splitEntry struct {
+	ptr  string
+	file string
+	raw  gjson.Result
+}
+
+type splitter struct {
+	layout SplitLayout
+	root   string
+	// files are the files of the split entries, keyed by JSON pointer
+	files map[string]string
+	taken map[string]bool
+}
+
+// unique returns file or, if it is already taken, file with the lowest
+// numeric suffix, starting at 2, which is not
+func (s *splitter) unique(file string) string {
+	ext := path.Ext(file)
+	base := strings.TrimSuffix(file, ext)
+	for n := 2; s.taken[file]; n++ {
+		file = base + strconv.Itoa(n) + ext
+	}
+	s.taken[file] = true
+	return file
+}
+
+func (s *splitter) encode(data []byte) ([]byte, error) {
+	if s.layout.JSON {
+		b := bytes.Buffer{}
+		if err := json.Indent(&b, data, "", "  "); err != nil {
+			return nil, err
+		}
+		b.WriteByte('\n')
+		return b.Bytes(), nil
+	}
+	return transcode.YAMLFromJSON(data)
+}
+
+// write writes v, located at tokens of the Document, to b as it should
+// appear in file
+func (s *splitter) write(b *bytes.Buffer, file string, v gjson.Result, tokens []string) error {
+	if file == s.root && len(tokens) > 0 {
+		if target, ok := s.files[joinPointerTokens(tokens)]; ok {
+			ref, err := json.Marshal(relativeFile(file, target))
+			if err != nil {
+				return err
+			}
+			b.WriteString(`{"$ref":`)
+			b.Write(ref)
+			b.WriteByte('}')
+			return nil
+		}
+	}
+	switch {
+	case v.IsObject():
+		b.WriteByte('{')
+		var err error
+		i := 0
+		v.ForEach(func(key, value gjson.Result) bool {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			i++
+			b.WriteString(key.Raw)
+			b.WriteByte(':')
+			t := append(tokens[:len(tokens):len(tokens)], key.String())
+			switch {
+			case key.String() == "$ref" && value.Type == gjson.String:
+				var ref []byte
+				if ref, err = json.Marshal(s.rewrite(file, value.String())); err == nil {
+					b.Write(ref)
+				}
+			case isSplitDataMember(t, value):
+				b.WriteString(value.Raw)
+			default:
+				err = s.write(b, file, value, t)
+			}
+			return err == nil
+		})
+		if err != nil {
+			return err
+		}
+		b.WriteByte('}')
+	case v.IsArray():
+		b.WriteByte('[')
+		var err error
+		for i, value := range v.Array() {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			t := append(tokens[:len(tokens):len(tokens)], strconv.Itoa(i))
+			if err = s.write(b, file, value, t); err != nil {
+				return err
+			}
+		}
+		b.WriteByte(']')
+	default:
+		b.WriteString(v.Raw)
+	}
+	return nil
+}
+
+// rewrite returns ref, as it appears in the Document, relative to file
+func (s *splitter) rewrite(file string, ref string) string {
+	u, err := url.Parse(ref)
+	if err != nil || u.Scheme != "" || u.Host != "" || strings.HasPrefix(u.Path, "/") {
+		return ref
+	}
+	if u.Path != "" {
+		u.Path = relativeFile(file, path.Join(path.Dir(s.root), u.Path))
+		return u.String()
+	}
+	target, rest := s.root, u.Fragment
+	for ptr, f := range s.files {
+		if u.Fragment == ptr || strings.HasPrefix(u.Fragment, ptr+"/") {
+			target, rest = f, u.Fragment[len(ptr):]
+			break
+		}
+	}
+	res := &url.URL{Fragment: rest}
+	if target != file {
+		res.Path = relativeFile(file, target)
+	}
+	if res.Path == "" && rest == "" {
+		return "#"
+	}
+	return res.String()
+}
+
+// relativeFile returns the path of target relative to the directory of file
+func relativeFile(file string, target string) string {
+	rel, err := filepath.Rel(filepath.FromSlash(path.Dir(file)), filepath.FromSlash(target))
+	if err != nil {
+		return target
+	}
+	return filepath.ToSlash(rel)
+}
+
+// isSplitDataMember reports whether the member at tokens is an instance
+// value (e.g. an example) rather than part of the specification
+func isSplitDataMember(tokens []string, v gjson.Result) bool {
+	n := len(tokens)
+	if n > 1 {
+		switch tokens[n-2] {
+		case "properties", "patternProperties", "$defs", "definitions", "dependentSchemas":
+			return false
+		}
+	}
+	switch tokens[n-1] {
+	case "example", "default", "const", "enum":
+		return true
+	case "examples":
+		return v.IsArray()
+	case "value":
+		return n > 2 && tokens[n-3] == "examples"
+	}
+	return false
+}
+
+func escapePointerToken(tok string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(tok)
+}
+
+func splitPointerTokens(ptr string) []string {
+	toks := strings.Split(strings.TrimPrefix(ptr, "/"), "/")
+	for i, t := range toks {
+		toks[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(t)
+	}
+	return toks
+}
+
+func joinPointerTokens(tokens []string) string {
+	b := strings.Builder{}
+	for _, t := range tokens {
+		b.WriteByte('/')
+		b.WriteString(escapePointerToken(t))
+	}
+	return b.String()
+}
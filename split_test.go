@@ -0,0 +1,96 @@
+package openapi_test
+
+import (
+	"testing"
+
+	"github.com/chanced/openapi"
+	"github.com/tidwall/gjson"
+)
+
+func TestSplit(t *testing.T) {
+	data := []byte(`{
+		"openapi": "3.1.0",
+		"info": { "title": "split", "version": "1.0.0" },
+		"paths": {
+			"/pets/{petId}": {
+				"get": {
+					"responses": {
+						"200": {
+							"description": "ok",
+							"content": {
+								"application/json": { "schema": { "$ref": "#/components/schemas/Pet" } }
+							}
+						}
+					}
+				}
+			}
+		},
+		"components": {
+			"schemas": {
+				"Pet": {
+					"type": "object",
+					"properties": {
+						"next": { "$ref": "#/components/schemas/Pet" },
+						"owner": { "$ref": "#/components/schemas/Owner/properties/name" }
+					}
+				},
+				"Owner": {
+					"type": "object",
+					"properties": { "name": { "type": "string" } }
+				}
+			}
+		}
+	}`)
+	doc := loadDocument(t, "https://example.com/openapi.json", data)
+	files, err := doc.Split(openapi.SplitLayout{JSON: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 4 {
+		t.Errorf("expected 4 files, got %d", len(files))
+	}
+	refs := []struct {
+		file     string
+		path     string
+		expected string
+	}{
+		{"openapi.json", `paths./pets/{petId}.$ref`, "paths/pets_petId.json"},
+		{"openapi.json", "components.schemas.Pet.$ref", "components/schemas/Pet.json"},
+		{"paths/pets_petId.json", "get.responses.200.content.application/json.schema.$ref", "../components/schemas/Pet.json"},
+		{"components/schemas/Pet.json", "properties.next.$ref", "#"},
+		{"components/schemas/Pet.json", "properties.owner.$ref", "Owner.json#/properties/name"},
+	}
+	for _, r := range refs {
+		f, ok := files[r.file]
+		if !ok {
+			t.Fatalf("expected file %q", r.file)
+		}
+		if ref := gjson.GetBytes(f, r.path).String(); ref != r.expected {
+			t.Errorf("expected %s of %s to be %q, got %q", r.path, r.file, r.expected, ref)
+		}
+	}
+	if title := gjson.GetBytes(files["openapi.json"], "info.title").String(); title != "split" {
+		t.Errorf("expected info to remain in the root file, got %q", title)
+	}
+
+	files, err = doc.Split(openapi.SplitLayout{
+		Component: func(kind, key openapi.Text) string {
+			if key == "Owner" {
+				return ""
+			}
+			return "models/" + key.String() + ".yaml"
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := files["openapi.yaml"]; !ok {
+		t.Error("expected openapi.yaml")
+	}
+	if _, ok := files["models/Pet.yaml"]; !ok {
+		t.Error("expected models/Pet.yaml")
+	}
+	if _, ok := files["components/schemas/Owner.yaml"]; ok {
+		t.Error("expected Owner to remain in the root file")
+	}
+}
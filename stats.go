@@ -0,0 +1,143 @@
+package openapi
+
+// Stats are statistics about the surface of a Document, intended to track
+// the health of a specification over time.
+type Stats struct {
+	// Paths is the number of entries in the Document's Paths.
+	Paths int `json:"paths"`
+	// Webhooks is the number of entries in the Document's Webhooks.
+	Webhooks int `json:"webhooks"`
+	// Operations is the number of Operations defined in Paths.
+	Operations int `json:"operations"`
+	// OperationsByMethod is the number of Operations, keyed by HTTP method
+	// (e.g. "GET").
+	OperationsByMethod map[Text]int `json:"operationsByMethod"`
+	// DeprecatedOperations is the number of Operations marked as deprecated.
+	DeprecatedOperations int `json:"deprecatedOperations"`
+	// OperationsWithoutDescription is the number of Operations which do not
+	// have a description.
+	OperationsWithoutDescription int `json:"operationsWithoutDescription"`
+	// OperationsWithoutExamples is the number of Operations which do not
+	// have an example or examples on any of their Parameters, request body
+	// or responses.
+	OperationsWithoutExamples int `json:"operationsWithoutExamples"`
+	// ComponentSchemas is the number of Schemas in the Document's
+	// Components.
+	ComponentSchemas int `json:"componentSchemas"`
+	// Schemas is the number of Schemas defined in the Document, including
+	// those nested within other Schemas.
+	Schemas int `json:"schemas"`
+	// ReferencedSchemas is the number of Schemas which consist of a $ref,
+	// $dynamicRef, or $recursiveRef.
+	ReferencedSchemas int `json:"referencedSchemas"`
+	// InlineSchemas is the number of Schemas which are neither components
+	// nor references.
+	InlineSchemas int `json:"inlineSchemas"`
+}
+
+// ExampleCoverage returns the ratio of Operations which have examples. If
+// there are no Operations, 1 is returned.
+func (s Stats) ExampleCoverage() float64 {
+	if s.Operations == 0 {
+		return 1
+	}
+	return float64(s.Operations-s.OperationsWithoutExamples) / float64(s.Operations)
+}
+
+// DescriptionCoverage returns the ratio of Operations which have a
+// description. If there are no Operations, 1 is returned.
+func (s Stats) DescriptionCoverage() float64 {
+	if s.Operations == 0 {
+		return 1
+	}
+	return float64(s.Operations-s.OperationsWithoutDescription) / float64(s.Operations)
+}
+
+// Stats returns statistics about the Document.
+//
+// Only nodes defined by the Document are considered; the targets of
+// references are not.
+func (d *Document) Stats() Stats {
+	stats := Stats{OperationsByMethod: map[Text]int{}}
+	if d == nil {
+		return stats
+	}
+	if d.Paths != nil {
+		stats.Paths = len(d.Paths.Items)
+		for _, item := range d.Paths.Items {
+			for _, mo := range item.Value.Operations() {
+				stats.Operations++
+				stats.OperationsByMethod[mo.Method]++
+				if mo.Operation.Deprecated {
+					stats.DeprecatedOperations++
+				}
+				if mo.Operation.Description == "" {
+					stats.OperationsWithoutDescription++
+				}
+				if !mo.Operation.hasExamples() {
+					stats.OperationsWithoutExamples++
+				}
+			}
+		}
+	}
+	if d.Webhooks != nil {
+		stats.Webhooks = len(d.Webhooks.Items)
+	}
+	components := map[*Schema]bool{}
+	if d.Components != nil && d.Components.Schemas != nil {
+		stats.ComponentSchemas = len(d.Components.Schemas.Items)
+		for _, item := range d.Components.Schemas.Items {
+			components[item.Schema] = true
+		}
+	}
+	_ = walkLocalNodes(d, func(n node) error {
+		s, ok := n.(*Schema)
+		if !ok {
+			return nil
+		}
+		stats.Schemas++
+		switch {
+		case s.Ref != nil || s.DynamicRef != nil || s.RecursiveRef != nil:
+			stats.ReferencedSchemas++
+		case !components[s]:
+			stats.InlineSchemas++
+		}
+		return nil
+	})
+	return stats
+}
+
+// hasExamples reports whether any of the Parameters, request body, or
+// responses of o have an example or examples.
+func (o *Operation) hasExamples() bool {
+	if o.Parameters != nil {
+		for _, c := range o.Parameters.Items {
+			if p := c.Object; p != nil && (len(p.Example) > 0 || p.Examples != nil) {
+				return true
+			}
+		}
+	}
+	if o.RequestBody != nil && o.RequestBody.Object != nil && contentHasExamples(o.RequestBody.Object.Content) {
+		return true
+	}
+	if o.Responses != nil {
+		for _, e := range o.Responses.Items {
+			if e.Component != nil && e.Component.Object != nil && contentHasExamples(e.Component.Object.Content) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func contentHasExamples(content *ContentMap) bool {
+	if content == nil {
+		return false
+	}
+	for _, item := range content.Items {
+		if mt := item.Value; mt != nil && (len(mt.Example) > 0 || mt.Examples != nil) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/chanced/openapi"
+)
+
+func TestDocumentStats(t *testing.T) {
+	data := []byte(`{
+		"openapi": "3.1.0",
+		"info": { "title": "stats", "version": "1.0.0" },
+		"paths": {
+			"/pets": {
+				"get": {
+					"description": "Lists pets",
+					"responses": {
+						"200": {
+							"description": "ok",
+							"content": {
+								"application/json": {
+									"schema": { "type": "array", "items": { "$ref": "#/components/schemas/Pet" } },
+									"example": [{ "name": "Fido" }]
+								}
+							}
+						}
+					}
+				},
+				"post": {
+					"deprecated": true,
+					"requestBody": {
+						"content": {
+							"application/json": {
+								"schema": { "$ref": "#/components/schemas/Pet" }
+							}
+						}
+					},
+					"responses": {
+						"201": { "description": "created" }
+					}
+				}
+			}
+		},
+		"components": {
+			"schemas": {
+				"Pet": {
+					"type": "object",
+					"properties": {
+						"name": { "type": "string" }
+					}
+				}
+			}
+		}
+	}`)
+	var doc openapi.Document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	stats := doc.Stats()
+	expected := openapi.Stats{
+		Paths:                        1,
+		Operations:                   2,
+		OperationsByMethod:           map[openapi.Text]int{openapi.MethodGet: 1, openapi.MethodPost: 1},
+		DeprecatedOperations:         1,
+		OperationsWithoutDescription: 1,
+		OperationsWithoutExamples:    1,
+		ComponentSchemas:             1,
+		Schemas:                      5,
+		ReferencedSchemas:            2,
+		InlineSchemas:                2,
+	}
+	exp, _ := json.Marshal(expected)
+	got, _ := json.Marshal(stats)
+	if string(exp) != string(got) {
+		t.Errorf("expected %s, got %s", exp, got)
+	}
+	if stats.ExampleCoverage() != 0.5 {
+		t.Errorf("expected example coverage of 0.5, got %v", stats.ExampleCoverage())
+	}
+}
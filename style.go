@@ -22,4 +22,11 @@ const (
 	// array values if the array is a single parameter, as in
 	// 	arr=a|b|c
 	StylePipeDelimited Text = "pipeDelimited"
+	// StyleSpaceDelimited is space-separated array values.
+	//
+	// Same as collectionFormat: ssv in OpenAPI 2.0. Has effect only for
+	// non-exploded arrays (explode: false), that is, the space separates the
+	// array values if the array is a single parameter, as in
+	// 	arr=a%20b%20c
+	StyleSpaceDelimited Text = "spaceDelimited"
 )
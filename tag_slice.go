@@ -2,8 +2,8 @@ package openapi
 
 import (
 	"encoding/json"
+	"strconv"
 
-	"github.com/chanced/transcode"
 	"gopkg.in/yaml.v3"
 )
 
@@ -97,11 +97,7 @@ func (t TagSlice) MarshalYAML() (interface{}, error) {
 
 // UnmarshalYAML satisfies gopkg.in/yaml.v3 Unmarshaler interface
 func (t *TagSlice) UnmarshalYAML(value *yaml.Node) error {
-	v, err := yaml.Marshal(value)
-	if err != nil {
-		return err
-	}
-	j, err := transcode.JSONFromYAML(v)
+	j, err := jsonFromYAMLNode(value)
 	if err != nil {
 		return err
 	}
@@ -124,6 +120,11 @@ func (ts *TagSlice) setLocation(loc Location) error {
 		return nil
 	}
 	ts.Location = loc
+	for i, t := range ts.Items {
+		if err := t.setLocation(loc.AppendLocation(strconv.Itoa(i))); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
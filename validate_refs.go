@@ -0,0 +1,178 @@
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/chanced/uri"
+)
+
+// RefErrors is returned from Document.ValidateRefs when one or more
+// references are invalid. Each error contains the location of the
+// reference.
+type RefErrors []error
+
+func (e RefErrors) Error() string {
+	b := strings.Builder{}
+	b.WriteString("openapi: invalid references:")
+	for _, err := range e {
+		b.WriteString(fmt.Sprintf("\n- %s", err))
+	}
+	return b.String()
+}
+
+func (e RefErrors) As(target interface{}) bool {
+	for _, v := range e {
+		if errors.As(v, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e RefErrors) Is(err error) bool {
+	for _, v := range e {
+		if errors.Is(v, err) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateRefs checks each reference of the Document, returning RefErrors
+// containing an error for every reference which:
+//   - is empty (ErrEmptyRef)
+//   - targets a location or anchor of the Document which does not exist
+//     (ErrRefNotFound)
+//   - targets a node of a Kind other than the one referenced
+//     (ErrInvalidResolution)
+//   - targets another resource and has not been resolved
+//     (ErrUnresolvedReference)
+//
+// References local to the Document are checked against its current contents,
+// regardless of whether they have been resolved, so ValidateRefs may be used
+// on Documents constructed or modified in code. To do so, the Locations of
+// the Document's nodes are assigned relative to the Document's Location.
+//
+// $recursiveRef references are not checked as their targets are determined
+// dynamically.
+func (d *Document) ValidateRefs() error {
+	if d == nil {
+		return nil
+	}
+	if err := d.setLocation(d.Location); err != nil {
+		return err
+	}
+	anchors, err := d.Anchors()
+	if err != nil {
+		return err
+	}
+	index := map[string]node{}
+	var refs []Ref
+	_ = walkLocalNodes(d, func(n node) error {
+		index[n.location().RelativeLocation().String()] = n
+		if IsRef(n) {
+			refs = append(refs, n.(Ref))
+		}
+		return nil
+	})
+
+	var errs RefErrors
+	for _, r := range refs {
+		if err := d.validateRef(r, index, anchors); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func (d *Document) validateRef(r Ref, index map[string]node, anchors *Anchors) error {
+	u := r.URI()
+	if u == nil || *u == (uri.URI{}) {
+		return NewError(ErrEmptyRef, r.AbsoluteLocation())
+	}
+	if r.RefType() == RefTypeSchemaRecursiveRef {
+		return nil
+	}
+	if !d.isLocalRef(r) {
+		t := r.ResolvedNode()
+		if t == nil {
+			return newErrUnresolvedReference(r)
+		}
+		if n, ok := t.(node); ok && n.isNil() {
+			return newErrUnresolvedReference(r)
+		}
+		return checkRefKind(r, t)
+	}
+
+	if u.Fragment == "" || strings.HasPrefix(u.Fragment, "/") {
+		t, ok := index[u.Fragment]
+		if !ok {
+			return newRefNotFoundError(r)
+		}
+		return checkRefKind(r, t)
+	}
+
+	// the fragment is an anchor
+	if r.RefKind() != KindSchema {
+		return NewError(fmt.Errorf("%w: anchors are not supported for %s references: #%s", ErrInvalidResolution, r.RefKind(), u.Fragment), r.AbsoluteLocation())
+	}
+	var a *Anchor
+	if r.RefType() == RefTypeSchemaDynamicRef {
+		a = anchors.DynamicAnchor(Text(u.Fragment))
+	}
+	if a == nil {
+		a = anchors.StandardAnchor(Text(u.Fragment))
+	}
+	if a == nil {
+		return newRefNotFoundError(r)
+	}
+	return nil
+}
+
+// isLocalRef reports whether r references a location within the Document
+func (d *Document) isLocalRef(r Ref) bool {
+	u := r.URI()
+	if u.Host == "" && u.Path == "" {
+		return true
+	}
+	base := d.AbsoluteLocation()
+	if base == (uri.URI{}) {
+		return false
+	}
+	target := *base.ResolveReference(u)
+	target.Fragment = ""
+	target.RawFragment = ""
+	base.Fragment = ""
+	base.RawFragment = ""
+	return target.String() == base.String()
+}
+
+// checkRefKind returns an error if the Kind of t is not the Kind referenced
+// by r
+func checkRefKind(r Ref, t Node) error {
+	expected, actual := refObjectKind(r), t.Kind()
+	switch v := t.(type) {
+	case interface{ ObjectKind() Kind }:
+		actual = v.ObjectKind()
+	case Ref:
+		// t is itself a reference; its Kind is that of the node it references
+		actual = refObjectKind(v)
+	}
+	if expected != actual {
+		return NewError(NewResolutionError(r, expected, actual), r.AbsoluteLocation())
+	}
+	return nil
+}
+
+// refObjectKind returns the Kind of node referenced by r
+func refObjectKind(r Ref) Kind {
+	if o, ok := r.(interface{ objectKind() Kind }); ok {
+		return o.objectKind()
+	}
+	return r.RefKind()
+}
@@ -0,0 +1,64 @@
+package openapi_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/chanced/openapi"
+	"github.com/chanced/uri"
+)
+
+func TestValidateRefs(t *testing.T) {
+	doc := &openapi.Document{
+		Info: &openapi.Info{Title: "refs", Version: "1.0.0"},
+		Components: &openapi.Components{
+			Responses:  &openapi.ResponseMap{},
+			Parameters: &openapi.ParameterMap{},
+			Schemas:    &openapi.SchemaMap{},
+		},
+	}
+	c := doc.Components
+	c.Responses.SetObject("NotFound", &openapi.Response{Description: "not found"})
+	c.Responses.SetRef("Found", *uri.MustParse("#/components/responses/NotFound"))
+	c.Responses.SetRef("Dangling", *uri.MustParse("#/components/responses/Gone"))
+	c.Responses.SetRef("Mismatched", *uri.MustParse("#/components/parameters/Limit"))
+	c.Responses.SetRef("Remote", *uri.MustParse("https://example.com/common.json#/components/responses/Error"))
+	c.Responses.SetRef("Empty", uri.URI{})
+	c.Parameters.SetObject("Limit", &openapi.Parameter{
+		Name:   "limit",
+		In:     openapi.InQuery,
+		Schema: &openapi.Schema{Ref: &openapi.SchemaRef{Ref: uri.MustParse("#limit")}},
+	})
+	c.Schemas.Set("Pet", &openapi.Schema{Anchor: "pet"})
+	c.Schemas.Set("Pets", &openapi.Schema{Ref: &openapi.SchemaRef{Ref: uri.MustParse("#pet")}})
+	c.Schemas.Set("Cat", &openapi.Schema{Ref: &openapi.SchemaRef{Ref: uri.MustParse("#/components/schemas/Pet")}})
+
+	err := doc.ValidateRefs()
+	var re openapi.RefErrors
+	if !errors.As(err, &re) {
+		t.Fatalf("expected RefErrors, got %v", err)
+	}
+	expected := []error{
+		openapi.ErrRefNotFound,
+		openapi.ErrInvalidResolution,
+		openapi.ErrUnresolvedReference,
+		openapi.ErrEmptyRef,
+		openapi.ErrRefNotFound,
+	}
+	if len(re) != len(expected) {
+		t.Fatalf("expected %d errors, got %d: %v", len(expected), len(re), err)
+	}
+	for i, e := range expected {
+		if !errors.Is(re[i], e) {
+			t.Errorf("expected error %d to be %v, got %v", i, e, re[i])
+		}
+	}
+	var oe *openapi.Error
+	if !errors.As(re[0], &oe) || oe.ResourceURI.String() != "#/components/responses/Dangling" {
+		t.Errorf("expected the error to be located at the reference, got %v", re[0])
+	}
+
+	if err = loadDereferenceDocument(t).ValidateRefs(); err != nil {
+		t.Errorf("expected the references of a loaded document to be valid, got %v", err)
+	}
+}
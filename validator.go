@@ -9,7 +9,10 @@ import (
 	"io"
 	"io/fs"
 	"log"
+	"path"
 	"path/filepath"
+	"runtime"
+	"sync"
 
 	"github.com/Masterminds/semver"
 	"github.com/chanced/uri"
@@ -29,6 +32,10 @@ const (
 	JSON_SCHEMA_2020_12 = "https://json-schema.org/draft/2020-12/schema"
 	// URI for JSON Schema 2019-09
 	JSON_SCHEMA_2019_09 = "https://json-schema.org/draft/2019-09/schema"
+	// URI for JSON Schema 07
+	JSON_SCHEMA_07 = "http://json-schema.org/draft-07/schema#"
+	// URI for the OpenAPI 3.1 Schema Object dialect
+	OPEN_API_3_1_DIALECT = "https://spec.openapis.org/oas/3.1/dialect/base"
 )
 
 var (
@@ -40,6 +47,10 @@ var (
 	JSONSchemaDialect202012 = *uri.MustParse(JSON_SCHEMA_2020_12)
 	// JSONSchemaDialect201909 is the URI for JSON Schema 2019-09
 	JSONSchemaDialect201909 = *uri.MustParse(JSON_SCHEMA_2019_09)
+	// JSONSchemaDialect07 is the URI for JSON Schema 07
+	JSONSchemaDialect07 = *uri.MustParse(JSON_SCHEMA_07)
+	// OpenAPI31Dialect is the URI for the OpenAPI 3.1 Schema Object dialect
+	OpenAPI31Dialect = *uri.MustParse(OPEN_API_3_1_DIALECT)
 	// VersionConstraints3_0 is a semantic versioning constraint for 3.0:
 	//	>= 3.0.0, < 3.1.0
 	VersionConstraints3_0 = mustParseConstraints(">= 3.0.0, < 3.1.0")
@@ -52,13 +63,13 @@ var (
 	// This is currently:
 	//	>= 3.0.0, < 3.2.0
 	SupportedVersions = mustParseConstraints(">= 3.0.0, < 3.2.0")
+	// Version3_2 is a semantic version for 3.2.x
+	Version3_2 = *semver.MustParse("3.2")
 	// Version3_1 is a semantic version for 3.1.x
 	Version3_1 = *semver.MustParse("3.1")
 	// Version3_0 is a semantic version for 3.0.x
 	Version3_0 = *semver.MustParse("3.0")
 
-	// // JSONSchemaDialect07 is the URI for JSON Schema 07
-	// JSONSchemaDialect07 = *uri.MustParse("http://json-schema.org/draft-07/schema#")
 	// // JSONSchemaDialect04 is the URI for JSON Schema 04
 	// JSONSchemaDialect04 = *uri.MustParse("http://json-schema.org/draft-04/schema#")
 )
@@ -108,11 +119,29 @@ type Validator interface {
 //   - OpenAPI 3.0: "https://spec.openapis.org/oas/3.0/schema/2021-09-28"
 //   - JSON Schema 2020-12: "https://json-schema.org/draft/2020-12/schema"
 //   - JSON Schema 2019-09: "https://json-schema.org/draft/2019-09/schema"
+//
+// To validate schemas of other dialects, set StdValidator.Dialects.
+//
+// To treat the format keyword as an assertion during document validation, call
+// FormatRegistry.AssertFormats with compiler prior to NewValidator.
 func NewValidator(compiler *jsonschema.Compiler, resources ...fs.FS) (*StdValidator, error) {
 	if compiler == nil {
 		return nil, errors.New("openapi: compiler is required")
 	}
-	compiled, err := CompileSchemas(compiler)
+	return NewValidatorWithCompiler(JSONSchemaCompiler{compiler})
+}
+
+// NewValidatorWithCompiler creates and returns a new StdValidator which
+// compiles the OpenAPI and JSON Schema resources with compiler, allowing for
+// alternative JSON Schema engines to be used.
+//
+// The resources must have already been added to compiler, e.g. with
+// AddCompilerResources.
+func NewValidatorWithCompiler(compiler Compiler) (*StdValidator, error) {
+	if compiler == nil {
+		return nil, errors.New("openapi: compiler is required")
+	}
+	compiled, err := CompileSchemasWithCompiler(compiler)
 	if err != nil {
 		return nil, fmt.Errorf("failed to compile schemas: %w", err)
 	}
@@ -121,24 +150,234 @@ func NewValidator(compiler *jsonschema.Compiler, resources ...fs.FS) (*StdValida
 	}, nil
 }
 
+// NewOfflineValidator creates and returns a new StdValidator with all of the
+// embedded OpenAPI and JSON Schema resources compiled.
+//
+// Unlike NewValidator, a jsonschema.Compiler is not required. The compiler
+// used is guaranteed to not access the network; any resource which is not
+// embedded or provided by resources results in an ErrOffline error.
+//
+// Each fs.FS in resources will be walked and all files ending in .json will be
+// be added to the compiler.
+func NewOfflineValidator(resources ...fs.FS) (*StdValidator, error) {
+	compiler, err := SetupCompiler(newOfflineCompiler(), resources...)
+	if err != nil {
+		return nil, err
+	}
+	return NewValidator(compiler)
+}
+
+// newOfflineCompiler returns a jsonschema.Compiler which does not load
+// resources which have not been added to it.
+func newOfflineCompiler() *jsonschema.Compiler {
+	c := jsonschema.NewCompiler()
+	c.LoadURL = func(s string) (io.ReadCloser, error) {
+		return nil, fmt.Errorf("%w: %q", ErrOffline, s)
+	}
+	return c
+}
+
 // StdValidator is an implemtation of the Validator interface.
 type StdValidator struct {
 	Schemas CompiledSchemas
+	// Dialects, if set, is used to validate schemas of a dialect without a
+	// CompiledSchema (e.g. a company-internal dialect) against the
+	// meta-schema of the dialect's Base.
+	Dialects *DialectRegistry
+	// Extensions are the CompiledSchemas of known extensions, keyed by
+	// extension name (e.g. "x-internal-id"). See RegisterExtension.
+	Extensions map[Text]CompiledSchema
+	// ExtensionProfiles are sets of extensions, matched by pattern, whose
+	// values are validated by ValidateDocument. See
+	// RegisterExtensionProfile.
+	ExtensionProfiles []ExtensionProfile
+	// Annotations, if set, are validated by ValidateDocument wherever they
+	// appear in a Document. See NewAnnotationRegistry.
+	Annotations *AnnotationRegistry
+	// Concurrency is the maximum number of nodes which ValidateDocument
+	// validates concurrently. If Concurrency is 0, runtime.GOMAXPROCS(0) is
+	// used. Set Concurrency to 1 to validate serially.
+	//
+	// Unless Concurrency is 1, the CompiledSchemas of the StdValidator must be
+	// safe for concurrent use. CompiledSchemas of
+	// github.com/santhosh-tekuri/jsonschema/v5 are.
+	Concurrency int
+	// Cache, if set, retains the JSON encoding of each node validated by
+	// ValidateDocument so that nodes which have not changed are not
+	// re-marshaled when a Document is validated again. Nodes which are
+	// modified in place must be invalidated; see MarshalCache.
+	Cache *MarshalCache
+}
+
+// RegisterExtension registers schema as the CompiledSchema of the extension
+// key. The value of the extension, wherever it appears in a Document, is
+// validated against schema by ValidateDocument.
+func (sv *StdValidator) RegisterExtension(key Text, schema CompiledSchema) {
+	if !key.HasPrefix("x-") {
+		key = "x-" + key
+	}
+	if sv.Extensions == nil {
+		sv.Extensions = map[Text]CompiledSchema{}
+	}
+	sv.Extensions[key] = schema
+}
+
+// ExtensionProfile is a set of vendor extensions (e.g.
+// "x-amazon-apigateway-*") whose values are validated against a
+// CompiledSchema.
+type ExtensionProfile struct {
+	// Name of the profile, used in error messages.
+	Name string
+	// Patterns of extension keys which belong to the profile. Patterns
+	// follow the syntax of path.Match (e.g. "x-go-*").
+	Patterns []Text
+	// Schema is the CompiledSchema which the value of each matching
+	// extension is validated against.
+	Schema CompiledSchema
+}
+
+// Matches reports whether key matches any of the profile's Patterns.
+func (ep ExtensionProfile) Matches(key Text) bool {
+	for _, p := range ep.Patterns {
+		if ok, _ := path.Match(p.String(), key.String()); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// RegisterExtensionProfile registers p so that the value of each extension
+// matching one of p's Patterns is validated by ValidateDocument.
+func (sv *StdValidator) RegisterExtensionProfile(p ExtensionProfile) error {
+	if p.Schema == nil {
+		return fmt.Errorf("openapi: extension profile %q is missing a schema", p.Name)
+	}
+	if len(p.Patterns) == 0 {
+		return fmt.Errorf("openapi: extension profile %q is missing patterns", p.Name)
+	}
+	for _, pattern := range p.Patterns {
+		if !pattern.HasPrefix("x-") {
+			return fmt.Errorf("openapi: pattern %q of extension profile %q must start with \"x-\"", pattern, p.Name)
+		}
+		if _, err := path.Match(pattern.String(), ""); err != nil {
+			return fmt.Errorf("openapi: invalid pattern %q for extension profile %q: %w", pattern, p.Name, err)
+		}
+	}
+	sv.ExtensionProfiles = append(sv.ExtensionProfiles, p)
+	return nil
+}
+
+// validateExtensions validates each extension of doc and its descendants
+// which has a registered CompiledSchema or matches a registered
+// ExtensionProfile. All violations are returned as ExtensionErrors.
+func (sv *StdValidator) validateExtensions(doc *Document) error {
+	if len(sv.Extensions) == 0 && len(sv.ExtensionProfiles) == 0 {
+		return nil
+	}
+	var errs ExtensionErrors
+	err := walkNodes(doc, func(n node) error {
+		e, ok := n.(extended)
+		if !ok {
+			return nil
+		}
+		exts := e.exts()
+		for _, key := range exts.Keys() {
+			var profiles []ExtensionProfile
+			for _, p := range sv.ExtensionProfiles {
+				if p.Matches(key) {
+					profiles = append(profiles, p)
+				}
+			}
+			schema, ok := sv.Extensions[key]
+			if !ok && len(profiles) == 0 {
+				continue
+			}
+			var v interface{}
+			loc := n.location().AppendLocation(key.String()).AbsoluteLocation()
+			if err := json.Unmarshal(exts[key], &v); err != nil {
+				return NewError(fmt.Errorf("openapi: failed to decode extension %q: %w", key, err), loc)
+			}
+			if ok {
+				if err := schema.Validate(v); err != nil {
+					errs = append(errs, NewValidationError(err, n.Kind(), loc))
+				}
+			}
+			for _, p := range profiles {
+				if err := p.Schema.Validate(v); err != nil {
+					err = fmt.Errorf("extension %q violates profile %q: %w", key, p.Name, err)
+					errs = append(errs, NewValidationError(err, n.Kind(), loc))
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
 }
 
 // Validate should validate the fully-resolved OpenAPI document.
 //
-// This currently only validates with JSON Schema.
+// This currently validates with JSON Schema, ensures that example and
+// examples are not both set on any Parameter, Header, or MediaType, ensures
+// that webhooks do not define servers, and validates registered extensions
+// and extension profiles.
+//
+// The Document and each node it references from another resource are
+// validated concurrently; see Concurrency.
 func (sv *StdValidator) ValidateDocument(doc *Document) error {
+	dialect, err := sv.checkDocument(doc)
+	if err != nil {
+		return err
+	}
+	jobs := []validationJob{{
+		node:     doc,
+		kind:     KindDocument,
+		location: doc.AbsoluteLocation(),
+		dialect:  dialect,
+	}}
+	jobs = append(jobs, remoteValidationJobs(doc, dialect)...)
+	return sv.validateJobs(jobs, *doc.OpenAPI)
+}
+
+// checkDocument performs the validations of ValidateDocument which are not
+// reliant upon JSON Schema and returns the JSON Schema dialect of doc.
+func (sv *StdValidator) checkDocument(doc *Document) (uri.URI, error) {
 	// The openapi spec claims there are validations which json
 	// schema can not fully encompass. Those will need to be added here.
 	// TODO: Improve validation beyond JSON Schema
 
-	d, err := doc.MarshalJSON()
-	if err != nil {
-		return fmt.Errorf("failed to marshal document: %w", err)
+	if err := validateExamples(doc); err != nil {
+		return uri.URI{}, err
+	}
+	if err := validateWebhooks(doc); err != nil {
+		return uri.URI{}, err
+	}
+	if err := validateResponseCodes(doc); err != nil {
+		return uri.URI{}, err
+	}
+	if err := validateLicense(doc); err != nil {
+		return uri.URI{}, err
+	}
+	if err := validateOAuthFlows(doc); err != nil {
+		return uri.URI{}, err
+	}
+	if err := sv.validateExtensions(doc); err != nil {
+		return uri.URI{}, err
+	}
+	if sv.Annotations != nil {
+		if err := sv.Annotations.Validate(doc); err != nil {
+			return uri.URI{}, err
+		}
 	}
 
+	if doc.OpenAPI == nil {
+		return uri.URI{}, NewError(ErrMissingOpenAPIVersion, doc.AbsoluteLocation())
+	}
 	dialect := doc.JSONSchemaDialect
 	if dialect == nil {
 		if VersionConstraints3_1.Check(doc.OpenAPI) {
@@ -146,45 +385,121 @@ func (sv *StdValidator) ValidateDocument(doc *Document) error {
 			// } else if VersionConstraints3_0.Check(doc.OpenAPI) {
 			// 	dialect = &JSONSchemaDialect201909
 		} else {
-			return fmt.Errorf("openapi: unable to detect OpenAPI version: %s", doc.OpenAPI)
+			return uri.URI{}, fmt.Errorf("openapi: unable to detect OpenAPI version: %s", doc.OpenAPI)
 		}
 	}
-	if err = sv.Validate(d, doc.AbsoluteLocation(), KindDocument, *doc.OpenAPI, *dialect); err != nil {
-		return err
-	}
+	return *dialect, nil
+}
+
+// remoteValidationJobs returns a validationJob for each distinct node which
+// is referenced by doc from another resource.
+func remoteValidationJobs(doc *Document, dialect uri.URI) []validationJob {
+	var jobs []validationJob
 	m := map[string]struct{}{}
 
 	for _, r := range doc.Refs() {
 		u := r.URI()
-		if _, ok := m[r.ResolvedNode().AbsoluteLocation().String()]; ok {
+		rn := r.ResolvedNode()
+		loc := rn.AbsoluteLocation()
+		if _, ok := m[loc.String()]; ok {
 			continue
 		} else {
-			m[r.ResolvedNode().AbsoluteLocation().String()] = struct{}{}
+			m[loc.String()] = struct{}{}
 		}
 		if u.Path != "" || u.Host != "" {
-			if s, ok := r.ResolvedNode().(*Schema); ok {
-				sd := dialect
-				if s.Schema != nil {
-					sd = s.Schema
-				}
-				d, err := s.MarshalJSON()
-				if err != nil {
-					return fmt.Errorf("failed to marshal schema: %w", err)
-				}
-				if err = sv.Validate(d, s.AbsoluteLocation(), KindSchema, *doc.OpenAPI, *sd); err != nil {
-					return err
-				}
-			} else {
-				d, err := r.ResolvedNode().MarshalJSON()
-				if err != nil {
-					return fmt.Errorf("failed to marshal node: %w", err)
-				}
+			job := validationJob{
+				node:     rn,
+				kind:     rn.Kind(),
+				location: loc,
+				dialect:  dialect,
+			}
+			if s, ok := rn.(*Schema); ok && s.Schema != nil {
+				job.dialect = *s.Schema
+			}
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs
+}
 
-				if err = sv.Validate(d, r.ResolvedNode().AbsoluteLocation(), r.ResolvedNode().Kind(), *doc.OpenAPI, *dialect); err != nil {
-					return err
-				}
+// validationJob is a node which is validated by ValidateDocument.
+type validationJob struct {
+	node     Node
+	kind     Kind
+	location uri.URI
+	dialect  uri.URI
+}
+
+func (sv *StdValidator) concurrency() int {
+	if sv.Concurrency > 0 {
+		return sv.Concurrency
+	}
+	return runtime.GOMAXPROCS(0)
+}
+
+// validateJobs marshals and validates each job, distributing them across up
+// to sv.Concurrency goroutines. Each node is only marshaled once. If more than
+// one job fails, the error of the first in jobs is returned so that the result
+// does not depend on scheduling.
+func (sv *StdValidator) validateJobs(jobs []validationJob, openapi semver.Version) error {
+	for _, err := range sv.runJobs(jobs, openapi, true) {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runJobs validates each job and returns the error of each, by index. If
+// failFast is true and the jobs are validated serially, runJobs stops at the
+// first error.
+func (sv *StdValidator) runJobs(jobs []validationJob, openapi semver.Version, failFast bool) []error {
+	errs := make([]error, len(jobs))
+	workers := sv.concurrency()
+	if workers > len(jobs) {
+		workers = len(jobs)
+	}
+	if workers <= 1 {
+		for i, job := range jobs {
+			if errs[i] = sv.validateJob(job, openapi); errs[i] != nil && failFast {
+				break
 			}
 		}
+		return errs
+	}
+	next := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				errs[i] = sv.validateJob(jobs[i], openapi)
+			}
+		}()
+	}
+	for i := range jobs {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	return errs
+}
+
+func (sv *StdValidator) validateJob(job validationJob, openapi semver.Version) error {
+	d, err := sv.Cache.marshal(job.node, job.location)
+	if err != nil {
+		switch job.kind {
+		case KindDocument:
+			return fmt.Errorf("failed to marshal document: %w", err)
+		case KindSchema:
+			return fmt.Errorf("failed to marshal schema: %w", err)
+		default:
+			return fmt.Errorf("failed to marshal node: %w", err)
+		}
+	}
+	if err = sv.Validate(d, job.location, job.kind, openapi, job.dialect); err != nil {
+		return NewValidationError(err, job.kind, job.location)
 	}
 	return nil
 }
@@ -194,6 +509,9 @@ func (sv *StdValidator) Validate(data []byte, resource uri.URI, kind Kind, opena
 
 	if kind == KindSchema {
 		schema, ok := sv.Schemas.JSONSchema[jsonschema]
+		if !ok {
+			schema, ok = sv.Schemas.JSONSchema[sv.Dialects.base(jsonschema)]
+		}
 		if !ok {
 			return fmt.Errorf("openapi: no schema found for %q", jsonschema)
 		}
@@ -230,15 +548,37 @@ type CompiledSchema interface {
 	Validate(data interface{}) error
 }
 
-// // Compiler is an interface satisfied by any type which manages and compiles
-// // resources (received in the form of io.Reader) based off of a URIs (including
-// // fragments).
-// //
-// // github.com/santhosh-tekuri/jsonschema/v5 satisfies this interface.
-// type Compiler interface {
-// 	AddResource(id string, r io.Reader) error
-// 	Compile(url string) (CompiledSchema, error)
-// }
+// Compiler is an interface satisfied by any type which manages and compiles
+// resources (received in the form of io.Reader) based off of a URIs (including
+// fragments).
+//
+// Compiler allows for JSON Schema engines other than
+// github.com/santhosh-tekuri/jsonschema/v5 to be used by StdValidator. The
+// engine must be able to compile the OpenAPI schema's definitions by URI with a
+// JSON pointer fragment (e.g. "https://spec.openapis.org/oas/3.1/schema/2022-02-27#/$defs/operation").
+//
+// JSONSchemaCompiler adapts a *jsonschema.Compiler to Compiler.
+type Compiler interface {
+	AddResource(id string, r io.Reader) error
+	Compile(url string) (CompiledSchema, error)
+}
+
+// JSONSchemaCompiler is an adapter for *jsonschema.Compiler which satisfies
+// the Compiler interface.
+type JSONSchemaCompiler struct {
+	*jsonschema.Compiler
+}
+
+var _ Compiler = JSONSchemaCompiler{}
+
+// Compile compiles the schema at url.
+func (c JSONSchemaCompiler) Compile(url string) (CompiledSchema, error) {
+	s, err := c.Compiler.Compile(url)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
 
 // CompiledSchemas are used in the the StdValidator
 type CompiledSchemas struct {
@@ -260,12 +600,27 @@ func SetupCompiler(compiler *jsonschema.Compiler, resources ...fs.FS) (*jsonsche
 	if compiler == nil {
 		return nil, errors.New("openapi: compiler is required")
 	}
+	if err := AddCompilerResources(JSONSchemaCompiler{compiler}, resources...); err != nil {
+		return nil, err
+	}
+	return compiler, nil
+}
+
+// AddCompilerResources adds the embedded OpenAPI and JSON Schema resources,
+// along with each .json file found in resources, to compiler.
+//
+// It is the equivalent of SetupCompiler for alternative Compiler
+// implementations.
+func AddCompilerResources(compiler Compiler, resources ...fs.FS) error {
+	if compiler == nil {
+		return errors.New("openapi: compiler is required")
+	}
 	resources = append([]fs.FS{embeddedSchemas}, resources...)
 	err := addCompilerResources(compiler, resources)
 	if err != nil {
-		return nil, fmt.Errorf("failed to add resources to compiler: %w", err)
+		return fmt.Errorf("failed to add resources to compiler: %w", err)
 	}
-	return compiler, nil
+	return nil
 }
 
 // addCompilerResources adds the following schemas to a compiler:
@@ -273,7 +628,7 @@ func SetupCompiler(compiler *jsonschema.Compiler, resources ...fs.FS) (*jsonsche
 //   - OpenAPI 3.0 ("https://spec.openapis.org/oas/3.0/schema/2021-09-28")
 //   - JSON Schema 2020-12
 //   - JSON Schema 2019-09
-func addCompilerResources(compiler *jsonschema.Compiler, dirs []fs.FS) error {
+func addCompilerResources(compiler Compiler, dirs []fs.FS) error {
 	var err error
 	for _, dir := range dirs {
 		err = fs.WalkDir(dir, ".", func(path string, d fs.DirEntry, err error) error {
@@ -353,6 +708,12 @@ func openAPISchemaVMap(openAPISchemas []map[string]uri.URI) (map[semver.Version]
 //	{ "3.1": "https://spec.openapis.org/oas/3.1/schema/2022-02-27)" }
 //	{ "3.0": "https://spec.openapis.org/oas/3.0/schema/2021-09-28"  }
 func CompileSchemas(compiler *jsonschema.Compiler, openAPISchemas ...map[string]uri.URI) (CompiledSchemas, error) {
+	return CompileSchemasWithCompiler(JSONSchemaCompiler{compiler}, openAPISchemas...)
+}
+
+// CompileSchemasWithCompiler is the equivalent of CompileSchemas for
+// alternative Compiler implementations.
+func CompileSchemasWithCompiler(compiler Compiler, openAPISchemas ...map[string]uri.URI) (CompiledSchemas, error) {
 	var err error
 
 	openapis, err := compileOpenAPISchemas(compiler, openAPISchemas)
@@ -369,9 +730,9 @@ func CompileSchemas(compiler *jsonschema.Compiler, openAPISchemas ...map[string]
 	}, nil
 }
 
-func compileJSONSchemaSchemas(c *jsonschema.Compiler) (map[uri.URI]CompiledSchema, error) {
+func compileJSONSchemaSchemas(c Compiler) (map[uri.URI]CompiledSchema, error) {
 	var err error
-	jsonschemas := make(map[uri.URI]CompiledSchema, 2)
+	jsonschemas := make(map[uri.URI]CompiledSchema, 3)
 	jsonschemas[JSONSchemaDialect202012], err = c.Compile(JSON_SCHEMA_2020_12)
 	if err != nil {
 		return nil, err
@@ -380,10 +741,14 @@ func compileJSONSchemaSchemas(c *jsonschema.Compiler) (map[uri.URI]CompiledSchem
 	if err != nil {
 		return nil, err
 	}
+	jsonschemas[JSONSchemaDialect07], err = c.Compile(JSON_SCHEMA_07)
+	if err != nil {
+		return nil, err
+	}
 	return jsonschemas, nil
 }
 
-func compileOpenAPISchemas(c *jsonschema.Compiler, openAPISchemas []map[string]uri.URI) (map[semver.Version]map[Kind]CompiledSchema, error) {
+func compileOpenAPISchemas(c Compiler, openAPISchemas []map[string]uri.URI) (map[semver.Version]map[Kind]CompiledSchema, error) {
 	vm, err := openAPISchemaVMap(openAPISchemas)
 	if err != nil {
 		return nil, err
@@ -399,7 +764,7 @@ func compileOpenAPISchemas(c *jsonschema.Compiler, openAPISchemas []map[string]u
 	return compiled, nil
 }
 
-func compileOpenAPISchemasFor(compiler *jsonschema.Compiler, uri uri.URI) (map[Kind]CompiledSchema, error) {
+func compileOpenAPISchemasFor(compiler Compiler, uri uri.URI) (map[Kind]CompiledSchema, error) {
 	uri.Fragment = ""
 	uri.RawFragment = ""
 	spec := uri.String()
@@ -0,0 +1,280 @@
+package openapi
+
+import (
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/Masterminds/semver"
+	"github.com/chanced/uri"
+)
+
+var _ Validator = (*IncrementalValidator)(nil)
+
+// IncrementalValidator is a Validator which retains the result of validating
+// each subtree of a Document so that, after the Document is edited, only the
+// subtrees affected by the edits are re-marshaled and re-validated. It is
+// intended for long-lived Documents which are repeatedly edited and
+// validated, such as those of interactive editors.
+//
+// A Document is divided into the following subtrees:
+//   - each PathItem of paths and webhooks
+//   - each component of components
+//   - each node referenced from another resource
+//   - the remainder of the Document
+//
+// A subtree is validated by ValidateDocument if it has not yet been validated
+// or if it has been invalidated since. Edits made with
+// Document.ApplyJSONPatch or Document.ApplyMergePatch invalidate the subtrees
+// they affect, provided the IncrementalValidator is the PatchOpts.Validator.
+// All other edits, such as assigning fields, must be reported with Invalidate
+// or InvalidateNode.
+//
+// The validations of StdValidator.ValidateDocument which do not rely on JSON
+// Schema (e.g. examples and extensions) do not marshal the Document and are
+// always performed on the entire Document.
+//
+// An IncrementalValidator is safe for concurrent use.
+type IncrementalValidator struct {
+	validator *StdValidator
+
+	mu sync.Mutex
+	// results are the errors, if any, of each validated subtree, keyed by
+	// the absolute location of the subtree
+	results     map[string]error
+	invalidated []uri.URI
+	validated   int
+	total       int
+}
+
+// NewIncrementalValidator returns an IncrementalValidator which validates with
+// v.
+func NewIncrementalValidator(v *StdValidator) (*IncrementalValidator, error) {
+	if v == nil {
+		return nil, errors.New("openapi: validator is required")
+	}
+	return &IncrementalValidator{
+		validator: v,
+		results:   map[string]error{},
+	}, nil
+}
+
+// ValidateDocument validates each subtree of doc which has not been
+// validated or has been invalidated since it was last validated. The
+// results of all other subtrees are reused.
+//
+// If more than one subtree is invalid, the error of the first, in the order
+// of the Document, is returned.
+func (iv *IncrementalValidator) ValidateDocument(doc *Document) error {
+	dialect, err := iv.validator.checkDocument(doc)
+	if err != nil {
+		return err
+	}
+	jobs := incrementalValidationJobs(doc, dialect)
+
+	iv.mu.Lock()
+	defer iv.mu.Unlock()
+
+	stale := iv.stale(jobs)
+	var pending []validationJob
+	for _, job := range jobs {
+		key := job.location.String()
+		if _, ok := iv.results[key]; !ok || stale[key] {
+			pending = append(pending, job)
+		}
+	}
+	results := make(map[string]error, len(jobs))
+	for i, err := range iv.validator.runJobs(pending, *doc.OpenAPI, false) {
+		results[pending[i].location.String()] = err
+	}
+	for _, job := range jobs {
+		key := job.location.String()
+		if _, ok := results[key]; !ok {
+			results[key] = iv.results[key]
+		}
+	}
+	iv.results = results
+	iv.invalidated = nil
+	iv.validated = len(pending)
+	iv.total = len(jobs)
+
+	for _, job := range jobs {
+		if err := results[job.location.String()]; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Validate validates data with the StdValidator of iv. The result is not
+// retained.
+func (iv *IncrementalValidator) Validate(data []byte, resource uri.URI, kind Kind, openapi semver.Version, jsonschema uri.URI) error {
+	return iv.validator.Validate(data, resource, kind, openapi, jsonschema)
+}
+
+// Invalidate reports that the node at loc, an absolute location (e.g.
+// "https://example.com/openapi.json#/paths/~1pets/get"), has been edited.
+// The subtree containing loc, along with any subtrees within loc, will be
+// validated by the next call to ValidateDocument. The entries of the Cache of
+// the StdValidator, if any, are invalidated as well.
+func (iv *IncrementalValidator) Invalidate(loc uri.URI) {
+	iv.validator.Cache.Invalidate(loc)
+	iv.mu.Lock()
+	defer iv.mu.Unlock()
+	iv.invalidated = append(iv.invalidated, loc)
+}
+
+// InvalidateNode reports that n, or one of its descendants, has been edited.
+// See Invalidate.
+func (iv *IncrementalValidator) InvalidateNode(n Node) {
+	if n == nil {
+		return
+	}
+	iv.Invalidate(n.AbsoluteLocation())
+}
+
+// Reset discards all retained results, along with the entries of the Cache
+// of the StdValidator, so that the next call to ValidateDocument validates
+// every subtree.
+func (iv *IncrementalValidator) Reset() {
+	iv.validator.Cache.Reset()
+	iv.mu.Lock()
+	defer iv.mu.Unlock()
+	iv.results = map[string]error{}
+	iv.invalidated = nil
+}
+
+// Validated reports the number of subtrees which were validated by the last
+// call to ValidateDocument along with the total number of subtrees of the
+// Document.
+func (iv *IncrementalValidator) Validated() (validated int, total int) {
+	iv.mu.Lock()
+	defer iv.mu.Unlock()
+	return iv.validated, iv.total
+}
+
+// stale returns the keys of the subtrees of jobs which have been
+// invalidated. An invalidated location invalidates the innermost subtree
+// which contains it as well as each subtree it contains.
+func (iv *IncrementalValidator) stale(jobs []validationJob) map[string]bool {
+	stale := map[string]bool{}
+	for _, loc := range iv.invalidated {
+		var innermost *validationJob
+		for i, job := range jobs {
+			switch {
+			case containsLocation(job.location, loc):
+				if innermost == nil || len(job.location.Fragment) > len(innermost.location.Fragment) {
+					innermost = &jobs[i]
+				}
+			case containsLocation(loc, job.location):
+				stale[job.location.String()] = true
+			}
+		}
+		if innermost != nil {
+			stale[innermost.location.String()] = true
+		}
+	}
+	return stale
+}
+
+// containsLocation reports whether the node at location b is, or is a
+// descendant of, the node at location a.
+func containsLocation(a, b uri.URI) bool {
+	fa, fb := a.Fragment, b.Fragment
+	a.Fragment, a.RawFragment = "", ""
+	b.Fragment, b.RawFragment = "", ""
+	if a.String() != b.String() {
+		return false
+	}
+	fa, fb = strings.TrimSuffix(fa, "/"), strings.TrimSuffix(fb, "/")
+	return fa == fb || strings.HasPrefix(fb, fa+"/")
+}
+
+// incrementalValidationJobs returns a validationJob for each subtree of doc
+// (see IncrementalValidator).
+func incrementalValidationJobs(doc *Document, dialect uri.URI) []validationJob {
+	// the remainder of the Document is validated with each subtree removed
+	root := *doc
+	if doc.Paths != nil {
+		root.Paths = &Paths{Extensions: doc.Paths.Extensions}
+	}
+	if doc.Webhooks != nil {
+		root.Webhooks = &PathItemMap{}
+	}
+	if c := doc.Components; c != nil {
+		root.Components = &Components{Extensions: c.Extensions}
+	}
+	jobs := []validationJob{{
+		node:     &root,
+		kind:     KindDocument,
+		location: doc.AbsoluteLocation(),
+		dialect:  dialect,
+	}}
+	if doc.Paths != nil {
+		for _, item := range doc.Paths.Items {
+			if item.Value == nil {
+				continue
+			}
+			jobs = append(jobs, validationJob{
+				node:     item.Value,
+				kind:     KindPathItem,
+				location: item.Value.AbsoluteLocation(),
+				dialect:  dialect,
+			})
+		}
+	}
+	jobs = appendComponentJobs(jobs, doc.Webhooks, dialect)
+	if c := doc.Components; c != nil {
+		if c.Schemas != nil {
+			for _, e := range c.Schemas.Items {
+				if e.Schema == nil {
+					continue
+				}
+				job := validationJob{
+					node:     e.Schema,
+					kind:     KindSchema,
+					location: e.Schema.AbsoluteLocation(),
+					dialect:  dialect,
+				}
+				if e.Schema.Schema != nil {
+					job.dialect = *e.Schema.Schema
+				}
+				jobs = append(jobs, job)
+			}
+		}
+		jobs = appendComponentJobs(jobs, c.Responses, dialect)
+		jobs = appendComponentJobs(jobs, c.Parameters, dialect)
+		jobs = appendComponentJobs(jobs, c.RequestBodies, dialect)
+		jobs = appendComponentJobs(jobs, c.Headers, dialect)
+		jobs = appendComponentJobs(jobs, c.SecuritySchemes, dialect)
+		jobs = appendComponentJobs(jobs, c.Links, dialect)
+		jobs = appendComponentJobs(jobs, c.Callbacks, dialect)
+		jobs = appendComponentJobs(jobs, c.PathItems, dialect)
+		jobs = appendComponentJobs(jobs, c.Examples, dialect)
+	}
+	return append(jobs, remoteValidationJobs(doc, dialect)...)
+}
+
+func appendComponentJobs[T refable](jobs []validationJob, cm *ComponentMap[T], dialect uri.URI) []validationJob {
+	if cm == nil {
+		return jobs
+	}
+	for _, e := range cm.Items {
+		c := e.Component
+		if c == nil {
+			continue
+		}
+		job := validationJob{dialect: dialect}
+		switch {
+		case c.IsReference():
+			job.node, job.kind = c.Reference, KindReference
+		case !c.Object.isNil():
+			job.node, job.kind = c.Object, c.ObjectKind()
+		default:
+			continue
+		}
+		job.location = job.node.AbsoluteLocation()
+		jobs = append(jobs, job)
+	}
+	return jobs
+}
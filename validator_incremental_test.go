@@ -0,0 +1,111 @@
+package openapi_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/chanced/openapi"
+	"github.com/chanced/uri"
+)
+
+func TestIncrementalValidator(t *testing.T) {
+	data := []byte(`{
+		"openapi": "3.1.0",
+		"info": { "title": "Pets", "version": "1.0.0" },
+		"paths": {
+			"/pets": {
+				"get": {
+					"responses": { "200": { "$ref": "#/components/responses/Pets" } }
+				}
+			},
+			"/pets/{petId}": {
+				"get": {
+					"summary": "Get a pet",
+					"responses": { "200": { "description": "ok" } }
+				}
+			}
+		},
+		"components": {
+			"schemas": {
+				"Pet": { "type": "object" }
+			},
+			"responses": {
+				"Pets": { "description": "pets" }
+			}
+		}
+	}`)
+	fn := func(ctx context.Context, u uri.URI, kind openapi.Kind) (openapi.Kind, []byte, error) {
+		return openapi.KindDocument, data, nil
+	}
+	sv, err := openapi.NewOfflineValidator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := openapi.Load(context.Background(), "https://example.com/openapi.json", sv, fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	iv, err := openapi.NewIncrementalValidator(sv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectValidated := func(expected int) {
+		t.Helper()
+		if n, total := iv.Validated(); n != expected || total != 5 {
+			t.Errorf("expected %d of 5 subtrees to be validated, got %d of %d", expected, n, total)
+		}
+	}
+
+	if err = iv.ValidateDocument(doc); err != nil {
+		t.Fatal(err)
+	}
+	expectValidated(5)
+	if err = iv.ValidateDocument(doc); err != nil {
+		t.Fatal(err)
+	}
+	expectValidated(0)
+
+	patch := []byte(`[{ "op": "replace", "path": "/paths/~1pets~1{petId}/get/summary", "value": "Get one pet" }]`)
+	if err = doc.ApplyJSONPatch(patch, openapi.PatchOpts{Validator: iv}); err != nil {
+		t.Fatal(err)
+	}
+	expectValidated(1)
+
+	res := doc.Components.Responses.Get("Pets").Object
+	res.Description = ""
+	iv.InvalidateNode(res)
+	err = iv.ValidateDocument(doc)
+	var ve *openapi.ValidationError
+	if !errors.As(err, &ve) {
+		t.Fatalf("expected a ValidationError, got %v", err)
+	}
+	if ve.URI.Fragment != "/components/responses/Pets" {
+		t.Errorf("expected the error to be located at the response, got %s", ve.URI.String())
+	}
+	expectValidated(1)
+	if err = iv.ValidateDocument(doc); err == nil {
+		t.Error("expected the retained error to be returned")
+	}
+	expectValidated(0)
+
+	if err = doc.ApplyMergePatch([]byte(`{"components": {"responses": {"Pets": {"description": "pets"}}}}`), openapi.PatchOpts{Validator: iv}); err != nil {
+		t.Fatal(err)
+	}
+	expectValidated(1)
+
+	patch = []byte(`[{ "op": "remove", "path": "/paths/~1pets/get/responses/200" }, { "op": "add", "path": "/paths/~1pets/get/responses/200", "value": { "summary": 1 } }]`)
+	if err = doc.ApplyJSONPatch(patch, openapi.PatchOpts{Validator: iv}); err == nil {
+		t.Fatal("expected the patch to be rejected")
+	}
+	if err = iv.ValidateDocument(doc); err != nil {
+		t.Errorf("expected the retained document to be revalidated, got %v", err)
+	}
+	expectValidated(1)
+
+	iv.Reset()
+	if err = iv.ValidateDocument(doc); err != nil {
+		t.Fatal(err)
+	}
+	expectValidated(5)
+}
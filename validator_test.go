@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"io/fs"
 	"strings"
@@ -133,3 +134,159 @@ func TestValidator_Schema(t *testing.T) {
 
 	v.Validate(d, *uri.MustParse("testdata/schemas/string-map.yaml"), openapi.KindSchema, openapi.Version3_1, openapi.JSONSchemaDialect202012)
 }
+
+func TestNewOfflineValidator(t *testing.T) {
+	ctx := context.Background()
+	v, err := openapi.NewOfflineValidator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for p, valid := range map[string]bool{
+		"testdata/documents/validation/pass/servers.yaml": true,
+		"testdata/documents/validation/fail/servers.yaml": false,
+	} {
+		f, err := testdata.Open(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fn := func(_ context.Context, uri uri.URI, kind openapi.Kind) (openapi.Kind, []byte, error) {
+			d, err := io.ReadAll(f)
+			return openapi.KindDocument, d, err
+		}
+		_, err = openapi.Load(ctx, p, v, fn)
+		if valid && err != nil {
+			t.Errorf("expected %s to be valid, received: %v", p, err)
+		}
+		if !valid && err == nil {
+			t.Errorf("expected %s to be invalid", p)
+		}
+	}
+}
+
+type countingCompiler struct {
+	openapi.Compiler
+	compiled int
+}
+
+func (c *countingCompiler) Compile(url string) (openapi.CompiledSchema, error) {
+	c.compiled++
+	return c.Compiler.Compile(url)
+}
+
+func TestNewValidatorWithCompiler(t *testing.T) {
+	c := &countingCompiler{Compiler: openapi.JSONSchemaCompiler{Compiler: jsonschema.NewCompiler()}}
+	if err := openapi.AddCompilerResources(c); err != nil {
+		t.Fatal(err)
+	}
+	v, err := openapi.NewValidatorWithCompiler(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.compiled == 0 {
+		t.Error("expected schemas to be compiled with the provided compiler")
+	}
+	if _, ok := v.Schemas.OpenAPI[openapi.Version3_1][openapi.KindOperation]; !ok {
+		t.Error("expected Operation schema to be compiled")
+	}
+}
+
+func TestValidateExamples(t *testing.T) {
+	var mt openapi.MediaType
+	if err := mt.SetExample(map[string]string{"name": "fido"}); err != nil {
+		t.Fatal(err)
+	}
+	if string(mt.Example) != `{"name":"fido"}` {
+		t.Errorf("expected example to be marshaled, got %s", mt.Example)
+	}
+	mt.Examples = &openapi.ExampleMap{Items: []*openapi.ComponentEntry[*openapi.Example]{
+		{Key: "fido", Component: &openapi.Component[*openapi.Example]{Object: &openapi.Example{Value: []byte(`{"name":"fido"}`)}}},
+	}}
+	if err := mt.ValidateExamples(); !errors.Is(err, openapi.ErrExampleAndExamples) {
+		t.Errorf("expected ErrExampleAndExamples, got %v", err)
+	}
+	mt.SetExamples(mt.Examples)
+	if mt.Example != nil {
+		t.Error("expected SetExamples to clear example")
+	}
+	if err := mt.ValidateExamples(); err != nil {
+		t.Error(err)
+	}
+
+	p := openapi.Parameter{Examples: mt.Examples}
+	if err := p.SetExample("fido"); err != nil {
+		t.Fatal(err)
+	}
+	if p.Examples != nil {
+		t.Error("expected SetExample to clear examples")
+	}
+
+	v, err := openapi.NewOfflineValidator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := openapi.Header{Example: []byte(`"fido"`), Examples: mt.Examples}
+	doc := openapi.Document{
+		Components: &openapi.Components{
+			Headers: &openapi.HeaderMap{Items: []*openapi.ComponentEntry[*openapi.Header]{
+				{Key: "X-Pet", Component: &openapi.Component[*openapi.Header]{Object: &h}},
+			}},
+		},
+	}
+	if err = v.ValidateDocument(&doc); !errors.Is(err, openapi.ErrExampleAndExamples) {
+		t.Errorf("expected ErrExampleAndExamples, got %v", err)
+	}
+}
+
+func TestValidateDocumentConcurrency(t *testing.T) {
+	ctx := context.Background()
+	resources := map[string]string{
+		"https://example.com/openapi.json": `{
+			"openapi": "3.1.0",
+			"info": { "title": "concurrency", "version": "1.0.0" },
+			"components": {
+				"schemas": {
+					"Name": { "$ref": "https://example.com/schemas/name.json" },
+					"Age": { "$ref": "https://example.com/schemas/age.json" },
+					"Email": { "$ref": "https://example.com/schemas/email.json" }
+				}
+			}
+		}`,
+		"https://example.com/schemas/name.json":  `{ "type": "string" }`,
+		"https://example.com/schemas/age.json":   `{ "type": "integer", "minLength": -1 }`,
+		"https://example.com/schemas/email.json": `{ "type": "string", "format": "email" }`,
+	}
+	fn := func(_ context.Context, u uri.URI, kind openapi.Kind) (openapi.Kind, []byte, error) {
+		u.Fragment = ""
+		data, ok := resources[u.String()]
+		if !ok {
+			return 0, nil, fmt.Errorf("resource not found: %s", u.String())
+		}
+		return kind, []byte(data), nil
+	}
+	doc, err := openapi.Load(ctx, "https://example.com/openapi.json", NoopValidator{}, fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := openapi.SetupCompiler(jsonschema.NewCompiler())
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := openapi.NewValidator(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var expected string
+	for _, concurrency := range []int{1, 0, 8} {
+		v.Concurrency = concurrency
+		err = v.ValidateDocument(doc)
+		if err == nil {
+			t.Fatalf("expected an error with Concurrency %d", concurrency)
+		}
+		if expected == "" {
+			expected = err.Error()
+		} else if err.Error() != expected {
+			t.Errorf("expected error with Concurrency %d to be %q, got %q", concurrency, expected, err.Error())
+		}
+	}
+}
@@ -0,0 +1,278 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/Masterminds/semver"
+	"github.com/chanced/jsonpointer"
+	"github.com/chanced/jsonx"
+	"github.com/chanced/uri"
+)
+
+// VersionCapabilities describes how Documents of an OpenAPI version which
+// does not satisfy SupportedVersions are loaded when LoadOpts.Tolerant is
+// set.
+type VersionCapabilities struct {
+	// Version is the major and minor version (e.g. 3.2). The patch version
+	// is ignored.
+	Version semver.Version
+	// ValidateAs is the supported version which Documents of Version are
+	// validated and interpreted as (e.g. 3.1). It determines the default
+	// JSON Schema dialect.
+	ValidateAs semver.Version
+	// Fields are the fields introduced by Version, keyed by the Kind of the
+	// object they belong to, which are not modeled. They are preserved in
+	// the Extensions of their object.
+	Fields map[Kind][]Text
+}
+
+// Introduced reports whether field of objects of kind was introduced by the
+// version.
+func (vc VersionCapabilities) Introduced(kind Kind, field Text) bool {
+	for _, f := range vc.Fields[kind] {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// VersionRegistry is a registry of the VersionCapabilities of OpenAPI
+// versions, consulted by Load when LoadOpts.Tolerant is set.
+//
+// A VersionRegistry is safe for concurrent use.
+type VersionRegistry struct {
+	mu       sync.RWMutex
+	versions map[string]VersionCapabilities
+}
+
+// NewVersionRegistry returns a VersionRegistry with OpenAPI 3.2 registered
+// to be validated as 3.1.
+func NewVersionRegistry() *VersionRegistry {
+	vr := &VersionRegistry{versions: map[string]VersionCapabilities{}}
+	err := vr.Register(VersionCapabilities{
+		Version:    Version3_2,
+		ValidateAs: Version3_1,
+		Fields: map[Kind][]Text{
+			KindDocument:       {"$self"},
+			KindPathItem:       {"query", "additionalOperations"},
+			KindTag:            {"summary", "parent", "kind"},
+			KindMediaType:      {"itemSchema", "itemEncoding", "prefixEncoding"},
+			KindEncoding:       {"encoding", "itemEncoding", "prefixEncoding"},
+			KindResponse:       {"summary"},
+			KindServer:         {"name"},
+			KindExample:        {"dataValue", "serializedValue"},
+			KindDiscriminator:  {"defaultMapping"},
+			KindXML:            {"nodeType"},
+			KindSecurityScheme: {"oauth2MetadataUrl", "deprecated"},
+			KindOAuthFlows:     {"deviceAuthorization"},
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+	return vr
+}
+
+func versionKey(v semver.Version) string {
+	return fmt.Sprintf("%d.%d", v.Major(), v.Minor())
+}
+
+// Register registers vc, replacing the VersionCapabilities of the same major
+// and minor version, if any.
+//
+// An error is returned if vc.ValidateAs does not satisfy SupportedVersions.
+func (vr *VersionRegistry) Register(vc VersionCapabilities) error {
+	validateAs, err := semver.NewVersion(versionKey(vc.ValidateAs))
+	if err != nil {
+		return err
+	}
+	if !SupportedVersions.Check(validateAs) {
+		return &UnsupportedVersionError{Version: validateAs.String()}
+	}
+	vr.mu.Lock()
+	defer vr.mu.Unlock()
+	if vr.versions == nil {
+		vr.versions = map[string]VersionCapabilities{}
+	}
+	vr.versions[versionKey(vc.Version)] = vc
+	return nil
+}
+
+// Lookup returns the VersionCapabilities of the major and minor version of v.
+func (vr *VersionRegistry) Lookup(v semver.Version) (VersionCapabilities, bool) {
+	if vr == nil {
+		return VersionCapabilities{}, false
+	}
+	vr.mu.RLock()
+	defer vr.mu.RUnlock()
+	vc, ok := vr.versions[versionKey(v)]
+	return vc, ok
+}
+
+// versionCapabilities returns the VersionCapabilities of v if v does not
+// satisfy SupportedVersions and is tolerated
+func (l *loader) versionCapabilities(v *semver.Version, u uri.URI) (VersionCapabilities, bool, error) {
+	if !l.opts.Tolerant || SupportedVersions.Check(v) {
+		return VersionCapabilities{}, false, nil
+	}
+	versions := l.opts.Versions
+	if versions == nil {
+		versions = NewVersionRegistry()
+	}
+	vc, ok := versions.Lookup(*v)
+	if !ok {
+		return vc, false, NewError(&UnsupportedVersionError{Version: v.String()}, u)
+	}
+	validateAs, err := semver.NewVersion(versionKey(vc.ValidateAs))
+	if err != nil {
+		return vc, false, NewError(err, u)
+	}
+	vc.ValidateAs = *validateAs
+	l.opts.Hooks.warning(WarningEvent{
+		Location: u,
+		Message:  fmt.Sprintf("OpenAPI %s is not supported; loading as OpenAPI %s", v, validateAs),
+	})
+	return vc, true, nil
+}
+
+// validateAs validates doc with the Validator as if it were of version v
+func (l *loader) validateAs(doc *Document, v semver.Version) error {
+	orig := doc.OpenAPI
+	doc.OpenAPI = &v
+	defer func() { doc.OpenAPI = orig }()
+	return l.validator.ValidateDocument(doc)
+}
+
+// preserveUnknownFields adds the fields of the objects of doc which are not
+// modeled, as found in data, to the Extensions of their object. Schemas are
+// skipped as their unknown keywords are preserved in Keywords.
+func (l *loader) preserveUnknownFields(doc *Document, data []byte, vc VersionCapabilities) error {
+	var raw interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	return walkLocalNodes(doc, func(n node) error {
+		if _, ok := n.(*Schema); ok {
+			return nil
+		}
+		e, ok := n.(extended)
+		if !ok {
+			return nil
+		}
+		x, ok := n.(extender)
+		if !ok {
+			return nil
+		}
+		known := jsonFields(reflect.TypeOf(n))
+		if len(known) == 0 {
+			// maps (e.g. Paths) do not have fields
+			return nil
+		}
+		obj, ok := rawValueAt(raw, n.location().RelativeLocation()).(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		keys := make([]string, 0, len(obj))
+		for k := range obj {
+			if !known[k] && !IsExtensionKey(Text(k)) {
+				keys = append(keys, k)
+			}
+		}
+		if len(keys) == 0 {
+			return nil
+		}
+		sort.Strings(keys)
+		exts := e.exts()
+		if exts == nil {
+			exts = Extensions{}
+		}
+		for _, k := range keys {
+			b, err := json.Marshal(obj[k])
+			if err != nil {
+				return err
+			}
+			exts[Text(k)] = jsonx.RawMessage(b)
+			msg := fmt.Sprintf("%s field %q is not supported", n.Kind(), k)
+			if vc.Introduced(n.Kind(), Text(k)) {
+				msg += fmt.Sprintf(" (introduced in OpenAPI %d.%d)", vc.Version.Major(), vc.Version.Minor())
+			}
+			l.opts.Hooks.warning(WarningEvent{
+				Location: n.location().AppendLocation(k).AbsoluteLocation(),
+				Message:  msg + "; it is preserved in Extensions",
+			})
+		}
+		x.setExts(exts)
+		return nil
+	})
+}
+
+var jsonFieldsCache sync.Map // map[reflect.Type]map[string]bool
+
+// jsonFields returns the names of the JSON fields of the struct t (or
+// pointer to struct), including those of embedded structs
+func jsonFields(t reflect.Type) map[string]bool {
+	if v, ok := jsonFieldsCache.Load(t); ok {
+		return v.(map[string]bool)
+	}
+	fields := map[string]bool{}
+	var collect func(t reflect.Type)
+	collect = func(t reflect.Type) {
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct {
+			return
+		}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			tag := f.Tag.Get("json")
+			if tag == "-" {
+				continue
+			}
+			name := strings.Split(tag, ",")[0]
+			if f.Anonymous && name == "" {
+				collect(f.Type)
+				continue
+			}
+			if !f.IsExported() {
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			fields[name] = true
+		}
+	}
+	collect(t)
+	jsonFieldsCache.Store(t, fields)
+	return fields
+}
+
+// rawValueAt returns the value of raw at ptr, or nil if it does not exist
+func rawValueAt(raw interface{}, ptr jsonpointer.Pointer) interface{} {
+	for _, tok := range ptr.Tokens() {
+		if tok == "" {
+			continue
+		}
+		switch v := raw.(type) {
+		case map[string]interface{}:
+			raw = v[tok]
+		case []interface{}:
+			i, err := strconv.Atoi(tok)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil
+			}
+			raw = v[i]
+		default:
+			return nil
+		}
+	}
+	return raw
+}
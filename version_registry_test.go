@@ -0,0 +1,131 @@
+package openapi_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Masterminds/semver"
+	"github.com/chanced/openapi"
+	"github.com/chanced/uri"
+)
+
+func TestLoadTolerant(t *testing.T) {
+	data := []byte(`{
+		"openapi": "3.2.0",
+		"$self": "https://example.com/openapi.json",
+		"info": { "title": "Pets", "version": "1.0.0" },
+		"tags": [{ "name": "pets", "summary": "Pets", "kind": "nav" }],
+		"paths": {
+			"/pets": {
+				"get": {
+					"tags": ["pets"],
+					"responses": { "200": { "description": "ok" } }
+				},
+				"query": {
+					"responses": { "200": { "description": "ok" } }
+				}
+			}
+		}
+	}`)
+	fn := func(ctx context.Context, u uri.URI, kind openapi.Kind) (openapi.Kind, []byte, error) {
+		return openapi.KindDocument, data, nil
+	}
+	v, err := openapi.NewOfflineValidator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if _, err = openapi.Load(ctx, "https://example.com/openapi.json", v, fn); err == nil {
+		t.Fatal("expected an error loading a 3.2 document without Tolerant")
+	}
+
+	var warnings []openapi.WarningEvent
+	doc, err := openapi.Load(ctx, "https://example.com/openapi.json", v, fn, openapi.LoadOpts{
+		Tolerant: true,
+		Hooks: &openapi.LoadHooks{
+			OnWarning: func(e openapi.WarningEvent) { warnings = append(warnings, e) },
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.OpenAPI.String() != "3.2.0" {
+		t.Errorf("expected the version to be preserved, got %s", doc.OpenAPI)
+	}
+	if string(doc.Extensions["$self"]) != `"https://example.com/openapi.json"` {
+		t.Errorf("expected $self to be preserved in Extensions, got %v", doc.Extensions)
+	}
+	pi := doc.Paths.Get("/pets")
+	if pi == nil || pi.Extensions["query"] == nil {
+		t.Fatalf("expected query operation to be preserved in Extensions")
+	}
+	tag := doc.Tags.Items[0]
+	if string(tag.Extensions["summary"]) != `"Pets"` || string(tag.Extensions["kind"]) != `"nav"` {
+		t.Errorf("expected tag fields to be preserved, got %v", tag.Extensions)
+	}
+
+	var found, invalid bool
+	for _, w := range warnings {
+		if strings.HasPrefix(w.Message, "document is not valid OpenAPI") {
+			invalid = true
+			if w.Message != "document is not valid OpenAPI 3.1.0" {
+				t.Errorf("expected the version to be formatted, got %q", w.Message)
+			}
+		}
+		if strings.Contains(w.Message, `"query"`) {
+			found = true
+			if w.Location.Fragment != "/paths/~1pets/query" {
+				t.Errorf("unexpected location for query: %s", w.Location.Fragment)
+			}
+			if !strings.Contains(w.Message, "introduced in OpenAPI 3.2") {
+				t.Errorf("expected the field to be attributed to 3.2: %s", w.Message)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("expected a warning for the query field, got %+v", warnings)
+	}
+	if !invalid {
+		t.Errorf("expected a warning for the validation of the document, got %+v", warnings)
+	}
+
+	b, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), `"$self":"https://example.com/openapi.json"`) {
+		t.Errorf("expected $self to round trip: %s", b)
+	}
+
+	_, err = openapi.Load(ctx, "https://example.com/openapi.json", v, func(ctx context.Context, u uri.URI, kind openapi.Kind) (openapi.Kind, []byte, error) {
+		return openapi.KindDocument, []byte(`{"openapi": "4.0.0", "info": { "title": "Pets", "version": "1.0.0" }}`), nil
+	}, openapi.LoadOpts{Tolerant: true})
+	var uve *openapi.UnsupportedVersionError
+	if !errors.As(err, &uve) {
+		t.Errorf("expected an UnsupportedVersionError for an unregistered version, got %v", err)
+	}
+}
+
+func TestVersionRegistry(t *testing.T) {
+	vr := openapi.NewVersionRegistry()
+	vc, ok := vr.Lookup(*semver.MustParse("3.2.1"))
+	if !ok || vc.ValidateAs.String() != "3.1.0" {
+		t.Fatalf("expected 3.2 to be registered, got %+v", vc)
+	}
+	if !vc.Introduced(openapi.KindPathItem, "query") || vc.Introduced(openapi.KindPathItem, "get") {
+		t.Error("unexpected Introduced result")
+	}
+	err := vr.Register(openapi.VersionCapabilities{Version: *semver.MustParse("3.3"), ValidateAs: *semver.MustParse("3.3")})
+	if err == nil {
+		t.Error("expected an error registering a version validated as an unsupported version")
+	}
+	if err = vr.Register(openapi.VersionCapabilities{Version: *semver.MustParse("3.3"), ValidateAs: openapi.Version3_1}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok = vr.Lookup(*semver.MustParse("3.3.0")); !ok {
+		t.Error("expected 3.3 to be registered")
+	}
+}
@@ -0,0 +1,248 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"strconv"
+
+	"github.com/tidwall/gjson"
+)
+
+const (
+	// ExtensionInternal is the default extension which marks a node as
+	// internal, excluding it from all views. See Document.View.
+	ExtensionInternal Text = "x-internal"
+	// ExtensionAudience is the default extension which lists the audiences
+	// of a node, either as a string or an array of strings. See
+	// Document.View.
+	ExtensionAudience Text = "x-audience"
+	// AudiencePublic is the audience of PublicView.
+	AudiencePublic Text = "public"
+)
+
+// ViewOpts configures Document.View.
+type ViewOpts struct {
+	// Audiences are the audiences of the view. Nodes annotated with an
+	// audience extension are only retained if one of their audiences is in
+	// Audiences.
+	Audiences []Text
+
+	// InternalExtension is the extension which marks a node as internal
+	// when true. The default is "x-internal".
+	InternalExtension Text
+
+	// AudienceExtension is the extension which lists the audiences of a
+	// node. The default is "x-audience".
+	AudienceExtension Text
+
+	// KeepExtensions retains the internal and audience extensions of the
+	// nodes which remain in the view. They are removed by default.
+	KeepExtensions bool
+}
+
+func (opts ViewOpts) internal() string {
+	if opts.InternalExtension != "" {
+		return opts.InternalExtension.String()
+	}
+	return ExtensionInternal.String()
+}
+
+func (opts ViewOpts) audience() string {
+	if opts.AudienceExtension != "" {
+		return opts.AudienceExtension.String()
+	}
+	return ExtensionAudience.String()
+}
+
+// PublicView returns the view of doc for the "public" audience, stripping
+// everything which is marked "x-internal" or which has an "x-audience" other
+// than "public". See Document.View.
+func PublicView(doc *Document) (*Document, error) {
+	return doc.View(ViewOpts{Audiences: []Text{AudiencePublic}})
+}
+
+// View returns a new Document containing only the parts of d which are
+// visible to opts.Audiences. d is not modified.
+//
+// A node is hidden if it is marked internal (e.g. "x-internal": true) or if
+// it lists audiences (e.g. "x-audience": ["partner"]), none of which are in
+// opts.Audiences. A Reference to a hidden component is itself hidden. The
+// following hidden nodes are removed:
+//   - PathItems of Paths and webhooks
+//   - Operations
+//   - Parameters
+//   - components
+//   - properties of Schemas, which are also removed from the Schema's
+//     required properties
+//   - subschemas of allOf, anyOf, and oneOf
+//
+// Components which are no longer referenced, and tags which are no longer
+// used, are then removed as they are by Document.Filter.
+func (d *Document) View(opts ViewOpts) (*Document, error) {
+	data, err := d.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	v := viewer{
+		opts:   opts,
+		hidden: map[string]bool{},
+	}
+	root := gjson.ParseBytes(data)
+	root.Get("components").ForEach(func(kind, m gjson.Result) bool {
+		m.ForEach(func(key, value gjson.Result) bool {
+			if v.hides(value) {
+				v.hidden[joinPointerTokens([]string{"components", kind.String(), key.String()})] = true
+			}
+			return true
+		})
+		return true
+	})
+
+	b := bytes.Buffer{}
+	v.write(&b, root, nil)
+	var res Document
+	if err = json.Unmarshal(b.Bytes(), &res); err != nil {
+		return nil, err
+	}
+	return res.Filter(OperationSelector{
+		Match: func(OperationEntry) bool { return true },
+	})
+}
+
+type viewer struct {
+	opts ViewOpts
+	// hidden are the JSON pointers of the hidden components
+	hidden map[string]bool
+}
+
+// hides reports whether v is hidden from the audiences of the view
+func (vw *viewer) hides(v gjson.Result) bool {
+	if !v.IsObject() {
+		return false
+	}
+	if v.Get(gjsonEscape(vw.opts.internal())).Type == gjson.True {
+		return true
+	}
+	if a := v.Get(gjsonEscape(vw.opts.audience())); a.Exists() {
+		visible := false
+		for _, audience := range append([]gjson.Result{a}, a.Array()...) {
+			if audience.Type != gjson.String {
+				continue
+			}
+			for _, t := range vw.opts.Audiences {
+				visible = visible || audience.String() == t.String()
+			}
+		}
+		if !visible {
+			return true
+		}
+	}
+	if ref := v.Get(`\$ref`); ref.Type == gjson.String {
+		u, err := url.Parse(ref.String())
+		if err == nil && u.Scheme == "" && u.Host == "" && u.Path == "" {
+			return vw.hidden[u.Fragment]
+		}
+	}
+	return false
+}
+
+// removes reports whether the member value at tokens is removed from the
+// view
+func (vw *viewer) removes(tokens []string, value gjson.Result) bool {
+	n := len(tokens)
+	switch {
+	case !vw.opts.KeepExtensions && (tokens[n-1] == vw.opts.internal() || tokens[n-1] == vw.opts.audience()):
+		return true
+	case (n == 2 || n == 3) && (tokens[0] == "paths" || tokens[0] == "webhooks"):
+		return vw.hides(value)
+	case n == 3 && tokens[0] == "components":
+		return vw.hidden[joinPointerTokens(tokens)]
+	case n > 1 && tokens[n-2] == "properties":
+		return vw.hides(value)
+	}
+	return false
+}
+
+// filtersElements reports whether the hidden elements of the array at
+// tokens are removed from the view
+func (vw *viewer) filtersElements(tokens []string) bool {
+	n := len(tokens)
+	if n > 1 && tokens[n-2] == "properties" {
+		return false
+	}
+	switch tokens[n-1] {
+	case "parameters", "allOf", "anyOf", "oneOf":
+		return true
+	}
+	return false
+}
+
+// write writes v, located at tokens, to b with the hidden nodes removed
+func (vw *viewer) write(b *bytes.Buffer, v gjson.Result, tokens []string) {
+	switch {
+	case v.IsObject():
+		// the properties which are removed, and so must be removed from
+		// required as well
+		removed := map[string]bool{}
+		v.Get("properties").ForEach(func(key, value gjson.Result) bool {
+			if vw.hides(value) {
+				removed[key.String()] = true
+			}
+			return true
+		})
+		b.WriteByte('{')
+		i := 0
+		v.ForEach(func(key, value gjson.Result) bool {
+			t := append(tokens[:len(tokens):len(tokens)], key.String())
+			if vw.removes(t, value) {
+				return true
+			}
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			i++
+			b.WriteString(key.Raw)
+			b.WriteByte(':')
+			switch {
+			case isSplitDataMember(t, value):
+				b.WriteString(value.Raw)
+			case key.String() == "required" && value.IsArray() && len(removed) > 0:
+				b.WriteByte('[')
+				j := 0
+				for _, r := range value.Array() {
+					if removed[r.String()] {
+						continue
+					}
+					if j > 0 {
+						b.WriteByte(',')
+					}
+					j++
+					b.WriteString(r.Raw)
+				}
+				b.WriteByte(']')
+			default:
+				vw.write(b, value, t)
+			}
+			return true
+		})
+		b.WriteByte('}')
+	case v.IsArray():
+		filter := len(tokens) > 0 && vw.filtersElements(tokens)
+		b.WriteByte('[')
+		i := 0
+		for j, value := range v.Array() {
+			if filter && vw.hides(value) {
+				continue
+			}
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			i++
+			vw.write(b, value, append(tokens[:len(tokens):len(tokens)], strconv.Itoa(j)))
+		}
+		b.WriteByte(']')
+	default:
+		b.WriteString(v.Raw)
+	}
+}
@@ -0,0 +1,121 @@
+package openapi_test
+
+import (
+	"testing"
+
+	"github.com/chanced/openapi"
+)
+
+func TestPublicView(t *testing.T) {
+	data := []byte(`{
+		"openapi": "3.1.0",
+		"info": { "title": "view", "version": "1.0.0" },
+		"tags": [{ "name": "pets" }, { "name": "admin" }],
+		"paths": {
+			"/pets": {
+				"get": {
+					"operationId": "listPets",
+					"tags": ["pets"],
+					"parameters": [
+						{ "name": "limit", "in": "query", "schema": { "type": "integer" } },
+						{ "name": "debug", "in": "query", "x-internal": true, "schema": { "type": "boolean" } },
+						{ "$ref": "#/components/parameters/Trace" }
+					],
+					"responses": {
+						"200": {
+							"description": "ok",
+							"content": { "application/json": { "schema": { "$ref": "#/components/schemas/Pet" } } }
+						}
+					}
+				},
+				"delete": {
+					"operationId": "deletePets",
+					"tags": ["admin"],
+					"x-audience": ["internal", "ops"],
+					"responses": {
+						"200": {
+							"description": "ok",
+							"content": { "application/json": { "schema": { "$ref": "#/components/schemas/Audit" } } }
+						}
+					}
+				}
+			},
+			"/partners": {
+				"x-audience": "partner",
+				"get": { "responses": { "200": { "description": "ok" } } }
+			}
+		},
+		"components": {
+			"parameters": {
+				"Trace": { "name": "trace", "in": "header", "x-internal": true, "schema": { "type": "string" } }
+			},
+			"schemas": {
+				"Pet": {
+					"type": "object",
+					"required": ["name", "cost", "owner"],
+					"properties": {
+						"name": { "type": "string", "x-audience": "public" },
+						"cost": { "type": "number", "x-internal": true },
+						"owner": { "$ref": "#/components/schemas/Owner" }
+					},
+					"x-internal": false
+				},
+				"Owner": { "type": "object", "x-internal": true },
+				"Audit": { "type": "object" }
+			}
+		}
+	}`)
+	doc := loadDocument(t, "https://example.com/view.json", data)
+	res, err := openapi.PublicView(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ops := res.Operations(); len(ops) != 1 || ops[0].Operation.OperationID != "listPets" {
+		t.Fatalf("expected only listPets to be visible, got %v", ops)
+	}
+	if params := res.Paths.Get("/pets").Get.Parameters; len(params.Items) != 1 || params.Items[0].Object.Name != "limit" {
+		t.Errorf("expected only the limit parameter to be visible, got %v", params)
+	}
+	pet := res.Components.Schemas.Get("Pet")
+	if pet == nil {
+		t.Fatal("expected Pet to be visible")
+	}
+	if keys := schemaKeys(pet.Properties); len(keys) != 1 || keys[0] != "name" {
+		t.Errorf("expected only the name property to be visible, got %v", keys)
+	}
+	if len(pet.Required) != 1 || pet.Required[0] != "name" {
+		t.Errorf("expected required to be [name], got %v", pet.Required)
+	}
+	if _, ok := pet.Extensions["x-internal"]; ok {
+		t.Error("expected x-internal to be removed")
+	}
+	if _, ok := pet.Properties.Get("name").Extensions["x-audience"]; ok {
+		t.Error("expected x-audience to be removed")
+	}
+	if keys := schemaKeys(res.Components.Schemas); len(keys) != 1 {
+		t.Errorf("expected only Pet to remain, got %v", keys)
+	}
+	if res.Components.Parameters != nil {
+		t.Error("expected internal parameters to be removed")
+	}
+	if res.Tags == nil || len(res.Tags.Items) != 1 || res.Tags.Items[0].Name != "pets" {
+		t.Errorf("expected only the pets tag to remain, got %v", res.Tags)
+	}
+
+	res, err = doc.View(openapi.ViewOpts{Audiences: []openapi.Text{"ops", "partner"}, KeepExtensions: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ops := res.Operations(); len(ops) != 3 {
+		t.Errorf("expected 3 operations to be visible, got %d", len(ops))
+	}
+	if res.Components.Schemas.Get("Audit") == nil {
+		t.Error("expected Audit to be visible")
+	}
+	if res.Components.Schemas.Get("Pet").Properties.Get("name") != nil {
+		t.Error("expected name to be hidden from audiences other than public")
+	}
+	if _, ok := res.Components.Schemas.Get("Pet").Extensions["x-internal"]; !ok {
+		t.Error("expected x-internal to be kept")
+	}
+}
@@ -0,0 +1,113 @@
+package openapi
+
+import "fmt"
+
+// OperationEntry is an Operation along with the path or webhook it is
+// defined in.
+type OperationEntry struct {
+	// Key is the path of the PathItem (e.g. "/pets/{petId}") or, if Webhook
+	// is true, the name of the webhook.
+	Key Text
+	// Webhook indicates that the Operation is defined in the Document's
+	// Webhooks rather than its Paths.
+	Webhook bool
+	// Method is the HTTP method of the Operation.
+	Method Text
+	// PathItem is the PathItem which contains the Operation.
+	PathItem *PathItem
+	// Operation is the Operation.
+	Operation *Operation
+}
+
+// WebhookItems returns the name and PathItem of each of the Document's
+// Webhooks, in order. References are resolved; webhooks which are unresolved
+// references are omitted.
+func (d *Document) WebhookItems() []PathItemEntry {
+	if d == nil || d.Webhooks == nil {
+		return nil
+	}
+	entries := make([]PathItemEntry, 0, len(d.Webhooks.Items))
+	for _, item := range d.Webhooks.Items {
+		if item.Component == nil || item.Component.Object == nil {
+			continue
+		}
+		entries = append(entries, PathItemEntry{Key: item.Key, PathItem: item.Component.Object})
+	}
+	return entries
+}
+
+// Webhook returns the PathItem of the webhook name, resolving the reference
+// if the webhook is a Reference. If the webhook does not exist or is an
+// unresolved reference, nil is returned.
+func (d *Document) Webhook(name Text) *PathItem {
+	if d == nil || d.Webhooks == nil {
+		return nil
+	}
+	c := d.Webhooks.Get(name)
+	if c == nil {
+		return nil
+	}
+	return c.Object
+}
+
+// EffectiveWebhook returns the PathItem of the webhook name with the summary
+// and description overrides of its Reference, if any, merged in. The
+// referenced PathItem is not modified. If the webhook does not exist or is an
+// unresolved reference, nil is returned.
+func (d *Document) EffectiveWebhook(name Text) *PathItem {
+	if d == nil || d.Webhooks == nil {
+		return nil
+	}
+	return d.Webhooks.Get(name).Effective()
+}
+
+// Operations returns each Operation of the Document's Paths followed by those
+// of its Webhooks.
+func (d *Document) Operations() []OperationEntry {
+	if d == nil {
+		return nil
+	}
+	var ops []OperationEntry
+	if d.Paths != nil {
+		for _, item := range d.Paths.Items {
+			for _, mo := range item.Value.Operations() {
+				ops = append(ops, OperationEntry{
+					Key:       item.Key,
+					Method:    mo.Method,
+					PathItem:  item.Value,
+					Operation: mo.Operation,
+				})
+			}
+		}
+	}
+	for _, e := range d.WebhookItems() {
+		for _, mo := range e.PathItem.Operations() {
+			ops = append(ops, OperationEntry{
+				Key:       e.Key,
+				Webhook:   true,
+				Method:    mo.Method,
+				PathItem:  e.PathItem,
+				Operation: mo.Operation,
+			})
+		}
+	}
+	return ops
+}
+
+// validateWebhooks ensures that neither the PathItem of a webhook nor its
+// Operations define servers.
+func validateWebhooks(doc *Document) error {
+	for _, e := range doc.WebhookItems() {
+		if e.PathItem.Servers != nil && len(e.PathItem.Servers.Items) > 0 {
+			loc := e.PathItem.Location.AppendLocation("servers").AbsoluteLocation()
+			return NewValidationError(fmt.Errorf("%w: %q", ErrWebhookServers, e.Key), KindPathItem, loc)
+		}
+		for _, mo := range e.PathItem.Operations() {
+			if mo.Operation.Servers != nil && len(mo.Operation.Servers.Items) > 0 {
+				loc := mo.Operation.Location.AppendLocation("servers").AbsoluteLocation()
+				return NewValidationError(fmt.Errorf("%w: %q", ErrWebhookServers, e.Key), KindOperation, loc)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package openapi_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/chanced/openapi"
+)
+
+func TestWebhooks(t *testing.T) {
+	data := []byte(`{
+		"openapi": "3.1.0",
+		"info": { "title": "webhooks", "version": "1.0.0" },
+		"paths": {
+			"/pets": {
+				"get": { "operationId": "listPets", "responses": { "200": { "description": "ok" } } }
+			}
+		},
+		"webhooks": {
+			"newPet": { "$ref": "#/components/pathItems/NewPet" },
+			"petDeleted": {
+				"delete": { "operationId": "petDeleted", "responses": { "200": { "description": "ok" } } }
+			}
+		},
+		"components": {
+			"pathItems": {
+				"NewPet": {
+					"post": { "operationId": "newPet", "responses": { "200": { "description": "ok" } } }
+				}
+			}
+		}
+	}`)
+	doc := loadDocument(t, "https://example.com/webhooks.json", data)
+	newPet := doc.Webhook("newPet")
+	if newPet == nil || newPet.Post == nil {
+		t.Fatal("expected newPet webhook to be resolved")
+	}
+	ops := doc.Operations()
+	expected := []openapi.Text{"listPets", "newPet", "petDeleted"}
+	if len(ops) != len(expected) {
+		t.Fatalf("expected %d operations, got %d", len(expected), len(ops))
+	}
+	for i, op := range ops {
+		if op.Operation.OperationID != expected[i] {
+			t.Errorf("expected %q, got %q", expected[i], op.Operation.OperationID)
+		}
+		if op.Webhook != (i > 0) {
+			t.Errorf("unexpected value for Webhook of %q", op.Operation.OperationID)
+		}
+	}
+
+	v, err := openapi.NewOfflineValidator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	newPet.Servers = &openapi.ServerSlice{Items: []*openapi.Server{{URL: "https://example.com"}}}
+	if err = v.ValidateDocument(doc); !errors.Is(err, openapi.ErrWebhookServers) {
+		t.Errorf("expected ErrWebhookServers, got %v", err)
+	}
+}
+
+func TestEffectiveWebhook(t *testing.T) {
+	data, err := testdata.ReadFile("testdata/documents/validation/pass/mega.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := loadDocument(t, "https://example.com/mega.yaml", data)
+	wh := doc.EffectiveWebhook("myWebhook")
+	if wh == nil {
+		t.Fatal("expected myWebhook to be resolved")
+	}
+	if wh.Description != "Overriding description" {
+		t.Errorf("expected the description to be overridden, got %q", wh.Description)
+	}
+	if wh.Post == nil || wh.Post.RequestBody == nil {
+		t.Error("expected the operations of the referenced PathItem")
+	}
+	referenced := doc.Components.PathItems.Get("myPathItem").Object
+	if referenced.Description != "" {
+		t.Errorf("expected the referenced PathItem to be unchanged, got %q", referenced.Description)
+	}
+	if doc.Webhook("myWebhook") != referenced {
+		t.Error("expected Webhook to return the referenced PathItem")
+	}
+	if doc.EffectiveWebhook("missing") != nil {
+		t.Error("expected nil for a missing webhook")
+	}
+
+	if err = doc.Dereference(openapi.DereferenceOpts{ApplyOverrides: true}); err != nil {
+		t.Fatal(err)
+	}
+	if d := doc.Webhook("myWebhook").Description; d != "Overriding description" {
+		t.Errorf("expected the description to be merged when dereferenced, got %q", d)
+	}
+}
@@ -0,0 +1,210 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"gopkg.in/yaml.v3"
+)
+
+// jsonFromYAMLNode encodes value as JSON, preserving the order of mapping
+// keys.
+//
+// Unlike transcode.JSONFromYAML, the yaml.Node is walked directly rather than
+// being marshaled back into YAML and parsed again.
+func jsonFromYAMLNode(value *yaml.Node) ([]byte, error) {
+	b := getBuffer()
+	if err := writeYAMLNodeAsJSON(b, value); err != nil {
+		putBuffer(b)
+		return nil, err
+	}
+	return releaseBuffer(b), nil
+}
+
+func writeYAMLNodeAsJSON(b *bytes.Buffer, value *yaml.Node) error {
+	if value == nil {
+		b.WriteString("null")
+		return nil
+	}
+	switch value.Kind {
+	case yaml.DocumentNode:
+		if len(value.Content) == 0 {
+			b.WriteString("null")
+			return nil
+		}
+		return writeYAMLNodeAsJSON(b, value.Content[0])
+	case yaml.AliasNode:
+		return writeYAMLNodeAsJSON(b, value.Alias)
+	case yaml.SequenceNode:
+		b.WriteByte('[')
+		for i, n := range value.Content {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			if err := writeYAMLNodeAsJSON(b, n); err != nil {
+				return err
+			}
+		}
+		b.WriteByte(']')
+		return nil
+	case yaml.MappingNode:
+		return writeYAMLMappingAsJSON(b, value)
+	case yaml.ScalarNode:
+		return writeYAMLScalarAsJSON(b, value)
+	case 0:
+		b.WriteString("null")
+		return nil
+	default:
+		return fmt.Errorf("openapi: unsupported yaml node kind %d at line %d", value.Kind, value.Line)
+	}
+}
+
+type yamlMember struct {
+	key   string
+	value *yaml.Node
+}
+
+func writeYAMLMappingAsJSON(b *bytes.Buffer, value *yaml.Node) error {
+	members, err := yamlMappingMembers(value, nil)
+	if err != nil {
+		return err
+	}
+	b.WriteByte('{')
+	for i, m := range members {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		writeJSONString(b, m.key)
+		b.WriteByte(':')
+		if err := writeYAMLNodeAsJSON(b, m.value); err != nil {
+			return err
+		}
+	}
+	b.WriteByte('}')
+	return nil
+}
+
+// yamlMappingMembers returns the members of the mapping value, expanding
+// merge keys ("<<"). Explicit keys take precedence over merged keys and the
+// last occurrence of a duplicate key wins.
+func yamlMappingMembers(value *yaml.Node, seen map[*yaml.Node]struct{}) ([]yamlMember, error) {
+	for value.Kind == yaml.AliasNode {
+		value = value.Alias
+	}
+	if value.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("openapi: expected yaml mapping at line %d", value.Line)
+	}
+	if seen == nil {
+		seen = map[*yaml.Node]struct{}{}
+	}
+	if _, ok := seen[value]; ok {
+		return nil, fmt.Errorf("openapi: recursive yaml merge at line %d", value.Line)
+	}
+	seen[value] = struct{}{}
+	defer delete(seen, value)
+
+	var members []yamlMember
+	index := map[string]int{}
+	set := func(key string, v *yaml.Node, override bool) {
+		if i, ok := index[key]; ok {
+			if override {
+				members[i].value = v
+			}
+			return
+		}
+		index[key] = len(members)
+		members = append(members, yamlMember{key: key, value: v})
+	}
+	var merged []yamlMember
+	for i := 0; i+1 < len(value.Content); i += 2 {
+		k, v := value.Content[i], value.Content[i+1]
+		for k.Kind == yaml.AliasNode {
+			k = k.Alias
+		}
+		if k.Kind != yaml.ScalarNode {
+			return nil, fmt.Errorf("openapi: unsupported yaml mapping key at line %d", k.Line)
+		}
+		if k.ShortTag() == "!!merge" {
+			sources := []*yaml.Node{v}
+			for v.Kind == yaml.AliasNode {
+				v = v.Alias
+			}
+			if v.Kind == yaml.SequenceNode {
+				sources = v.Content
+			}
+			for _, src := range sources {
+				mm, err := yamlMappingMembers(src, seen)
+				if err != nil {
+					return nil, err
+				}
+				merged = append(merged, mm...)
+			}
+			continue
+		}
+		set(k.Value, v, true)
+	}
+	for _, m := range merged {
+		set(m.key, m.value, false)
+	}
+	return members, nil
+}
+
+func writeYAMLScalarAsJSON(b *bytes.Buffer, value *yaml.Node) error {
+	switch value.ShortTag() {
+	case "!!null":
+		b.WriteString("null")
+		return nil
+	case "!!bool":
+		var v bool
+		if err := value.Decode(&v); err != nil {
+			return err
+		}
+		if v {
+			b.WriteString("true")
+		} else {
+			b.WriteString("false")
+		}
+		return nil
+	case "!!int", "!!float":
+		// numbers which are already valid JSON are written verbatim so that
+		// precision is retained
+		if isJSONNumber(value.Value) {
+			b.WriteString(value.Value)
+			return nil
+		}
+		var v interface{}
+		if err := value.Decode(&v); err != nil {
+			return err
+		}
+		if f, ok := v.(float64); ok && (math.IsInf(f, 0) || math.IsNaN(f)) {
+			return fmt.Errorf("openapi: yaml value %q at line %d can not be represented in JSON", value.Value, value.Line)
+		}
+		d, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		b.Write(d)
+		return nil
+	default:
+		writeJSONString(b, value.Value)
+		return nil
+	}
+}
+
+func isJSONNumber(s string) bool {
+	if s == "" || (s[0] != '-' && (s[0] < '0' || s[0] > '9')) {
+		return false
+	}
+	return json.Valid([]byte(s))
+}
+
+func writeJSONString(b *bytes.Buffer, s string) {
+	enc := json.NewEncoder(b)
+	enc.SetEscapeHTML(false)
+	// encoding a string can not fail
+	_ = enc.Encode(s)
+	// Encode appends a newline
+	b.Truncate(b.Len() - 1)
+}
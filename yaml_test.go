@@ -0,0 +1,99 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/chanced/openapi"
+	"github.com/chanced/transcode"
+	"gopkg.in/yaml.v3"
+)
+
+func TestUnmarshalYAML(t *testing.T) {
+	data, err := testdata.ReadFile("testdata/documents/petstore.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fromYAML openapi.Document
+	if err = yaml.Unmarshal(data, &fromYAML); err != nil {
+		t.Fatal(err)
+	}
+	j, err := transcode.JSONFromYAML(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fromJSON openapi.Document
+	if err = json.Unmarshal(j, &fromJSON); err != nil {
+		t.Fatal(err)
+	}
+	expected, err := fromJSON.Checksum()
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, err := fromYAML.Checksum()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected != actual {
+		t.Error("expected document unmarshaled from YAML to match document unmarshaled from JSON")
+	}
+
+	data = []byte(`
+type: object
+x-owner: &owner
+  team: pets
+  contact: pets@example.com
+x-copy:
+  <<: *owner
+  team: cats
+required: [name]
+properties:
+  name:
+    type: string
+    maxLength: 0x10
+    default: "<none>"
+  tags:
+    type: array
+    x-pi: 3.14
+    x-nothing: ~
+`)
+	var s openapi.Schema
+	if err = yaml.Unmarshal(data, &s); err != nil {
+		t.Fatal(err)
+	}
+	var owner map[string]string
+	if err = json.Unmarshal(s.Extensions["x-copy"], &owner); err != nil {
+		t.Fatal(err)
+	}
+	if owner["team"] != "cats" || owner["contact"] != "pets@example.com" {
+		t.Errorf("expected merge key to be expanded, got %v", owner)
+	}
+	name := s.Properties.Get("name")
+	if name.MaxLength == nil || name.MaxLength.String() != "16" {
+		t.Errorf("expected maxLength to be 16, got %v", name.MaxLength)
+	}
+	if string(name.Default) != `"<none>"` {
+		t.Errorf("expected default to be %q, got %s", "<none>", name.Default)
+	}
+	tags := s.Properties.Get("tags")
+	for key, expected := range map[openapi.Text]string{"x-pi": "3.14", "x-nothing": "null"} {
+		if string(tags.Extensions[key]) != expected {
+			t.Errorf("expected %s to be %s, got %s", key, expected, tags.Extensions[key])
+		}
+	}
+}
+
+func BenchmarkUnmarshalYAML(b *testing.B) {
+	data, err := testdata.ReadFile("testdata/documents/petstore.yaml")
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		var doc openapi.Document
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			b.Fatal(err)
+		}
+	}
+}